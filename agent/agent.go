@@ -167,6 +167,10 @@ const (
 
 	MgoStatsEnabled = "MGO_STATS_ENABLED"
 
+	// ControllerProxy holds the URL of an HTTP CONNECT or SOCKS5 proxy
+	// through which the agent connects to the controller.
+	ControllerProxy = "CONTROLLER_PROXY"
+
 	// LoggingOverride will set the logging for this agent to the value
 	// specified. Model configuration will be ignored and this value takes
 	// precidence for the agent.
@@ -91,6 +91,10 @@ func InitializeState(
 	}
 	info.Tag = nil
 	info.Password = c.OldPassword()
+	secretsKey, err := agent.SecretsKey(c)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 
 	if err := initRaft(c); err != nil {
 		return nil, nil, errors.Trace(err)
@@ -136,6 +140,7 @@ func InitializeState(
 		MongoSession:              session,
 		AdminPassword:             info.Password,
 		NewPolicy:                 newPolicy,
+		SecretsKey:                secretsKey,
 	})
 	if err != nil {
 		return nil, nil, errors.Errorf("failed to initialize state: %v", err)
@@ -252,6 +257,8 @@ func paramsStateServingInfoToStateStateServingInfo(i params.StateServingInfo) st
 		CAPrivateKey:   i.CAPrivateKey,
 		SharedSecret:   i.SharedSecret,
 		SystemIdentity: i.SystemIdentity,
+
+		SSHCAPrivateKey: i.SSHCAPrivateKey,
 	}
 }
 
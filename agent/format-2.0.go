@@ -52,6 +52,8 @@ type format_2_0Serialization struct {
 	ControllerCert     string `yaml:"controllercert,omitempty"`
 	ControllerKey      string `yaml:"controllerkey,omitempty"`
 	CAPrivateKey       string `yaml:"caprivatekey,omitempty"`
+	SSHCAPrivateKey    string `yaml:"sshcaprivatekey,omitempty"`
+	SecretsKey         string `yaml:"secretskey,omitempty"`
 	APIPort            int    `yaml:"apiport,omitempty"`
 	StatePort          int    `yaml:"stateport,omitempty"`
 	SharedSecret       string `yaml:"sharedsecret,omitempty"`
@@ -120,6 +122,9 @@ func (formatter_2_0) unmarshal(data []byte) (*configInternal, error) {
 			StatePort:      format.StatePort,
 			SharedSecret:   format.SharedSecret,
 			SystemIdentity: format.SystemIdentity,
+
+			SSHCAPrivateKey: format.SSHCAPrivateKey,
+			SecretsKey:      format.SecretsKey,
 		}
 		// If private key is not present, infer it from the ports in the state addresses.
 		if config.servingInfo.StatePort == 0 {
@@ -181,6 +186,8 @@ func (formatter_2_0) marshal(config *configInternal) ([]byte, error) {
 		format.StatePort = config.servingInfo.StatePort
 		format.SharedSecret = config.servingInfo.SharedSecret
 		format.SystemIdentity = config.servingInfo.SystemIdentity
+		format.SSHCAPrivateKey = config.servingInfo.SSHCAPrivateKey
+		format.SecretsKey = config.servingInfo.SecretsKey
 		format.StatePassword = config.statePassword
 	}
 	if config.apiDetails != nil {
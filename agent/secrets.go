@@ -0,0 +1,25 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"encoding/base64"
+
+	"github.com/juju/errors"
+)
+
+// SecretsKey returns the key that charm secrets are encrypted with,
+// held in the state serving info of controller agents. It returns nil
+// if the agent has no key.
+func SecretsKey(c Config) ([]byte, error) {
+	info, ok := c.StateServingInfo()
+	if !ok || info.SecretsKey == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(info.SecretsKey)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode secrets key")
+	}
+	return key, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type secretsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) TestSecretsKey(c *gc.C) {
+	params := attributeParams
+	params.Paths.DataDir = c.MkDir()
+	info := servingInfo
+	info.SecretsKey = "c2VjcmV0cyBrZXk="
+	conf, err := NewStateMachineConfig(params, info)
+	c.Assert(err, jc.ErrorIsNil)
+	key, err := SecretsKey(conf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(key), gc.Equals, "secrets key")
+}
+
+func (s *secretsSuite) TestSecretsKeyMissing(c *gc.C) {
+	params := attributeParams
+	params.Paths.DataDir = c.MkDir()
+	conf, err := NewStateMachineConfig(params, servingInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	key, err := SecretsKey(conf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, gc.IsNil)
+}
+
+func (s *secretsSuite) TestSecretsKeyInvalid(c *gc.C) {
+	params := attributeParams
+	params.Paths.DataDir = c.MkDir()
+	info := servingInfo
+	info.SecretsKey = "not base64!"
+	conf, err := NewStateMachineConfig(params, info)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = SecretsKey(conf)
+	c.Assert(err, gc.ErrorMatches, "cannot decode secrets key: .*")
+}
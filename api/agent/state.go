@@ -93,6 +93,39 @@ func (c *State) WatchCredential(tag names.CloudCredentialTag) (watcher.NotifyWat
 	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
 }
 
+// WatchControllerCACert returns a watcher which reports when the
+// controller CA certificate may have changed.
+func (st *State) WatchControllerCACert() (watcher.NotifyWatcher, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("watching the controller CA certificate")
+	}
+	var result params.NotifyWatchResult
+	err := st.facade.FacadeCall("WatchControllerCACert", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
+// AcknowledgeControllerCACert tells the controller which controller
+// CA certificate the agent with the given tag has recorded.
+func (st *State) AcknowledgeControllerCACert(tag names.Tag, caCert string) error {
+	if st.facade.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("acknowledging the controller CA certificate")
+	}
+	args := params.AgentCACerts{
+		CACerts: []params.AgentCACert{{Tag: tag.String(), CACert: caCert}},
+	}
+	var results params.ErrorResults
+	if err := st.facade.FacadeCall("AcknowledgeControllerCACerts", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 type Entity struct {
 	st  *State
 	tag names.Tag
@@ -99,6 +99,11 @@ type state struct {
 	// by an officially signed certificate.
 	publicDNSName string
 
+	// controllerCACert is the controller CA certificate returned
+	// from Login, which may differ from the one the client used
+	// while the controller CA is being rotated.
+	controllerCACert string
+
 	// facadeVersions holds the versions of all facades as reported by
 	// Login
 	facadeVersions map[string][]int
@@ -129,11 +134,13 @@ type state struct {
 	// access it safely.
 	loggedIn int32
 
-	// tag, password, macaroons and nonce hold the cached login
-	// credentials. These are only valid if loggedIn is 1.
+	// tag, password, macaroons, token, idToken and nonce hold the
+	// cached login credentials. These are only valid if loggedIn is 1.
 	tag       string
 	password  string
 	macaroons []macaroon.Slice
+	token     string
+	idToken   string
 	nonce     string
 
 	// serverRootAddress holds the cached API server address and port used
@@ -150,6 +157,10 @@ type state struct {
 	// bakeryClient holds the client that will be used to
 	// authorize macaroon based login requests.
 	bakeryClient *httpbakery.Client
+
+	// controllerProxy holds the proxy through which connections
+	// to the controller are made, if any.
+	controllerProxy *url.URL
 }
 
 // RedirectError is returned from Open when the controller
@@ -184,6 +195,9 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 	if opts.Clock == nil {
 		opts.Clock = clock.WallClock
 	}
+	if err := proxy.ValidateControllerProxy(opts.ControllerProxy); err != nil {
+		return nil, errors.Trace(err)
+	}
 	ctx := context.TODO()
 	dialCtx := ctx
 	if opts.Timeout > 0 {
@@ -213,9 +227,14 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 	// Technically when there's no CACert, we don't need this
 	// machinery, because we could just use http.DefaultTransport
 	// for everything, but it's easier just to leave it in place.
+	primary := utils.NewHttpTLSTransport(dialResult.tlsConfig)
+	if err := useControllerProxyForHTTP(primary, opts.ControllerProxy); err != nil {
+		dialResult.conn.Close()
+		return nil, errors.Trace(err)
+	}
 	bakeryClient.Client.Transport = &hostSwitchingTransport{
 		primaryHost: dialResult.addr,
-		primary:     utils.NewHttpTLSTransport(dialResult.tlsConfig),
+		primary:     primary,
 		fallback:    http.DefaultTransport,
 	}
 
@@ -237,13 +256,16 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 		// login because, when doing HTTP requests, we'll want
 		// to use the same username and password for authenticating
 		// those. If login fails, we discard the connection.
-		tag:          tagToString(info.Tag),
-		password:     info.Password,
-		macaroons:    info.Macaroons,
-		nonce:        info.Nonce,
-		tlsConfig:    dialResult.tlsConfig,
-		bakeryClient: bakeryClient,
-		modelTag:     info.ModelTag,
+		tag:             tagToString(info.Tag),
+		password:        info.Password,
+		macaroons:       info.Macaroons,
+		token:           info.Token,
+		idToken:         info.IDToken,
+		nonce:           info.Nonce,
+		tlsConfig:       dialResult.tlsConfig,
+		bakeryClient:    bakeryClient,
+		modelTag:        info.ModelTag,
+		controllerProxy: opts.ControllerProxy,
 	}
 	if !info.SkipLogin {
 		if err := loginWithContext(dialCtx, st, info); err != nil {
@@ -376,13 +398,15 @@ func (st *state) connectStream(path string, attrs url.Values, extraHeaders http.
 	// in any case (lp:1644009). Review.
 
 	dialer := &websocket.Dialer{
-		Proxy:           proxy.DefaultConfig.GetProxy,
 		TLSClientConfig: st.tlsConfig,
 		// In order to deal with the remote side not handling message
 		// fragmentation, we default to largeish frames.
 		ReadBufferSize:  websocketFrameSize,
 		WriteBufferSize: websocketFrameSize,
 	}
+	if err := useControllerProxy(dialer, st.controllerProxy); err != nil {
+		return nil, errors.Trace(err)
+	}
 	var requestHeader http.Header
 	if st.tag != "" {
 		requestHeader = utils.BasicAuthHeader(st.tag, st.password)
@@ -571,7 +595,10 @@ func dialAPI(ctx context.Context, info *Info, opts0 DialOpts) (*dialResult, erro
 	// Set opts.DialWebsocket and opts.Clock here rather than in open because
 	// some tests call dialAPI directly.
 	if opts.DialWebsocket == nil {
-		opts.DialWebsocket = gorillaDialWebsocket
+		controllerProxy := opts.ControllerProxy
+		opts.DialWebsocket = func(ctx context.Context, urlStr string, tlsConfig *tls.Config, ipAddr string) (jsoncodec.JSONConn, error) {
+			return gorillaDialWebsocket(ctx, urlStr, tlsConfig, ipAddr, controllerProxy)
+		}
 	}
 	if opts.IPAddrResolver == nil {
 		opts.IPAddrResolver = net.DefaultResolver
@@ -603,8 +630,9 @@ func dialAPI(ctx context.Context, info *Info, opts0 DialOpts) (*dialResult, erro
 // gorilla websocket package. The ipAddr parameter holds the
 // actual IP address that will be contacted - the host in urlStr
 // is used only for TLS verification when tlsConfig.ServerName
-// is empty.
-func gorillaDialWebsocket(ctx context.Context, urlStr string, tlsConfig *tls.Config, ipAddr string) (jsoncodec.JSONConn, error) {
+// is empty. If controllerProxy is non-nil, the connection is made
+// through that proxy.
+func gorillaDialWebsocket(ctx context.Context, urlStr string, tlsConfig *tls.Config, ipAddr string, controllerProxy *url.URL) (jsoncodec.JSONConn, error) {
 	url, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -622,13 +650,15 @@ func gorillaDialWebsocket(ctx context.Context, urlStr string, tlsConfig *tls.Con
 			}
 			return netDialer.DialContext(ctx, netw, addr)
 		},
-		Proxy:           proxy.DefaultConfig.GetProxy,
 		TLSClientConfig: tlsConfig,
 		// In order to deal with the remote side not handling message
 		// fragmentation, we default to largeish frames.
 		ReadBufferSize:  websocketFrameSize,
 		WriteBufferSize: websocketFrameSize,
 	}
+	if err := useControllerProxy(dialer, controllerProxy); err != nil {
+		return nil, errors.Trace(err)
+	}
 	// Note: no extra headers.
 	c, _, err := dialer.Dial(urlStr, nil)
 	if err != nil {
@@ -1022,6 +1052,12 @@ func (s *state) PublicDNSName() string {
 	return s.publicDNSName
 }
 
+// ControllerCACert returns the controller's CA certificate, as
+// reported at login. It is empty if the controller did not report it.
+func (s *state) ControllerCACert() string {
+	return s.controllerCACert
+}
+
 // AllFacadeVersions returns what versions we know about for all facades
 func (s *state) AllFacadeVersions() map[string][]int {
 	facades := make(map[string][]int, len(s.facadeVersions))
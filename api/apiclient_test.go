@@ -596,6 +596,13 @@ func (s *apiclientSuite) TestPublicDNSName(c *gc.C) {
 	c.Assert(conn.PublicDNSName(), gc.Equals, "somewhere.example.com")
 }
 
+func (s *apiclientSuite) TestControllerCACert(c *gc.C) {
+	conn, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	c.Assert(conn.ControllerCACert(), gc.Equals, jtesting.CACert)
+}
+
 func (s *apiclientSuite) TestOpenWithRedirect(c *gc.C) {
 	redirectToHosts := []string{"0.1.2.3:1234", "0.1.2.4:1235"}
 	redirectToCACert := "fake CA cert"
@@ -772,3 +772,46 @@ func (c *Client) UnsetApplicationConfig(application string, options []string) er
 	}
 	return results.OneError()
 }
+
+// GrantApplicationAccess grants the user read, operate or admin access
+// to the named applications.
+func (c *Client) GrantApplicationAccess(user, access string, applications ...string) error {
+	return c.modifyApplicationAccess(params.GrantModelAccess, user, access, applications)
+}
+
+// RevokeApplicationAccess revokes any access granted to the user on the
+// named applications. Access the user has on the model is unaffected.
+func (c *Client) RevokeApplicationAccess(user string, applications ...string) error {
+	return c.modifyApplicationAccess(params.RevokeModelAccess, user, "", applications)
+}
+
+func (c *Client) modifyApplicationAccess(action params.ModelAction, user, access string, applications []string) error {
+	if c.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("application access on this version of Juju")
+	}
+	if !names.IsValidUser(user) {
+		return errors.Errorf("invalid username: %q", user)
+	}
+	userTag := names.NewUserTag(user)
+	var args params.ModifyApplicationAccessRequest
+	for _, app := range applications {
+		if !names.IsValidApplication(app) {
+			return errors.NotValidf("application name %q", app)
+		}
+		args.Changes = append(args.Changes, params.ModifyApplicationAccess{
+			UserTag:        userTag.String(),
+			Action:         action,
+			Access:         params.UserAccessPermission(access),
+			ApplicationTag: names.NewApplicationTag(app).String(),
+		})
+	}
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ModifyApplicationAccess", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(result.Results) != len(args.Changes) {
+		return errors.Errorf("expected %d results, got %d", len(args.Changes), len(result.Results))
+	}
+	return result.Combine()
+}
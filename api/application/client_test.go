@@ -1041,3 +1041,39 @@ func (s *applicationSuite) TestUnsetApplicationConfigAPIv5(c *gc.C) {
 	err := client.UnsetApplicationConfig("foo", []string{})
 	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
 }
+
+func (s *applicationSuite) TestGrantApplicationAccess(c *gc.C) {
+	called := false
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Check(request, gc.Equals, "ModifyApplicationAccess")
+				c.Check(a, jc.DeepEquals, params.ModifyApplicationAccessRequest{
+					Changes: []params.ModifyApplicationAccess{{
+						UserTag:        "user-bob",
+						Action:         params.GrantModelAccess,
+						Access:         "operate",
+						ApplicationTag: "application-mysql",
+					}},
+				})
+				result := response.(*params.ErrorResults)
+				result.Results = []params.ErrorResult{{}}
+				return nil
+			},
+		),
+		BestVersion: 7,
+	})
+	err := client.GrantApplicationAccess("bob", "operate", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestRevokeApplicationAccessNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+	err := client.RevokeApplicationAccess("bob", "mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,188 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+)
+
+const (
+	// deviceCodeGrantType is the grant type used to exchange a device
+	// code for tokens, as defined by RFC 8628.
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultPollInterval is the interval between token requests used
+	// when the provider does not specify one.
+	defaultPollInterval = 5 * time.Second
+
+	// slowDownIncrement is added to the poll interval each time the
+	// provider asks the client to slow down.
+	slowDownIncrement = 5 * time.Second
+
+	// oidcHTTPTimeout bounds each request made to the provider.
+	oidcHTTPTimeout = 30 * time.Second
+)
+
+// OIDCDeviceFlow obtains an OpenID Connect identity token using the
+// OAuth 2.0 device authorization grant (RFC 8628). The user is asked
+// to visit a URL, on any device, and approve the login; meanwhile the
+// client polls the provider until the token is issued.
+type OIDCDeviceFlow struct {
+	// IssuerURL is the URL of the OpenID Connect provider.
+	IssuerURL string
+
+	// ClientID is the client ID the controller is registered
+	// with at the provider.
+	ClientID string
+
+	// Scopes holds the scopes to request. If empty, "openid",
+	// "profile" and "groups" are requested.
+	Scopes []string
+
+	// Client is used to make requests to the provider. If nil,
+	// a client with a timeout is used.
+	Client *http.Client
+
+	// Clock is used to wait between token requests.
+	Clock clock.Clock
+
+	// Prompt is called with the URL the user must visit, and the
+	// code they must enter there, to approve the login.
+	Prompt func(verificationURI, userCode string) error
+}
+
+type oidcProviderConfig struct {
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// IDToken runs the device flow and returns the identity token issued
+// to the user.
+func (f *OIDCDeviceFlow) IDToken() (string, error) {
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: oidcHTTPTimeout}
+	}
+	var config oidcProviderConfig
+	configURL := strings.TrimSuffix(f.IssuerURL, "/") + "/.well-known/openid-configuration"
+	if err := doJSON(client, http.MethodGet, configURL, nil, &config); err != nil {
+		return "", errors.Annotate(err, "getting OpenID Connect provider configuration")
+	}
+	if config.DeviceAuthorizationEndpoint == "" || config.TokenEndpoint == "" {
+		return "", errors.NotSupportedf("OpenID Connect provider without device authorization")
+	}
+
+	scopes := f.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "profile", "groups"}
+	}
+	var auth deviceAuthorization
+	if err := doJSON(client, http.MethodPost, config.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {f.ClientID},
+		"scope":     {strings.Join(scopes, " ")},
+	}, &auth); err != nil {
+		return "", errors.Annotate(err, "requesting device authorization")
+	}
+	verificationURI := auth.VerificationURIComplete
+	if verificationURI == "" {
+		verificationURI = auth.VerificationURI
+	}
+	if err := f.Prompt(verificationURI, auth.UserCode); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	interval := defaultPollInterval
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+	var deadline time.Time
+	if auth.ExpiresIn > 0 {
+		deadline = f.Clock.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	}
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {auth.DeviceCode},
+		"client_id":   {f.ClientID},
+	}
+	for {
+		<-f.Clock.After(interval)
+		if !deadline.IsZero() && !f.Clock.Now().Before(deadline) {
+			return "", errors.New("login not approved before the device code expired")
+		}
+		var token tokenResponse
+		err := doJSON(client, http.MethodPost, config.TokenEndpoint, form, &token)
+		if err != nil && token.Error == "" {
+			return "", errors.Annotate(err, "requesting identity token")
+		}
+		switch token.Error {
+		case "":
+			if token.IDToken == "" {
+				return "", errors.New("provider did not issue an identity token")
+			}
+			return token.IDToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += slowDownIncrement
+		case "access_denied":
+			return "", errors.New("login was denied")
+		case "expired_token":
+			return "", errors.New("login not approved before the device code expired")
+		default:
+			if token.ErrorDescription != "" {
+				return "", errors.Errorf("requesting identity token: %s: %s", token.Error, token.ErrorDescription)
+			}
+			return "", errors.Errorf("requesting identity token: %s", token.Error)
+		}
+	}
+}
+
+// doJSON makes a request to the provider, posting the given form if
+// the method is POST, and decodes the JSON response into result. Error
+// responses are decoded too, as OAuth errors are reported in the
+// body; the returned error is non-nil for any non-200 response.
+func doJSON(client *http.Client, method, target string, form url.Values, result interface{}) error {
+	var resp *http.Response
+	var err error
+	if method == http.MethodPost {
+		resp, err = client.PostForm(target, form)
+	} else {
+		resp, err = client.Get(target)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	jsonErr := json.Unmarshal(data, result)
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("%s %s: %s", method, target, resp.Status)
+	}
+	return errors.Trace(jsonErr)
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/authentication"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type OIDCDeviceFlowSuite struct {
+	testing.IsolationSuite
+	clock  *testing.Clock
+	server *httptest.Server
+
+	// tokenResponses holds the responses returned by the token
+	// endpoint, in order.
+	tokenResponses []string
+	tokenRequests  int
+}
+
+var _ = gc.Suite(&OIDCDeviceFlowSuite{})
+
+func (s *OIDCDeviceFlowSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Time{})
+	s.tokenResponses = nil
+	s.tokenRequests = 0
+
+	mux := http.NewServeMux()
+	s.server = httptest.NewServer(mux)
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"device_authorization_endpoint": %q, "token_endpoint": %q}`,
+			s.server.URL+"/device", s.server.URL+"/token")
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.PostFormValue("client_id"), gc.Equals, "juju")
+		c.Check(r.PostFormValue("scope"), gc.Equals, "openid profile groups")
+		fmt.Fprintf(w, `{"device_code": "dev-code", "user_code": "ABCD-EFGH", "verification_uri": %q, "expires_in": 600, "interval": 5}`,
+			s.server.URL+"/activate")
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.PostFormValue("grant_type"), gc.Equals, "urn:ietf:params:oauth:grant-type:device_code")
+		c.Check(r.PostFormValue("device_code"), gc.Equals, "dev-code")
+		response := s.tokenResponses[s.tokenRequests]
+		s.tokenRequests++
+		if response[0] != '{' {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error": %q}`, response)
+			return
+		}
+		fmt.Fprint(w, response)
+	})
+}
+
+func (s *OIDCDeviceFlowSuite) flow(prompted *[]string) *authentication.OIDCDeviceFlow {
+	return &authentication.OIDCDeviceFlow{
+		IssuerURL: s.server.URL + "/",
+		ClientID:  "juju",
+		Clock:     s.clock,
+		Prompt: func(uri, code string) error {
+			*prompted = append(*prompted, uri, code)
+			return nil
+		},
+	}
+}
+
+type tokenResult struct {
+	token string
+	err   error
+}
+
+func (s *OIDCDeviceFlowSuite) run(c *gc.C, flow *authentication.OIDCDeviceFlow, waits ...time.Duration) tokenResult {
+	done := make(chan tokenResult, 1)
+	go func() {
+		token, err := flow.IDToken()
+		done <- tokenResult{token, err}
+	}()
+	for _, d := range waits {
+		c.Assert(s.clock.WaitAdvance(d, coretesting.LongWait, 1), jc.ErrorIsNil)
+	}
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for device flow")
+	}
+	panic("unreachable")
+}
+
+func (s *OIDCDeviceFlowSuite) TestIDToken(c *gc.C) {
+	s.tokenResponses = []string{
+		"authorization_pending",
+		"slow_down",
+		`{"id_token": "the-token", "access_token": "ignored"}`,
+	}
+	var prompted []string
+	result := s.run(c, s.flow(&prompted), 5*time.Second, 5*time.Second, 10*time.Second)
+	c.Assert(result.err, jc.ErrorIsNil)
+	c.Assert(result.token, gc.Equals, "the-token")
+	c.Assert(prompted, jc.DeepEquals, []string{s.server.URL + "/activate", "ABCD-EFGH"})
+	c.Assert(s.tokenRequests, gc.Equals, 3)
+}
+
+func (s *OIDCDeviceFlowSuite) TestIDTokenDenied(c *gc.C) {
+	s.tokenResponses = []string{"access_denied"}
+	var prompted []string
+	result := s.run(c, s.flow(&prompted), 5*time.Second)
+	c.Assert(result.err, gc.ErrorMatches, "login was denied")
+}
+
+func (s *OIDCDeviceFlowSuite) TestIDTokenExpired(c *gc.C) {
+	s.tokenResponses = []string{"authorization_pending"}
+	var prompted []string
+	result := s.run(c, s.flow(&prompted), 5*time.Second, 600*time.Second)
+	c.Assert(result.err, gc.ErrorMatches, "login not approved before the device code expired")
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/juju/errors"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
+
+	"github.com/juju/juju/apiserver/params"
 )
 
 const authMethod = "juju_userpass"
@@ -20,6 +22,7 @@ const authMethod = "juju_userpass"
 type Visitor struct {
 	username    string
 	getPassword func(string) (string, error)
+	getMFACode  func(string) (string, error)
 }
 
 // NewVisitor returns a new Visitor.
@@ -30,6 +33,17 @@ func NewVisitor(username string, getPassword func(string) (string, error)) *Visi
 	}
 }
 
+// NewMFAVisitor returns a new Visitor that, if the controller
+// requires it, also provides a multi-factor authentication code
+// obtained by calling getMFACode.
+func NewMFAVisitor(username string, getPassword, getMFACode func(string) (string, error)) *Visitor {
+	return &Visitor{
+		username:    username,
+		getPassword: getPassword,
+		getMFACode:  getMFACode,
+	}
+}
+
 // VisitWebPage is part of the httpbakery.Visitor interface.
 func (v *Visitor) VisitWebPage(client *httpbakery.Client, methodURLs map[string]*url.URL) error {
 	methodURL := methodURLs[authMethod]
@@ -43,10 +57,26 @@ func (v *Visitor) VisitWebPage(client *httpbakery.Client, methodURLs map[string]
 	}
 
 	// POST to the URL with username and password.
-	resp, err := client.PostForm(methodURL.String(), url.Values{
+	form := url.Values{
 		"user":     {v.username},
 		"password": {password},
-	})
+	}
+	err = postForm(client, methodURL, form)
+	if err, ok := err.(*httpbakery.Error); ok && err.Code == params.CodeMFARequired && v.getMFACode != nil {
+		// The password was accepted, but the user must
+		// also provide a multi-factor authentication code.
+		code, err := v.getMFACode(v.username)
+		if err != nil {
+			return err
+		}
+		form.Set("mfa-code", code)
+		return postForm(client, methodURL, form)
+	}
+	return err
+}
+
+func postForm(client *httpbakery.Client, methodURL *url.URL, form url.Values) error {
+	resp, err := client.PostForm(methodURL.String(), form)
 	if err != nil {
 		return err
 	}
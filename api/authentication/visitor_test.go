@@ -80,6 +80,42 @@ func (s *VisitorSuite) TestVisitWebPageErrorResult(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "bleh")
 }
 
+func (s *VisitorSuite) TestVisitWebPageMFA(c *gc.C) {
+	v := authentication.NewMFAVisitor("bob", func(username string) (string, error) {
+		return "hunter2", nil
+	}, func(username string) (string, error) {
+		c.Assert(username, gc.Equals, "bob")
+		return "123456", nil
+	})
+	var formCodes []string
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		c.Check(r.Form.Get("password"), gc.Equals, "hunter2")
+		formCodes = append(formCodes, r.Form.Get("mfa-code"))
+		if r.Form.Get("mfa-code") == "" {
+			http.Error(w, `{"Code":"multi-factor authentication code required","Message":"code required"}`, http.StatusUnauthorized)
+		}
+	})
+	err := v.VisitWebPage(s.client, map[string]*url.URL{
+		"juju_userpass": mustParseURL(s.server.URL),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(formCodes, jc.DeepEquals, []string{"", "123456"})
+}
+
+func (s *VisitorSuite) TestVisitWebPageMFANotSupported(c *gc.C) {
+	v := authentication.NewVisitor("bob", func(username string) (string, error) {
+		return "hunter2", nil
+	})
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"Code":"multi-factor authentication code required","Message":"code required"}`, http.StatusUnauthorized)
+	})
+	err := v.VisitWebPage(s.client, map[string]*url.URL{
+		"juju_userpass": mustParseURL(s.server.URL),
+	})
+	c.Assert(err, gc.ErrorMatches, "code required")
+}
+
 func mustParseURL(s string) *url.URL {
 	u, err := url.Parse(s)
 	if err != nil {
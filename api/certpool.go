@@ -13,23 +13,28 @@ import (
 	"github.com/juju/utils/cert"
 	"github.com/juju/utils/series"
 
+	jujucert "github.com/juju/juju/cert"
 	"github.com/juju/juju/juju/paths"
 )
 
 var certDir = filepath.FromSlash(paths.MustSucceed(paths.CertDir(series.MustHostSeries())))
 
 // CreateCertPool creates a new x509.CertPool and adds in the caCert passed
-// in.  All certs from the cert directory (/etc/juju/cert.d on ubuntu) are
+// in. The caCert may be a bundle of several certificates, as it is while
+// the controller CA is being rotated, in which case all are trusted. All
+// certs from the cert directory (/etc/juju/cert.d on ubuntu) are
 // also added.
 func CreateCertPool(caCert string) (*x509.CertPool, error) {
 
 	pool := x509.NewCertPool()
 	if caCert != "" {
-		xcert, err := cert.ParseCert(caCert)
+		xcerts, err := jujucert.ParseCerts(caCert)
 		if err != nil {
 			return nil, errors.Annotatef(err, "cannot parse certificate %q", caCert)
 		}
-		pool.AddCert(xcert)
+		for _, xcert := range xcerts {
+			pool.AddCert(xcert)
+		}
 	}
 
 	count := processCertDir(pool)
@@ -45,6 +45,14 @@ func (*certPoolSuite) TestCreateCertPoolTestCert(c *gc.C) {
 	c.Assert(pool.Subjects(), gc.HasLen, 1)
 }
 
+func (*certPoolSuite) TestCreateCertPoolBundle(c *gc.C) {
+	otherCACert, _, err := cert.NewCA("other", "1", time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	pool, err := api.CreateCertPool(testing.CACert + otherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool.Subjects(), gc.HasLen, 2)
+}
+
 func (s *certPoolSuite) TestCreateCertPoolNoDir(c *gc.C) {
 	certDir := filepath.Join(c.MkDir(), "missing")
 	s.PatchValue(api.CertDir, certDir)
@@ -257,6 +257,43 @@ func (c *Client) ConfigSet(values map[string]interface{}) error {
 	)
 }
 
+// ControllerCARotation returns the controller CA rotation in progress.
+// The result's phase is empty if there is none.
+func (c *Client) ControllerCARotation() (params.ControllerCARotation, error) {
+	var result params.ControllerCARotation
+	if c.BestAPIVersion() < 6 {
+		return result, errors.NotSupportedf("controller CA rotation")
+	}
+	err := c.facade.FacadeCall("ControllerCARotation", nil, &result)
+	return result, errors.Trace(err)
+}
+
+// StartControllerCARotation starts rotating the controller CA to a
+// newly generated CA, which is trusted alongside the current one.
+func (c *Client) StartControllerCARotation() error {
+	return c.caRotationCall("StartControllerCARotation")
+}
+
+// ReissueControllerCerts makes the new CA of the rotation in progress
+// the controller's signing CA, so controllers reissue their server
+// certificates with it.
+func (c *Client) ReissueControllerCerts() error {
+	return c.caRotationCall("ReissueControllerCerts")
+}
+
+// FinishControllerCARotation completes the rotation in progress, so
+// that only the new CA is trusted.
+func (c *Client) FinishControllerCARotation() error {
+	return c.caRotationCall("FinishControllerCARotation")
+}
+
+func (c *Client) caRotationCall(request string) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotSupportedf("controller CA rotation")
+	}
+	return errors.Trace(c.facade.FacadeCall(request, nil, nil))
+}
+
 // MigrationSpec holds the details required to start the migration of
 // a single model.
 type MigrationSpec struct {
@@ -372,6 +372,58 @@ func (s *Suite) TestConfigSet(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "ruth mundy")
 }
 
+func (s *Suite) TestControllerCARotation(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(objType, gc.Equals, "Controller")
+			c.Assert(version, gc.Equals, 6)
+			c.Assert(request, gc.Equals, "ControllerCARotation")
+			c.Assert(args, gc.IsNil)
+			*result.(*params.ControllerCARotation) = params.ControllerCARotation{
+				Phase: "trusting",
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	rotation, err := client.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Phase, gc.Equals, "trusting")
+}
+
+func (s *Suite) TestControllerCARotationSteps(c *gc.C) {
+	var calls []string
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(objType, gc.Equals, "Controller")
+			c.Assert(args, gc.IsNil)
+			c.Assert(result, gc.IsNil)
+			calls = append(calls, request)
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	c.Assert(client.StartControllerCARotation(), jc.ErrorIsNil)
+	c.Assert(client.ReissueControllerCerts(), jc.ErrorIsNil)
+	c.Assert(client.FinishControllerCARotation(), jc.ErrorIsNil)
+	c.Assert(calls, jc.DeepEquals, []string{
+		"StartControllerCARotation",
+		"ReissueControllerCerts",
+		"FinishControllerCARotation",
+	})
+}
+
+func (s *Suite) TestControllerCARotationAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 5}
+	client := controller.NewClient(apiCaller)
+	_, err := client.ControllerCARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.StartControllerCARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *Suite) TestConfigSetAgainstOlderAPIVersion(c *gc.C) {
 	apiCaller := apitesting.BestVersionCaller{BestVersion: 4}
 	client := controller.NewClient(apiCaller)
@@ -0,0 +1,98 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+	"github.com/juju/errors"
+	netproxy "golang.org/x/net/proxy"
+
+	"github.com/juju/juju/utils/proxy"
+)
+
+// dialFunc adapts a function to the golang.org/x/net/proxy.Dialer
+// interface.
+type dialFunc func(network, addr string) (net.Conn, error)
+
+// Dial implements netproxy.Dialer.
+func (f dialFunc) Dial(network, addr string) (net.Conn, error) {
+	return f(network, addr)
+}
+
+// socks5Dial returns a function dialing through the SOCKS5 proxy
+// at proxyURL, using forward to reach the proxy itself.
+func socks5Dial(proxyURL *url.URL, forward dialFunc) (dialFunc, error) {
+	var auth *netproxy.Auth
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		auth = &netproxy.Auth{
+			User:     proxyURL.User.Username(),
+			Password: password,
+		}
+	}
+	dialer, err := netproxy.SOCKS5("tcp", proxyURL.Host, auth, forward)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating SOCKS5 dialer")
+	}
+	return dialer.Dial, nil
+}
+
+// useControllerProxy configures the websocket dialer to connect
+// through the given controller proxy. When proxyURL is nil, the
+// process-wide proxy settings are used.
+func useControllerProxy(dialer *websocket.Dialer, proxyURL *url.URL) error {
+	if proxyURL == nil {
+		dialer.Proxy = proxy.DefaultConfig.GetProxy
+		return nil
+	}
+	if err := validateControllerProxy(proxyURL); err != nil {
+		return errors.Trace(err)
+	}
+	if proxyURL.Scheme == "http" {
+		dialer.Proxy = http.ProxyURL(proxyURL)
+		return nil
+	}
+	forward := dialFunc(net.Dial)
+	if dialer.NetDial != nil {
+		forward = dialer.NetDial
+	}
+	dial, err := socks5Dial(proxyURL, forward)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	dialer.Proxy = nil
+	dialer.NetDial = dial
+	return nil
+}
+
+// useControllerProxyForHTTP configures the HTTP transport used for
+// requests to the controller to go through the given controller
+// proxy. The transport is left untouched when proxyURL is nil.
+func useControllerProxyForHTTP(transport *http.Transport, proxyURL *url.URL) error {
+	if proxyURL == nil {
+		return nil
+	}
+	if err := validateControllerProxy(proxyURL); err != nil {
+		return errors.Trace(err)
+	}
+	if proxyURL.Scheme == "http" {
+		transport.Proxy = http.ProxyURL(proxyURL)
+		return nil
+	}
+	forward := dialFunc(net.Dial)
+	if transport.Dial != nil {
+		forward = transport.Dial
+	}
+	dial, err := socks5Dial(proxyURL, forward)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	transport.Proxy = nil
+	transport.Dial = dial
+	return nil
+}
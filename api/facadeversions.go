@@ -13,12 +13,12 @@ package api
 var facadeVersions = map[string]int{
 	"Action":                       2,
 	"ActionPruner":                 1,
-	"Agent":                        2,
+	"Agent":                        3,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  6,
+	"Application":                  7,
 	"ApplicationOffers":            1,
 	"ApplicationScaler":            1,
 	"Backups":                      1,
@@ -34,7 +34,7 @@ var facadeVersions = map[string]int{
 	"Cleaner":                      2,
 	"Client":                       1,
 	"Cloud":                        2,
-	"Controller":                   5,
+	"Controller":                   6,
 	"CrossController":              1,
 	"CrossModelRelations":          1,
 	"Deployer":                     1,
@@ -46,11 +46,11 @@ var facadeVersions = map[string]int{
 	"Firewaller":                   5,
 	"FirewallRules":                1,
 	"HighAvailability":             2,
-	"HostKeyReporter":              1,
+	"HostKeyReporter":              2,
 	"ImageManager":                 2,
 	"ImageMetadata":                3,
 	"ImageMetadataManager":         1,
-	"InstancePoller":               3,
+	"InstancePoller":               4,
 	"KeyManager":                   1,
 	"KeyUpdater":                   1,
 	"LeadershipService":            2,
@@ -58,8 +58,9 @@ var facadeVersions = map[string]int{
 	"LogForwarding":                1,
 	"Logger":                       1,
 	"MachineActions":               1,
-	"MachineManager":               4,
+	"MachineManager":               6,
 	"MachineUndertaker":            1,
+	"MachineUsage":                 1,
 	"Machiner":                     1,
 	"MeterStatus":                  1,
 	"MetricsAdder":                 2,
@@ -78,7 +79,7 @@ var facadeVersions = map[string]int{
 	"Payloads":                     1,
 	"PayloadsHookContext":          1,
 	"Pinger":                       1,
-	"Provisioner":                  5,
+	"Provisioner":                  6,
 	"ProxyUpdater":                 1,
 	"Reboot":                       2,
 	"RelationStatusWatcher":        1,
@@ -90,7 +91,7 @@ var facadeVersions = map[string]int{
 	"RetryStrategy":                1,
 	"Singular":                     2,
 	"Spaces":                       3,
-	"SSHClient":                    2,
+	"SSHClient":                    3,
 	"StatusHistory":                2,
 	"Storage":                      4,
 	"StorageProvisioner":           4,
@@ -98,9 +99,9 @@ var facadeVersions = map[string]int{
 	"Subnets":                      2,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       8,
+	"Uniter":                       9,
 	"Upgrader":                     1,
-	"UserManager":                  2,
+	"UserManager":                  5,
 	"VolumeAttachmentsWatcher":     2,
 }
 
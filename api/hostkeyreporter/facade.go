@@ -6,6 +6,7 @@
 package hostkeyreporter
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
@@ -39,3 +40,29 @@ func (f *Facade) ReportKeys(machineId string, publicKeys []string) error {
 	}
 	return result.OneError()
 }
+
+// SignKeys asks the controller to sign the public SSH host keys of a
+// machine, returning a host certificate, in authorized_keys format,
+// for each key in the same order. It returns an error satisfying
+// errors.IsNotSupported if the controller cannot sign host keys.
+func (f *Facade) SignKeys(machineId string, publicKeys []string) ([]string, error) {
+	if f.caller.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("signing SSH host keys")
+	}
+	args := params.SSHHostKeySet{EntityKeys: []params.SSHHostKeys{{
+		Tag:        names.NewMachineTag(machineId).String(),
+		PublicKeys: publicKeys,
+	}}}
+	var result params.SSHHostKeyCertificatesResults
+	err := f.caller.FacadeCall("SignKeys", args, &result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(result.Results))
+	}
+	if err := result.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return result.Results[0].Certificates, nil
+}
@@ -4,8 +4,7 @@
 package hostkeyreporter_test
 
 import (
-	"errors"
-
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -87,3 +86,56 @@ func (s *facadeSuite) TestInnerError(c *gc.C) {
 	err := facade.ReportKeys("42", []string{"rsa", "dsa"})
 	c.Assert(err, gc.ErrorMatches, "blam")
 }
+
+func (s *facadeSuite) TestSignKeys(c *gc.C) {
+	stub := new(testing.Stub)
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(func(
+			objType string, version int,
+			id, request string,
+			args, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "HostKeyReporter")
+			stub.AddCall(request, args)
+			*response.(*params.SSHHostKeyCertificatesResults) = params.SSHHostKeyCertificatesResults{
+				Results: []params.SSHHostKeyCertificatesResult{{
+					Certificates: []string{"rsa-cert", "dsa-cert"},
+				}},
+			}
+			return nil
+		}),
+		BestVersion: 2,
+	}
+	facade := hostkeyreporter.NewFacade(apiCaller)
+
+	certs, err := facade.SignKeys("42", []string{"rsa", "dsa"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(certs, jc.DeepEquals, []string{"rsa-cert", "dsa-cert"})
+
+	stub.CheckCalls(c, []testing.StubCall{{
+		"SignKeys", []interface{}{params.SSHHostKeySet{
+			EntityKeys: []params.SSHHostKeys{{
+				Tag:        names.NewMachineTag("42").String(),
+				PublicKeys: []string{"rsa", "dsa"},
+			}},
+		}},
+	}})
+}
+
+func (s *facadeSuite) TestSignKeysNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(func(
+			objType string, version int,
+			id, request string,
+			args, response interface{},
+		) error {
+			c.Fatalf("unexpected call to %s.%s", objType, request)
+			return nil
+		}),
+		BestVersion: 1,
+	}
+	facade := hostkeyreporter.NewFacade(apiCaller)
+
+	_, err := facade.SignKeys("42", []string{"rsa", "dsa"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -96,6 +96,28 @@ func (m *Machine) IsManual() (bool, error) {
 	return result.Result, nil
 }
 
+// AgentPresence returns whether the machine's agent is connected to
+// the controller.
+func (m *Machine) AgentPresence() (bool, error) {
+	var results params.BoolResults
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: m.tag.String()},
+	}}
+	err := m.facade.FacadeCall("AgentPresence", args, &results)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		err := errors.Errorf("expected 1 result, got %d", len(results.Results))
+		return false, err
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
 // InstanceId returns the machine's instance id.
 func (m *Machine) InstanceId() (instance.Id, error) {
 	var results params.StringResults
@@ -151,6 +173,19 @@ func (m *Machine) SetInstanceStatus(status status.Status, message string, data m
 	return result.OneError()
 }
 
+// SetStatus sets the status of the machine.
+func (m *Machine) SetStatus(status status.Status, message string, data map[string]interface{}) error {
+	var result params.ErrorResults
+	args := params.SetStatus{Entities: []params.EntityStatusArgs{
+		{Tag: m.tag.String(), Status: status.String(), Info: message, Data: data},
+	}}
+	err := m.facade.FacadeCall("SetStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // ProviderAddresses returns all addresses of the machine known to the
 // cloud provider.
 func (m *Machine) ProviderAddresses() ([]network.Address, error) {
@@ -188,3 +223,42 @@ func (m *Machine) SetProviderAddresses(addrs ...network.Address) error {
 	}
 	return result.OneError()
 }
+
+// Replace replaces the machine with a new machine, moving its units to
+// the replacement, and forcibly destroys the machine. The ID of the
+// replacement machine is returned; it is returned along with the error
+// if the machine was replaced but could not be destroyed.
+func (m *Machine) Replace() (string, error) {
+	var results params.StringResults
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: m.tag.String()},
+	}}
+	err := m.facade.FacadeCall("ReplaceMachines", args, &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		err := errors.Errorf("expected 1 result, got %d", len(results.Results))
+		return "", err
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Result, result.Error
+	}
+	return result.Result, nil
+}
+
+// ForceDestroy forcibly destroys the machine. It is used to retry
+// destroying a machine that has been replaced, if Replace could not
+// destroy it.
+func (m *Machine) ForceDestroy() error {
+	var result params.ErrorResults
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: m.tag.String()},
+	}}
+	err := m.facade.FacadeCall("DestroyMachines", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
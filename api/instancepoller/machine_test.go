@@ -72,6 +72,24 @@ var machineErrorTests = []struct {
 		return err
 	},
 	resultsRef: params.BoolResults{},
+}, {
+	method: "AgentPresence",
+	wrapper: func(m *instancepoller.Machine) error {
+		_, err := m.AgentPresence()
+		return err
+	},
+	resultsRef: params.BoolResults{},
+}, {
+	method: "Replace",
+	wrapper: func(m *instancepoller.Machine) error {
+		_, err := m.Replace()
+		return err
+	},
+	resultsRef: params.StringResults{},
+}, {
+	method:     "ForceDestroy",
+	wrapper:    (*instancepoller.Machine).ForceDestroy,
+	resultsRef: params.ErrorResults{},
 }, {
 	method: "InstanceId",
 	wrapper: func(m *instancepoller.Machine) error {
@@ -99,6 +117,12 @@ var machineErrorTests = []struct {
 		return m.SetInstanceStatus("", "", nil)
 	},
 	resultsRef: params.ErrorResults{},
+}, {
+	method: "SetStatus",
+	wrapper: func(m *instancepoller.Machine) error {
+		return m.SetStatus("", "", nil)
+	},
+	resultsRef: params.ErrorResults{},
 }, {
 	method: "ProviderAddresses",
 	wrapper: func(m *instancepoller.Machine) error {
@@ -188,6 +212,55 @@ func (s *MachineSuite) TestIsManualSuccess(c *gc.C) {
 	c.Check(apiCaller.CallCount, gc.Equals, 1)
 }
 
+func (s *MachineSuite) TestAgentPresenceSuccess(c *gc.C) {
+	results := params.BoolResults{
+		Results: []params.BoolResult{{Result: true}},
+	}
+	apiCaller := successAPICaller(c, "AgentPresence", entitiesArgs, results)
+	machine := instancepoller.NewMachine(apiCaller, s.tag, params.Alive)
+	alive, err := machine.AgentPresence()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(alive, jc.IsTrue)
+	c.Check(apiCaller.CallCount, gc.Equals, 1)
+}
+
+func (s *MachineSuite) TestReplaceSuccess(c *gc.C) {
+	results := params.StringResults{
+		Results: []params.StringResult{{Result: "43"}},
+	}
+	apiCaller := successAPICaller(c, "ReplaceMachines", entitiesArgs, results)
+	machine := instancepoller.NewMachine(apiCaller, s.tag, params.Alive)
+	replacement, err := machine.Replace()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(replacement, gc.Equals, "43")
+	c.Check(apiCaller.CallCount, gc.Equals, 1)
+}
+
+func (s *MachineSuite) TestReplaceDestroyFailure(c *gc.C) {
+	results := params.StringResults{
+		Results: []params.StringResult{{
+			Result: "43",
+			Error:  apiservertesting.ServerError("destroying machine 42 after replacing it with machine 43: boom"),
+		}},
+	}
+	apiCaller := successAPICaller(c, "ReplaceMachines", entitiesArgs, results)
+	machine := instancepoller.NewMachine(apiCaller, s.tag, params.Alive)
+	replacement, err := machine.Replace()
+	c.Check(err, gc.ErrorMatches, "destroying machine 42 after replacing it with machine 43: boom")
+	c.Check(replacement, gc.Equals, "43")
+	c.Check(apiCaller.CallCount, gc.Equals, 1)
+}
+
+func (s *MachineSuite) TestForceDestroySuccess(c *gc.C) {
+	results := params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	}
+	apiCaller := successAPICaller(c, "DestroyMachines", entitiesArgs, results)
+	machine := instancepoller.NewMachine(apiCaller, s.tag, params.Alive)
+	c.Check(machine.ForceDestroy(), jc.ErrorIsNil)
+	c.Check(apiCaller.CallCount, gc.Equals, 1)
+}
+
 func (s *MachineSuite) TestInstanceIdSuccess(c *gc.C) {
 	results := params.StringResults{
 		Results: []params.StringResult{{Result: "i-foo"}},
@@ -230,6 +303,23 @@ func (s *MachineSuite) TestSetInstanceStatusSuccess(c *gc.C) {
 	c.Check(apiCaller.CallCount, gc.Equals, 1)
 }
 
+func (s *MachineSuite) TestSetStatusSuccess(c *gc.C) {
+	expectArgs := params.SetStatus{
+		Entities: []params.EntityStatusArgs{{
+			Tag:    "machine-42",
+			Status: "error",
+			Info:   "cannot recover lost machine",
+		}}}
+	results := params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	}
+	apiCaller := successAPICaller(c, "SetStatus", expectArgs, results)
+	machine := instancepoller.NewMachine(apiCaller, s.tag, params.Alive)
+	err := machine.SetStatus(status.Error, "cannot recover lost machine", nil)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(apiCaller.CallCount, gc.Equals, 1)
+}
+
 func (s *MachineSuite) TestProviderAddressesSuccess(c *gc.C) {
 	addresses := network.NewAddresses("2001:db8::1", "0.1.2.3")
 	results := params.MachineAddressesResults{
@@ -78,6 +78,17 @@ type Info struct {
 	// authenticate with the API server.
 	Macaroons []macaroon.Slice `yaml:",omitempty"`
 
+	// Token holds an API token that may be used to authenticate
+	// with the API server instead of a password. If Tag is also
+	// set, it must be the tag of the token's owner.
+	Token string `yaml:",omitempty"`
+
+	// IDToken holds an OpenID Connect identity token, issued by the
+	// controller's OpenID Connect provider, that may be used to
+	// authenticate with the API server. The user is identified by
+	// the token, so Tag need not be set.
+	IDToken string `yaml:"-"`
+
 	// Nonce holds the nonce used when provisioning the machine. Used
 	// only by the machine agent.
 	Nonce string `yaml:",omitempty"`
@@ -115,6 +126,12 @@ func (info *Info) Validate() error {
 		if len(info.Macaroons) > 0 {
 			return errors.NotValidf("specifying Macaroons and SkipLogin")
 		}
+		if info.Token != "" {
+			return errors.NotValidf("specifying Token and SkipLogin")
+		}
+		if info.IDToken != "" {
+			return errors.NotValidf("specifying IDToken and SkipLogin")
+		}
 	}
 	return nil
 }
@@ -174,6 +191,13 @@ type DialOpts struct {
 	// Clock is used as a time source for retries.
 	// If it is nil, clock.WallClock will be used.
 	Clock clock.Clock
+
+	// ControllerProxy, if set, holds the URL of a proxy through
+	// which all connections to the controller are made, including
+	// streaming connections such as the log sink. Both "http"
+	// (HTTP CONNECT) and "socks5" proxies are supported. When set it
+	// takes precedence over the process-wide proxy settings.
+	ControllerProxy *url.URL
 }
 
 // IPAddrResolver implements a resolved from host name to the
@@ -250,6 +274,11 @@ type Connection interface {
 	// the connection.
 	PublicDNSName() string
 
+	// ControllerCACert returns the controller's CA certificate, as
+	// reported at login. It is empty if the controller did not
+	// report it.
+	ControllerCACert() string
+
 	// These are a bit off -- ServerVersion is apparently not known until after
 	// Login()? Maybe evidence of need for a separate AuthenticatedConnection..?
 	Login(name names.Tag, password, nonce string, ms []macaroon.Slice) error
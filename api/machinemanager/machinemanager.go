@@ -4,6 +4,8 @@
 package machinemanager
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
@@ -85,6 +87,45 @@ func (client *Client) DestroyMachinesWithParams(force, keep bool, machines ...st
 	return allResults, nil
 }
 
+// ReplaceMachines replaces the given set of machines with new machines
+// with the same constraints and placement, moving their units to the
+// replacements and then destroying them. If force is true, the original
+// machines are forcibly destroyed.
+func (client *Client) ReplaceMachines(force bool, machines ...string) ([]params.ReplaceMachineResult, error) {
+	if client.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("replacing machines with this version of Juju")
+	}
+	args := params.ReplaceMachinesParams{
+		Force:       force,
+		MachineTags: make([]string, 0, len(machines)),
+	}
+	allResults := make([]params.ReplaceMachineResult, len(machines))
+	index := make([]int, 0, len(machines))
+	for i, machineId := range machines {
+		if !names.IsValidMachine(machineId) {
+			allResults[i].Error = &params.Error{
+				Message: errors.NotValidf("machine ID %q", machineId).Error(),
+			}
+			continue
+		}
+		index = append(index, i)
+		args.MachineTags = append(args.MachineTags, names.NewMachineTag(machineId).String())
+	}
+	if len(args.MachineTags) > 0 {
+		var result params.ReplaceMachineResults
+		if err := client.facade.FacadeCall("ReplaceMachines", args, &result); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n := len(result.Results); n != len(args.MachineTags) {
+			return nil, errors.Errorf("expected %d result(s), got %d", len(args.MachineTags), n)
+		}
+		for i, result := range result.Results {
+			allResults[index[i]] = result
+		}
+	}
+	return allResults, nil
+}
+
 func (client *Client) destroyMachines(method string, machines []string) ([]params.DestroyMachineResult, error) {
 	args := params.Entities{
 		Entities: make([]params.Entity, 0, len(machines)),
@@ -135,3 +176,29 @@ func (client *Client) UpdateMachineSeries(machineName, series string, force bool
 	}
 	return results.OneError()
 }
+
+// InstanceTypeRecommendations returns instance type recommendations for
+// the specified applications, or for all applications in the model if
+// none are specified, based on the resources used by their machines
+// over the given window. Headroom is the fraction of the observed usage
+// to add when sizing the recommended instance types.
+func (client *Client) InstanceTypeRecommendations(
+	applications []string, window time.Duration, headroom float64,
+) ([]params.InstanceTypeRecommendationResult, error) {
+	if client.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("instance type recommendations with this version of Juju")
+	}
+	args := params.InstanceTypeRecommendationsArgs{
+		Applications: applications,
+		Window:       window,
+		Headroom:     headroom,
+	}
+	var results params.InstanceTypeRecommendationsResults
+	if err := client.facade.FacadeCall("InstanceTypeRecommendations", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(applications) > 0 && len(results.Results) != len(applications) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(applications), len(results.Results))
+	}
+	return results.Results, nil
+}
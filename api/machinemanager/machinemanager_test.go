@@ -6,6 +6,7 @@ package machinemanager_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -210,3 +211,91 @@ func (s *MachinemanagerSuite) TestDestroyMachinesWithParams(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
+
+func (s *MachinemanagerSuite) TestInstanceTypeRecommendations(c *gc.C) {
+	expectedResults := []params.InstanceTypeRecommendationResult{{
+		Result: &params.InstanceTypeRecommendation{
+			Application: "mysql",
+			Action:      params.RecommendDownsize,
+			Recommended: params.InstanceType{Name: "small"},
+		},
+	}}
+	caller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Check(objType, gc.Equals, "MachineManager")
+			c.Check(version, gc.Equals, 5)
+			c.Check(request, gc.Equals, "InstanceTypeRecommendations")
+			c.Check(a, jc.DeepEquals, params.InstanceTypeRecommendationsArgs{
+				Applications: []string{"mysql"},
+				Window:       time.Hour,
+				Headroom:     0.2,
+			})
+			c.Assert(response, gc.FitsTypeOf, &params.InstanceTypeRecommendationsResults{})
+			out := response.(*params.InstanceTypeRecommendationsResults)
+			*out = params.InstanceTypeRecommendationsResults{expectedResults}
+			return nil
+		},
+		BestVersion: 5,
+	}
+	client := machinemanager.NewClient(caller)
+	results, err := client.InstanceTypeRecommendations([]string{"mysql"}, time.Hour, 0.2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectedResults)
+}
+
+func (s *MachinemanagerSuite) TestInstanceTypeRecommendationsNotSupported(c *gc.C) {
+	caller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+		BestVersion: 4,
+	}
+	client := machinemanager.NewClient(caller)
+	_, err := client.InstanceTypeRecommendations(nil, time.Hour, 0)
+	c.Assert(err, gc.ErrorMatches, "instance type recommendations with this version of Juju not supported")
+}
+
+func (s *MachinemanagerSuite) TestReplaceMachines(c *gc.C) {
+	expectedResults := []params.ReplaceMachineResult{{
+		Error: &params.Error{Message: `machine ID "!" not valid`},
+	}, {
+		Info: &params.ReplaceMachineInfo{
+			Replacement: "machine-1",
+			MovedUnits:  []params.Entity{{"unit-foo-0"}},
+		},
+	}}
+	caller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Check(objType, gc.Equals, "MachineManager")
+			c.Check(version, gc.Equals, 6)
+			c.Check(request, gc.Equals, "ReplaceMachines")
+			c.Check(a, jc.DeepEquals, params.ReplaceMachinesParams{
+				MachineTags: []string{"machine-0"},
+				Force:       true,
+			})
+			c.Assert(response, gc.FitsTypeOf, &params.ReplaceMachineResults{})
+			out := response.(*params.ReplaceMachineResults)
+			*out = params.ReplaceMachineResults{expectedResults[1:]}
+			return nil
+		},
+		BestVersion: 6,
+	}
+	client := machinemanager.NewClient(caller)
+	results, err := client.ReplaceMachines(true, "!", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectedResults)
+}
+
+func (s *MachinemanagerSuite) TestReplaceMachinesNotSupported(c *gc.C) {
+	caller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		},
+		BestVersion: 5,
+	}
+	client := machinemanager.NewClient(caller)
+	_, err := client.ReplaceMachines(false, "0")
+	c.Assert(err, gc.ErrorMatches, "replacing machines with this version of Juju not supported")
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machineusage implements the client-side API facade used
+// by the machineusage worker.
+package machineusage
+
+import (
+	"time"
+
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Usage holds a sample of the resources used by a machine.
+type Usage struct {
+	// Time is the time at which the sample was taken.
+	Time time.Time
+
+	// CPU is the percentage of the machine's total CPU capacity
+	// in use, from 0 to 100.
+	CPU float64
+
+	// Cores is the number of CPU cores available to the machine.
+	Cores uint64
+
+	// MemoryUsed, MemoryTotal, DiskUsed and DiskTotal hold the
+	// memory and root disk space in use, and available, in MiB.
+	MemoryUsed  uint64
+	MemoryTotal uint64
+	DiskUsed    uint64
+	DiskTotal   uint64
+}
+
+// Facade provides access to the MachineUsage API facade.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade creates a new client-side MachineUsage facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "MachineUsage"),
+	}
+}
+
+// RecordUsage records a sample of the resources used by a machine.
+func (f *Facade) RecordUsage(machineId string, usage Usage) error {
+	args := params.MachineUsageSamples{Samples: []params.MachineUsageSample{{
+		Tag:         names.NewMachineTag(machineId).String(),
+		Time:        usage.Time,
+		CPU:         usage.CPU,
+		Cores:       usage.Cores,
+		MemoryUsed:  usage.MemoryUsed,
+		MemoryTotal: usage.MemoryTotal,
+		DiskUsed:    usage.DiskUsed,
+		DiskTotal:   usage.DiskTotal,
+	}}}
+	var result params.ErrorResults
+	err := f.caller.FacadeCall("RecordUsage", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineusage_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/machineusage"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type facadeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+var usage = machineusage.Usage{
+	Time:        time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+	CPU:         25,
+	Cores:       2,
+	MemoryUsed:  512,
+	MemoryTotal: 2048,
+	DiskUsed:    1024,
+	DiskTotal:   8192,
+}
+
+func (s *facadeSuite) TestRecordUsage(c *gc.C) {
+	stub := new(testing.Stub)
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "MachineUsage")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		stub.AddCall(request, args)
+		*response.(*params.ErrorResults) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				(*params.Error)(nil),
+			}},
+		}
+		return nil
+	})
+	facade := machineusage.NewFacade(apiCaller)
+
+	err := facade.RecordUsage("42", usage)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stub.CheckCalls(c, []testing.StubCall{{
+		"RecordUsage", []interface{}{params.MachineUsageSamples{
+			Samples: []params.MachineUsageSample{{
+				Tag:         names.NewMachineTag("42").String(),
+				Time:        usage.Time,
+				CPU:         25,
+				Cores:       2,
+				MemoryUsed:  512,
+				MemoryTotal: 2048,
+				DiskUsed:    1024,
+				DiskTotal:   8192,
+			}},
+		}},
+	}})
+}
+
+func (s *facadeSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		return errors.New("blam")
+	})
+	facade := machineusage.NewFacade(apiCaller)
+
+	err := facade.RecordUsage("42", usage)
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *facadeSuite) TestInnerError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		*response.(*params.ErrorResults) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				&params.Error{Message: "blam"},
+			}},
+		}
+		return nil
+	})
+	facade := machineusage.NewFacade(apiCaller)
+
+	err := facade.RecordUsage("42", usage)
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
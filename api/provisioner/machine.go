@@ -12,6 +12,7 @@ import (
 
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/watcher"
@@ -248,6 +249,26 @@ func (m *Machine) AvailabilityZone() (string, error) {
 	return result.Result, nil
 }
 
+// Constraints returns the machine's constraints.
+func (m *Machine) Constraints() (constraints.Value, error) {
+	var results params.ConstraintsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("Constraints", args, &results)
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	if len(results.Results) != 1 {
+		return constraints.Value{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return constraints.Value{}, result.Error
+	}
+	return result.Constraints, nil
+}
+
 // DistributionGroup returns a slice of instance.Ids
 // that belong to the same distribution group as this
 // Machine. The provisioner may use this information
@@ -418,6 +439,28 @@ func (m *Machine) WatchAllContainers() (watcher.StringsWatcher, error) {
 	return w, nil
 }
 
+// WatchUnits returns a StringsWatcher that notifies of changes to
+// the units assigned to the machine.
+func (m *Machine) WatchUnits() (watcher.StringsWatcher, error) {
+	var results params.StringsWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("WatchUnits", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewStringsWatcher(m.st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
 // SetSupportedContainers updates the list of containers supported by this machine.
 func (m *Machine) SetSupportedContainers(containerTypes ...instance.ContainerType) error {
 	var results params.ErrorResults
@@ -393,6 +393,20 @@ func (s *provisionerSuite) TestKeepInstance(c *gc.C) {
 	c.Assert(keep, jc.IsTrue)
 }
 
+func (s *provisionerSuite) TestConstraints(c *gc.C) {
+	cons := constraints.MustParse("mem=4G interruptible=true")
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: cons,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	apiMachine := s.assertGetOneMachine(c, machine.MachineTag())
+	result, err := apiMachine.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, cons)
+}
+
 func (s *provisionerSuite) TestDistributionGroup(c *gc.C) {
 	apiMachine := s.assertGetOneMachine(c, s.machine.MachineTag())
 	instances, err := apiMachine.DistributionGroup()
@@ -586,6 +600,30 @@ func (s *provisionerSuite) TestWatchContainers(c *gc.C) {
 	wc.AssertChange(container.Id())
 }
 
+func (s *provisionerSuite) TestWatchUnits(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	apiMachine := s.assertGetOneMachine(c, machine.MachineTag())
+
+	w, err := apiMachine.WatchUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewStringsWatcherC(c, w, s.BackingState.StartSync)
+	defer wc.AssertStops()
+
+	// Initial event.
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	// Assign a unit to the machine and make sure it's detected.
+	application := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := application.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(unit.Name())
+	wc.AssertNoChange()
+}
+
 func (s *provisionerSuite) TestWatchContainersAcceptsSupportedContainers(c *gc.C) {
 	apiMachine := s.assertGetOneMachine(c, s.machine.MachineTag())
 
@@ -111,6 +111,24 @@ func (facade *Facade) Proxy() (bool, error) {
 	return out.UseProxy, nil
 }
 
+// SignUserKey asks the controller to sign the given SSH public key,
+// in authorized_keys format, returning a short-lived user certificate
+// in the same format. It returns an error satisfying
+// errors.IsNotSupported if the controller cannot sign keys.
+func (facade *Facade) SignUserKey(publicKey string) (string, error) {
+	if facade.BestAPIVersion() < 3 {
+		return "", errors.NotSupportedf("signing SSH keys")
+	}
+	var out params.SSHSignUserKeyResult
+	err := facade.caller.FacadeCall("SignUserKey", params.SSHSignUserKeyArg{
+		PublicKey: publicKey,
+	}, &out)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return out.Certificate, nil
+}
+
 func targetToEntities(target string) (params.Entities, error) {
 	tag, err := targetToTag(target)
 	if err != nil {
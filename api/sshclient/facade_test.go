@@ -280,3 +280,37 @@ func (s *FacadeSuite) TestProxyError(c *gc.C) {
 	_, err := facade.Proxy()
 	c.Check(err, gc.ErrorMatches, "boom")
 }
+
+func (s *FacadeSuite) TestSignUserKey(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*result.(*params.SSHSignUserKeyResult) = params.SSHSignUserKeyResult{
+				Certificate: "ssh-rsa-cert-v01@openssh.com AAAA\n",
+			}
+			return nil
+		}),
+		BestVersion: 3,
+	}
+	facade := sshclient.NewFacade(apiCaller)
+	cert, err := facade.SignUserKey("ssh-rsa AAAA igor@example.com")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cert, gc.Equals, "ssh-rsa-cert-v01@openssh.com AAAA\n")
+	stub.CheckCalls(c, []jujutesting.StubCall{{"SSHClient.SignUserKey", []interface{}{
+		params.SSHSignUserKeyArg{PublicKey: "ssh-rsa AAAA igor@example.com"},
+	}}})
+}
+
+func (s *FacadeSuite) TestSignUserKeyNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s.%s", objType, request)
+			return nil
+		}),
+		BestVersion: 2,
+	}
+	facade := sshclient.NewFacade(apiCaller)
+	_, err := facade.SignUserKey("ssh-rsa AAAA igor@example.com")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -44,6 +44,8 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 		Nonce:       nonce,
 		Macaroons:   macaroons,
 		CLIArgs:     utils.CommandString(os.Args...),
+		Token:       st.token,
+		IDToken:     st.idToken,
 	}
 	// If we are in developer mode, add the stack location as user data to the
 	// login request. This will allow the apiserver to connect connection ids
@@ -52,7 +54,7 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 		request.UserData = string(debug.Stack())
 	}
 
-	if password == "" {
+	if password == "" && st.token == "" && st.idToken == "" {
 		// Add any macaroons from the cookie jar that might work for
 		// authenticating the login request.
 		request.Macaroons = append(request.Macaroons,
@@ -131,6 +133,7 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 		controllerTag:    result.ControllerTag,
 		servers:          servers,
 		publicDNSName:    result.PublicDNSName,
+		controllerCACert: result.ControllerCACert,
 		facades:          result.Facades,
 		modelAccess:      modelAccess,
 		controllerAccess: controllerAccess,
@@ -153,6 +156,7 @@ type loginResultParams struct {
 	servers          [][]network.HostPort
 	facades          []params.FacadeVersions
 	publicDNSName    string
+	controllerCACert string
 }
 
 func (st *state) setLoginResult(p loginResultParams) error {
@@ -185,6 +189,7 @@ func (st *state) setLoginResult(p loginResultParams) error {
 	}
 	st.hostPorts = hostPorts
 	st.publicDNSName = p.publicDNSName
+	st.controllerCACert = p.controllerCACert
 
 	st.facadeVersions = make(map[string][]int, len(p.facades))
 	for _, facade := range p.facades {
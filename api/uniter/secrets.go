@@ -0,0 +1,164 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/watcher"
+)
+
+// secretsAPIVersion is the first version of the Uniter facade that
+// supports secrets.
+const secretsAPIVersion = 9
+
+func (u *Unit) checkSecretsSupported(method string) error {
+	if u.st.facade.BestAPIVersion() < secretsAPIVersion {
+		return errors.NotSupportedf("%s() (need V%d+)", method, secretsAPIVersion)
+	}
+	return nil
+}
+
+// SetSecret creates or updates the named secret owned by the unit's
+// application. The secret is due to be rotated every rotateInterval,
+// unless that is zero. Only the application's leader may set secrets.
+func (u *Unit) SetSecret(name string, values map[string]string, rotateInterval time.Duration) error {
+	if err := u.checkSecretsSupported("SetSecret"); err != nil {
+		return err
+	}
+	var results params.ErrorResults
+	args := params.SetSecretArgs{
+		Args: []params.SetSecretArg{{
+			UnitTag:        u.tag.String(),
+			Name:           name,
+			Values:         values,
+			RotateInterval: rotateInterval,
+		}},
+	}
+	if err := u.st.facade.FacadeCall("SetSecrets", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// Secret returns the values of the named secret. If application is
+// empty, the secret is owned by the unit's application; otherwise it is
+// owned by the named application, which must have shared it with the
+// unit's application.
+func (u *Unit) Secret(name, application string) (map[string]string, error) {
+	if err := u.checkSecretsSupported("Secret"); err != nil {
+		return nil, err
+	}
+	var results params.SecretValuesResults
+	args := params.GetSecretArgs{
+		Args: []params.GetSecretArg{{
+			UnitTag:     u.tag.String(),
+			Name:        name,
+			Application: application,
+		}},
+	}
+	if err := u.st.facade.FacadeCall("GetSecrets", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Values, nil
+}
+
+// ShareSecret shares the named secret owned by the unit's application
+// with the named related application. Only the application's leader
+// may share secrets.
+func (u *Unit) ShareSecret(name, application string) error {
+	return u.shareSecret(name, application, false)
+}
+
+// UnshareSecret stops sharing the named secret owned by the unit's
+// application with the named application. Only the application's
+// leader may unshare secrets.
+func (u *Unit) UnshareSecret(name, application string) error {
+	return u.shareSecret(name, application, true)
+}
+
+func (u *Unit) shareSecret(name, application string, revoke bool) error {
+	if err := u.checkSecretsSupported("ShareSecret"); err != nil {
+		return err
+	}
+	var results params.ErrorResults
+	args := params.ShareSecretArgs{
+		Args: []params.ShareSecretArg{{
+			UnitTag:     u.tag.String(),
+			Name:        name,
+			Application: application,
+			Revoke:      revoke,
+		}},
+	}
+	if err := u.st.facade.FacadeCall("ShareSecrets", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// SecretRotated records that the named secret owned by the unit's
+// application has been rotated.
+func (u *Unit) SecretRotated(name string) error {
+	return u.secretCall("SecretsRotated", name)
+}
+
+// RemoveSecret removes the named secret owned by the unit's
+// application. Only the application's leader may remove secrets.
+func (u *Unit) RemoveSecret(name string) error {
+	return u.secretCall("RemoveSecrets", name)
+}
+
+func (u *Unit) secretCall(request, name string) error {
+	if err := u.checkSecretsSupported(request); err != nil {
+		return err
+	}
+	var results params.ErrorResults
+	args := params.SecretArgs{
+		Args: []params.SecretArg{{
+			UnitTag: u.tag.String(),
+			Name:    name,
+		}},
+	}
+	if err := u.st.facade.FacadeCall(request, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// WatchSecretRotations returns a StringsWatcher that notifies of the
+// names of the secrets owned by the unit's application that are due
+// to be rotated.
+func (u *Unit) WatchSecretRotations() (watcher.StringsWatcher, error) {
+	if err := u.checkSecretsSupported("WatchSecretRotations"); err != nil {
+		return nil, err
+	}
+	var results params.StringsWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchSecretRotations", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewStringsWatcher(u.st.facade.RawAPICaller(), result)
+	return w, nil
+}
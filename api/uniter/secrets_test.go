@@ -0,0 +1,126 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type secretsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) newUnit(c *gc.C, expectRequest string, expectArg interface{}, response interface{}) *uniter.Unit {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Assert(objType, gc.Equals, "Uniter")
+		c.Assert(version, gc.Equals, expectedAPIVersion)
+		c.Assert(id, gc.Equals, "")
+		c.Assert(request, gc.Equals, expectRequest)
+		c.Assert(arg, jc.DeepEquals, expectArg)
+		switch result := result.(type) {
+		case *params.ErrorResults:
+			*result = response.(params.ErrorResults)
+		case *params.SecretValuesResults:
+			*result = response.(params.SecretValuesResults)
+		default:
+			c.Fatalf("unexpected result type %T", result)
+		}
+		return nil
+	})
+	st := uniter.NewState(apiCaller, names.NewUnitTag("mysql/0"))
+	return uniter.CreateUnit(st, names.NewUnitTag("mysql/0"))
+}
+
+func (s *secretsSuite) TestSetSecret(c *gc.C) {
+	unit := s.newUnit(c, "SetSecrets", params.SetSecretArgs{
+		Args: []params.SetSecretArg{{
+			UnitTag:        "unit-mysql-0",
+			Name:           "root-password",
+			Values:         map[string]string{"password": "sekrit"},
+			RotateInterval: time.Hour,
+		}},
+	}, params.ErrorResults{Results: []params.ErrorResult{{
+		Error: &params.Error{Message: "not leader"},
+	}}})
+	err := unit.SetSecret("root-password", map[string]string{"password": "sekrit"}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, "not leader")
+}
+
+func (s *secretsSuite) TestSecret(c *gc.C) {
+	unit := s.newUnit(c, "GetSecrets", params.GetSecretArgs{
+		Args: []params.GetSecretArg{{
+			UnitTag:     "unit-mysql-0",
+			Name:        "salt",
+			Application: "wordpress",
+		}},
+	}, params.SecretValuesResults{Results: []params.SecretValuesResult{{
+		Values: map[string]string{"salt": "pepper"},
+	}}})
+	values, err := unit.Secret("salt", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{"salt": "pepper"})
+}
+
+func (s *secretsSuite) TestUnshareSecret(c *gc.C) {
+	unit := s.newUnit(c, "ShareSecrets", params.ShareSecretArgs{
+		Args: []params.ShareSecretArg{{
+			UnitTag:     "unit-mysql-0",
+			Name:        "root-password",
+			Application: "wordpress",
+			Revoke:      true,
+		}},
+	}, params.ErrorResults{Results: []params.ErrorResult{{}}})
+	err := unit.UnshareSecret("root-password", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) TestSecretRotated(c *gc.C) {
+	unit := s.newUnit(c, "SecretsRotated", params.SecretArgs{
+		Args: []params.SecretArg{{
+			UnitTag: "unit-mysql-0",
+			Name:    "root-password",
+		}},
+	}, params.ErrorResults{Results: []params.ErrorResult{{}}})
+	err := unit.SecretRotated("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) TestRemoveSecret(c *gc.C) {
+	unit := s.newUnit(c, "RemoveSecrets", params.SecretArgs{
+		Args: []params.SecretArg{{
+			UnitTag: "unit-mysql-0",
+			Name:    "root-password",
+		}},
+	}, params.ErrorResults{Results: []params.ErrorResult{{}}})
+	err := unit.RemoveSecret("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) TestSecretsNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	st := uniter.NewStateV8(apiCaller, names.NewUnitTag("mysql/0"))
+	unit := uniter.CreateUnit(st, names.NewUnitTag("mysql/0"))
+
+	err := unit.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = unit.Secret("root-password", "")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = unit.WatchSecretRotations()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -207,3 +207,260 @@ func (c *Client) ResetPassword(username string) ([]byte, error) {
 	}
 	return result.SecretKey, nil
 }
+
+// AddAPIToken creates an API token owned by the logged in user,
+// returning the value to be presented at login.
+func (c *Client) AddAPIToken(args params.AddAPIToken) (string, error) {
+	if c.BestAPIVersion() < 3 {
+		return "", errors.NotSupportedf("API tokens with this version of Juju")
+	}
+	in := params.AddAPITokens{Tokens: []params.AddAPIToken{args}}
+	var out params.AddAPITokenResults
+	if err := c.facade.FacadeCall("AddAPITokens", in, &out); err != nil {
+		return "", errors.Trace(err)
+	}
+	if count := len(out.Results); count != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", count)
+	}
+	result := out.Results[0]
+	if result.Error != nil {
+		return "", errors.Trace(result.Error)
+	}
+	return result.Token, nil
+}
+
+// APITokens returns information on the API tokens owned by the logged
+// in user.
+func (c *Client) APITokens() ([]params.APITokenInfo, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("API tokens with this version of Juju")
+	}
+	var out params.APITokenInfoResults
+	if err := c.facade.FacadeCall("APITokens", nil, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out.Results, nil
+}
+
+// RevokeAPIToken revokes the named API token owned by the logged in
+// user.
+func (c *Client) RevokeAPIToken(name string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("API tokens with this version of Juju")
+	}
+	in := params.RevokeAPITokens{Names: []string{name}}
+	var out params.ErrorResults
+	if err := c.facade.FacadeCall("RevokeAPITokens", in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
+
+// EnableMFA starts enrolling the logged in user for multi-factor
+// authentication, returning the secret to add to their authenticator
+// application. Enrolment is completed by calling ConfirmMFA.
+func (c *Client) EnableMFA() (params.MFAEnrolment, error) {
+	var out params.MFAEnrolment
+	if c.BestAPIVersion() < 4 {
+		return out, errors.NotSupportedf("multi-factor authentication with this version of Juju")
+	}
+	if err := c.facade.FacadeCall("EnableMFA", nil, &out); err != nil {
+		return out, errors.Trace(err)
+	}
+	return out, nil
+}
+
+// ConfirmMFA enables multi-factor authentication for the logged in
+// user, given a code generated from the secret returned by EnableMFA.
+func (c *Client) ConfirmMFA(code string) error {
+	if c.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("multi-factor authentication with this version of Juju")
+	}
+	in := params.MFACode{Code: code}
+	return errors.Trace(c.facade.FacadeCall("ConfirmMFA", in, nil))
+}
+
+// DisableMFA disables multi-factor authentication for the given user.
+// A code from the user's authenticator application is required when
+// disabling it for the logged in user.
+func (c *Client) DisableMFA(username, code string) error {
+	if c.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("multi-factor authentication with this version of Juju")
+	}
+	if !names.IsValidUser(username) {
+		return errors.NotValidf("user name %q", username)
+	}
+	in := params.DisableMFAArgs{
+		Entities: []params.DisableMFAArg{{
+			Tag:  names.NewUserTag(username).String(),
+			Code: code,
+		}},
+	}
+	var out params.ErrorResults
+	if err := c.facade.FacadeCall("DisableMFA", in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
+
+// AddUserGroup creates a new, empty, user group.
+func (c *Client) AddUserGroup(name string) error {
+	return c.userGroupsCall("AddUserGroups", name)
+}
+
+// RemoveUserGroup removes a user group, along with all access granted
+// to it.
+func (c *Client) RemoveUserGroup(name string) error {
+	return c.userGroupsCall("RemoveUserGroups", name)
+}
+
+func (c *Client) userGroupsCall(method, name string) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("user groups with this version of Juju")
+	}
+	in := params.UserGroupNames{Names: []string{name}}
+	var out params.ErrorResults
+	if err := c.facade.FacadeCall(method, in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
+
+// AddUserGroupMembers adds the given users to a user group.
+func (c *Client) AddUserGroupMembers(group string, usernames ...string) error {
+	return c.userGroupMembersCall("AddUserGroupMembers", group, usernames)
+}
+
+// RemoveUserGroupMembers removes the given users from a user group.
+func (c *Client) RemoveUserGroupMembers(group string, usernames ...string) error {
+	return c.userGroupMembersCall("RemoveUserGroupMembers", group, usernames)
+}
+
+func (c *Client) userGroupMembersCall(method, group string, usernames []string) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("user groups with this version of Juju")
+	}
+	member := params.UserGroupMember{Group: group}
+	for _, username := range usernames {
+		if !names.IsValidUser(username) {
+			return errors.NotValidf("user name %q", username)
+		}
+		member.UserTags = append(member.UserTags, names.NewUserTag(username).String())
+	}
+	in := params.UserGroupMembers{Changes: []params.UserGroupMember{member}}
+	var out params.ErrorResults
+	if err := c.facade.FacadeCall(method, in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
+
+// AddUserGroupExternalGroups maps the given external identity provider
+// groups, as "name@domain", to a user group.
+func (c *Client) AddUserGroupExternalGroups(group string, externalGroups ...string) error {
+	return c.userGroupExternalGroupsCall("AddUserGroupExternalGroups", group, externalGroups)
+}
+
+// RemoveUserGroupExternalGroups removes the mappings of the given
+// external identity provider groups to a user group.
+func (c *Client) RemoveUserGroupExternalGroups(group string, externalGroups ...string) error {
+	return c.userGroupExternalGroupsCall("RemoveUserGroupExternalGroups", group, externalGroups)
+}
+
+func (c *Client) userGroupExternalGroupsCall(method, group string, externalGroups []string) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("user groups with this version of Juju")
+	}
+	in := params.UserGroupExternalGroups{Changes: []params.UserGroupExternalGroup{{
+		Group:          group,
+		ExternalGroups: externalGroups,
+	}}}
+	var out params.ErrorResults
+	if err := c.facade.FacadeCall(method, in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
+
+// UserGroupInfo returns information on the named user groups, or on
+// all the groups visible to the logged in user if no names are given.
+func (c *Client) UserGroupInfo(groups ...string) ([]params.UserGroupInfo, error) {
+	if c.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("user groups with this version of Juju")
+	}
+	in := params.UserGroupNames{Names: groups}
+	var out params.UserGroupInfoResults
+	if err := c.facade.FacadeCall("UserGroupInfo", in, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var info []params.UserGroupInfo
+	for i, result := range out.Results {
+		if result.Error != nil {
+			annotation := "all groups"
+			if len(groups) > 0 {
+				annotation = groups[i]
+			}
+			return nil, errors.Annotate(result.Error, annotation)
+		}
+		info = append(info, *result.Result)
+	}
+	return info, nil
+}
+
+// GrantUserGroup grants the given access to a user group on a model,
+// or on the controller.
+func (c *Client) GrantUserGroup(group, access string, target names.Tag) error {
+	return c.modifyUserGroupAccess(params.ModifyUserGroupAccess{
+		Group:     group,
+		Action:    params.GrantUserGroupAccess,
+		Access:    access,
+		TargetTag: target.String(),
+	})
+}
+
+// RevokeUserGroup revokes the given access from a user group on a
+// model, or on the controller.
+func (c *Client) RevokeUserGroup(group, access string, target names.Tag) error {
+	return c.modifyUserGroupAccess(params.ModifyUserGroupAccess{
+		Group:     group,
+		Action:    params.RevokeUserGroupAccess,
+		Access:    access,
+		TargetTag: target.String(),
+	})
+}
+
+// GrantUserGroupOffer grants the given access to a user group on the
+// offer with the given URL.
+func (c *Client) GrantUserGroupOffer(group, access, offerURL string) error {
+	return c.modifyUserGroupAccess(params.ModifyUserGroupAccess{
+		Group:    group,
+		Action:   params.GrantUserGroupAccess,
+		Access:   access,
+		OfferURL: offerURL,
+	})
+}
+
+// RevokeUserGroupOffer revokes the given access from a user group on
+// the offer with the given URL.
+func (c *Client) RevokeUserGroupOffer(group, access, offerURL string) error {
+	return c.modifyUserGroupAccess(params.ModifyUserGroupAccess{
+		Group:    group,
+		Action:   params.RevokeUserGroupAccess,
+		Access:   access,
+		OfferURL: offerURL,
+	})
+}
+
+func (c *Client) modifyUserGroupAccess(change params.ModifyUserGroupAccess) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("user groups with this version of Juju")
+	}
+	in := params.ModifyUserGroupAccessRequest{
+		Changes: []params.ModifyUserGroupAccess{change},
+	}
+	var out params.ErrorResults
+	if err := c.facade.FacadeCall("ModifyUserGroupAccess", in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
@@ -4,12 +4,15 @@
 package usermanager_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	apitesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/usermanager"
+	"github.com/juju/juju/apiserver/authentication/totp"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
@@ -270,3 +273,100 @@ func (s *usermanagerSuite) TestResetPasswordResultCount(c *gc.C) {
 	_, err := client.ResetPassword("foobar")
 	c.Assert(err, gc.ErrorMatches, "expected 1 result, got 2")
 }
+
+func (s *usermanagerSuite) TestAPITokens(c *gc.C) {
+	expires := time.Now().Add(time.Hour).Round(time.Second).UTC()
+	value, err := s.usermanager.AddAPIToken(params.AddAPIToken{
+		Name:     "ci",
+		Expires:  expires,
+		ReadOnly: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(value, gc.Not(gc.Equals), "")
+
+	tokens, err := s.usermanager.APITokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 1)
+	c.Assert(tokens[0].Name, gc.Equals, "ci")
+	c.Assert(tokens[0].ReadOnly, jc.IsTrue)
+	c.Assert(tokens[0].Expires, gc.Equals, expires)
+
+	err = s.usermanager.RevokeAPIToken("ci")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.RevokeAPIToken("ci")
+	c.Assert(err, gc.ErrorMatches, `token "ci" for "admin" not found`)
+}
+
+func (s *usermanagerSuite) TestMFA(c *gc.C) {
+	enrolment, err := s.usermanager.EnableMFA()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enrolment.Secret, gc.Not(gc.Equals), "")
+
+	err = s.usermanager.ConfirmMFA("000000x")
+	c.Assert(err, gc.ErrorMatches, "cannot confirm multi-factor authentication: code not valid")
+	code, err := totp.Code(enrolment.Secret, totp.Counter(time.Now()))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.ConfirmMFA(code)
+	c.Assert(err, jc.ErrorIsNil)
+
+	user, err := s.State.User(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsTrue)
+
+	err = s.usermanager.DisableMFA("admin", "")
+	c.Assert(err, gc.ErrorMatches, "cannot disable multi-factor authentication: code not valid")
+	code, err = totp.Code(enrolment.Secret, totp.Counter(time.Now())+1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.DisableMFA("admin", code)
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+}
+
+func (s *usermanagerSuite) TestUserGroups(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+
+	err := s.usermanager.AddUserGroup("devops")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.AddUserGroupMembers("devops", "alex")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.AddUserGroupExternalGroups("devops", "sre@ldap", "ops@oidc")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.GrantUserGroup("devops", "write", s.IAASModel.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.usermanager.UserGroupInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, gc.HasLen, 1)
+	c.Assert(info[0].Name, gc.Equals, "devops")
+	c.Assert(info[0].Members, jc.DeepEquals, []string{"alex"})
+	c.Assert(info[0].ExternalGroups, jc.DeepEquals, []string{"ops@oidc", "sre@ldap"})
+	access, err := s.State.UserGroupAccess("devops", s.IAASModel.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(access), gc.Equals, "write")
+
+	err = s.usermanager.RevokeUserGroup("devops", "read", s.IAASModel.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.RemoveUserGroupMembers("devops", "alex")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.RemoveUserGroupExternalGroups("devops", "ops@oidc")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.RemoveUserGroup("devops")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.usermanager.UserGroupInfo("devops")
+	c.Assert(err, gc.ErrorMatches, `devops: group "devops" not found`)
+}
+
+func (s *usermanagerSuite) TestUserGroupsNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		}),
+		BestVersion: 4,
+	}
+	client := usermanager.NewClient(apiCaller)
+	err := client.AddUserGroup("devops")
+	c.Assert(err, gc.ErrorMatches, "user groups with this version of Juju not supported")
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
@@ -73,6 +74,25 @@ func (a *admin) RedirectInfo() (params.RedirectInfoResult, error) {
 	return params.RedirectInfoResult{}, fmt.Errorf("not redirected")
 }
 
+// OIDCLoginInfo returns the details clients need to obtain an
+// identity token from the controller's OpenID Connect provider
+// before logging in. The result is empty if OpenID Connect logins
+// are not configured.
+func (a *admin) OIDCLoginInfo() (params.OIDCLoginInfoResult, error) {
+	controllerConfig, err := a.root.state.ControllerConfig()
+	if err != nil {
+		return params.OIDCLoginInfoResult{}, errors.Trace(err)
+	}
+	issuerURL := controllerConfig.OIDCIssuerURL()
+	if issuerURL == "" {
+		return params.OIDCLoginInfoResult{}, nil
+	}
+	return params.OIDCLoginInfoResult{
+		IssuerURL: issuerURL,
+		ClientID:  controllerConfig.OIDCClientID(),
+	}, nil
+}
+
 var MaintenanceNoLoginError = errors.New("login failed - maintenance in progress")
 var errAlreadyLoggedIn = errors.New("already logged in")
 
@@ -96,6 +116,7 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		logger.Infof("login failed with discharge-required error: %v", err)
 		return loginResult, nil
 	}
+	a.recordLogin(req, authResult, err)
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
@@ -141,6 +162,13 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		facadeFilters = append(facadeFilters, IsModelFacade)
 		modelTag = a.root.model.Tag().String()
 	}
+	if authResult.apiToken != nil {
+		if facades := set.NewStrings(authResult.apiToken.Facades()...); !facades.IsEmpty() {
+			facadeFilters = append(facadeFilters, func(name string) bool {
+				return name == "Pinger" || facades.Contains(name)
+			})
+		}
+	}
 
 	auditConfig := a.srv.GetAuditConfig()
 	auditRecorder, err := a.getAuditRecorder(req, authResult, auditConfig)
@@ -151,15 +179,22 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 	recorderFactory := observer.NewRecorderFactory(
 		a.apiObserver, auditRecorder, auditConfig.CaptureAPIArgs)
 
+	controllerConfig, err := a.root.state.ControllerConfig()
+	if err != nil {
+		return fail, errors.Trace(err)
+	}
+	caCert, _ := controllerConfig.CACert()
+
 	a.root.rpcConn.ServeRoot(apiRoot, recorderFactory, serverError)
 	return params.LoginResult{
-		Servers:       params.FromNetworkHostsPorts(hostPorts),
-		ControllerTag: a.root.model.ControllerTag().String(),
-		UserInfo:      authResult.userInfo,
-		ServerVersion: jujuversion.Current.String(),
-		PublicDNSName: a.srv.publicDNSName(),
-		ModelTag:      modelTag,
-		Facades:       filterFacades(a.srv.facades, facadeFilters...),
+		Servers:          params.FromNetworkHostsPorts(hostPorts),
+		ControllerTag:    a.root.model.ControllerTag().String(),
+		UserInfo:         authResult.userInfo,
+		ServerVersion:    jujuversion.Current.String(),
+		PublicDNSName:    a.srv.publicDNSName(),
+		ControllerCACert: caCert,
+		ModelTag:         modelTag,
+		Facades:          filterFacades(a.srv.facades, facadeFilters...),
 	}, nil
 }
 
@@ -188,6 +223,39 @@ func (a *admin) getAuditRecorder(req params.LoginRequest, authResult *authResult
 	return result, nil
 }
 
+// recordLogin records a user's attempt to log in in the audit log,
+// if auditing is enabled. Agent and anonymous logins, and failed
+// logins that do not name a user, are not recorded.
+func (a *admin) recordLogin(req params.LoginRequest, authResult *authResult, authErr error) {
+	cfg := a.srv.GetAuditConfig()
+	if !cfg.Enabled || cfg.Target == nil {
+		return
+	}
+	var who string
+	if authErr == nil {
+		if !authResult.userLogin || a.root.entity == nil {
+			return
+		}
+		who = a.root.entity.Tag().Id()
+	} else {
+		tag, err := names.ParseUserTag(req.AuthTag)
+		if err != nil || tag.Id() == api.AnonymousUsername {
+			return
+		}
+		who = tag.Id()
+	}
+	err := auditlog.RecordLogin(cfg.Target, a.srv.clock, auditlog.LoginArgs{
+		Who:           who,
+		ModelUUID:     a.root.modelUUID,
+		ConnectionID:  a.root.connectionID,
+		RemoteAddress: a.root.remoteAddr,
+		Error:         authErr,
+	})
+	if err != nil {
+		logger.Errorf("couldn't add login to audit log: %v", err)
+	}
+}
+
 type authResult struct {
 	tag                    names.Tag // nil if external user login
 	anonymousLogin         bool
@@ -195,6 +263,14 @@ type authResult struct {
 	controllerOnlyLogin    bool
 	controllerMachineLogin bool
 	userInfo               *params.AuthUserInfo
+
+	// apiToken holds the API token used to log in, if any. Its
+	// scope limits what the connection may do.
+	apiToken *state.APIToken
+
+	// mfaEnrolmentRequired records whether the user must enable
+	// multi-factor authentication before doing anything else.
+	mfaEnrolmentRequired bool
 }
 
 func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
@@ -260,6 +336,10 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 			// We only need to run a pinger for controller machine
 			// agents when logging into the controller model.
 			startPinger = false
+		} else if req.Token != "" {
+			if result.apiToken, err = a.checkAPITokenScope(req.Token, result.controllerOnlyLogin); err != nil {
+				return nil, errors.Trace(err)
+			}
 		}
 	}
 	a.loggedIn = true
@@ -278,6 +358,7 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 			// presence pinger in the dependency engine also.
 		}
 		a.root.entity = entity
+		a.root.apiToken = result.apiToken
 		a.apiObserver.Login(entity.Tag(), a.root.model.ModelTag(), result.controllerMachineLogin, req.UserData)
 	}
 
@@ -286,12 +367,62 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 			return nil, errors.Trace(err)
 		}
 	}
+	if result.userLogin {
+		if result.mfaEnrolmentRequired, err = a.mfaEnrolmentRequired(entity); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	if err := a.fillLoginDetails(result, lastConnection); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return result, nil
 }
 
+// mfaEnrolmentRequired reports whether the given user must enable
+// multi-factor authentication before using the API, because the
+// controller requires local users to do so.
+func (a *admin) mfaEnrolmentRequired(entity state.Entity) (bool, error) {
+	user, ok := entity.(interface {
+		MFAEnabled() bool
+	})
+	if !ok || user.MFAEnabled() {
+		return false, nil
+	}
+	if tag, ok := entity.Tag().(names.UserTag); !ok || !tag.IsLocal() {
+		return false, nil
+	}
+	controllerConfig, err := a.root.state.ControllerConfig()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return controllerConfig.RequireMFA(), nil
+}
+
+// checkAPITokenScope returns the API token with the given value,
+// which has already been authenticated, checking that it may be used
+// to log in to the model being connected to. A token limited to models
+// may not be used to log in to the controller.
+func (a *admin) checkAPITokenScope(value string, controllerOnlyLogin bool) (*state.APIToken, error) {
+	id, _, err := state.ParseAPIToken(value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	token, err := a.root.state.APIToken(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if controllerOnlyLogin {
+		if len(token.Models()) > 0 {
+			logger.Debugf("API token %q limited to models not valid for controller login", token.Name())
+			return nil, errors.Trace(common.ErrPerm)
+		}
+	} else if !apiTokenAllowsModel(token.Models(), a.root.model.ModelTag()) {
+		logger.Debugf("API token %q not valid for model %s", token.Name(), a.root.model.UUID())
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	return token, nil
+}
+
 func (a *admin) handleAuthError(
 	req params.LoginRequest,
 	authTag names.Tag,
@@ -383,6 +514,14 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 	} else {
 		return nil, errors.Annotatef(err, "obtaining ControllerUser for logged in user %s", userTag.Id())
 	}
+	// Controller access may also be inherited from the user's groups.
+	groupAccess, err := a.root.state.EffectiveUserPermission(userTag, a.root.state.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Annotatef(err, "obtaining group controller access for logged in user %s", userTag.Id())
+	}
+	if groupAccess.GreaterControllerAccessThan(controllerAccess) {
+		controllerAccess = groupAccess
+	}
 	if !controllerOnlyLogin {
 		// Only grab modelUser permissions if this is not a controller only
 		// login. In all situations, if the model user is not found, they have
@@ -390,7 +529,18 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 		// admin.
 
 		var err error
-		modelAccess, err = a.root.state.UserPermission(userTag, a.root.model.ModelTag())
+		modelAccess, err = a.root.state.EffectiveUserPermission(userTag, a.root.model.ModelTag())
+		if errors.IsNotFound(err) && controllerAccess != permission.SuperuserAccess {
+			// Users who have only been granted access to some of
+			// the model's applications may log in to use those.
+			hasAppAccess, appErr := a.root.state.HasApplicationAccess(userTag)
+			if appErr != nil {
+				return nil, errors.Annotatef(appErr, "obtaining application access for logged in user %s", userTag.Id())
+			}
+			if hasAppAccess {
+				modelAccess, err = permission.NoAccess, nil
+			}
+		}
 		if err != nil && controllerAccess != permission.SuperuserAccess {
 			return nil, errors.Wrap(err, common.ErrPerm)
 		}
@@ -714,6 +864,63 @@ func (u *modelUserEntity) UpdateLastLogin() error {
 	return nil
 }
 
+// IsLocked implements authentication.passwordUser by reporting
+// whether the local user has been locked out.
+func (u *modelUserEntity) IsLocked() bool {
+	return u.user != nil && u.user.IsLocked()
+}
+
+// RecordFailedLogin implements authentication.passwordUser by
+// recording a failed login for the local user.
+func (u *modelUserEntity) RecordFailedLogin(threshold int, lockout time.Duration) error {
+	if u.user == nil {
+		return nil
+	}
+	return u.user.RecordFailedLogin(threshold, lockout)
+}
+
+// ResetFailedLogins implements authentication.passwordUser by
+// clearing the local user's failed logins.
+func (u *modelUserEntity) ResetFailedLogins() error {
+	if u.user == nil {
+		return nil
+	}
+	return u.user.ResetFailedLogins()
+}
+
+// PasswordChanged implements authentication.passwordUser by returning
+// when the local user's password was last set.
+func (u *modelUserEntity) PasswordChanged() time.Time {
+	if u.user == nil {
+		return time.Time{}
+	}
+	return u.user.PasswordChanged()
+}
+
+// MFAEnabled implements authentication.passwordUser by reporting
+// whether the local user has enabled multi-factor authentication.
+func (u *modelUserEntity) MFAEnabled() bool {
+	return u.user != nil && u.user.MFAEnabled()
+}
+
+// MFASecret implements authentication.passwordUser by returning the
+// local user's multi-factor authentication secret.
+func (u *modelUserEntity) MFASecret() string {
+	if u.user == nil {
+		return ""
+	}
+	return u.user.MFASecret()
+}
+
+// UseMFACounter implements authentication.passwordUser by recording
+// the use of a multi-factor authentication code by the local user.
+func (u *modelUserEntity) UseMFACounter(counter int64) error {
+	if u.user == nil {
+		return errors.New("cannot use multi-factor authentication code for external user")
+	}
+	return u.user.UseMFACounter(counter)
+}
+
 // presenceShim exists to represent a statepresence.Agent in a form
 // convenient to the apiserver/presence package, which exists to work
 // around the common.Resources infrastructure's lack of handling for
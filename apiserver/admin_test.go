@@ -684,6 +684,80 @@ func (s *loginSuite) TestControllerModel(c *gc.C) {
 	s.assertRemoteModel(c, st, s.IAASModel.ModelTag())
 }
 
+func (s *loginSuite) TestLoginRequireMFA(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		corecontroller.RequireMFA: true,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info, srv := newServer(c, s.StatePool)
+	defer assertStop(c, srv)
+
+	info.ModelTag = names.ModelTag{}
+	st := s.openAPIWithoutLogin(c, info)
+	err = st.Login(s.AdminUserTag(c), "dummy-secret", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Until the user enables multi-factor authentication, they
+	// may only enrol.
+	err = st.APICall("ModelManager", 4, "", "ListModels", params.Entity{
+		Tag: s.AdminUserTag(c).String(),
+	}, &params.UserModelList{})
+	c.Assert(err, gc.ErrorMatches, `multi-factor authentication required by controller; run "juju enable-mfa" first`)
+	var enrolment params.MFAEnrolment
+	err = st.APICall("UserManager", 4, "", "EnableMFA", nil, &enrolment)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enrolment.Secret, gc.Not(gc.Equals), "")
+}
+
+func (s *loginSuite) TestOIDCLoginInfo(c *gc.C) {
+	info, srv := newServer(c, s.StatePool)
+	defer assertStop(c, srv)
+	info.ModelTag = names.ModelTag{}
+
+	var result params.OIDCLoginInfoResult
+	err := s.openAPIWithoutLogin(c, info).APICall("Admin", 3, "", "OIDCLoginInfo", nil, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.OIDCLoginInfoResult{})
+
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		corecontroller.OIDCIssuerURL: "https://id.example.com",
+		corecontroller.OIDCClientID:  "juju",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.openAPIWithoutLogin(c, info).APICall("Admin", 3, "", "OIDCLoginInfo", nil, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.OIDCLoginInfoResult{
+		IssuerURL: "https://id.example.com",
+		ClientID:  "juju",
+	})
+}
+
+func (s *loginSuite) TestAPITokenLimitedToModelsControllerLogin(c *gc.C) {
+	_, value, err := s.State.AddAPIToken(state.APITokenSpec{
+		Name:    "ci",
+		Owner:   s.AdminUserTag(c),
+		Expires: time.Now().Add(time.Hour),
+		Models:  []names.ModelTag{s.IAASModel.ModelTag()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	info, srv := newServer(c, s.StatePool)
+	defer assertStop(c, srv)
+
+	request := &params.LoginRequest{Token: value}
+	var result params.LoginResult
+
+	info.ModelTag = s.IAASModel.ModelTag()
+	err = s.openAPIWithoutLogin(c, info).APICall("Admin", 3, "", "Login", request, &result)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info.ModelTag = names.ModelTag{}
+	err = s.openAPIWithoutLogin(c, info).APICall("Admin", 3, "", "Login", request, &result)
+	c.Assert(errors.Cause(err), gc.DeepEquals, &rpc.RequestError{
+		Message: "permission denied",
+		Code:    "unauthorized access",
+	})
+}
+
 func (s *loginSuite) TestControllerModelBadCreds(c *gc.C) {
 	info, srv := newServer(c, s.StatePool)
 	defer assertStop(c, srv)
@@ -1007,9 +1081,10 @@ func (s *loginSuite) TestLoginAddsAuditConversationEventually(c *gc.C) {
 	err := conn.APICall("Admin", 3, "", "Login", request, &result)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result.UserInfo, gc.NotNil)
-	// Nothing's logged at this point because there haven't been any
-	// interesting requests.
-	log.CheckCallNames(c)
+	// Only the login is logged at this point because there haven't
+	// been any interesting requests.
+	log.CheckCallNames(c, "AddLogin")
+	log.ResetCalls()
 
 	var addResults params.AddMachinesResults
 	addReq := &params.AddMachines{
@@ -1049,9 +1124,61 @@ func (s *loginSuite) TestLoginAddsAuditConversationEventually(c *gc.C) {
 	})
 }
 
+func (s *loginSuite) TestLoginAddsAuditLogin(c *gc.C) {
+	log := &servertesting.FakeAuditLog{}
+	cfg := defaultServerConfig(c)
+	cfg.GetAuditConfig = func() auditlog.Config {
+		return auditlog.Config{
+			Enabled: true,
+			Target:  log,
+		}
+	}
+	cfg.Clock = jt.NewClock(cfg.Clock.Now())
+	info, srv := newServerWithConfig(c, s.StatePool, cfg)
+	defer assertStop(c, srv)
+	info.ModelTag = s.IAASModel.Tag().(names.ModelTag)
+
+	password := "shhh..."
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: password,
+	})
+	conn := s.openAPIWithoutLogin(c, info)
+
+	var result params.LoginResult
+	request := &params.LoginRequest{
+		AuthTag:     user.Tag().String(),
+		Credentials: "wrong",
+	}
+	err := conn.APICall("Admin", 3, "", "Login", request, &result)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password.*")
+
+	request.Credentials = password
+	err = conn.APICall("Admin", 3, "", "Login", request, &result)
+	c.Assert(err, jc.ErrorIsNil)
+
+	log.CheckCallNames(c, "AddLogin", "AddLogin")
+	failed := log.Calls()[0].Args[0].(auditlog.Login)
+	c.Assert(failed.RemoteAddress, gc.Not(gc.Equals), "")
+	failed.ConnectionID = "something"
+	failed.RemoteAddress = "somewhere"
+	c.Assert(failed, gc.Equals, auditlog.Login{
+		Who:           user.Tag().Id(),
+		When:          cfg.Clock.Now().Format(time.RFC3339),
+		ModelUUID:     s.IAASModel.UUID(),
+		ConnectionID:  "something",
+		RemoteAddress: "somewhere",
+		Error:         "invalid entity name or password",
+	})
+	succeeded := log.Calls()[1].Args[0].(auditlog.Login)
+	c.Assert(succeeded.Who, gc.Equals, user.Tag().Id())
+	c.Assert(succeeded.Success, jc.IsTrue)
+	c.Assert(succeeded.Error, gc.Equals, "")
+}
+
 func (s *loginSuite) TestAuditLoggingFailureOnInterestingRequest(c *gc.C) {
 	log := &servertesting.FakeAuditLog{}
-	log.SetErrors(errors.Errorf("bad news bears"))
+	// Recording the login succeeds.
+	log.SetErrors(nil, errors.Errorf("bad news bears"))
 	cfg := defaultServerConfig(c)
 	cfg.GetAuditConfig = func() auditlog.Config {
 		return auditlog.Config{
@@ -1120,9 +1247,10 @@ func (s *loginSuite) TestAuditLoggingUsesExcludeMethods(c *gc.C) {
 	err := conn.APICall("Admin", 3, "", "Login", request, &result)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result.UserInfo, gc.NotNil)
-	// Nothing's logged at this point because there haven't been any
-	// interesting requests.
-	log.CheckCallNames(c)
+	// Only the login is logged at this point because there haven't
+	// been any interesting requests.
+	log.CheckCallNames(c, "AddLogin")
+	log.ResetCalls()
 
 	var addResults params.AddMachinesResults
 	addReq := &params.AddMachines{
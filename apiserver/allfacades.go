@@ -24,6 +24,7 @@ import (
 	loggerapi "github.com/juju/juju/apiserver/facades/agent/logger"
 	"github.com/juju/juju/apiserver/facades/agent/machine"
 	"github.com/juju/juju/apiserver/facades/agent/machineactions"
+	"github.com/juju/juju/apiserver/facades/agent/machineusage"
 	"github.com/juju/juju/apiserver/facades/agent/meterstatus"
 	"github.com/juju/juju/apiserver/facades/agent/metricsadder"
 	"github.com/juju/juju/apiserver/facades/agent/migrationflag"
@@ -126,6 +127,7 @@ func AllFacades() *facade.Registry {
 	reg("Action", 2, action.NewActionAPI)
 	reg("ActionPruner", 1, actionpruner.NewAPI)
 	reg("Agent", 2, agent.NewAgentAPIV2)
+	reg("Agent", 3, agent.NewAgentAPIV3) // Adds WatchControllerCACert and AcknowledgeControllerCACerts
 	reg("AgentTools", 1, agenttools.NewFacade)
 	reg("Annotations", 2, annotations.NewAPI)
 
@@ -137,6 +139,7 @@ func AllFacades() *facade.Registry {
 	reg("Application", 3, application.NewFacadeV4)
 	reg("Application", 4, application.NewFacadeV4)
 	reg("Application", 5, application.NewFacadeV5) // adds AttachStorage & UpdateApplicationSeries & SetRelationStatus
+	reg("Application", 7, application.NewFacadeV7) // adds ModifyApplicationAccess
 
 	reg("ApplicationOffers", 1, applicationoffers.NewOffersAPI)
 	reg("ApplicationScaler", 1, applicationscaler.NewAPI)
@@ -163,6 +166,7 @@ func AllFacades() *facade.Registry {
 	reg("Controller", 3, controller.NewControllerAPIv3)
 	reg("Controller", 4, controller.NewControllerAPIv4)
 	reg("Controller", 5, controller.NewControllerAPIv5)
+	reg("Controller", 6, controller.NewControllerAPIv6) // Adds ControllerCARotation, StartControllerCARotation, ReissueControllerCerts and FinishControllerCARotation
 	reg("CrossModelRelations", 1, crossmodelrelations.NewStateCrossModelRelationsAPI)
 	reg("CrossController", 1, crosscontroller.NewStateCrossControllerAPI)
 	reg("ExternalControllerUpdater", 1, externalcontrollerupdater.NewStateAPI)
@@ -176,6 +180,7 @@ func AllFacades() *facade.Registry {
 	reg("FirewallRules", 1, firewallrules.NewFacade)
 	reg("HighAvailability", 2, highavailability.NewHighAvailabilityAPI)
 	reg("HostKeyReporter", 1, hostkeyreporter.NewFacade)
+	reg("HostKeyReporter", 2, hostkeyreporter.NewFacade) // v2 adds SignKeys() method.
 	reg("ImageManager", 2, imagemanager.NewImageManagerAPI)
 	reg("ImageMetadata", 3, imagemetadata.NewAPI)
 
@@ -184,6 +189,7 @@ func AllFacades() *facade.Registry {
 	}
 
 	reg("InstancePoller", 3, instancepoller.NewFacade)
+	reg("InstancePoller", 4, instancepoller.NewFacadeV4) // Adds AgentPresence, ReplaceMachines, DestroyMachines and SetStatus.
 	reg("KeyManager", 1, keymanager.NewKeyManagerAPI)
 	reg("KeyUpdater", 1, keyupdater.NewKeyUpdaterAPI)
 	reg("LeadershipService", 2, leadership.NewLeadershipServiceFacade)
@@ -195,8 +201,11 @@ func AllFacades() *facade.Registry {
 	reg("MachineManager", 2, machinemanager.NewFacade)
 	reg("MachineManager", 3, machinemanager.NewFacade)   // Version 3 adds DestroyMachine and ForceDestroyMachine.
 	reg("MachineManager", 4, machinemanager.NewFacadeV4) // Version 4 adds DestroyMachineWithParams.
+	reg("MachineManager", 5, machinemanager.NewFacadeV5) // Version 5 adds InstanceTypeRecommendations.
+	reg("MachineManager", 6, machinemanager.NewFacadeV6) // Version 6 adds ReplaceMachines.
 
 	reg("MachineUndertaker", 1, machineundertaker.NewFacade)
+	reg("MachineUsage", 1, machineusage.NewFacade)
 	reg("Machiner", 1, machine.NewMachinerAPI)
 
 	reg("MeterStatus", 1, meterstatus.NewMeterStatusAPI)
@@ -226,6 +235,7 @@ func AllFacades() *facade.Registry {
 	reg("Provisioner", 3, provisioner.NewProvisionerAPI)
 	reg("Provisioner", 4, provisioner.NewProvisionerAPI)
 	reg("Provisioner", 5, provisioner.NewProvisionerAPIV5) // v5 adds DistributionGroupByMachineId()
+	reg("Provisioner", 6, provisioner.NewProvisionerAPIV6) // v6 adds WatchUnits()
 	reg("ProxyUpdater", 1, proxyupdater.NewAPI)
 	reg("Reboot", 2, reboot.NewRebootAPI)
 	reg("RemoteRelations", 1, remoterelations.NewStateRemoteRelationsAPI)
@@ -243,6 +253,7 @@ func AllFacades() *facade.Registry {
 
 	reg("SSHClient", 1, sshclient.NewFacade)
 	reg("SSHClient", 2, sshclient.NewFacade) // v2 adds AllAddresses() method.
+	reg("SSHClient", 3, sshclient.NewFacade) // v3 adds SignUserKey() method.
 
 	reg("Spaces", 2, spaces.NewAPIV2)
 	reg("Spaces", 3, spaces.NewAPI)
@@ -262,11 +273,15 @@ func AllFacades() *facade.Registry {
 	reg("Uniter", 5, uniter.NewUniterAPIV5)
 	reg("Uniter", 6, uniter.NewUniterAPIV6)
 	reg("Uniter", 7, uniter.NewUniterAPIV7)
-	reg("Uniter", 8, uniter.NewUniterAPI)
+	reg("Uniter", 8, uniter.NewUniterAPIV8)
+	reg("Uniter", 9, uniter.NewUniterAPI)
 
 	reg("Upgrader", 1, upgrader.NewUpgraderFacade)
 	reg("UserManager", 1, usermanager.NewUserManagerAPI)
-	reg("UserManager", 2, usermanager.NewUserManagerAPI) // Adds ResetPassword
+	reg("UserManager", 2, usermanager.NewUserManagerAPI)   // Adds ResetPassword
+	reg("UserManager", 3, usermanager.NewUserManagerAPI)   // Adds AddAPITokens, APITokens and RevokeAPITokens
+	reg("UserManager", 4, usermanager.NewUserManagerAPI)   // Adds EnableMFA, ConfirmMFA and DisableMFA
+	reg("UserManager", 5, usermanager.NewUserManagerAPIV5) // Adds user group methods
 
 	regRaw("AllWatcher", 1, NewAllWatcher, reflect.TypeOf((*SrvAllWatcher)(nil)))
 	// Note: AllModelWatcher uses the same infrastructure as AllWatcher
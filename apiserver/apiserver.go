@@ -83,6 +83,7 @@ type Server struct {
 	totalConn              int64
 	loginAttempts          int64
 	getCertificate         func() *tls.Certificate
+	getExternalCertificate func() *tls.Certificate
 	tlsConfig              *tls.Config
 	allowModelAccess       bool
 	logSinkWriter          io.WriteCloser
@@ -97,10 +98,10 @@ type Server struct {
 	mu sync.Mutex
 
 	// publicDNSName_ holds the value that will be returned in
-	// LoginResult.PublicDNSName. Currently this is set once from
+	// LoginResult.PublicDNSName when there is no external
+	// certificate. Currently this is set once from
 	// AutocertDNSName and does not change but in the future it
-	// may change when a server certificate is explicitly set,
-	// hence it's here guarded by the mutex.
+	// may change, hence it's here guarded by the mutex.
 	publicDNSName_ string
 
 	// registerIntrospectionHandlers is a function that will
@@ -144,6 +145,13 @@ type ServerConfig struct {
 	// new connection is accepted.
 	GetCertificate func() *tls.Certificate
 
+	// GetExternalCertificate holds a function that returns the
+	// current externally provided TLS certificate for the server,
+	// or nil if there is none. If set, the certificate is served
+	// to clients that connect using one of its DNS names, in
+	// preference to the local and autocert certificates.
+	GetExternalCertificate func() *tls.Certificate
+
 	// UpgradeComplete is a function that reports whether or not
 	// the if the agent running the API server has completed
 	// running upgrade steps. This is used by the API server to
@@ -310,6 +318,7 @@ func newServer(stPool *state.StatePool, lis net.Listener, cfg ServerConfig) (_ *
 		facades:                       AllFacades(),
 		centralHub:                    cfg.Hub,
 		getCertificate:                cfg.GetCertificate,
+		getExternalCertificate:        cfg.GetExternalCertificate,
 		allowModelAccess:              cfg.AllowModelAccess,
 		publicDNSName_:                cfg.AutocertDNSName,
 		registerIntrospectionHandlers: cfg.RegisterIntrospectionHandlers,
@@ -409,6 +418,9 @@ func (srv *Server) newTLSConfig(cfg ServerConfig) (*tls.Config, http.Handler) {
 	if cfg.AutocertDNSName == "" {
 		// No official DNS name, no certificate.
 		tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if cert := srv.externalCertificate(clientHello.ServerName); cert != nil {
+				return cert, nil
+			}
 			cert, _ := srv.localCertificate(clientHello.ServerName)
 			return cert, nil
 		}
@@ -426,6 +438,9 @@ func (srv *Server) newTLSConfig(cfg ServerConfig) (*tls.Config, http.Handler) {
 	}
 	tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 		logger.Infof("getting certificate for server name %q", clientHello.ServerName)
+		if cert := srv.externalCertificate(clientHello.ServerName); cert != nil {
+			return cert, nil
+		}
 		// Get the locally created certificate and whether it's appropriate
 		// for the SNI name. If not, we'll try to get an acme cert and
 		// fall back to the local certificate if that fails.
@@ -886,6 +901,7 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 			connectionID,
 			apiObserver,
 			req.Host,
+			req.RemoteAddr,
 		); err != nil {
 			logger.Errorf("error serving RPCs: %v", err)
 		}
@@ -899,6 +915,7 @@ func (srv *Server) serveConn(
 	connectionID uint64,
 	apiObserver observer.Observer,
 	host string,
+	remoteAddr string,
 ) error {
 	codec := jsoncodec.NewWebsocket(wsConn.Conn)
 	recorderFactory := observer.NewRecorderFactory(
@@ -922,7 +939,7 @@ func (srv *Server) serveConn(
 
 	if err == nil {
 		defer st.Release()
-		h, err = newAPIHandler(srv, st.State, conn, modelUUID, connectionID, host)
+		h, err = newAPIHandler(srv, st.State, conn, modelUUID, connectionID, host, remoteAddr)
 	}
 
 	if err != nil {
@@ -946,8 +963,18 @@ func (srv *Server) serveConn(
 	return conn.Close()
 }
 
-// publicDNSName returns the current public hostname.
+// publicDNSName returns the current public hostname. This is the
+// first DNS name of the external certificate, if there is one.
 func (srv *Server) publicDNSName() string {
+	if srv.getExternalCertificate != nil {
+		if cert := srv.getExternalCertificate(); cert != nil {
+			for _, name := range cert.Leaf.DNSNames {
+				if !strings.HasPrefix(name, "*.") {
+					return name
+				}
+			}
+		}
+	}
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
 	return srv.publicDNSName_
@@ -978,6 +1005,26 @@ func (srv *Server) localCertificate(serverName string) (*tls.Certificate, bool)
 	return cert, false
 }
 
+// externalCertificate returns the external server certificate if it
+// should be used to serve a connection addressed to the given server
+// name, or nil otherwise.
+func (srv *Server) externalCertificate(serverName string) *tls.Certificate {
+	if srv.getExternalCertificate == nil {
+		return nil
+	}
+	if net.ParseIP(serverName) != nil || !strings.Contains(serverName, ".") {
+		// As for the local certificate, addresses and names such
+		// as "juju-apiserver" are only served by the local
+		// certificate, which agents verify with the controller CA.
+		return nil
+	}
+	cert := srv.getExternalCertificate()
+	if cert == nil || cert.Leaf.VerifyHostname(serverName) != nil {
+		return nil
+	}
+	return cert
+}
+
 func serverError(err error) error {
 	return common.ServerError(err)
 }
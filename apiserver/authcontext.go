@@ -18,8 +18,10 @@ import (
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/authentication/ldap"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/bakerystorage"
 )
@@ -54,6 +56,13 @@ type authContext struct {
 	macaroonAuthOnce   sync.Once
 	_macaroonAuth      *authentication.ExternalMacaroonAuthenticator
 	_macaroonAuthError error
+
+	// oidcMutex guards the fields below it.
+	oidcMutex sync.Mutex
+	// oidcKeys caches the signing keys of the OpenID Connect
+	// provider at oidcIssuerURL.
+	oidcKeys      *authentication.RemoteOIDCKeySet
+	oidcIssuerURL string
 }
 
 // newAuthContext creates a new authentication context for st.
@@ -148,6 +157,20 @@ func (a authenticator) Authenticate(
 	tag names.Tag,
 	req params.LoginRequest,
 ) (state.Entity, error) {
+	if req.IDToken != "" {
+		auth, err := a.ctxt.oidcAuth()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return auth.Authenticate(entityFinder, tag, req)
+	}
+	if req.Token != "" {
+		auth, err := a.ctxt.tokenAuth()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return auth.Authenticate(entityFinder, tag, req)
+	}
 	auth, err := a.authenticatorForTag(tag)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -172,25 +195,46 @@ func (a authenticator) authenticatorForTag(tag names.Tag) (authentication.Entity
 	case names.UnitTagKind, names.MachineTagKind, names.ApplicationTagKind:
 		return &a.ctxt.agentAuth, nil
 	case names.UserTagKind:
-		return a.localUserAuth(), nil
+		if tag.(names.UserTag).Domain() == authentication.LDAPUserDomain {
+			auth, err := a.ctxt.ldapAuth()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if auth != nil {
+				return auth, nil
+			}
+		}
+		auth, err := a.localUserAuth()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return auth, nil
 	default:
 		return nil, errors.Annotatef(common.ErrBadRequest, "unexpected login entity tag")
 	}
 }
 
 // localUserAuth returns an authenticator that can authenticate logins for
-// local users with either passwords or macaroons.
-func (a authenticator) localUserAuth() *authentication.UserAuthenticator {
+// local users with either passwords or macaroons. The controller's
+// password policy and lockout threshold are read on each call.
+func (a authenticator) localUserAuth() (*authentication.UserAuthenticator, error) {
+	controllerCfg, err := a.ctxt.st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller config")
+	}
 	localUserIdentityLocation := url.URL{
 		Scheme: "https",
 		Host:   a.serverHost,
 		Path:   localUserIdentityLocationPath,
 	}
 	return &authentication.UserAuthenticator{
-		Service: a.ctxt.localUserBakeryService,
-		Clock:   a.ctxt.clock,
+		Service:                   a.ctxt.localUserBakeryService,
+		Clock:                     a.ctxt.clock,
 		LocalUserIdentityLocation: localUserIdentityLocation.String(),
-	}
+		PasswordPolicy:            controllerCfg.PasswordPolicy(),
+		LockoutThreshold:          controllerCfg.LoginLockoutThreshold(),
+		LockoutDuration:           controllerCfg.LoginLockoutDuration(),
+	}, nil
 }
 
 // externalMacaroonAuth returns an authenticator that can authenticate macaroon-based
@@ -207,6 +251,105 @@ func (ctxt *authContext) externalMacaroonAuth() (authentication.EntityAuthentica
 
 var errMacaroonAuthNotConfigured = errors.New("macaroon authentication is not configured")
 
+// oidcAuth returns an authenticator that can authenticate logins for
+// external users presenting OpenID Connect identity tokens. The
+// controller configuration is read on each call, so that changes to
+// the issuer take effect without restarting the API server.
+func (ctxt *authContext) oidcAuth() (authentication.EntityAuthenticator, error) {
+	controllerCfg, err := ctxt.st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller config")
+	}
+	issuerURL := controllerCfg.OIDCIssuerURL()
+	if issuerURL == "" {
+		return nil, errors.Trace(common.ErrNoCreds)
+	}
+	ctxt.oidcMutex.Lock()
+	if ctxt.oidcKeys == nil || ctxt.oidcIssuerURL != issuerURL {
+		ctxt.oidcKeys = authentication.NewRemoteOIDCKeySet(issuerURL, nil, ctxt.clock)
+		ctxt.oidcIssuerURL = issuerURL
+	}
+	keys := ctxt.oidcKeys
+	ctxt.oidcMutex.Unlock()
+
+	return &authentication.OIDCAuthenticator{
+		IssuerURL:     issuerURL,
+		ClientID:      controllerCfg.OIDCClientID(),
+		UsernameClaim: controllerCfg.OIDCUsernameClaim(),
+		GroupsClaim:   controllerCfg.OIDCGroupsClaim(),
+		Keys:          keys,
+		Clock:         ctxt.clock,
+		SyncGroups:    ctxt.st.SyncUserGroupMembership,
+	}, nil
+}
+
+// tokenAuth returns an authenticator that can authenticate logins
+// for users presenting an API token. The controller's lockout
+// settings are read on each call.
+func (ctxt *authContext) tokenAuth() (authentication.EntityAuthenticator, error) {
+	controllerCfg, err := ctxt.st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller config")
+	}
+	return &authentication.TokenAuthenticator{
+		Tokens:           apiTokenGetter{ctxt.st},
+		Clock:            ctxt.clock,
+		LockoutThreshold: controllerCfg.LoginLockoutThreshold(),
+		LockoutDuration:  controllerCfg.LoginLockoutDuration(),
+	}, nil
+}
+
+// apiTokenGetter implements authentication.APITokenGetter.
+type apiTokenGetter struct {
+	st *state.State
+}
+
+// APIToken implements authentication.APITokenGetter.
+func (g apiTokenGetter) APIToken(id string) (authentication.APIToken, error) {
+	token, err := g.st.APIToken(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return token, nil
+}
+
+// ldapAuth returns an authenticator that can authenticate password
+// logins for users in the LDAP domain, or nil if no LDAP directory is
+// configured. As with oidcAuth, the controller configuration is read
+// on each call.
+func (ctxt *authContext) ldapAuth() (authentication.EntityAuthenticator, error) {
+	controllerCfg, err := ctxt.st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller config")
+	}
+	if controllerCfg.LDAPURL() == "" {
+		return nil, nil
+	}
+	directory, err := ldap.NewDirectory(ldap.NewConfig(controllerCfg))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create LDAP directory")
+	}
+	auth := &authentication.LDAPAuthenticator{
+		Directory:  directory,
+		SyncGroups: ctxt.st.SyncUserGroupMembership,
+	}
+	if controllerCfg.LDAPAutoCreateUsers() {
+		auth.CreateUser = func(user names.UserTag) error {
+			owner, err := ctxt.st.ControllerOwner()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			_, err = ctxt.st.AddControllerUser(state.UserAccessSpec{
+				User:      user,
+				CreatedBy: owner,
+				Access:    permission.LoginAccess,
+			})
+			return errors.Trace(err)
+		}
+	}
+	return auth, nil
+}
+
 // newExternalMacaroonAuth returns an authenticator that can authenticate
 // macaroon-based logins for external users. This is just a helper function
 // for authCtxt.externalMacaroonAuth.
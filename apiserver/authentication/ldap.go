@@ -0,0 +1,89 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/authentication/ldap"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// LDAPUserDomain is the domain of users authenticated against the
+// controller's LDAP directory.
+const LDAPUserDomain = "ldap"
+
+// LDAPDirectory checks user credentials against an LDAP directory.
+type LDAPDirectory interface {
+	// Login verifies the given user's password, returning the names
+	// of the groups they are a member of. If the password is wrong,
+	// an error with the cause ldap.ErrInvalidCredentials is returned.
+	Login(username, password string) ([]string, error)
+}
+
+// LDAPAuthenticator performs password authentication for users in
+// LDAPUserDomain, checking their credentials against an LDAP
+// directory.
+type LDAPAuthenticator struct {
+	// Directory is the directory users are authenticated against.
+	Directory LDAPDirectory
+
+	// SyncGroups, if non-nil, is called after each successful login
+	// with the groups the directory reports for the user.
+	SyncGroups func(user names.UserTag, groups []string) error
+
+	// CreateUser, if non-nil, is called to add a user that has been
+	// authenticated by the directory but is not known to the
+	// controller. If it is nil, such users are refused.
+	CreateUser func(user names.UserTag) error
+}
+
+var _ EntityAuthenticator = (*LDAPAuthenticator)(nil)
+
+// Authenticate authenticates the user with the given tag using the
+// password in the login request.
+func (a *LDAPAuthenticator) Authenticate(
+	entityFinder EntityFinder, tag names.Tag, req params.LoginRequest,
+) (state.Entity, error) {
+	userTag, ok := tag.(names.UserTag)
+	if !ok || userTag.Domain() != LDAPUserDomain {
+		return nil, errors.Errorf("%q is not an LDAP user", tag)
+	}
+	if req.Credentials == "" {
+		return nil, errors.Trace(common.ErrNoCreds)
+	}
+	groups, err := a.Directory.Login(userTag.Name(), req.Credentials)
+	if errors.Cause(err) == ldap.ErrInvalidCredentials {
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "authenticating against LDAP directory")
+	}
+	logger.Debugf("LDAP user %s authenticated, groups %v", userTag.Id(), groups)
+
+	// Group membership is synced before looking up the user, as
+	// the groups may be what gives them access to the controller.
+	if a.SyncGroups != nil {
+		if err := a.SyncGroups(userTag, groups); err != nil {
+			return nil, errors.Annotate(err, "syncing LDAP groups")
+		}
+	}
+	entity, err := entityFinder.FindEntity(userTag)
+	if errors.IsNotFound(err) && a.CreateUser != nil {
+		logger.Infof("adding LDAP user %s to the controller", userTag.Id())
+		if err := a.CreateUser(userTag); err != nil {
+			return nil, errors.Annotatef(err, "adding LDAP user %q", userTag.Id())
+		}
+		entity, err = entityFinder.FindEntity(userTag)
+	}
+	if errors.IsNotFound(err) {
+		logger.Debugf("entity %s not found", userTag.String())
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return entity, nil
+}
@@ -0,0 +1,293 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package ldap authenticates users against an LDAP directory with a
+// simple bind, and looks up the groups they are a member of.
+package ldap
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	ldapv2 "gopkg.in/ldap.v2"
+
+	"github.com/juju/juju/controller"
+)
+
+const (
+	// DefaultMemberAttribute is the group attribute listing the
+	// distinguished names of the group's members.
+	DefaultMemberAttribute = "member"
+
+	// DefaultTimeout is the default timeout for directory operations.
+	DefaultTimeout = 10 * time.Second
+)
+
+// ErrInvalidCredentials is returned when the directory rejects a
+// user's password.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Config holds the configuration of a Directory.
+type Config struct {
+	// URL is the address of the directory server, with scheme
+	// "ldap" or "ldaps".
+	URL string
+
+	// UserDNTemplate is used to build the distinguished name of a
+	// user from their username, which replaces the "%s" it must
+	// contain. For example "uid=%s,ou=people,dc=example,dc=com".
+	UserDNTemplate string
+
+	// GroupBaseDN, if set, is the base of the subtree searched for
+	// the groups a user is a member of.
+	GroupBaseDN string
+
+	// MemberAttribute is the group attribute listing members. It
+	// defaults to DefaultMemberAttribute.
+	MemberAttribute string
+
+	// StartTLS, if true, upgrades connections to an "ldap" URL to
+	// TLS with the StartTLS operation before binding.
+	StartTLS bool
+
+	// CACert, if set, holds the PEM-encoded CA certificates trusted
+	// to sign the directory server's certificate. Otherwise the
+	// system's trusted roots are used.
+	CACert string
+
+	// BindDN and BindPassword are the credentials used to search
+	// for a user's groups outside of a login, by UserGroups. If
+	// BindDN is empty, the directory is searched anonymously.
+	BindDN       string
+	BindPassword string
+
+	// Timeout bounds the time taken by a single login. It defaults
+	// to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// NewConfig returns the directory configuration held in the given
+// controller configuration.
+func NewConfig(cfg controller.Config) Config {
+	return Config{
+		URL:            cfg.LDAPURL(),
+		UserDNTemplate: cfg.LDAPUserDNTemplate(),
+		GroupBaseDN:    cfg.LDAPGroupBaseDN(),
+		StartTLS:       cfg.LDAPStartTLS(),
+		CACert:         cfg.LDAPCACert(),
+		BindDN:         cfg.LDAPBindDN(),
+		BindPassword:   cfg.LDAPBindPassword(),
+	}
+}
+
+// Validate returns an error if the config is not valid.
+func (c Config) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return errors.NotValidf("LDAP URL %q", c.URL)
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return errors.NotValidf("LDAP URL scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.NotValidf("LDAP URL %q without host", c.URL)
+	}
+	if c.StartTLS && u.Scheme != "ldap" {
+		return errors.NotValidf("StartTLS with LDAP URL scheme %q", u.Scheme)
+	}
+	if strings.Count(c.UserDNTemplate, "%s") != 1 {
+		return errors.NotValidf("user DN template %q", c.UserDNTemplate)
+	}
+	if c.CACert != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(c.CACert)) {
+		return errors.NotValidf("LDAP CA certificate")
+	}
+	return nil
+}
+
+// conn holds the operations used on a connection to the directory.
+// It is implemented by *ldapv2.Conn.
+type conn interface {
+	Bind(username, password string) error
+	Search(*ldapv2.SearchRequest) (*ldapv2.SearchResult, error)
+	Close()
+}
+
+// Directory authenticates users against an LDAP directory.
+type Directory struct {
+	config    Config
+	tlsConfig *tls.Config
+	dial      func() (conn, error)
+}
+
+// NewDirectory returns a Directory using the given config.
+func NewDirectory(config Config) (*Directory, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.MemberAttribute == "" {
+		config.MemberAttribute = DefaultMemberAttribute
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	u, _ := url.Parse(config.URL)
+	tlsConfig := &tls.Config{ServerName: u.Hostname()}
+	if config.CACert != "" {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		tlsConfig.RootCAs.AppendCertsFromPEM([]byte(config.CACert))
+	}
+	d := &Directory{
+		config:    config,
+		tlsConfig: tlsConfig,
+	}
+	d.dial = d.connect
+	return d, nil
+}
+
+// Login verifies the password of the named user by binding to the
+// directory as them. If the directory has a group base configured,
+// the common names of the groups the user is a member of are returned.
+func (d *Directory) Login(username, password string) ([]string, error) {
+	// An empty password would make for an unauthenticated bind,
+	// which most servers accept for any name.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	c, err := d.dial()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer c.Close()
+
+	userDN := d.userDN(username)
+	if err := c.Bind(userDN, password); err != nil {
+		if ldapv2.IsErrorWithCode(err, ldapv2.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, errors.Annotate(err, "binding to directory")
+	}
+	return d.searchGroups(c, userDN)
+}
+
+// UserGroups returns the common names of the groups the named user is
+// a member of, binding to the directory with the configured bind
+// credentials. It is used to sync groups between logins.
+func (d *Directory) UserGroups(username string) ([]string, error) {
+	if d.config.GroupBaseDN == "" {
+		return nil, nil
+	}
+	c, err := d.dial()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer c.Close()
+
+	if d.config.BindDN != "" {
+		if err := c.Bind(d.config.BindDN, d.config.BindPassword); err != nil {
+			return nil, errors.Annotatef(err, "binding to directory as %q", d.config.BindDN)
+		}
+	}
+	return d.searchGroups(c, d.userDN(username))
+}
+
+func (d *Directory) userDN(username string) string {
+	return fmt.Sprintf(d.config.UserDNTemplate, EscapeDN(username))
+}
+
+// searchGroups returns the common names of the groups under the group
+// base whose member attribute lists memberDN.
+func (d *Directory) searchGroups(c conn, memberDN string) ([]string, error) {
+	if d.config.GroupBaseDN == "" {
+		return nil, nil
+	}
+	filter := fmt.Sprintf("(%s=%s)",
+		ldapv2.EscapeFilter(d.config.MemberAttribute),
+		ldapv2.EscapeFilter(memberDN),
+	)
+	result, err := c.Search(ldapv2.NewSearchRequest(
+		d.config.GroupBaseDN,
+		ldapv2.ScopeWholeSubtree,
+		ldapv2.NeverDerefAliases,
+		0, // no size limit
+		int(d.config.Timeout/time.Second),
+		false,
+		filter,
+		[]string{"cn"},
+		nil,
+	))
+	if err != nil {
+		return nil, errors.Annotate(err, "searching for groups")
+	}
+	var groups []string
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.GetAttributeValues("cn")...)
+	}
+	return groups, nil
+}
+
+// connect connects to the directory server, over TLS if the URL has
+// the "ldaps" scheme or StartTLS is configured.
+func (d *Directory) connect() (conn, error) {
+	u, _ := url.Parse(d.config.URL)
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "ldaps" {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+	}
+	netConn, err := net.DialTimeout("tcp", host, d.config.Timeout)
+	if err != nil {
+		return nil, errors.Annotate(err, "connecting to directory")
+	}
+	isTLS := u.Scheme == "ldaps"
+	if isTLS {
+		tlsConn := tls.Client(netConn, d.tlsConfig)
+		tlsConn.SetDeadline(time.Now().Add(d.config.Timeout))
+		if err := tlsConn.Handshake(); err != nil {
+			netConn.Close()
+			return nil, errors.Annotate(err, "connecting to directory")
+		}
+		tlsConn.SetDeadline(time.Time{})
+		netConn = tlsConn
+	}
+	c := ldapv2.NewConn(netConn, isTLS)
+	c.SetTimeout(d.config.Timeout)
+	c.Start()
+	if d.config.StartTLS {
+		if err := c.StartTLS(d.tlsConfig); err != nil {
+			c.Close()
+			return nil, errors.Annotate(err, "starting TLS")
+		}
+	}
+	return c, nil
+}
+
+// EscapeDN escapes s for use as an attribute value in a
+// distinguished name, as described in RFC 4514.
+func EscapeDN(s string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case strings.IndexByte(`,+"\<>;=`, ch) >= 0,
+			ch == '#' && i == 0,
+			ch == ' ' && (i == 0 || i == len(s)-1):
+			buf.WriteByte('\\')
+			buf.WriteByte(ch)
+		case ch < 0x20 || ch == 0x7f:
+			fmt.Fprintf(&buf, `\%02x`, ch)
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	return buf.String()
+}
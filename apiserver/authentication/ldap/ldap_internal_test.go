@@ -0,0 +1,205 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ldap
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	ldapv2 "gopkg.in/ldap.v2"
+
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ldapSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ldapSuite{})
+
+func (s *ldapSuite) TestEscapeDN(c *gc.C) {
+	c.Assert(EscapeDN("bob"), gc.Equals, "bob")
+	c.Assert(EscapeDN("bob,ou=admins"), gc.Equals, `bob\,ou\=admins`)
+	c.Assert(EscapeDN(" #bob "), gc.Equals, `\ #bob\ `)
+	c.Assert(EscapeDN("#bob"), gc.Equals, `\#bob`)
+	c.Assert(EscapeDN("a\x00b"), gc.Equals, `a\00b`)
+}
+
+func (s *ldapSuite) TestConfigValidate(c *gc.C) {
+	for _, test := range []struct {
+		config Config
+		err    string
+	}{{
+		config: Config{URL: "ldap://ldap.example.com", UserDNTemplate: "uid=%s,dc=example"},
+	}, {
+		config: Config{URL: "ldaps://ldap.example.com:1636", UserDNTemplate: "uid=%s,dc=example"},
+	}, {
+		config: Config{URL: "ldap://ldap.example.com", UserDNTemplate: "uid=%s,dc=example", StartTLS: true},
+	}, {
+		config: Config{URL: "ldap://ldap.example.com", UserDNTemplate: "uid=%s,dc=example", CACert: coretesting.CACert},
+	}, {
+		config: Config{URL: "http://ldap.example.com", UserDNTemplate: "uid=%s,dc=example"},
+		err:    `LDAP URL scheme "http" not valid`,
+	}, {
+		config: Config{URL: "ldap://", UserDNTemplate: "uid=%s,dc=example"},
+		err:    `LDAP URL "ldap://" without host not valid`,
+	}, {
+		config: Config{URL: "ldap://ldap.example.com", UserDNTemplate: "uid=bob,dc=example"},
+		err:    `user DN template "uid=bob,dc=example" not valid`,
+	}, {
+		config: Config{URL: "ldaps://ldap.example.com", UserDNTemplate: "uid=%s,dc=example", StartTLS: true},
+		err:    `StartTLS with LDAP URL scheme "ldaps" not valid`,
+	}, {
+		config: Config{URL: "ldap://ldap.example.com", UserDNTemplate: "uid=%s,dc=example", CACert: "junk"},
+		err:    `LDAP CA certificate not valid`,
+	}} {
+		err := test.config.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *ldapSuite) TestTLSConfig(c *gc.C) {
+	d, err := NewDirectory(Config{
+		URL:            "ldaps://ldap.example.com:1636",
+		UserDNTemplate: "uid=%s,dc=example",
+		CACert:         coretesting.CACert,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(d.tlsConfig.ServerName, gc.Equals, "ldap.example.com")
+	c.Assert(d.tlsConfig.RootCAs, gc.NotNil)
+	c.Assert(d.tlsConfig.InsecureSkipVerify, jc.IsFalse)
+}
+
+// fakeConn is an in-memory directory connection.
+type fakeConn struct {
+	users  map[string]string
+	groups map[string][]string
+	binds  []string
+	filter []string
+	closed bool
+}
+
+func (f *fakeConn) Bind(dn, password string) error {
+	f.binds = append(f.binds, dn)
+	if want, ok := f.users[dn]; ok && want == password {
+		return nil
+	}
+	return ldapv2.NewError(ldapv2.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (f *fakeConn) Search(req *ldapv2.SearchRequest) (*ldapv2.SearchResult, error) {
+	f.filter = append(f.filter, req.Filter)
+	var result ldapv2.SearchResult
+	for cn, members := range f.groups {
+		for _, member := range members {
+			if req.Filter == "(member="+ldapv2.EscapeFilter(member)+")" {
+				result.Entries = append(result.Entries, ldapv2.NewEntry(
+					"cn="+cn+",ou=groups", map[string][]string{"cn": {cn}},
+				))
+			}
+		}
+	}
+	return &result, nil
+}
+
+func (f *fakeConn) Close() {
+	f.closed = true
+}
+
+func (s *ldapSuite) newDirectory(c *gc.C, fake *fakeConn, config Config) *Directory {
+	config.URL = "ldap://ldap.example.com"
+	config.UserDNTemplate = "uid=%s,ou=people,dc=example,dc=com"
+	d, err := NewDirectory(config)
+	c.Assert(err, jc.ErrorIsNil)
+	d.dial = func() (conn, error) {
+		return fake, nil
+	}
+	return d
+}
+
+func (s *ldapSuite) TestLogin(c *gc.C) {
+	fake := &fakeConn{
+		users: map[string]string{"uid=bob,ou=people,dc=example,dc=com": "secret"},
+		groups: map[string][]string{
+			"dev": {"uid=bob,ou=people,dc=example,dc=com"},
+			"ops": {"uid=mary,ou=people,dc=example,dc=com"},
+		},
+	}
+	d := s.newDirectory(c, fake, Config{GroupBaseDN: "ou=groups,dc=example,dc=com"})
+	groups, err := d.Login("bob", "secret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"dev"})
+	c.Assert(fake.binds, jc.DeepEquals, []string{"uid=bob,ou=people,dc=example,dc=com"})
+	c.Assert(fake.filter, jc.DeepEquals, []string{"(member=uid=bob,ou=people,dc=example,dc=com)"})
+	c.Assert(fake.closed, jc.IsTrue)
+}
+
+func (s *ldapSuite) TestLoginNoGroupBase(c *gc.C) {
+	fake := &fakeConn{
+		users: map[string]string{"uid=bob,ou=people,dc=example,dc=com": "secret"},
+	}
+	d := s.newDirectory(c, fake, Config{})
+	groups, err := d.Login("bob", "secret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+	c.Assert(fake.filter, gc.HasLen, 0)
+}
+
+func (s *ldapSuite) TestLoginInvalidCredentials(c *gc.C) {
+	fake := &fakeConn{
+		users: map[string]string{"uid=bob,ou=people,dc=example,dc=com": "secret"},
+	}
+	d := s.newDirectory(c, fake, Config{})
+	_, err := d.Login("bob", "wrong")
+	c.Assert(errors.Cause(err), gc.Equals, ErrInvalidCredentials)
+}
+
+func (s *ldapSuite) TestLoginEmptyPassword(c *gc.C) {
+	fake := &fakeConn{}
+	d := s.newDirectory(c, fake, Config{})
+	_, err := d.Login("bob", "")
+	c.Assert(errors.Cause(err), gc.Equals, ErrInvalidCredentials)
+	c.Assert(fake.binds, gc.HasLen, 0)
+}
+
+func (s *ldapSuite) TestLoginEscapesUsername(c *gc.C) {
+	fake := &fakeConn{}
+	d := s.newDirectory(c, fake, Config{})
+	_, err := d.Login("bob,ou=admins", "secret")
+	c.Assert(errors.Cause(err), gc.Equals, ErrInvalidCredentials)
+	c.Assert(fake.binds, jc.DeepEquals, []string{`uid=bob\,ou\=admins,ou=people,dc=example,dc=com`})
+}
+
+func (s *ldapSuite) TestUserGroups(c *gc.C) {
+	fake := &fakeConn{
+		users: map[string]string{"cn=juju,dc=example,dc=com": "sync-secret"},
+		groups: map[string][]string{
+			"dev": {"uid=bob,ou=people,dc=example,dc=com"},
+		},
+	}
+	d := s.newDirectory(c, fake, Config{
+		GroupBaseDN:  "ou=groups,dc=example,dc=com",
+		BindDN:       "cn=juju,dc=example,dc=com",
+		BindPassword: "sync-secret",
+	})
+	groups, err := d.UserGroups("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"dev"})
+	c.Assert(fake.binds, jc.DeepEquals, []string{"cn=juju,dc=example,dc=com"})
+}
+
+func (s *ldapSuite) TestUserGroupsAnonymous(c *gc.C) {
+	fake := &fakeConn{}
+	d := s.newDirectory(c, fake, Config{GroupBaseDN: "ou=groups,dc=example,dc=com"})
+	groups, err := d.UserGroups("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+	c.Assert(fake.binds, gc.HasLen, 0)
+	c.Assert(fake.filter, gc.HasLen, 1)
+}
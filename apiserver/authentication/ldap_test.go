@@ -0,0 +1,139 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/authentication/ldap"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type ldapSuite struct {
+	testing.IsolationSuite
+	directory *fakeDirectory
+	synced    map[string][]string
+	created   []names.UserTag
+	auth      *authentication.LDAPAuthenticator
+}
+
+var _ = gc.Suite(&ldapSuite{})
+
+type fakeDirectory struct {
+	passwords map[string]string
+	groups    map[string][]string
+	err       error
+}
+
+func (d *fakeDirectory) Login(username, password string) ([]string, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if want, ok := d.passwords[username]; !ok || want != password {
+		return nil, errors.Trace(ldap.ErrInvalidCredentials)
+	}
+	return d.groups[username], nil
+}
+
+func (s *ldapSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.directory = &fakeDirectory{
+		passwords: map[string]string{"bob": "secret"},
+		groups:    map[string][]string{"bob": {"dev", "ops"}},
+	}
+	s.synced = make(map[string][]string)
+	s.created = nil
+	s.auth = &authentication.LDAPAuthenticator{
+		Directory: s.directory,
+		SyncGroups: func(user names.UserTag, groups []string) error {
+			s.synced[user.Id()] = groups
+			return nil
+		},
+	}
+}
+
+func (s *ldapSuite) TestAuthenticate(c *gc.C) {
+	bob := names.NewUserTag("bob@ldap")
+	entity, err := s.auth.Authenticate(&ldapEntityFinder{known: []names.Tag{bob}}, bob, params.LoginRequest{
+		Credentials: "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, bob)
+	c.Assert(s.synced, jc.DeepEquals, map[string][]string{"bob@ldap": {"dev", "ops"}})
+}
+
+func (s *ldapSuite) TestAuthenticateBadPassword(c *gc.C) {
+	bob := names.NewUserTag("bob@ldap")
+	_, err := s.auth.Authenticate(&ldapEntityFinder{known: []names.Tag{bob}}, bob, params.LoginRequest{
+		Credentials: "wrong",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	c.Assert(s.synced, gc.HasLen, 0)
+}
+
+func (s *ldapSuite) TestAuthenticateNoPassword(c *gc.C) {
+	bob := names.NewUserTag("bob@ldap")
+	_, err := s.auth.Authenticate(&ldapEntityFinder{known: []names.Tag{bob}}, bob, params.LoginRequest{})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrNoCreds)
+}
+
+func (s *ldapSuite) TestAuthenticateDirectoryError(c *gc.C) {
+	s.directory.err = errors.New("connection refused")
+	bob := names.NewUserTag("bob@ldap")
+	_, err := s.auth.Authenticate(&ldapEntityFinder{}, bob, params.LoginRequest{
+		Credentials: "secret",
+	})
+	c.Assert(err, gc.ErrorMatches, "authenticating against LDAP directory: connection refused")
+}
+
+func (s *ldapSuite) TestAuthenticateWrongDomain(c *gc.C) {
+	_, err := s.auth.Authenticate(&ldapEntityFinder{}, names.NewUserTag("bob"), params.LoginRequest{
+		Credentials: "secret",
+	})
+	c.Assert(err, gc.ErrorMatches, `"user-bob" is not an LDAP user`)
+}
+
+func (s *ldapSuite) TestAuthenticateUnknownUser(c *gc.C) {
+	bob := names.NewUserTag("bob@ldap")
+	_, err := s.auth.Authenticate(&ldapEntityFinder{}, bob, params.LoginRequest{
+		Credentials: "secret",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *ldapSuite) TestAuthenticateCreatesUser(c *gc.C) {
+	finder := &ldapEntityFinder{}
+	s.auth.CreateUser = func(user names.UserTag) error {
+		s.created = append(s.created, user)
+		finder.known = append(finder.known, user)
+		return nil
+	}
+	bob := names.NewUserTag("bob@ldap")
+	entity, err := s.auth.Authenticate(finder, bob, params.LoginRequest{
+		Credentials: "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, bob)
+	c.Assert(s.created, jc.DeepEquals, []names.UserTag{bob})
+}
+
+type ldapEntityFinder struct {
+	known []names.Tag
+}
+
+func (f *ldapEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
+	for _, t := range f.known {
+		if t == tag {
+			return &oidcEntity{tag: tag}, nil
+		}
+	}
+	return nil, errors.NotFoundf("user %q", tag.Id())
+}
@@ -0,0 +1,383 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+const (
+	// OIDCUserDomain is the domain given to users authenticated with
+	// an OpenID Connect identity token whose username claim does not
+	// already carry a domain.
+	OIDCUserDomain = "oidc"
+
+	// oidcClockSkew is the leeway allowed when checking the validity
+	// period of identity tokens.
+	oidcClockSkew = time.Minute
+
+	// oidcHTTPTimeout bounds each request made to the OpenID Connect
+	// provider, so that an unresponsive provider cannot hold up logins
+	// indefinitely.
+	oidcHTTPTimeout = 30 * time.Second
+
+	// oidcMinRefreshInterval is the minimum time between fetches of
+	// the provider's keys. Tokens signed by an unknown key cannot
+	// make the controller fetch the keys more often than this.
+	oidcMinRefreshInterval = time.Minute
+)
+
+// OIDCKeySet provides the keys used to verify the signatures of
+// identity tokens.
+type OIDCKeySet interface {
+	// PublicKey returns the RSA public key with the given key ID.
+	PublicKey(keyID string) (*rsa.PublicKey, error)
+}
+
+// OIDCIdentity holds the identity asserted by a verified identity token.
+type OIDCIdentity struct {
+	// User is the tag of the user the token was issued to.
+	User names.UserTag
+
+	// Groups holds the names of the groups the user is a member
+	// of, according to the issuer.
+	Groups []string
+}
+
+// OIDCAuthenticator performs authentication for external users using
+// OpenID Connect identity tokens.
+type OIDCAuthenticator struct {
+	// IssuerURL is the URL of the trusted OpenID Connect provider.
+	IssuerURL string
+
+	// ClientID is the audience identity tokens must be issued for.
+	ClientID string
+
+	// UsernameClaim is the name of the claim holding the user's name.
+	UsernameClaim string
+
+	// GroupsClaim is the name of the claim holding the user's groups.
+	GroupsClaim string
+
+	// Keys provides the issuer's signing keys.
+	Keys OIDCKeySet
+
+	// Clock is used to check the validity period of tokens.
+	Clock clock.Clock
+
+	// SyncGroups, if non-nil, is called after each successful login
+	// with the groups named in the token's groups claim.
+	SyncGroups func(user names.UserTag, groups []string) error
+}
+
+var _ EntityAuthenticator = (*OIDCAuthenticator)(nil)
+
+// Authenticate authenticates the user holding the identity token in
+// the login request. The tag is ignored; the user is identified
+// by the token.
+func (a *OIDCAuthenticator) Authenticate(
+	entityFinder EntityFinder, _ names.Tag, req params.LoginRequest,
+) (state.Entity, error) {
+	identity, err := a.Verify(req.IDToken)
+	if err != nil {
+		logger.Debugf("OIDC authentication failed: %v", err)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	logger.Debugf("OIDC user %s authenticated, groups %v", identity.User.Id(), identity.Groups)
+
+	// As for LDAP users, group membership is synced before looking
+	// up the user, as the groups may be what gives them access.
+	if a.SyncGroups != nil {
+		if err := a.SyncGroups(identity.User, identity.Groups); err != nil {
+			return nil, errors.Annotate(err, "syncing OIDC groups")
+		}
+	}
+	entity, err := entityFinder.FindEntity(identity.User)
+	if errors.IsNotFound(err) {
+		logger.Debugf("entity %s not found", identity.User.String())
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return entity, nil
+}
+
+type oidcHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// Verify checks the signature and claims of the given identity token,
+// and returns the identity it asserts.
+func (a *OIDCAuthenticator) Verify(token string) (*OIDCIdentity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.NotValidf("identity token")
+	}
+	var header oidcHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, errors.Annotate(err, "decoding token header")
+	}
+	if header.Algorithm != "RS256" {
+		return nil, errors.NotSupportedf("token signing algorithm %q", header.Algorithm)
+	}
+	key, err := a.Keys.PublicKey(header.KeyID)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting signing key")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.NotValidf("token signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("token signature verification failed")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, errors.Annotate(err, "decoding token claims")
+	}
+	if err := a.checkClaims(claims); err != nil {
+		return nil, errors.Trace(err)
+	}
+	user, err := a.userTag(claims)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &OIDCIdentity{
+		User:   user,
+		Groups: stringsClaim(claims[a.GroupsClaim]),
+	}, nil
+}
+
+func (a *OIDCAuthenticator) checkClaims(claims map[string]interface{}) error {
+	if iss, _ := claims["iss"].(string); iss != a.IssuerURL {
+		return errors.Errorf("token issued by %q, expected %q", iss, a.IssuerURL)
+	}
+	audienceOK := false
+	for _, aud := range stringsClaim(claims["aud"]) {
+		if aud == a.ClientID {
+			audienceOK = true
+			break
+		}
+	}
+	if !audienceOK {
+		return errors.Errorf("token not issued for %q", a.ClientID)
+	}
+	now := a.Clock.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("token has no expiry time")
+	}
+	if now.Add(-oidcClockSkew).After(time.Unix(int64(exp), 0)) {
+		return errors.New("token has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(oidcClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token not yet valid")
+	}
+	return nil
+}
+
+// userTag returns the tag of the user named by the username claim.
+// Users are always placed in OIDCUserDomain; names that carry a
+// domain of their own are rejected, so that identity tokens can never
+// be used to log in as a local user or as a user of another identity
+// provider.
+func (a *OIDCAuthenticator) userTag(claims map[string]interface{}) (names.UserTag, error) {
+	username, _ := claims[a.UsernameClaim].(string)
+	if username == "" {
+		return names.UserTag{}, errors.Errorf("token has no %q claim", a.UsernameClaim)
+	}
+	if strings.Contains(username, "@") {
+		return names.UserTag{}, errors.NotValidf("username %q with domain", username)
+	}
+	username += "@" + OIDCUserDomain
+	if !names.IsValidUser(username) {
+		return names.UserTag{}, errors.NotValidf("username %q", username)
+	}
+	return names.NewUserTag(username), nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(data, v))
+}
+
+// stringsClaim returns the value of a claim that may be either a
+// single string or a list of strings.
+func stringsClaim(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// RemoteOIDCKeySet is an OIDCKeySet fetching keys from the JSON Web Key
+// Set published by an OpenID Connect provider. Keys are cached, and the
+// key set is fetched again when a token is signed by an unknown key,
+// at most once every oidcMinRefreshInterval.
+type RemoteOIDCKeySet struct {
+	issuerURL string
+	client    *http.Client
+	clock     clock.Clock
+
+	// fetchMu serialises fetches of the key set, and guards
+	// lastFetch. The key set is fetched without holding mu, so that
+	// lookups of cached keys are not held up by a slow provider.
+	fetchMu   sync.Mutex
+	lastFetch time.Time
+
+	// mu guards keys.
+	mu   sync.Mutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewRemoteOIDCKeySet returns a key set for the provider at the given
+// issuer URL. If client is nil, a client that times out requests after
+// oidcHTTPTimeout is used.
+func NewRemoteOIDCKeySet(issuerURL string, client *http.Client, clock clock.Clock) *RemoteOIDCKeySet {
+	if client == nil {
+		client = &http.Client{Timeout: oidcHTTPTimeout}
+	}
+	return &RemoteOIDCKeySet{
+		issuerURL: strings.TrimSuffix(issuerURL, "/"),
+		client:    client,
+		clock:     clock,
+	}
+}
+
+// PublicKey implements OIDCKeySet.
+func (s *RemoteOIDCKeySet) PublicKey(keyID string) (*rsa.PublicKey, error) {
+	if key, ok := s.cached(keyID); ok {
+		return key, nil
+	}
+
+	// Only one caller fetches the key set at a time; the others
+	// wait, and then find the key in the cache.
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	if key, ok := s.cached(keyID); ok {
+		return key, nil
+	}
+	now := s.clock.Now()
+	if !s.lastFetch.IsZero() && now.Sub(s.lastFetch) < oidcMinRefreshInterval {
+		return nil, errors.NotFoundf("signing key %q", keyID)
+	}
+	s.lastFetch = now
+	keys, err := s.fetch()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	if key, ok := s.cached(keyID); ok {
+		return key, nil
+	}
+	return nil, errors.NotFoundf("signing key %q", keyID)
+}
+
+func (s *RemoteOIDCKeySet) cached(keyID string) (*rsa.PublicKey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lookup(keyID)
+}
+
+// lookup returns the key with the given ID. If the ID is empty and
+// the key set holds a single key, that key is returned. It must be
+// called with mu held.
+func (s *RemoteOIDCKeySet) lookup(keyID string) (*rsa.PublicKey, bool) {
+	if keyID == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[keyID]
+	return key, ok
+}
+
+func (s *RemoteOIDCKeySet) fetch() (map[string]*rsa.PublicKey, error) {
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := s.getJSON(s.issuerURL+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, errors.Annotate(err, "fetching provider configuration")
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("provider configuration has no jwks_uri")
+	}
+	var jwks struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			Use     string `json:"use"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := s.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return nil, errors.Annotate(err, "fetching provider keys")
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.KeyType != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, errors.NotValidf("modulus of key %q", k.KeyID)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, errors.NotValidf("exponent of key %q", k.KeyID)
+		}
+		keys[k.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (s *RemoteOIDCKeySet) getJSON(url string, v interface{}) error {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return errors.Trace(json.NewDecoder(resp.Body).Decode(v))
+}
@@ -0,0 +1,308 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+const oidcIssuer = "https://login.example.com"
+
+type oidcSuite struct {
+	testing.IsolationSuite
+	key   *rsa.PrivateKey
+	clock *testing.Clock
+	auth  *authentication.OIDCAuthenticator
+}
+
+var _ = gc.Suite(&oidcSuite{})
+
+type staticKeySet map[string]*rsa.PublicKey
+
+func (s staticKeySet) PublicKey(keyID string) (*rsa.PublicKey, error) {
+	key, ok := s[keyID]
+	if !ok {
+		return nil, errors.NotFoundf("signing key %q", keyID)
+	}
+	return key, nil
+}
+
+func (s *oidcSuite) SetUpSuite(c *gc.C) {
+	s.IsolationSuite.SetUpSuite(c)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.key = key
+}
+
+func (s *oidcSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC))
+	s.auth = &authentication.OIDCAuthenticator{
+		IssuerURL:     oidcIssuer,
+		ClientID:      "juju",
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+		Keys:          staticKeySet{"key-1": &s.key.PublicKey},
+		Clock:         s.clock,
+	}
+}
+
+func (s *oidcSuite) claims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                oidcIssuer,
+		"aud":                "juju",
+		"sub":                "0123456789",
+		"preferred_username": "bob",
+		"groups":             []string{"dev", "ops"},
+		"iat":                s.clock.Now().Unix(),
+		"exp":                s.clock.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func signToken(c *gc.C, key *rsa.PrivateKey, header, claims map[string]interface{}) string {
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		c.Assert(err, jc.ErrorIsNil)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	c.Assert(err, jc.ErrorIsNil)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (s *oidcSuite) token(c *gc.C, claims map[string]interface{}) string {
+	return signToken(c, s.key, map[string]interface{}{"alg": "RS256", "kid": "key-1"}, claims)
+}
+
+func (s *oidcSuite) TestVerify(c *gc.C) {
+	identity, err := s.auth.Verify(s.token(c, s.claims()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity, jc.DeepEquals, &authentication.OIDCIdentity{
+		User:   names.NewUserTag("bob@oidc"),
+		Groups: []string{"dev", "ops"},
+	})
+}
+
+func (s *oidcSuite) TestVerifyUsernameClaim(c *gc.C) {
+	s.auth.UsernameClaim = "sub"
+	identity, err := s.auth.Verify(s.token(c, s.claims()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(identity.User, gc.Equals, names.NewUserTag("0123456789@oidc"))
+}
+
+func (s *oidcSuite) TestVerifyAudienceList(c *gc.C) {
+	claims := s.claims()
+	claims["aud"] = []string{"other", "juju"}
+	_, err := s.auth.Verify(s.token(c, claims))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *oidcSuite) TestVerifyInvalidClaims(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		modify func(map[string]interface{})
+		err    string
+	}{{
+		about:  "wrong issuer",
+		modify: func(claims map[string]interface{}) { claims["iss"] = "https://evil.example.com" },
+		err:    `token issued by "https://evil.example.com", expected "https://login.example.com"`,
+	}, {
+		about:  "wrong audience",
+		modify: func(claims map[string]interface{}) { claims["aud"] = "other" },
+		err:    `token not issued for "juju"`,
+	}, {
+		about:  "no expiry",
+		modify: func(claims map[string]interface{}) { delete(claims, "exp") },
+		err:    `token has no expiry time`,
+	}, {
+		about: "expired",
+		modify: func(claims map[string]interface{}) {
+			claims["exp"] = s.clock.Now().Add(-2 * time.Minute).Unix()
+		},
+		err: `token has expired`,
+	}, {
+		about: "not yet valid",
+		modify: func(claims map[string]interface{}) {
+			claims["nbf"] = s.clock.Now().Add(2 * time.Minute).Unix()
+		},
+		err: `token not yet valid`,
+	}, {
+		about:  "missing username",
+		modify: func(claims map[string]interface{}) { delete(claims, "preferred_username") },
+		err:    `token has no "preferred_username" claim`,
+	}, {
+		about:  "local user",
+		modify: func(claims map[string]interface{}) { claims["preferred_username"] = "admin@local" },
+		err:    `username "admin@local" with domain not valid`,
+	}, {
+		about:  "user in another domain",
+		modify: func(claims map[string]interface{}) { claims["preferred_username"] = "bob@ldap" },
+		err:    `username "bob@ldap" with domain not valid`,
+	}, {
+		about:  "invalid username",
+		modify: func(claims map[string]interface{}) { claims["preferred_username"] = "bob smith" },
+		err:    `username "bob smith@oidc" not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		claims := s.claims()
+		test.modify(claims)
+		_, err := s.auth.Verify(s.token(c, claims))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *oidcSuite) TestVerifyBadSignature(c *gc.C) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	token := signToken(c, otherKey, map[string]interface{}{"alg": "RS256", "kid": "key-1"}, s.claims())
+	_, err = s.auth.Verify(token)
+	c.Assert(err, gc.ErrorMatches, "token signature verification failed")
+}
+
+func (s *oidcSuite) TestVerifyUnknownKey(c *gc.C) {
+	token := signToken(c, s.key, map[string]interface{}{"alg": "RS256", "kid": "key-2"}, s.claims())
+	_, err := s.auth.Verify(token)
+	c.Assert(err, gc.ErrorMatches, `getting signing key: signing key "key-2" not found`)
+}
+
+func (s *oidcSuite) TestVerifyUnsupportedAlgorithm(c *gc.C) {
+	token := signToken(c, s.key, map[string]interface{}{"alg": "none"}, s.claims())
+	_, err := s.auth.Verify(token)
+	c.Assert(err, gc.ErrorMatches, `token signing algorithm "none" not supported`)
+}
+
+func (s *oidcSuite) TestVerifyMalformed(c *gc.C) {
+	_, err := s.auth.Verify("not-a-token")
+	c.Assert(err, gc.ErrorMatches, "identity token not valid")
+}
+
+func (s *oidcSuite) TestAuthenticate(c *gc.C) {
+	finder := &oidcEntityFinder{}
+	entity, err := s.auth.Authenticate(finder, nil, params.LoginRequest{
+		IDToken: s.token(c, s.claims()),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, names.NewUserTag("bob@oidc"))
+}
+
+func (s *oidcSuite) TestAuthenticateSyncsGroups(c *gc.C) {
+	var synced []string
+	s.auth.SyncGroups = func(user names.UserTag, groups []string) error {
+		c.Check(user, gc.Equals, names.NewUserTag("bob@oidc"))
+		synced = groups
+		return nil
+	}
+	_, err := s.auth.Authenticate(&oidcEntityFinder{}, nil, params.LoginRequest{
+		IDToken: s.token(c, s.claims()),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(synced, jc.DeepEquals, []string{"dev", "ops"})
+}
+
+func (s *oidcSuite) TestAuthenticateBadToken(c *gc.C) {
+	claims := s.claims()
+	claims["aud"] = "other"
+	_, err := s.auth.Authenticate(&oidcEntityFinder{}, nil, params.LoginRequest{
+		IDToken: s.token(c, claims),
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *oidcSuite) TestAuthenticateUnknownUser(c *gc.C) {
+	finder := &oidcEntityFinder{err: errors.NotFoundf("user")}
+	_, err := s.auth.Authenticate(finder, nil, params.LoginRequest{
+		IDToken: s.token(c, s.claims()),
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *oidcSuite) TestRemoteKeySet(c *gc.C) {
+	var fetches int
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		fmt.Fprintf(w, `{"issuer": %q, "jwks_uri": %q}`, server.URL, server.URL+"/keys")
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(s.key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+
+	keys := authentication.NewRemoteOIDCKeySet(server.URL+"/", nil, s.clock)
+	key, err := keys.PublicKey("key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, jc.DeepEquals, &s.key.PublicKey)
+
+	// Known keys are cached.
+	_, err = keys.PublicKey("key-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fetches, gc.Equals, 1)
+
+	// Unknown keys cause the key set to be fetched again, but no more
+	// than once a minute.
+	s.clock.Advance(time.Minute)
+	_, err = keys.PublicKey("key-2")
+	c.Assert(err, gc.ErrorMatches, `signing key "key-2" not found`)
+	c.Assert(fetches, gc.Equals, 2)
+	_, err = keys.PublicKey("key-3")
+	c.Assert(err, gc.ErrorMatches, `signing key "key-3" not found`)
+	c.Assert(fetches, gc.Equals, 2)
+
+	s.clock.Advance(time.Minute)
+	_, err = keys.PublicKey("key-3")
+	c.Assert(err, gc.ErrorMatches, `signing key "key-3" not found`)
+	c.Assert(fetches, gc.Equals, 3)
+}
+
+type oidcEntityFinder struct {
+	err error
+}
+
+func (f *oidcEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &oidcEntity{tag: tag}, nil
+}
+
+type oidcEntity struct {
+	state.Entity
+	tag names.Tag
+}
+
+func (e *oidcEntity) Tag() names.Tag {
+	return e.tag
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// APIToken is the part of a stored API token needed to authenticate
+// a login with it.
+type APIToken interface {
+	// Owner returns the tag of the user the token acts as.
+	Owner() names.UserTag
+
+	// Expires returns the time after which the token is not valid.
+	Expires() time.Time
+
+	// SecretValid reports whether the given secret matches the token's.
+	SecretValid(secret string) bool
+}
+
+// APITokenGetter looks up API tokens by ID.
+type APITokenGetter interface {
+	APIToken(id string) (APIToken, error)
+}
+
+// TokenAuthenticator performs authentication for users logging in
+// with an API token they have created.
+type TokenAuthenticator struct {
+	// Tokens is used to look up the token presented at login.
+	Tokens APITokenGetter
+
+	// Clock is used to check whether tokens have expired.
+	Clock clock.Clock
+
+	// LockoutThreshold is the number of consecutive failed logins
+	// after which a local user is locked out, or zero if users are
+	// never locked out. Invalid secrets presented with a token count
+	// as failed logins of its owner.
+	LockoutThreshold int
+
+	// LockoutDuration is how long a local user is locked out for.
+	LockoutDuration time.Duration
+}
+
+var _ EntityAuthenticator = (*TokenAuthenticator)(nil)
+
+// Authenticate authenticates the owner of the API token in the login
+// request. If a tag is given, it must be that of the token's owner.
+// As with password logins, the owner must not be disabled, deleted or
+// locked out, and an invalid secret counts as a failed login. Tokens
+// are intended for unattended clients, so no multi-factor
+// authentication code is required; creating a token requires a login
+// that presented one.
+func (a *TokenAuthenticator) Authenticate(
+	entityFinder EntityFinder, tag names.Tag, req params.LoginRequest,
+) (state.Entity, error) {
+	id, secret, err := state.ParseAPIToken(req.Token)
+	if err != nil {
+		logger.Debugf("API token authentication failed: %v", err)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	token, err := a.Tokens.APIToken(id)
+	if errors.IsNotFound(err) {
+		logger.Debugf("API token %q not found", id)
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	owner := token.Owner()
+	if tag != nil && tag != owner {
+		logger.Debugf("API token owned by %s presented for %s", owner.Id(), tag)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	entity, err := entityFinder.FindEntity(owner)
+	if _, ok := errors.Cause(err).(state.DeletedUserError); ok || errors.IsNotFound(err) {
+		logger.Debugf("entity %s not found", owner.String())
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	user, ok := entity.(passwordUser)
+	if !ok {
+		return nil, errors.Trace(common.ErrBadRequest)
+	}
+	if err := checkUserActive(user); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !token.SecretValid(secret) {
+		logger.Debugf("secret of API token %q not valid", id)
+		recordFailedLogin(user, a.LockoutThreshold, a.LockoutDuration)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	if !a.Clock.Now().Before(token.Expires()) {
+		logger.Debugf("API token %q has expired", id)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	// Failed logins are not reset here, as presenting a token
+	// proves nothing about the password; otherwise a client logging
+	// in with a token would keep clearing the count of a password
+	// guessing attempt.
+	return entity, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/authentication/totp"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type tokenSuite struct {
+	testing.IsolationSuite
+	clock  *testing.Clock
+	tokens fakeTokens
+	users  map[names.Tag]*tokenUser
+	auth   *authentication.TokenAuthenticator
+}
+
+var _ = gc.Suite(&tokenSuite{})
+
+type fakeToken struct {
+	owner   names.UserTag
+	expires time.Time
+	secret  string
+}
+
+func (t *fakeToken) Owner() names.UserTag           { return t.owner }
+func (t *fakeToken) Expires() time.Time             { return t.expires }
+func (t *fakeToken) SecretValid(secret string) bool { return secret == t.secret }
+
+type fakeTokens map[string]*fakeToken
+
+func (f fakeTokens) APIToken(id string) (authentication.APIToken, error) {
+	token, ok := f[id]
+	if !ok {
+		return nil, errors.NotFoundf("API token %q", id)
+	}
+	return token, nil
+}
+
+func (s *tokenSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC))
+	s.tokens = fakeTokens{
+		"bob:ci": {
+			owner:   names.NewUserTag("bob"),
+			expires: s.clock.Now().Add(time.Hour),
+			secret:  "s3cret",
+		},
+	}
+	s.users = map[names.Tag]*tokenUser{
+		names.NewUserTag("bob"): {tag: names.NewUserTag("bob")},
+	}
+	s.auth = &authentication.TokenAuthenticator{
+		Tokens:           s.tokens,
+		Clock:            s.clock,
+		LockoutThreshold: 3,
+	}
+}
+
+func (s *tokenSuite) FindEntity(tag names.Tag) (state.Entity, error) {
+	user, ok := s.users[tag]
+	if !ok {
+		return nil, errors.NotFoundf("user %q", tag.Id())
+	}
+	return user, nil
+}
+
+func (s *tokenSuite) login(tag names.Tag, token string) (state.Entity, error) {
+	return s.auth.Authenticate(s, tag, params.LoginRequest{Token: token})
+}
+
+func (s *tokenSuite) TestAuthenticate(c *gc.C) {
+	entity, err := s.login(nil, state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, names.NewUserTag("bob"))
+}
+
+func (s *tokenSuite) TestAuthenticateMatchingTag(c *gc.C) {
+	_, err := s.login(names.NewUserTag("bob"), state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.login(names.NewUserTag("mary"), state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenSuite) TestAuthenticateBadSecret(c *gc.C) {
+	_, err := s.login(nil, state.FormatAPIToken("bob:ci", "wrong"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	c.Assert(s.users[names.NewUserTag("bob")].failedLogins, gc.Equals, 1)
+}
+
+func (s *tokenSuite) TestAuthenticateBadSecretLocksOut(c *gc.C) {
+	for i := 0; i < 3; i++ {
+		_, err := s.login(nil, state.FormatAPIToken("bob:ci", "wrong"))
+		c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	}
+	_, err := s.login(nil, state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	c.Assert(s.users[names.NewUserTag("bob")].failedLogins, gc.Equals, 3)
+}
+
+func (s *tokenSuite) TestAuthenticateUnknownToken(c *gc.C) {
+	_, err := s.login(nil, state.FormatAPIToken("bob:deploy", "s3cret"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenSuite) TestAuthenticateMalformedToken(c *gc.C) {
+	_, err := s.login(nil, "garbage")
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenSuite) TestAuthenticateExpired(c *gc.C) {
+	s.clock.Advance(time.Hour)
+	_, err := s.login(nil, state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenSuite) TestAuthenticateUnknownOwner(c *gc.C) {
+	s.tokens["mary:ci"] = &fakeToken{
+		owner:   names.NewUserTag("mary"),
+		expires: s.clock.Now().Add(time.Hour),
+		secret:  "s3cret",
+	}
+	_, err := s.login(nil, state.FormatAPIToken("mary:ci", "s3cret"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenSuite) TestAuthenticateDisabledOwner(c *gc.C) {
+	s.users[names.NewUserTag("bob")].disabled = true
+	_, err := s.login(nil, state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenSuite) TestAuthenticateDeletedOwner(c *gc.C) {
+	s.users[names.NewUserTag("bob")].deleted = true
+	_, err := s.login(nil, state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenSuite) TestAuthenticateLockedOwner(c *gc.C) {
+	s.users[names.NewUserTag("bob")].failedLogins = 3
+	_, err := s.login(nil, state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenSuite) TestAuthenticateMFANotRequired(c *gc.C) {
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	s.users[names.NewUserTag("bob")].mfaSecret = secret
+	entity, err := s.login(nil, state.FormatAPIToken("bob:ci", "s3cret"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, names.NewUserTag("bob"))
+}
+
+// tokenUser is a local user owning API tokens.
+type tokenUser struct {
+	state.Entity
+	state.Authenticator
+	tag          names.Tag
+	disabled     bool
+	deleted      bool
+	failedLogins int
+	mfaSecret    string
+	mfaCounter   int64
+}
+
+func (u *tokenUser) Tag() names.Tag   { return u.tag }
+func (u *tokenUser) IsDisabled() bool { return u.disabled }
+func (u *tokenUser) IsDeleted() bool  { return u.deleted }
+func (u *tokenUser) IsLocked() bool   { return u.failedLogins >= 3 }
+func (u *tokenUser) MFAEnabled() bool { return u.mfaSecret != "" }
+func (u *tokenUser) MFASecret() string {
+	return u.mfaSecret
+}
+
+func (u *tokenUser) RecordFailedLogin(threshold int, lockout time.Duration) error {
+	u.failedLogins++
+	return nil
+}
+
+func (u *tokenUser) ResetFailedLogins() error {
+	u.failedLogins = 0
+	return nil
+}
+
+func (u *tokenUser) PasswordChanged() time.Time {
+	return time.Time{}
+}
+
+func (u *tokenUser) UseMFACounter(counter int64) error {
+	if counter <= u.mfaCounter {
+		return errors.New("code already used")
+	}
+	u.mfaCounter = counter
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package totp implements the time-based one-time passwords of
+// RFC 6238, as generated by common authenticator applications, for
+// use as a second authentication factor.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// Digits is the number of digits in a code.
+	Digits = 6
+
+	// Period is the length of time for which each code is valid.
+	Period = 30 * time.Second
+
+	// Skew is the number of periods either side of the current one
+	// for which codes are accepted, to allow for clock drift and for
+	// the time taken to type the code.
+	Skew = 1
+
+	secretLength = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret returns a new random secret, base32 encoded as
+// authenticator applications expect it to be entered.
+func NewSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Annotate(err, "generating secret")
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// URI returns an otpauth URI describing the given secret, which
+// authenticator applications accept, typically as a QR code, to
+// enrol the account.
+func URI(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("digits", fmt.Sprint(Digits))
+	v.Set("period", fmt.Sprint(int(Period/time.Second)))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// Counter returns the counter of the period containing the given time.
+func Counter(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// Code returns the code for the given secret and counter.
+func Code(secret string, counter int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", errors.NotValidf("secret")
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation, as described in RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod), nil
+}
+
+// Validate checks the code against the given secret at the given
+// time, returning the counter the code was generated for. Callers
+// should refuse codes whose counter is not greater than that of the
+// last code accepted, so that a code cannot be replayed.
+func Validate(secret, code string, now time.Time) (int64, error) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, errors.NotValidf("code")
+	}
+	current := Counter(now)
+	for counter := current - Skew; counter <= current+Skew; counter++ {
+		expect, err := Code(secret, counter)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if hmac.Equal([]byte(expect), []byte(code)) {
+			return counter, nil
+		}
+	}
+	return 0, errors.NotValidf("code")
+}
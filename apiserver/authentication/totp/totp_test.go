@@ -0,0 +1,90 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package totp_test
+
+import (
+	"net/url"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication/totp"
+)
+
+type totpSuite struct{}
+
+var _ = gc.Suite(&totpSuite{})
+
+// rfcSecret is the base32 encoding of the SHA1 secret used in the
+// test vectors of RFC 6238.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func (s *totpSuite) TestCodeRFCVectors(c *gc.C) {
+	for i, test := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+	} {
+		c.Logf("test %d: %d", i, test.unix)
+		code, err := totp.Code(rfcSecret, totp.Counter(time.Unix(test.unix, 0)))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(code, gc.Equals, test.code)
+	}
+}
+
+func (s *totpSuite) TestCodeInvalidSecret(c *gc.C) {
+	_, err := totp.Code("not base32!", 1)
+	c.Assert(err, gc.ErrorMatches, "secret not valid")
+}
+
+func (s *totpSuite) TestValidate(c *gc.C) {
+	now := time.Unix(1234567890, 0)
+	counter, err := totp.Validate(rfcSecret, "005924", now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(counter, gc.Equals, int64(41152263))
+
+	// Codes from adjacent periods are accepted.
+	counter, err = totp.Validate(rfcSecret, " 005924\n", now.Add(totp.Period))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(counter, gc.Equals, int64(41152263))
+}
+
+func (s *totpSuite) TestValidateInvalid(c *gc.C) {
+	now := time.Unix(1234567890, 0)
+	for _, code := range []string{"", "12345", "1234567", "005925"} {
+		_, err := totp.Validate(rfcSecret, code, now)
+		c.Check(err, gc.ErrorMatches, "code not valid")
+	}
+	_, err := totp.Validate(rfcSecret, "005924", now.Add(2*totp.Period))
+	c.Check(err, gc.ErrorMatches, "code not valid")
+}
+
+func (s *totpSuite) TestNewSecret(c *gc.C) {
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret, gc.HasLen, 32)
+	other, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(other, gc.Not(gc.Equals), secret)
+	_, err = totp.Code(secret, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *totpSuite) TestURI(c *gc.C) {
+	uri, err := url.Parse(totp.URI("juju", "bob", rfcSecret))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uri.Scheme, gc.Equals, "otpauth")
+	c.Assert(uri.Host, gc.Equals, "totp")
+	c.Assert(uri.Path, gc.Equals, "/juju:bob")
+	c.Assert(uri.Query(), jc.DeepEquals, url.Values{
+		"secret": {rfcSecret},
+		"issuer": {"juju"},
+		"digits": {"6"},
+		"period": {"30"},
+	})
+}
@@ -16,8 +16,10 @@ import (
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
 	"gopkg.in/macaroon.v1"
 
+	"github.com/juju/juju/apiserver/authentication/totp"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 )
 
@@ -38,6 +40,35 @@ type UserAuthenticator struct {
 	// to for local users. This always points at the same controller
 	// agent that is servicing the authorisation request.
 	LocalUserIdentityLocation string
+
+	// PasswordPolicy holds the controller's password policy. Password
+	// logins are refused once the user's password is older than the
+	// policy's MaxAge.
+	PasswordPolicy controller.PasswordPolicy
+
+	// LockoutThreshold is the number of consecutive failed password
+	// logins after which a local user is locked out, or zero if users
+	// are never locked out.
+	LockoutThreshold int
+
+	// LockoutDuration is how long a local user is locked out for.
+	LockoutDuration time.Duration
+}
+
+// passwordUser is implemented by local users logging in with a
+// password, whose logins are subject to lockout and password expiry.
+type passwordUser interface {
+	state.Entity
+	state.Authenticator
+	IsDisabled() bool
+	IsDeleted() bool
+	IsLocked() bool
+	RecordFailedLogin(threshold int, lockout time.Duration) error
+	ResetFailedLogins() error
+	PasswordChanged() time.Time
+	MFAEnabled() bool
+	MFASecret() string
+	UseMFACounter(counter int64) error
 }
 
 const (
@@ -74,9 +105,100 @@ func (u *UserAuthenticator) Authenticate(
 	if req.Credentials == "" && userTag.IsLocal() {
 		return u.authenticateMacaroons(entityFinder, userTag, req)
 	}
+	if userTag.IsLocal() {
+		return u.authenticatePassword(entityFinder, userTag, req)
+	}
 	return u.AgentAuthenticator.Authenticate(entityFinder, tag, req)
 }
 
+// authenticatePassword checks the password, and multi-factor
+// authentication code if the user has enabled it, of a local user,
+// recording failed attempts so that the user is locked out after too
+// many of them, and refusing logins with expired passwords.
+func (u *UserAuthenticator) authenticatePassword(
+	entityFinder EntityFinder, tag names.UserTag, req params.LoginRequest,
+) (state.Entity, error) {
+	entity, err := entityFinder.FindEntity(tag)
+	if errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	user, ok := entity.(passwordUser)
+	if !ok {
+		return nil, errors.Trace(common.ErrBadRequest)
+	}
+	if err := checkUserActive(user); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !user.PasswordValid(req.Credentials) {
+		recordFailedLogin(user, u.LockoutThreshold, u.LockoutDuration)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	if err := checkMFACode(user, req, u.Clock.Now(), u.LockoutThreshold, u.LockoutDuration); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := user.ResetFailedLogins(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if u.PasswordPolicy.MaxAge > 0 && u.PasswordPolicy.Expired(user.PasswordChanged(), u.Clock.Now()) {
+		return nil, errors.Trace(common.ErrPasswordExpired)
+	}
+	return entity, nil
+}
+
+// checkUserActive returns an error if the user may not log in because
+// they are disabled, deleted or locked out. So as not to reveal which
+// users exist, the error is the same as for an invalid password.
+func checkUserActive(user passwordUser) error {
+	if user.IsDisabled() || user.IsDeleted() {
+		return errors.Trace(common.ErrBadCreds)
+	}
+	if user.IsLocked() {
+		logger.Debugf("user %s is locked out", user.Tag().Id())
+		return errors.Trace(common.ErrBadCreds)
+	}
+	return nil
+}
+
+// checkMFACode checks the multi-factor authentication code in the
+// login request if the user has enabled it, recording an invalid code
+// as a failed login.
+func checkMFACode(
+	user passwordUser, req params.LoginRequest, now time.Time,
+	lockoutThreshold int, lockoutDuration time.Duration,
+) error {
+	if !user.MFAEnabled() {
+		return nil
+	}
+	if req.MFACode == "" {
+		return errors.Trace(common.ErrMFARequired)
+	}
+	counter, err := totp.Validate(user.MFASecret(), req.MFACode, now)
+	if err == nil {
+		err = user.UseMFACounter(counter)
+	}
+	if err != nil {
+		logger.Debugf("multi-factor authentication of %s failed: %v", user.Tag().Id(), err)
+		recordFailedLogin(user, lockoutThreshold, lockoutDuration)
+		return errors.Trace(common.ErrBadCreds)
+	}
+	return nil
+}
+
+// recordFailedLogin records a failed login for the user, locking them
+// out for the lockout duration if they have now failed to log in too
+// many times.
+func recordFailedLogin(user passwordUser, threshold int, lockout time.Duration) {
+	name := user.Tag().Id()
+	if err := user.RecordFailedLogin(threshold, lockout); err != nil {
+		logger.Errorf("cannot record failed login for %s: %v", name, err)
+	} else if user.IsLocked() {
+		logger.Warningf("user %s locked for %v after %d failed logins", name, lockout, threshold)
+	}
+}
+
 // CreateLocalLoginMacaroon creates a macaroon that may be provided to a
 // user as proof that they have logged in with a valid username and password.
 // This macaroon may then be used to obtain a discharge macaroon so that
@@ -21,8 +21,10 @@ import (
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/authentication/totp"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -120,6 +122,130 @@ func (s *userAuthenticatorSuite) TestUserLoginWrongPassword(c *gc.C) {
 
 }
 
+func (s *userAuthenticatorSuite) TestUserLoginLockout(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "bobbrown",
+		Password: "password",
+	})
+
+	authenticator := &authentication.UserAuthenticator{
+		LockoutThreshold: 2,
+		LockoutDuration:  time.Hour,
+	}
+	for i := 0; i < 2; i++ {
+		_, err := authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+			Credentials: "wrongpassword",
+		})
+		c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	}
+	// A locked out user sees the same error as for a bad password.
+	_, err := authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "password",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+
+	err = user.Enable()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "password",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *userAuthenticatorSuite) TestUserLoginResetsFailedLogins(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "bobbrown",
+		Password: "password",
+	})
+
+	authenticator := &authentication.UserAuthenticator{LockoutThreshold: 2}
+	for i := 0; i < 3; i++ {
+		_, err := authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+			Credentials: "wrongpassword",
+		})
+		c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+		_, err = authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+			Credentials: "password",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *userAuthenticatorSuite) TestUserLoginPasswordExpired(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "bobbrown",
+		Password: "password",
+	})
+
+	clock := testing.NewClock(user.PasswordChanged().Add(time.Hour))
+	authenticator := &authentication.UserAuthenticator{
+		Clock:          clock,
+		PasswordPolicy: controller.PasswordPolicy{MaxAge: 2 * time.Hour},
+	}
+	_, err := authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "password",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock.Advance(2 * time.Hour)
+	_, err = authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "password",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPasswordExpired)
+}
+
+func (s *userAuthenticatorSuite) TestUserLoginMFA(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "bobbrown",
+		Password: "password",
+	})
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetMFASecret(secret)
+	c.Assert(err, jc.ErrorIsNil)
+	clock := testing.NewClock(time.Now())
+	err = user.ConfirmMFA(totp.Counter(clock.Now()) - 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	authenticator := &authentication.UserAuthenticator{
+		Clock:            clock,
+		LockoutThreshold: 5,
+	}
+	_, err = authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "password",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrMFARequired)
+
+	_, err = authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "password",
+		MFACode:     "000000x",
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	code, err := totp.Code(secret, totp.Counter(clock.Now()))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "wrongpassword",
+		MFACode:     code,
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	_, err = authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "password",
+		MFACode:     code,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The same code cannot be used twice.
+	_, err = authenticator.Authenticate(s.State, user.Tag(), params.LoginRequest{
+		Credentials: "password",
+		MFACode:     code,
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.FailedLogins(), gc.Equals, 1)
+}
+
 func (s *userAuthenticatorSuite) TestInvalidRelationLogin(c *gc.C) {
 
 	// add relation
@@ -154,6 +154,72 @@ func (s *certSuite) TestAutocertNoAutocertDNSName(c *gc.C) {
 	}})
 }
 
+func (s *certSuite) newExternalTLSCert(c *gc.C) *tls.Certificate {
+	// The external certificate is issued by a CA that agents do
+	// not know about.
+	srvCert, srvKey, err := cert.NewServer(coretesting.OtherCACert, coretesting.OtherCAKey, time.Now().AddDate(1, 0, 0), []string{"*.example.com", "api.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	tlsCert, err := tls.X509KeyPair([]byte(srvCert), []byte(srvKey))
+	c.Assert(err, jc.ErrorIsNil)
+	tlsCert.Leaf, err = x509.ParseCertificate(tlsCert.Certificate[0])
+	c.Assert(err, jc.ErrorIsNil)
+	return &tlsCert
+}
+
+func (s *certSuite) TestExternalCertificate(c *gc.C) {
+	externalCert := s.newExternalTLSCert(c)
+	config := s.sampleConfig(c)
+	config.DisableAutocertChallengeHandler = true
+	config.GetExternalCertificate = func() *tls.Certificate {
+		return externalCert
+	}
+	srv := s.newServer(c, config)
+	apiInfo := s.APIInfo(srv)
+
+	// Clients connecting by a name in the external certificate
+	// are served it, and can verify it with the external CA.
+	externalCA := x509.NewCertPool()
+	c.Assert(externalCA.AppendCertsFromPEM([]byte(coretesting.OtherCACert)), jc.IsTrue)
+	conn, err := tls.Dial("tcp", apiInfo.Addrs[0], &tls.Config{
+		ServerName: "api.example.com",
+		RootCAs:    externalCA,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	conn.Close()
+
+	// Other names are served the local certificate.
+	_, err = tls.Dial("tcp", apiInfo.Addrs[0], &tls.Config{
+		ServerName: "somewhere.else",
+		RootCAs:    externalCA,
+	})
+	c.Assert(err, gc.NotNil)
+
+	// Agents, which use the controller CA, are unaffected.
+	apiConn := s.OpenAPIAsAdmin(c, srv)
+	c.Assert(pingConn(apiConn), jc.ErrorIsNil)
+	c.Assert(apiConn.PublicDNSName(), gc.Equals, "api.example.com")
+}
+
+func (s *certSuite) TestExternalCertificateRemoved(c *gc.C) {
+	externalCert := s.newExternalTLSCert(c)
+	config := s.sampleConfig(c)
+	config.DisableAutocertChallengeHandler = true
+	config.GetExternalCertificate = func() *tls.Certificate {
+		return externalCert
+	}
+	srv := s.newServer(c, config)
+	apiInfo := s.APIInfo(srv)
+
+	externalCert = nil
+	_, err := tls.Dial("tcp", apiInfo.Addrs[0], &tls.Config{
+		ServerName: "api.example.com",
+	})
+	c.Assert(err, gc.ErrorMatches, `x509: certificate is valid for \*, not api.example.com|x509: certificate signed by unknown authority`)
+
+	apiConn := s.OpenAPIAsAdmin(c, srv)
+	c.Assert(apiConn.PublicDNSName(), gc.Equals, "")
+}
+
 func gatherLog(f func()) []loggo.Entry {
 	var tw loggo.TestWriter
 	err := loggo.RegisterWriter("test", &tw)
@@ -8,6 +8,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 )
 
@@ -29,14 +30,21 @@ func NewControllerConfig(st state.ControllerAccessor) *ControllerConfigAPI {
 	}
 }
 
-// ControllerConfig returns the controller's configuration.
+// ControllerConfig returns the controller's configuration, without
+// the attributes that hold secrets.
 func (s *ControllerConfigAPI) ControllerConfig() (params.ControllerConfigResult, error) {
 	result := params.ControllerConfigResult{}
 	config, err := s.st.ControllerConfig()
 	if err != nil {
 		return result, err
 	}
-	result.Config = params.ControllerConfig(config)
+	result.Config = make(params.ControllerConfig)
+	for key, value := range config {
+		if controller.SecretConfigAttributes.Contains(key) {
+			continue
+		}
+		result.Config[key] = value
+	}
 	return result, nil
 }
 
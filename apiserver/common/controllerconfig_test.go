@@ -44,6 +44,8 @@ func (f *fakeControllerAccessor) ControllerConfig() (controller.Config, error) {
 		controller.CACertKey:         testing.CACert,
 		controller.APIPort:           4321,
 		controller.StatePort:         1234,
+		controller.ExternalAPIKeyKey: "private key",
+		controller.LDAPBindPassword:  "sekrit",
 	}, nil
 }
 
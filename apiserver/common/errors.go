@@ -91,6 +91,8 @@ var (
 	ErrBadCreds           = errors.New("invalid entity name or password")
 	ErrNoCreds            = errors.New("no credentials provided")
 	ErrLoginExpired       = errors.New("login expired")
+	ErrPasswordExpired    = errors.New("password expired")
+	ErrMFARequired        = errors.New("multi-factor authentication code required")
 	ErrPerm               = errors.New("permission denied")
 	ErrNotLoggedIn        = errors.New("not logged in")
 	ErrUnknownWatcher     = errors.New("unknown watcher id")
@@ -125,6 +127,8 @@ var singletonErrorCodes = map[error]string{
 	ErrBadCreds:                  params.CodeUnauthorized,
 	ErrNoCreds:                   params.CodeNoCreds,
 	ErrLoginExpired:              params.CodeLoginExpired,
+	ErrPasswordExpired:           params.CodeUnauthorized,
+	ErrMFARequired:               params.CodeUnauthorized,
 	ErrPerm:                      params.CodeUnauthorized,
 	ErrNotLoggedIn:               params.CodeUnauthorized,
 	ErrUnknownWatcher:            params.CodeNotFound,
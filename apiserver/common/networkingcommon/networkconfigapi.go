@@ -67,9 +67,75 @@ func (api *NetworkConfigAPI) SetObservedNetworkConfig(args params.SetMachineNetw
 		return errors.Trace(err)
 	}
 
+	if err := api.discoverIPv6Subnets(mergedConfig); err != nil {
+		return errors.Trace(err)
+	}
+
 	return api.setOneMachineNetworkConfig(m, mergedConfig)
 }
 
+// discoverIPv6Subnets adds to state the IPv6 subnets of addresses
+// observed on a machine which are not already known. Many providers
+// only report the IPv4 subnets of dual-stack networks, so an IPv6
+// subnet is added alongside a known IPv4 subnet on the same interface,
+// and takes its space, zone and provider network from it.
+func (api *NetworkConfigAPI) discoverIPv6Subnets(networkConfig []params.NetworkConfig) error {
+	knownByInterface := make(map[string]*state.Subnet)
+	var candidates []params.NetworkConfig
+	for _, config := range networkConfig {
+		if config.CIDR == "" {
+			continue
+		}
+		ip, _, err := net.ParseCIDR(config.CIDR)
+		if err != nil {
+			logger.Debugf("ignoring invalid CIDR %q on interface %q", config.CIDR, config.InterfaceName)
+			continue
+		}
+		if ip.To4() == nil {
+			if ip.IsGlobalUnicast() {
+				candidates = append(candidates, config)
+			}
+			continue
+		}
+		if _, ok := knownByInterface[config.InterfaceName]; ok {
+			continue
+		}
+		subnet, err := api.st.Subnet(config.CIDR)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		knownByInterface[config.InterfaceName] = subnet
+	}
+
+	for _, config := range candidates {
+		sibling, ok := knownByInterface[config.InterfaceName]
+		if !ok {
+			continue
+		}
+		if _, err := api.st.Subnet(config.CIDR); err == nil {
+			continue
+		} else if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		_, err := api.st.AddSubnet(state.SubnetInfo{
+			CIDR:              config.CIDR,
+			VLANTag:           sibling.VLANTag(),
+			ProviderNetworkId: sibling.ProviderNetworkId(),
+			AvailabilityZone:  sibling.AvailabilityZone(),
+			SpaceName:         sibling.SpaceName(),
+		})
+		if errors.IsAlreadyExists(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("discovered IPv6 subnet %q alongside subnet %q", config.CIDR, sibling.CIDR())
+	}
+	return nil
+}
+
 // fixUpFanSubnets takes network config and updates FAN subnets with proper CIDR, providerId and providerSubnetId.
 // The method how fan overlay is cut into segments is described in network/fan.go.
 func (api *NetworkConfigAPI) fixUpFanSubnets(networkConfig []params.NetworkConfig) ([]params.NetworkConfig, error) {
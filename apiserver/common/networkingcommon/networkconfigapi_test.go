@@ -4,6 +4,7 @@
 package networkingcommon_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -84,6 +85,55 @@ func (s *networkConfigSuite) TestSetObservedNetworkConfig(c *gc.C) {
 	}
 }
 
+func (s *networkConfigSuite) TestSetObservedNetworkConfigDiscoversIPv6Subnets(c *gc.C) {
+	_, err := s.State.AddSpace("dmz", "", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(state.SubnetInfo{
+		CIDR:              "0.10.0.0/24",
+		ProviderNetworkId: "dummy-net",
+		AvailabilityZone:  "zone1",
+		SpaceName:         "dmz",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetInstanceInfo("i-foo", "FAKE_NONCE", nil, nil, nil, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.SetMachineNetworkConfig{
+		Tag: s.machine.Tag().String(),
+		Config: []params.NetworkConfig{{
+			InterfaceName: "eth0",
+			InterfaceType: "ethernet",
+			MACAddress:    "aa:bb:cc:dd:ee:f0",
+			CIDR:          "0.10.0.0/24",
+			Address:       "0.10.0.2",
+		}, {
+			InterfaceName: "eth0",
+			InterfaceType: "ethernet",
+			MACAddress:    "aa:bb:cc:dd:ee:f0",
+			CIDR:          "2001:db8:10::/64",
+			Address:       "2001:db8:10::2",
+		}, {
+			InterfaceName: "eth1",
+			InterfaceType: "ethernet",
+			MACAddress:    "aa:bb:cc:dd:ee:f1",
+			CIDR:          "2001:db8:20::/64",
+			Address:       "2001:db8:20::2",
+		}},
+	}
+	err = s.networkconfig.SetObservedNetworkConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	subnet, err := s.State.Subnet("2001:db8:10::/64")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(subnet.SpaceName(), gc.Equals, "dmz")
+	c.Check(subnet.AvailabilityZone(), gc.Equals, "zone1")
+	c.Check(string(subnet.ProviderNetworkId()), gc.Equals, "dummy-net")
+
+	// There is no known subnet on eth1 to place its IPv6 subnet by.
+	_, err = s.State.Subnet("2001:db8:20::/64")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *networkConfigSuite) TestSetObservedNetworkConfigPermissions(c *gc.C) {
 	args := params.SetMachineNetworkConfig{
 		Tag:    "machine-1",
@@ -31,6 +31,8 @@ func HasPermission(
 		validate = permission.ValidateModelAccess
 	case names.ApplicationOfferTagKind:
 		validate = permission.ValidateOfferAccess
+	case names.ApplicationTagKind:
+		validate = permission.ValidateApplicationAccess
 	default:
 		return false, nil
 	}
@@ -56,7 +58,8 @@ func HasPermission(
 	modelPermission := userAccess.EqualOrGreaterModelAccessThan(requestedPermission) && target.Kind() == names.ModelTagKind
 	controllerPermission := userAccess.EqualOrGreaterControllerAccessThan(requestedPermission) && target.Kind() == names.ControllerTagKind
 	offerPermission := userAccess.EqualOrGreaterOfferAccessThan(requestedPermission) && target.Kind() == names.ApplicationOfferTagKind
-	if !controllerPermission && !modelPermission && !offerPermission {
+	applicationPermission := userAccess.EqualOrGreaterApplicationAccessThan(requestedPermission) && target.Kind() == names.ApplicationTagKind
+	if !controllerPermission && !modelPermission && !offerPermission && !applicationPermission {
 		return false, nil
 	}
 	return true, nil
@@ -106,3 +109,26 @@ func HasModelAdmin(
 	}
 	return authorizer.HasPermission(permission.AdminAccess, model.ModelTag())
 }
+
+// HasApplicationPermission reports whether the authenticated user may
+// perform the given operation on the named application. Read access
+// to the model allows the user to read all of its applications, and
+// write access allows any operation on them; otherwise the user must
+// have been granted at least the requested access on the application
+// itself.
+func HasApplicationPermission(
+	authorizer facade.Authorizer,
+	modelTag names.ModelTag,
+	appName string,
+	operation permission.Access,
+) (bool, error) {
+	modelAccess := permission.WriteAccess
+	if operation == permission.ReadAccess {
+		modelAccess = permission.ReadAccess
+	}
+	allowed, err := authorizer.HasPermission(modelAccess, modelTag)
+	if err != nil || allowed {
+		return allowed, errors.Trace(err)
+	}
+	return authorizer.HasPermission(operation, names.NewApplicationTag(appName))
+}
@@ -10,6 +10,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/testing"
 )
@@ -130,6 +131,30 @@ func (r *PermissionSuite) TestHasPermission(c *gc.C) {
 			access:           permission.AddModelAccess,
 			expected:         false,
 		},
+		{
+			title:            "user has lesser application permission than required",
+			userGetterAccess: permission.ReadAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewApplicationTag("mysql"),
+			access:           permission.OperateAccess,
+			expected:         false,
+		},
+		{
+			title:            "user has greater application permission than required",
+			userGetterAccess: permission.AdminAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewApplicationTag("mysql"),
+			access:           permission.OperateAccess,
+			expected:         true,
+		},
+		{
+			title:            "user requests model permission on application",
+			userGetterAccess: permission.AdminAccess,
+			user:             names.NewUserTag("validuser"),
+			target:           names.NewApplicationTag("mysql"),
+			access:           permission.WriteAccess,
+			expected:         false,
+		},
 	}
 	for i, t := range testCases {
 		userGetter := &fakeUserAccess{
@@ -221,3 +246,55 @@ func (r *PermissionSuite) TestEveryoneAtExternal(c *gc.C) {
 		c.Assert(hasPermission, gc.Equals, t.expected)
 	}
 }
+
+func (r *PermissionSuite) TestHasApplicationPermission(c *gc.C) {
+	modelTag := names.NewModelTag("beef1beef2-0000-0000-000011112222")
+	testCases := []struct {
+		title    string
+		user     string
+		access   permission.Access
+		expected bool
+	}{{
+		title:    "model writers may operate any application",
+		user:     "write",
+		access:   permission.AdminAccess,
+		expected: true,
+	}, {
+		title:    "application grant allows operation",
+		user:     "operate-application-mysql",
+		access:   permission.OperateAccess,
+		expected: true,
+	}, {
+		title:    "application grant does not extend to other applications",
+		user:     "operate-application-wordpress",
+		access:   permission.OperateAccess,
+		expected: false,
+	}, {
+		title:    "model readers may not operate applications",
+		user:     "read",
+		access:   permission.OperateAccess,
+		expected: false,
+	}, {
+		title:    "model readers may read any application",
+		user:     "read",
+		access:   permission.ReadAccess,
+		expected: true,
+	}, {
+		title:    "application read grant allows reading without model access",
+		user:     "read-application-mysql",
+		access:   permission.ReadAccess,
+		expected: true,
+	}, {
+		title:    "application read grant does not allow operation",
+		user:     "read-application-mysql",
+		access:   permission.OperateAccess,
+		expected: false,
+	}}
+	for i, t := range testCases {
+		c.Logf("HasApplicationPermission test n %d: %s", i, t.title)
+		authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUserTag(t.user)}
+		hasPermission, err := common.HasApplicationPermission(authorizer, modelTag, "mysql", t.access)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(hasPermission, gc.Equals, t.expected)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
@@ -275,17 +276,23 @@ func MaybeAssignedStorageInstance(
 func storageTags(
 	storageInstance state.StorageInstance,
 	modelUUID, controllerUUID string,
-	tagger tags.ResourceTagger,
+	environConfig *config.Config,
 ) (map[string]string, error) {
+	var ownerId string
+	if storageInstance != nil {
+		if owner, ok := storageInstance.Owner(); ok {
+			ownerId = owner.Id()
+		}
+	}
 	storageTags := tags.ResourceTags(
 		names.NewModelTag(modelUUID),
 		names.NewControllerTag(controllerUUID),
-		tagger,
+		tags.Templated(environConfig, tags.StorageTemplateValues(environConfig.Name(), ownerId)),
 	)
 	if storageInstance != nil {
 		storageTags[tags.JujuStorageInstance] = storageInstance.Tag().Id()
-		if owner, ok := storageInstance.Owner(); ok {
-			storageTags[tags.JujuStorageOwner] = owner.Id()
+		if ownerId != "" {
+			storageTags[tags.JujuStorageOwner] = ownerId
 		}
 	}
 	return storageTags, nil
@@ -89,3 +89,31 @@ func (*volumesSuite) TestVolumeParamsStorageTags(c *gc.C) {
 		},
 	})
 }
+
+func (*volumesSuite) TestVolumeParamsTemplatedTags(c *gc.C) {
+	volumeTag := names.NewVolumeTag("100")
+	storageTag := names.NewStorageTag("mystore/0")
+	unitTag := names.NewUnitTag("mysql/123")
+	p, err := storagecommon.VolumeParams(
+		&fakeVolume{tag: volumeTag, params: &state.VolumeParams{
+			Pool: "loop", Size: 1024,
+		}},
+		&fakeStorageInstance{tag: storageTag, owner: unitTag},
+		testing.ModelTag.Id(),
+		testing.ControllerTag.Id(),
+		testing.CustomModelConfig(c, testing.Attrs{
+			"resource-tags": "owner=${unit} cost=${model}/${application} host=${machine}",
+		}),
+		&fakePoolManager{},
+		provider.CommonStorageProviders(),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Tags, jc.DeepEquals, map[string]string{
+		tags.JujuController:      testing.ControllerTag.Id(),
+		tags.JujuModel:           testing.ModelTag.Id(),
+		tags.JujuStorageInstance: "mystore/0",
+		tags.JujuStorageOwner:    "mysql/123",
+		"owner":                  "mysql/123",
+		"cost":                   "testenv/mysql",
+	})
+}
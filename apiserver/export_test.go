@@ -109,7 +109,7 @@ func TestingAPIHandler(c *gc.C, pool *state.StatePool, st *state.State) (*apiHan
 		statePool:     pool,
 		tag:           names.NewMachineTag("0"),
 	}
-	h, err := newAPIHandler(srv, st, nil, st.ModelUUID(), 6543, "testing.invalid:1234")
+	h, err := newAPIHandler(srv, st, nil, st.ModelUUID(), 6543, "testing.invalid:1234", "testing.invalid:5678")
 	c.Assert(err, jc.ErrorIsNil)
 	return h, h.getResources()
 }
@@ -165,6 +165,23 @@ func TestingCAASModelOnlyRoot() rpc.Root {
 	return restrictRoot(r, caasModelFacadesOnly)
 }
 
+// TestingAPITokenRoot returns a restricted srvRoot as if logged
+// in with an API token with the given scope.
+func TestingAPITokenRoot(facades []string, readOnly bool) rpc.Root {
+	r := TestingAPIRoot(AllFacades())
+	return restrictRoot(r, apiTokenMethodsOnly(facades, readOnly))
+}
+
+// TestingMFAEnrolmentRoot returns a restricted srvRoot as if logged
+// in as a user who must enable multi-factor authentication.
+func TestingMFAEnrolmentRoot() rpc.Root {
+	r := TestingAPIRoot(AllFacades())
+	return restrictRoot(r, mfaEnrolmentMethodsOnly)
+}
+
+// APITokenAllowsModel exposes apiTokenAllowsModel for testing.
+var APITokenAllowsModel = apiTokenAllowsModel
+
 // TestingRestrictedRoot returns a restricted srvRoot.
 func TestingRestrictedRoot(check func(string, string) error) rpc.Root {
 	r := TestingAPIRoot(AllFacades())
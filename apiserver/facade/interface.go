@@ -111,6 +111,10 @@ type Authorizer interface {
 	// ConnectedModel returns the UUID of the model to which the API
 	// connection was made.
 	ConnectedModel() string
+
+	// APIToken returns the API token the authenticated entity logged
+	// in with, or nil if it did not log in with one.
+	APIToken() *state.APIToken
 }
 
 // Resources allows you to store and retrieve Resource implementations.
@@ -7,6 +7,8 @@
 package agent
 
 import (
+	"encoding/base64"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
@@ -63,6 +65,23 @@ func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Auth
 	}, nil
 }
 
+// AgentAPIV3 implements version 3 of the Agent API, which adds
+// WatchControllerCACert and AcknowledgeControllerCACerts.
+type AgentAPIV3 struct {
+	*AgentAPIV2
+}
+
+// NewAgentAPIV3 returns an object implementing version 3 of the Agent
+// API with the given authorizer representing the currently logged in
+// client.
+func NewAgentAPIV3(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV3, error) {
+	v2, err := NewAgentAPIV2(st, resources, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AgentAPIV3{v2}, nil
+}
+
 func (api *AgentAPIV2) GetEntities(args params.Entities) params.AgentGetEntitiesResults {
 	results := params.AgentGetEntitiesResults{
 		Entities: make([]params.AgentGetEntitiesResult, len(args.Entities)),
@@ -122,6 +141,13 @@ func (api *AgentAPIV2) StateServingInfo() (result params.StateServingInfo, err e
 		CAPrivateKey:   info.CAPrivateKey,
 		SharedSecret:   info.SharedSecret,
 		SystemIdentity: info.SystemIdentity,
+
+		SSHCAPrivateKey: info.SSHCAPrivateKey,
+	}
+	// The secrets key is not stored in the database; new
+	// controllers get it from the configuration of this one.
+	if key := api.st.SecretsKey(); len(key) != 0 {
+		result.SecretsKey = base64.StdEncoding.EncodeToString(key)
 	}
 
 	return result, nil
@@ -188,3 +214,45 @@ func (api *AgentAPIV2) WatchCredentials(args params.Entities) (params.NotifyWatc
 	}
 	return results, nil
 }
+
+// WatchControllerCACert returns a watcher that notifies when the
+// controller configuration, and so possibly the controller CA
+// certificate, changes. Agents use it to record the CA certificates
+// they must trust while the controller CA is rotated.
+func (api *AgentAPIV3) WatchControllerCACert() (params.NotifyWatchResult, error) {
+	var result params.NotifyWatchResult
+	watch := api.st.WatchControllerConfig()
+	// Consume the initial event. Technically, API calls to Watch
+	// 'transmit' the initial event in the Watch response. But
+	// NotifyWatchers have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		result.Error = common.ServerError(watcher.EnsureErr(watch))
+	}
+	return result, nil
+}
+
+// AcknowledgeControllerCACerts records the controller CA certificates
+// that agents have recorded in their configuration, so that a rotation
+// of the controller CA does not retire the old CA before every agent
+// trusts the new one.
+func (api *AgentAPIV3) AcknowledgeControllerCACerts(args params.AgentCACerts) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.CACerts)),
+	}
+	for i, arg := range args.CACerts {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !api.auth.AuthOwner(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = api.st.AcknowledgeControllerCACert(tag, arg.CACert)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
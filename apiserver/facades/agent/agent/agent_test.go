@@ -276,3 +276,43 @@ func (s *agentSuite) TestWatchAuthError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 }
+
+func (s *agentSuite) TestAcknowledgeControllerCACerts(c *gc.C) {
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := api.AcknowledgeControllerCACerts(params.AgentCACerts{
+		CACerts: []params.AgentCACert{
+			{Tag: "machine-0", CACert: coretesting.CACert},
+			{Tag: "machine-1", CACert: coretesting.CACert},
+			{Tag: "machine-42", CACert: coretesting.CACert},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *agentSuite) TestWatchControllerCACert(c *gc.C) {
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.WatchControllerCACert()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+
+	w := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w)
+
+	// The initial event has been consumed by the Watch call.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.State.StartControllerCARotation(coretesting.OtherCACert, coretesting.OtherCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
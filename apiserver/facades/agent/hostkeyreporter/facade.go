@@ -6,23 +6,31 @@
 package hostkeyreporter
 
 import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"golang.org/x/crypto/ssh"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	jujussh "github.com/juju/juju/network/ssh"
 	"github.com/juju/juju/state"
 )
 
 // Backend defines the State API used by the hostkeyreporter facade.
 type Backend interface {
 	SetSSHHostKeys(names.MachineTag, state.SSHHostKeys) error
+	SSHCAPrivateKey() (string, error)
+	MachineAddresses(names.MachineTag) ([]network.Address, error)
 }
 
 // Facade implements the API required by the hostkeyreporter worker.
 type Facade struct {
 	backend      Backend
 	getCanModify common.GetAuthFunc
+	clock        clock.Clock
 }
 
 // New returns a new API facade for the hostkeyreporter worker.
@@ -32,6 +40,7 @@ func New(backend Backend, _ facade.Resources, authorizer facade.Authorizer) (*Fa
 		getCanModify: func() (common.AuthFunc, error) {
 			return authorizer.AuthOwner, nil
 		},
+		clock: clock.WallClock,
 	}, nil
 }
 
@@ -60,3 +69,65 @@ func (facade *Facade) ReportKeys(args params.SSHHostKeySet) (params.ErrorResults
 	}
 	return results, nil
 }
+
+// SignKeys signs the SSH host keys of one or more entities with the
+// controller's SSH certificate authority. The certificates are valid
+// for the machine's current addresses, and are returned in the order
+// of the keys given.
+func (facade *Facade) SignKeys(args params.SSHHostKeySet) (params.SSHHostKeyCertificatesResults, error) {
+	results := params.SSHHostKeyCertificatesResults{
+		Results: make([]params.SSHHostKeyCertificatesResult, len(args.EntityKeys)),
+	}
+
+	canModify, err := facade.getCanModify()
+	if err != nil {
+		return results, err
+	}
+
+	var ca *jujussh.CertificateAuthority
+	for i, arg := range args.EntityKeys {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil || !canModify(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if ca == nil {
+			privateKey, err := facade.backend.SSHCAPrivateKey()
+			if err != nil {
+				return results, errors.Trace(err)
+			}
+			ca, err = jujussh.ParseCertificateAuthority(privateKey, facade.clock)
+			if err != nil {
+				return results, errors.Trace(err)
+			}
+		}
+		certs, err := facade.signKeys(ca, tag, arg.PublicKeys)
+		results.Results[i].Certificates = certs
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (facade *Facade) signKeys(ca *jujussh.CertificateAuthority, tag names.MachineTag, publicKeys []string) ([]string, error) {
+	addresses, err := facade.backend.MachineAddresses(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hosts := make([]string, len(addresses))
+	for i, address := range addresses {
+		hosts[i] = address.Value
+	}
+	certs := make([]string, len(publicKeys))
+	for i, key := range publicKeys {
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+		if err != nil {
+			return nil, errors.NewNotValid(err, "invalid public key")
+		}
+		cert, err := ca.SignHostKey(publicKey, tag.String(), hosts)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		certs[i] = string(ssh.MarshalAuthorizedKey(cert))
+	}
+	return certs, nil
+}
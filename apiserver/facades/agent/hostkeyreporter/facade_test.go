@@ -6,12 +6,15 @@ package hostkeyreporter_test
 import (
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/ssh"
+	cryptossh "golang.org/x/crypto/ssh"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/facades/agent/hostkeyreporter"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
@@ -26,7 +29,9 @@ type facadeSuite struct {
 var _ = gc.Suite(&facadeSuite{})
 
 func (s *facadeSuite) SetUpTest(c *gc.C) {
-	s.backend = new(mockBackend)
+	caKey, _, err := ssh.GenerateKey("juju-ssh-ca")
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend = &mockBackend{caKey: caKey}
 	s.authorizer = new(apiservertesting.FakeAuthorizer)
 	facade, err := hostkeyreporter.New(s.backend, nil, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
@@ -65,11 +70,80 @@ func (s *facadeSuite) TestReportKeys(c *gc.C) {
 	}})
 }
 
+func (s *facadeSuite) TestSignKeys(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("1")
+	_, hostKey, err := ssh.GenerateKey("host")
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.SSHHostKeySet{
+		EntityKeys: []params.SSHHostKeys{
+			{
+				Tag:        names.NewMachineTag("0").String(),
+				PublicKeys: []string{hostKey},
+			}, {
+				Tag:        names.NewMachineTag("1").String(),
+				PublicKeys: []string{hostKey},
+			},
+		},
+	}
+	result, err := s.facade.SignKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[1].Certificates, gc.HasLen, 1)
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"SSHCAPrivateKey", []interface{}{}},
+		{"MachineAddresses", []interface{}{names.NewMachineTag("1")}},
+	})
+
+	pub, _, _, _, err := cryptossh.ParseAuthorizedKey([]byte(result.Results[1].Certificates[0]))
+	c.Assert(err, jc.ErrorIsNil)
+	cert, ok := pub.(*cryptossh.Certificate)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(cert.CertType, gc.Equals, uint32(cryptossh.HostCert))
+	c.Check(cert.KeyId, gc.Equals, "machine-1")
+	c.Check(cert.ValidPrincipals, jc.DeepEquals, []string{"10.0.0.1", "fc00::1"})
+
+	caSigner, err := cryptossh.ParsePrivateKey([]byte(s.backend.caKey))
+	c.Assert(err, jc.ErrorIsNil)
+	checker := &cryptossh.CertChecker{
+		IsHostAuthority: func(auth cryptossh.PublicKey, _ string) bool {
+			return string(auth.Marshal()) == string(caSigner.PublicKey().Marshal())
+		},
+	}
+	c.Assert(checker.CheckHostKey("10.0.0.1:22", nil, cert), jc.ErrorIsNil)
+}
+
+func (s *facadeSuite) TestSignKeysInvalidKey(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("1")
+	result, err := s.facade.SignKeys(params.SSHHostKeySet{
+		EntityKeys: []params.SSHHostKeys{{
+			Tag:        names.NewMachineTag("1").String(),
+			PublicKeys: []string{"rubbish"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "invalid public key: .*")
+}
+
 type mockBackend struct {
-	stub jujutesting.Stub
+	stub  jujutesting.Stub
+	caKey string
 }
 
 func (backend *mockBackend) SetSSHHostKeys(tag names.MachineTag, keys state.SSHHostKeys) error {
 	backend.stub.AddCall("SetSSHHostKeys", tag, keys)
 	return nil
 }
+
+func (backend *mockBackend) SSHCAPrivateKey() (string, error) {
+	backend.stub.AddCall("SSHCAPrivateKey")
+	return backend.caKey, nil
+}
+
+func (backend *mockBackend) MachineAddresses(tag names.MachineTag) ([]network.Address, error) {
+	backend.stub.AddCall("MachineAddresses", tag)
+	return network.NewAddresses("10.0.0.1", "fc00::1"), nil
+}
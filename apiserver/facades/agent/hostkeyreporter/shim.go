@@ -5,16 +5,32 @@ package hostkeyreporter
 
 import (
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
 
 // NewFacade wraps New to express the supplied *state.State as a Backend.
 func NewFacade(st *state.State, res facade.Resources, auth facade.Authorizer) (*Facade, error) {
-	facade, err := New(st, res, auth)
+	facade, err := New(backend{st}, res, auth)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return facade, nil
 }
+
+type backend struct {
+	*state.State
+}
+
+// MachineAddresses returns the addresses of the machine with the
+// given tag.
+func (b backend) MachineAddresses(tag names.MachineTag) ([]network.Address, error) {
+	machine, err := b.State.Machine(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machine.Addresses(), nil
+}
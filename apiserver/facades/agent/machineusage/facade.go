@@ -0,0 +1,74 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machineusage implements the API facade used by the
+// machineusage worker.
+package machineusage
+
+import (
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the State API used by the machineusage facade.
+type Backend interface {
+	RecordMachineUsage(machineId string, usage state.MachineUsage) error
+}
+
+// Facade implements the API required by the machineusage worker.
+type Facade struct {
+	backend      Backend
+	getCanModify common.GetAuthFunc
+}
+
+// New returns a new API facade for the machineusage worker.
+func New(backend Backend, _ facade.Resources, authorizer facade.Authorizer) (*Facade, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &Facade{
+		backend: backend,
+		getCanModify: func() (common.AuthFunc, error) {
+			return authorizer.AuthOwner, nil
+		},
+	}, nil
+}
+
+// RecordUsage records samples of resource usage for one or more
+// machines.
+func (facade *Facade) RecordUsage(args params.MachineUsageSamples) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Samples)),
+	}
+
+	canModify, err := facade.getCanModify()
+	if err != nil {
+		return results, err
+	}
+
+	for i, arg := range args.Samples {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canModify(tag) {
+			err = facade.backend.RecordMachineUsage(tag.Id(), state.MachineUsage{
+				Time:        arg.Time,
+				CPU:         arg.CPU,
+				Cores:       arg.Cores,
+				MemoryUsed:  arg.MemoryUsed,
+				MemoryTotal: arg.MemoryTotal,
+				DiskUsed:    arg.DiskUsed,
+				DiskTotal:   arg.DiskTotal,
+			})
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
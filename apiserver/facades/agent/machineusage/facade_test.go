@@ -0,0 +1,98 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineusage_test
+
+import (
+	"time"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/machineusage"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	testing.BaseSuite
+	backend    *mockBackend
+	authorizer *apiservertesting.FakeAuthorizer
+	facade     *machineusage.Facade
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) SetUpTest(c *gc.C) {
+	s.backend = new(mockBackend)
+	s.authorizer = &apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("1"),
+	}
+	facade, err := machineusage.New(s.backend, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func (s *facadeSuite) TestNewRequiresMachineAgent(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bob")
+	_, err := machineusage.New(s.backend, nil, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *facadeSuite) TestRecordUsage(c *gc.C) {
+	now := time.Now()
+	args := params.MachineUsageSamples{
+		Samples: []params.MachineUsageSample{{
+			Tag:  names.NewMachineTag("0").String(),
+			Time: now,
+			CPU:  12.5,
+		}, {
+			Tag:         names.NewMachineTag("1").String(),
+			Time:        now,
+			CPU:         50,
+			Cores:       2,
+			MemoryUsed:  512,
+			MemoryTotal: 2048,
+			DiskUsed:    1024,
+			DiskTotal:   8192,
+		}, {
+			Tag: "application-foo",
+		}},
+	}
+	result, err := s.facade.RecordUsage(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{nil},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{{
+		"RecordMachineUsage",
+		[]interface{}{"1", state.MachineUsage{
+			Time:        now,
+			CPU:         50,
+			Cores:       2,
+			MemoryUsed:  512,
+			MemoryTotal: 2048,
+			DiskUsed:    1024,
+			DiskTotal:   8192,
+		}},
+	}})
+}
+
+type mockBackend struct {
+	stub jujutesting.Stub
+}
+
+func (backend *mockBackend) RecordMachineUsage(machineId string, usage state.MachineUsage) error {
+	backend.stub.AddCall("RecordMachineUsage", machineId, usage)
+	return nil
+}
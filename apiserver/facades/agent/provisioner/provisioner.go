@@ -153,6 +153,25 @@ func NewProvisionerAPIV5(st *state.State, resources facade.Resources, authorizer
 	return &ProvisionerAPIV5{provisionerAPI}, nil
 }
 
+// ProvisionerAPIV6 provides v6 of the Provisioner API facade,
+// which adds WatchUnits.
+type ProvisionerAPIV6 struct {
+	*ProvisionerAPIV5
+	*common.UnitsWatcher
+}
+
+// NewProvisionerAPIV6 creates a new server-side Provisioner API facade.
+func NewProvisionerAPIV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV6, error) {
+	provisionerAPI, err := NewProvisionerAPIV5(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ProvisionerAPIV6{
+		ProvisionerAPIV5: provisionerAPI,
+		UnitsWatcher:     common.NewUnitsWatcher(st, resources, provisionerAPI.getAuthFunc),
+	}, nil
+}
+
 func (p *ProvisionerAPI) getMachine(canAccess common.AuthFunc, tag names.MachineTag) (*state.Machine, error) {
 	if !canAccess(tag) {
 		return nil, common.ErrPerm
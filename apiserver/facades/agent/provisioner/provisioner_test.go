@@ -632,6 +632,36 @@ func (s *withoutControllerSuite) TestWatchContainers(c *gc.C) {
 	wc1.AssertNoChange()
 }
 
+func (s *withoutControllerSuite) TestWatchUnits(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	provisionerV6, err := provisioner.NewProvisionerAPIV6(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: "machine-42"},
+		{Tag: "unit-foo-0"},
+	}}
+	result, err := provisionerV6.WatchUnits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].StringsWatcherId, gc.Equals, "1")
+	c.Assert(result.Results[0].Changes, gc.HasLen, 0)
+	c.Assert(result.Results[1].Error, jc.DeepEquals, apiservertesting.NotFoundError("machine 42"))
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	// Verify the resource was registered and stop it when done.
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	w := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w)
+
+	// Check that the Watch has consumed the initial event ("returned"
+	// in the Watch call)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
+
 func (s *withoutControllerSuite) TestWatchAllContainers(c *gc.C) {
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 
@@ -96,19 +96,25 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		return nil, errors.Annotate(err, "cannot get controller configuration")
 	}
 
+	sshUserCAPublicKey, err := p.st.SSHCAPublicKey()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get SSH CA public key")
+	}
+
 	return &params.ProvisioningInfo{
-		Constraints:       cons,
-		Series:            m.Series(),
-		Placement:         m.Placement(),
-		Jobs:              jobs,
-		Volumes:           volumes,
-		VolumeAttachments: volumeAttachments,
-		Tags:              tags,
-		SubnetsToZones:    subnetsToZones,
-		EndpointBindings:  endpointBindings,
-		ImageMetadata:     imageMetadata,
-		ControllerConfig:  controllerCfg,
-		CloudInitUserData: env.Config().CloudInitUserData(),
+		Constraints:        cons,
+		Series:             m.Series(),
+		Placement:          m.Placement(),
+		Jobs:               jobs,
+		Volumes:            volumes,
+		VolumeAttachments:  volumeAttachments,
+		Tags:               tags,
+		SubnetsToZones:     subnetsToZones,
+		EndpointBindings:   endpointBindings,
+		ImageMetadata:      imageMetadata,
+		ControllerConfig:   controllerCfg,
+		CloudInitUserData:  env.Config().CloudInitUserData(),
+		SSHUserCAPublicKey: sshUserCAPublicKey,
 	}, nil
 }
 
@@ -215,11 +221,13 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 		return nil, errors.Trace(err)
 	}
 	unitNames := make([]string, 0, len(units))
+	applicationNames := set.NewStrings()
 	for _, unit := range units {
 		if !unit.IsPrincipal() {
 			continue
 		}
 		unitNames = append(unitNames, unit.Name())
+		applicationNames.Add(unit.ApplicationName())
 	}
 	sort.Strings(unitNames)
 
@@ -231,7 +239,13 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	machineTags := instancecfg.InstanceTags(cfg.UUID(), controllerCfg.ControllerUUID(), cfg, jobs)
+	tagger := tags.Templated(cfg, tags.TemplateValues{
+		Model:       cfg.Name(),
+		Machine:     m.Id(),
+		Application: strings.Join(applicationNames.SortedValues(), " "),
+		Unit:        strings.Join(unitNames, " "),
+	})
+	machineTags := instancecfg.InstanceTags(cfg.UUID(), controllerCfg.ControllerUUID(), tagger, jobs)
 	if len(unitNames) > 0 {
 		machineTags[tags.JujuUnitsDeployed] = strings.Join(unitNames, " ")
 	}
@@ -56,12 +56,15 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithStorage(c *gc.C) {
 	// Dummy provider uses a random port, which is added to cfg used to create environment.
 	apiPort := dummy.APIPort(s.Environ.Provider())
 	controllerCfg["api-port"] = apiPort
+	sshCAPublicKey, err := s.State.SSHCAPublicKey()
+	c.Assert(err, jc.ErrorIsNil)
 	expected := params.ProvisioningInfoResults{
 		Results: []params.ProvisioningInfoResult{
 			{Result: &params.ProvisioningInfo{
-				ControllerConfig: controllerCfg,
-				Series:           "quantal",
-				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				ControllerConfig:   controllerCfg,
+				SSHUserCAPublicKey: sshCAPublicKey,
+				Series:             "quantal",
+				Jobs:               []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
@@ -69,11 +72,12 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithStorage(c *gc.C) {
 				},
 			}},
 			{Result: &params.ProvisioningInfo{
-				ControllerConfig: controllerCfg,
-				Series:           "quantal",
-				Constraints:      template.Constraints,
-				Placement:        template.Placement,
-				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				ControllerConfig:   controllerCfg,
+				SSHUserCAPublicKey: sshCAPublicKey,
+				Series:             "quantal",
+				Constraints:        template.Constraints,
+				Placement:          template.Placement,
+				Jobs:               []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
@@ -144,14 +148,17 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithSingleNegativeAndPositi
 	// Dummy provider uses a random port, which is added to cfg used to create environment.
 	apiPort := dummy.APIPort(s.Environ.Provider())
 	controllerCfg["api-port"] = apiPort
+	sshCAPublicKey, err := s.State.SSHCAPublicKey()
+	c.Assert(err, jc.ErrorIsNil)
 	expected := params.ProvisioningInfoResults{
 		Results: []params.ProvisioningInfoResult{{
 			Result: &params.ProvisioningInfo{
-				ControllerConfig: controllerCfg,
-				Series:           "quantal",
-				Constraints:      template.Constraints,
-				Placement:        template.Placement,
-				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				ControllerConfig:   controllerCfg,
+				SSHUserCAPublicKey: sshCAPublicKey,
+				Series:             "quantal",
+				Constraints:        template.Constraints,
+				Placement:          template.Placement,
+				Jobs:               []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
@@ -215,12 +222,15 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithEndpointBindings(c *gc.
 	// Dummy provider uses a random port, which is added to cfg used to create environment.
 	apiPort := dummy.APIPort(s.Environ.Provider())
 	controllerCfg["api-port"] = apiPort
+	sshCAPublicKey, err := s.State.SSHCAPublicKey()
+	c.Assert(err, jc.ErrorIsNil)
 	expected := params.ProvisioningInfoResults{
 		Results: []params.ProvisioningInfoResult{{
 			Result: &params.ProvisioningInfo{
-				ControllerConfig: controllerCfg,
-				Series:           "quantal",
-				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				ControllerConfig:   controllerCfg,
+				SSHUserCAPublicKey: sshCAPublicKey,
+				Series:             "quantal",
+				Jobs:               []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController:    coretesting.ControllerTag.Id(),
 					tags.JujuModel:         coretesting.ModelTag.Id(),
@@ -239,6 +249,41 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithEndpointBindings(c *gc.
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutControllerSuite) TestProvisioningInfoTemplatedResourceTags(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"resource-tags": "owner=${unit} cost=${model}/${application} host=${machine}",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpressMachine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wordpressService := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpressUnit, err := wordpressService.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpressUnit.AssignToMachine(wordpressMachine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: wordpressMachine.Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.Tags, jc.DeepEquals, map[string]string{
+		tags.JujuController:    coretesting.ControllerTag.Id(),
+		tags.JujuModel:         coretesting.ModelTag.Id(),
+		tags.JujuMachine:       "controller-machine-5",
+		tags.JujuUnitsDeployed: wordpressUnit.Name(),
+		"owner":                wordpressUnit.Name(),
+		"cost":                 "controller/wordpress",
+		"host":                 wordpressMachine.Id(),
+	})
+}
+
 func (s *withoutControllerSuite) TestProvisioningInfoWithUnsuitableSpacesConstraints(c *gc.C) {
 	// Add an empty space.
 	_, err := s.State.AddSpace("empty", "", nil, true)
@@ -302,14 +347,17 @@ func (s *withoutControllerSuite) TestStorageProviderFallbackToType(c *gc.C) {
 	// Dummy provider uses a random port, which is added to cfg used to create environment.
 	apiPort := dummy.APIPort(s.Environ.Provider())
 	controllerCfg["api-port"] = apiPort
+	sshCAPublicKey, err := s.State.SSHCAPublicKey()
+	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result, jc.DeepEquals, params.ProvisioningInfoResults{
 		Results: []params.ProvisioningInfoResult{
 			{Result: &params.ProvisioningInfo{
-				ControllerConfig: controllerCfg,
-				Series:           "quantal",
-				Constraints:      template.Constraints,
-				Placement:        template.Placement,
-				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				ControllerConfig:   controllerCfg,
+				SSHUserCAPublicKey: sshCAPublicKey,
+				Series:             "quantal",
+				Constraints:        template.Constraints,
+				Placement:          template.Placement,
+				Jobs:               []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
@@ -450,12 +498,15 @@ func (s *withoutControllerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 	// Dummy provider uses a random port, which is added to cfg used to create environment.
 	apiPort := dummy.APIPort(s.Environ.Provider())
 	controllerCfg["api-port"] = apiPort
+	sshCAPublicKey, err := s.State.SSHCAPublicKey()
+	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, params.ProvisioningInfoResults{
 		Results: []params.ProvisioningInfoResult{
 			{Result: &params.ProvisioningInfo{
-				ControllerConfig: controllerCfg,
-				Series:           "quantal",
-				Jobs:             []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				ControllerConfig:   controllerCfg,
+				SSHUserCAPublicKey: sshCAPublicKey,
+				Series:             "quantal",
+				Jobs:               []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Tags: map[string]string{
 					tags.JujuController: coretesting.ControllerTag.Id(),
 					tags.JujuModel:      coretesting.ModelTag.Id(),
@@ -0,0 +1,238 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// SetSecrets creates or updates secrets owned by the units'
+// applications. Only the leader unit of an application may set its
+// secrets.
+func (u *UniterAPI) SetSecrets(args params.SetSecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		app, err := u.leaderApplication(canAccess, arg.UnitTag)
+		if err == nil {
+			err = app.SetSecret(arg.Name, arg.Values, arg.RotateInterval)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// GetSecrets returns the values of secrets owned by the units'
+// applications, or shared with them by related applications.
+func (u *UniterAPI) GetSecrets(args params.GetSecretArgs) (params.SecretValuesResults, error) {
+	result := params.SecretValuesResults{
+		Results: make([]params.SecretValuesResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SecretValuesResults{}, err
+	}
+	for i, arg := range args.Args {
+		values, err := u.getOneSecret(canAccess, arg)
+		result.Results[i].Values = values
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) getOneSecret(canAccess common.AuthFunc, arg params.GetSecretArg) (map[string]string, error) {
+	unit, err := u.accessibleUnit(canAccess, arg.UnitTag)
+	if err != nil {
+		return nil, err
+	}
+	owner := arg.Application
+	if owner == "" {
+		owner = unit.ApplicationName()
+	}
+	app, err := u.st.Application(owner)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	secret, err := app.Secret(arg.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	readable, err := secret.ReadableBy(unit.ApplicationName())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !readable {
+		return nil, common.ErrPerm
+	}
+	return secret.Values()
+}
+
+// ShareSecrets shares secrets owned by the units' applications with
+// related applications, or stops sharing them. Only the leader unit of
+// an application may share its secrets.
+func (u *UniterAPI) ShareSecrets(args params.ShareSecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		app, err := u.leaderApplication(canAccess, arg.UnitTag)
+		if err == nil {
+			if arg.Revoke {
+				err = app.UnshareSecret(arg.Name, arg.Application)
+			} else {
+				err = app.ShareSecret(arg.Name, arg.Application)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SecretsRotated records that secrets owned by the units' applications
+// have been rotated, so that they are not due for rotation until their
+// rotate interval has passed again.
+func (u *UniterAPI) SecretsRotated(args params.SecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		app, err := u.leaderApplication(canAccess, arg.UnitTag)
+		if err == nil {
+			err = app.SecretRotated(arg.Name)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RemoveSecrets removes secrets owned by the units' applications. Only
+// the leader unit of an application may remove its secrets.
+func (u *UniterAPI) RemoveSecrets(args params.SecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		app, err := u.leaderApplication(canAccess, arg.UnitTag)
+		if err == nil {
+			err = app.RemoveSecret(arg.Name)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchSecretRotations returns a StringsWatcher for each given unit
+// that reports the names of the secrets owned by the unit's
+// application that are due to be rotated.
+func (u *UniterAPI) WatchSecretRotations(args params.Entities) (params.StringsWatchResults, error) {
+	result := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringsWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		unit, err := u.accessibleUnit(canAccess, entity.Tag)
+		if err == nil {
+			result.Results[i], err = u.watchOneSecretRotations(unit)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) watchOneSecretRotations(unit *state.Unit) (params.StringsWatchResult, error) {
+	nothing := params.StringsWatchResult{}
+	app, err := unit.Application()
+	if err != nil {
+		return nothing, errors.Trace(err)
+	}
+	watch := app.WatchSecretRotations()
+	// Consume the initial event and forward it to the result.
+	if changes, ok := <-watch.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: u.resources.Register(watch),
+			Changes:          changes,
+		}, nil
+	}
+	return nothing, watcher.EnsureErr(watch)
+}
+
+// accessibleUnit returns the unit with the given tag, if the
+// authenticated agent may access it.
+func (u *UniterAPI) accessibleUnit(canAccess common.AuthFunc, unitTag string) (*state.Unit, error) {
+	tag, err := names.ParseUnitTag(unitTag)
+	if err != nil || !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	unit, err := u.getUnit(tag)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	}
+	return unit, errors.Trace(err)
+}
+
+// leaderApplication returns the application of the unit with the
+// given tag, if the authenticated agent may access the unit and the
+// unit is its application's leader.
+func (u *UniterAPI) leaderApplication(canAccess common.AuthFunc, unitTag string) (*state.Application, error) {
+	unit, err := u.accessibleUnit(canAccess, unitTag)
+	if err != nil {
+		return nil, err
+	}
+	token := u.st.LeadershipChecker().LeadershipCheck(unit.ApplicationName(), unit.Name())
+	if err := token.Check(nil); err != nil {
+		return nil, errors.Trace(err)
+	}
+	app, err := unit.Application()
+	return app, errors.Trace(err)
+}
+
+// Mask the secrets methods from the v8 API. The API reflection code
+// in rpc/rpcreflect/type.go:newMethod skips 2-argument methods, so
+// this removes the methods as far as the RPC machinery is concerned.
+
+// SetSecrets isn't on the v8 API.
+func (u *UniterAPIV8) SetSecrets(_, _ struct{}) {}
+
+// GetSecrets isn't on the v8 API.
+func (u *UniterAPIV8) GetSecrets(_, _ struct{}) {}
+
+// ShareSecrets isn't on the v8 API.
+func (u *UniterAPIV8) ShareSecrets(_, _ struct{}) {}
+
+// SecretsRotated isn't on the v8 API.
+func (u *UniterAPIV8) SecretsRotated(_, _ struct{}) {}
+
+// RemoveSecrets isn't on the v8 API.
+func (u *UniterAPIV8) RemoveSecrets(_, _ struct{}) {}
+
+// WatchSecretRotations isn't on the v8 API.
+func (u *UniterAPIV8) WatchSecretRotations(_, _ struct{}) {}
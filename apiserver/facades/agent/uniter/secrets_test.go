@@ -0,0 +1,218 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/status"
+)
+
+type uniterSecretsSuite struct {
+	uniterSuiteBase
+}
+
+var _ = gc.Suite(&uniterSecretsSuite{})
+
+func (s *uniterSecretsSuite) claimLeadership(c *gc.C) {
+	err := s.State.LeadershipClaimer().ClaimLeadership("wordpress", "wordpress/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *uniterSecretsSuite) TestSetSecrets(c *gc.C) {
+	args := params.SetSecretArgs{Args: []params.SetSecretArg{
+		{UnitTag: "unit-wordpress-0", Name: "salt", Values: map[string]string{"salt": "pepper"}},
+		{UnitTag: "unit-mysql-0", Name: "root-password", Values: map[string]string{"password": "sekrit"}},
+		{UnitTag: "application-wordpress", Name: "salt", Values: map[string]string{"salt": "pepper"}},
+	}}
+	result, err := s.uniter.SetSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `"wordpress/0" is not leader of "wordpress"`)
+	c.Assert(result.Results[1].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	s.claimLeadership(c)
+	result, err = s.uniter.SetSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+
+	secret, err := s.wordpress.Secret("salt")
+	c.Assert(err, jc.ErrorIsNil)
+	values, err := secret.Values()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{"salt": "pepper"})
+}
+
+func (s *uniterSecretsSuite) TestGetSecrets(c *gc.C) {
+	err := s.wordpress.SetSecret("salt", map[string]string{"salt": "pepper"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetSecret("admin-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addRelation(c, "wordpress", "mysql")
+	err = s.mysql.ShareSecret("root-password", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Reading secrets doesn't require leadership.
+	result, err := s.uniter.GetSecrets(params.GetSecretArgs{Args: []params.GetSecretArg{
+		{UnitTag: "unit-wordpress-0", Name: "salt"},
+		{UnitTag: "unit-wordpress-0", Name: "root-password", Application: "mysql"},
+		{UnitTag: "unit-wordpress-0", Name: "admin-password", Application: "mysql"},
+		{UnitTag: "unit-wordpress-0", Name: "pepper"},
+		{UnitTag: "unit-mysql-0", Name: "root-password"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SecretValuesResults{
+		Results: []params.SecretValuesResult{
+			{Values: map[string]string{"salt": "pepper"}},
+			{Values: map[string]string{"password": "sekrit"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`secret "pepper" of application "wordpress"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSecretsSuite) TestShareSecrets(c *gc.C) {
+	err := s.wordpress.SetSecret("salt", map[string]string{"salt": "pepper"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addRelation(c, "wordpress", "mysql")
+	s.claimLeadership(c)
+
+	result, err := s.uniter.ShareSecrets(params.ShareSecretArgs{Args: []params.ShareSecretArg{
+		{UnitTag: "unit-wordpress-0", Name: "salt", Application: "mysql"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{{}}})
+	secret, err := s.wordpress.Secret("salt")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.SharedWith(), jc.DeepEquals, []string{"mysql"})
+
+	result, err = s.uniter.ShareSecrets(params.ShareSecretArgs{Args: []params.ShareSecretArg{
+		{UnitTag: "unit-wordpress-0", Name: "salt", Application: "mysql", Revoke: true},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{{}}})
+	secret, err = s.wordpress.Secret("salt")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.SharedWith(), gc.HasLen, 0)
+}
+
+func (s *uniterSecretsSuite) TestWatchSecretRotations(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	result, err := s.uniter.WatchSecretRotations(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-mysql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done.
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	wc := statetesting.NewStringsWatcherC(c, s.State, resource.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
+
+func (s *uniterSecretsSuite) TestSecretsRotated(c *gc.C) {
+	err := s.wordpress.SetSecret("salt", map[string]string{"salt": "pepper"}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	s.claimLeadership(c)
+
+	result, err := s.uniter.SecretsRotated(params.SecretArgs{Args: []params.SecretArg{
+		{UnitTag: "unit-wordpress-0", Name: "salt"},
+		{UnitTag: "unit-wordpress-0", Name: "pepper"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{
+		{},
+		{Error: apiservertesting.NotFoundError(`secret "pepper" of application "wordpress"`)},
+	}})
+}
+
+func (s *uniterSecretsSuite) TestRemoveSecrets(c *gc.C) {
+	err := s.wordpress.SetSecret("salt", map[string]string{"salt": "pepper"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.SecretArgs{Args: []params.SecretArg{
+		{UnitTag: "unit-wordpress-0", Name: "salt"},
+		{UnitTag: "unit-mysql-0", Name: "root-password"},
+	}}
+	result, err := s.uniter.RemoveSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `"wordpress/0" is not leader of "wordpress"`)
+	c.Assert(result.Results[1].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	s.claimLeadership(c)
+	result, err = s.uniter.RemoveSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	_, err = s.wordpress.Secret("salt")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *uniterSecretsSuite) TestSetUnitStatusRedactsSecrets(c *gc.C) {
+	err := s.wordpress.SetSecret("salt", map[string]string{"salt": "pepper"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.SetUnitStatus(params.SetStatus{Entities: []params.EntityStatusArgs{
+		{Tag: "unit-wordpress-0", Status: status.Active.String(), Info: "salted with pepper", Data: map[string]interface{}{
+			"seasoning": "pepper",
+			"all":       []interface{}{"salt", "pepper"},
+		}},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{{}}})
+
+	statusInfo, err := s.wordpressUnit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Message, gc.Equals, "salted with [REDACTED]")
+	c.Assert(statusInfo.Data, jc.DeepEquals, map[string]interface{}{
+		"seasoning": "[REDACTED]",
+		"all":       []interface{}{"salt", "[REDACTED]"},
+	})
+}
+
+func (s *uniterSecretsSuite) TestSetUnitStatusRedactFailure(c *gc.C) {
+	err := s.wordpress.SetSecret("salt", map[string]string{"salt": "pepper"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressUnit.SetStatus(status.StatusInfo{Status: status.Maintenance, Message: "seasoning"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A secret that cannot be decrypted fails the status change
+	// rather than letting its values through.
+	secrets := s.State.MongoSession().DB("juju").C("secrets")
+	err = secrets.Update(bson.D{{"application", "wordpress"}}, bson.D{{"$set", bson.D{{"data", []byte("garbage")}}}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.SetUnitStatus(params.SetStatus{Entities: []params.EntityStatusArgs{
+		{Tag: "unit-wordpress-0", Status: status.Active.String(), Info: "salted with pepper"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `cannot redact secrets from unit-wordpress-0 status: .*`)
+
+	statusInfo, err := s.wordpressUnit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Message, gc.Equals, "seasoning")
+}
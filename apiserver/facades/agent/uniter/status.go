@@ -4,6 +4,9 @@
 package uniter
 
 import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
@@ -13,12 +16,14 @@ import (
 // status from different entities, this particular separation from
 // base is because we have a shim to support unit/agent split.
 type StatusAPI struct {
+	st            *state.State
 	agentSetter   *common.StatusSetter
 	unitSetter    *common.StatusSetter
 	unitGetter    *common.StatusGetter
 	serviceSetter *common.ApplicationStatusSetter
 	serviceGetter *common.ApplicationStatusGetter
 	getCanModify  common.GetAuthFunc
+	redactor      *state.SecretRedactor
 }
 
 // NewStatusAPI creates a new server-side Status setter API facade.
@@ -31,12 +36,14 @@ func NewStatusAPI(st *state.State, getCanModify common.GetAuthFunc) *StatusAPI {
 	serviceGetter := common.NewApplicationStatusGetter(st, getCanModify)
 	agentSetter := common.NewStatusSetter(&common.UnitAgentFinder{st}, getCanModify)
 	return &StatusAPI{
+		st:            st,
 		agentSetter:   agentSetter,
 		unitSetter:    unitSetter,
 		unitGetter:    unitGetter,
 		serviceSetter: serviceSetter,
 		serviceGetter: serviceGetter,
 		getCanModify:  getCanModify,
+		redactor:      st.NewSecretRedactor(),
 	}
 }
 
@@ -57,13 +64,111 @@ func (s *StatusAPI) SetAgentStatus(args params.SetStatus) (params.ErrorResults,
 // with SetStatus is that if an entity is a Unit it will set its status instead
 // of its agent.
 func (s *StatusAPI) SetUnitStatus(args params.SetStatus) (params.ErrorResults, error) {
-	return s.unitSetter.SetStatus(args)
+	return s.setRedactedStatus(args, s.unitSetter.SetStatus)
 }
 
 // SetApplicationStatus sets the status for all the Services in args if the given Unit is
 // the leader.
 func (s *StatusAPI) SetApplicationStatus(args params.SetStatus) (params.ErrorResults, error) {
-	return s.serviceSetter.SetStatus(args)
+	return s.setRedactedStatus(args, s.serviceSetter.SetStatus)
+}
+
+// setRedactedStatus sets the status of the entities in args with any
+// secret values owned by each entity's application removed from the
+// status messages and data, so that charms cannot leak secrets into
+// status output by accident. The status of an entity whose secrets
+// cannot be redacted is not set.
+func (s *StatusAPI) setRedactedStatus(
+	args params.SetStatus,
+	setStatus func(params.SetStatus) (params.ErrorResults, error),
+) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	var redacted params.SetStatus
+	var indexes []int
+	for i, arg := range args.Entities {
+		arg, err := s.redactSecrets(arg)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		redacted.Entities = append(redacted.Entities, arg)
+		indexes = append(indexes, i)
+	}
+	if len(redacted.Entities) == 0 {
+		return result, nil
+	}
+	setResults, err := setStatus(redacted)
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for i, setResult := range setResults.Results {
+		result.Results[indexes[i]] = setResult
+	}
+	return result, nil
+}
+
+// redactSecrets returns a copy of arg with the values of the secrets
+// owned by the entity's application removed.
+func (s *StatusAPI) redactSecrets(arg params.EntityStatusArgs) (params.EntityStatusArgs, error) {
+	if arg.Info == "" && len(arg.Data) == 0 {
+		return arg, nil
+	}
+	// Bad tags are reported by the status setters.
+	tag, err := names.ParseTag(arg.Tag)
+	if err != nil {
+		return arg, nil
+	}
+	var appName string
+	switch tag := tag.(type) {
+	case names.UnitTag:
+		appName, _ = names.UnitApplication(tag.Id())
+	case names.ApplicationTag:
+		appName = tag.Id()
+	default:
+		return arg, nil
+	}
+	values, err := s.redactor.Values(appName)
+	if err != nil {
+		return arg, errors.Annotatef(err, "cannot redact secrets from %s status", arg.Tag)
+	}
+	if len(values) == 0 {
+		return arg, nil
+	}
+	arg.Info = state.RedactSecretValues(values, arg.Info)
+	if arg.Data != nil {
+		arg.Data = redactSecretData(values, arg.Data).(map[string]interface{})
+	}
+	return arg, nil
+}
+
+// redactSecretData returns a copy of data with the given secret
+// values removed from all the strings it holds.
+func redactSecretData(values []string, data interface{}) interface{} {
+	switch data := data.(type) {
+	case string:
+		return state.RedactSecretValues(values, data)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			result[state.RedactSecretValues(values, k)] = redactSecretData(values, v)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(data))
+		for i, v := range data {
+			result[i] = redactSecretData(values, v)
+		}
+		return result
+	case []string:
+		result := make([]string, len(data))
+		for i, v := range data {
+			result[i] = state.RedactSecretValues(values, v)
+		}
+		return result
+	}
+	return data
 }
 
 // UnitStatus returns the workload status information for the unit.
@@ -35,7 +35,7 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v8) of the Uniter API.
+// UniterAPI implements the latest version (v9) of the Uniter API.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -62,9 +62,14 @@ type UniterAPI struct {
 	cloudSpec       cloudspec.CloudSpecAPI
 }
 
+// UniterAPIV8 doesn't have the secrets methods.
+type UniterAPIV8 struct {
+	UniterAPI
+}
+
 // UniterAPIV7 adds CMR support to NetworkInfo.
 type UniterAPIV7 struct {
-	UniterAPI
+	UniterAPIV8
 }
 
 // UniterAPIV6 adds NetworkInfo as a preferred method to calling NetworkConfig.
@@ -248,14 +253,25 @@ func NewUniterAPI(st *state.State, resources facade.Resources, authorizer facade
 	}, nil
 }
 
+// NewUniterAPIV8 creates an instance of the V8 uniter API.
+func NewUniterAPIV8(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV8, error) {
+	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV8{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV7 creates an instance of the V7 uniter API.
 func NewUniterAPIV7(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV7, error) {
-	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	uniterAPI, err := NewUniterAPIV8(st, resources, authorizer)
 	if err != nil {
 		return nil, err
 	}
 	return &UniterAPIV7{
-		UniterAPI: *uniterAPI,
+		UniterAPIV8: *uniterAPI,
 	}, nil
 }
 
@@ -65,6 +65,32 @@ func (a *ActionAPI) checkCanWrite() error {
 	return nil
 }
 
+// checkCanOperate returns an error unless the user has write access to
+// the model, or has been granted operate access on the application of
+// the given action receiver.
+func (a *ActionAPI) checkCanOperate(receiver string) error {
+	unitTag, err := names.ParseUnitTag(receiver)
+	if err != nil {
+		return a.checkCanWrite()
+	}
+	appName, err := names.UnitApplication(unitTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return a.checkCanOperateApplication(appName)
+}
+
+func (a *ActionAPI) checkCanOperateApplication(appName string) error {
+	allowed, err := common.HasApplicationPermission(a.authorizer, a.model.ModelTag(), appName, permission.OperateAccess)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
 func (a *ActionAPI) checkCanAdmin() error {
 	canAdmin, err := a.authorizer.HasPermission(permission.AdminAccess, a.model.ModelTag())
 	if err != nil {
@@ -176,7 +202,7 @@ func (a *ActionAPI) FindActionsByNames(arg params.FindActionsByNames) (params.Ac
 // enqueued Action, or an error if there was a problem enqueueing the
 // Action.
 func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
-	if err := a.checkCanWrite(); err != nil {
+	if err := a.checkCanRead(); err != nil {
 		return params.ActionResults{}, errors.Trace(err)
 	}
 
@@ -188,6 +214,10 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
 	for i, action := range arg.Actions {
 		currentResult := &response.Results[i]
+		if err := a.checkCanOperate(action.Receiver); err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
 		receiver, err := tagToActionReceiver(action.Receiver)
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
@@ -304,7 +334,7 @@ func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 // services.
 func (a *ActionAPI) ApplicationsCharmsActions(args params.Entities) (params.ApplicationsCharmActionsResults, error) {
 	result := params.ApplicationsCharmActionsResults{Results: make([]params.ApplicationCharmActionsResult, len(args.Entities))}
-	if err := a.checkCanWrite(); err != nil {
+	if err := a.checkCanRead(); err != nil {
 		return result, errors.Trace(err)
 	}
 
@@ -316,6 +346,10 @@ func (a *ActionAPI) ApplicationsCharmsActions(args params.Entities) (params.Appl
 			continue
 		}
 		currentResult.ApplicationTag = svcTag.String()
+		if err := a.checkCanOperateApplication(svcTag.Id()); err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
 		svc, err := a.state.Application(svcTag.Id())
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	jujuFactory "github.com/juju/juju/testing/factory"
@@ -764,3 +765,43 @@ func stringify(r params.ActionResult) string {
 	}
 	return fmt.Sprintf("%s-%s-%#v-%s-%s-%#v", a.Tag, a.Name, a.Parameters, r.Status, r.Message, r.Output)
 }
+
+// applicationAccessAuthorizer grants a user read access to the model
+// and operate access to the named application.
+type applicationAccessAuthorizer struct {
+	apiservertesting.FakeAuthorizer
+	application string
+}
+
+func (a applicationAccessAuthorizer) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
+	switch target.Kind() {
+	case names.ModelTagKind:
+		return operation == permission.ReadAccess, nil
+	case names.ApplicationTagKind:
+		return target.Id() == a.application && operation == permission.OperateAccess, nil
+	}
+	return false, nil
+}
+
+func (s *actionSuite) TestEnqueueWithApplicationAccess(c *gc.C) {
+	api, err := action.NewActionAPI(s.State, nil, applicationAccessAuthorizer{
+		FakeAuthorizer: apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("fred")},
+		application:    "wordpress",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := api.Enqueue(params.Actions{
+		Actions: []params.Action{
+			{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction"},
+			{Receiver: s.mysqlUnit.Tag().String(), Name: "fakeaction"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 2)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[1].Error, gc.ErrorMatches, "permission denied")
+
+	actions, err := s.mysqlUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
@@ -51,6 +51,11 @@ type APIv6 struct {
 	*APIv5
 }
 
+// APIv7 provides the Application API facade for version 7.
+type APIv7 struct {
+	*APIv6
+}
+
 // API implements the application interface and is the concrete
 // implementation of the api end point.
 //
@@ -90,6 +95,16 @@ func NewFacadeV6(ctx facade.Context) (*APIv6, error) {
 	return &APIv6{apiV5}, nil
 }
 
+// NewFacadeV7 provides the signature required for facade registration
+// for version 7.
+func NewFacadeV7(ctx facade.Context) (*APIv7, error) {
+	apiV6, err := NewFacadeV6(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv7{apiV6}, nil
+}
+
 // NewFacade provides the signature required for facade registration.
 func NewFacadeV5(ctx facade.Context) (*APIv5, error) {
 	backend, err := NewStateBackend(ctx.State())
@@ -138,17 +153,60 @@ func (api *APIv5) checkPermission(tag names.Tag, perm permission.Access) error {
 	return nil
 }
 
+// checkCanRead returns an error unless the user has read access to the
+// model, or has been granted access to at least one of its
+// applications. Callers must check access to each application they
+// go on to use.
 func (api *APIv5) checkCanRead() error {
-	return api.checkPermission(api.backend.ModelTag(), permission.ReadAccess)
+	err := api.checkPermission(api.backend.ModelTag(), permission.ReadAccess)
+	if err != common.ErrPerm {
+		return err
+	}
+	user, ok := api.authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return err
+	}
+	hasAppAccess, appErr := api.backend.HasApplicationAccess(user)
+	if appErr != nil {
+		return errors.Trace(appErr)
+	}
+	if !hasAppAccess {
+		return err
+	}
+	return nil
 }
 
 func (api *APIv5) checkCanWrite() error {
 	return api.checkPermission(api.backend.ModelTag(), permission.WriteAccess)
 }
 
+// checkApplicationAccess returns an error unless the user has access to
+// the model allowing the given access to all of its applications, or
+// has been granted at least the given access on the named application.
+func (api *APIv5) checkApplicationAccess(appName string, perm permission.Access) error {
+	allowed, err := common.HasApplicationPermission(api.authorizer, api.backend.ModelTag(), appName, perm)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// checkUnitAccess returns an error unless the user may act on the
+// application of the given unit with the given access.
+func (api *APIv5) checkUnitAccess(unitName string, perm permission.Access) error {
+	appName, err := names.UnitApplication(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return api.checkApplicationAccess(appName, perm)
+}
+
 // SetMetricCredentials sets credentials on the application.
 func (api *APIv5) SetMetricCredentials(args params.ApplicationMetricCredentials) (params.ErrorResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanRead(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
 	result := params.ErrorResults{
@@ -158,6 +216,10 @@ func (api *APIv5) SetMetricCredentials(args params.ApplicationMetricCredentials)
 		return result, nil
 	}
 	for i, a := range args.Creds {
+		if err := api.checkApplicationAccess(a.ApplicationName, permission.OperateAccess); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
 		application, err := api.backend.Application(a.ApplicationName)
 		if err != nil {
 			result.Results[i].Error = common.ServerError(err)
@@ -416,7 +478,12 @@ func parseSettingsCompatible(charmConfig *charm.Config, settings map[string]stri
 // minimum number of units, charm config and constraints.
 // All parameters in params.ApplicationUpdate except the application name are optional.
 func (api *APIv5) Update(args params.ApplicationUpdate) error {
-	if err := api.checkCanWrite(); err != nil {
+	// Changing the charm requires admin access to the application.
+	access := permission.OperateAccess
+	if args.CharmURL != "" {
+		access = permission.AdminAccess
+	}
+	if err := api.checkApplicationAccess(args.ApplicationName, access); err != nil {
 		return err
 	}
 	if !args.ForceCharmURL {
@@ -474,7 +541,7 @@ func (api *APIv5) Update(args params.ApplicationUpdate) error {
 // UpdateApplicationSeries updates the application series. Series for
 // subordinates updated too.
 func (api *APIv5) UpdateApplicationSeries(args params.UpdateSeriesArgs) (params.ErrorResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanRead(); err != nil {
 		return params.ErrorResults{}, err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -501,6 +568,9 @@ func (api *APIv5) updateOneApplicationSeries(arg params.UpdateSeriesArg) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err := api.checkApplicationAccess(applicationTag.Id(), permission.AdminAccess); err != nil {
+		return err
+	}
 	app, err := api.backend.Application(applicationTag.Id())
 	if err != nil {
 		return errors.Trace(err)
@@ -519,7 +589,7 @@ func (api *APIv5) updateOneApplicationSeries(arg params.UpdateSeriesArg) error {
 
 // SetCharm sets the charm for a given for the application.
 func (api *APIv5) SetCharm(args params.ApplicationSetCharm) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkApplicationAccess(args.ApplicationName, permission.AdminAccess); err != nil {
 		return err
 	}
 	// when forced units in error, don't block
@@ -571,6 +641,9 @@ func (api *APIv5) getCharmConfig(entity string) (map[string]interface{}, error)
 	}
 	switch kind := tag.Kind(); kind {
 	case names.ApplicationTagKind:
+		if err := api.checkApplicationAccess(tag.Id(), permission.ReadAccess); err != nil {
+			return nil, err
+		}
 		app, err := api.backend.Application(tag.Id())
 		if err != nil {
 			return nil, err
@@ -705,7 +778,7 @@ func applicationSetCharmConfigYAML(appName string, application Application, sett
 // GetCharmURL returns the charm URL the given application is
 // running at present.
 func (api *APIv5) GetCharmURL(args params.ApplicationGet) (params.StringResult, error) {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkApplicationAccess(args.ApplicationName, permission.ReadAccess); err != nil {
 		return params.StringResult{}, errors.Trace(err)
 	}
 	application, err := api.backend.Application(args.ApplicationName)
@@ -720,7 +793,7 @@ func (api *APIv5) GetCharmURL(args params.ApplicationGet) (params.StringResult,
 // It does not unset values that are set to an empty string.
 // Unset should be used for that.
 func (api *APIv5) Set(p params.ApplicationSet) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkApplicationAccess(p.ApplicationName, permission.OperateAccess); err != nil {
 		return err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -746,7 +819,7 @@ func (api *APIv5) Set(p params.ApplicationSet) error {
 
 // Unset implements the server side of Client.Unset.
 func (api *APIv5) Unset(p params.ApplicationUnset) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkApplicationAccess(p.ApplicationName, permission.OperateAccess); err != nil {
 		return err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -766,7 +839,7 @@ func (api *APIv5) Unset(p params.ApplicationUnset) error {
 // CharmRelations implements the server side of Application.CharmRelations.
 func (api *APIv5) CharmRelations(p params.ApplicationCharmRelations) (params.ApplicationCharmRelationsResults, error) {
 	var results params.ApplicationCharmRelationsResults
-	if err := api.checkCanRead(); err != nil {
+	if err := api.checkApplicationAccess(p.ApplicationName, permission.ReadAccess); err != nil {
 		return results, errors.Trace(err)
 	}
 
@@ -788,7 +861,7 @@ func (api *APIv5) CharmRelations(p params.ApplicationCharmRelations) (params.App
 // Expose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (api *APIv5) Expose(args params.ApplicationExpose) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkApplicationAccess(args.ApplicationName, permission.OperateAccess); err != nil {
 		return errors.Trace(err)
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -815,7 +888,7 @@ func (api *APIv5) Expose(args params.ApplicationExpose) error {
 // Unexpose changes the juju-managed firewall to unexpose any ports that
 // were also explicitly marked by units as open.
 func (api *APIv5) Unexpose(args params.ApplicationUnexpose) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkApplicationAccess(args.ApplicationName, permission.OperateAccess); err != nil {
 		return err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -830,7 +903,10 @@ func (api *APIv5) Unexpose(args params.ApplicationUnexpose) error {
 
 // AddUnits adds a given number of units to an application.
 func (api *APIv5) AddUnits(args params.AddApplicationUnits) (params.AddApplicationUnitsResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkApplicationAccess(args.ApplicationName, permission.OperateAccess); err != nil {
+		return params.AddApplicationUnitsResults{}, errors.Trace(err)
+	}
+	if err := api.checkPlacementAccess(args.Placement); err != nil {
 		return params.AddApplicationUnitsResults{}, errors.Trace(err)
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -847,6 +923,45 @@ func (api *APIv5) AddUnits(args params.AddApplicationUnits) (params.AddApplicati
 	return params.AddApplicationUnitsResults{Units: unitNames}, nil
 }
 
+// checkPlacementAccess returns an error if the user may not place units
+// on the existing machines named by the given placement directives.
+// Without write access to the model, a user may only place units on, or
+// in new containers on, machines whose principal units all belong to
+// applications the user may operate, so that an application grant does
+// not give access to machines used by other applications.
+func (api *APIv5) checkPlacementAccess(placements []*instance.Placement) error {
+	canWrite, err := api.authorizer.HasPermission(permission.WriteAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if canWrite {
+		return nil
+	}
+	for _, p := range placements {
+		if p == nil || p.Directive == "" {
+			continue
+		}
+		if _, err := instance.ParseContainerType(p.Scope); err != nil && p.Scope != instance.MachineScope {
+			// Provider placement, which always creates a new machine.
+			continue
+		}
+		machine, err := api.backend.Machine(p.Directive)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		principals := machine.Principals()
+		if len(principals) == 0 {
+			return errors.Trace(common.ErrPerm)
+		}
+		for _, unitName := range principals {
+			if err := api.checkUnitAccess(unitName, permission.OperateAccess); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
 // addApplicationUnits adds a given number of units to an application.
 func addApplicationUnits(backend Backend, args params.AddApplicationUnits) ([]Unit, error) {
 	if args.NumUnits < 1 {
@@ -949,7 +1064,7 @@ func (api *APIv4) DestroyUnit(args params.Entities) (params.DestroyUnitResults,
 
 // DestroyUnit removes a given set of application units.
 func (api *APIv5) DestroyUnit(args params.DestroyUnitsParams) (params.DestroyUnitResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanRead(); err != nil {
 		return params.DestroyUnitResults{}, errors.Trace(err)
 	}
 	if err := api.check.RemoveAllowed(); err != nil {
@@ -961,6 +1076,9 @@ func (api *APIv5) DestroyUnit(args params.DestroyUnitsParams) (params.DestroyUni
 			return nil, errors.Trace(err)
 		}
 		name := unitTag.Id()
+		if err := api.checkUnitAccess(name, permission.OperateAccess); err != nil {
+			return nil, err
+		}
 		unit, err := api.backend.Unit(name)
 		if errors.IsNotFound(err) {
 			return nil, errors.Errorf("unit %q does not exist", name)
@@ -1055,7 +1173,7 @@ func (api *APIv4) DestroyApplication(args params.Entities) (params.DestroyApplic
 
 // DestroyApplication removes a given set of applications.
 func (api *APIv5) DestroyApplication(args params.DestroyApplicationsParams) (params.DestroyApplicationResults, error) {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanRead(); err != nil {
 		return params.DestroyApplicationResults{}, err
 	}
 	if err := api.check.RemoveAllowed(); err != nil {
@@ -1066,6 +1184,9 @@ func (api *APIv5) DestroyApplication(args params.DestroyApplicationsParams) (par
 		if err != nil {
 			return nil, err
 		}
+		if err := api.checkApplicationAccess(tag.Id(), permission.AdminAccess); err != nil {
+			return nil, err
+		}
 		var info params.DestroyApplicationInfo
 		app, err := api.backend.Application(tag.Id())
 		if err != nil {
@@ -1193,6 +1314,9 @@ func (api *APIv5) getConstraints(entity string) (constraints.Value, error) {
 	}
 	switch kind := tag.Kind(); kind {
 	case names.ApplicationTagKind:
+		if err := api.checkApplicationAccess(tag.Id(), permission.ReadAccess); err != nil {
+			return constraints.Value{}, err
+		}
 		app, err := api.backend.Application(tag.Id())
 		if err != nil {
 			return constraints.Value{}, err
@@ -1205,7 +1329,7 @@ func (api *APIv5) getConstraints(entity string) (constraints.Value, error) {
 
 // SetConstraints sets the constraints for a given application.
 func (api *APIv5) SetConstraints(args params.SetConstraints) error {
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkApplicationAccess(args.ApplicationName, permission.OperateAccess); err != nil {
 		return err
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -1543,7 +1667,7 @@ func (u *APIv4) GetConfig(_, _ struct{}) {}
 
 // GetConstraints returns the v4 implementation of GetConstraints.
 func (api *APIv4) GetConstraints(args params.GetApplicationConstraints) (params.GetConstraintsResults, error) {
-	if err := api.checkCanRead(); err != nil {
+	if err := api.checkApplicationAccess(args.ApplicationName, permission.ReadAccess); err != nil {
 		return params.GetConstraintsResults{}, errors.Trace(err)
 	}
 	app, err := api.backend.Application(args.ApplicationName)
@@ -1564,7 +1688,7 @@ func (api *APIv6) CharmConfig(args params.Entities) (params.ApplicationGetConfig
 // Unset should be used for that.
 func (api *APIv6) SetApplicationsConfig(args params.ApplicationConfigSetArgs) (params.ErrorResults, error) {
 	var result params.ErrorResults
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanRead(); err != nil {
 		return result, errors.Trace(err)
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -1579,6 +1703,9 @@ func (api *APIv6) SetApplicationsConfig(args params.ApplicationConfigSetArgs) (p
 }
 
 func (api *APIv6) setApplicationConfig(arg params.ApplicationConfigSet) error {
+	if err := api.checkApplicationAccess(arg.ApplicationName, permission.OperateAccess); err != nil {
+		return err
+	}
 	app, err := api.backend.Application(arg.ApplicationName)
 	if err != nil {
 		return errors.Trace(err)
@@ -1618,7 +1745,7 @@ func (api *APIv6) setApplicationConfig(arg params.ApplicationConfigSet) error {
 // UnsetApplicationsConfig implements the server side of Application.UnsetApplicationsConfig.
 func (api *APIv6) UnsetApplicationsConfig(args params.ApplicationConfigUnsetArgs) (params.ErrorResults, error) {
 	var result params.ErrorResults
-	if err := api.checkCanWrite(); err != nil {
+	if err := api.checkCanRead(); err != nil {
 		return result, errors.Trace(err)
 	}
 	if err := api.check.ChangeAllowed(); err != nil {
@@ -1633,6 +1760,9 @@ func (api *APIv6) UnsetApplicationsConfig(args params.ApplicationConfigUnsetArgs
 }
 
 func (api *APIv6) unsetApplicationConfig(arg params.ApplicationUnset) error {
+	if err := api.checkApplicationAccess(arg.ApplicationName, permission.OperateAccess); err != nil {
+		return err
+	}
 	app, err := api.backend.Application(arg.ApplicationName)
 	if err != nil {
 		return errors.Trace(err)
@@ -1666,3 +1796,52 @@ func (api *APIv6) unsetApplicationConfig(arg params.ApplicationUnset) error {
 	}
 	return nil
 }
+
+// ModifyApplicationAccess grants or revokes users' read, operate or
+// admin access to applications in the model. Only model admins may
+// change application access.
+func (api *APIv7) ModifyApplicationAccess(args params.ModifyApplicationAccessRequest) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !isAdmin {
+		if err := api.checkPermission(api.backend.ModelTag(), permission.AdminAccess); err != nil {
+			return result, errors.Trace(err)
+		}
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		err := api.modifyOneApplicationAccess(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *APIv7) modifyOneApplicationAccess(arg params.ModifyApplicationAccess) error {
+	appTag, err := names.ParseApplicationTag(arg.ApplicationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	userTag, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	access := permission.Access(arg.Access)
+	switch arg.Action {
+	case params.GrantModelAccess:
+		if err := permission.ValidateApplicationAccess(access); err != nil {
+			return errors.Annotate(err, "could not modify application access")
+		}
+		return api.backend.SetApplicationAccess(appTag.Id(), userTag, access)
+	case params.RevokeModelAccess:
+		return api.backend.RemoveApplicationAccess(appTag.Id(), userTag)
+	default:
+		return errors.Errorf("unknown action %q", arg.Action)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
 	coretesting "github.com/juju/juju/testing"
@@ -1198,3 +1199,184 @@ func (s *ApplicationSuite) TestCAASExposeWithHostname(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	app.CheckCallNames(c, "ApplicationConfig", "SetExposed")
 }
+
+// accessAuthorizer grants a user access to the model and to individual
+// applications as given.
+type accessAuthorizer struct {
+	apiservertesting.FakeAuthorizer
+	access map[names.Tag]permission.Access
+}
+
+func (a accessAuthorizer) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
+	access := a.access[target]
+	if target.Kind() == names.ApplicationTagKind {
+		return access.EqualOrGreaterApplicationAccessThan(operation), nil
+	}
+	return access.EqualOrGreaterModelAccessThan(operation), nil
+}
+
+func (s *ApplicationSuite) setAPIAccess(c *gc.C, access map[names.Tag]permission.Access) {
+	authorizer := accessAuthorizer{
+		FakeAuthorizer: apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("fred")},
+		access:         access,
+	}
+	api, err := application.NewAPIV5(
+		&s.backend,
+		authorizer,
+		&s.blockChecker,
+		func(application.Charm) *state.Charm {
+			return &state.Charm{}
+		},
+		func(application.ApplicationDeployer, application.DeployApplicationParams) (application.Application, error) {
+			return nil, nil
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = &application.APIv6{api}
+}
+
+func (s *ApplicationSuite) TestApplicationOperateAccess(c *gc.C) {
+	s.setAPIAccess(c, map[names.Tag]permission.Access{
+		s.backend.ModelTag():                  permission.ReadAccess,
+		names.NewApplicationTag("postgresql"): permission.OperateAccess,
+	})
+	err := s.api.Set(params.ApplicationSet{
+		ApplicationName: "postgresql",
+		Options:         map[string]string{"stringOption": "value"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Operate access does not allow the application to be destroyed.
+	results, err := s.api.DestroyApplication(params.DestroyApplicationsParams{
+		Applications: []params.DestroyApplicationParams{{
+			ApplicationTag: "application-postgresql",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ApplicationSuite) TestApplicationReadAccess(c *gc.C) {
+	// A user with no access to the model may use the applications
+	// they have been granted access to.
+	s.setAPIAccess(c, map[names.Tag]permission.Access{
+		names.NewApplicationTag("postgresql"): permission.ReadAccess,
+	})
+	s.backend.hasApplicationAccess = map[names.UserTag]bool{names.NewUserTag("fred"): true}
+	_, err := s.api.CharmRelations(params.ApplicationCharmRelations{ApplicationName: "postgresql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.api.CharmRelations(params.ApplicationCharmRelations{ApplicationName: "postgresql-subordinate"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	// Read access does not allow the application to be changed.
+	err = s.api.Set(params.ApplicationSet{
+		ApplicationName: "postgresql",
+		Options:         map[string]string{"stringOption": "value"},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	results, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
+		Args: []params.ApplicationConfigSet{{
+			ApplicationName: "postgresql",
+			Config:          map[string]string{"stringOption": "value"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ApplicationSuite) TestApplicationAccessIsPerApplication(c *gc.C) {
+	s.setAPIAccess(c, map[names.Tag]permission.Access{
+		s.backend.ModelTag():                  permission.ReadAccess,
+		names.NewApplicationTag("postgresql"): permission.AdminAccess,
+	})
+	results, err := s.api.DestroyUnit(params.DestroyUnitsParams{
+		Units: []params.DestroyUnitParams{
+			{UnitTag: "unit-postgresql-0"},
+			{UnitTag: "unit-postgresql-subordinate-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+
+	err = s.api.Unexpose(params.ApplicationUnexpose{ApplicationName: "postgresql-subordinate"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ApplicationSuite) TestAddUnitsPlacementAccess(c *gc.C) {
+	s.backend.machines = map[string]*mockMachine{
+		"0": {principals: []string{"postgresql/0"}},
+		"1": {principals: []string{"postgresql/1", "postgresql-subordinate/0"}},
+		"2": {},
+	}
+	s.setAPIAccess(c, map[names.Tag]permission.Access{
+		s.backend.ModelTag():                  permission.ReadAccess,
+		names.NewApplicationTag("postgresql"): permission.OperateAccess,
+	})
+	addUnit := func(placement string) error {
+		_, err := s.api.AddUnits(params.AddApplicationUnits{
+			ApplicationName: "postgresql",
+			NumUnits:        1,
+			Placement:       []*instance.Placement{instance.MustParsePlacement(placement)},
+		})
+		return err
+	}
+	c.Assert(addUnit("0"), jc.ErrorIsNil)
+	c.Assert(addUnit("lxd:0"), jc.ErrorIsNil)
+	c.Assert(addUnit("lxd"), jc.ErrorIsNil)
+	c.Assert(addUnit("zone=az1"), jc.ErrorIsNil)
+
+	// Machines hosting units of other applications, and machines
+	// without units, are not available.
+	c.Assert(addUnit("1"), gc.ErrorMatches, "permission denied")
+	c.Assert(addUnit("lxd:1"), gc.ErrorMatches, "permission denied")
+	c.Assert(addUnit("2"), gc.ErrorMatches, "permission denied")
+}
+
+func (s *ApplicationSuite) TestModifyApplicationAccess(c *gc.C) {
+	api := &application.APIv7{s.api}
+	results, err := api.ModifyApplicationAccess(params.ModifyApplicationAccessRequest{
+		Changes: []params.ModifyApplicationAccess{{
+			UserTag:        "user-bob",
+			Action:         params.GrantModelAccess,
+			Access:         "operate",
+			ApplicationTag: "application-postgresql",
+		}, {
+			UserTag:        "user-bob",
+			Action:         params.RevokeModelAccess,
+			ApplicationTag: "application-postgresql",
+		}, {
+			UserTag:        "user-bob",
+			Action:         params.GrantModelAccess,
+			Access:         "write",
+			ApplicationTag: "application-postgresql",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `could not modify application access: "write" application access not valid`)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"SetApplicationAccess", []interface{}{"postgresql", names.NewUserTag("bob"), permission.OperateAccess}},
+		{"RemoveApplicationAccess", []interface{}{"postgresql", names.NewUserTag("bob")}},
+	})
+}
+
+func (s *ApplicationSuite) TestModifyApplicationAccessRequiresModelAdmin(c *gc.C) {
+	s.setAPIAccess(c, map[names.Tag]permission.Access{
+		s.backend.ModelTag():                  permission.WriteAccess,
+		names.NewApplicationTag("postgresql"): permission.AdminAccess,
+	})
+	api := &application.APIv7{s.api}
+	_, err := api.ModifyApplicationAccess(params.ModifyApplicationAccessRequest{
+		Changes: []params.ModifyApplicationAccess{{
+			UserTag:        "user-bob",
+			Action:         params.GrantModelAccess,
+			Access:         "operate",
+			ApplicationTag: "application-postgresql",
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckNoCalls(c)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
 )
@@ -41,6 +42,9 @@ type Backend interface {
 	Machine(string) (Machine, error)
 	ModelTag() names.ModelTag
 	ModelType() state.ModelType
+	SetApplicationAccess(string, names.UserTag, permission.Access) error
+	HasApplicationAccess(names.UserTag) (bool, error)
+	RemoveApplicationAccess(string, names.UserTag) error
 	Unit(string) (Unit, error)
 	SaveController(info crossmodel.ControllerInfo, modelUUID string) (ExternalController, error)
 	ControllerTag() names.ControllerTag
@@ -99,6 +103,7 @@ type Charm interface {
 // details on the methods, see the methods on state.Machine with
 // the same names.
 type Machine interface {
+	Principals() []string
 }
 
 // Relation defines a subset of the functionality provided by the
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/permission"
 )
 
 // Get returns the charm configuration for an application.
@@ -47,7 +48,7 @@ func (api *APIv5) getConfig(
 	args params.ApplicationGet,
 	describe func(settings charm.Settings, config *charm.Config) map[string]interface{},
 ) (params.ApplicationGetResults, error) {
-	if err := api.checkCanRead(); err != nil {
+	if err := api.checkApplicationAccess(args.ApplicationName, permission.ReadAccess); err != nil {
 		return params.ApplicationGetResults{}, err
 	}
 	app, err := api.backend.Application(args.ApplicationName)
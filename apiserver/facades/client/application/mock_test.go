@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/status"
@@ -297,6 +298,8 @@ type mockBackend struct {
 	storageInstances           map[string]*mockStorage
 	storageInstanceFilesystems map[string]*mockFilesystem
 	controllers                map[string]crossmodel.ControllerInfo
+	machines                   map[string]*mockMachine
+	hasApplicationAccess       map[names.UserTag]bool
 }
 
 func (m *mockBackend) ControllerTag() names.ControllerTag {
@@ -340,6 +343,26 @@ func (m *mockBackend) Unit(name string) (application.Unit, error) {
 	return nil, errors.NotFoundf("unit %q", name)
 }
 
+func (m *mockBackend) Machine(id string) (application.Machine, error) {
+	m.MethodCall(m, "Machine", id)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	machine, ok := m.machines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %q", id)
+	}
+	return machine, nil
+}
+
+type mockMachine struct {
+	principals []string
+}
+
+func (m *mockMachine) Principals() []string {
+	return m.principals
+}
+
 func (m *mockBackend) InferEndpoints(endpoints ...string) ([]state.Endpoint, error) {
 	m.MethodCall(m, "InferEndpoints", endpoints)
 	if err := m.NextErr(); err != nil {
@@ -718,3 +741,18 @@ func (s *recordingStorage) Remove(path string) error {
 	s.blobs.Remove(path)
 	return nil
 }
+
+func (m *mockBackend) SetApplicationAccess(appName string, user names.UserTag, access permission.Access) error {
+	m.MethodCall(m, "SetApplicationAccess", appName, user, access)
+	return m.NextErr()
+}
+
+func (m *mockBackend) RemoveApplicationAccess(appName string, user names.UserTag) error {
+	m.MethodCall(m, "RemoveApplicationAccess", appName, user)
+	return m.NextErr()
+}
+
+func (m *mockBackend) HasApplicationAccess(user names.UserTag) (bool, error) {
+	m.MethodCall(m, "HasApplicationAccess", user)
+	return m.hasApplicationAccess[user], m.NextErr()
+}
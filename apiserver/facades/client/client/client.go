@@ -93,6 +93,33 @@ func (c *Client) checkCanWrite() error {
 	return nil
 }
 
+// checkUnitAccess checks that the user has the given access to the
+// application of the named unit, either through their access to the
+// model or through access granted on the application itself.
+func (c *Client) checkUnitAccess(unitName string, access permission.Access) error {
+	checkModelAccess := c.checkCanWrite
+	if access == permission.ReadAccess {
+		checkModelAccess = c.checkCanRead
+	}
+	if err := checkModelAccess(); err == nil {
+		return nil
+	}
+	appName, err := names.UnitApplication(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	allowed, err := common.HasApplicationPermission(
+		c.api.auth, c.api.stateAccessor.ModelTag(), appName, access,
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return common.ErrPerm
+	}
+	return nil
+}
+
 func (c *Client) checkIsAdmin() error {
 	isAdmin, err := c.api.auth.HasPermission(permission.SuperuserAccess, c.api.stateAccessor.ControllerTag())
 	if err != nil {
@@ -204,7 +231,7 @@ func (c *Client) WatchAll() (params.AllWatcherId, error) {
 
 // Resolved implements the server side of Client.Resolved.
 func (c *Client) Resolved(p params.Resolved) error {
-	if err := c.checkCanWrite(); err != nil {
+	if err := c.checkUnitAccess(p.UnitName, permission.OperateAccess); err != nil {
 		return err
 	}
 	if err := c.check.ChangeAllowed(); err != nil {
@@ -219,12 +246,11 @@ func (c *Client) Resolved(p params.Resolved) error {
 
 // PublicAddress implements the server side of Client.PublicAddress.
 func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAddressResults, err error) {
-	if err := c.checkCanRead(); err != nil {
-		return params.PublicAddressResults{}, err
-	}
-
 	switch {
 	case names.IsValidMachine(p.Target):
+		if err := c.checkCanRead(); err != nil {
+			return params.PublicAddressResults{}, err
+		}
 		machine, err := c.api.stateAccessor.Machine(p.Target)
 		if err != nil {
 			return results, err
@@ -236,6 +262,9 @@ func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAdd
 		return params.PublicAddressResults{PublicAddress: addr.Value}, nil
 
 	case names.IsValidUnit(p.Target):
+		if err := c.checkUnitAccess(p.Target, permission.ReadAccess); err != nil {
+			return params.PublicAddressResults{}, err
+		}
 		unit, err := c.api.stateAccessor.Unit(p.Target)
 		if err != nil {
 			return results, err
@@ -251,12 +280,11 @@ func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAdd
 
 // PrivateAddress implements the server side of Client.PrivateAddress.
 func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.PrivateAddressResults, err error) {
-	if err := c.checkCanRead(); err != nil {
-		return params.PrivateAddressResults{}, err
-	}
-
 	switch {
 	case names.IsValidMachine(p.Target):
+		if err := c.checkCanRead(); err != nil {
+			return params.PrivateAddressResults{}, err
+		}
 		machine, err := c.api.stateAccessor.Machine(p.Target)
 		if err != nil {
 			return results, err
@@ -268,6 +296,9 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 		return params.PrivateAddressResults{PrivateAddress: addr.Value}, nil
 
 	case names.IsValidUnit(p.Target):
+		if err := c.checkUnitAccess(p.Target, permission.ReadAccess); err != nil {
+			return params.PrivateAddressResults{}, err
+		}
 		unit, err := c.api.stateAccessor.Unit(p.Target)
 		if err != nil {
 			return results, err
@@ -606,6 +606,54 @@ func (s *clientSuite) TestClientUnitResolvedRetry(c *gc.C) {
 	s.testClientUnitResolved(c, false, state.ResolvedRetryHooks)
 }
 
+func (s *clientSuite) TestClientUnitResolvedApplicationAccess(c *gc.C) {
+	u := s.setupResolved(c)
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "operator",
+		Password: "operator-password",
+		Access:   permission.ReadAccess,
+	})
+	client := s.OpenAPIAs(c, user.UserTag(), "operator-password").Client()
+	defer client.Close()
+
+	err := client.Resolved("wordpress/0", false)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	err = s.State.SetApplicationAccess("wordpress", user.UserTag(), permission.OperateAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.Resolved("wordpress/0", false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Resolved(), gc.Equals, state.ResolvedRetryHooks)
+}
+
+func (s *clientSuite) TestClientApplicationReadAccess(c *gc.C) {
+	s.setUpScenario(c)
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:        "reader",
+		Password:    "reader-password",
+		NoModelUser: true,
+	})
+	// A user with no access to the model may log in to it to read
+	// the applications they have been granted access to.
+	err := s.State.SetApplicationAccess("wordpress", user.UserTag(), permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	client := s.OpenAPIAs(c, user.UserTag(), "reader-password").Client()
+	defer client.Close()
+
+	filter := status.StatusHistoryFilter{Size: 1}
+	_, err = client.StatusHistory(status.KindUnit, names.NewUnitTag("wordpress/0"), filter)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.StatusHistory(status.KindUnit, names.NewUnitTag("logging/0"), filter)
+	c.Assert(err, gc.ErrorMatches, ".*permission denied")
+	_, err = client.StatusHistory(status.KindMachine, names.NewMachineTag("0"), filter)
+	c.Assert(err, gc.ErrorMatches, ".*permission denied")
+
+	err = client.Resolved("wordpress/0", false)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *clientSuite) setupResolved(c *gc.C) *state.Unit {
 	s.setUpScenario(c)
 	u, err := s.State.Unit("wordpress/0")
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/status"
@@ -115,7 +116,7 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 			Delta:    request.Filter.Delta,
 			Exclude:  set.NewStrings(request.Filter.Exclude...),
 		}
-		if err := c.checkCanRead(); err != nil {
+		if err := c.checkStatusHistoryAccess(request); err != nil {
 			history := params.StatusHistoryResult{
 				Error: common.ServerError(err),
 			}
@@ -164,6 +165,19 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 	return results
 }
 
+// checkStatusHistoryAccess checks that the user may read the status
+// history of the entity in the request. The history of a unit may be
+// read by users who have been granted access to its application.
+func (c *Client) checkStatusHistoryAccess(request params.StatusHistoryRequest) error {
+	switch status.HistoryKind(request.Kind) {
+	case status.KindUnit, status.KindWorkload, status.KindUnitAgent:
+		if u, err := names.ParseUnitTag(request.Tag); err == nil {
+			return c.checkUnitAccess(u.Id(), permission.ReadAccess)
+		}
+	}
+	return c.checkCanRead()
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error) {
 	if err := c.checkCanRead(); err != nil {
@@ -8,10 +8,12 @@ package controller
 import (
 	"encoding/json"
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/txn"
+	"github.com/juju/utils"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/juju/juju/apiserver/common/cloudspec"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cert"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
 	"github.com/juju/juju/permission"
@@ -42,11 +45,18 @@ type ControllerAPI struct {
 	resources  facade.Resources
 }
 
+// ControllerAPIv5 provides the v5 Controller API. The only difference
+// between this and v6 is that v5 doesn't have the controller CA
+// rotation methods.
+type ControllerAPIv5 struct {
+	*ControllerAPI
+}
+
 // ControllerAPIv4 provides the v4 Controller API. The only difference
 // between this and v5 is that v4 doesn't have the
 // UpdateControllerConfig method.
 type ControllerAPIv4 struct {
-	*ControllerAPI
+	*ControllerAPIv5
 }
 
 // ControllerAPIv3 provides the v3 Controller API.
@@ -54,8 +64,8 @@ type ControllerAPIv3 struct {
 	*ControllerAPIv4
 }
 
-// NewControllerAPIv5 creates a new ControllerAPIv5.
-func NewControllerAPIv5(ctx facade.Context) (*ControllerAPI, error) {
+// NewControllerAPIv6 creates a new ControllerAPIv6.
+func NewControllerAPIv6(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	authorizer := ctx.Auth()
 	pool := ctx.StatePool()
@@ -69,6 +79,15 @@ func NewControllerAPIv5(ctx facade.Context) (*ControllerAPI, error) {
 	)
 }
 
+// NewControllerAPIv5 creates a new ControllerAPIv5.
+func NewControllerAPIv5(ctx facade.Context) (*ControllerAPIv5, error) {
+	v6, err := NewControllerAPIv6(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv5{v6}, nil
+}
+
 // NewControllerAPIv4 creates a new ControllerAPIv4.
 func NewControllerAPIv4(ctx facade.Context) (*ControllerAPIv4, error) {
 	v5, err := NewControllerAPIv5(ctx)
@@ -531,6 +550,89 @@ func (c *ControllerAPI) ConfigSet(args params.ControllerConfigSet) error {
 // ConfigSet isn't on the v4 API.
 func (c *ControllerAPIv4) ConfigSet(_, _ struct{}) {}
 
+// ControllerCARotation returns the controller CA rotation in progress.
+// The result's phase is empty if there is none.
+func (c *ControllerAPI) ControllerCARotation() (params.ControllerCARotation, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	rotation, err := c.state.ControllerCARotation()
+	if errors.IsNotFound(err) {
+		return params.ControllerCARotation{}, nil
+	} else if err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	return params.ControllerCARotation{
+		Phase:     string(rotation.Phase),
+		OldCACert: rotation.OldCACert,
+		NewCACert: rotation.NewCACert,
+		Started:   &rotation.Started,
+
+		PendingAgents:      rotation.PendingAgents,
+		PendingControllers: rotation.PendingControllers,
+	}, nil
+}
+
+// caCertValidityYears is how long a rotated controller CA is valid
+// for. It matches the CA generated at bootstrap, so rotating does not
+// shorten the lifetime operators expect of their controller CA.
+const caCertValidityYears = 10
+
+// StartControllerCARotation generates a new controller CA and starts
+// rotating to it. Agents are sent the current and new CAs as soon
+// as they are watching for them, and clients when they next log in.
+func (c *ControllerAPI) StartControllerCARotation() error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	expiry := time.Now().UTC().AddDate(caCertValidityYears, 0, 0)
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return errors.Annotate(err, "generating UUID for CA certificate")
+	}
+	caCert, caKey, err := cert.NewCA("juju-ca", uuid.String(), expiry)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.state.StartControllerCARotation(caCert, caKey))
+}
+
+// ReissueControllerCerts makes the new controller CA the signing CA
+// of a rotation in progress, so that controllers reissue their server
+// certificates with it. The current CA is still trusted. It fails
+// until every agent has acknowledged the new CA.
+func (c *ControllerAPI) ReissueControllerCerts() error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.state.ReissueControllerCerts())
+}
+
+// FinishControllerCARotation completes a controller CA rotation in
+// progress, so that only the new CA is trusted. It fails until every
+// agent has acknowledged the new CA and every controller has reissued
+// its certificate with it.
+func (c *ControllerAPI) FinishControllerCARotation() error {
+	if err := c.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.state.FinishControllerCARotation())
+}
+
+// Mask the controller CA rotation methods from the v5 API.
+
+// ControllerCARotation isn't on the v5 API.
+func (c *ControllerAPIv5) ControllerCARotation(_, _ struct{}) {}
+
+// StartControllerCARotation isn't on the v5 API.
+func (c *ControllerAPIv5) StartControllerCARotation(_, _ struct{}) {}
+
+// ReissueControllerCerts isn't on the v5 API.
+func (c *ControllerAPIv5) ReissueControllerCerts(_, _ struct{}) {}
+
+// FinishControllerCARotation isn't on the v5 API.
+func (c *ControllerAPIv5) FinishControllerCARotation(_, _ struct{}) {}
+
 // runMigrationPrechecks runs prechecks on the migration and updates
 // information in targetInfo as needed based on information
 // retrieved from the target controller.
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/controller"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/cert"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -59,7 +60,7 @@ func (s *controllerSuite) SetUpTest(c *gc.C) {
 		AdminTag: s.Owner,
 	}
 
-	controller, err := controller.NewControllerAPIv5(
+	controller, err := controller.NewControllerAPIv6(
 		facadetest.Context{
 			State_:     s.State,
 			StatePool_: s.StatePool,
@@ -925,7 +926,7 @@ func (s *controllerSuite) TestConfigSetRequiresSuperUser(c *gc.C) {
 	anAuthoriser := apiservertesting.FakeAuthorizer{
 		Tag: user.Tag(),
 	}
-	endpoint, err := controller.NewControllerAPIv5(
+	endpoint, err := controller.NewControllerAPIv6(
 		facadetest.Context{
 			State_:     s.State,
 			Resources_: s.resources,
@@ -939,3 +940,63 @@ func (s *controllerSuite) TestConfigSetRequiresSuperUser(c *gc.C) {
 
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
+
+func (s *controllerSuite) TestControllerCARotation(c *gc.C) {
+	s.PatchValue(&cert.NewCA, testing.NewCA)
+	err := s.State.SetStateServingInfo(state.StateServingInfo{
+		APIPort:      1,
+		StatePort:    2,
+		Cert:         testing.ServerCert,
+		PrivateKey:   testing.ServerKey,
+		CAPrivateKey: testing.CAKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	rotation, err := s.controller.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation, jc.DeepEquals, params.ControllerCARotation{})
+
+	err = s.controller.StartControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	rotation, err = s.controller.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Phase, gc.Equals, "trusting")
+	c.Assert(rotation.OldCACert, gc.Equals, testing.CACert)
+	c.Assert(rotation.NewCACert, gc.Not(gc.Equals), "")
+	c.Assert(rotation.Started, gc.NotNil)
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	c.Assert(caCert, gc.Equals, testing.CACert+rotation.NewCACert)
+
+	err = s.controller.ReissueControllerCerts()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.controller.FinishControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ = cfg.CACert()
+	c.Assert(caCert, gc.Equals, rotation.NewCACert)
+}
+
+func (s *controllerSuite) TestControllerCARotationRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Access: permission.ReadAccess,
+	})
+	endpoint, err := controller.NewControllerAPIv6(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      apiservertesting.FakeAuthorizer{Tag: user.Tag()},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.ControllerCARotation()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = endpoint.StartControllerCARotation()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = endpoint.ReissueControllerCerts()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = endpoint.FinishControllerCARotation()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
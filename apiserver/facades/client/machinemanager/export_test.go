@@ -4,3 +4,4 @@
 package machinemanager
 
 var InstanceTypes = instanceTypes
+var InstanceTypeRecommendations = instanceTypeRecommendations
@@ -25,23 +25,10 @@ func instanceTypes(mm *MachineManagerAPI,
 	getEnviron environGetFunc,
 	cons params.ModelInstanceTypesConstraints,
 ) (params.InstanceTypesResults, error) {
-	model, err := mm.st.Model()
+	env, err := modelEnviron(mm, getEnviron)
 	if err != nil {
 		return params.InstanceTypesResults{}, errors.Trace(err)
 	}
-
-	cloudSpec := func() (environs.CloudSpec, error) {
-		cloudName := model.Cloud()
-		regionName := model.CloudRegion()
-		credentialTag, _ := model.CloudCredential()
-		return stateenvirons.CloudSpec(mm.st, cloudName, regionName, credentialTag)
-	}
-	backend := common.EnvironConfigGetterFuncs{
-		CloudSpecFunc:   cloudSpec,
-		ModelConfigFunc: model.Config,
-	}
-
-	env, err := getEnviron(backend, environs.New)
 	result := make([]params.InstanceTypesResult, len(cons.Constraints))
 	// TODO(perrito666) Cache the results to avoid excessive querying of the cloud.
 	for i, c := range cons.Constraints {
@@ -59,3 +46,23 @@ func instanceTypes(mm *MachineManagerAPI,
 
 	return params.InstanceTypesResults{Results: result}, nil
 }
+
+// modelEnviron returns the Environ for the current model.
+func modelEnviron(mm *MachineManagerAPI, getEnviron environGetFunc) (environs.Environ, error) {
+	model, err := mm.st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cloudSpec := func() (environs.CloudSpec, error) {
+		cloudName := model.Cloud()
+		regionName := model.CloudRegion()
+		credentialTag, _ := model.CloudCredential()
+		return stateenvirons.CloudSpec(mm.st, cloudName, regionName, credentialTag)
+	}
+	backend := common.EnvironConfigGetterFuncs{
+		CloudSpecFunc:   cloudSpec,
+		ModelConfigFunc: model.Config,
+	}
+	return getEnviron(backend, environs.New)
+}
@@ -0,0 +1,305 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/instance"
+)
+
+// cpuPercentile is the percentile of CPU usage samples that instance
+// types are sized for. Memory and disk are sized for their peak usage,
+// since running out of either is fatal rather than merely slow.
+const cpuPercentile = 0.95
+
+// InstanceTypeRecommendations recommends instance types for the
+// specified applications, based on the resources used by their
+// machines over the requested window.
+func (mm *MachineManagerAPIV5) InstanceTypeRecommendations(args params.InstanceTypeRecommendationsArgs) (params.InstanceTypeRecommendationsResults, error) {
+	return instanceTypeRecommendations(mm.MachineManagerAPI, environs.GetEnviron, args, time.Now())
+}
+
+func instanceTypeRecommendations(
+	mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	args params.InstanceTypeRecommendationsArgs,
+	now time.Time,
+) (params.InstanceTypeRecommendationsResults, error) {
+	if args.Window <= 0 {
+		return params.InstanceTypeRecommendationsResults{}, errors.NotValidf("window %v", args.Window)
+	}
+	if args.Headroom < 0 {
+		return params.InstanceTypeRecommendationsResults{}, errors.NotValidf("headroom %v", args.Headroom)
+	}
+
+	appNames := args.Applications
+	if len(appNames) == 0 {
+		apps, err := mm.st.AllApplications()
+		if err != nil {
+			return params.InstanceTypeRecommendationsResults{}, errors.Trace(err)
+		}
+		for _, app := range apps {
+			appNames = append(appNames, app.Name())
+		}
+	}
+
+	env, err := modelEnviron(mm, getEnviron)
+	if err != nil {
+		return params.InstanceTypeRecommendationsResults{}, errors.Trace(err)
+	}
+	r := recommender{
+		st:       mm.st,
+		env:      env,
+		since:    now.Add(-args.Window),
+		headroom: args.Headroom,
+	}
+	results := make([]params.InstanceTypeRecommendationResult, len(appNames))
+	for i, appName := range appNames {
+		recommendation, err := r.recommend(appName)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i].Result = recommendation
+	}
+	return params.InstanceTypeRecommendationsResults{Results: results}, nil
+}
+
+type recommender struct {
+	st       Backend
+	env      environs.Environ
+	since    time.Time
+	headroom float64
+}
+
+// usage summarises the resources used by an application's machines.
+type usage struct {
+	machines []string
+	samples  int
+	cores    float64
+	mem      uint64
+	disk     uint64
+	hardware *instance.HardwareCharacteristics
+}
+
+func (r *recommender) recommend(appName string) (*params.InstanceTypeRecommendation, error) {
+	app, err := r.st.Application(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	u, err := r.usage(app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	needCores := uint64(math.Ceil(u.cores * (1 + r.headroom)))
+	if needCores == 0 {
+		needCores = 1
+	}
+	needMem := uint64(math.Ceil(float64(u.mem) * (1 + r.headroom)))
+	needDisk := uint64(math.Ceil(float64(u.disk) * (1 + r.headroom)))
+
+	var cons constraints.Value
+	if u.hardware != nil && u.hardware.Arch != nil {
+		cons.Arch = u.hardware.Arch
+	}
+	catalogue, err := r.env.InstanceTypes(cons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	recommended, ok := cheapestInstanceType(catalogue.InstanceTypes, func(t instances.InstanceType) bool {
+		return t.CpuCores >= needCores && t.Mem >= needMem && (t.RootDisk == 0 || t.RootDisk >= needDisk)
+	})
+	if !ok {
+		return nil, errors.NotFoundf(
+			"instance type with %d cores, %dM memory and %dM root disk",
+			needCores, needMem, needDisk,
+		)
+	}
+
+	result := &params.InstanceTypeRecommendation{
+		Application:  app.Name(),
+		Machines:     u.machines,
+		Samples:      u.samples,
+		CPUCores:     u.cores,
+		Memory:       u.mem,
+		RootDisk:     u.disk,
+		CostUnit:     catalogue.CostUnit,
+		CostCurrency: catalogue.CostCurrency,
+		CostDivisor:  catalogue.CostDivisor,
+	}
+	result.Action, recommended = compareInstanceType(u.hardware, catalogue.InstanceTypes, recommended, func(cores, mem uint64) bool {
+		return cores >= needCores && mem >= needMem
+	})
+	if current, ok := currentInstanceType(u.hardware, catalogue.InstanceTypes); ok {
+		paramsCurrent := common.ToParamsInstanceType(current)
+		result.Current = &paramsCurrent
+	}
+	result.Recommended = common.ToParamsInstanceType(recommended)
+	result.Constraints = constraints.Value{
+		CpuCores: &recommended.CpuCores,
+		Mem:      &recommended.Mem,
+	}
+	if u.hardware != nil && u.hardware.RootDisk != nil && *u.hardware.RootDisk < needDisk {
+		result.Constraints.RootDisk = &needDisk
+	}
+	return result, nil
+}
+
+// usage gathers the usage samples recorded since the recommender's
+// start time for all of the machines hosting units of the application.
+func (r *recommender) usage(app Application) (*usage, error) {
+	units, err := app.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineIds := set.NewStrings()
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machineIds.Add(machineId)
+	}
+	if machineIds.IsEmpty() {
+		return nil, errors.NotFoundf("machines for application %q", app.Name())
+	}
+
+	result := &usage{machines: machineIds.SortedValues()}
+	var cores []float64
+	for _, machineId := range result.machines {
+		machine, err := r.st.Machine(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if result.hardware == nil {
+			hardware, err := machine.HardwareCharacteristics()
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+			result.hardware = hardware
+		}
+		samples, err := r.st.MachineUsage(machineId, r.since)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, sample := range samples {
+			cores = append(cores, sample.CoresUsed())
+			if sample.MemoryUsed > result.mem {
+				result.mem = sample.MemoryUsed
+			}
+			if sample.DiskUsed > result.disk {
+				result.disk = sample.DiskUsed
+			}
+		}
+	}
+	if len(cores) == 0 {
+		return nil, errors.NotFoundf("usage samples for application %q", app.Name())
+	}
+	result.samples = len(cores)
+	result.cores = percentile(cores, cpuPercentile)
+	return result, nil
+}
+
+// compareInstanceType compares the recommended instance type with the
+// machine hardware currently in use, and returns the action to take
+// along with the instance type to recommend. If the current hardware
+// already satisfies the application's needs, and no cheaper instance
+// type does too, the current instance type is recommended. If the
+// current hardware is not known, the recommended instance type is
+// kept as is.
+func compareInstanceType(
+	hardware *instance.HardwareCharacteristics,
+	catalogue []instances.InstanceType,
+	recommended instances.InstanceType,
+	satisfies func(cores, mem uint64) bool,
+) (string, instances.InstanceType) {
+	if hardware == nil || hardware.CpuCores == nil || hardware.Mem == nil {
+		return params.RecommendKeep, recommended
+	}
+	if !satisfies(*hardware.CpuCores, *hardware.Mem) {
+		return params.RecommendUpsize, recommended
+	}
+	current, ok := currentInstanceType(hardware, catalogue)
+	if ok {
+		if current.Cost <= recommended.Cost {
+			return params.RecommendKeep, current
+		}
+		return params.RecommendDownsize, recommended
+	}
+	if recommended.CpuCores >= *hardware.CpuCores && recommended.Mem >= *hardware.Mem {
+		return params.RecommendKeep, recommended
+	}
+	return params.RecommendDownsize, recommended
+}
+
+// currentInstanceType returns the cheapest instance type in the
+// catalogue with exactly the cores and memory of the given hardware.
+func currentInstanceType(
+	hardware *instance.HardwareCharacteristics,
+	catalogue []instances.InstanceType,
+) (instances.InstanceType, bool) {
+	if hardware == nil || hardware.CpuCores == nil || hardware.Mem == nil {
+		return instances.InstanceType{}, false
+	}
+	return cheapestInstanceType(catalogue, func(t instances.InstanceType) bool {
+		return t.CpuCores == *hardware.CpuCores && t.Mem == *hardware.Mem
+	})
+}
+
+// cheapestInstanceType returns the cheapest non-deprecated instance
+// type for which match returns true. Ties are broken by preferring
+// fewer cores, then less memory, then name.
+func cheapestInstanceType(
+	catalogue []instances.InstanceType,
+	match func(instances.InstanceType) bool,
+) (instances.InstanceType, bool) {
+	var candidates []instances.InstanceType
+	for _, t := range catalogue {
+		if !t.Deprecated && match(t) {
+			candidates = append(candidates, t)
+		}
+	}
+	if len(candidates) == 0 {
+		return instances.InstanceType{}, false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Cost != b.Cost {
+			return a.Cost < b.Cost
+		}
+		if a.CpuCores != b.CpuCores {
+			return a.CpuCores < b.CpuCores
+		}
+		if a.Mem != b.Mem {
+			return a.Mem < b.Mem
+		}
+		return a.Name < b.Name
+	})
+	return candidates[0], true
+}
+
+// percentile returns the p'th percentile of values, using the
+// nearest-rank method.
+func percentile(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
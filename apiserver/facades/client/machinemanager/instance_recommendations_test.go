@@ -0,0 +1,288 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type instanceTypeRecommendationsSuite struct {
+	backend *recommendationsBackend
+	env     *catalogueEnviron
+	api     *machinemanager.MachineManagerAPI
+	now     time.Time
+}
+
+var _ = gc.Suite(&instanceTypeRecommendationsSuite{})
+
+var catalogue = []instances.InstanceType{
+	{Name: "small", Arches: []string{"amd64"}, CpuCores: 1, Mem: 2048, Cost: 10},
+	{Name: "medium", Arches: []string{"amd64"}, CpuCores: 2, Mem: 4096, Cost: 20},
+	{Name: "medium-old", Arches: []string{"amd64"}, CpuCores: 2, Mem: 4096, Cost: 5, Deprecated: true},
+	{Name: "large", Arches: []string{"amd64"}, CpuCores: 4, Mem: 8192, Cost: 40},
+	{Name: "xlarge", Arches: []string{"amd64"}, CpuCores: 8, Mem: 16384, Cost: 80},
+}
+
+func (s *instanceTypeRecommendationsSuite) SetUpTest(c *gc.C) {
+	s.now = time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.backend = &recommendationsBackend{
+		applications: make(map[string]*mockApplication),
+		machines:     make(map[string]*mockMachine),
+		hardware:     make(map[string]*instance.HardwareCharacteristics),
+		usage:        make(map[string][]state.MachineUsage),
+	}
+	s.env = &catalogueEnviron{
+		catalogue: instances.InstanceTypesWithCostMetadata{
+			InstanceTypes: catalogue,
+			CostUnit:      "USD/h",
+			CostCurrency:  "USD",
+		},
+	}
+	authorizer := testing.FakeAuthorizer{Tag: names.NewUserTag("admin"), Controller: true}
+	api, err := machinemanager.NewMachineManagerAPI(s.backend, &mockPool{}, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *instanceTypeRecommendationsSuite) addMachine(id string, cores, mem uint64, cpu []float64, memUsed uint64) {
+	s.backend.machines[id] = &mockMachine{}
+	arch := "amd64"
+	s.backend.hardware[id] = &instance.HardwareCharacteristics{
+		Arch:     &arch,
+		CpuCores: &cores,
+		Mem:      &mem,
+	}
+	for i, percent := range cpu {
+		s.backend.usage[id] = append(s.backend.usage[id], state.MachineUsage{
+			Time:        s.now.Add(-time.Duration(len(cpu)-i) * time.Minute),
+			CPU:         percent,
+			Cores:       cores,
+			MemoryUsed:  memUsed,
+			MemoryTotal: mem,
+			DiskUsed:    1024,
+			DiskTotal:   8192,
+		})
+	}
+}
+
+func (s *instanceTypeRecommendationsSuite) addApplication(name string, machineIds ...string) {
+	app := &mockApplication{name: name}
+	for i, machineId := range machineIds {
+		app.units = append(app.units, &mockUnit{
+			tag:       names.NewUnitTag(fmt.Sprintf("%s/%d", name, i)),
+			machineId: machineId,
+		})
+	}
+	s.backend.applications[name] = app
+}
+
+func (s *instanceTypeRecommendationsSuite) recommend(c *gc.C, apps ...string) []params.InstanceTypeRecommendationResult {
+	getEnviron := func(environs.EnvironConfigGetter, environs.NewEnvironFunc) (environs.Environ, error) {
+		return s.env, nil
+	}
+	results, err := machinemanager.InstanceTypeRecommendations(s.api, getEnviron, params.InstanceTypeRecommendationsArgs{
+		Applications: apps,
+		Window:       time.Hour,
+		Headroom:     0.25,
+	}, s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, len(apps))
+	return results.Results
+}
+
+func (s *instanceTypeRecommendationsSuite) TestDownsize(c *gc.C) {
+	// 10% of 4 cores is 0.4 cores; plus 25% is 0.5, rounded up to 1.
+	s.addMachine("0", 4, 8192, []float64{5, 10, 10}, 1024)
+	s.addApplication("mysql", "0")
+
+	results := s.recommend(c, "mysql")
+	c.Assert(results[0].Error, gc.IsNil)
+	r := results[0].Result
+	c.Assert(r.Application, gc.Equals, "mysql")
+	c.Assert(r.Machines, jc.DeepEquals, []string{"0"})
+	c.Assert(r.Samples, gc.Equals, 3)
+	c.Assert(r.CPUCores, gc.Equals, 0.4)
+	c.Assert(r.Memory, gc.Equals, uint64(1024))
+	c.Assert(r.Action, gc.Equals, params.RecommendDownsize)
+	c.Assert(r.Current, gc.NotNil)
+	c.Assert(r.Current.Name, gc.Equals, "large")
+	c.Assert(r.Recommended.Name, gc.Equals, "small")
+	c.Assert(r.CostUnit, gc.Equals, "USD/h")
+	c.Assert(r.Constraints, jc.DeepEquals, constraints.MustParse("cores=1 mem=2048M"))
+}
+
+func (s *instanceTypeRecommendationsSuite) TestUpsize(c *gc.C) {
+	s.addMachine("0", 2, 4096, []float64{90, 100}, 3800)
+	s.addApplication("mysql", "0")
+
+	results := s.recommend(c, "mysql")
+	c.Assert(results[0].Error, gc.IsNil)
+	r := results[0].Result
+	c.Assert(r.Action, gc.Equals, params.RecommendUpsize)
+	c.Assert(r.Current.Name, gc.Equals, "medium")
+	c.Assert(r.Recommended.Name, gc.Equals, "large")
+}
+
+func (s *instanceTypeRecommendationsSuite) TestKeep(c *gc.C) {
+	s.addMachine("0", 2, 4096, []float64{50, 60}, 3000)
+	s.addApplication("mysql", "0")
+
+	results := s.recommend(c, "mysql")
+	c.Assert(results[0].Error, gc.IsNil)
+	r := results[0].Result
+	c.Assert(r.Action, gc.Equals, params.RecommendKeep)
+	c.Assert(r.Recommended.Name, gc.Equals, "medium")
+}
+
+func (s *instanceTypeRecommendationsSuite) TestCPUPercentileIgnoresSpikes(c *gc.C) {
+	// One spike in 20 samples is ignored by the 95th percentile.
+	cpu := make([]float64, 20)
+	for i := range cpu {
+		cpu[i] = 10
+	}
+	cpu[7] = 100
+	s.addMachine("0", 4, 8192, cpu, 1024)
+	s.addApplication("mysql", "0")
+
+	results := s.recommend(c, "mysql")
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[0].Result.CPUCores, gc.Equals, 0.4)
+}
+
+func (s *instanceTypeRecommendationsSuite) TestMultipleMachines(c *gc.C) {
+	s.addMachine("0", 4, 8192, []float64{10}, 1024)
+	s.addMachine("1", 4, 8192, []float64{10}, 5000)
+	s.addApplication("mysql", "0", "1")
+
+	results := s.recommend(c, "mysql")
+	c.Assert(results[0].Error, gc.IsNil)
+	r := results[0].Result
+	c.Assert(r.Machines, jc.DeepEquals, []string{"0", "1"})
+	c.Assert(r.Memory, gc.Equals, uint64(5000))
+	c.Assert(r.Action, gc.Equals, params.RecommendKeep)
+	c.Assert(r.Recommended.Name, gc.Equals, "large")
+}
+
+func (s *instanceTypeRecommendationsSuite) TestAllApplications(c *gc.C) {
+	s.addMachine("0", 4, 8192, []float64{10}, 1024)
+	s.addApplication("mysql", "0")
+
+	getEnviron := func(environs.EnvironConfigGetter, environs.NewEnvironFunc) (environs.Environ, error) {
+		return s.env, nil
+	}
+	results, err := machinemanager.InstanceTypeRecommendations(s.api, getEnviron, params.InstanceTypeRecommendationsArgs{
+		Window: time.Hour,
+	}, s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Result.Application, gc.Equals, "mysql")
+}
+
+func (s *instanceTypeRecommendationsSuite) TestErrors(c *gc.C) {
+	s.addMachine("0", 4, 8192, nil, 0)
+	s.addApplication("nosamples", "0")
+	s.addApplication("unassigned", "")
+
+	results := s.recommend(c, "nosamples", "unassigned", "missing")
+	c.Assert(results[0].Error, gc.ErrorMatches, `usage samples for application "nosamples" not found`)
+	c.Assert(results[1].Error, gc.ErrorMatches, `machines for application "unassigned" not found`)
+	c.Assert(results[2].Error, gc.ErrorMatches, `application "missing" not found`)
+}
+
+func (s *instanceTypeRecommendationsSuite) TestInvalidWindow(c *gc.C) {
+	_, err := machinemanager.InstanceTypeRecommendations(s.api, nil, params.InstanceTypeRecommendationsArgs{}, s.now)
+	c.Assert(err, gc.ErrorMatches, "window 0s not valid")
+}
+
+type recommendationsBackend struct {
+	mockBackend
+
+	applications map[string]*mockApplication
+	machines     map[string]*mockMachine
+	hardware     map[string]*instance.HardwareCharacteristics
+	usage        map[string][]state.MachineUsage
+}
+
+func (b *recommendationsBackend) Application(name string) (machinemanager.Application, error) {
+	app, ok := b.applications[name]
+	if !ok {
+		return nil, errors.NotFoundf("application %q", name)
+	}
+	return app, nil
+}
+
+func (b *recommendationsBackend) AllApplications() ([]machinemanager.Application, error) {
+	var apps []machinemanager.Application
+	for _, app := range b.applications {
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+func (b *recommendationsBackend) Machine(id string) (machinemanager.Machine, error) {
+	m, ok := b.machines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %q", id)
+	}
+	return &hardwareMachine{mockMachine: m, hardware: b.hardware[id]}, nil
+}
+
+func (b *recommendationsBackend) MachineUsage(id string, since time.Time) ([]state.MachineUsage, error) {
+	var result []state.MachineUsage
+	for _, usage := range b.usage[id] {
+		if !usage.Time.Before(since) {
+			result = append(result, usage)
+		}
+	}
+	return result, nil
+}
+
+type hardwareMachine struct {
+	*mockMachine
+	hardware *instance.HardwareCharacteristics
+}
+
+func (m *hardwareMachine) HardwareCharacteristics() (*instance.HardwareCharacteristics, error) {
+	if m.hardware == nil {
+		return nil, errors.NotFoundf("hardware characteristics")
+	}
+	return m.hardware, nil
+}
+
+type mockApplication struct {
+	name  string
+	units []machinemanager.Unit
+}
+
+func (a *mockApplication) Name() string {
+	return a.name
+}
+
+func (a *mockApplication) AllUnits() ([]machinemanager.Unit, error) {
+	return a.units, nil
+}
+
+type catalogueEnviron struct {
+	environs.Environ
+	catalogue instances.InstanceTypesWithCostMetadata
+}
+
+func (e *catalogueEnviron) InstanceTypes(constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+	return e.catalogue, nil
+}
@@ -57,6 +57,32 @@ func NewFacadeV4(ctx facade.Context) (*MachineManagerAPIV4, error) {
 	return &MachineManagerAPIV4{machineManagerAPI}, nil
 }
 
+type MachineManagerAPIV5 struct {
+	*MachineManagerAPIV4
+}
+
+// NewFacadeV5 creates a new server-side MachineManager API facade.
+func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
+	machineManagerAPIV4, err := NewFacadeV4(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV5{machineManagerAPIV4}, nil
+}
+
+type MachineManagerAPIV6 struct {
+	*MachineManagerAPIV5
+}
+
+// NewFacadeV6 creates a new server-side MachineManager API facade.
+func NewFacadeV6(ctx facade.Context) (*MachineManagerAPIV6, error) {
+	machineManagerAPIV5, err := NewFacadeV5(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV6{machineManagerAPIV5}, nil
+}
+
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
 func NewMachineManagerAPI(backend Backend, pool Pool, auth facade.Authorizer) (*MachineManagerAPI, error) {
 	if !auth.AuthClient() {
@@ -291,6 +317,123 @@ func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep bo
 	return params.DestroyMachineResults{results}, nil
 }
 
+// ReplaceMachines replaces each of the given machines with a new
+// machine with the same constraints and placement, moves their units
+// to the replacements, and destroys the original machines. Machines
+// that have been provisioned are only replaced, and then forcibly
+// destroyed, if force is true. If a machine is replaced but cannot be
+// destroyed, the result holds both the replacement info and the error.
+func (mm *MachineManagerAPIV6) ReplaceMachines(args params.ReplaceMachinesParams) (params.ReplaceMachineResults, error) {
+	if err := mm.checkCanWrite(); err != nil {
+		return params.ReplaceMachineResults{}, err
+	}
+	if err := mm.check.RemoveAllowed(); err != nil {
+		return params.ReplaceMachineResults{}, err
+	}
+	results := make([]params.ReplaceMachineResult, len(args.MachineTags))
+	for i, tag := range args.MachineTags {
+		info, err := mm.replaceMachine(tag, args.Force)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+		}
+		results[i].Info = info
+	}
+	return params.ReplaceMachineResults{results}, nil
+}
+
+func (mm *MachineManagerAPI) replaceMachine(tag string, force bool) (*params.ReplaceMachineInfo, error) {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	machine, err := mm.st.Machine(machineTag.Id())
+	if err != nil {
+		return nil, err
+	}
+	if !force {
+		if err := checkCanReplace(machine); err != nil {
+			return nil, err
+		}
+	}
+	var info params.ReplaceMachineInfo
+	units, err := machine.Units()
+	if err != nil {
+		return nil, err
+	}
+	storageSeen := make(set.Tags)
+	for _, unit := range units {
+		info.MovedUnits = append(
+			info.MovedUnits,
+			params.Entity{unit.UnitTag().String()},
+		)
+		storage, err := storagecommon.UnitStorage(mm.st, unit.UnitTag())
+		if err != nil {
+			return nil, err
+		}
+		for _, storage := range storage {
+			storageTag := storage.StorageTag()
+			if storageSeen.Contains(storageTag) {
+				continue
+			}
+			storageSeen.Add(storageTag)
+			info.MovedStorage = append(
+				info.MovedStorage,
+				params.Entity{storageTag.String()},
+			)
+		}
+	}
+	replacement, err := machine.Replace()
+	if err != nil {
+		return nil, err
+	}
+	info.Replacement = names.NewMachineTag(replacement.Id()).String()
+	logger.Infof("replaced machine %v with machine %v", machineTag.Id(), replacement.Id())
+
+	// The units' storage is detached from the original machine when
+	// it is destroyed, and then attached to the replacement.
+	destroy := machine.Destroy
+	if force {
+		destroy = machine.ForceDestroy
+	}
+	if err := destroy(); err != nil {
+		// The units have been moved to the replacement, so the
+		// info is returned along with the error.
+		return &info, errors.Annotatef(
+			err, "destroying machine %v after replacing it with machine %v",
+			machineTag.Id(), replacement.Id(),
+		)
+	}
+	return &info, nil
+}
+
+// checkCanReplace returns an error if the given machine has been
+// provisioned, and so cannot safely be replaced without force. If its
+// agent is not running, the machine would never finish dying; if it
+// is running, it would keep running the machine's units until it
+// notices they have been moved, while they are deployed afresh on the
+// replacement.
+func checkCanReplace(machine Machine) error {
+	if _, err := machine.InstanceId(); errors.IsNotProvisioned(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	present, err := machine.AgentPresence()
+	if err != nil {
+		return err
+	}
+	if !present {
+		return errors.Errorf(
+			"agent for machine %v is not running; use --force to replace it anyway",
+			machine.Id(),
+		)
+	}
+	return errors.Errorf(
+		"agent for machine %v is running its units; use --force to replace it anyway",
+		machine.Id(),
+	)
+}
+
 // UpdateMachineSeries updates the series of the given machine(s) as well as all
 // units and subordintes installed on the machine(s).
 func (mm *MachineManagerAPIV4) UpdateMachineSeries(args params.UpdateSeriesArgs) (params.ErrorResults, error) {
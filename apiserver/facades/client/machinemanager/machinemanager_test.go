@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/storage"
@@ -176,6 +177,146 @@ func (s *MachineManagerSuite) TestDestroyMachineWithParams(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) apiV6() *machinemanager.MachineManagerAPIV6 {
+	return &machinemanager.MachineManagerAPIV6{
+		&machinemanager.MachineManagerAPIV5{
+			&machinemanager.MachineManagerAPIV4{s.api},
+		},
+	}
+}
+
+func (s *MachineManagerSuite) TestReplaceMachines(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.machines["0"].SetErrors(errors.NotProvisionedf("machine 0"))
+	results, err := s.apiV6().ReplaceMachines(params.ReplaceMachinesParams{
+		MachineTags: []string{"machine-0", "machine-42", "unit-foo-0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ReplaceMachineResults{
+		Results: []params.ReplaceMachineResult{{
+			Info: &params.ReplaceMachineInfo{
+				Replacement: "machine-1",
+				MovedUnits: []params.Entity{
+					{"unit-foo-0"},
+					{"unit-foo-1"},
+					{"unit-foo-2"},
+				},
+				MovedStorage: []params.Entity{
+					{"storage-disks-0"},
+					{"storage-disks-1"},
+				},
+			},
+		}, {
+			Error: &params.Error{Message: "machine 42 not found", Code: "not found"},
+		}, {
+			Error: &params.Error{Message: `"unit-foo-0" is not a valid machine tag`},
+		}},
+	})
+	s.st.machines["0"].CheckCallNames(c, "InstanceId", "Replace", "Destroy")
+}
+
+func (s *MachineManagerSuite) TestReplaceMachinesForce(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	results, err := s.apiV6().ReplaceMachines(params.ReplaceMachinesParams{
+		MachineTags: []string{"machine-0"},
+		Force:       true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	s.st.machines["0"].CheckCallNames(c, "Replace", "ForceDestroy")
+}
+
+func (s *MachineManagerSuite) TestReplaceMachinesAgentLost(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{id: "0", agentLost: true}
+	results, err := s.apiV6().ReplaceMachines(params.ReplaceMachinesParams{
+		MachineTags: []string{"machine-0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ReplaceMachineResults{
+		Results: []params.ReplaceMachineResult{{
+			Error: &params.Error{
+				Message: "agent for machine 0 is not running; use --force to replace it anyway",
+			},
+		}},
+	})
+	s.st.machines["0"].CheckCallNames(c, "InstanceId", "AgentPresence")
+}
+
+func (s *MachineManagerSuite) TestReplaceMachinesAgentRunning(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{id: "0"}
+	results, err := s.apiV6().ReplaceMachines(params.ReplaceMachinesParams{
+		MachineTags: []string{"machine-0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ReplaceMachineResults{
+		Results: []params.ReplaceMachineResult{{
+			Error: &params.Error{
+				Message: "agent for machine 0 is running its units; use --force to replace it anyway",
+			},
+		}},
+	})
+	s.st.machines["0"].CheckCallNames(c, "InstanceId", "AgentPresence")
+}
+
+func (s *MachineManagerSuite) TestReplaceMachinesError(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.machines["0"].SetErrors(errors.NotProvisionedf("machine 0"), errors.New("boom"))
+	results, err := s.apiV6().ReplaceMachines(params.ReplaceMachinesParams{
+		MachineTags: []string{"machine-0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ReplaceMachineResults{
+		Results: []params.ReplaceMachineResult{{
+			Error: &params.Error{Message: "boom"},
+		}},
+	})
+	s.st.machines["0"].CheckCallNames(c, "InstanceId", "Replace")
+}
+
+func (s *MachineManagerSuite) TestReplaceMachinesDestroyError(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.machines["0"].SetErrors(errors.NotProvisionedf("machine 0"), nil, errors.New("boom"))
+	results, err := s.apiV6().ReplaceMachines(params.ReplaceMachinesParams{
+		MachineTags: []string{"machine-0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ReplaceMachineResults{
+		Results: []params.ReplaceMachineResult{{
+			Error: &params.Error{Message: "destroying machine 0 after replacing it with machine 1: boom"},
+			Info: &params.ReplaceMachineInfo{
+				Replacement: "machine-1",
+				MovedUnits: []params.Entity{
+					{"unit-foo-0"},
+					{"unit-foo-1"},
+					{"unit-foo-2"},
+				},
+				MovedStorage: []params.Entity{
+					{"storage-disks-0"},
+					{"storage-disks-1"},
+				},
+			},
+		}},
+	})
+}
+
+func (s *MachineManagerSuite) TestReplaceMachinesBlocked(c *gc.C) {
+	s.st.blockMsg = "TestReplaceMachinesBlocked"
+	s.st.block = state.RemoveBlock
+	_, err := s.apiV6().ReplaceMachines(params.ReplaceMachinesParams{
+		MachineTags: []string{"machine-0"},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue, gc.Commentf("error: %#v", err))
+}
+
+func (s *MachineManagerSuite) TestReplaceMachinesPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.apiV6().ReplaceMachines(params.ReplaceMachinesParams{
+		MachineTags: []string{"machine-0"},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *MachineManagerSuite) setupUpdateMachineSeries(c *gc.C) {
 	s.st.machines = map[string]*mockMachine{
 		"0": &mockMachine{series: "trusty"},
@@ -415,16 +556,48 @@ type mockMachine struct {
 	jtesting.Stub
 	machinemanager.Machine
 
-	keep   bool
-	series string
+	id        string
+	keep      bool
+	series    string
+	agentLost bool
+}
+
+func (m *mockMachine) Id() string {
+	return m.id
+}
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	m.MethodCall(m, "InstanceId")
+	if err := m.NextErr(); err != nil {
+		return "", err
+	}
+	return instance.Id("inst-" + m.id), nil
+}
+
+func (m *mockMachine) AgentPresence() (bool, error) {
+	m.MethodCall(m, "AgentPresence")
+	if err := m.NextErr(); err != nil {
+		return false, err
+	}
+	return !m.agentLost, nil
 }
 
 func (m *mockMachine) Destroy() error {
-	return nil
+	m.MethodCall(m, "Destroy")
+	return m.NextErr()
 }
 
 func (m *mockMachine) ForceDestroy() error {
-	return nil
+	m.MethodCall(m, "ForceDestroy")
+	return m.NextErr()
+}
+
+func (m *mockMachine) Replace() (machinemanager.Machine, error) {
+	m.MethodCall(m, "Replace")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return &mockMachine{id: "1"}, nil
 }
 
 func (m *mockMachine) SetKeepInstance(keep bool) error {
@@ -439,9 +612,9 @@ func (m *mockMachine) Series() string {
 
 func (m *mockMachine) Units() ([]machinemanager.Unit, error) {
 	return []machinemanager.Unit{
-		&mockUnit{names.NewUnitTag("foo/0")},
-		&mockUnit{names.NewUnitTag("foo/1")},
-		&mockUnit{names.NewUnitTag("foo/2")},
+		&mockUnit{tag: names.NewUnitTag("foo/0")},
+		&mockUnit{tag: names.NewUnitTag("foo/1")},
+		&mockUnit{tag: names.NewUnitTag("foo/2")},
 	}, nil
 }
 
@@ -451,13 +624,21 @@ func (m *mockMachine) UpdateMachineSeries(series string, force bool) error {
 }
 
 type mockUnit struct {
-	tag names.UnitTag
+	tag       names.UnitTag
+	machineId string
 }
 
 func (u *mockUnit) UnitTag() names.UnitTag {
 	return u.tag
 }
 
+func (u *mockUnit) AssignedMachineId() (string, error) {
+	if u.machineId == "" {
+		return "", errors.NotAssignedf("unit %q", u.tag.Id())
+	}
+	return u.machineId, nil
+}
+
 type mockStorage struct {
 	state.StorageInstance
 	tag  names.StorageTag
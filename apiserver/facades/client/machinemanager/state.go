@@ -4,6 +4,8 @@
 package machinemanager
 
 import (
+	"time"
+
 	names "gopkg.in/juju/names.v2"
 
 	"github.com/juju/errors"
@@ -25,6 +27,9 @@ type Backend interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	Application(string) (Application, error)
+	AllApplications() ([]Application, error)
+	MachineUsage(machineId string, since time.Time) ([]state.MachineUsage, error)
 }
 
 type Pool interface {
@@ -41,12 +46,17 @@ type Model interface {
 }
 
 type Machine interface {
+	Id() string
 	Destroy() error
 	ForceDestroy() error
 	Series() string
 	Units() ([]Unit, error)
 	SetKeepInstance(keepInstance bool) error
 	UpdateMachineSeries(string, bool) error
+	HardwareCharacteristics() (*instance.HardwareCharacteristics, error)
+	InstanceId() (instance.Id, error)
+	AgentPresence() (bool, error)
+	Replace() (Machine, error)
 }
 
 type stateShim struct {
@@ -66,6 +76,26 @@ func (s stateShim) Model() (Model, error) {
 	return s.State.Model()
 }
 
+func (s stateShim) Application(name string) (Application, error) {
+	app, err := s.State.Application(name)
+	if err != nil {
+		return nil, err
+	}
+	return applicationShim{app}, nil
+}
+
+func (s stateShim) AllApplications() ([]Application, error) {
+	apps, err := s.State.AllApplications()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Application, len(apps))
+	for i, app := range apps {
+		out[i] = applicationShim{app}
+	}
+	return out, nil
+}
+
 type poolShim struct {
 	pool *state.StatePool
 }
@@ -82,6 +112,14 @@ type machineShim struct {
 	*state.Machine
 }
 
+func (m machineShim) Replace() (Machine, error) {
+	replacement, err := m.Machine.Replace()
+	if err != nil {
+		return nil, err
+	}
+	return machineShim{replacement}, nil
+}
+
 func (m machineShim) Units() ([]Unit, error) {
 	units, err := m.Machine.Units()
 	if err != nil {
@@ -96,4 +134,26 @@ func (m machineShim) Units() ([]Unit, error) {
 
 type Unit interface {
 	UnitTag() names.UnitTag
+	AssignedMachineId() (string, error)
+}
+
+type Application interface {
+	Name() string
+	AllUnits() ([]Unit, error)
+}
+
+type applicationShim struct {
+	*state.Application
+}
+
+func (a applicationShim) AllUnits() ([]Unit, error) {
+	units, err := a.Application.AllUnits()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Unit, len(units))
+	for i, u := range units {
+		out[i] = u
+	}
+	return out, nil
 }
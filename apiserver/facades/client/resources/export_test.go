@@ -0,0 +1,12 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources
+
+import "github.com/juju/juju/permission"
+
+// SetCheckApplicationAccess sets the function used by the facade to
+// check that the user has access to an application.
+func SetCheckApplicationAccess(f *Facade, check func(applicationID string, access permission.Access) error) {
+	f.checkApplicationAccess = check
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/charmstore"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/api"
 	"github.com/juju/juju/state"
@@ -53,6 +54,11 @@ type Facade struct {
 	store Backend
 
 	newCharmstoreClient func() (CharmStore, error)
+
+	// checkApplicationAccess, if set, returns an error unless the
+	// authenticated user has the given access to the named
+	// application.
+	checkApplicationAccess func(applicationID string, access permission.Access) error
 }
 
 // NewPublicFacade creates a public API facade for resources. It is
@@ -73,6 +79,17 @@ func NewPublicFacade(st *state.State, _ facade.Resources, authorizer facade.Auth
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	modelTag := st.ModelTag()
+	facade.checkApplicationAccess = func(applicationID string, access permission.Access) error {
+		allowed, err := common.HasApplicationPermission(authorizer, modelTag, applicationID, access)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !allowed {
+			return common.ErrPerm
+		}
+		return nil
+	}
 	return facade, nil
 }
 
@@ -113,6 +130,13 @@ func (f Facade) ListResources(args params.ListResourcesArgs) (params.ResourcesRe
 			continue
 		}
 
+		if f.checkApplicationAccess != nil {
+			if err := f.checkApplicationAccess(tag.Id(), permission.ReadAccess); err != nil {
+				r.Results[i] = errorResult(err)
+				continue
+			}
+		}
+
 		svcRes, err := f.store.ListResources(tag.Id())
 		if err != nil {
 			r.Results[i] = errorResult(err)
@@ -136,6 +160,12 @@ func (f Facade) AddPendingResources(args params.AddPendingResourcesArgs) (params
 		return result, nil
 	}
 	applicationID := tag.Id()
+	if f.checkApplicationAccess != nil {
+		if err := f.checkApplicationAccess(applicationID, permission.OperateAccess); err != nil {
+			result.Error = common.ServerError(err)
+			return result, nil
+		}
+	}
 
 	channel := csparams.Channel(args.Channel)
 	ids, err := f.addPendingResources(applicationID, args.URL, channel, args.CharmStoreMacaroon, args.Resources)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/apiserver/facades/client/resources"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
 )
 
 var _ = gc.Suite(&AddPendingResourcesSuite{})
@@ -348,3 +349,28 @@ func (s *AddPendingResourcesSuite) TestDataStoreError(c *gc.C) {
 		}},
 	})
 }
+
+func (s *AddPendingResourcesSuite) TestPermissionDenied(c *gc.C) {
+	_, apiRes1 := newResource(c, "spam", "a-user", "spamspamspam")
+	facade, err := resources.NewFacade(s.data, s.newCSClient)
+	c.Assert(err, jc.ErrorIsNil)
+	var checked string
+	resources.SetCheckApplicationAccess(facade, func(applicationID string, access permission.Access) error {
+		c.Check(access, gc.Equals, permission.OperateAccess)
+		checked = applicationID
+		return errors.New("permission denied")
+	})
+
+	result, err := facade.AddPendingResources(params.AddPendingResourcesArgs{
+		Entity: params.Entity{
+			Tag: "application-a-application",
+		},
+		Resources: []params.CharmResource{
+			apiRes1.CharmResource,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checked, gc.Equals, "a-application")
+	c.Assert(result.Error, gc.ErrorMatches, "permission denied")
+	s.stub.CheckNoCalls(c)
+}
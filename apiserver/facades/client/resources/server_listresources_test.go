@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/apiserver/facades/client/resources"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/resource"
 )
 
@@ -144,3 +145,33 @@ func (s *ListResourcesSuite) TestError(c *gc.C) {
 	})
 	s.stub.CheckCallNames(c, "ListResources")
 }
+
+func (s *ListResourcesSuite) TestPermissionDenied(c *gc.C) {
+	facade, err := resources.NewFacade(s.data, s.newCSClient)
+	c.Assert(err, jc.ErrorIsNil)
+	resources.SetCheckApplicationAccess(facade, func(applicationID string, access permission.Access) error {
+		c.Check(access, gc.Equals, permission.ReadAccess)
+		if applicationID == "b-application" {
+			return errors.New("permission denied")
+		}
+		return nil
+	})
+
+	results, err := facade.ListResources(params.ListResourcesArgs{
+		Entities: []params.Entity{{
+			Tag: "application-a-application",
+		}, {
+			Tag: "application-b-application",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(results, jc.DeepEquals, params.ResourcesResults{
+		Results: []params.ResourcesResult{{}, {
+			ErrorResult: params.ErrorResult{Error: &params.Error{
+				Message: "permission denied",
+			}},
+		}},
+	})
+	s.stub.CheckCallNames(c, "ListResources")
+}
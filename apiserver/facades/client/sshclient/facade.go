@@ -8,12 +8,15 @@ package sshclient
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/network"
+	jujussh "github.com/juju/juju/network/ssh"
 	"github.com/juju/juju/permission"
 )
 
@@ -23,6 +26,7 @@ var logger = loggo.GetLogger("juju.apiserver.sshclient")
 type Facade struct {
 	backend    Backend
 	authorizer facade.Authorizer
+	clock      clock.Clock
 }
 
 // New returns a new API facade for the sshclient worker.
@@ -30,7 +34,7 @@ func New(backend Backend, _ facade.Resources, authorizer facade.Authorizer) (*Fa
 	if !authorizer.AuthClient() {
 		return nil, common.ErrPerm
 	}
-	return &Facade{backend: backend, authorizer: authorizer}, nil
+	return &Facade{backend: backend, authorizer: authorizer, clock: clock.WallClock}, nil
 }
 
 func (facade *Facade) checkIsModelAdmin() error {
@@ -190,6 +194,44 @@ func (facade *Facade) PublicKeys(args params.Entities) (params.SSHPublicKeysResu
 	return out, nil
 }
 
+// SignUserKey signs the given SSH public key with the controller's SSH
+// certificate authority, returning a short-lived certificate that the
+// model's machines accept for logging in as the ubuntu user. The
+// certificate's only principal is the model UUID, which is what the
+// model's machines list as authorized principals, so it cannot be used
+// on the machines of other models. Only model administrators may have
+// keys signed; since the certificate expires after a few minutes,
+// revoking a user's access soon stops them from using SSH.
+func (facade *Facade) SignUserKey(arg params.SSHSignUserKeyArg) (params.SSHSignUserKeyResult, error) {
+	if err := facade.checkIsModelAdmin(); err != nil {
+		return params.SSHSignUserKeyResult{}, errors.Trace(err)
+	}
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(arg.PublicKey))
+	if err != nil {
+		return params.SSHSignUserKeyResult{}, errors.NewNotValid(err, "invalid public key")
+	}
+	privateKey, err := facade.backend.SSHCAPrivateKey()
+	if err != nil {
+		return params.SSHSignUserKeyResult{}, errors.Trace(err)
+	}
+	ca, err := jujussh.ParseCertificateAuthority(privateKey, facade.clock)
+	if err != nil {
+		return params.SSHSignUserKeyResult{}, errors.Trace(err)
+	}
+	modelUUID := facade.backend.ModelTag().Id()
+	cert, err := ca.SignUserKey(jujussh.UserCertificateParams{
+		PublicKey:  publicKey,
+		KeyId:      facade.authorizer.GetAuthTag().Id() + "@" + modelUUID,
+		Principals: []string{modelUUID},
+	})
+	if err != nil {
+		return params.SSHSignUserKeyResult{}, errors.Trace(err)
+	}
+	return params.SSHSignUserKeyResult{
+		Certificate: string(ssh.MarshalAuthorizedKey(cert)),
+	}, nil
+}
+
 // Proxy returns whether SSH connections should be proxied through the
 // controller hosts for the model associated with the API connection.
 func (facade *Facade) Proxy() (params.SSHProxyResult, error) {
@@ -7,6 +7,8 @@ import (
 	"github.com/juju/errors"
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/ssh"
+	cryptossh "golang.org/x/crypto/ssh"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
@@ -28,6 +30,7 @@ type facadeSuite struct {
 	authorizer       *apiservertesting.FakeAuthorizer
 	facade           *sshclient.Facade
 	m0, uFoo, uOther string
+	caKey, userKey   string
 }
 
 var _ = gc.Suite(&facadeSuite{})
@@ -37,12 +40,18 @@ func (s *facadeSuite) SetUpSuite(c *gc.C) {
 	s.m0 = names.NewMachineTag("0").String()
 	s.uFoo = names.NewUnitTag("foo/0").String()
 	s.uOther = names.NewUnitTag("other/1").String()
+
+	var err error
+	s.caKey, _, err = ssh.GenerateKey("juju-ssh-ca")
+	c.Assert(err, jc.ErrorIsNil)
+	_, s.userKey, err = ssh.GenerateKey("igor@example.com")
+	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *facadeSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 
-	s.backend = new(mockBackend)
+	s.backend = &mockBackend{caKey: s.caKey}
 	s.authorizer = new(apiservertesting.FakeAuthorizer)
 	s.authorizer.Tag = names.NewUserTag("igor")
 	s.authorizer.AdminTag = names.NewUserTag("igor")
@@ -177,9 +186,49 @@ func (s *facadeSuite) TestProxyFalse(c *gc.C) {
 	})
 }
 
+func (s *facadeSuite) TestSignUserKey(c *gc.C) {
+	result, err := s.facade.SignUserKey(params.SSHSignUserKeyArg{PublicKey: s.userKey})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.stub.CheckCalls(c, []jujutesting.StubCall{
+		{"SSHCAPrivateKey", []interface{}{}},
+	})
+
+	pub, _, _, _, err := cryptossh.ParseAuthorizedKey([]byte(result.Certificate))
+	c.Assert(err, jc.ErrorIsNil)
+	cert, ok := pub.(*cryptossh.Certificate)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(cert.CertType, gc.Equals, uint32(cryptossh.UserCert))
+	c.Check(cert.KeyId, gc.Equals, "igor@deadbeef-2f18-4fd2-967d-db9663db7bea")
+	c.Check(cert.ValidPrincipals, jc.DeepEquals, []string{"deadbeef-2f18-4fd2-967d-db9663db7bea"})
+
+	caSigner, err := cryptossh.ParsePrivateKey([]byte(s.caKey))
+	c.Assert(err, jc.ErrorIsNil)
+	checker := &cryptossh.CertChecker{
+		IsUserAuthority: func(auth cryptossh.PublicKey) bool {
+			return string(auth.Marshal()) == string(caSigner.PublicKey().Marshal())
+		},
+	}
+	c.Assert(checker.CheckCert("deadbeef-2f18-4fd2-967d-db9663db7bea", cert), jc.ErrorIsNil)
+}
+
+func (s *facadeSuite) TestSignUserKeyInvalidKey(c *gc.C) {
+	_, err := s.facade.SignUserKey(params.SSHSignUserKeyArg{PublicKey: "rubbish"})
+	c.Assert(err, gc.ErrorMatches, "invalid public key: .*")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	s.backend.stub.CheckNoCalls(c)
+}
+
+func (s *facadeSuite) TestSignUserKeyNotModelAdmin(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bob")
+	_, err := s.facade.SignUserKey(params.SSHSignUserKeyArg{PublicKey: s.userKey})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	s.backend.stub.CheckNoCalls(c)
+}
+
 type mockBackend struct {
 	stub     jujutesting.Stub
 	proxySSH bool
+	caKey    string
 }
 
 func (backend *mockBackend) ModelTag() names.ModelTag {
@@ -235,6 +284,11 @@ func (backend *mockBackend) GetSSHHostKeys(tag names.MachineTag) (state.SSHHostK
 	return nil, errors.New("machine not found")
 }
 
+func (backend *mockBackend) SSHCAPrivateKey() (string, error) {
+	backend.stub.AddCall("SSHCAPrivateKey")
+	return backend.caKey, nil
+}
+
 func (backend *mockBackend) CloudSpec() (environs.CloudSpec, error) {
 	backend.stub.AddCall("CloudSpec")
 	return dummy.SampleCloudSpec(), nil
@@ -21,6 +21,7 @@ type Backend interface {
 	CloudSpec() (environs.CloudSpec, error)
 	GetMachineForEntity(tag string) (SSHMachine, error)
 	GetSSHHostKeys(names.MachineTag) (state.SSHHostKeys, error)
+	SSHCAPrivateKey() (string, error)
 	ModelTag() names.ModelTag
 }
 
@@ -0,0 +1,14 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/utils/clock"
+)
+
+// SetClock sets the clock used by the API to validate multi-factor
+// authentication codes.
+func SetClock(api *UserManagerAPI, clock clock.Clock) {
+	api.clock = clock
+}
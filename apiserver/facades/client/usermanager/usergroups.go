@@ -0,0 +1,357 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	jujucrossmodel "github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// AddUserGroups creates the named, empty, user groups. Only controller
+// superusers may create groups.
+func (api *UserManagerAPI) AddUserGroups(args params.UserGroupNames) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.checkCanManageUserGroups(); err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.ErrorResult, len(args.Names))
+	for i, name := range args.Names {
+		if _, err := api.state.AddUserGroup(name, api.apiUser.Id()); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// RemoveUserGroups removes the named user groups, along with all access
+// granted to them. Only controller superusers may remove groups.
+func (api *UserManagerAPI) RemoveUserGroups(args params.UserGroupNames) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.checkCanManageUserGroups(); err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.ErrorResult, len(args.Names))
+	for i, name := range args.Names {
+		if err := api.state.RemoveUserGroup(name); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// AddUserGroupMembers adds local users to user groups. Only controller
+// superusers may change group membership.
+func (api *UserManagerAPI) AddUserGroupMembers(args params.UserGroupMembers) (params.ErrorResults, error) {
+	return api.changeUserGroupMembers(args, (*state.UserGroup).AddMember)
+}
+
+// RemoveUserGroupMembers removes users from user groups. Only
+// controller superusers may change group membership.
+func (api *UserManagerAPI) RemoveUserGroupMembers(args params.UserGroupMembers) (params.ErrorResults, error) {
+	return api.changeUserGroupMembers(args, (*state.UserGroup).RemoveMember)
+}
+
+func (api *UserManagerAPI) changeUserGroupMembers(
+	args params.UserGroupMembers,
+	change func(*state.UserGroup, names.UserTag) error,
+) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.checkCanManageUserGroups(); err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.ErrorResult, len(args.Changes))
+	for i, arg := range args.Changes {
+		err := api.changeOneUserGroupMembers(arg, change)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) changeOneUserGroupMembers(
+	arg params.UserGroupMember,
+	change func(*state.UserGroup, names.UserTag) error,
+) error {
+	group, err := api.state.UserGroup(arg.Group)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, tag := range arg.UserTags {
+		userTag, err := names.ParseUserTag(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := change(group, userTag); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// AddUserGroupExternalGroups maps external identity provider groups to
+// user groups; external users who are members of a mapped group become
+// members of the user group when their membership is next synced. Only
+// controller superusers may change the mappings.
+func (api *UserManagerAPI) AddUserGroupExternalGroups(args params.UserGroupExternalGroups) (params.ErrorResults, error) {
+	return api.changeUserGroupExternalGroups(args, (*state.UserGroup).AddExternalGroup)
+}
+
+// RemoveUserGroupExternalGroups removes mappings of external identity
+// provider groups to user groups. Only controller superusers may
+// change the mappings.
+func (api *UserManagerAPI) RemoveUserGroupExternalGroups(args params.UserGroupExternalGroups) (params.ErrorResults, error) {
+	return api.changeUserGroupExternalGroups(args, (*state.UserGroup).RemoveExternalGroup)
+}
+
+func (api *UserManagerAPI) changeUserGroupExternalGroups(
+	args params.UserGroupExternalGroups,
+	change func(*state.UserGroup, string) error,
+) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.checkCanManageUserGroups(); err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.ErrorResult, len(args.Changes))
+	for i, arg := range args.Changes {
+		err := api.changeOneUserGroupExternalGroups(arg, change)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) changeOneUserGroupExternalGroups(
+	arg params.UserGroupExternalGroup,
+	change func(*state.UserGroup, string) error,
+) error {
+	group, err := api.state.UserGroup(arg.Group)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, externalGroup := range arg.ExternalGroups {
+		if err := change(group, externalGroup); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// UserGroupInfo returns information on the named user groups, or on
+// all groups if no names are given. Users who are not controller
+// superusers only see the groups they are a member of.
+func (api *UserManagerAPI) UserGroupInfo(args params.UserGroupNames) (params.UserGroupInfoResults, error) {
+	var result params.UserGroupInfoResults
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	visible := func(group *state.UserGroup) bool {
+		if isSuperUser {
+			return true
+		}
+		for _, member := range group.Members() {
+			if member == api.apiUser {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(args.Names) == 0 {
+		groups, err := api.state.AllUserGroups()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		for _, group := range groups {
+			if visible(group) {
+				result.Results = append(result.Results, params.UserGroupInfoResult{
+					Result: userGroupInfo(group),
+				})
+			}
+		}
+		return result, nil
+	}
+
+	result.Results = make([]params.UserGroupInfoResult, len(args.Names))
+	for i, name := range args.Names {
+		group, err := api.state.UserGroup(name)
+		if errors.IsNotFound(err) && !isSuperUser {
+			// Don't reveal which groups exist.
+			err = common.ErrPerm
+		}
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !visible(group) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		result.Results[i].Result = userGroupInfo(group)
+	}
+	return result, nil
+}
+
+func userGroupInfo(group *state.UserGroup) *params.UserGroupInfo {
+	info := &params.UserGroupInfo{
+		Name:        group.Name(),
+		Members:     []string{},
+		CreatedBy:   group.CreatedBy(),
+		DateCreated: group.DateCreated(),
+	}
+	for _, member := range group.Members() {
+		info.Members = append(info.Members, member.Id())
+	}
+	info.ExternalGroups = group.ExternalGroups()
+	return info
+}
+
+// ModifyUserGroupAccess grants access to, or revokes access from, user
+// groups on models, offers and the controller. Controller superusers
+// may change any access; model administrators may change access to
+// their models and the offers in them.
+func (api *UserManagerAPI) ModifyUserGroupAccess(args params.ModifyUserGroupAccessRequest) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if api.statePool == nil {
+		return result, errors.NotSupportedf("modifying user group access")
+	}
+	result.Results = make([]params.ErrorResult, len(args.Changes))
+	for i, arg := range args.Changes {
+		err := api.modifyOneUserGroupAccess(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) modifyOneUserGroupAccess(arg params.ModifyUserGroupAccess) error {
+	var (
+		target   names.Tag
+		modelTag names.ModelTag
+	)
+	if arg.OfferURL != "" {
+		url, err := jujucrossmodel.ParseOfferURL(arg.OfferURL)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		modelTag, err = api.modelForOfferURL(url)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		target = names.NewApplicationOfferTag(url.ApplicationName)
+	} else {
+		var err error
+		target, err = names.ParseTag(arg.TargetTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch target := target.(type) {
+		case names.ModelTag:
+			modelTag = target
+		case names.ControllerTag:
+			modelTag = names.NewModelTag(api.state.ControllerModelUUID())
+		default:
+			return errors.NotValidf("%s as a user group access target", names.ReadableString(target))
+		}
+	}
+
+	st, err := api.statePool.Get(modelTag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+
+	canModify, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canModify && target.Kind() != names.ControllerTagKind {
+		canModify, err = api.authorizer.HasPermission(permission.AdminAccess, modelTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if !canModify && target.Kind() == names.ApplicationOfferTagKind {
+		canModify, err = api.authorizer.HasPermission(permission.AdminAccess, target)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if !canModify {
+		return common.ErrPerm
+	}
+
+	access := permission.Access(arg.Access)
+	switch arg.Action {
+	case params.GrantUserGroupAccess:
+		return errors.Trace(st.SetUserGroupAccess(arg.Group, target, access))
+	case params.RevokeUserGroupAccess:
+		return errors.Trace(revokeUserGroupAccess(st.State, arg.Group, target, access))
+	default:
+		return errors.Errorf("unknown action %q", arg.Action)
+	}
+}
+
+// modelForOfferURL returns the tag of the model hosting the offer with
+// the given URL.
+func (api *UserManagerAPI) modelForOfferURL(url *jujucrossmodel.OfferURL) (names.ModelTag, error) {
+	owner := url.User
+	if owner == "" {
+		owner = api.apiUser.Id()
+	}
+	uuids, err := api.state.AllModelUUIDs()
+	if err != nil {
+		return names.ModelTag{}, errors.Trace(err)
+	}
+	for _, uuid := range uuids {
+		model, ph, err := api.statePool.GetModel(uuid)
+		if err != nil {
+			return names.ModelTag{}, errors.Trace(err)
+		}
+		found := model.Name() == url.ModelName && model.Owner().Id() == owner
+		ph.Release()
+		if found {
+			return names.NewModelTag(uuid), nil
+		}
+	}
+	return names.ModelTag{}, errors.NotFoundf("model %s/%s", owner, url.ModelName)
+}
+
+// lowerAccess holds, for each kind of target, the access each level of
+// access is reduced to when it is revoked.
+var lowerAccess = map[string]map[permission.Access]permission.Access{
+	names.ModelTagKind: {
+		permission.WriteAccess: permission.ReadAccess,
+		permission.AdminAccess: permission.WriteAccess,
+	},
+	names.ControllerTagKind: {
+		permission.AddModelAccess:  permission.LoginAccess,
+		permission.SuperuserAccess: permission.AddModelAccess,
+	},
+	names.ApplicationOfferTagKind: {
+		permission.ConsumeAccess: permission.ReadAccess,
+		permission.AdminAccess:   permission.ConsumeAccess,
+	},
+}
+
+// revokeUserGroupAccess revokes the given access from the group in the
+// same way as for users: revoking the lowest level of access removes
+// the group's access entirely, while revoking a higher level reduces
+// the group's access to the level below it.
+func revokeUserGroupAccess(st *state.State, group string, target names.Tag, access permission.Access) error {
+	reduced, ok := lowerAccess[target.Kind()][access]
+	if !ok {
+		return errors.Trace(st.RemoveUserGroupAccess(group, target))
+	}
+	if _, err := st.UserGroupAccess(group, target); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(st.SetUserGroupAccess(group, target, reduced))
+}
@@ -0,0 +1,240 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facade/facadetest"
+	"github.com/juju/juju/apiserver/facades/client/usermanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/testing/factory"
+)
+
+func (s *userManagerSuite) newAPIV5(c *gc.C, tag names.Tag) *usermanager.UserManagerAPI {
+	api, err := usermanager.NewUserManagerAPIV5(facadetest.Context{
+		State_:     s.State,
+		StatePool_: s.StatePool,
+		Resources_: s.resources,
+		Auth_:      apiservertesting.FakeAuthorizer{Tag: tag},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *userManagerSuite) TestAddRemoveUserGroups(c *gc.C) {
+	api := s.newAPIV5(c, s.AdminUserTag(c))
+	results, err := api.AddUserGroups(params.UserGroupNames{Names: []string{"devops", "qa"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+
+	groups, err := s.State.AllUserGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 2)
+	c.Assert(groups[0].CreatedBy(), gc.Equals, s.adminName)
+
+	results, err = api.RemoveUserGroups(params.UserGroupNames{Names: []string{"qa", "missing"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `group "missing" not found`)
+	groups, err = s.State.AllUserGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 1)
+	c.Assert(groups[0].Name(), gc.Equals, "devops")
+}
+
+func (s *userManagerSuite) TestAddUserGroupsNotSuperuser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	api := s.newAPIV5(c, alex.Tag())
+	_, err := api.AddUserGroups(params.UserGroupNames{Names: []string{"devops"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestUserGroupMembers(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb", NoModelUser: true})
+	_, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddUserGroup("qa", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.newAPIV5(c, s.AdminUserTag(c))
+	results, err := api.AddUserGroupMembers(params.UserGroupMembers{
+		Changes: []params.UserGroupMember{{
+			Group:    "devops",
+			UserTags: []string{alex.Tag().String(), barb.Tag().String()},
+		}, {
+			Group:    "qa",
+			UserTags: []string{alex.Tag().String()},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+
+	results, err = api.RemoveUserGroupMembers(params.UserGroupMembers{
+		Changes: []params.UserGroupMember{{
+			Group:    "devops",
+			UserTags: []string{barb.Tag().String()},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+
+	// Users only see the groups they are a member of.
+	info, err := s.newAPIV5(c, alex.Tag()).UserGroupInfo(params.UserGroupNames{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results, gc.HasLen, 2)
+	c.Assert(info.Results[0].Result.Name, gc.Equals, "devops")
+	c.Assert(info.Results[0].Result.Members, jc.DeepEquals, []string{"alex"})
+
+	info, err = s.newAPIV5(c, barb.Tag()).UserGroupInfo(params.UserGroupNames{
+		Names: []string{"devops", "missing"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results, gc.HasLen, 2)
+	c.Assert(info.Results[0].Error, gc.ErrorMatches, "permission denied")
+	c.Assert(info.Results[1].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestUserGroupExternalGroups(c *gc.C) {
+	_, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.newAPIV5(c, s.AdminUserTag(c))
+	results, err := api.AddUserGroupExternalGroups(params.UserGroupExternalGroups{
+		Changes: []params.UserGroupExternalGroup{{
+			Group:          "devops",
+			ExternalGroups: []string{"sre@ldap", "ops@oidc"},
+		}, {
+			Group:          "devops",
+			ExternalGroups: []string{"sre"},
+		}, {
+			Group:          "missing",
+			ExternalGroups: []string{"sre@ldap"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `external group "sre" without domain not valid`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `group "missing" not found`)
+
+	results, err = api.RemoveUserGroupExternalGroups(params.UserGroupExternalGroups{
+		Changes: []params.UserGroupExternalGroup{{
+			Group:          "devops",
+			ExternalGroups: []string{"ops@oidc"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+
+	info, err := api.UserGroupInfo(params.UserGroupNames{Names: []string{"devops"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results[0].Result.ExternalGroups, jc.DeepEquals, []string{"sre@ldap"})
+
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	_, err = s.newAPIV5(c, alex.Tag()).AddUserGroupExternalGroups(params.UserGroupExternalGroups{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestUserInfoIncludesGroups(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true, Access: permission.LoginAccess})
+	group, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.AddMember(alex.UserTag()), jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("devops", s.State.ControllerTag(), permission.AddModelAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.usermanager.UserInfo(params.UserInfoRequest{
+		Entities: []params.Entity{{Tag: alex.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Groups, jc.DeepEquals, []string{"devops"})
+	c.Assert(results.Results[0].Result.Access, gc.Equals, "add-model")
+}
+
+func (s *userManagerSuite) TestModifyUserGroupAccess(c *gc.C) {
+	_, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	modelTag := s.IAASModel.ModelTag()
+
+	api := s.newAPIV5(c, s.AdminUserTag(c))
+	results, err := api.ModifyUserGroupAccess(params.ModifyUserGroupAccessRequest{
+		Changes: []params.ModifyUserGroupAccess{{
+			Group:     "devops",
+			Action:    params.GrantUserGroupAccess,
+			Access:    string(permission.AdminAccess),
+			TargetTag: modelTag.String(),
+		}, {
+			Group:     "devops",
+			Action:    params.GrantUserGroupAccess,
+			Access:    string(permission.AddModelAccess),
+			TargetTag: s.State.ControllerTag().String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+
+	// Revoking admin access leaves write access; revoking add-model
+	// leaves login.
+	results, err = api.ModifyUserGroupAccess(params.ModifyUserGroupAccessRequest{
+		Changes: []params.ModifyUserGroupAccess{{
+			Group:     "devops",
+			Action:    params.RevokeUserGroupAccess,
+			Access:    string(permission.AdminAccess),
+			TargetTag: modelTag.String(),
+		}, {
+			Group:     "devops",
+			Action:    params.RevokeUserGroupAccess,
+			Access:    string(permission.AddModelAccess),
+			TargetTag: s.State.ControllerTag().String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+	access, err := s.State.UserGroupAccess("devops", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+	access, err = s.State.UserGroupAccess("devops", s.State.ControllerTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.LoginAccess)
+
+	// Revoking read access removes the grant.
+	results, err = api.ModifyUserGroupAccess(params.ModifyUserGroupAccessRequest{
+		Changes: []params.ModifyUserGroupAccess{{
+			Group:     "devops",
+			Action:    params.RevokeUserGroupAccess,
+			Access:    string(permission.ReadAccess),
+			TargetTag: modelTag.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+	_, err = s.State.UserGroupAccess("devops", modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestModifyUserGroupAccessNotAdmin(c *gc.C) {
+	_, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+
+	results, err := s.newAPIV5(c, alex.Tag()).ModifyUserGroupAccess(params.ModifyUserGroupAccessRequest{
+		Changes: []params.ModifyUserGroupAccess{{
+			Group:     "devops",
+			Action:    params.GrantUserGroupAccess,
+			Access:    string(permission.SuperuserAccess),
+			TargetTag: s.State.ControllerTag().String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, "permission denied")
+}
@@ -8,8 +8,11 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/authentication/totp"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
@@ -27,6 +30,12 @@ type UserManagerAPI struct {
 	check      *common.BlockChecker
 	apiUser    names.UserTag
 	isAdmin    bool
+	clock      clock.Clock
+
+	// statePool is used to reach the models that hold offers when
+	// changing the access granted to user groups. It is nil for
+	// facade versions before 5.
+	statePool *state.StatePool
 }
 
 // NewUserManagerAPI provides the signature required for facade registration.
@@ -55,9 +64,21 @@ func NewUserManagerAPI(
 		check:      common.NewBlockChecker(st),
 		apiUser:    apiUser,
 		isAdmin:    isAdmin,
+		clock:      clock.WallClock,
 	}, nil
 }
 
+// NewUserManagerAPIV5 returns a user manager API that also manages
+// user groups.
+func NewUserManagerAPIV5(ctx facade.Context) (*UserManagerAPI, error) {
+	api, err := NewUserManagerAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	api.statePool = ctx.StatePool()
+	return api, nil
+}
+
 func (api *UserManagerAPI) hasControllerAdminAccess() (bool, error) {
 	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
 	if errors.IsNotFound(err) {
@@ -66,6 +87,22 @@ func (api *UserManagerAPI) hasControllerAdminAccess() (bool, error) {
 	return isAdmin, err
 }
 
+// checkCanManageUserGroups returns an error if the user groups on the
+// controller may not be changed by the authenticated user.
+func (api *UserManagerAPI) checkCanManageUserGroups() error {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperUser {
+		return common.ErrPerm
+	}
+	return nil
+}
+
 // AddUser adds a user with a username, and either a password or
 // a randomly generated secret key which will be returned.
 func (api *UserManagerAPI) AddUser(args params.AddUsers) (params.AddUserResults, error) {
@@ -257,13 +294,24 @@ func (api *UserManagerAPI) UserInfo(request params.UserInfoRequest) (params.User
 	}
 
 	var accessForUser = func(userTag names.UserTag, result *params.UserInfoResult) {
-		// Lookup the access the specified user has to the controller.
-		access, err := common.GetPermission(api.state.UserPermission, userTag, api.state.ControllerTag())
+		// Lookup the access the specified user has to the controller,
+		// including any access inherited from their groups.
+		access, err := common.GetPermission(api.state.EffectiveUserPermission, userTag, api.state.ControllerTag())
 		if err == nil {
 			result.Result.Access = string(access)
 		} else if err != nil && !errors.IsNotFound(err) {
 			result.Result = nil
 			result.Error = common.ServerError(err)
+			return
+		}
+		groups, err := api.state.UserGroupsForUser(userTag)
+		if err != nil {
+			result.Result = nil
+			result.Error = common.ServerError(err)
+			return
+		}
+		for _, group := range groups {
+			result.Result.Groups = append(result.Result.Groups, group.Name())
 		}
 	}
 
@@ -285,6 +333,7 @@ func (api *UserManagerAPI) UserInfo(request params.UserInfoRequest) (params.User
 				DateCreated:    user.DateCreated(),
 				LastConnection: lastLogin,
 				Disabled:       user.IsDisabled(),
+				Locked:         user.IsLocked(),
 			},
 		}
 		accessForUser(user.UserTag(), &result)
@@ -425,3 +474,230 @@ func (api *UserManagerAPI) ResetPassword(args params.Entities) (params.AddUserRe
 	}
 	return result, nil
 }
+
+// AddAPITokens creates API tokens owned by the calling user. The value
+// of each token is returned; it cannot be retrieved again.
+func (api *UserManagerAPI) AddAPITokens(args params.AddAPITokens) (params.AddAPITokenResults, error) {
+	var result params.AddAPITokenResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.AddAPITokenResult, len(args.Tokens))
+	for i, arg := range args.Tokens {
+		value, err := api.addAPIToken(arg)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Token = value
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) addAPIToken(arg params.AddAPIToken) (string, error) {
+	spec := state.APITokenSpec{
+		Name:     arg.Name,
+		Owner:    api.apiUser,
+		Expires:  arg.Expires,
+		Facades:  arg.Facades,
+		ReadOnly: arg.ReadOnly,
+	}
+	for _, m := range arg.Models {
+		tag, err := names.ParseModelTag(m)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		spec.Models = append(spec.Models, tag)
+	}
+	if loginToken := api.authorizer.APIToken(); loginToken != nil {
+		if err := checkAPITokenWithin(spec, loginToken); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	_, value, err := api.state.AddAPIToken(spec)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return value, nil
+}
+
+// checkAPITokenWithin returns an error if the token described by spec
+// would allow more than the token the user logged in with, so that a
+// token cannot be used to create a token with a wider scope.
+func checkAPITokenWithin(spec state.APITokenSpec, loginToken *state.APIToken) error {
+	if spec.Expires.After(loginToken.Expires()) {
+		return errors.Annotatef(common.ErrPerm, "token expiring after the login token")
+	}
+	if loginToken.ReadOnly() && !spec.ReadOnly {
+		return errors.Annotatef(common.ErrPerm, "read-write token created with a read-only login token")
+	}
+	if models := loginToken.Models(); len(models) > 0 {
+		allowed := set.NewStrings()
+		for _, m := range models {
+			allowed.Add(m.Id())
+		}
+		if len(spec.Models) == 0 {
+			return errors.Annotatef(common.ErrPerm, "token for all models created with a login token limited to models")
+		}
+		for _, m := range spec.Models {
+			if !allowed.Contains(m.Id()) {
+				return errors.Annotatef(common.ErrPerm, "token for model %q not allowed by the login token", m.Id())
+			}
+		}
+	}
+	if facades := set.NewStrings(loginToken.Facades()...); !facades.IsEmpty() {
+		if len(spec.Facades) == 0 {
+			return errors.Annotatef(common.ErrPerm, "token for all facades created with a login token limited to facades")
+		}
+		for _, f := range spec.Facades {
+			if !facades.Contains(f) {
+				return errors.Annotatef(common.ErrPerm, "token for facade %q not allowed by the login token", f)
+			}
+		}
+	}
+	return nil
+}
+
+// APITokens returns information on the API tokens owned by the
+// calling user.
+func (api *UserManagerAPI) APITokens() (params.APITokenInfoResults, error) {
+	var result params.APITokenInfoResults
+	tokens, err := api.state.APITokensForUser(api.apiUser)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.APITokenInfo, len(tokens))
+	for i, token := range tokens {
+		info := params.APITokenInfo{
+			Name:        token.Name(),
+			Facades:     token.Facades(),
+			ReadOnly:    token.ReadOnly(),
+			DateCreated: token.DateCreated(),
+			Expires:     token.Expires(),
+		}
+		for _, m := range token.Models() {
+			info.Models = append(info.Models, m.String())
+		}
+		result.Results[i] = info
+	}
+	return result, nil
+}
+
+// RevokeAPITokens revokes the named API tokens owned by the calling
+// user. Revocation is not subject to blocks, so that a leaked token
+// can always be disabled.
+func (api *UserManagerAPI) RevokeAPITokens(args params.RevokeAPITokens) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	result.Results = make([]params.ErrorResult, len(args.Names))
+	for i, name := range args.Names {
+		if err := api.state.RevokeAPIToken(api.apiUser, name); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// mfaIssuer identifies the controller in users' authenticator
+// applications.
+const mfaIssuer = "juju"
+
+// EnableMFA starts enrolling the calling user for multi-factor
+// authentication, returning a new secret for them to add to their
+// authenticator application. Multi-factor authentication is not
+// enabled until the user confirms they hold the secret by calling
+// ConfirmMFA.
+func (api *UserManagerAPI) EnableMFA() (params.MFAEnrolment, error) {
+	var result params.MFAEnrolment
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	user, err := api.mfaUser()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if user.MFAEnabled() {
+		return result, errors.AlreadyExistsf("multi-factor authentication for %q", user.Name())
+	}
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := user.SetMFASecret(secret); err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Secret = secret
+	result.URI = totp.URI(mfaIssuer, user.Name(), secret)
+	return result, nil
+}
+
+// ConfirmMFA enables multi-factor authentication for the calling
+// user, once they have provided a valid code generated from the
+// secret returned by EnableMFA.
+func (api *UserManagerAPI) ConfirmMFA(arg params.MFACode) error {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	user, err := api.mfaUser()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if user.MFASecret() == "" {
+		return errors.New(`multi-factor authentication enrolment not started; run "juju enable-mfa"`)
+	}
+	counter, err := totp.Validate(user.MFASecret(), arg.Code, api.clock.Now())
+	if err != nil {
+		return errors.Annotate(err, "cannot confirm multi-factor authentication")
+	}
+	return errors.Trace(user.ConfirmMFA(counter))
+}
+
+func (api *UserManagerAPI) mfaUser() (*state.User, error) {
+	if !api.apiUser.IsLocal() {
+		return nil, errors.NotSupportedf("multi-factor authentication for external users")
+	}
+	return api.state.User(api.apiUser)
+}
+
+// DisableMFA disables multi-factor authentication for the specified
+// users. Users may disable it for themselves, given a valid code;
+// controller superusers may disable it for anyone else without one,
+// for example after a user loses access to their authenticator
+// application.
+func (api *UserManagerAPI) DisableMFA(args params.DisableMFAArgs) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.ErrorResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		if err := api.disableMFA(arg); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) disableMFA(arg params.DisableMFAArg) error {
+	user, err := api.getUser(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if api.apiUser != user.UserTag() {
+		if !api.isAdmin {
+			return common.ErrPerm
+		}
+		return errors.Trace(user.DisableMFA())
+	}
+	// Someone holding just a user's login must not be able to
+	// remove the second factor protecting their password.
+	if user.MFAEnabled() {
+		counter, err := totp.Validate(user.MFASecret(), arg.Code, api.clock.Now())
+		if err == nil {
+			err = user.UseMFACounter(counter)
+		}
+		if err != nil {
+			return errors.Annotate(err, "cannot disable multi-factor authentication")
+		}
+	}
+	return errors.Trace(user.DisableMFA())
+}
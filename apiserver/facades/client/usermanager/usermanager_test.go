@@ -8,10 +8,12 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/authentication/totp"
 	"github.com/juju/juju/apiserver/common"
 	commontesting "github.com/juju/juju/apiserver/common/testing"
 	"github.com/juju/juju/apiserver/facades/client/controller"
@@ -534,6 +536,28 @@ func (s *userManagerSuite) TestUserInfoEveryonePermission(c *gc.C) {
 	})
 }
 
+func (s *userManagerSuite) TestUserInfoLocked(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	err := user.RecordFailedLogin(1, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.UserInfoRequest{Entities: []params.Entity{{Tag: user.Tag().String()}}}
+	results, err := s.usermanager.UserInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Locked, jc.IsTrue)
+
+	enableArgs := params.Entities{Entities: []params.Entity{{Tag: user.Tag().String()}}}
+	enableResults, err := s.usermanager.EnableUser(enableArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enableResults.OneError(), jc.ErrorIsNil)
+
+	results, err = s.usermanager.UserInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Result.Locked, jc.IsFalse)
+}
+
 func lastLoginPointer(c *gc.C, user *state.User) *time.Time {
 	lastLogin, err := user.LastLogin()
 	if err != nil {
@@ -990,3 +1014,223 @@ func (s *userManagerSuite) TestResetPasswordEmpty(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results.Results, gc.HasLen, 0)
 }
+
+func (s *userManagerSuite) TestAPITokens(c *gc.C) {
+	expires := time.Now().Add(time.Hour).Round(time.Second).UTC()
+	result, err := s.usermanager.AddAPITokens(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			Name:     "ci",
+			Expires:  expires,
+			Models:   []string{s.Model.ModelTag().String()},
+			Facades:  []string{"Application"},
+			ReadOnly: true,
+		}, {
+			Name:    "bad name",
+			Expires: expires,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Token, gc.Not(gc.Equals), "")
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `token name "bad name" not valid`)
+
+	id, _, err := state.ParseAPIToken(result.Results[0].Token)
+	c.Assert(err, jc.ErrorIsNil)
+	token, err := s.State.APIToken(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Owner(), gc.Equals, s.AdminUserTag(c))
+
+	infos, err := s.usermanager.APITokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos.Results, gc.HasLen, 1)
+	info := infos.Results[0]
+	c.Assert(info.DateCreated, gc.Not(gc.Equals), time.Time{})
+	info.DateCreated = time.Time{}
+	c.Assert(info, jc.DeepEquals, params.APITokenInfo{
+		Name:     "ci",
+		Models:   []string{s.Model.ModelTag().String()},
+		Facades:  []string{"Application"},
+		ReadOnly: true,
+		Expires:  expires,
+	})
+
+	revoked, err := s.usermanager.RevokeAPITokens(params.RevokeAPITokens{
+		Names: []string{"ci", "ci"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revoked.Results, gc.HasLen, 2)
+	c.Assert(revoked.Results[0].Error, gc.IsNil)
+	c.Assert(revoked.Results[1].Error, gc.ErrorMatches, `token "ci" for "admin" not found`)
+
+	infos, err = s.usermanager.APITokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos.Results, gc.HasLen, 0)
+}
+
+func (s *userManagerSuite) TestAddAPITokensWithinLoginToken(c *gc.C) {
+	expires := time.Now().Add(time.Hour).Round(time.Second)
+	model := s.Model.ModelTag().String()
+	loginToken, _, err := s.State.AddAPIToken(state.APITokenSpec{
+		Name:     "ci",
+		Owner:    s.AdminUserTag(c),
+		Expires:  expires,
+		Models:   []names.ModelTag{s.Model.ModelTag()},
+		Facades:  []string{"Application", "Client"},
+		ReadOnly: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.authorizer.Token = loginToken
+	api, err := usermanager.NewUserManagerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := api.AddAPITokens(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			Name:     "narrower",
+			Expires:  expires,
+			Models:   []string{model},
+			Facades:  []string{"Client"},
+			ReadOnly: true,
+		}, {
+			Name:     "later",
+			Expires:  expires.Add(time.Second),
+			Models:   []string{model},
+			Facades:  []string{"Client"},
+			ReadOnly: true,
+		}, {
+			Name:    "read-write",
+			Expires: expires,
+			Models:  []string{model},
+			Facades: []string{"Client"},
+		}, {
+			Name:     "all-models",
+			Expires:  expires,
+			Facades:  []string{"Client"},
+			ReadOnly: true,
+		}, {
+			Name:     "all-facades",
+			Expires:  expires,
+			Models:   []string{model},
+			ReadOnly: true,
+		}, {
+			Name:     "other-facade",
+			Expires:  expires,
+			Models:   []string{model},
+			Facades:  []string{"UserManager"},
+			ReadOnly: true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 6)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "token expiring after the login token: permission denied")
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, "read-write token created with a read-only login token: permission denied")
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, "token for all models created with a login token limited to models: permission denied")
+	c.Assert(result.Results[4].Error, gc.ErrorMatches, "token for all facades created with a login token limited to facades: permission denied")
+	c.Assert(result.Results[5].Error, gc.ErrorMatches, `token for facade "UserManager" not allowed by the login token: permission denied`)
+}
+
+func (s *userManagerSuite) TestAddAPITokensBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestAddAPITokensBlocked")
+	_, err := s.usermanager.AddAPITokens(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{Name: "ci", Expires: time.Now().Add(time.Hour)}},
+	})
+	s.AssertBlocked(c, err, "TestAddAPITokensBlocked")
+}
+
+func (s *userManagerSuite) TestEnableMFA(c *gc.C) {
+	clock := testing.NewClock(time.Now().Add(time.Hour))
+	usermanager.SetClock(s.usermanager, clock)
+	enrolment, err := s.usermanager.EnableMFA()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enrolment.Secret, gc.Not(gc.Equals), "")
+	c.Assert(enrolment.URI, gc.Matches, "otpauth://totp/juju:admin\\?.*secret="+enrolment.Secret+".*")
+
+	user, err := s.State.User(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+
+	err = s.usermanager.ConfirmMFA(params.MFACode{Code: "000000x"})
+	c.Assert(err, gc.ErrorMatches, "cannot confirm multi-factor authentication: code not valid")
+
+	// Codes are validated against the API's clock.
+	code, err := totp.Code(enrolment.Secret, totp.Counter(time.Now()))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.ConfirmMFA(params.MFACode{Code: code})
+	c.Assert(err, gc.ErrorMatches, "cannot confirm multi-factor authentication: code not valid")
+	code, err = totp.Code(enrolment.Secret, totp.Counter(clock.Now()))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.ConfirmMFA(params.MFACode{Code: code})
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsTrue)
+
+	_, err = s.usermanager.EnableMFA()
+	c.Assert(err, gc.ErrorMatches, `multi-factor authentication for "admin" already exists`)
+}
+
+func (s *userManagerSuite) TestConfirmMFANotStarted(c *gc.C) {
+	err := s.usermanager.ConfirmMFA(params.MFACode{Code: "123456"})
+	c.Assert(err, gc.ErrorMatches, "multi-factor authentication enrolment not started.*")
+}
+
+func (s *userManagerSuite) TestConfirmMFABlocked(c *gc.C) {
+	_, err := s.usermanager.EnableMFA()
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockAllChanges(c, "TestConfirmMFABlocked")
+	err = s.usermanager.ConfirmMFA(params.MFACode{Code: "123456"})
+	s.AssertBlocked(c, err, "TestConfirmMFABlocked")
+}
+
+func (s *userManagerSuite) TestDisableMFA(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb", NoModelUser: true})
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, user := range []*state.User{alex, barb} {
+		err := user.SetMFASecret(secret)
+		c.Assert(err, jc.ErrorIsNil)
+		err = user.ConfirmMFA(1)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	api, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	clock := testing.NewClock(time.Now())
+	usermanager.SetClock(api, clock)
+
+	// Users must provide a valid code to disable it for themselves.
+	results, err := api.DisableMFA(params.DisableMFAArgs{
+		Entities: []params.DisableMFAArg{{Tag: alex.Tag().String(), Code: "000000x"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, "cannot disable multi-factor authentication: code not valid")
+
+	code, err := totp.Code(secret, totp.Counter(clock.Now()))
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = api.DisableMFA(params.DisableMFAArgs{
+		Entities: []params.DisableMFAArg{
+			{Tag: alex.Tag().String(), Code: code},
+			{Tag: barb.Tag().String(), Code: code},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.MFAEnabled(), jc.IsFalse)
+
+	// Superusers may disable multi-factor authentication for others.
+	results, err = s.usermanager.DisableMFA(params.DisableMFAArgs{
+		Entities: []params.DisableMFAArg{{Tag: barb.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	err = barb.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(barb.MFAEnabled(), jc.IsFalse)
+}
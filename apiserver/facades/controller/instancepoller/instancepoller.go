@@ -45,6 +45,30 @@ func NewFacade(
 	return NewInstancePollerAPI(st, m, resources, authorizer, clock.WallClock)
 }
 
+// InstancePollerAPIV4 provides access to version 4 of the
+// InstancePoller API facade, which adds the methods used to recover
+// lost machines.
+type InstancePollerAPIV4 struct {
+	*InstancePollerAPI
+	*common.StatusSetter
+}
+
+// NewFacadeV4 wraps NewInstancePollerAPI for facade registration.
+func NewFacadeV4(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*InstancePollerAPIV4, error) {
+	api, err := NewFacade(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// SetStatus() is supported for machines, so that the
+	// failure to recover them can be reported.
+	statusSetter := common.NewStatusSetter(api.st, api.accessMachine)
+	return &InstancePollerAPIV4{api, statusSetter}, nil
+}
+
 // NewInstancePollerAPI creates a new server-side InstancePoller API
 // facade.
 func NewInstancePollerAPI(
@@ -245,3 +269,78 @@ func (a *InstancePollerAPI) AreManuallyProvisioned(args params.Entities) (params
 	}
 	return result, nil
 }
+
+// AgentPresence returns whether the agent of each given entity is
+// connected to the controller. Only machine tags are accepted.
+func (a *InstancePollerAPIV4) AgentPresence(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := a.accessMachine()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		machine, err := a.getOneMachine(arg.Tag, canAccess)
+		if err == nil {
+			result.Results[i].Result, err = machine.AgentPresence()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ReplaceMachines replaces each given machine with a new machine,
+// moving its units to the replacement, and then forcibly destroys
+// the original machine. The ID of each replacement is returned.
+// Only machine tags are accepted.
+func (a *InstancePollerAPIV4) ReplaceMachines(args params.Entities) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	canAccess, err := a.accessMachine()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		machine, err := a.getOneMachine(arg.Tag, canAccess)
+		if err == nil {
+			var replacement StateMachine
+			replacement, err = a.st.ReplaceMachine(machine.Id())
+			if err == nil {
+				result.Results[i].Result = replacement.Id()
+				// The original machine's agent has been lost,
+				// so it cannot be relied upon to clean up.
+				if err = machine.ForceDestroy(); err != nil {
+					err = errors.Annotatef(err,
+						"destroying machine %v after replacing it with machine %v",
+						machine.Id(), replacement.Id(),
+					)
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// DestroyMachines forcibly destroys each given machine. It is used to
+// retry destroying machines that ReplaceMachines replaced but could
+// not destroy. Only machine tags are accepted.
+func (a *InstancePollerAPIV4) DestroyMachines(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := a.accessMachine()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		machine, err := a.getOneMachine(arg.Tag, canAccess)
+		if err == nil {
+			err = machine.ForceDestroy()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
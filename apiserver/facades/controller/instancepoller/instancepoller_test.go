@@ -712,3 +712,120 @@ func (s *InstancePollerSuite) TestAreManuallyProvisionedFailure(c *gc.C) {
 func statusInfo(st string) status.StatusInfo {
 	return status.StatusInfo{Status: status.Status(st)}
 }
+
+func (s *InstancePollerSuite) TestAgentPresence(c *gc.C) {
+	s.st.SetMachineInfo(c, machineInfo{id: "1", agentPresence: true})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", agentPresence: false})
+
+	api := &instancepoller.InstancePollerAPIV4{InstancePollerAPI: s.api}
+	result, err := api.AgentPresence(s.mixedEntities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: true},
+			{Result: false},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ServerError(`"application-unknown" is not a valid machine tag`)},
+			{Error: apiservertesting.ServerError(`"invalid-tag" is not a valid tag`)},
+			{Error: apiservertesting.ServerError(`"unit-missing-1" is not a valid machine tag`)},
+			{Error: apiservertesting.ServerError(`"" is not a valid tag`)},
+			{Error: apiservertesting.ServerError(`"42" is not a valid tag`)},
+		}},
+	)
+
+	s.st.CheckFindEntityCall(c, 0, "1")
+	s.st.CheckCall(c, 1, "AgentPresence")
+	s.st.CheckFindEntityCall(c, 2, "2")
+	s.st.CheckCall(c, 3, "AgentPresence")
+	s.st.CheckFindEntityCall(c, 4, "42")
+}
+
+func (s *InstancePollerSuite) TestReplaceMachines(c *gc.C) {
+	s.st.SetMachineInfo(c, machineInfo{id: "1", life: state.Alive})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", life: state.Alive})
+
+	api := &instancepoller.InstancePollerAPIV4{InstancePollerAPI: s.api}
+	result, err := api.ReplaceMachines(s.mixedEntities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: "100"},
+			{Result: "101"},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ServerError(`"application-unknown" is not a valid machine tag`)},
+			{Error: apiservertesting.ServerError(`"invalid-tag" is not a valid tag`)},
+			{Error: apiservertesting.ServerError(`"unit-missing-1" is not a valid machine tag`)},
+			{Error: apiservertesting.ServerError(`"" is not a valid tag`)},
+			{Error: apiservertesting.ServerError(`"42" is not a valid tag`)},
+		}},
+	)
+
+	s.st.CheckFindEntityCall(c, 0, "1")
+	s.st.CheckCall(c, 1, "ReplaceMachine", "1")
+	s.st.CheckCall(c, 2, "ForceDestroy")
+	s.st.CheckFindEntityCall(c, 3, "2")
+	s.st.CheckCall(c, 4, "ReplaceMachine", "2")
+	s.st.CheckCall(c, 5, "ForceDestroy")
+	s.st.CheckFindEntityCall(c, 6, "42")
+}
+
+func (s *InstancePollerSuite) TestReplaceMachinesFailure(c *gc.C) {
+	s.st.SetErrors(
+		errors.New("pow!"), // m1 := FindEntity("1")
+		nil,                // m2 := FindEntity("2")
+		nil,                // ReplaceMachine("2")
+		errors.New("FAIL"), // m2.ForceDestroy()
+		nil,                // m3 := FindEntity("3")
+		errors.New("nope"), // ReplaceMachine("3")
+	)
+	s.st.SetMachineInfo(c, machineInfo{id: "1", life: state.Alive})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", life: state.Alive})
+	s.st.SetMachineInfo(c, machineInfo{id: "3", life: state.Alive})
+
+	api := &instancepoller.InstancePollerAPIV4{InstancePollerAPI: s.api}
+	result, err := api.ReplaceMachines(s.machineEntities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Error: apiservertesting.ServerError("pow!")},
+			{
+				Result: "100",
+				Error:  apiservertesting.ServerError("destroying machine 2 after replacing it with machine 100: FAIL"),
+			},
+			{Error: apiservertesting.ServerError("nope")},
+		}},
+	)
+}
+
+func (s *InstancePollerSuite) TestDestroyMachines(c *gc.C) {
+	s.st.SetErrors(
+		nil,                // m1 := FindEntity("1")
+		nil,                // m1.ForceDestroy()
+		nil,                // m2 := FindEntity("2")
+		errors.New("FAIL"), // m2.ForceDestroy()
+	)
+	s.st.SetMachineInfo(c, machineInfo{id: "1", life: state.Alive})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", life: state.Alive})
+
+	api := &instancepoller.InstancePollerAPIV4{InstancePollerAPI: s.api}
+	result, err := api.DestroyMachines(s.mixedEntities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ServerError("FAIL")},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ServerError(`"application-unknown" is not a valid machine tag`)},
+			{Error: apiservertesting.ServerError(`"invalid-tag" is not a valid tag`)},
+			{Error: apiservertesting.ServerError(`"unit-missing-1" is not a valid machine tag`)},
+			{Error: apiservertesting.ServerError(`"" is not a valid tag`)},
+			{Error: apiservertesting.ServerError(`"42" is not a valid tag`)},
+		}},
+	)
+
+	s.st.CheckFindEntityCall(c, 0, "1")
+	s.st.CheckCall(c, 1, "ForceDestroy")
+	s.st.CheckFindEntityCall(c, 2, "2")
+	s.st.CheckCall(c, 3, "ForceDestroy")
+	s.st.CheckFindEntityCall(c, 4, "42")
+}
@@ -5,6 +5,7 @@ package instancepoller_test
 
 import (
 	"sort"
+	"strconv"
 	"sync"
 
 	"github.com/juju/errors"
@@ -32,12 +33,17 @@ type mockState struct {
 
 	config   *config.Config
 	machines map[string]*mockMachine
+
+	// nextMachineId is the ID given to the next machine added
+	// by ReplaceMachine.
+	nextMachineId int
 }
 
 func NewMockState() *mockState {
 	return &mockState{
-		Stub:     &testing.Stub{},
-		machines: make(map[string]*mockMachine),
+		Stub:          &testing.Stub{},
+		machines:      make(map[string]*mockMachine),
+		nextMachineId: 100,
 	}
 }
 
@@ -200,6 +206,31 @@ func (m *mockState) Machine(id string) (instancepoller.StateMachine, error) {
 	return machine, nil
 }
 
+// ReplaceMachine implements StateInterface.
+func (m *mockState) ReplaceMachine(id string) (instancepoller.StateMachine, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MethodCall(m, "ReplaceMachine", id)
+
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	if _, found := m.machines[id]; !found {
+		return nil, errors.NotFoundf("machine %s", id)
+	}
+	replacement := &mockMachine{
+		Stub: m.Stub,
+		machineInfo: machineInfo{
+			id:   strconv.Itoa(m.nextMachineId),
+			life: state.Alive,
+		},
+	}
+	m.nextMachineId++
+	m.machines[replacement.id] = replacement
+	return replacement, nil
+}
+
 // StartSync implements statetesting.SyncStarter, so mockState can be
 // used with watcher helpers/checkers.
 func (m *mockState) StartSync() {}
@@ -212,6 +243,7 @@ type machineInfo struct {
 	providerAddresses []network.Address
 	life              state.Life
 	isManual          bool
+	agentPresence     bool
 }
 
 type mockMachine struct {
@@ -225,6 +257,14 @@ type mockMachine struct {
 
 var _ instancepoller.StateMachine = (*mockMachine)(nil)
 
+// Id implements StateMachine.
+func (m *mockMachine) Id() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.id
+}
+
 // InstanceId implements StateMachine.
 func (m *mockMachine) InstanceId() (instance.Id, error) {
 	m.mu.Lock()
@@ -317,6 +357,28 @@ func (m *mockMachine) Status() (status.StatusInfo, error) {
 	return m.status, m.NextErr()
 }
 
+// AgentPresence implements StateMachine.
+func (m *mockMachine) AgentPresence() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MethodCall(m, "AgentPresence")
+	return m.agentPresence, m.NextErr()
+}
+
+// ForceDestroy implements StateMachine.
+func (m *mockMachine) ForceDestroy() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MethodCall(m, "ForceDestroy")
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	m.life = state.Dying
+	return nil
+}
+
 type mockBaseWatcher struct {
 	err error
 
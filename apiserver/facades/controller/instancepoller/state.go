@@ -26,6 +26,8 @@ type StateMachine interface {
 	Life() state.Life
 	Status() (status.StatusInfo, error)
 	IsManual() (bool, error)
+	AgentPresence() (bool, error)
+	ForceDestroy() error
 }
 
 type StateInterface interface {
@@ -34,6 +36,7 @@ type StateInterface interface {
 	state.EntityFinder
 
 	Machine(id string) (StateMachine, error)
+	ReplaceMachine(id string) (StateMachine, error)
 }
 
 // TODO - CAAS(ericclaudejones): This should contain state alone, model will be
@@ -47,6 +50,18 @@ func (s stateShim) Machine(id string) (StateMachine, error) {
 	return s.State.Machine(id)
 }
 
+func (s stateShim) ReplaceMachine(id string) (StateMachine, error) {
+	m, err := s.State.Machine(id)
+	if err != nil {
+		return nil, err
+	}
+	replacement, err := m.Replace()
+	if err != nil {
+		return nil, err
+	}
+	return replacement, nil
+}
+
 var getState = func(st *state.State, m *state.Model) StateInterface {
 	return stateShim{st, m}
 }
@@ -16,6 +16,7 @@ import (
 	macaroon "gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
@@ -68,7 +69,16 @@ func (h *localLoginHandlers) serveLoginPost(p httprequest.Params) (interface{},
 	authenticator := h.authCtxt.authenticator(p.Request.Host)
 	if _, err := authenticator.Authenticate(h.state, userTag, params.LoginRequest{
 		Credentials: password,
+		MFACode:     p.Request.Form.Get("mfa-code"),
 	}); err != nil {
+		if errors.Cause(err) == common.ErrMFARequired {
+			// Leave the interaction pending, so the client
+			// may post the form again along with a code.
+			return nil, &httpbakery.Error{
+				Code:    params.CodeMFARequired,
+				Message: err.Error(),
+			}
+		}
 		// Mark the interaction as done (but failed),
 		// unblocking a pending "/auth/wait" request.
 		if err := h.authCtxt.localUserInteractions.Done(waitId, userTag, err); err != nil {
@@ -76,6 +76,12 @@ func (l *bufferedLog) AddResponse(r auditlog.ResponseErrors) error {
 	return l.dest.AddResponse(r)
 }
 
+// AddLogin implements auditlog.AuditLog. Login attempts are always
+// interesting, so they are forwarded on immediately.
+func (l *bufferedLog) AddLogin(login auditlog.Login) error {
+	return l.dest.AddLogin(login)
+}
+
 // Close implements auditlog.AuditLog.
 func (l *bufferedLog) Close() error {
 	return errors.Trace(l.dest.Close())
@@ -127,6 +133,12 @@ func MakeInterestingRequestFilter(excludeMethods set.Strings) func(auditlog.Requ
 	}
 }
 
+// IsReadOnlyMethod reports whether the given method is known to make
+// no changes to the controller or its models.
+func IsReadOnlyMethod(facadeName, methodName string) bool {
+	return readonlyMethods.Contains(facadeName + "." + methodName)
+}
+
 var readonlyMethods = set.NewStrings(
 	// Collected by running read-only commands.
 	"Action.Actions",
@@ -75,6 +75,15 @@ func (s *auditFilterSuite) TestFiltersUninterestingConversations(c *gc.C) {
 	target.CheckCallNames(c, "AddRequest", "AddResponse")
 }
 
+func (s *auditFilterSuite) TestLoginsNotBuffered(c *gc.C) {
+	target := &apitesting.FakeAuditLog{}
+	log := observer.NewAuditLogFilter(target, func(auditlog.Request) bool { return false })
+
+	err := log.AddLogin(auditlog.Login{Who: "bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	target.CheckCallNames(c, "AddLogin")
+}
+
 func (s *auditFilterSuite) TestMakeFilter(c *gc.C) {
 	f1 := observer.MakeInterestingRequestFilter(set.NewStrings("Battery.Kinzie", "Helplessness.Blues"))
 	c.Assert(f1(auditlog.Request{Facade: "Battery", Method: "Kinzie"}), jc.IsFalse)
@@ -95,3 +104,9 @@ func (s *auditFilterSuite) TestOnlyExcludeReadonlyMethodsIfWeShould(c *gc.C) {
 	// Doesn't allow the readonly methods unless they've included the special key.
 	c.Assert(f1(auditlog.Request{Facade: "Client", Method: "FullStatus"}), jc.IsTrue)
 }
+
+func (s *auditFilterSuite) TestIsReadOnlyMethod(c *gc.C) {
+	c.Assert(observer.IsReadOnlyMethod("Client", "FullStatus"), jc.IsTrue)
+	c.Assert(observer.IsReadOnlyMethod("Pinger", "Ping"), jc.IsTrue)
+	c.Assert(observer.IsReadOnlyMethod("Application", "Deploy"), jc.IsFalse)
+}
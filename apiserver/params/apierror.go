@@ -98,6 +98,11 @@ const (
 	CodeRedirect                  = "redirection required"
 	CodeRetry                     = "retry"
 	CodeIncompatibleSeries        = "incompatible series"
+
+	// CodeMFARequired is returned by the local login form when the
+	// user's password is correct but a multi-factor authentication
+	// code must also be provided.
+	CodeMFARequired = "multi-factor authentication code required"
 )
 
 // ErrCode returns the error code associated with
@@ -3,6 +3,8 @@
 
 package params
 
+import "time"
+
 // DestroyControllerArgs holds the arguments for destroying a controller.
 type DestroyControllerArgs struct {
 	// DestroyModels specifies whether or not the hosted models
@@ -96,6 +98,24 @@ type ControllerConfigSet struct {
 	Config map[string]interface{} `json:"config"`
 }
 
+// ControllerCARotation describes a controller CA rotation in progress,
+// as returned by Controller.ControllerCARotation. Phase is empty if no
+// rotation is in progress.
+type ControllerCARotation struct {
+	Phase     string     `json:"phase,omitempty"`
+	OldCACert string     `json:"old-ca-cert,omitempty"`
+	NewCACert string     `json:"new-ca-cert,omitempty"`
+	Started   *time.Time `json:"started,omitempty"`
+
+	// PendingAgents holds the agents, as "<model UUID>:<tag>", that
+	// have not yet acknowledged the new CA.
+	PendingAgents []string `json:"pending-agents,omitempty"`
+
+	// PendingControllers holds the ids of the controller machines
+	// that have not yet reissued their certificates with the new CA.
+	PendingControllers []string `json:"pending-controllers,omitempty"`
+}
+
 // ControllerAction is an action that can be performed on a model.
 type ControllerAction string
 
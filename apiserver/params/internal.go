@@ -452,6 +452,19 @@ type AgentGetEntitiesResult struct {
 	Error         *Error                    `json:"error,omitempty"`
 }
 
+// AgentCACerts holds the controller CA certificates recorded by
+// agents, as acknowledged by agent.API.AcknowledgeControllerCACerts.
+type AgentCACerts struct {
+	CACerts []AgentCACert `json:"ca-certs"`
+}
+
+// AgentCACert holds the controller CA certificate, possibly a bundle,
+// recorded by an agent.
+type AgentCACert struct {
+	Tag    string `json:"tag"`
+	CACert string `json:"ca-cert"`
+}
+
 // VersionResult holds the version and possibly error for a given
 // DesiredVersion() API call.
 type VersionResult struct {
@@ -676,18 +689,19 @@ type AgentVersionResult struct {
 
 // ProvisioningInfo holds machine provisioning info.
 type ProvisioningInfo struct {
-	Constraints       constraints.Value         `json:"constraints"`
-	Series            string                    `json:"series"`
-	Placement         string                    `json:"placement"`
-	Jobs              []multiwatcher.MachineJob `json:"jobs"`
-	Volumes           []VolumeParams            `json:"volumes,omitempty"`
-	VolumeAttachments []VolumeAttachmentParams  `json:"volume-attachments,omitempty"`
-	Tags              map[string]string         `json:"tags,omitempty"`
-	SubnetsToZones    map[string][]string       `json:"subnets-to-zones,omitempty"`
-	ImageMetadata     []CloudImageMetadata      `json:"image-metadata,omitempty"`
-	EndpointBindings  map[string]string         `json:"endpoint-bindings,omitempty"`
-	ControllerConfig  map[string]interface{}    `json:"controller-config,omitempty"`
-	CloudInitUserData map[string]interface{}    `json:"cloudinit-userdata,omitempty"`
+	Constraints        constraints.Value         `json:"constraints"`
+	Series             string                    `json:"series"`
+	Placement          string                    `json:"placement"`
+	Jobs               []multiwatcher.MachineJob `json:"jobs"`
+	Volumes            []VolumeParams            `json:"volumes,omitempty"`
+	VolumeAttachments  []VolumeAttachmentParams  `json:"volume-attachments,omitempty"`
+	Tags               map[string]string         `json:"tags,omitempty"`
+	SubnetsToZones     map[string][]string       `json:"subnets-to-zones,omitempty"`
+	ImageMetadata      []CloudImageMetadata      `json:"image-metadata,omitempty"`
+	EndpointBindings   map[string]string         `json:"endpoint-bindings,omitempty"`
+	ControllerConfig   map[string]interface{}    `json:"controller-config,omitempty"`
+	CloudInitUserData  map[string]interface{}    `json:"cloudinit-userdata,omitempty"`
+	SSHUserCAPublicKey string                    `json:"ssh-user-ca-public-key,omitempty"`
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
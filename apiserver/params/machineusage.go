@@ -0,0 +1,108 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+
+	"github.com/juju/juju/constraints"
+)
+
+// MachineUsageSamples holds samples of machine resource usage to
+// be recorded by the MachineUsage facade.
+type MachineUsageSamples struct {
+	Samples []MachineUsageSample `json:"samples"`
+}
+
+// MachineUsageSample holds a sample of the resources used by a machine.
+type MachineUsageSample struct {
+	Tag  string    `json:"tag"`
+	Time time.Time `json:"time"`
+
+	// CPU is the percentage of the machine's total CPU capacity in
+	// use, from 0 to 100.
+	CPU   float64 `json:"cpu"`
+	Cores uint64  `json:"cores"`
+
+	// Memory and disk sizes are in MiB.
+	MemoryUsed  uint64 `json:"memory-used"`
+	MemoryTotal uint64 `json:"memory-total"`
+	DiskUsed    uint64 `json:"disk-used"`
+	DiskTotal   uint64 `json:"disk-total"`
+}
+
+// InstanceTypeRecommendationsArgs holds the arguments for the
+// MachineManager.InstanceTypeRecommendations API call.
+type InstanceTypeRecommendationsArgs struct {
+	// Applications holds the names of the applications for which
+	// to recommend instance types. If empty, recommendations are
+	// made for all applications in the model.
+	Applications []string `json:"applications,omitempty"`
+
+	// Window is the length of time over which machine usage
+	// is considered.
+	Window time.Duration `json:"window"`
+
+	// Headroom is the fraction of the observed usage to add
+	// when sizing recommended instance types, e.g. 0.2 for 20%.
+	Headroom float64 `json:"headroom"`
+}
+
+// InstanceTypeRecommendationsResults holds the results of the
+// MachineManager.InstanceTypeRecommendations API call.
+type InstanceTypeRecommendationsResults struct {
+	Results []InstanceTypeRecommendationResult `json:"results"`
+}
+
+// InstanceTypeRecommendationResult holds an instance type
+// recommendation for an application, or an error.
+type InstanceTypeRecommendationResult struct {
+	Result *InstanceTypeRecommendation `json:"result,omitempty"`
+	Error  *Error                      `json:"error,omitempty"`
+}
+
+// Recommendation actions for InstanceTypeRecommendation.Action.
+const (
+	RecommendKeep     = "keep"
+	RecommendDownsize = "downsize"
+	RecommendUpsize   = "upsize"
+)
+
+// InstanceTypeRecommendation describes the resources used by an
+// application's machines, and the instance type recommended to
+// host it.
+type InstanceTypeRecommendation struct {
+	Application string   `json:"application"`
+	Machines    []string `json:"machines"`
+	Samples     int      `json:"samples"`
+
+	// CPUCores is the 95th percentile of the number of CPU cores
+	// in use on any of the application's machines.
+	CPUCores float64 `json:"cpu-cores"`
+
+	// Memory and RootDisk are the peak memory and root disk space,
+	// in MiB, in use on any of the application's machines.
+	Memory   uint64 `json:"memory"`
+	RootDisk uint64 `json:"root-disk"`
+
+	// Current is the instance type in the provider's catalogue
+	// matching the application's machines, if any.
+	Current *InstanceType `json:"current,omitempty"`
+
+	// Recommended is the cheapest instance type that satisfies the
+	// observed usage plus headroom.
+	Recommended InstanceType `json:"recommended"`
+
+	// Action is one of RecommendKeep, RecommendDownsize or
+	// RecommendUpsize.
+	Action string `json:"action"`
+
+	// Constraints holds the application constraints that would
+	// select the recommended instance type.
+	Constraints constraints.Value `json:"constraints"`
+
+	CostUnit     string `json:"cost-unit,omitempty"`
+	CostCurrency string `json:"cost-currency,omitempty"`
+	CostDivisor  uint64 `json:"cost-divisor,omitempty"`
+}
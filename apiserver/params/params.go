@@ -356,6 +356,21 @@ type ApplicationUnset struct {
 	Options         []string `json:"options"`
 }
 
+// ModifyApplicationAccessRequest holds the parameters for granting or
+// revoking access to applications.
+type ModifyApplicationAccessRequest struct {
+	Changes []ModifyApplicationAccess `json:"changes"`
+}
+
+// ModifyApplicationAccess holds the parameters for granting or revoking
+// a user's read, operate or admin access to an application.
+type ModifyApplicationAccess struct {
+	UserTag        string               `json:"user-tag"`
+	Action         ModelAction          `json:"action"`
+	Access         UserAccessPermission `json:"access"`
+	ApplicationTag string               `json:"application-tag"`
+}
+
 // ApplicationGet holds parameters for making the Get or
 // GetCharmURL calls.
 type ApplicationGet struct {
@@ -589,6 +604,19 @@ type LoginRequest struct {
 	Macaroons   []macaroon.Slice `json:"macaroons"`
 	CLIArgs     string           `json:"cli-args,omitempty"`
 	UserData    string           `json:"user-data"`
+
+	// IDToken holds an OpenID Connect identity token identifying
+	// the user logging in, as an alternative to a tag and credentials.
+	IDToken string `json:"id-token,omitempty"`
+
+	// Token holds an API token created by the user logging in, as
+	// an alternative to a tag and credentials.
+	Token string `json:"token,omitempty"`
+
+	// MFACode holds a multi-factor authentication code, required
+	// along with the credentials of local users who have enabled
+	// multi-factor authentication.
+	MFACode string `json:"mfa-code,omitempty"`
 }
 
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
@@ -695,6 +723,11 @@ type StateServingInfo struct {
 	// this will be passed as the KeyFile argument to MongoDB
 	SharedSecret   string `json:"shared-secret"`
 	SystemIdentity string `json:"system-identity"`
+	// The private key of the controller's SSH certificate authority.
+	SSHCAPrivateKey string `json:"ssh-ca-private-key,omitempty"`
+	// The base64-encoded key that charm secrets are encrypted with.
+	// Unlike the other fields, it is never stored in the database.
+	SecretsKey string `json:"secrets-key,omitempty"`
 }
 
 // IsMasterResult holds the result of an IsMaster API call.
@@ -809,6 +842,17 @@ type RedirectInfoResult struct {
 	CACert string `json:"ca-cert"`
 }
 
+// OIDCLoginInfoResult holds the result of an OIDCLoginInfo call.
+type OIDCLoginInfoResult struct {
+	// IssuerURL holds the URL of the OpenID Connect provider trusted
+	// by the controller. It is empty if OpenID Connect logins are
+	// not configured.
+	IssuerURL string `json:"issuer-url,omitempty"`
+
+	// ClientID holds the client ID identity tokens must be issued for.
+	ClientID string `json:"client-id,omitempty"`
+}
+
 // ReauthRequest holds a challenge/response token meaningful to the identity
 // provider.
 type ReauthRequest struct {
@@ -859,6 +903,12 @@ type LoginResult struct {
 	// the connection.
 	PublicDNSName string `json:"public-dns-name,omitempty"`
 
+	// ControllerCACert holds the controller's CA certificate, in PEM
+	// format. While the controller CA is being rotated it is a bundle
+	// of the old and new CA certificates; clients and agents should
+	// replace the CA certificate they trust with it.
+	ControllerCACert string `json:"controller-ca-cert,omitempty"`
+
 	// ModelTag is the tag for the model that is being connected to.
 	ModelTag string `json:"model-tag,omitempty"`
 
@@ -1122,6 +1172,43 @@ type DestroyMachineInfo struct {
 	DestroyedUnits []Entity `json:"destroyed-units,omitempty"`
 }
 
+// ReplaceMachinesParams holds the parameters for the
+// MachineManager.ReplaceMachines API call.
+type ReplaceMachinesParams struct {
+	MachineTags []string `json:"machine-tags"`
+	Force       bool     `json:"force,omitempty"`
+}
+
+// ReplaceMachineResults contains the results of a
+// MachineManager.ReplaceMachines API request.
+type ReplaceMachineResults struct {
+	Results []ReplaceMachineResult `json:"results,omitempty"`
+}
+
+// ReplaceMachineResult contains one of the results of a
+// MachineManager.ReplaceMachines API request.
+type ReplaceMachineResult struct {
+	Error *Error              `json:"error,omitempty"`
+	Info  *ReplaceMachineInfo `json:"info,omitempty"`
+}
+
+// ReplaceMachineInfo contains information related to the replacement
+// of a machine.
+type ReplaceMachineInfo struct {
+	// Replacement is the tag of the machine that replaces the
+	// original machine.
+	Replacement string `json:"replacement"`
+
+	// MovedUnits is the tags of units that were moved to the
+	// replacement machine.
+	MovedUnits []Entity `json:"moved-units,omitempty"`
+
+	// MovedStorage is the tags of storage instances that will be
+	// detached from the original machine and attached to the
+	// replacement machine.
+	MovedStorage []Entity `json:"moved-storage,omitempty"`
+}
+
 // DestroyApplicationResults contains the results of a DestroyApplication
 // API request.
 type DestroyApplicationResults struct {
@@ -0,0 +1,80 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// SetSecretArgs holds the arguments for setting secrets owned by
+// units' applications.
+type SetSecretArgs struct {
+	Args []SetSecretArg `json:"args"`
+}
+
+// SetSecretArg holds the arguments for setting a secret owned by a
+// unit's application.
+type SetSecretArg struct {
+	UnitTag string            `json:"unit-tag"`
+	Name    string            `json:"name"`
+	Values  map[string]string `json:"values"`
+
+	// RotateInterval holds how often the secret should be
+	// rotated, or zero if it is not rotated.
+	RotateInterval time.Duration `json:"rotate-interval,omitempty"`
+}
+
+// GetSecretArgs holds the arguments for getting secrets.
+type GetSecretArgs struct {
+	Args []GetSecretArg `json:"args"`
+}
+
+// GetSecretArg holds the arguments for a unit getting a secret.
+type GetSecretArg struct {
+	UnitTag string `json:"unit-tag"`
+	Name    string `json:"name"`
+
+	// Application holds the name of the application that owns
+	// the secret. If empty, it is the unit's own application.
+	Application string `json:"application,omitempty"`
+}
+
+// SecretValuesResults holds the results of getting secrets.
+type SecretValuesResults struct {
+	Results []SecretValuesResult `json:"results"`
+}
+
+// SecretValuesResult holds the values of a secret, or an error.
+type SecretValuesResult struct {
+	Values map[string]string `json:"values,omitempty"`
+	Error  *Error            `json:"error,omitempty"`
+}
+
+// ShareSecretArgs holds the arguments for sharing secrets.
+type ShareSecretArgs struct {
+	Args []ShareSecretArg `json:"args"`
+}
+
+// ShareSecretArg holds the arguments for sharing a secret owned by a
+// unit's application with a related application.
+type ShareSecretArg struct {
+	UnitTag     string `json:"unit-tag"`
+	Name        string `json:"name"`
+	Application string `json:"application"`
+
+	// Revoke reports whether to stop sharing the secret, rather
+	// than share it.
+	Revoke bool `json:"revoke,omitempty"`
+}
+
+// SecretArgs holds the arguments for operating on secrets.
+type SecretArgs struct {
+	Args []SecretArg `json:"args"`
+}
+
+// SecretArg identifies a secret owned by a unit's application.
+type SecretArg struct {
+	UnitTag string `json:"unit-tag"`
+	Name    string `json:"name"`
+}
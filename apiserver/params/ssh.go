@@ -15,6 +15,32 @@ type SSHHostKeys struct {
 	PublicKeys []string `json:"public-keys"`
 }
 
+// SSHHostKeyCertificatesResults holds the results of the
+// HostKeyReporter.SignKeys API, one for each entity.
+type SSHHostKeyCertificatesResults struct {
+	Results []SSHHostKeyCertificatesResult `json:"results"`
+}
+
+// SSHHostKeyCertificatesResult holds the SSH host certificates, in
+// authorized_keys format, for the host keys of one entity, in the
+// order the keys were given.
+type SSHHostKeyCertificatesResult struct {
+	Certificates []string `json:"certificates,omitempty"`
+	Error        *Error   `json:"error,omitempty"`
+}
+
+// SSHSignUserKeyArg holds the public key, in authorized_keys format,
+// to be signed by the SSHClient.SignUserKey API.
+type SSHSignUserKeyArg struct {
+	PublicKey string `json:"public-key"`
+}
+
+// SSHSignUserKeyResult defines the response from the
+// SSHClient.SignUserKey API.
+type SSHSignUserKeyResult struct {
+	Certificate string `json:"certificate"`
+}
+
 // SSHProxyResult defines the response from the SSHClient.Proxy API.
 type SSHProxyResult struct {
 	UseProxy bool `json:"use-proxy"`
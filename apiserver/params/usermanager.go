@@ -16,6 +16,11 @@ type UserInfo struct {
 	DateCreated    time.Time  `json:"date-created"`
 	LastConnection *time.Time `json:"last-connection,omitempty"`
 	Disabled       bool       `json:"disabled"`
+	Locked         bool       `json:"locked,omitempty"`
+
+	// Groups holds the names of the groups the user is a member of.
+	// Access includes any controller access inherited from them.
+	Groups []string `json:"groups,omitempty"`
 }
 
 // UserInfoResult holds the result of a UserInfo call.
@@ -67,3 +72,172 @@ type AddUserResult struct {
 	SecretKey []byte `json:"secret-key,omitempty"`
 	Error     *Error `json:"error,omitempty"`
 }
+
+// AddAPITokens holds the parameters for creating API tokens owned by
+// the calling user.
+type AddAPITokens struct {
+	Tokens []AddAPIToken `json:"tokens"`
+}
+
+// AddAPIToken holds the parameters for creating one API token.
+type AddAPIToken struct {
+	Name    string    `json:"name"`
+	Expires time.Time `json:"expires"`
+
+	// Models optionally holds the tags of the models the token is
+	// limited to.
+	Models []string `json:"models,omitempty"`
+
+	// Facades optionally holds the names of the facades the token
+	// is limited to.
+	Facades []string `json:"facades,omitempty"`
+
+	// ReadOnly limits the token to read-only calls.
+	ReadOnly bool `json:"read-only,omitempty"`
+}
+
+// AddAPITokenResults holds the results of the bulk AddAPITokens API call.
+type AddAPITokenResults struct {
+	Results []AddAPITokenResult `json:"results"`
+}
+
+// AddAPITokenResult holds the value of a newly created API token, to
+// be presented at login, or an error.
+type AddAPITokenResult struct {
+	Token string `json:"token,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// APITokenInfo holds information on an API token.
+type APITokenInfo struct {
+	Name        string    `json:"name"`
+	Models      []string  `json:"models,omitempty"`
+	Facades     []string  `json:"facades,omitempty"`
+	ReadOnly    bool      `json:"read-only,omitempty"`
+	DateCreated time.Time `json:"date-created"`
+	Expires     time.Time `json:"expires"`
+}
+
+// APITokenInfoResults holds the result of an APITokens API call.
+type APITokenInfoResults struct {
+	Results []APITokenInfo `json:"results"`
+}
+
+// RevokeAPITokens holds the names of API tokens owned by the calling
+// user to be revoked.
+type RevokeAPITokens struct {
+	Names []string `json:"names"`
+}
+
+// MFAEnrolment holds the secret a user enrols in their authenticator
+// application to enable multi-factor authentication.
+type MFAEnrolment struct {
+	// Secret holds the base32 encoded secret.
+	Secret string `json:"secret"`
+
+	// URI holds an otpauth URI describing the secret, which
+	// authenticator applications accept as a QR code.
+	URI string `json:"uri"`
+}
+
+// MFACode holds a multi-factor authentication code.
+type MFACode struct {
+	Code string `json:"code"`
+}
+
+// DisableMFAArgs holds the users to disable multi-factor
+// authentication for.
+type DisableMFAArgs struct {
+	Entities []DisableMFAArg `json:"entities"`
+}
+
+// DisableMFAArg identifies a user to disable multi-factor
+// authentication for. Users disabling it for themselves must provide
+// a valid code.
+type DisableMFAArg struct {
+	Tag  string `json:"tag"`
+	Code string `json:"code,omitempty"`
+}
+
+// UserGroupNames holds the names of user groups.
+type UserGroupNames struct {
+	Names []string `json:"names"`
+}
+
+// UserGroupMembers holds changes to the membership of user groups.
+type UserGroupMembers struct {
+	Changes []UserGroupMember `json:"changes"`
+}
+
+// UserGroupMember holds the users to add to, or remove from, one
+// user group.
+type UserGroupMember struct {
+	Group    string   `json:"group"`
+	UserTags []string `json:"user-tags"`
+}
+
+// UserGroupExternalGroups holds changes to the external identity
+// provider groups mapped to user groups.
+type UserGroupExternalGroups struct {
+	Changes []UserGroupExternalGroup `json:"changes"`
+}
+
+// UserGroupExternalGroup holds the external groups, as "name@domain",
+// to map to, or unmap from, one user group.
+type UserGroupExternalGroup struct {
+	Group          string   `json:"group"`
+	ExternalGroups []string `json:"external-groups"`
+}
+
+// UserGroupInfo holds information on a user group.
+type UserGroupInfo struct {
+	Name           string    `json:"name"`
+	Members        []string  `json:"members"`
+	ExternalGroups []string  `json:"external-groups,omitempty"`
+	CreatedBy      string    `json:"created-by"`
+	DateCreated    time.Time `json:"date-created"`
+}
+
+// UserGroupInfoResult holds the result of a UserGroupInfo call for
+// one group.
+type UserGroupInfoResult struct {
+	Result *UserGroupInfo `json:"result,omitempty"`
+	Error  *Error         `json:"error,omitempty"`
+}
+
+// UserGroupInfoResults holds the results of a bulk UserGroupInfo API
+// call.
+type UserGroupInfoResults struct {
+	Results []UserGroupInfoResult `json:"results"`
+}
+
+// UserGroupAction is an action that can be performed on the access
+// granted to a user group.
+type UserGroupAction string
+
+// Actions that can be performed on the access granted to a user group.
+const (
+	GrantUserGroupAccess  UserGroupAction = "grant"
+	RevokeUserGroupAccess UserGroupAction = "revoke"
+)
+
+// ModifyUserGroupAccessRequest holds the parameters for changing the
+// access granted to user groups.
+type ModifyUserGroupAccessRequest struct {
+	Changes []ModifyUserGroupAccess `json:"changes"`
+}
+
+// ModifyUserGroupAccess holds a change to the access granted to a user
+// group on a model, offer or the controller.
+type ModifyUserGroupAccess struct {
+	Group  string          `json:"group"`
+	Action UserGroupAction `json:"action"`
+	Access string          `json:"access"`
+
+	// TargetTag holds the tag of the model or controller the access
+	// applies to. It is empty when OfferURL is set.
+	TargetTag string `json:"target-tag,omitempty"`
+
+	// OfferURL holds the URL of the offer the access applies to.
+	OfferURL string `json:"offer-url,omitempty"`
+}
@@ -0,0 +1,47 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/observer"
+)
+
+// apiTokenMethodsOnly returns a check function limiting calls to the
+// given facades, if any are specified, and to read-only methods if
+// readOnly is true. Pinging the server is always allowed.
+func apiTokenMethodsOnly(facades []string, readOnly bool) func(string, string) error {
+	allowed := set.NewStrings(facades...)
+	return func(facadeName, methodName string) error {
+		if facadeName == "Pinger" {
+			return nil
+		}
+		if !allowed.IsEmpty() && !allowed.Contains(facadeName) {
+			return errors.NewNotSupported(nil, fmt.Sprintf("facade %q not supported for this API token", facadeName))
+		}
+		if readOnly && !observer.IsReadOnlyMethod(facadeName, methodName) {
+			return errors.NewNotSupported(nil, fmt.Sprintf("method %s.%s not supported for read-only API token", facadeName, methodName))
+		}
+		return nil
+	}
+}
+
+// apiTokenAllowsModel reports whether a token limited to the given
+// models may be used to log in to the model with the given tag.
+func apiTokenAllowsModel(models []names.ModelTag, model names.ModelTag) bool {
+	if len(models) == 0 {
+		return true
+	}
+	for _, m := range models {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type restrictAPITokenSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restrictAPITokenSuite{})
+
+func (s *restrictAPITokenSuite) TestUnrestricted(c *gc.C) {
+	root := apiserver.TestingAPITokenRoot(nil, false)
+	caller, err := root.FindMethod("Application", 5, "Deploy")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (s *restrictAPITokenSuite) TestFacades(c *gc.C) {
+	root := apiserver.TestingAPITokenRoot([]string{"Application"}, false)
+	caller, err := root.FindMethod("Application", 5, "Deploy")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+
+	caller, err = root.FindMethod("Pinger", 1, "Ping")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+
+	caller, err = root.FindMethod("Client", 1, "FullStatus")
+	c.Assert(err, gc.ErrorMatches, `facade "Client" not supported for this API token`)
+	c.Assert(errors.IsNotSupported(err), jc.IsTrue)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (s *restrictAPITokenSuite) TestReadOnly(c *gc.C) {
+	root := apiserver.TestingAPITokenRoot(nil, true)
+	caller, err := root.FindMethod("Client", 1, "FullStatus")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+
+	caller, err = root.FindMethod("Application", 5, "Deploy")
+	c.Assert(err, gc.ErrorMatches, `method Application.Deploy not supported for read-only API token`)
+	c.Assert(errors.IsNotSupported(err), jc.IsTrue)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (s *restrictAPITokenSuite) TestAllowsModel(c *gc.C) {
+	model1 := names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d")
+	model2 := names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00e")
+	c.Assert(apiserver.APITokenAllowsModel(nil, model1), jc.IsTrue)
+	c.Assert(apiserver.APITokenAllowsModel([]names.ModelTag{model1}, model1), jc.IsTrue)
+	c.Assert(apiserver.APITokenAllowsModel([]names.ModelTag{model1}, model2), jc.IsFalse)
+}
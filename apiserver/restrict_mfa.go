@@ -0,0 +1,26 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+)
+
+// mfaEnrolmentMethods holds the UserManager methods a user may call
+// to enable multi-factor authentication when the controller requires
+// it but they have not yet done so.
+var mfaEnrolmentMethods = set.NewStrings("EnableMFA", "ConfirmMFA")
+
+// mfaEnrolmentMethodsOnly allows only the calls needed to enable
+// multi-factor authentication. Pinging the server is always allowed.
+func mfaEnrolmentMethodsOnly(facadeName, methodName string) error {
+	if facadeName == "Pinger" {
+		return nil
+	}
+	if facadeName == "UserManager" && mfaEnrolmentMethods.Contains(methodName) {
+		return nil
+	}
+	return errors.NewNotSupported(nil, `multi-factor authentication required by controller; run "juju enable-mfa" first`)
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type restrictMFASuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restrictMFASuite{})
+
+func (s *restrictMFASuite) TestAllowed(c *gc.C) {
+	root := apiserver.TestingMFAEnrolmentRoot()
+	for _, method := range []string{"EnableMFA", "ConfirmMFA"} {
+		caller, err := root.FindMethod("UserManager", 4, method)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caller, gc.NotNil)
+	}
+	caller, err := root.FindMethod("Pinger", 1, "Ping")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(caller, gc.NotNil)
+}
+
+func (s *restrictMFASuite) TestNotAllowed(c *gc.C) {
+	root := apiserver.TestingMFAEnrolmentRoot()
+	for _, method := range []struct {
+		facade  string
+		version int
+		name    string
+	}{
+		{"UserManager", 4, "AddUser"},
+		{"Client", 1, "FullStatus"},
+	} {
+		caller, err := root.FindMethod(method.facade, method.version, method.name)
+		c.Check(err, gc.ErrorMatches, `multi-factor authentication required by controller; run "juju enable-mfa" first`)
+		c.Check(errors.IsNotSupported(err), jc.IsTrue)
+		c.Check(caller, gc.IsNil)
+	}
+}
@@ -36,6 +36,7 @@ var allowedMethodsDuringMigration = map[string]set.Strings{
 		"BestAPIVersion",
 		"AllAddresses",
 		"PublicKeys",
+		"SignUserKey",
 		"Proxy",
 	),
 	"Pinger": set.NewStrings(
@@ -43,6 +43,7 @@ var allowedMethodsDuringUpgrades = map[string]set.Strings{
 		"BestAPIVersion",
 		"AllAddresses",
 		"PublicKeys",
+		"SignUserKey",
 		"Proxy",
 	),
 	"Pinger": set.NewStrings(
@@ -60,12 +60,19 @@ type apiHandler struct {
 	// serverHost is the host:port of the API server that the client
 	// connected to.
 	serverHost string
+
+	// remoteAddr is the address of the client, recorded in the audit
+	// log when a user logs in.
+	remoteAddr string
+
+	// apiToken holds the API token the user logged in with, if any.
+	apiToken *state.APIToken
 }
 
 var _ = (*apiHandler)(nil)
 
 // newAPIHandler returns a new apiHandler.
-func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID string, connectionID uint64, serverHost string) (*apiHandler, error) {
+func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID string, connectionID uint64, serverHost, remoteAddr string) (*apiHandler, error) {
 	m, err := st.Model()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -78,6 +85,7 @@ func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID st
 		modelUUID:    modelUUID,
 		connectionID: connectionID,
 		serverHost:   serverHost,
+		remoteAddr:   remoteAddr,
 	}
 
 	if err := r.resources.RegisterNamed("machineID", common.StringResource(srv.tag.Id())); err != nil {
@@ -205,6 +213,12 @@ func restrictAPIRoot(
 			apiRoot = restrictRoot(apiRoot, caasModelFacadesOnly)
 		}
 	}
+	if auth.apiToken != nil {
+		apiRoot = restrictRoot(apiRoot, apiTokenMethodsOnly(auth.apiToken.Facades(), auth.apiToken.ReadOnly()))
+	}
+	if auth.mfaEnrolmentRequired {
+		apiRoot = restrictRoot(apiRoot, mfaEnrolmentMethodsOnly)
+	}
 	return apiRoot, nil
 }
 
@@ -496,14 +510,21 @@ func (r *apiHandler) ConnectedModel() string {
 	return r.modelUUID
 }
 
-// HasPermission returns true if the logged in user can perform <operation> on <target>.
+// APIToken returns the API token used to log in, if any.
+func (r *apiHandler) APIToken() *state.APIToken {
+	return r.apiToken
+}
+
+// HasPermission returns true if the logged in user can perform <operation> on <target>,
+// either directly or through one of the groups they are a member of.
 func (r *apiHandler) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
-	return common.HasPermission(r.state.UserPermission, r.entity.Tag(), operation, target)
+	return common.HasPermission(r.state.EffectiveUserPermission, r.entity.Tag(), operation, target)
 }
 
-// UserHasPermission returns true if the passed in user can perform <operation> on <target>.
+// UserHasPermission returns true if the passed in user can perform <operation> on <target>,
+// either directly or through one of the groups they are a member of.
 func (r *apiHandler) UserHasPermission(user names.UserTag, operation permission.Access, target names.Tag) (bool, error) {
-	return common.HasPermission(r.state.UserPermission, user, operation, target)
+	return common.HasPermission(r.state.EffectiveUserPermission, user, operation, target)
 }
 
 // DescribeFacades returns the list of available Facades and their Versions
@@ -29,6 +29,11 @@ func (l *FakeAuditLog) AddResponse(m auditlog.ResponseErrors) error {
 	return l.Stub.NextErr()
 }
 
+func (l *FakeAuditLog) AddLogin(m auditlog.Login) error {
+	l.Stub.AddCall("AddLogin", m)
+	return l.Stub.NextErr()
+}
+
 func (l *FakeAuditLog) Close() error {
 	l.Stub.AddCall("Close")
 	return l.Stub.NextErr()
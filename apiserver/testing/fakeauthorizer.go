@@ -9,6 +9,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
 )
 
 // FakeAuthorizer implements the facade.Authorizer interface.
@@ -18,6 +19,7 @@ type FakeAuthorizer struct {
 	ModelUUID   string
 	AdminTag    names.UserTag
 	HasWriteTag names.UserTag
+	Token       *state.APIToken
 }
 
 func (fa FakeAuthorizer) AuthOwner(tag names.Tag) bool {
@@ -96,6 +98,8 @@ func nameBasedHasPermission(name string, operation permission.Access, target nam
 		perm = permission.WriteAccess
 	case strings.HasPrefix(name, string(permission.ConsumeAccess)):
 		perm = permission.ConsumeAccess
+	case strings.HasPrefix(name, string(permission.OperateAccess)):
+		perm = permission.OperateAccess
 	case strings.HasPrefix(name, string(permission.ReadAccess)):
 		perm = permission.ReadAccess
 	default:
@@ -124,6 +128,11 @@ func (fa FakeAuthorizer) ConnectedModel() string {
 	return fa.ModelUUID
 }
 
+// APIToken returns the API token the client logged in with, if any.
+func (fa FakeAuthorizer) APIToken() *state.APIToken {
+	return fa.Token
+}
+
 // UserHasPermission returns true if the passed user is admin or has a name equal to
 // the pre-set admin tag.
 func (fa FakeAuthorizer) UserHasPermission(user names.UserTag, operation permission.Access, target names.Tag) (bool, error) {
@@ -5,6 +5,7 @@ package cert
 
 import (
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"time"
 
@@ -13,9 +14,11 @@ import (
 )
 
 // Verify verifies that the given server certificate is valid with
-// respect to the given CA certificate at the given time.
+// respect to the given CA certificate at the given time. The CA
+// certificate may be a bundle of several PEM encoded certificates,
+// any of which is trusted.
 func Verify(srvCertPEM, caCertPEM string, when time.Time) error {
-	caCert, err := cert.ParseCert(caCertPEM)
+	caCerts, err := ParseCerts(caCertPEM)
 	if err != nil {
 		return errors.Annotate(err, "cannot parse CA certificate")
 	}
@@ -24,7 +27,9 @@ func Verify(srvCertPEM, caCertPEM string, when time.Time) error {
 		return errors.Annotate(err, "cannot parse server certificate")
 	}
 	pool := x509.NewCertPool()
-	pool.AddCert(caCert)
+	for _, caCert := range caCerts {
+		pool.AddCert(caCert)
+	}
 	opts := x509.VerifyOptions{
 		Roots:       pool,
 		CurrentTime: when,
@@ -33,6 +38,34 @@ func Verify(srvCertPEM, caCertPEM string, when time.Time) error {
 	return err
 }
 
+// ParseCerts parses all the PEM encoded certificates in the given
+// data, in order. Unlike cert.ParseCert, which only looks at the
+// first certificate, it is suitable for CA bundles holding more than
+// one trusted certificate.
+func ParseCerts(certPEM string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	data := []byte(certPEM)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		xcert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		certs = append(certs, xcert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return certs, nil
+}
+
 // NewLeafKeyBits is the number of bits used for the cert.NewLeaf call.
 var NewLeafKeyBits = 2048
 
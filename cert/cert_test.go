@@ -163,6 +163,38 @@ func (certSuite) TestVerify(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "x509: certificate signed by unknown authority")
 }
 
+func (certSuite) TestVerifyBundle(c *gc.C) {
+	now := time.Now()
+	caCert, caKey, err := cert.NewCA("foo", "1", now.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	caCert2, caKey2, err := cert.NewCA("bar", "1", now.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+
+	srvCert, _, err := cert.NewServer(caCert, caKey, now.Add(time.Hour), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	srvCert2, _, err := cert.NewServer(caCert2, caKey2, now.Add(time.Hour), nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Server certificates signed by either CA in the
+	// bundle are trusted.
+	bundle := caCert + caCert2
+	c.Assert(cert.Verify(srvCert, bundle, now), jc.ErrorIsNil)
+	c.Assert(cert.Verify(srvCert2, bundle, now), jc.ErrorIsNil)
+}
+
+func (certSuite) TestParseCerts(c *gc.C) {
+	certs, err := cert.ParseCerts(caCertPEM + nonCAKey + nonCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(certs, gc.HasLen, 2)
+	c.Assert(certs[0].IsCA, jc.IsTrue)
+	c.Assert(certs[1].IsCA, jc.IsFalse)
+}
+
+func (certSuite) TestParseCertsNoCertificates(c *gc.C) {
+	_, err := cert.ParseCerts(nonCAKey)
+	c.Assert(err, gc.ErrorMatches, "no certificates found")
+}
+
 func (certSuite) TestNewServer(c *gc.C) {
 	now := time.Now()
 	expiry := roundTime(now.AddDate(1, 0, 0))
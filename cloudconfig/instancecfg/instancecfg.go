@@ -117,6 +117,13 @@ type InstanceConfig struct {
 	// commands cannot work.
 	AuthorizedKeys string
 
+	// SSHUserCAPublicKey, if set, holds the public key, in
+	// authorized_keys format, of the controller's SSH certificate
+	// authority. sshd on the instance is configured to accept user
+	// certificates signed by it that were issued for the instance's
+	// model.
+	SSHUserCAPublicKey string
+
 	// AgentEnvironment defines additional configuration variables to set in
 	// the instance agent config.
 	AgentEnvironment map[string]string
@@ -835,6 +842,10 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 		logger.Debugf("Setting numa ctl preference to %v", icfg.Controller.Config.NUMACtlPreference())
 		// Unfortunately, AgentEnvironment can only take strings as values
 		icfg.AgentEnvironment[agent.NUMACtlPreference] = fmt.Sprintf("%v", icfg.Controller.Config.NUMACtlPreference())
+	} else if controllerProxy := cfg.ControllerProxy(); controllerProxy != "" {
+		// Controllers talk to themselves directly; only other
+		// machines reach the API through the controller proxy.
+		icfg.AgentEnvironment[agent.ControllerProxy] = controllerProxy
 	}
 	return nil
 }
@@ -129,6 +129,26 @@ func (s *CloudInitSuite) TestFinishInstanceConfigNonDefault(c *gc.C) {
 	})
 }
 
+func (s *CloudInitSuite) TestFinishInstanceConfigControllerProxy(c *gc.C) {
+	userTag := names.NewLocalUserTag("not-touched")
+	attrs := dummySampleConfig().Merge(testing.Attrs{
+		"authorized-keys":  "we-are-the-keys",
+		"controller-proxy": "socks5://10.0.0.1:1080",
+	})
+	cfg, err := config.New(config.NoDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	icfg := &instancecfg.InstanceConfig{
+		APIInfo: &api.Info{Tag: userTag},
+	}
+	err = instancecfg.FinishInstanceConfig(icfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(icfg.AgentEnvironment, jc.DeepEquals, map[string]string{
+		agent.ProviderType:    "dummy",
+		agent.ContainerType:   "",
+		agent.ControllerProxy: "socks5://10.0.0.1:1080",
+	})
+}
+
 func (s *CloudInitSuite) TestUserData(c *gc.C) {
 	s.testUserData(c, "quantal", false)
 }
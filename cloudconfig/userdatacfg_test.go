@@ -36,6 +36,7 @@ import (
 	"github.com/juju/juju/juju/paths"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/network/ssh"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/tools"
@@ -474,6 +475,17 @@ curl .* --noproxy "\*" --insecure -o \$bin/tools\.tar\.gz 'https://state-addr\.t
 `,
 	},
 
+	// agent binaries fetched through the controller proxy.
+	{
+		cfg: makeNormalConfig("quantal").mutate(func(cfg *testInstanceConfig) {
+			cfg.AgentEnvironment[agent.ControllerProxy] = "socks5://10.0.0.1:1080"
+		}),
+		inexactMatch: true,
+		expectScripts: `
+curl .* --connect-timeout 20 --proxy 'socks5://10\.0\.0\.1:1080' --insecure -o \$bin/tools\.tar\.gz 'https://state-addr\.testing\.invalid:54321/deadbeef-0bad-400d-8000-4b1d0d06f00d/tools/1\.2\.3-quantal-amd64'
+`,
+	},
+
 	// empty bootstrap contraints.
 	{
 		cfg: makeBootstrapConfig("precise").mutate(func(cfg *testInstanceConfig) {
@@ -1264,6 +1276,43 @@ DefaultEnvironment="http_proxy=http://user@10.0.0.1" "HTTP_PROXY=http://user@10.
 	c.Assert(found, jc.IsTrue)
 }
 
+func (s *cloudinitSuite) TestSSHUserCAWritten(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	instanceCfg.SSHUserCAPublicKey = "ssh-ed25519 AAAA juju-ca\n"
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := ssh.TrustUserCAScript("ssh-ed25519 AAAA juju-ca", instanceCfg.APIInfo.ModelTag.Id())
+	found := false
+	for _, cmd := range cloudcfg.RunCmds() {
+		if cmd == expected {
+			found = true
+			break
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
+
+func (s *cloudinitSuite) TestSSHUserCANotWrittenIfNotSet(c *gc.C) {
+	environConfig := minimalModelConfig(c)
+	instanceCfg := s.createInstanceConfig(c, environConfig)
+	cloudcfg, err := cloudinit.New("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	udata, err := cloudconfig.NewUserdataConfig(instanceCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, cmd := range cloudcfg.RunCmds() {
+		c.Assert(cmd, gc.Not(jc.Contains), "TrustedUserCAKeys")
+	}
+}
+
 func (s *cloudinitSuite) TestAptMirror(c *gc.C) {
 	environConfig := minimalModelConfig(c)
 	environConfig, err := environConfig.Apply(map[string]interface{}{
@@ -17,6 +17,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils"
 	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/os"
 	"github.com/juju/utils/proxy"
@@ -27,6 +28,7 @@ import (
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network/ssh"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
@@ -151,6 +153,11 @@ func (w *unixConfigure) ConfigureBasic() error {
 		w.addCleanShutdownJob(service.InitSystemSystemd)
 	}
 	SetUbuntuUser(w.conf, w.icfg.AuthorizedKeys)
+	if w.icfg.SSHUserCAPublicKey != "" && w.icfg.APIInfo != nil {
+		w.conf.AddScripts(ssh.TrustUserCAScript(
+			w.icfg.SSHUserCAPublicKey, w.icfg.APIInfo.ModelTag.Id(),
+		))
+	}
 
 	if w.icfg.Bootstrap != nil {
 		// For the bootstrap machine only, we set the host keys
@@ -446,8 +453,14 @@ func (w *unixConfigure) addDownloadToolsCmds() error {
 			// based on space overlap.
 			curlCommand += " --connect-timeout 20"
 
-			// Don't go through the proxy when downloading tools from the controllers
-			curlCommand += ` --noproxy "*"`
+			// Don't go through the HTTP proxy when downloading tools from the
+			// controllers; if the controllers are only reachable through the
+			// controller proxy, use that instead.
+			if controllerProxy := w.icfg.AgentEnvironment[agent.ControllerProxy]; controllerProxy != "" {
+				curlCommand += " --proxy " + utils.ShQuote(controllerProxy)
+			} else {
+				curlCommand += ` --noproxy "*"`
+			}
 
 			// Our API server certificates are unusable by curl (invalid subject name),
 			// so we must disable certificate validation. It doesn't actually
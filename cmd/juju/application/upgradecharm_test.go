@@ -695,6 +695,10 @@ func (m *mockAPIConnection) PublicDNSName() string {
 	return ""
 }
 
+func (m *mockAPIConnection) ControllerCACert() string {
+	return ""
+}
+
 func (m *mockAPIConnection) APIHostPorts() [][]network.HostPort {
 	p, _ := network.ParseHostPorts(m.Addr())
 	return [][]network.HostPort{p}
@@ -34,7 +34,7 @@ func (s *listSuite) TestListPublic(c *gc.C) {
 	// LXD should be there too.
 	c.Assert(out, gc.Matches, `.*localhost[ ]*1[ ]*localhost[ ]*lxd.*`)
 	// The private provider types should be there also.
-	c.Assert(out, gc.Matches, `.*maas, manual, openstack, oracle, vsphere.*`)
+	c.Assert(out, gc.Matches, `.*libvirt, maas, manual, openstack, oracle, vsphere.*`)
 }
 
 func (s *listSuite) TestListPublicAndPersonal(c *gc.C) {
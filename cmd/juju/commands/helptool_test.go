@@ -52,6 +52,10 @@ Currently available charm hook tools are:
     relation-ids             list all relation ids with the given relation name
     relation-list            list relation units
     relation-set             set relation settings
+    secret-get               print the values of a secret
+    secret-remove            remove a secret
+    secret-set               create or update a secret
+    secret-share             share a secret with a related application
     status-get               print status information
     status-set               set status information
     storage-add              add storage instances
@@ -95,6 +99,10 @@ var expectedCommands = []string{
 	"relation-list",
 	"relation-set",
 	"resource-get",
+	"secret-get",
+	"secret-remove",
+	"secret-set",
+	"secret-share",
 	"status-get",
 	"status-set",
 	"storage-add",
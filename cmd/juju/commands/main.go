@@ -347,6 +347,16 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(user.NewLogoutCommand())
 	r.Register(user.NewRemoveCommand())
 	r.Register(user.NewWhoAmICommand())
+	r.Register(user.NewAddTokenCommand())
+	r.Register(user.NewTokensCommand())
+	r.Register(user.NewRevokeTokenCommand())
+	r.Register(user.NewEnableMFACommand())
+	r.Register(user.NewDisableMFACommand())
+	r.Register(user.NewAddGroupCommand())
+	r.Register(user.NewRemoveGroupCommand())
+	r.Register(user.NewAddToGroupCommand())
+	r.Register(user.NewRemoveFromGroupCommand())
+	r.Register(user.NewGroupsCommand())
 
 	// Manage cached images
 	r.Register(cachedimages.NewRemoveCommand())
@@ -355,8 +365,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage machines
 	r.Register(machine.NewAddCommand())
 	r.Register(machine.NewRemoveCommand())
+	r.Register(machine.NewReplaceCommand())
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
+	r.Register(machine.NewRecommendInstanceTypesCommand())
 
 	// Manage model
 	r.Register(model.NewConfigCommand())
@@ -435,6 +447,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(controller.NewRegisterCommand())
 	r.Register(controller.NewUnregisterCommand(jujuclient.NewFileClientStore()))
 	r.Register(controller.NewEnableDestroyControllerCommand())
+	r.Register(controller.NewRotateControllerCACommand())
 	r.Register(controller.NewShowControllerCommand())
 	r.Register(controller.NewConfigCommand())
 
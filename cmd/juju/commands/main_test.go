@@ -389,6 +389,7 @@ var commandNames = []string{
 	"actions",
 	"add-cloud",
 	"add-credential",
+	"add-group",
 	"add-machine",
 	"add-model",
 	"add-relation",
@@ -396,6 +397,8 @@ var commandNames = []string{
 	"add-ssh-key",
 	"add-storage",
 	"add-subnet",
+	"add-to-group",
+	"add-token",
 	"add-unit",
 	"add-user",
 	"agree",
@@ -429,12 +432,14 @@ var commandNames = []string{
 	"destroy-model",
 	"detach-storage",
 	"disable-command",
+	"disable-mfa",
 	"disable-user",
 	"disabled-commands",
 	"download-backup",
 	"enable-command",
 	"enable-destroy-controller",
 	"enable-ha",
+	"enable-mfa",
 	"enable-user",
 	"expose",
 	"find-offers",
@@ -442,6 +447,7 @@ var commandNames = []string{
 	"get-constraints",
 	"get-model-constraints",
 	"grant",
+	"groups",
 	"gui",
 	"help",
 	"help-tool",
@@ -460,6 +466,7 @@ var commandNames = []string{
 	"list-credentials",
 	"list-disabled-commands",
 	"list-firewall-rules",
+	"list-groups",
 	"list-machines",
 	"list-models",
 	"list-offers",
@@ -472,6 +479,7 @@ var commandNames = []string{
 	"list-storage",
 	"list-storage-pools",
 	"list-subnets",
+	"list-tokens",
 	"list-users",
 	"list-wallets",
 	"login",
@@ -487,6 +495,7 @@ var commandNames = []string{
 	"offers",
 	"payloads",
 	"plans",
+	"recommend-instance-types",
 	"regions",
 	"register",
 	"relate", //alias for add-relation
@@ -497,6 +506,8 @@ var commandNames = []string{
 	"remove-cloud",
 	"remove-consumed-application",
 	"remove-credential",
+	"remove-from-group",
+	"remove-group",
 	"remove-machine",
 	"remove-offer",
 	"remove-relation",
@@ -505,6 +516,7 @@ var commandNames = []string{
 	"remove-storage",
 	"remove-unit",
 	"remove-user",
+	"replace-machine",
 	"resolved",
 	"resolve",
 	"resources",
@@ -512,6 +524,8 @@ var commandNames = []string{
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
+	"revoke-token",
+	"rotate-controller-ca",
 	"run",
 	"run-action",
 	"scp",
@@ -550,6 +564,7 @@ var commandNames = []string{
 	"switch",
 	"sync-agent-binaries",
 	"sync-tools",
+	"tokens",
 	"unexpose",
 	"unregister",
 	"update-clouds",
@@ -50,6 +50,7 @@ type sshAPIClient interface {
 	PrivateAddress(target string) (string, error)
 	AllAddresses(target string) ([]string, error)
 	PublicKeys(target string) ([]string, error)
+	SignUserKey(publicKey string) (string, error)
 	Proxy() (bool, error)
 	Close() error
 }
@@ -190,6 +191,15 @@ func (c *SSHCommon) getSSHOptions(enablePty bool, targets ...*resolvedTarget) (*
 		}
 	}
 
+	for _, target := range targets {
+		if target.isAgent() {
+			if err := c.signUserKeys(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			break
+		}
+	}
+
 	if enablePty {
 		options.EnablePTY()
 	}
@@ -244,6 +254,36 @@ func (c *SSHCommon) generateKnownHosts(targets []*resolvedTarget) (string, error
 	return c.knownHostsPath, nil
 }
 
+// signUserKeys asks the controller to sign the client's SSH public
+// keys, writing each certificate alongside its private key where
+// OpenSSH finds it. Machines trust the controller's SSH certificate
+// authority, so the short-lived certificates let the user log in
+// without their key having been distributed to authorized_keys.
+// Controllers that cannot sign keys are ignored.
+func (c *SSHCommon) signUserKeys() error {
+	for _, publicKeyPath := range ssh.PublicKeyFiles() {
+		if !strings.HasSuffix(publicKeyPath, ".pub") {
+			continue
+		}
+		publicKey, err := ioutil.ReadFile(publicKeyPath)
+		if err != nil {
+			return errors.Annotate(err, "reading SSH public key")
+		}
+		cert, err := c.apiClient.SignUserKey(string(publicKey))
+		if errors.IsNotSupported(err) {
+			logger.Debugf("controller cannot sign SSH keys")
+			return nil
+		} else if err != nil {
+			return errors.Annotatef(err, "signing SSH public key %q", publicKeyPath)
+		}
+		certPath := strings.TrimSuffix(publicKeyPath, ".pub") + "-cert.pub"
+		if err := utils.AtomicWriteFile(certPath, []byte(cert), 0644); err != nil {
+			return errors.Annotate(err, "writing SSH certificate")
+		}
+	}
+	return nil
+}
+
 // proxySSH returns false if both c.proxy and the proxy-ssh model
 // configuration are false -- otherwise it returns true.
 func (c *SSHCommon) proxySSH() (bool, error) {
@@ -8,11 +8,15 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"reflect"
 
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/ssh"
+	cryptossh "golang.org/x/crypto/ssh"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver"
@@ -239,6 +243,26 @@ func (s *SSHSuite) TestSSHCommandModelConfigProxySSH(c *gc.C) {
 
 }
 
+func (s *SSHSuite) TestSSHCommandSignsUserKey(c *gc.C) {
+	s.setupModel(c)
+	keyDir := c.MkDir()
+	err := ssh.LoadClientKeys(keyDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	hostChecker := validAddresses("0.private", "0.public", "0.1.2.3")
+	_, err = cmdtesting.RunCommand(c, newSSHCommand(hostChecker, nil), "0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(filepath.Join(keyDir, "juju_id_rsa-cert.pub"))
+	c.Assert(err, jc.ErrorIsNil)
+	pub, _, _, _, err := cryptossh.ParseAuthorizedKey(data)
+	c.Assert(err, jc.ErrorIsNil)
+	cert, ok := pub.(*cryptossh.Certificate)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(cert.CertType, gc.Equals, uint32(cryptossh.UserCert))
+	c.Check(cert.ValidPrincipals, jc.DeepEquals, []string{"ubuntu"})
+}
+
 func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	// Check the API client interface used by "juju ssh" against what
 	// the API server will allow during upgrades. Ensure that the API
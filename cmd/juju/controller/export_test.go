@@ -92,6 +92,16 @@ func NewEnableDestroyControllerCommandForTest(api removeBlocksAPI, store jujucli
 	return modelcmd.WrapController(c)
 }
 
+// NewRotateControllerCACommandForTest returns a rotateControllerCACommand
+// with the function used to open the API connection mocked out.
+func NewRotateControllerCACommandForTest(api rotateControllerCAAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &rotateControllerCACommand{
+		api: api,
+	}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
 // NewDestroyCommandForTest returns a DestroyCommand with the controller and
 // client endpoints mocked out.
 func NewDestroyCommandForTest(
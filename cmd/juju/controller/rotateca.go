@@ -0,0 +1,192 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	jujucontroller "github.com/juju/juju/controller"
+)
+
+// NewRotateControllerCACommand returns a command to rotate the
+// controller's CA.
+func NewRotateControllerCACommand() cmd.Command {
+	return modelcmd.WrapController(&rotateControllerCACommand{})
+}
+
+type rotateControllerCACommand struct {
+	modelcmd.ControllerCommandBase
+	api rotateControllerCAAPI
+
+	step string
+}
+
+type rotateControllerCAAPI interface {
+	Close() error
+	ControllerCARotation() (params.ControllerCARotation, error)
+	StartControllerCARotation() error
+	ReissueControllerCerts() error
+	FinishControllerCARotation() error
+	ControllerConfig() (jujucontroller.Config, error)
+}
+
+const (
+	rotateStepStart   = "start"
+	rotateStepReissue = "reissue"
+	rotateStepFinish  = "finish"
+)
+
+var rotateControllerCADoc = `
+Replaces the CA that signs the controller's certificates, without
+redeploying agents. Rotation takes three steps, run in order:
+
+    start    Generate a new CA. Agents are sent the new CA at once,
+             and clients trust it from their next login.
+    reissue  Controllers reissue their server certificates with the
+             new CA, and mongo is restarted to serve them. Both CAs
+             are still trusted.
+    finish   Retire the old CA, so that only the new CA is trusted.
+
+Reissue is refused until every agent has acknowledged the new CA, and
+finish until every controller has reissued its certificate as well.
+Run this command without a step to see how far a rotation has got,
+including the agents and controllers still to catch up.
+
+Clients other than this one pick up the new CA when they next connect.
+
+Examples:
+    juju rotate-controller-ca
+    juju rotate-controller-ca start
+    juju rotate-controller-ca reissue
+    juju rotate-controller-ca finish
+
+See also:
+    controller-config
+    show-controller
+`
+
+// Info implements Command.Info.
+func (c *rotateControllerCACommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "rotate-controller-ca",
+		Args:    "[start|reissue|finish]",
+		Purpose: "Rotates the controller's CA certificate.",
+		Doc:     rotateControllerCADoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *rotateControllerCACommand) Init(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case rotateStepStart, rotateStepReissue, rotateStepFinish:
+			c.step = args[0]
+		default:
+			return errors.Errorf("unknown step %q, expected start, reissue or finish", args[0])
+		}
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *rotateControllerCACommand) getAPI() (rotateControllerCAAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewControllerAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *rotateControllerCACommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	switch c.step {
+	case "":
+		return c.showRotation(ctx, client)
+	case rotateStepStart:
+		err = client.StartControllerCARotation()
+	case rotateStepReissue:
+		err = client.ReissueControllerCerts()
+	case rotateStepFinish:
+		err = client.FinishControllerCARotation()
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// This client trusts the changed CA at once, rather than
+	// waiting for its next login.
+	if err := c.updateCACert(client); err != nil {
+		return errors.Annotate(err, "cannot record controller CA certificate")
+	}
+	return c.showRotation(ctx, client)
+}
+
+func (c *rotateControllerCACommand) showRotation(ctx *cmd.Context, client rotateControllerCAAPI) error {
+	rotation, err := client.ControllerCARotation()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch rotation.Phase {
+	case "":
+		ctx.Infof("No controller CA rotation in progress.")
+	case "trusting":
+		ctx.Infof("Controller CA rotation started %s: both CAs are trusted.", formatStarted(rotation.Started))
+		showPending(ctx, "Agents yet to acknowledge the new CA", rotation.PendingAgents)
+		ctx.Infof("Once every agent has acknowledged the new CA, run %q.", "juju rotate-controller-ca reissue")
+	case "reissued":
+		ctx.Infof("Controller CA rotation started %s: certificates are signed by the new CA.", formatStarted(rotation.Started))
+		showPending(ctx, "Agents yet to acknowledge the new CA", rotation.PendingAgents)
+		showPending(ctx, "Controllers yet to reissue their certificates", rotation.PendingControllers)
+		ctx.Infof("Once every controller has reissued its certificate, run %q.", "juju rotate-controller-ca finish")
+	default:
+		ctx.Infof("Controller CA rotation in phase %q.", rotation.Phase)
+	}
+	return nil
+}
+
+func showPending(ctx *cmd.Context, what string, pending []string) {
+	if len(pending) == 0 {
+		return
+	}
+	ctx.Infof("%s: %s", what, strings.Join(pending, ", "))
+}
+
+func formatStarted(t *time.Time) string {
+	if t == nil {
+		return "at an unknown time"
+	}
+	return t.Local().Format(time.RFC1123)
+}
+
+func (c *rotateControllerCACommand) updateCACert(client rotateControllerCAAPI) error {
+	cfg, err := client.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	caCert, ok := cfg.CACert()
+	if !ok {
+		return nil
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store := c.ClientStore()
+	details, err := store.ControllerByName(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	details.CACert = caCert
+	return errors.Trace(store.UpdateController(controllerName, *details))
+}
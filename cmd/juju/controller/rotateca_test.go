@@ -0,0 +1,170 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/controller"
+	jujucontroller "github.com/juju/juju/controller"
+	"github.com/juju/juju/jujuclient"
+)
+
+type rotateControllerCASuite struct {
+	baseControllerSuite
+	api   *fakeRotateControllerCAAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&rotateControllerCASuite{})
+
+func (s *rotateControllerCASuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+
+	s.api = &fakeRotateControllerCAAPI{caCert: "old-ca"}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{CACert: "old-ca"}
+}
+
+func (s *rotateControllerCASuite) newCommand() cmd.Command {
+	return controller.NewRotateControllerCACommandForTest(s.api, s.store)
+}
+
+func (s *rotateControllerCASuite) TestStatusNoRotation(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No controller CA rotation in progress.\n")
+	s.api.CheckCallNames(c, "ControllerCARotation", "Close")
+}
+
+func (s *rotateControllerCASuite) TestStart(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "start")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Matches, `(?s)Controller CA rotation started .*: both CAs are trusted\.\n.*"juju rotate-controller-ca reissue".*`)
+	s.api.CheckCallNames(c, "StartControllerCARotation", "ControllerConfig", "ControllerCARotation", "Close")
+	// The client trusts both CAs straight away.
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "old-ca"+"new-ca")
+}
+
+func (s *rotateControllerCASuite) TestReissue(c *gc.C) {
+	s.api.phase = "trusting"
+	s.api.caCert = "old-ca" + "new-ca"
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "reissue")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "ReissueControllerCerts", "ControllerConfig", "ControllerCARotation", "Close")
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "new-ca"+"old-ca")
+}
+
+func (s *rotateControllerCASuite) TestFinish(c *gc.C) {
+	s.api.phase = "reissued"
+	s.api.caCert = "new-ca" + "old-ca"
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "finish")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No controller CA rotation in progress.\n")
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "new-ca")
+}
+
+func (s *rotateControllerCASuite) TestStatusPending(c *gc.C) {
+	s.api.phase = "reissued"
+	s.api.pendingAgents = []string{"deadbeef:machine-1", "deadbeef:unit-mysql-0"}
+	s.api.pendingControllers = []string{"0", "2"}
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Matches, `(?s)Controller CA rotation started .*
+Agents yet to acknowledge the new CA: deadbeef:machine-1, deadbeef:unit-mysql-0
+Controllers yet to reissue their certificates: 0, 2
+.*"juju rotate-controller-ca finish".*`)
+}
+
+func (s *rotateControllerCASuite) TestStepError(c *gc.C) {
+	s.api.SetErrors(common.ErrPerm)
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "start")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.api.CheckCallNames(c, "StartControllerCARotation", "Close")
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "old-ca")
+}
+
+func (s *rotateControllerCASuite) TestUnknownStep(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "rewind")
+	c.Assert(err, gc.ErrorMatches, `unknown step "rewind", expected start, reissue or finish`)
+}
+
+func (s *rotateControllerCASuite) TestTooManyArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "start", "now")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["now"\]`)
+}
+
+// fakeRotateControllerCAAPI models the controller's side of a
+// rotation from "old-ca" to "new-ca".
+type fakeRotateControllerCAAPI struct {
+	testing.Stub
+	phase  string
+	caCert string
+
+	pendingAgents      []string
+	pendingControllers []string
+}
+
+func (f *fakeRotateControllerCAAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeRotateControllerCAAPI) ControllerCARotation() (params.ControllerCARotation, error) {
+	f.MethodCall(f, "ControllerCARotation")
+	if f.phase == "" {
+		return params.ControllerCARotation{}, f.NextErr()
+	}
+	started := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	return params.ControllerCARotation{
+		Phase:     f.phase,
+		OldCACert: "old-ca",
+		NewCACert: "new-ca",
+		Started:   &started,
+
+		PendingAgents:      f.pendingAgents,
+		PendingControllers: f.pendingControllers,
+	}, f.NextErr()
+}
+
+func (f *fakeRotateControllerCAAPI) StartControllerCARotation() error {
+	f.MethodCall(f, "StartControllerCARotation")
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	f.phase, f.caCert = "trusting", "old-ca"+"new-ca"
+	return nil
+}
+
+func (f *fakeRotateControllerCAAPI) ReissueControllerCerts() error {
+	f.MethodCall(f, "ReissueControllerCerts")
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	f.phase, f.caCert = "reissued", "new-ca"+"old-ca"
+	return nil
+}
+
+func (f *fakeRotateControllerCAAPI) FinishControllerCARotation() error {
+	f.MethodCall(f, "FinishControllerCARotation")
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	f.phase, f.caCert = "", "new-ca"
+	return nil
+}
+
+func (f *fakeRotateControllerCAAPI) ControllerConfig() (jujucontroller.Config, error) {
+	f.MethodCall(f, "ControllerConfig")
+	return jujucontroller.Config{jujucontroller.CACertKey: f.caCert}, f.NextErr()
+}
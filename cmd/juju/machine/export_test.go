@@ -57,3 +57,18 @@ func NewRemoveCommandForTest(apiRoot api.Connection, machineAPI RemoveMachineAPI
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
+
+// NewRecommendInstanceTypesCommandForTest returns a
+// recommendInstanceTypesCommand with the APIs provided as specified.
+func NewRecommendInstanceTypesCommandForTest(api RecommendInstanceTypesAPI, applicationAPI ApplicationConstraintsAPI) cmd.Command {
+	return modelcmd.Wrap(&recommendInstanceTypesCommand{
+		api:            api,
+		applicationAPI: applicationAPI,
+	})
+}
+
+// NewReplaceCommandForTest returns a replaceCommand with the api
+// provided as specified.
+func NewReplaceCommandForTest(api ReplaceMachineAPI) cmd.Command {
+	return modelcmd.Wrap(&replaceCommand{api: api})
+}
@@ -0,0 +1,347 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/constraints"
+)
+
+const (
+	defaultRecommendWindow   = 7 * 24 * time.Hour
+	defaultRecommendHeadroom = 20
+)
+
+const recommendInstanceTypesDoc = `
+Machine agents periodically sample the CPU, memory and root disk usage
+of their machines. This command compares the usage of the machines
+hosting each application over a window of time against the instance
+types offered by the model's cloud, and recommends the cheapest
+instance type that would accommodate the application.
+
+CPU is sized for the 95th percentile of the samples, and memory and
+disk for the peak usage, plus the requested headroom. The recommended
+action is "downsize" if a cheaper instance type would do, "upsize" if
+the application's machines are too small, and "keep" otherwise.
+
+If no applications are specified, recommendations are made for all
+applications in the model.
+
+With --apply, the cores and memory constraints of each application
+that should be resized are updated to select the recommended instance
+type for new machines. Existing machines are not changed.
+
+Examples:
+
+    juju recommend-instance-types
+    juju recommend-instance-types mysql wordpress --window 72h
+    juju recommend-instance-types mysql --headroom 50 --apply
+
+See also:
+    constraints
+    set-constraints
+`
+
+// NewRecommendInstanceTypesCommand returns a command that recommends
+// instance types for applications based on their machines' usage.
+func NewRecommendInstanceTypesCommand() cmd.Command {
+	return modelcmd.Wrap(&recommendInstanceTypesCommand{})
+}
+
+// RecommendInstanceTypesAPI defines the API methods used by the
+// recommend-instance-types command.
+type RecommendInstanceTypesAPI interface {
+	InstanceTypeRecommendations(applications []string, window time.Duration, headroom float64) ([]params.InstanceTypeRecommendationResult, error)
+	Close() error
+}
+
+// ApplicationConstraintsAPI defines the API methods used by the
+// recommend-instance-types command to apply its recommendations.
+type ApplicationConstraintsAPI interface {
+	GetConstraints(...string) ([]constraints.Value, error)
+	SetConstraints(string, constraints.Value) error
+	Close() error
+}
+
+// recommendInstanceTypesCommand recommends instance types for
+// applications.
+type recommendInstanceTypesCommand struct {
+	modelcmd.ModelCommandBase
+	out            cmd.Output
+	api            RecommendInstanceTypesAPI
+	applicationAPI ApplicationConstraintsAPI
+
+	applications []string
+	window       time.Duration
+	headroom     int
+	apply        bool
+}
+
+// InstanceTypeRecommendation holds an instance type recommendation
+// for display.
+type InstanceTypeRecommendation struct {
+	Application     string   `yaml:"application" json:"application"`
+	Machines        []string `yaml:"machines" json:"machines"`
+	Samples         int      `yaml:"samples" json:"samples"`
+	CPUCores        float64  `yaml:"cpu-cores" json:"cpu-cores"`
+	Memory          string   `yaml:"memory" json:"memory"`
+	RootDisk        string   `yaml:"root-disk" json:"root-disk"`
+	Current         string   `yaml:"current,omitempty" json:"current,omitempty"`
+	CurrentCost     string   `yaml:"current-cost,omitempty" json:"current-cost,omitempty"`
+	Recommended     string   `yaml:"recommended" json:"recommended"`
+	RecommendedCost string   `yaml:"recommended-cost,omitempty" json:"recommended-cost,omitempty"`
+	Action          string   `yaml:"action" json:"action"`
+	Constraints     string   `yaml:"constraints" json:"constraints"`
+	Applied         bool     `yaml:"applied,omitempty" json:"applied,omitempty"`
+}
+
+// Info implements Command.Info.
+func (c *recommendInstanceTypesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "recommend-instance-types",
+		Args:    "[<application> ...]",
+		Purpose: "Recommends instance types for applications based on machine usage.",
+		Doc:     recommendInstanceTypesDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *recommendInstanceTypesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.DurationVar(&c.window, "window", defaultRecommendWindow, "How far back to consider machine usage")
+	f.IntVar(&c.headroom, "headroom", defaultRecommendHeadroom, "Percentage to add to the observed usage when sizing instance types")
+	f.BoolVar(&c.apply, "apply", false, "Set application constraints to select the recommended instance types")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRecommendationsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *recommendInstanceTypesCommand) Init(args []string) error {
+	for _, arg := range args {
+		if !names.IsValidApplication(arg) {
+			return errors.NotValidf("application name %q", arg)
+		}
+	}
+	if c.window <= 0 {
+		return errors.Errorf("--window must be positive")
+	}
+	if c.headroom < 0 {
+		return errors.Errorf("--headroom must not be negative")
+	}
+	c.applications = args
+	return nil
+}
+
+func (c *recommendInstanceTypesCommand) getAPI() (RecommendInstanceTypesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *recommendInstanceTypesCommand) getApplicationAPI() (ApplicationConstraintsAPI, error) {
+	if c.applicationAPI != nil {
+		return c.applicationAPI, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *recommendInstanceTypesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.InstanceTypeRecommendations(c.applications, c.window, float64(c.headroom)/100)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var anyFailed bool
+	var recommendations []params.InstanceTypeRecommendation
+	for i, result := range results {
+		if result.Error != nil {
+			anyFailed = true
+			if len(c.applications) > 0 {
+				ctx.Infof("cannot recommend instance type for %s: %s", c.applications[i], result.Error)
+			} else {
+				ctx.Infof("cannot recommend instance type: %s", result.Error)
+			}
+			continue
+		}
+		recommendations = append(recommendations, *result.Result)
+	}
+
+	applied := make(map[string]bool)
+	if c.apply {
+		if err := c.applyRecommendations(ctx, recommendations, applied); err != nil {
+			return err
+		}
+	}
+
+	infos := make([]InstanceTypeRecommendation, len(recommendations))
+	for i, r := range recommendations {
+		infos[i] = InstanceTypeRecommendation{
+			Application: r.Application,
+			Machines:    r.Machines,
+			Samples:     r.Samples,
+			CPUCores:    r.CPUCores,
+			Memory:      formatMiB(r.Memory),
+			RootDisk:    formatMiB(r.RootDisk),
+			Recommended: r.Recommended.Name,
+			Action:      r.Action,
+			Constraints: r.Constraints.String(),
+			Applied:     applied[r.Application],
+		}
+		infos[i].RecommendedCost = formatCost(r.Recommended.Cost, r)
+		if r.Current != nil {
+			infos[i].Current = r.Current.Name
+			infos[i].CurrentCost = formatCost(r.Current.Cost, r)
+		}
+	}
+	if err := c.out.Write(ctx, infos); err != nil {
+		return errors.Trace(err)
+	}
+	// Failures are expected for some applications when considering
+	// the whole model, e.g. those without units; only fail for those
+	// explicitly requested.
+	if anyFailed && len(c.applications) > 0 {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+// applyRecommendations sets the constraints of each application that
+// should be resized, recording those that were updated in applied.
+func (c *recommendInstanceTypesCommand) applyRecommendations(
+	ctx *cmd.Context,
+	recommendations []params.InstanceTypeRecommendation,
+	applied map[string]bool,
+) error {
+	var resize []params.InstanceTypeRecommendation
+	for _, r := range recommendations {
+		if r.Action != params.RecommendKeep {
+			resize = append(resize, r)
+		}
+	}
+	if len(resize) == 0 {
+		return nil
+	}
+
+	api, err := c.getApplicationAPI()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	for _, r := range resize {
+		existing, err := api.GetConstraints(r.Application)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cons := existing[0]
+		// An instance type constraint would override the
+		// recommended cores and memory.
+		cons.InstanceType = nil
+		cons.CpuCores = r.Constraints.CpuCores
+		cons.Mem = r.Constraints.Mem
+		if r.Constraints.RootDisk != nil {
+			cons.RootDisk = r.Constraints.RootDisk
+		}
+		err = api.SetConstraints(r.Application, cons)
+		if err := block.ProcessBlockedError(err, block.BlockChange); err != nil {
+			return err
+		}
+		ctx.Infof("set constraints for %s to %q", r.Application, cons.String())
+		applied[r.Application] = true
+	}
+	return nil
+}
+
+func formatMiB(mib uint64) string {
+	if mib >= 1024 && mib%1024 == 0 {
+		return fmt.Sprintf("%dG", mib/1024)
+	}
+	if mib >= 1024 {
+		return fmt.Sprintf("%.1fG", float64(mib)/1024)
+	}
+	return fmt.Sprintf("%dM", mib)
+}
+
+func formatCost(cost int, r params.InstanceTypeRecommendation) string {
+	if cost == 0 {
+		return ""
+	}
+	value := strconv.Itoa(cost)
+	if r.CostDivisor > 0 {
+		value = strconv.FormatFloat(float64(cost)/float64(r.CostDivisor), 'f', -1, 64)
+	}
+	if r.CostUnit != "" {
+		value += " " + r.CostUnit
+	}
+	return value
+}
+
+func formatRecommendationsTabular(writer io.Writer, value interface{}) error {
+	recommendations, ok := value.([]InstanceTypeRecommendation)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", recommendations, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("App", "Machines", "Samples", "CPU", "Memory", "Disk", "Current", "Recommended", "Action", "Constraints")
+	for _, r := range recommendations {
+		current := r.Current
+		if current == "" {
+			current = "-"
+		}
+		action := r.Action
+		if r.Applied {
+			action += " (applied)"
+		}
+		w.Println(
+			r.Application,
+			strings.Join(r.Machines, ","),
+			r.Samples,
+			strconv.FormatFloat(r.CPUCores, 'f', 2, 64),
+			r.Memory,
+			r.RootDisk,
+			current,
+			r.Recommended,
+			action,
+			r.Constraints,
+		)
+	}
+	tw.Flush()
+	return nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type RecommendInstanceTypesSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api    *fakeRecommendAPI
+	appAPI *fakeApplicationConstraintsAPI
+}
+
+var _ = gc.Suite(&RecommendInstanceTypesSuite{})
+
+func (s *RecommendInstanceTypesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	cores, mem := uint64(1), uint64(2048)
+	keepCores, keepMem := uint64(2), uint64(4096)
+	s.api = &fakeRecommendAPI{
+		results: []params.InstanceTypeRecommendationResult{{
+			Result: &params.InstanceTypeRecommendation{
+				Application: "mysql",
+				Machines:    []string{"0", "1"},
+				Samples:     24,
+				CPUCores:    0.4,
+				Memory:      1024,
+				RootDisk:    3584,
+				Current:     &params.InstanceType{Name: "large", Cost: 40},
+				Recommended: params.InstanceType{Name: "small", Cost: 10},
+				Action:      params.RecommendDownsize,
+				Constraints: constraints.Value{CpuCores: &cores, Mem: &mem},
+				CostUnit:    "USD/h",
+				CostDivisor: 100,
+			},
+		}, {
+			Result: &params.InstanceTypeRecommendation{
+				Application: "wordpress",
+				Machines:    []string{"2"},
+				Samples:     12,
+				CPUCores:    1.5,
+				Memory:      3000,
+				RootDisk:    1024,
+				Recommended: params.InstanceType{Name: "medium"},
+				Action:      params.RecommendKeep,
+				Constraints: constraints.Value{CpuCores: &keepCores, Mem: &keepMem},
+			},
+		}},
+	}
+	s.appAPI = &fakeApplicationConstraintsAPI{
+		constraints: constraints.MustParse("instance-type=large spaces=db"),
+	}
+}
+
+func (s *RecommendInstanceTypesSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := machine.NewRecommendInstanceTypesCommandForTest(s.api, s.appAPI)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *RecommendInstanceTypesSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"mysql", "wordpress"},
+	}, {
+		args: []string{"--window", "24h", "--headroom", "0"},
+	}, {
+		args: []string{"mysql/0"},
+		err:  `application name "mysql/0" not valid`,
+	}, {
+		args: []string{"--window", "0s"},
+		err:  "--window must be positive",
+	}, {
+		args: []string{"--headroom", "-10"},
+		err:  "--headroom must not be negative",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := machine.NewRecommendInstanceTypesCommandForTest(s.api, s.appAPI)
+		err := cmdtesting.InitCommand(command, test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *RecommendInstanceTypesSuite) TestRecommendTabular(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+App        Machines  Samples  CPU   Memory  Disk  Current  Recommended  Action    Constraints
+mysql      0,1       24       0.40  1G      3.5G  large    small        downsize  cores=1 mem=2048M
+wordpress  2         12       1.50  2.9G    1G    -        medium       keep      cores=2 mem=4096M
+`[1:])
+	s.api.CheckCall(c, 0, "InstanceTypeRecommendations", []string(nil), 7*24*time.Hour, 0.2)
+	s.appAPI.CheckNoCalls(c)
+}
+
+func (s *RecommendInstanceTypesSuite) TestRecommendYAML(c *gc.C) {
+	s.api.results = s.api.results[:1]
+	ctx, err := s.run(c, "mysql", "--window", "24h", "--headroom", "50", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- application: mysql
+  machines:
+  - "0"
+  - "1"
+  samples: 24
+  cpu-cores: 0.4
+  memory: 1G
+  root-disk: 3.5G
+  current: large
+  current-cost: 0.4 USD/h
+  recommended: small
+  recommended-cost: 0.1 USD/h
+  action: downsize
+  constraints: cores=1 mem=2048M
+`[1:])
+	s.api.CheckCall(c, 0, "InstanceTypeRecommendations", []string{"mysql"}, 24*time.Hour, 0.5)
+}
+
+func (s *RecommendInstanceTypesSuite) TestApply(c *gc.C) {
+	ctx, err := s.run(c, "--apply")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `set constraints for mysql to "cores=1 mem=2048M spaces=db"`+"\n")
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, "downsize (applied)")
+
+	s.appAPI.CheckCallNames(c, "GetConstraints", "SetConstraints", "Close")
+	s.appAPI.CheckCall(c, 0, "GetConstraints", []string{"mysql"})
+	s.appAPI.CheckCall(c, 1, "SetConstraints", "mysql", constraints.MustParse("cores=1 mem=2048M spaces=db"))
+}
+
+func (s *RecommendInstanceTypesSuite) TestErrors(c *gc.C) {
+	s.api.results = []params.InstanceTypeRecommendationResult{
+		s.api.results[0],
+		{Error: &params.Error{Message: `usage samples for application "wordpress" not found`}},
+	}
+	ctx, err := s.run(c, "mysql", "wordpress")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals,
+		`cannot recommend instance type for wordpress: usage samples for application "wordpress" not found`+"\n")
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, "mysql")
+
+	// Errors are reported, but not fatal, when considering all applications.
+	_, err = s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type fakeRecommendAPI struct {
+	jujutesting.Stub
+	results []params.InstanceTypeRecommendationResult
+}
+
+func (f *fakeRecommendAPI) InstanceTypeRecommendations(applications []string, window time.Duration, headroom float64) ([]params.InstanceTypeRecommendationResult, error) {
+	f.MethodCall(f, "InstanceTypeRecommendations", applications, window, headroom)
+	return f.results, f.NextErr()
+}
+
+func (f *fakeRecommendAPI) Close() error {
+	return nil
+}
+
+type fakeApplicationConstraintsAPI struct {
+	jujutesting.Stub
+	constraints constraints.Value
+}
+
+func (f *fakeApplicationConstraintsAPI) GetConstraints(applications ...string) ([]constraints.Value, error) {
+	f.MethodCall(f, "GetConstraints", applications)
+	return []constraints.Value{f.constraints}, f.NextErr()
+}
+
+func (f *fakeApplicationConstraintsAPI) SetConstraints(application string, cons constraints.Value) error {
+	f.MethodCall(f, "SetConstraints", application, cons)
+	return f.NextErr()
+}
+
+func (f *fakeApplicationConstraintsAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
@@ -0,0 +1,175 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const replaceMachineDoc = `
+Replacing a machine provisions a new machine with the same series,
+constraints and placement directives, moves the units of the original
+machine to it, and then removes the original machine. The units keep
+their names and relations; their agents are deployed afresh on the new
+machine, so the charms' install hooks are run again.
+
+This can be used to recover from a machine whose instance has been
+lost or damaged, or to pick up changed constraints.
+
+Storage attached to the units is detached from the original machine
+and attached to the new one. Machines whose units have storage that
+cannot be detached, such as loop devices or the root disk, cannot be
+replaced. Neither can controller machines, containers, machines
+hosting containers or manually provisioned machines.
+
+The original machine is removed in the same way as with remove-machine.
+Machines that have been provisioned are not replaced unless the
+'--force' option is used, which removes the original machine without
+waiting for its agent to shut down cleanly. If the machine's agent is
+not running, for example because its instance is no longer running,
+the machine could not otherwise be removed; if it is running, it keeps
+running the units until it sees that they have been moved, while they
+are deployed on the new machine. Machines that have not been
+provisioned are replaced without '--force'. Machines with no units
+cannot be replaced. If the original machine cannot be removed once its
+units have been moved, the new machine and the moved units are still
+reported.
+
+Examples:
+
+Replace machine 3, which has not yet been provisioned:
+
+    juju replace-machine 3
+
+Replace machine 5, whose instance has been lost:
+
+    juju replace-machine 5 --force
+
+See also:
+    add-machine
+    remove-machine
+`
+
+// NewReplaceCommand returns a command used to replace machines.
+func NewReplaceCommand() cmd.Command {
+	return modelcmd.Wrap(&replaceCommand{})
+}
+
+// ReplaceMachineAPI defines the API methods used by the
+// replace-machine command.
+type ReplaceMachineAPI interface {
+	ReplaceMachines(force bool, machines ...string) ([]params.ReplaceMachineResult, error)
+	Close() error
+}
+
+// replaceCommand replaces existing machines with new ones.
+type replaceCommand struct {
+	modelcmd.ModelCommandBase
+	api        ReplaceMachineAPI
+	MachineIds []string
+	Force      bool
+}
+
+// Info implements Command.Info.
+func (c *replaceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "replace-machine",
+		Args:    "<machine number> ...",
+		Purpose: "Replaces machines with newly provisioned ones, redeploying their units.",
+		Doc:     replaceMachineDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *replaceCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.Force, "force", false, "Forcibly remove the original machines once replaced")
+}
+
+// Init implements Command.Init.
+func (c *replaceCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return errors.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.MachineIds = args
+	return nil
+}
+
+func (c *replaceCommand) getAPI() (ReplaceMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *replaceCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.ReplaceMachines(c.Force, c.MachineIds...)
+	if err := block.ProcessBlockedError(err, block.BlockRemove); err != nil {
+		return err
+	}
+
+	anyFailed := false
+	for i, id := range c.MachineIds {
+		result := results[i]
+		if result.Error != nil {
+			anyFailed = true
+			ctx.Infof("replacing machine %s failed: %s", id, result.Error)
+			if result.Info == nil {
+				continue
+			}
+		}
+		replacement, err := names.ParseMachineTag(result.Info.Replacement)
+		if err != nil {
+			anyFailed = true
+			ctx.Infof("replacing machine %s failed: %s", id, err)
+			continue
+		}
+		ctx.Infof("replacing machine %s with machine %s", id, replacement.Id())
+		for _, entity := range result.Info.MovedUnits {
+			unitTag, err := names.ParseUnitTag(entity.Tag)
+			if err != nil {
+				logger.Warningf("%s", err)
+				continue
+			}
+			ctx.Infof("- will redeploy %s", names.ReadableString(unitTag))
+		}
+		for _, entity := range result.Info.MovedStorage {
+			storageTag, err := names.ParseStorageTag(entity.Tag)
+			if err != nil {
+				logger.Warningf("%s", err)
+				continue
+			}
+			ctx.Infof("- will reattach %s", names.ReadableString(storageTag))
+		}
+	}
+
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type ReplaceMachineSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api *fakeReplaceMachineAPI
+}
+
+var _ = gc.Suite(&ReplaceMachineSuite{})
+
+func (s *ReplaceMachineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeReplaceMachineAPI{
+		results: []params.ReplaceMachineResult{{
+			Info: &params.ReplaceMachineInfo{
+				Replacement:  "machine-3",
+				MovedUnits:   []params.Entity{{"unit-foo-0"}, {"unit-bar-0"}},
+				MovedStorage: []params.Entity{{"storage-disks-0"}},
+			},
+		}},
+	}
+}
+
+func (s *ReplaceMachineSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, machine.NewReplaceCommandForTest(s.api), args...)
+}
+
+func (s *ReplaceMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no machines specified",
+	}, {
+		args: []string{"1", "2"},
+	}, {
+		args: []string{"--force", "1"},
+	}, {
+		args: []string{"lxd"},
+		err:  `invalid machine id "lxd"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(machine.NewReplaceCommandForTest(s.api), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *ReplaceMachineSuite) TestReplace(c *gc.C) {
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+replacing machine 1 with machine 3
+- will redeploy unit foo/0
+- will redeploy unit bar/0
+- will reattach storage disks/0
+`[1:])
+	s.api.CheckCallNames(c, "ReplaceMachines", "Close")
+	s.api.CheckCall(c, 0, "ReplaceMachines", false, []string{"1"})
+}
+
+func (s *ReplaceMachineSuite) TestReplaceForce(c *gc.C) {
+	_, err := s.run(c, "--force", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "ReplaceMachines", true, []string{"1"})
+}
+
+func (s *ReplaceMachineSuite) TestReplaceFailed(c *gc.C) {
+	s.api.results = append(s.api.results, params.ReplaceMachineResult{
+		Error: &params.Error{Message: "cannot replace machine 2: machine is required by the model"},
+	})
+	ctx, err := s.run(c, "1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), jc.Contains, `
+replacing machine 2 failed: cannot replace machine 2: machine is required by the model
+`[1:])
+}
+
+func (s *ReplaceMachineSuite) TestReplaceFailedToRemoveOriginal(c *gc.C) {
+	s.api.results[0].Error = &params.Error{
+		Message: "destroying machine 1 after replacing it with machine 3: boom",
+	}
+	ctx, err := s.run(c, "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+replacing machine 1 failed: destroying machine 1 after replacing it with machine 3: boom
+replacing machine 1 with machine 3
+- will redeploy unit foo/0
+- will redeploy unit bar/0
+- will reattach storage disks/0
+`[1:])
+}
+
+func (s *ReplaceMachineSuite) TestReplaceBlocked(c *gc.C) {
+	s.api.SetErrors(common.OperationBlockedError("TestReplaceBlocked"))
+	_, err := s.run(c, "1")
+	testing.AssertOperationWasBlocked(c, err, ".*TestReplaceBlocked.*")
+}
+
+type fakeReplaceMachineAPI struct {
+	jujutesting.Stub
+	results []params.ReplaceMachineResult
+}
+
+func (f *fakeReplaceMachineAPI) ReplaceMachines(force bool, machines ...string) ([]params.ReplaceMachineResult, error) {
+	f.MethodCall(f, "ReplaceMachines", force, machines)
+	return f.results, f.NextErr()
+}
+
+func (f *fakeReplaceMachineAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
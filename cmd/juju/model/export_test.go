@@ -115,4 +115,20 @@ func NewRevokeCommandForTest(modelsApi RevokeModelAPI, offersAPI RevokeOfferAPI,
 	return modelcmd.WrapController(cmd), &RevokeCommand{cmd}
 }
 
+// NewGrantGroupCommandForTest returns a grant command with the user
+// group api provided as specified.
+func NewGrantGroupCommandForTest(groupsApi UserGroupAccessAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &grantCommand{accessCommand: accessCommand{groupsApi: groupsApi}}
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd)
+}
+
+// NewRevokeGroupCommandForTest returns a revoke command with the user
+// group api provided as specified.
+func NewRevokeGroupCommandForTest(groupsApi UserGroupAccessAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &revokeCommand{accessCommand: accessCommand{groupsApi: groupsApi}}
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd)
+}
+
 var GetBudgetAPIClient = &getBudgetAPIClient
@@ -6,6 +6,7 @@ package model
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/applicationoffers"
@@ -69,9 +70,14 @@ Grant user 'sam' 'read' access to application offers 'fred/prod.hosted-mysql' an
 
     juju grant sam read fred/prod.hosted-mysql mary/test.hosted-mysql
 
+Grant the members of user group 'devops' 'admin' access to model 'mymodel':
+
+    juju grant --group devops admin mymodel
+
 See also: 
     revoke
-    add-user`[1:]
+    add-user
+    add-group`[1:]
 
 var usageRevokeSummary = `
 Revokes access from a Juju user for a model, controller, or application offer.`[1:]
@@ -104,11 +110,20 @@ Revoke 'consume' access from user 'sam' for models 'fred/prod.hosted-mysql' and
 
     juju revoke sam consume fred/prod.hosted-mysql mary/test.hosted-mysql
 
+Revoke 'admin' access from user group 'devops' for model 'mymodel',
+leaving its members with 'write' access:
+
+    juju revoke --group devops admin mymodel
+
 See also: 
     grant`[1:]
 
 type accessCommand struct {
 	modelcmd.ControllerCommandBase
+	groupsApi UserGroupAccessAPI
+
+	// Group is true if User names a user group rather than a user.
+	Group bool
 
 	User       string
 	ModelNames []string
@@ -116,9 +131,18 @@ type accessCommand struct {
 	Access     string
 }
 
+// SetFlags implements cmd.Command.
+func (c *accessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.BoolVar(&c.Group, "group", false, "Change the access of a user group rather than a user")
+}
+
 // Init implements cmd.Command.
 func (c *accessCommand) Init(args []string) error {
 	if len(args) < 1 {
+		if c.Group {
+			return errors.New("no group specified")
+		}
 		return errors.New("no user specified")
 	}
 
@@ -177,6 +201,75 @@ func (c *accessCommand) Init(args []string) error {
 	return nil
 }
 
+// UserGroupAccessAPI defines the API functions used by the grant and
+// revoke commands to change the access of user groups.
+type UserGroupAccessAPI interface {
+	Close() error
+	GrantUserGroup(group, access string, target names.Tag) error
+	RevokeUserGroup(group, access string, target names.Tag) error
+	GrantUserGroupOffer(group, access, offerURL string) error
+	RevokeUserGroupOffer(group, access, offerURL string) error
+}
+
+func (c *accessCommand) getGroupAPI() (UserGroupAccessAPI, error) {
+	if c.groupsApi != nil {
+		return c.groupsApi, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// runForGroup changes the access of the user group named by c.User
+// to the models, offers or controller given on the command line.
+func (c *accessCommand) runForGroup(
+	modify func(api UserGroupAccessAPI, group, access string, target names.Tag) error,
+	modifyOffer func(api UserGroupAccessAPI, group, access, offerURL string) error,
+) error {
+	client, err := c.getGroupAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var targets []names.Tag
+	switch {
+	case len(c.ModelNames) > 0:
+		models, err := c.ModelUUIDs(c.ModelNames)
+		if err != nil {
+			return err
+		}
+		for _, uuid := range models {
+			targets = append(targets, names.NewModelTag(uuid))
+		}
+	case len(c.OfferURLs) > 0:
+		if err := setUnsetUsers(c, c.OfferURLs); err != nil {
+			return errors.Trace(err)
+		}
+		for _, url := range c.OfferURLs {
+			err := modifyOffer(client, c.User, c.Access, url.String())
+			if err != nil {
+				return block.ProcessBlockedError(err, block.BlockChange)
+			}
+		}
+		return nil
+	default:
+		controllerName, err := c.ControllerName()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		details, err := c.ClientStore().ControllerByName(controllerName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		targets = append(targets, names.NewControllerTag(details.ControllerUUID))
+	}
+	for _, target := range targets {
+		if err := modify(client, c.User, c.Access, target); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+	return nil
+}
+
 // NewGrantCommand returns a new grant command.
 func NewGrantCommand() cmd.Command {
 	return modelcmd.WrapController(&grantCommand{})
@@ -193,7 +286,7 @@ type grantCommand struct {
 func (c *grantCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "grant",
-		Args:    "<user name> <permission> [<model name> ... | <offer url> ...]",
+		Args:    "<user name> | --group <group name> <permission> [<model name> ... | <offer url> ...]",
 		Purpose: usageGrantSummary,
 		Doc:     usageGrantDetails,
 	}
@@ -241,6 +334,9 @@ type GrantOfferAPI interface {
 
 // Run implements cmd.Command.
 func (c *grantCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		return c.runForGroup(UserGroupAccessAPI.GrantUserGroup, UserGroupAccessAPI.GrantUserGroupOffer)
+	}
 	if len(c.ModelNames) > 0 {
 		return c.runForModel()
 	}
@@ -308,7 +404,7 @@ type revokeCommand struct {
 func (c *revokeCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "revoke",
-		Args:    "<user name> <permission> [<model name> ... | <offer url> ...]",
+		Args:    "<user name> | --group <group name> <permission> [<model name> ... | <offer url> ...]",
 		Purpose: usageRevokeSummary,
 		Doc:     usageRevokeDetails,
 	}
@@ -356,6 +452,9 @@ type RevokeOfferAPI interface {
 
 // Run implements cmd.Command.
 func (c *revokeCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		return c.runForGroup(UserGroupAccessAPI.RevokeUserGroup, UserGroupAccessAPI.RevokeUserGroupOffer)
+	}
 	if len(c.ModelNames) > 0 {
 		return c.runForModel()
 	}
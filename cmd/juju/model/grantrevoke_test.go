@@ -8,8 +8,10 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/cmd/juju/model"
@@ -282,3 +284,84 @@ func (f *fakeOffersGrantRevokeAPI) fake(user, access string, offerURLs ...string
 	f.offerURLs = append(f.offerURLs, offerURLs...)
 	return f.err
 }
+
+type groupGrantRevokeSuite struct {
+	grantRevokeSuite
+	fakeGroupAPI *fakeGroupGrantRevokeAPI
+}
+
+var _ = gc.Suite(&groupGrantRevokeSuite{})
+
+func (s *groupGrantRevokeSuite) SetUpTest(c *gc.C) {
+	s.grantRevokeSuite.SetUpTest(c)
+	s.fakeGroupAPI = &fakeGroupGrantRevokeAPI{}
+	s.store.Controllers["test-master"] = jujuclient.ControllerDetails{
+		ControllerUUID: "deadbeef-1bad-500d-9000-4b1d0d06f00d",
+	}
+}
+
+func (s *groupGrantRevokeSuite) TestGrantModels(c *gc.C) {
+	command := model.NewGrantGroupCommandForTest(s.fakeGroupAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "--group", "devops", "write", "foo", "bar")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fakeGroupAPI.CheckCalls(c, []jujutesting.StubCall{
+		{"GrantUserGroup", []interface{}{"devops", "write", names.NewModelTag(fooModelUUID)}},
+		{"GrantUserGroup", []interface{}{"devops", "write", names.NewModelTag(barModelUUID)}},
+		{"Close", nil},
+	})
+}
+
+func (s *groupGrantRevokeSuite) TestGrantController(c *gc.C) {
+	command := model.NewGrantGroupCommandForTest(s.fakeGroupAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "--group", "devops", "add-model")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fakeGroupAPI.CheckCalls(c, []jujutesting.StubCall{
+		{"GrantUserGroup", []interface{}{"devops", "add-model", names.NewControllerTag("deadbeef-1bad-500d-9000-4b1d0d06f00d")}},
+		{"Close", nil},
+	})
+}
+
+func (s *groupGrantRevokeSuite) TestRevokeOffer(c *gc.C) {
+	command := model.NewRevokeGroupCommandForTest(s.fakeGroupAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "--group", "devops", "consume", "prod.hosted-mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fakeGroupAPI.CheckCalls(c, []jujutesting.StubCall{
+		{"RevokeUserGroupOffer", []interface{}{"devops", "consume", "bob/prod.hosted-mysql"}},
+		{"Close", nil},
+	})
+}
+
+func (s *groupGrantRevokeSuite) TestInitNoGroup(c *gc.C) {
+	command := model.NewGrantGroupCommandForTest(s.fakeGroupAPI, s.store)
+	err := cmdtesting.InitCommand(command, []string{"--group"})
+	c.Assert(err, gc.ErrorMatches, "no group specified")
+}
+
+type fakeGroupGrantRevokeAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeGroupGrantRevokeAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeGroupGrantRevokeAPI) GrantUserGroup(group, access string, target names.Tag) error {
+	f.MethodCall(f, "GrantUserGroup", group, access, target)
+	return f.NextErr()
+}
+
+func (f *fakeGroupGrantRevokeAPI) RevokeUserGroup(group, access string, target names.Tag) error {
+	f.MethodCall(f, "RevokeUserGroup", group, access, target)
+	return f.NextErr()
+}
+
+func (f *fakeGroupGrantRevokeAPI) GrantUserGroupOffer(group, access, offerURL string) error {
+	f.MethodCall(f, "GrantUserGroupOffer", group, access, offerURL)
+	return f.NextErr()
+}
+
+func (f *fakeGroupGrantRevokeAPI) RevokeUserGroupOffer(group, access, offerURL string) error {
+	f.MethodCall(f, "RevokeUserGroupOffer", group, access, offerURL)
+	return f.NextErr()
+}
@@ -215,7 +215,7 @@ func (c *changePasswordCommand) updateUserPassword(ctx *cmd.Context) error {
 			// Log back in with macaroon authentication, so we can
 			// discard the password without having to log back in
 			// immediately.
-			if err := c.recordMacaroon(newPassword); err != nil {
+			if err := c.recordMacaroon(ctx, newPassword); err != nil {
 				return errors.Annotate(err, "recording macaroon")
 			}
 			// Wipe the password from disk. In the event of an
@@ -232,7 +232,7 @@ func (c *changePasswordCommand) updateUserPassword(ctx *cmd.Context) error {
 	return nil
 }
 
-func (c *changePasswordCommand) recordMacaroon(password string) error {
+func (c *changePasswordCommand) recordMacaroon(ctx *cmd.Context, password string) error {
 	accountDetails := &jujuclient.AccountDetails{User: c.accountDetails.User}
 	args, err := c.NewAPIConnectionParams(
 		c.ClientStore(), c.controllerName, "", accountDetails,
@@ -241,8 +241,11 @@ func (c *changePasswordCommand) recordMacaroon(password string) error {
 		return errors.Trace(err)
 	}
 	args.DialOpts.BakeryClient.WebPageVisitor = httpbakery.NewMultiVisitor(
-		authentication.NewVisitor(accountDetails.User, func(string) (string, error) {
+		authentication.NewMFAVisitor(accountDetails.User, func(string) (string, error) {
 			return password, nil
+		}, func(string) (string, error) {
+			fmt.Fprint(ctx.Stderr, "authentication code: ")
+			return readLine(ctx.Stdin)
 		}),
 		args.DialOpts.BakeryClient.WebPageVisitor,
 	)
@@ -32,6 +32,8 @@ Re-enables a previously disabled Juju user.`[1:]
 
 var usageEnableUserDetails = `
 An enabled Juju user is one that can log in to a controller.
+Enabling a user also unlocks a user who has been locked out after
+too many failed logins.
 
 Examples:
     juju enable-user bob
@@ -18,6 +18,7 @@ var (
 	ListModels       = &listModels
 	NewAPIConnection = &newAPIConnection
 	LoginClientStore = &loginClientStore
+	GetIDToken       = &getIDToken
 )
 
 const NoModelsMessage = noModelsMessage
@@ -124,3 +125,85 @@ func NewWhoAmICommandForTest(store jujuclient.ClientStore) cmd.Command {
 	c := &whoAmICommand{store: store}
 	return c
 }
+
+// NewAddTokenCommandForTest returns an add-token command with the api
+// provided as specified.
+func NewAddTokenCommandForTest(api APITokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addTokenCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewTokensCommandForTest returns a tokens command with the api
+// provided as specified.
+func NewTokensCommandForTest(api APITokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &tokensCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewRevokeTokenCommandForTest returns a revoke-token command with the
+// api provided as specified.
+func NewRevokeTokenCommandForTest(api APITokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &revokeTokenCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewEnableMFACommandForTest returns an enable-mfa command with the
+// api provided as specified.
+func NewEnableMFACommandForTest(api MFAAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &enableMFACommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewDisableMFACommandForTest returns a disable-mfa command with the
+// api provided as specified.
+func NewDisableMFACommandForTest(api MFAAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &disableMFACommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewAddGroupCommandForTest returns an add-group command with the api
+// provided as specified.
+func NewAddGroupCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addGroupCommand{userGroupCommandBase: userGroupCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewRemoveGroupCommandForTest returns a remove-group command with the
+// api provided as specified.
+func NewRemoveGroupCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &removeGroupCommand{userGroupCommandBase: userGroupCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewAddToGroupCommandForTest returns an add-to-group command with the
+// api provided as specified.
+func NewAddToGroupCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := newAddToGroupCommand()
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewRemoveFromGroupCommandForTest returns a remove-from-group command
+// with the api provided as specified.
+func NewRemoveFromGroupCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := newRemoveFromGroupCommand()
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewGroupsCommandForTest returns a groups command with the api
+// provided as specified.
+func NewGroupsCommandForTest(api UserGroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &groupsCommand{userGroupCommandBase: userGroupCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
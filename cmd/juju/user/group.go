@@ -0,0 +1,405 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// UserGroupAPI defines the usermanager API methods that the group
+// commands use.
+type UserGroupAPI interface {
+	AddUserGroup(name string) error
+	RemoveUserGroup(name string) error
+	AddUserGroupMembers(group string, usernames ...string) error
+	RemoveUserGroupMembers(group string, usernames ...string) error
+	AddUserGroupExternalGroups(group string, externalGroups ...string) error
+	RemoveUserGroupExternalGroups(group string, externalGroups ...string) error
+	UserGroupInfo(groups ...string) ([]params.UserGroupInfo, error)
+	Close() error
+}
+
+// userGroupCommandBase is embedded by the commands that act on user
+// groups.
+type userGroupCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api UserGroupAPI
+}
+
+func (c *userGroupCommandBase) getAPI() (UserGroupAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+var usageAddGroupSummary = `
+Creates a user group on the controller.`[1:]
+
+var usageAddGroupDetails = `
+A user group collects users so that access to models, offers and the
+controller can be granted to all of them at once with the --group
+option of "juju grant". Members of a group have the greatest of their
+own access and the access granted to any of their groups.
+
+Only controller superusers may create groups. A new group has no
+members; add them with "juju add-to-group".
+
+Examples:
+    juju add-group devops
+
+See also:
+    add-to-group
+    groups
+    grant
+    remove-group`[1:]
+
+// NewAddGroupCommand returns a command to create a user group.
+func NewAddGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&addGroupCommand{})
+}
+
+type addGroupCommand struct {
+	userGroupCommandBase
+	Group string
+}
+
+// Info implements Command.Info.
+func (c *addGroupCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-group",
+		Args:    "<group name>",
+		Purpose: usageAddGroupSummary,
+		Doc:     usageAddGroupDetails,
+	}
+}
+
+// Init implements Command.Init.
+func (c *addGroupCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no group name specified")
+	}
+	c.Group = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *addGroupCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.AddUserGroup(c.Group); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Group %q added", c.Group)
+	return nil
+}
+
+var usageRemoveGroupSummary = `
+Removes a user group from the controller.`[1:]
+
+var usageRemoveGroupDetails = `
+Removes a user group, along with all access granted to it. The group's
+members lose any access they only had through the group.
+
+Examples:
+    juju remove-group devops
+
+See also:
+    add-group
+    groups`[1:]
+
+// NewRemoveGroupCommand returns a command to remove a user group.
+func NewRemoveGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&removeGroupCommand{})
+}
+
+type removeGroupCommand struct {
+	userGroupCommandBase
+	Group string
+}
+
+// Info implements Command.Info.
+func (c *removeGroupCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove-group",
+		Args:    "<group name>",
+		Purpose: usageRemoveGroupSummary,
+		Doc:     usageRemoveGroupDetails,
+	}
+}
+
+// Init implements Command.Init.
+func (c *removeGroupCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no group name specified")
+	}
+	c.Group = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *removeGroupCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RemoveUserGroup(c.Group); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Group %q removed", c.Group)
+	return nil
+}
+
+var usageAddToGroupSummary = `
+Adds users to a user group.`[1:]
+
+var usageAddToGroupDetails = `
+Adds one or more local users to a user group. The users gain any access
+granted to the group.
+
+With --external, maps one or more groups of an external identity
+provider, given as <group>@<domain>, to the user group instead. Users
+of that domain who are members of a mapped group become members of the
+user group when they next log in, or when their directory groups are
+next synced; external users never join a group that has not been
+mapped to one of their groups.
+
+Examples:
+    juju add-to-group devops bob mary
+    juju add-to-group --external devops sre@ldap
+
+See also:
+    add-group
+    remove-from-group
+    groups`[1:]
+
+// NewAddToGroupCommand returns a command to add users to a user group.
+func NewAddToGroupCommand() cmd.Command {
+	return modelcmd.WrapController(newAddToGroupCommand())
+}
+
+func newAddToGroupCommand() *groupMembersCommand {
+	return &groupMembersCommand{
+		name:           "add-to-group",
+		purpose:        usageAddToGroupSummary,
+		doc:            usageAddToGroupDetails,
+		change:         UserGroupAPI.AddUserGroupMembers,
+		changeExternal: UserGroupAPI.AddUserGroupExternalGroups,
+		done:           "added to",
+	}
+}
+
+var usageRemoveFromGroupSummary = `
+Removes users from a user group.`[1:]
+
+var usageRemoveFromGroupDetails = `
+Removes one or more users from a user group. The users lose any access
+they only had through the group.
+
+With --external, removes the mapping of one or more external identity
+provider groups, given as <group>@<domain>, to the user group instead.
+
+Examples:
+    juju remove-from-group devops bob
+    juju remove-from-group --external devops sre@ldap
+
+See also:
+    add-to-group
+    groups`[1:]
+
+// NewRemoveFromGroupCommand returns a command to remove users from a
+// user group.
+func NewRemoveFromGroupCommand() cmd.Command {
+	return modelcmd.WrapController(newRemoveFromGroupCommand())
+}
+
+func newRemoveFromGroupCommand() *groupMembersCommand {
+	return &groupMembersCommand{
+		name:           "remove-from-group",
+		purpose:        usageRemoveFromGroupSummary,
+		doc:            usageRemoveFromGroupDetails,
+		change:         UserGroupAPI.RemoveUserGroupMembers,
+		changeExternal: UserGroupAPI.RemoveUserGroupExternalGroups,
+		done:           "removed from",
+	}
+}
+
+// groupMembersCommand adds users or external groups to, or removes
+// them from, a user group.
+type groupMembersCommand struct {
+	userGroupCommandBase
+
+	name           string
+	purpose        string
+	doc            string
+	change         func(api UserGroupAPI, group string, usernames ...string) error
+	changeExternal func(api UserGroupAPI, group string, externalGroups ...string) error
+	done           string
+
+	External bool
+	Group    string
+	Users    []string
+}
+
+// Info implements Command.Info.
+func (c *groupMembersCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    c.name,
+		Args:    "<group name> <user name>|<external group> ...",
+		Purpose: c.purpose,
+		Doc:     c.doc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *groupMembersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.BoolVar(&c.External, "external", false, "Change the external identity provider groups mapped to the group")
+}
+
+// Init implements Command.Init.
+func (c *groupMembersCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no group name specified")
+	}
+	if len(args) == 1 && c.External {
+		return errors.New("no external groups specified")
+	}
+	if len(args) == 1 {
+		return errors.New("no user names specified")
+	}
+	c.Group, c.Users = args[0], args[1:]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *groupMembersCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	change := c.change
+	if c.External {
+		change = c.changeExternal
+	}
+	if err := change(api, c.Group, c.Users...); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("%s %s group %q", strings.Join(c.Users, ", "), c.done, c.Group)
+	return nil
+}
+
+var usageGroupsSummary = `
+Lists the user groups on the controller.`[1:]
+
+var usageGroupsDetails = `
+Lists the user groups on the controller and their members. Users who
+are not controller superusers only see the groups they are a member of.
+
+Examples:
+    juju groups
+    juju groups --format yaml
+
+See also:
+    add-group
+    add-to-group
+    show-user`[1:]
+
+// NewGroupsCommand returns a command to list user groups.
+func NewGroupsCommand() cmd.Command {
+	return modelcmd.WrapController(&groupsCommand{})
+}
+
+type groupsCommand struct {
+	userGroupCommandBase
+	out cmd.Output
+}
+
+// GroupInfo holds the details of a user group for display.
+type GroupInfo struct {
+	Name           string   `yaml:"name" json:"name"`
+	Members        []string `yaml:"members" json:"members"`
+	ExternalGroups []string `yaml:"external-groups,omitempty" json:"external-groups,omitempty"`
+	CreatedBy      string   `yaml:"created-by" json:"created-by"`
+	DateCreated    string   `yaml:"date-created" json:"date-created"`
+}
+
+// Info implements Command.Info.
+func (c *groupsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "groups",
+		Purpose: usageGroupsSummary,
+		Doc:     usageGroupsDetails,
+		Aliases: []string{"list-groups"},
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *groupsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatGroupsTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *groupsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	groups, err := api.UserGroupInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	infos := make([]GroupInfo, len(groups))
+	for i, g := range groups {
+		infos[i] = GroupInfo{
+			Name:           g.Name,
+			Members:        g.Members,
+			ExternalGroups: g.ExternalGroups,
+			CreatedBy:      g.CreatedBy,
+			DateCreated:    common.FormatTime(&g.DateCreated, false),
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+func formatGroupsTabular(writer io.Writer, value interface{}) error {
+	groups, ok := value.([]GroupInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", groups, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Name", "Members", "External groups", "Created by", "Created")
+	for _, g := range groups {
+		w.Println(g.Name, strings.Join(g.Members, ","), strings.Join(g.ExternalGroups, ","), g.CreatedBy, g.DateCreated)
+	}
+	tw.Flush()
+	return nil
+}
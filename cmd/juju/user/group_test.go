@@ -0,0 +1,190 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/user"
+)
+
+type GroupCommandSuite struct {
+	BaseSuite
+	api *mockUserGroupAPI
+}
+
+var _ = gc.Suite(&GroupCommandSuite{})
+
+type mockUserGroupAPI struct {
+	testing.Stub
+	groups []params.UserGroupInfo
+}
+
+func (m *mockUserGroupAPI) AddUserGroup(name string) error {
+	m.MethodCall(m, "AddUserGroup", name)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) RemoveUserGroup(name string) error {
+	m.MethodCall(m, "RemoveUserGroup", name)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) AddUserGroupMembers(group string, usernames ...string) error {
+	m.MethodCall(m, "AddUserGroupMembers", group, usernames)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) RemoveUserGroupMembers(group string, usernames ...string) error {
+	m.MethodCall(m, "RemoveUserGroupMembers", group, usernames)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) AddUserGroupExternalGroups(group string, externalGroups ...string) error {
+	m.MethodCall(m, "AddUserGroupExternalGroups", group, externalGroups)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) RemoveUserGroupExternalGroups(group string, externalGroups ...string) error {
+	m.MethodCall(m, "RemoveUserGroupExternalGroups", group, externalGroups)
+	return m.NextErr()
+}
+
+func (m *mockUserGroupAPI) UserGroupInfo(groups ...string) ([]params.UserGroupInfo, error) {
+	m.MethodCall(m, "UserGroupInfo", groups)
+	return m.groups, m.NextErr()
+}
+
+func (m *mockUserGroupAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (s *GroupCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.api = &mockUserGroupAPI{}
+}
+
+func (s *GroupCommandSuite) TestAddGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.api, s.store), "devops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Group \"devops\" added\n")
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"AddUserGroup", []interface{}{"devops"}},
+		{"Close", nil},
+	})
+}
+
+func (s *GroupCommandSuite) TestAddGroupInit(c *gc.C) {
+	err := cmdtesting.InitCommand(user.NewAddGroupCommandForTest(s.api, s.store), nil)
+	c.Assert(err, gc.ErrorMatches, "no group name specified")
+	err = cmdtesting.InitCommand(user.NewAddGroupCommandForTest(s.api, s.store), []string{"devops", "qa"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["qa"\]`)
+}
+
+func (s *GroupCommandSuite) TestRemoveGroup(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	_, err := cmdtesting.RunCommand(c, user.NewRemoveGroupCommandForTest(s.api, s.store), "devops")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"RemoveUserGroup", []interface{}{"devops"}},
+		{"Close", nil},
+	})
+}
+
+func (s *GroupCommandSuite) TestAddToGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddToGroupCommandForTest(s.api, s.store), "devops", "bob", "mary")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "bob, mary added to group \"devops\"\n")
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"AddUserGroupMembers", []interface{}{"devops", []string{"bob", "mary"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *GroupCommandSuite) TestRemoveFromGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewRemoveFromGroupCommandForTest(s.api, s.store), "devops", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "bob removed from group \"devops\"\n")
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"RemoveUserGroupMembers", []interface{}{"devops", []string{"bob"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *GroupCommandSuite) TestAddToGroupExternal(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddToGroupCommandForTest(s.api, s.store), "--external", "devops", "sre@ldap")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "sre@ldap added to group \"devops\"\n")
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"AddUserGroupExternalGroups", []interface{}{"devops", []string{"sre@ldap"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *GroupCommandSuite) TestRemoveFromGroupExternal(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewRemoveFromGroupCommandForTest(s.api, s.store), "--external", "devops", "sre@ldap")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"RemoveUserGroupExternalGroups", []interface{}{"devops", []string{"sre@ldap"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *GroupCommandSuite) TestGroupMembersInit(c *gc.C) {
+	err := cmdtesting.InitCommand(user.NewAddToGroupCommandForTest(s.api, s.store), nil)
+	c.Assert(err, gc.ErrorMatches, "no group name specified")
+	err = cmdtesting.InitCommand(user.NewAddToGroupCommandForTest(s.api, s.store), []string{"devops"})
+	c.Assert(err, gc.ErrorMatches, "no user names specified")
+	err = cmdtesting.InitCommand(user.NewAddToGroupCommandForTest(s.api, s.store), []string{"--external", "devops"})
+	c.Assert(err, gc.ErrorMatches, "no external groups specified")
+}
+
+func (s *GroupCommandSuite) TestGroupsTabular(c *gc.C) {
+	created := time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.api.groups = []params.UserGroupInfo{{
+		Name:           "devops",
+		Members:        []string{"bob", "mary"},
+		ExternalGroups: []string{"sre@ldap"},
+		CreatedBy:      "admin",
+		DateCreated:    created,
+	}, {
+		Name:        "qa",
+		Members:     []string{},
+		CreatedBy:   "admin",
+		DateCreated: created,
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewGroupsCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Matches, `(?s)Name +Members +External groups +Created by +Created *
+devops +bob,mary +sre@ldap +admin .*
+qa +admin .*
+`)
+	s.api.CheckCallNames(c, "UserGroupInfo", "Close")
+}
+
+func (s *GroupCommandSuite) TestGroupsYAML(c *gc.C) {
+	created := time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.api.groups = []params.UserGroupInfo{{
+		Name:        "devops",
+		Members:     []string{"bob"},
+		CreatedBy:   "admin",
+		DateCreated: created,
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewGroupsCommandForTest(s.api, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Matches, `- name: devops
+  members:
+  - bob
+  created-by: admin
+  date-created: .*
+`)
+}
@@ -71,12 +71,14 @@ type infoCommand struct {
 
 // UserInfo defines the serialization behaviour of the user information.
 type UserInfo struct {
-	Username       string `yaml:"user-name" json:"user-name"`
-	DisplayName    string `yaml:"display-name,omitempty" json:"display-name,omitempty"`
-	Access         string `yaml:"access" json:"access"`
-	DateCreated    string `yaml:"date-created,omitempty" json:"date-created,omitempty"`
-	LastConnection string `yaml:"last-connection,omitempty" json:"last-connection,omitempty"`
-	Disabled       bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	Username       string   `yaml:"user-name" json:"user-name"`
+	DisplayName    string   `yaml:"display-name,omitempty" json:"display-name,omitempty"`
+	Access         string   `yaml:"access" json:"access"`
+	Groups         []string `yaml:"groups,omitempty" json:"groups,omitempty"`
+	DateCreated    string   `yaml:"date-created,omitempty" json:"date-created,omitempty"`
+	LastConnection string   `yaml:"last-connection,omitempty" json:"last-connection,omitempty"`
+	Disabled       bool     `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	Locked         bool     `yaml:"locked,omitempty" json:"locked,omitempty"`
 }
 
 // Info implements Command.Info.
@@ -145,7 +147,9 @@ func (c *infoCommandBase) apiUsersToUserInfoSlice(users []params.UserInfo) []Use
 			Username:    info.Username,
 			DisplayName: info.DisplayName,
 			Access:      info.Access,
+			Groups:      info.Groups,
 			Disabled:    info.Disabled,
+			Locked:      info.Locked,
 		}
 		// TODO(wallyworld) record login information about external users.
 		if names.NewUserTag(info.Username).IsLocal() {
@@ -58,6 +58,10 @@ func (*fakeUserInfoAPI) UserInfo(usernames []string, all usermanager.IncludeDisa
 		info.Username = "foobar"
 		info.DisplayName = "Foo Bar"
 		info.Access = "login"
+	case "mary":
+		info.Username = "mary"
+		info.Access = "add-model"
+		info.Groups = []string{"devops", "qa"}
 	case "fred@external":
 		info.Username = "fred@external"
 		info.DisplayName = "Fred External"
@@ -99,6 +103,19 @@ last-connection: 2014-01-01
 `)
 }
 
+func (s *UserInfoCommandSuite) TestUserInfoWithGroups(c *gc.C) {
+	context, err := cmdtesting.RunCommand(c, s.NewShowUserCommand(), "mary")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, `user-name: mary
+access: add-model
+groups:
+- devops
+- qa
+date-created: 1981-02-27
+last-connection: 2014-01-01
+`)
+}
+
 func (s *UserInfoCommandSuite) TestUserInfoExternalUser(c *gc.C) {
 	context, err := cmdtesting.RunCommand(c, s.NewShowUserCommand(), "fred@external")
 	c.Assert(err, jc.ErrorIsNil)
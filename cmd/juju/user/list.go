@@ -227,6 +227,8 @@ func (c *listCommand) formatControllerUsers(writer io.Writer, value interface{})
 		conn := user.LastConnection
 		if user.Disabled {
 			conn += " (disabled)"
+		} else if user.Locked {
+			conn += " (locked)"
 		}
 		var highlight *ansiterm.Context
 		userName := user.Username
@@ -13,10 +13,12 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/httprequest"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/authentication"
 	apibase "github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/modelmanager"
 	"github.com/juju/juju/apiserver/params"
@@ -44,6 +46,12 @@ except verify that fact.
 If the -u flag is provided, the juju login command will attempt to log
 into the controller as that user.
 
+If the --oidc flag is provided, the user logs in with the OpenID Connect
+provider configured on the controller. The command prints a URL and a
+code; visiting the URL, on any device, and entering the code there
+completes the login. The identity token obtained is used until it
+expires, when "juju login --oidc" must be run again.
+
 After login, a token ("macaroon") will become active. It has an expiration
 time of 24 hours. Upon expiration, no further Juju commands can be issued
 and the user will be prompted to log in again.
@@ -61,6 +69,7 @@ Examples:
     juju login somepubliccontroller
     juju login jimm.jujucharms.com
     juju login -u bob
+    juju login --oidc
 
 See also:
     disable-user
@@ -80,6 +89,7 @@ var (
 	// loginClientStore is used as the client store. When it is nil,
 	// the default client store will be used.
 	loginClientStore jujuclient.ClientStore
+	getIDToken       = oidcIDToken
 )
 
 // NewLoginCommand returns a new cmd.Command to handle "juju login".
@@ -94,6 +104,7 @@ type loginCommand struct {
 	modelcmd.ControllerCommandBase
 	domain   string
 	username string
+	oidc     bool
 
 	// controllerName holds the name of the current controller.
 	// We define this and the --controller flag here because
@@ -122,6 +133,7 @@ func (c *loginCommand) SetFlags(fset *gnuflag.FlagSet) {
 	fset.StringVar(&c.controllerName, "controller", "", "")
 	fset.StringVar(&c.username, "u", "", "log in as this local user")
 	fset.StringVar(&c.username, "user", "", "")
+	fset.BoolVar(&c.oidc, "oidc", false, "log in with the controller's OpenID Connect provider")
 }
 
 // Init implements Command.Init.
@@ -131,6 +143,9 @@ func (c *loginCommand) Init(args []string) error {
 		return errors.Trace(err)
 	}
 	c.domain = domain
+	if c.oidc && c.username != "" {
+		return errors.New("cannot specify both --oidc and a user name")
+	}
 	return nil
 }
 
@@ -303,13 +318,20 @@ func (c *loginCommand) publicControllerLogin(
 	dialOpts.BakeryClient = bclient
 
 	dial := func(d *jujuclient.AccountDetails) (api.Connection, error) {
+		if d == nil {
+			return apiOpen(&c.CommandBase, &api.Info{
+				Addrs:     []string{host},
+				SkipLogin: true,
+			}, dialOpts)
+		}
 		var tag names.Tag
-		if d.User != "" {
+		if d.User != "" && d.IDToken == "" {
 			tag = names.NewUserTag(d.User)
 		}
 		return apiOpen(&c.CommandBase, &api.Info{
 			Tag:      tag,
 			Password: d.Password,
+			IDToken:  d.IDToken,
 			Addrs:    []string{host},
 		}, dialOpts)
 	}
@@ -338,12 +360,16 @@ func (c *loginCommand) publicControllerLogin(
 // on whether we have some existing local controller information or not.
 //
 // The dial function should make API connection using the account
-// details that it is passed.
+// details that it is passed, or a connection that is not logged in
+// if they are nil.
 func (c *loginCommand) login(
 	ctx *cmd.Context,
 	accountDetails *jujuclient.AccountDetails,
 	dial func(*jujuclient.AccountDetails) (api.Connection, error),
 ) (api.Connection, *jujuclient.AccountDetails, error) {
+	if c.oidc {
+		return c.oidcLogin(ctx, dial)
+	}
 	username := c.username
 	if c.username != "" && accountDetails != nil && accountDetails.User != c.username {
 		// The user has specified a different username than the
@@ -354,9 +380,9 @@ Run "juju logout" first before attempting to log in as a different user.`,
 			accountDetails.User)
 	}
 
-	if accountDetails != nil && accountDetails.Password != "" {
+	if accountDetails != nil && (accountDetails.Password != "" || accountDetails.IDToken != "") {
 		// We've been provided some account details that
-		// contain a password, so try that first.
+		// contain a password or identity token, so try that first.
 		conn, err := dial(accountDetails)
 		if err == nil {
 			return conn, accountDetails, nil
@@ -405,6 +431,60 @@ Run "juju logout" first before attempting to log in as a different user.`,
 	return conn, accountDetails, errors.Trace(err)
 }
 
+// oidcLogin logs into the controller as the user identified by an
+// identity token obtained from the controller's OpenID Connect
+// provider.
+func (c *loginCommand) oidcLogin(
+	ctx *cmd.Context,
+	dial func(*jujuclient.AccountDetails) (api.Connection, error),
+) (api.Connection, *jujuclient.AccountDetails, error) {
+	conn, err := dial(nil)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	idToken, err := getIDToken(ctx, conn)
+	conn.Close()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	accountDetails := &jujuclient.AccountDetails{IDToken: idToken}
+	conn, err = dial(accountDetails)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	user, ok := conn.AuthTag().(names.UserTag)
+	if !ok {
+		conn.Close()
+		return nil, nil, errors.Errorf("logged in as %v, not a user", conn.AuthTag())
+	}
+	accountDetails.User = user.Id()
+	return conn, accountDetails, nil
+}
+
+// oidcIDToken obtains an identity token from the OpenID Connect
+// provider trusted by the controller that the given connection,
+// which is not logged in, is connected to.
+func oidcIDToken(ctx *cmd.Context, conn api.Connection) (string, error) {
+	var info params.OIDCLoginInfoResult
+	if err := conn.APICall("Admin", 3, "", "OIDCLoginInfo", nil, &info); err != nil {
+		return "", errors.Annotate(err, "getting OpenID Connect login details")
+	}
+	if info.IssuerURL == "" {
+		return "", errors.New("controller is not configured for OpenID Connect logins")
+	}
+	flow := &authentication.OIDCDeviceFlow{
+		IssuerURL: info.IssuerURL,
+		ClientID:  info.ClientID,
+		Clock:     clock.WallClock,
+		Prompt: func(verificationURI, userCode string) error {
+			fmt.Fprintf(ctx.Stderr, "To log in, visit %s\nand enter the code %s\n", verificationURI, userCode)
+			return nil
+		},
+	}
+	idToken, err := flow.IDToken()
+	return idToken, errors.Trace(err)
+}
+
 const noModelsMessage = `
 There are no models available. You can add models with
 "juju add-model", or you can ask an administrator or owner
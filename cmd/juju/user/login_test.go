@@ -258,6 +258,53 @@ There are no models available(.|\n)*`[1:])
 	c.Assert(code, gc.Equals, 0)
 }
 
+func (s *LoginCommandSuite) TestLoginWithOIDC(c *gc.C) {
+	err := s.store.RemoveAccount("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	s.apiConnection.authTag = names.NewUserTag("bob@oidc")
+	var dialled []*jujuclient.AccountDetails
+	*user.NewAPIConnection = func(p juju.NewAPIConnectionParams) (api.Connection, error) {
+		// The account details are modified in place, so take a copy.
+		if p.AccountDetails != nil {
+			accountDetails := *p.AccountDetails
+			p.AccountDetails = &accountDetails
+		}
+		dialled = append(dialled, p.AccountDetails)
+		return s.apiConnection, nil
+	}
+	s.PatchValue(user.GetIDToken, func(ctx *cmd.Context, conn api.Connection) (string, error) {
+		c.Check(conn, gc.Equals, s.apiConnection)
+		return "id-token", nil
+	})
+	stdout, stderr, code := runLogin(c, "", "--oidc")
+	c.Check(stdout, gc.Equals, "")
+	c.Check(stderr, gc.Matches, `
+Welcome, bob@oidc. You are now logged into "testing".
+
+There are no models available(.|\n)*`[1:])
+	c.Assert(code, gc.Equals, 0)
+
+	// The identity token is obtained over a connection that is not
+	// logged in, and then used to log in.
+	c.Assert(dialled, jc.DeepEquals, []*jujuclient.AccountDetails{
+		nil,
+		{IDToken: "id-token"},
+	})
+	account, err := s.store.AccountDetails("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(account, jc.DeepEquals, &jujuclient.AccountDetails{
+		User:            "bob@oidc",
+		IDToken:         "id-token",
+		LastKnownAccess: "superuser",
+	})
+}
+
+func (s *LoginCommandSuite) TestLoginWithOIDCAndUser(c *gc.C) {
+	_, stderr, code := runLogin(c, "", "--oidc", "-u", "bob")
+	c.Check(stderr, gc.Equals, "ERROR cannot specify both --oidc and a user name\n")
+	c.Assert(code, gc.Equals, 2)
+}
+
 func runLogin(c *gc.C, stdin string, args ...string) (stdout, stderr string, errCode int) {
 	c.Logf("in LoginControllerSuite.run")
 	var stdoutBuf, stderrBuf bytes.Buffer
@@ -0,0 +1,192 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageEnableMFASummary = `
+Enables multi-factor authentication for the current user.`[1:]
+
+var usageEnableMFADetails = `
+Once multi-factor authentication is enabled, logging in with a password
+also requires a six digit code from an authenticator application, such
+as Google Authenticator or FreeOTP.
+
+The command prints a secret, and a URI describing it, to add to the
+authenticator application. It then asks for a code generated by the
+application to confirm the secret has been added correctly; until then
+multi-factor authentication is not enabled.
+
+Existing logins remain valid until they expire, when the code will
+be requested.
+
+Examples:
+    juju enable-mfa
+
+See also:
+    disable-mfa
+    login`[1:]
+
+var usageDisableMFASummary = `
+Disables multi-factor authentication for a user.`[1:]
+
+var usageDisableMFADetails = `
+Once multi-factor authentication is disabled, a user may log in with
+just their password. Users may disable it for themselves, after
+entering a code from their authenticator application; controller
+administrators may disable it for any other user, for example if they
+lose access to their authenticator application.
+
+If no user is specified, multi-factor authentication is disabled for
+the current user.
+
+Examples:
+    juju disable-mfa
+    juju disable-mfa bob
+
+See also:
+    enable-mfa`[1:]
+
+// MFAAPI defines the usermanager API methods that the multi-factor
+// authentication commands use.
+type MFAAPI interface {
+	EnableMFA() (params.MFAEnrolment, error)
+	ConfirmMFA(code string) error
+	DisableMFA(username, code string) error
+	Close() error
+}
+
+// NewEnableMFACommand returns a command to enable multi-factor
+// authentication.
+func NewEnableMFACommand() cmd.Command {
+	return modelcmd.WrapController(&enableMFACommand{})
+}
+
+type enableMFACommand struct {
+	modelcmd.ControllerCommandBase
+	api MFAAPI
+}
+
+// Info implements Command.Info.
+func (c *enableMFACommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "enable-mfa",
+		Purpose: usageEnableMFASummary,
+		Doc:     usageEnableMFADetails,
+	}
+}
+
+// Run implements Command.Run.
+func (c *enableMFACommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	enrolment, err := api.EnableMFA()
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	fmt.Fprintf(ctx.Stdout, "secret: %s\n", enrolment.Secret)
+	fmt.Fprintf(ctx.Stdout, "uri: %s\n", enrolment.URI)
+	fmt.Fprint(ctx.Stderr, "Add the secret to your authenticator application, then enter the code it shows: ")
+	code, err := readLine(ctx.Stdin)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.ConfirmMFA(strings.TrimSpace(code)); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Multi-factor authentication enabled.")
+	return nil
+}
+
+func (c *enableMFACommand) getAPI() (MFAAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// NewDisableMFACommand returns a command to disable multi-factor
+// authentication.
+func NewDisableMFACommand() cmd.Command {
+	return modelcmd.WrapController(&disableMFACommand{})
+}
+
+type disableMFACommand struct {
+	modelcmd.ControllerCommandBase
+	api MFAAPI
+
+	User string
+}
+
+// Info implements Command.Info.
+func (c *disableMFACommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "disable-mfa",
+		Args:    "[<user name>]",
+		Purpose: usageDisableMFASummary,
+		Doc:     usageDisableMFADetails,
+	}
+}
+
+// Init implements Command.Init.
+func (c *disableMFACommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.User = args[0]
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *disableMFACommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	accountDetails, err := c.CurrentAccountDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	username := c.User
+	if username == "" {
+		username = accountDetails.User
+	}
+	var code string
+	if username == accountDetails.User {
+		fmt.Fprint(ctx.Stderr, "Enter the code shown by your authenticator application: ")
+		line, err := readLine(ctx.Stdin)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		code = strings.TrimSpace(line)
+	}
+	if err := api.DisableMFA(username, code); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Multi-factor authentication disabled for %q.", username)
+	return nil
+}
+
+func (c *disableMFACommand) getAPI() (MFAAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
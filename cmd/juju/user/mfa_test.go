@@ -0,0 +1,126 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/user"
+)
+
+type MFACommandSuite struct {
+	BaseSuite
+	api *mockMFAAPI
+}
+
+var _ = gc.Suite(&MFACommandSuite{})
+
+type mockMFAAPI struct {
+	testing.Stub
+}
+
+func (m *mockMFAAPI) EnableMFA() (params.MFAEnrolment, error) {
+	m.MethodCall(m, "EnableMFA")
+	return params.MFAEnrolment{
+		Secret: "GEZDGNBVGY3TQOJQ",
+		URI:    "otpauth://totp/juju:current-user?secret=GEZDGNBVGY3TQOJQ",
+	}, m.NextErr()
+}
+
+func (m *mockMFAAPI) ConfirmMFA(code string) error {
+	m.MethodCall(m, "ConfirmMFA", code)
+	return m.NextErr()
+}
+
+func (m *mockMFAAPI) DisableMFA(username, code string) error {
+	m.MethodCall(m, "DisableMFA", username, code)
+	return m.NextErr()
+}
+
+func (m *mockMFAAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (s *MFACommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.api = &mockMFAAPI{}
+}
+
+func (s *MFACommandSuite) runEnable(c *gc.C, stdin string) (string, error) {
+	command := user.NewEnableMFACommandForTest(s.api, s.store)
+	err := cmdtesting.InitCommand(command, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader(stdin)
+	err = command.Run(ctx)
+	return cmdtesting.Stdout(ctx), err
+}
+
+func (s *MFACommandSuite) TestEnableMFA(c *gc.C) {
+	stdout, err := s.runEnable(c, " 123456\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, `
+secret: GEZDGNBVGY3TQOJQ
+uri: otpauth://totp/juju:current-user?secret=GEZDGNBVGY3TQOJQ
+`[1:])
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"EnableMFA", nil},
+		{"ConfirmMFA", []interface{}{"123456"}},
+		{"Close", nil},
+	})
+}
+
+func (s *MFACommandSuite) TestEnableMFAInvalidCode(c *gc.C) {
+	s.api.SetErrors(nil, errors.New("cannot confirm multi-factor authentication: code not valid"))
+	_, err := s.runEnable(c, "000000\n")
+	c.Assert(err, gc.ErrorMatches, "cannot confirm multi-factor authentication: code not valid")
+}
+
+func (s *MFACommandSuite) runDisable(c *gc.C, stdin string, args ...string) (*cmd.Context, error) {
+	command := user.NewDisableMFACommandForTest(s.api, s.store)
+	err := cmdtesting.InitCommand(command, args)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader(stdin)
+	err = command.Run(ctx)
+	return ctx, err
+}
+
+func (s *MFACommandSuite) TestDisableMFACurrentUser(c *gc.C) {
+	ctx, err := s.runDisable(c, "123456\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"Enter the code shown by your authenticator application: "+
+		"Multi-factor authentication disabled for \"current-user\".\n")
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"DisableMFA", []interface{}{"current-user", "123456"}},
+		{"Close", nil},
+	})
+}
+
+func (s *MFACommandSuite) TestDisableMFACurrentUserByName(c *gc.C) {
+	_, err := s.runDisable(c, "123456\n", "current-user")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "DisableMFA", "current-user", "123456")
+}
+
+func (s *MFACommandSuite) TestDisableMFAOtherUser(c *gc.C) {
+	_, err := s.runDisable(c, "", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "DisableMFA", "bob", "")
+}
+
+func (s *MFACommandSuite) TestDisableMFATooManyArgs(c *gc.C) {
+	err := cmdtesting.InitCommand(user.NewDisableMFACommandForTest(s.api, s.store), []string{"bob", "alice"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["alice"\]`)
+}
@@ -0,0 +1,322 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// defaultTokenExpiry is the lifetime of tokens created without the
+// --expires flag.
+const defaultTokenExpiry = 30 * 24 * time.Hour
+
+var usageAddTokenSummary = `
+Creates an API token for automation to log in as the current user.`[1:]
+
+var usageAddTokenDetails = `
+An API token lets scripts and CI pipelines log in to the controller as
+the current user without knowing their password. The token is printed
+once and cannot be retrieved again; store it securely, and pass it to
+juju in the JUJU_TOKEN environment variable.
+
+Every token expires, after 30 days unless --expires says otherwise.
+A token may be limited to particular models, to particular API facades,
+and to read-only calls. It never grants more access than its owner has.
+
+Examples:
+    juju add-token ci
+    juju add-token deploy --expires 12h --model staging --facades Application
+    juju add-token monitoring --read-only
+
+See also:
+    tokens
+    revoke-token`[1:]
+
+// APITokenAPI defines the usermanager API methods that the token
+// commands use.
+type APITokenAPI interface {
+	AddAPIToken(params.AddAPIToken) (string, error)
+	APITokens() ([]params.APITokenInfo, error)
+	RevokeAPIToken(name string) error
+	Close() error
+}
+
+// NewAddTokenCommand returns a command to create an API token.
+func NewAddTokenCommand() cmd.Command {
+	return modelcmd.WrapController(&addTokenCommand{})
+}
+
+type addTokenCommand struct {
+	modelcmd.ControllerCommandBase
+	api APITokenAPI
+
+	Name     string
+	Expires  time.Duration
+	Models   []string
+	Facades  []string
+	ReadOnly bool
+}
+
+// Info implements Command.Info.
+func (c *addTokenCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-token",
+		Args:    "<token name>",
+		Purpose: usageAddTokenSummary,
+		Doc:     usageAddTokenDetails,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addTokenCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.DurationVar(&c.Expires, "expires", defaultTokenExpiry, "How long the token remains valid")
+	f.Var(cmd.NewStringsValue(nil, &c.Models), "model", "Comma separated list of models the token is limited to")
+	f.Var(cmd.NewStringsValue(nil, &c.Facades), "facades", "Comma separated list of API facades the token is limited to")
+	f.BoolVar(&c.ReadOnly, "read-only", false, "Limit the token to read-only calls")
+}
+
+// Init implements Command.Init.
+func (c *addTokenCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no token name specified")
+	}
+	c.Name = args[0]
+	if c.Expires <= 0 {
+		return errors.NotValidf("expiry %v", c.Expires)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *addTokenCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	args := params.AddAPIToken{
+		Name:     c.Name,
+		Expires:  time.Now().Add(c.Expires),
+		Facades:  c.Facades,
+		ReadOnly: c.ReadOnly,
+	}
+	if len(c.Models) > 0 {
+		uuids, err := c.ModelUUIDs(c.Models)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, uuid := range uuids {
+			args.Models = append(args.Models, names.NewModelTag(uuid).String())
+		}
+	}
+	token, err := api.AddAPIToken(args)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	fmt.Fprintln(ctx.Stdout, token)
+	ctx.Infof("Token %q expires at %s", c.Name, args.Expires.UTC().Format(time.RFC3339))
+	return nil
+}
+
+func (c *addTokenCommand) getAPI() (APITokenAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+var usageTokensSummary = `
+Lists the API tokens of the current user.`[1:]
+
+var usageTokensDetails = `
+Lists the API tokens created by the current user on the controller,
+with their scope and expiry time. Token values are not shown.
+
+Examples:
+    juju tokens
+    juju tokens --format yaml
+
+See also:
+    add-token
+    revoke-token`[1:]
+
+// NewTokensCommand returns a command to list API tokens.
+func NewTokensCommand() cmd.Command {
+	return modelcmd.WrapController(&tokensCommand{})
+}
+
+type tokensCommand struct {
+	modelcmd.ControllerCommandBase
+	api APITokenAPI
+	out cmd.Output
+}
+
+// TokenInfo holds the details of an API token for display.
+type TokenInfo struct {
+	Name        string   `yaml:"name" json:"name"`
+	Models      []string `yaml:"models,omitempty" json:"models,omitempty"`
+	Facades     []string `yaml:"facades,omitempty" json:"facades,omitempty"`
+	ReadOnly    bool     `yaml:"read-only,omitempty" json:"read-only,omitempty"`
+	DateCreated string   `yaml:"date-created" json:"date-created"`
+	Expires     string   `yaml:"expires" json:"expires"`
+}
+
+// Info implements Command.Info.
+func (c *tokensCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "tokens",
+		Purpose: usageTokensSummary,
+		Doc:     usageTokensDetails,
+		Aliases: []string{"list-tokens"},
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *tokensCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatTokensTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *tokensCommand) Run(ctx *cmd.Context) error {
+	api := c.api
+	if api == nil {
+		var err error
+		if api, err = c.NewUserManagerAPIClient(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	defer api.Close()
+
+	tokens, err := api.APITokens()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	infos := make([]TokenInfo, len(tokens))
+	for i, t := range tokens {
+		info := TokenInfo{
+			Name:        t.Name,
+			Facades:     t.Facades,
+			ReadOnly:    t.ReadOnly,
+			DateCreated: common.FormatTime(&t.DateCreated, false),
+			Expires:     common.FormatTime(&t.Expires, false),
+		}
+		for _, m := range t.Models {
+			tag, err := names.ParseModelTag(m)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			info.Models = append(info.Models, tag.Id())
+		}
+		infos[i] = info
+	}
+	return c.out.Write(ctx, infos)
+}
+
+func formatTokensTabular(writer io.Writer, value interface{}) error {
+	tokens, ok := value.([]TokenInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", tokens, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Name", "Models", "Facades", "Access", "Created", "Expires")
+	for _, t := range tokens {
+		models, facades, access := "all", "all", "read-write"
+		if len(t.Models) > 0 {
+			models = strings.Join(t.Models, ",")
+		}
+		if len(t.Facades) > 0 {
+			facades = strings.Join(t.Facades, ",")
+		}
+		if t.ReadOnly {
+			access = "read-only"
+		}
+		w.Println(t.Name, models, facades, access, t.DateCreated, t.Expires)
+	}
+	tw.Flush()
+	return nil
+}
+
+var usageRevokeTokenSummary = `
+Revokes an API token of the current user.`[1:]
+
+var usageRevokeTokenDetails = `
+Revokes the named API token so that it can no longer be used to log
+in. Connections already made with the token are not closed.
+
+Examples:
+    juju revoke-token ci
+
+See also:
+    add-token
+    tokens`[1:]
+
+// NewRevokeTokenCommand returns a command to revoke an API token.
+func NewRevokeTokenCommand() cmd.Command {
+	return modelcmd.WrapController(&revokeTokenCommand{})
+}
+
+type revokeTokenCommand struct {
+	modelcmd.ControllerCommandBase
+	api  APITokenAPI
+	Name string
+}
+
+// Info implements Command.Info.
+func (c *revokeTokenCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke-token",
+		Args:    "<token name>",
+		Purpose: usageRevokeTokenSummary,
+		Doc:     usageRevokeTokenDetails,
+	}
+}
+
+// Init implements Command.Init.
+func (c *revokeTokenCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no token name specified")
+	}
+	c.Name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *revokeTokenCommand) Run(ctx *cmd.Context) error {
+	api := c.api
+	if api == nil {
+		var err error
+		if api, err = c.NewUserManagerAPIClient(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	defer api.Close()
+
+	if err := api.RevokeAPIToken(c.Name); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Token %q revoked", c.Name)
+	return nil
+}
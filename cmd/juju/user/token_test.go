@@ -0,0 +1,159 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+)
+
+type TokenCommandSuite struct {
+	BaseSuite
+	api *mockAPITokenAPI
+}
+
+var _ = gc.Suite(&TokenCommandSuite{})
+
+type mockAPITokenAPI struct {
+	testing.Stub
+	tokens []params.APITokenInfo
+}
+
+func (m *mockAPITokenAPI) AddAPIToken(args params.AddAPIToken) (string, error) {
+	m.MethodCall(m, "AddAPIToken", args)
+	return "dG9rZW4.s3cret", m.NextErr()
+}
+
+func (m *mockAPITokenAPI) APITokens() ([]params.APITokenInfo, error) {
+	m.MethodCall(m, "APITokens")
+	return m.tokens, m.NextErr()
+}
+
+func (m *mockAPITokenAPI) RevokeAPIToken(name string) error {
+	m.MethodCall(m, "RevokeAPIToken", name)
+	return m.NextErr()
+}
+
+func (m *mockAPITokenAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (s *TokenCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.api = &mockAPITokenAPI{}
+	s.store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"current-user/staging": {
+				ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				ModelType: model.IAAS,
+			},
+		},
+	}
+}
+
+func (s *TokenCommandSuite) TestAddTokenInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no token name specified",
+	}, {
+		args: []string{"ci", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"ci", "--expires", "-1h"},
+		err:  `expiry -1h0m0s not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(user.NewAddTokenCommandForTest(s.api, s.store), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *TokenCommandSuite) TestAddToken(c *gc.C) {
+	before := time.Now()
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddTokenCommandForTest(s.api, s.store),
+		"ci", "--expires", "2h", "--model", "current-user/staging",
+		"--facades", "Application,Client", "--read-only",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "dG9rZW4.s3cret\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Matches, `Token "ci" expires at .*\n`)
+
+	s.api.CheckCallNames(c, "AddAPIToken", "Close")
+	args := s.api.Calls()[0].Args[0].(params.AddAPIToken)
+	c.Assert(args.Expires.After(before.Add(2*time.Hour-time.Second)), jc.IsTrue)
+	args.Expires = time.Time{}
+	c.Assert(args, jc.DeepEquals, params.AddAPIToken{
+		Name:     "ci",
+		Models:   []string{"model-deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+		Facades:  []string{"Application", "Client"},
+		ReadOnly: true,
+	})
+}
+
+func (s *TokenCommandSuite) TestTokensTabular(c *gc.C) {
+	created := time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.api.tokens = []params.APITokenInfo{{
+		Name:        "ci",
+		DateCreated: created,
+		Expires:     created.Add(24 * time.Hour),
+	}, {
+		Name:        "deploy",
+		Models:      []string{"model-deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+		Facades:     []string{"Application"},
+		ReadOnly:    true,
+		DateCreated: created,
+		Expires:     created.Add(24 * time.Hour),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewTokensCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Matches, `(?s)Name +Models +Facades +Access +Created +Expires *
+ci +all +all +read-write .*
+deploy +deadbeef-0bad-400d-8000-4b1d0d06f00d +Application +read-only .*
+`)
+	s.api.CheckCallNames(c, "APITokens", "Close")
+}
+
+func (s *TokenCommandSuite) TestTokensYAML(c *gc.C) {
+	created := time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.api.tokens = []params.APITokenInfo{{
+		Name:        "ci",
+		ReadOnly:    true,
+		DateCreated: created,
+		Expires:     created.Add(24 * time.Hour),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewTokensCommandForTest(s.api, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Matches, `- name: ci
+  read-only: true
+  date-created: .*
+  expires: .*
+`)
+}
+
+func (s *TokenCommandSuite) TestRevokeToken(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewRevokeTokenCommandForTest(s.api, s.store), "ci")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Token \"ci\" revoked\n")
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"RevokeAPIToken", []interface{}{"ci"}},
+		{"Close", nil},
+	})
+}
+
+func (s *TokenCommandSuite) TestRevokeTokenNoName(c *gc.C) {
+	err := cmdtesting.InitCommand(user.NewRevokeTokenCommandForTest(s.api, s.store), nil)
+	c.Assert(err, gc.ErrorMatches, "no token name specified")
+}
@@ -82,6 +82,7 @@ var (
 	}
 	notMigratingUnitWorkers = []string{
 		"api-address-updater",
+		"ca-cert-updater",
 		"charm-dir",
 		"hook-retry-strategy",
 		"leadership-tracker",
@@ -112,12 +113,15 @@ var (
 	}
 	notMigratingMachineWorkers = []string{
 		"api-address-updater",
+		"ca-cert-updater",
 		"disk-manager",
 		"fan-configurer",
 		// "host-key-reporter", not stable, exits when done
+		// "interruption-watcher", not stable, only runs on some clouds
 		"log-sender",
 		"logging-config-updater",
 		"machine-action-runner",
+		"machine-usage",
 		"machiner",
 		"proxy-config-updater",
 		"reboot-executor",
@@ -549,6 +549,7 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			},
 			ControllerLeaseDuration:           time.Minute,
 			LogPruneInterval:                  5 * time.Minute,
+			LDAPGroupSyncInterval:             15 * time.Minute,
 			TransactionPruneInterval:          time.Hour,
 			SetStatePool:                      statePoolReporter.set,
 			RegisterIntrospectionHTTPHandlers: registerIntrospectionHandlers,
@@ -755,6 +756,10 @@ func (a *MachineAgent) openStateForUpgrade() (*state.State, error) {
 	}
 	defer session.Close()
 
+	secretsKey, err := agent.SecretsKey(agentConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	st, err := state.Open(state.OpenParams{
 		Clock:              clock.WallClock,
 		ControllerTag:      agentConfig.Controller(),
@@ -763,6 +768,7 @@ func (a *MachineAgent) openStateForUpgrade() (*state.State, error) {
 		NewPolicy: stateenvirons.GetNewPolicyFunc(
 			stateenvirons.GetNewEnvironFunc(environs.New),
 		),
+		SecretsKey: secretsKey,
 		// state.InitDatabase is idempotent and needs to be called just
 		// prior to performing any upgrades since a new Juju binary may
 		// declare new indices or explicit collections.
@@ -910,6 +916,10 @@ func (a *MachineAgent) initController(agentConfig agent.Config) (*state.Controll
 	}
 	defer session.Close()
 
+	secretsKey, err := agent.SecretsKey(agentConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	ctlr, err := state.OpenController(state.OpenParams{
 		Clock:              clock.WallClock,
 		ControllerTag:      agentConfig.Controller(),
@@ -919,6 +929,7 @@ func (a *MachineAgent) initController(agentConfig agent.Config) (*state.Controll
 			stateenvirons.GetNewEnvironFunc(environs.New),
 		),
 		RunTransactionObserver: a.mongoTxnCollector.AfterRunTransaction,
+		SecretsKey:             secretsKey,
 	})
 	return ctlr, nil
 }
@@ -1057,6 +1068,10 @@ func openState(
 	}
 	defer session.Close()
 
+	secretsKey, err := agent.SecretsKey(agentConfig)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 	st, err := state.Open(state.OpenParams{
 		Clock:              clock.WallClock,
 		ControllerTag:      agentConfig.Controller(),
@@ -1066,6 +1081,7 @@ func openState(
 			stateenvirons.GetNewEnvironFunc(environs.New),
 		),
 		RunTransactionObserver: runTransactionObserver,
+		SecretsKey:             secretsKey,
 	})
 	if err != nil {
 		return nil, nil, err
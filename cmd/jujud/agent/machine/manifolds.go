@@ -37,6 +37,7 @@ import (
 	"github.com/juju/juju/worker/apiservercertwatcher"
 	"github.com/juju/juju/worker/auditconfigupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/centralhub"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/dblogpruner"
@@ -50,10 +51,13 @@ import (
 	"github.com/juju/juju/worker/globalclockupdater"
 	"github.com/juju/juju/worker/hostkeyreporter"
 	"github.com/juju/juju/worker/identityfilewriter"
+	"github.com/juju/juju/worker/interruptionwatcher"
+	"github.com/juju/juju/worker/ldapgroupsync"
 	"github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machineactions"
 	"github.com/juju/juju/worker/machiner"
+	"github.com/juju/juju/worker/machineusage"
 	"github.com/juju/juju/worker/migrationflag"
 	"github.com/juju/juju/worker/migrationminion"
 	"github.com/juju/juju/worker/modelworkermanager"
@@ -181,6 +185,10 @@ type ManifoldsConfig struct {
 	// the database.
 	LogPruneInterval time.Duration
 
+	// LDAPGroupSyncInterval defines how frequently the groups of LDAP
+	// users are synced with the directory.
+	LDAPGroupSyncInterval time.Duration
+
 	// TransactionPruneInterval defines how frequently mgo/txn transactions
 	// are pruned from the database.
 	TransactionPruneInterval time.Duration
@@ -350,6 +358,15 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			AgentConfigChanged: config.AgentConfigChanged,
 		})),
 
+		// The external-certificate-watcher manifold monitors the
+		// controller config for an externally provided API server
+		// certificate, and offers the result to the API server.
+		// This is only run by state servers.
+		externalCertificateWatcherName: ifController(apiservercertwatcher.ExternalManifold(apiservercertwatcher.ExternalManifoldConfig{
+			ClockName: clockName,
+			StateName: stateName,
+		})),
+
 		// The api caller is a thin concurrent wrapper around a connection
 		// to some API server. It's used by many other manifolds, which all
 		// select their own desired facades. It will be interesting to see
@@ -533,6 +550,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 		})),
 
+		// The CA cert updater is a leaf worker that rewrites agent
+		// config as the controller CA certificate changes while it
+		// is rotated.
+		caCertUpdaterName: ifNotMigrating(cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+		})),
+
 		fanConfigurerName: ifNotMigrating(fanconfigurer.Manifold(fanconfigurer.ManifoldConfig{
 			APICallerName: apiCallerName,
 			Clock:         config.Clock,
@@ -619,6 +644,28 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:     hostkeyreporter.NewWorker,
 		})),
 
+		interruptionWatcherName: ifNotMigrating(interruptionwatcher.Manifold(interruptionwatcher.ManifoldConfig{
+			AgentName:          agentName,
+			APICallerName:      apiCallerName,
+			Clock:              config.Clock,
+			Interval:           5 * time.Second,
+			NewFacade:          interruptionwatcher.NewFacade,
+			MachineConstraints: interruptionwatcher.MachineConstraints,
+			NewChecker:         interruptionwatcher.NewMetadataChecker,
+			NewWorker:          interruptionwatcher.New,
+		})),
+
+		machineUsageName: ifNotMigrating(machineusage.Manifold(machineusage.ManifoldConfig{
+			AgentName:            agentName,
+			APICallerName:        apiCallerName,
+			Clock:                config.Clock,
+			Interval:             5 * time.Minute,
+			PrometheusRegisterer: config.PrometheusRegisterer,
+			NewFacade:            machineusage.NewFacade,
+			NewSampler:           machineusage.NewSampler,
+			NewWorker:            machineusage.New,
+		})),
+
 		externalControllerUpdaterName: ifNotMigrating(ifPrimaryController(externalcontrollerupdater.Manifold(
 			externalcontrollerupdater.ManifoldConfig{
 				APICallerName:                      apiCallerName,
@@ -635,6 +682,15 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			},
 		))),
 
+		ldapGroupSyncName: ifNotMigrating(ifPrimaryController(ldapgroupsync.Manifold(
+			ldapgroupsync.ManifoldConfig{
+				ClockName: clockName,
+				StateName: stateName,
+				Interval:  config.LDAPGroupSyncInterval,
+				NewWorker: ldapgroupsync.NewWorker,
+			},
+		))),
+
 		txnPrunerName: ifNotMigrating(ifPrimaryController(txnpruner.Manifold(
 			txnpruner.ManifoldConfig{
 				ClockName:     clockName,
@@ -651,11 +707,12 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			UpgradeGateName:                   upgradeStepsGateName,
 			RestoreStatusName:                 restoreWatcherName,
 			CertWatcherName:                   certificateWatcherName,
+			ExternalCertWatcherName:           externalCertificateWatcherName,
 			AuditConfigUpdaterName:            auditConfigUpdaterName,
 			PrometheusRegisterer:              config.PrometheusRegisterer,
 			RegisterIntrospectionHTTPHandlers: config.RegisterIntrospectionHTTPHandlers,
-			Hub:       config.CentralHub,
-			NewWorker: apiserver.NewWorker,
+			Hub:                               config.CentralHub,
+			NewWorker:                         apiserver.NewWorker,
 		}),
 
 		modelWorkerManagerName: ifFullyUpgraded(modelworkermanager.Manifold(modelworkermanager.ManifoldConfig{
@@ -751,35 +808,40 @@ const (
 	migrationInactiveFlagName = "migration-inactive-flag"
 	migrationMinionName       = "migration-minion"
 
-	servingInfoSetterName         = "serving-info-setter"
-	apiWorkersName                = "unconverted-api-workers"
-	rebootName                    = "reboot-executor"
-	loggingConfigUpdaterName      = "logging-config-updater"
-	diskManagerName               = "disk-manager"
-	proxyConfigUpdater            = "proxy-config-updater"
-	apiAddressUpdaterName         = "api-address-updater"
-	machinerName                  = "machiner"
-	logSenderName                 = "log-sender"
-	deployerName                  = "unit-agent-deployer"
-	authenticationWorkerName      = "ssh-authkeys-updater"
-	storageProvisionerName        = "storage-provisioner"
-	resumerName                   = "mgo-txn-resumer"
-	identityFileWriterName        = "ssh-identity-writer"
-	toolsVersionCheckerName       = "tools-version-checker"
-	machineActionName             = "machine-action-runner"
-	hostKeyReporterName           = "host-key-reporter"
-	fanConfigurerName             = "fan-configurer"
-	externalControllerUpdaterName = "external-controller-updater"
-	globalClockUpdaterName        = "global-clock-updater"
-	isPrimaryControllerFlagName   = "is-primary-controller-flag"
-	isControllerFlagName          = "is-controller-flag"
-	logPrunerName                 = "log-pruner"
-	txnPrunerName                 = "transaction-pruner"
-	apiServerName                 = "api-server"
-	certificateWatcherName        = "certificate-watcher"
-	modelWorkerManagerName        = "model-worker-manager"
-	peergrouperName               = "peer-grouper"
-	restoreWatcherName            = "restore-watcher"
-	certificateUpdaterName        = "certificate-updater"
-	auditConfigUpdaterName        = "audit-config-updater"
+	servingInfoSetterName          = "serving-info-setter"
+	apiWorkersName                 = "unconverted-api-workers"
+	rebootName                     = "reboot-executor"
+	loggingConfigUpdaterName       = "logging-config-updater"
+	diskManagerName                = "disk-manager"
+	proxyConfigUpdater             = "proxy-config-updater"
+	apiAddressUpdaterName          = "api-address-updater"
+	caCertUpdaterName              = "ca-cert-updater"
+	machinerName                   = "machiner"
+	logSenderName                  = "log-sender"
+	deployerName                   = "unit-agent-deployer"
+	authenticationWorkerName       = "ssh-authkeys-updater"
+	storageProvisionerName         = "storage-provisioner"
+	resumerName                    = "mgo-txn-resumer"
+	identityFileWriterName         = "ssh-identity-writer"
+	toolsVersionCheckerName        = "tools-version-checker"
+	machineActionName              = "machine-action-runner"
+	hostKeyReporterName            = "host-key-reporter"
+	interruptionWatcherName        = "interruption-watcher"
+	machineUsageName               = "machine-usage"
+	fanConfigurerName              = "fan-configurer"
+	externalControllerUpdaterName  = "external-controller-updater"
+	globalClockUpdaterName         = "global-clock-updater"
+	isPrimaryControllerFlagName    = "is-primary-controller-flag"
+	isControllerFlagName           = "is-controller-flag"
+	logPrunerName                  = "log-pruner"
+	ldapGroupSyncName              = "ldap-group-sync"
+	txnPrunerName                  = "transaction-pruner"
+	apiServerName                  = "api-server"
+	certificateWatcherName         = "certificate-watcher"
+	externalCertificateWatcherName = "external-certificate-watcher"
+	modelWorkerManagerName         = "model-worker-manager"
+	peergrouperName                = "peer-grouper"
+	restoreWatcherName             = "restore-watcher"
+	certificateUpdaterName         = "certificate-updater"
+	auditConfigUpdaterName         = "audit-config-updater"
 )
@@ -49,21 +49,26 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"api-config-watcher",
 		"api-server",
 		"audit-config-updater",
+		"ca-cert-updater",
 		"central-hub",
 		"certificate-updater",
 		"certificate-watcher",
 		"clock",
 		"disk-manager",
+		"external-certificate-watcher",
 		"external-controller-updater",
 		"fan-configurer",
 		"global-clock-updater",
 		"host-key-reporter",
+		"interruption-watcher",
 		"is-controller-flag",
 		"is-primary-controller-flag",
+		"ldap-group-sync",
 		"log-pruner",
 		"log-sender",
 		"logging-config-updater",
 		"machine-action-runner",
+		"machine-usage",
 		"machiner",
 		"mgo-txn-resumer",
 		"migration-fortress",
@@ -118,6 +123,7 @@ func (*ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 		"certificate-watcher",
 		"central-hub",
 		"clock",
+		"external-certificate-watcher",
 		"global-clock-updater",
 		"is-controller-flag",
 		"is-primary-controller-flag",
@@ -158,10 +164,10 @@ func (*ManifoldsSuite) TestSingularGuardsUsed(c *gc.C) {
 	for name, manifold := range manifolds {
 		c.Logf(name)
 		switch name {
-		case "certificate-watcher", "audit-config-updater", "is-primary-controller-flag":
+		case "certificate-watcher", "external-certificate-watcher", "audit-config-updater", "is-primary-controller-flag":
 			checkContains(c, manifold.Inputs, "is-controller-flag")
 			checkNotContains(c, manifold.Inputs, "is-primary-controller-flag")
-		case "external-controller-updater", "log-pruner", "transaction-pruner":
+		case "external-controller-updater", "ldap-group-sync", "log-pruner", "transaction-pruner":
 			checkNotContains(c, manifold.Inputs, "is-controller-flag")
 			checkContains(c, manifold.Inputs, "is-primary-controller-flag")
 		default:
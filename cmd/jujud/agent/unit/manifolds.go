@@ -25,6 +25,7 @@ import (
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/gate"
@@ -250,6 +251,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 		})),
 
+		// The CA cert updater is a leaf worker that rewrites agent
+		// config as the controller CA certificate changes while it
+		// is rotated.
+		caCertUpdaterName: ifNotMigrating(cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+		})),
+
 		// The proxy config updater is a leaf worker that sets http/https/apt/etc
 		// proxy settings.
 		// TODO(fwereade): timing of this is suspicious. There was superstitious
@@ -373,6 +382,7 @@ const (
 	loggingConfigUpdaterName = "logging-config-updater"
 	proxyConfigUpdaterName   = "proxy-config-updater"
 	apiAddressUpdaterName    = "api-address-updater"
+	caCertUpdaterName        = "ca-cert-updater"
 
 	charmDirName          = "charm-dir"
 	leadershipTrackerName = "leadership-tracker"
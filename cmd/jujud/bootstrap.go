@@ -5,6 +5,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -213,6 +214,14 @@ func (c *BootstrapCommand) Run(_ *cmd.Context) error {
 	}
 	info.SharedSecret = sharedSecret
 	info.SystemIdentity = privateKey
+
+	// Generate the key that charm secrets are encrypted with. It
+	// is kept in the controller agents' configuration only.
+	secretsKey, err := state.GenerateSecretsKey()
+	if err != nil {
+		return errors.Annotate(err, "failed to generate secrets key")
+	}
+	info.SecretsKey = base64.StdEncoding.EncodeToString(secretsKey)
 	err = c.ChangeConfig(func(agentConfig agent.ConfigSetter) error {
 		agentConfig.SetStateServingInfo(info)
 		mmprof, err := mongo.NewMemoryProfile(args.ControllerConfig.MongoMemoryProfile())
@@ -242,5 +242,7 @@ func ParamsStateServingInfoToStateStateServingInfo(i params.StateServingInfo) st
 		CAPrivateKey:   i.CAPrivateKey,
 		SharedSecret:   i.SharedSecret,
 		SystemIdentity: i.SystemIdentity,
+
+		SSHCAPrivateKey: i.SSHCAPrivateKey,
 	}
 }
@@ -187,17 +187,24 @@ func (c *CommandBase) NewAPIConnectionParams(
 	if err != nil {
 		return juju.NewAPIConnectionParams{}, errors.Trace(err)
 	}
-	var getPassword func(username string) (string, error)
+	var getPassword, getMFACode func(username string) (string, error)
 	if c.cmdContext != nil {
 		getPassword = func(username string) (string, error) {
 			fmt.Fprintf(c.cmdContext.Stderr, "please enter password for %s on %s: ", username, controllerName)
 			defer fmt.Fprintln(c.cmdContext.Stderr)
 			return readPassword(c.cmdContext.Stdin)
 		}
+		getMFACode = func(username string) (string, error) {
+			fmt.Fprintf(c.cmdContext.Stderr, "please enter authentication code for %s on %s: ", username, controllerName)
+			return readLine(c.cmdContext.Stdin)
+		}
 	} else {
 		getPassword = func(username string) (string, error) {
 			return "", errors.New("no context to prompt for password")
 		}
+		getMFACode = func(username string) (string, error) {
+			return "", errors.New("no context to prompt for authentication code")
+		}
 	}
 
 	return newAPIConnectionParams(
@@ -206,6 +213,7 @@ func (c *CommandBase) NewAPIConnectionParams(
 		bakeryClient,
 		c.apiOpen,
 		getPassword,
+		getMFACode,
 	)
 }
 
@@ -397,6 +405,7 @@ func newAPIConnectionParams(
 	bakery *httpbakery.Client,
 	apiOpen api.OpenFunc,
 	getPassword func(string) (string, error),
+	getMFACode func(string) (string, error),
 ) (juju.NewAPIConnectionParams, error) {
 	if controllerName == "" {
 		return juju.NewAPIConnectionParams{}, errors.Trace(errNoNameSpecified)
@@ -414,7 +423,7 @@ func newAPIConnectionParams(
 
 	if accountDetails != nil {
 		bakery.WebPageVisitor = httpbakery.NewMultiVisitor(
-			authentication.NewVisitor(accountDetails.User, getPassword),
+			authentication.NewMFAVisitor(accountDetails.User, getPassword, getMFACode),
 			bakery.WebPageVisitor,
 		)
 	}
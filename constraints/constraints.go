@@ -32,6 +32,10 @@ const (
 	InstanceType = "instance-type"
 	Spaces       = "spaces"
 	VirtType     = "virt-type"
+	Zones        = "zones"
+	// Interruptible and MaxPrice request spot or preemptible capacity.
+	Interruptible = "interruptible"
+	MaxPrice      = "max-price"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -85,6 +89,20 @@ type Value struct {
 	// VirtType, if not nil or empty, indicates that a machine must run the named
 	// virtual type. Only valid for clouds with multi-hypervisor support.
 	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
+
+	// Zones, if not nil, holds a list of availability zones limiting
+	// where the machine can be located.
+	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
+
+	// Interruptible, if not nil, indicates whether the machine may run
+	// on interruptible capacity, such as EC2 spot instances or GCE
+	// preemptible instances, which the cloud may reclaim at any time.
+	Interruptible *bool `json:"interruptible,omitempty" yaml:"interruptible,omitempty"`
+
+	// MaxPrice, if not nil or empty, is the maximum hourly price, in
+	// US dollars, to pay for interruptible capacity. Only valid for
+	// clouds that let users bid for interruptible capacity.
+	MaxPrice *string `json:"max-price,omitempty" yaml:"max-price,omitempty"`
 }
 
 var rawAliases = map[string]string{
@@ -180,6 +198,24 @@ func (v *Value) HasVirtType() bool {
 	return v.VirtType != nil && *v.VirtType != ""
 }
 
+// HasZones returns true if the constraints.Value specifies availability
+// zones.
+func (v *Value) HasZones() bool {
+	return v.Zones != nil && len(*v.Zones) > 0
+}
+
+// HasInterruptible returns true if the constraints.Value requests
+// interruptible capacity.
+func (v *Value) HasInterruptible() bool {
+	return v.Interruptible != nil && *v.Interruptible
+}
+
+// HasMaxPrice returns true if the constraints.Value specifies a maximum
+// price for interruptible capacity.
+func (v *Value) HasMaxPrice() bool {
+	return v.MaxPrice != nil && *v.MaxPrice != ""
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -223,6 +259,16 @@ func (v Value) String() string {
 	if v.VirtType != nil {
 		strs = append(strs, "virt-type="+string(*v.VirtType))
 	}
+	if v.Zones != nil {
+		s := strings.Join(*v.Zones, ",")
+		strs = append(strs, "zones="+s)
+	}
+	if v.Interruptible != nil {
+		strs = append(strs, "interruptible="+strconv.FormatBool(*v.Interruptible))
+	}
+	if v.MaxPrice != nil {
+		strs = append(strs, "max-price="+*v.MaxPrice)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -264,6 +310,17 @@ func (v Value) GoString() string {
 	if v.VirtType != nil {
 		values = append(values, fmt.Sprintf("VirtType: %q", *v.VirtType))
 	}
+	if v.Zones != nil && *v.Zones != nil {
+		values = append(values, fmt.Sprintf("Zones: %q", *v.Zones))
+	} else if v.Zones != nil {
+		values = append(values, "Zones: (*[]string)(nil)")
+	}
+	if v.Interruptible != nil {
+		values = append(values, fmt.Sprintf("Interruptible: %v", *v.Interruptible))
+	}
+	if v.MaxPrice != nil {
+		values = append(values, fmt.Sprintf("MaxPrice: %q", *v.MaxPrice))
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -420,6 +477,12 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setSpaces(str)
 	case VirtType:
 		err = v.setVirtType(str)
+	case Zones:
+		err = v.setZones(str)
+	case Interruptible:
+		err = v.setInterruptible(str)
+	case MaxPrice:
+		err = v.setMaxPrice(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -483,6 +546,14 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			}
 		case VirtType:
 			v.VirtType = &vstr
+		case Zones:
+			v.Zones, err = parseYamlStrings("zones", val)
+		case Interruptible:
+			v.Interruptible, err = parseBool(vstr)
+		case MaxPrice:
+			if err = validatePrice(vstr); err == nil {
+				v.MaxPrice = &vstr
+			}
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -599,6 +670,14 @@ func (v *Value) validateSpaces(spaces *[]string) error {
 	return nil
 }
 
+func (v *Value) setZones(str string) error {
+	if v.Zones != nil {
+		return errors.Errorf("already set")
+	}
+	v.Zones = parseCommaDelimited(str)
+	return nil
+}
+
 func (v *Value) setVirtType(str string) error {
 	if v.VirtType != nil {
 		return errors.Errorf("already set")
@@ -607,6 +686,49 @@ func (v *Value) setVirtType(str string) error {
 	return nil
 }
 
+func (v *Value) setInterruptible(str string) (err error) {
+	if v.Interruptible != nil {
+		return errors.Errorf("already set")
+	}
+	v.Interruptible, err = parseBool(str)
+	return
+}
+
+func (v *Value) setMaxPrice(str string) error {
+	if v.MaxPrice != nil {
+		return errors.Errorf("already set")
+	}
+	if err := validatePrice(str); err != nil {
+		return err
+	}
+	v.MaxPrice = &str
+	return nil
+}
+
+func parseBool(str string) (*bool, error) {
+	var value bool
+	if str != "" {
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, errors.Errorf("must be true or false")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
+// validatePrice checks that str is empty or holds a non-negative
+// decimal price.
+func validatePrice(str string) error {
+	if str == "" {
+		return nil
+	}
+	if val, err := strconv.ParseFloat(str, 64); err != nil || val < 0 {
+		return errors.Errorf("must be a non-negative decimal")
+	}
+	return nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -284,6 +284,60 @@ var parseConstraintsTests = []struct {
 		args:    []string{"spaces="},
 	},
 
+	// zones
+	{
+		summary: "single zone",
+		args:    []string{"zones=az1"},
+	}, {
+		summary: "multiple zones",
+		args:    []string{"zones=az1,az2"},
+	}, {
+		summary: "no zones",
+		args:    []string{"zones="},
+	}, {
+		summary: "double set zones separately",
+		args:    []string{"zones=az1", "zones=az2"},
+		err:     `bad "zones" constraint: already set`,
+	},
+
+	// interruptible capacity
+	{
+		summary: "set interruptible",
+		args:    []string{"interruptible=true"},
+	}, {
+		summary: "set interruptible false",
+		args:    []string{"interruptible=false"},
+	}, {
+		summary: "set interruptible empty",
+		args:    []string{"interruptible="},
+	}, {
+		summary: "set interruptible invalid",
+		args:    []string{"interruptible=maybe"},
+		err:     `bad "interruptible" constraint: must be true or false`,
+	}, {
+		summary: "double set interruptible separately",
+		args:    []string{"interruptible=true", "interruptible=false"},
+		err:     `bad "interruptible" constraint: already set`,
+	}, {
+		summary: "set max-price",
+		args:    []string{"interruptible=true max-price=0.05"},
+	}, {
+		summary: "set max-price empty",
+		args:    []string{"max-price="},
+	}, {
+		summary: "set max-price negative",
+		args:    []string{"max-price=-1"},
+		err:     `bad "max-price" constraint: must be a non-negative decimal`,
+	}, {
+		summary: "set max-price invalid",
+		args:    []string{"max-price=cheap"},
+		err:     `bad "max-price" constraint: must be a non-negative decimal`,
+	}, {
+		summary: "double set max-price separately",
+		args:    []string{"max-price=0.05", "max-price=0.1"},
+		err:     `bad "max-price" constraint: already set`,
+	},
+
 	// instance type
 	{
 		summary: "set instance type",
@@ -319,13 +373,14 @@ var parseConstraintsTests = []struct {
 		args: []string{
 			"root-disk=8G mem=2T  arch=i386  cores=4096 cpu-power=9001 container=lxd " +
 				"tags=foo,bar spaces=space1,^space2 instance-type=foo",
-			"virt-type=kvm"},
+			"virt-type=kvm zones=az1,az2 interruptible=true max-price=0.05"},
 	}, {
 		summary: "kitchen sink separately",
 		args: []string{
 			"root-disk=8G", "mem=2T", "cores=4096", "cpu-power=9001", "arch=armhf",
 			"container=lxd", "tags=foo,bar", "spaces=space1,^space2",
-			"instance-type=foo", "virt-type=kvm"},
+			"instance-type=foo", "virt-type=kvm", "zones=az1,az2",
+			"interruptible=true", "max-price=0.05"},
 	},
 }
 
@@ -473,6 +528,10 @@ func strp(s string) *string {
 	return &s
 }
 
+func boolp(b bool) *bool {
+	return &b
+}
+
 func ctypep(ctype string) *instance.ContainerType {
 	res := instance.ContainerType(ctype)
 	return &res
@@ -508,18 +567,30 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Spaces1", constraints.Value{Spaces: nil}},
 	{"Spaces2", constraints.Value{Spaces: &[]string{}}},
 	{"Spaces3", constraints.Value{Spaces: &[]string{"space1", "^space2"}}},
+	{"Zones1", constraints.Value{Zones: nil}},
+	{"Zones2", constraints.Value{Zones: &[]string{}}},
+	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
+	{"Interruptible1", constraints.Value{Interruptible: nil}},
+	{"Interruptible2", constraints.Value{Interruptible: boolp(false)}},
+	{"Interruptible3", constraints.Value{Interruptible: boolp(true)}},
+	{"MaxPrice1", constraints.Value{MaxPrice: nil}},
+	{"MaxPrice2", constraints.Value{MaxPrice: strp("")}},
+	{"MaxPrice3", constraints.Value{MaxPrice: strp("0.05")}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
 	{"All", constraints.Value{
-		Arch:         strp("i386"),
-		Container:    ctypep("lxd"),
-		CpuCores:     uint64p(4096),
-		CpuPower:     uint64p(9001),
-		Mem:          uint64p(18000000000),
-		RootDisk:     uint64p(24000000000),
-		Tags:         &[]string{"foo", "bar"},
-		Spaces:       &[]string{"space1", "^space2"},
-		InstanceType: strp("foo"),
+		Arch:          strp("i386"),
+		Container:     ctypep("lxd"),
+		CpuCores:      uint64p(4096),
+		CpuPower:      uint64p(9001),
+		Mem:           uint64p(18000000000),
+		RootDisk:      uint64p(24000000000),
+		Tags:          &[]string{"foo", "bar"},
+		Spaces:        &[]string{"space1", "^space2"},
+		InstanceType:  strp("foo"),
+		Zones:         &[]string{"az1", "az2"},
+		Interruptible: boolp(true),
+		MaxPrice:      strp("0.05"),
 	}},
 }
 
@@ -597,6 +668,33 @@ func (s *ConstraintsSuite) TestHasInstanceType(c *gc.C) {
 	c.Check(cons.HasInstanceType(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestHasZones(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasZones(), jc.IsFalse)
+	cons = constraints.MustParse("zones=")
+	c.Check(cons.HasZones(), jc.IsFalse)
+	cons = constraints.MustParse("arch=amd64 zones=az1,az2")
+	c.Check(cons.HasZones(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasInterruptible(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasInterruptible(), jc.IsFalse)
+	cons = constraints.MustParse("interruptible=false")
+	c.Check(cons.HasInterruptible(), jc.IsFalse)
+	cons = constraints.MustParse("interruptible=true")
+	c.Check(cons.HasInterruptible(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasMaxPrice(c *gc.C) {
+	cons := constraints.MustParse("interruptible=true")
+	c.Check(cons.HasMaxPrice(), jc.IsFalse)
+	cons = constraints.MustParse("max-price=")
+	c.Check(cons.HasMaxPrice(), jc.IsFalse)
+	cons = constraints.MustParse("interruptible=true max-price=0.05")
+	c.Check(cons.HasMaxPrice(), jc.IsTrue)
+}
+
 const initialWithoutCons = "root-disk=8G mem=4G arch=amd64 cpu-power=1000 cores=4 spaces=space1,^space2 tags=foo container=lxd instance-type=bar"
 
 var withoutTests = []struct {
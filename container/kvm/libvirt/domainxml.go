@@ -196,6 +196,7 @@ type Domain struct {
 	XMLName       xml.Name    `xml:"domain"`
 	Type          string      `xml:"type,attr"`
 	Name          string      `xml:"name"`
+	Description   string      `xml:"description,omitempty"`
 	VCPU          uint64      `xml:"vcpu"`
 	CurrentMemory Memory      `xml:"currentMemory"`
 	Memory        Memory      `xml:"memory"`
@@ -364,6 +365,7 @@ type Disk struct {
 	Driver DiskDriver `xml:"driver"`
 	Source DiskSource `xml:"source"`
 	Target DiskTarget `xml:"target"`
+	Serial string     `xml:"serial,omitempty"`
 }
 
 // DiskDriver is the type of virtual disk. We generate it dynamically.
@@ -374,10 +376,12 @@ type DiskDriver struct {
 }
 
 // DiskSource is the location of the disk image. In our case the path to the
-// necessary images.
+// necessary images, or to the block device of a disk attached from a
+// block-based storage pool.
 // See: Disk
 type DiskSource struct {
 	File string `xml:"file,attr"`
+	Dev  string `xml:"dev,attr,omitempty"`
 }
 
 // DiskTarget is the target device on the guest. We generate these.
@@ -4,9 +4,11 @@
 package controller
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -77,6 +79,89 @@ const (
 	// IdentityPublicKey sets the public key of the identity manager.
 	IdentityPublicKey = "identity-public-key"
 
+	// OIDCIssuerURL sets the URL of the OpenID Connect provider whose
+	// identity tokens the controller accepts for user login.
+	OIDCIssuerURL = "oidc-issuer-url"
+
+	// OIDCClientID sets the client ID the controller is registered
+	// with at the OpenID Connect provider. Identity tokens must be
+	// issued for this audience.
+	OIDCClientID = "oidc-client-id"
+
+	// OIDCUsernameClaim sets the identity token claim holding the
+	// user's name. The name must not include a domain; users are
+	// always placed in the oidc domain.
+	OIDCUsernameClaim = "oidc-username-claim"
+
+	// OIDCGroupsClaim sets the identity token claim holding the
+	// groups the user is a member of.
+	OIDCGroupsClaim = "oidc-groups-claim"
+
+	// LDAPURL sets the address of the LDAP directory against which
+	// users in the "ldap" domain are authenticated.
+	LDAPURL = "ldap-url"
+
+	// LDAPUserDNTemplate sets the template used to build a user's
+	// distinguished name from their username, which replaces the
+	// "%s" in the template.
+	LDAPUserDNTemplate = "ldap-user-dn-template"
+
+	// LDAPGroupBaseDN sets the base of the directory subtree searched
+	// for the groups a user is a member of.
+	LDAPGroupBaseDN = "ldap-group-base-dn"
+
+	// LDAPAutoCreateUsers sets whether users authenticated by the
+	// LDAP directory are added to the controller on first login.
+	LDAPAutoCreateUsers = "ldap-auto-create-users"
+
+	// LDAPStartTLS sets whether connections to an "ldap" URL are
+	// upgraded to TLS with the StartTLS operation before binding.
+	LDAPStartTLS = "ldap-start-tls"
+
+	// LDAPCACert sets the PEM-encoded CA certificates trusted to sign
+	// the directory server's certificate. If unset, the system's
+	// trusted roots are used.
+	LDAPCACert = "ldap-ca-cert"
+
+	// LDAPBindDN sets the distinguished name the controller binds as
+	// when periodically syncing the groups of LDAP users. If unset,
+	// the directory is searched anonymously.
+	LDAPBindDN = "ldap-bind-dn"
+
+	// LDAPBindPassword sets the password for LDAPBindDN. It is never
+	// returned by the API.
+	LDAPBindPassword = "ldap-bind-password"
+
+	// PasswordMinLength sets the minimum number of characters in the
+	// password of a local user.
+	PasswordMinLength = "password-min-length"
+
+	// PasswordMinClasses sets the minimum number of character
+	// classes (lower case, upper case, digits and other characters)
+	// that the password of a local user must contain.
+	PasswordMinClasses = "password-min-character-classes"
+
+	// PasswordMaxAge sets how long the password of a local user may be
+	// used after it was set, eg "2160h". Passwords do not expire if
+	// this is not set.
+	PasswordMaxAge = "password-max-age"
+
+	// LoginLockoutThreshold sets the number of consecutive failed
+	// password logins after which a local user is locked out for
+	// LoginLockoutDuration, or until re-enabled by a controller
+	// administrator. Users are never locked out if this is zero, and
+	// the controller owner is never locked out.
+	LoginLockoutThreshold = "login-lockout-threshold"
+
+	// LoginLockoutDuration sets how long a local user is locked out
+	// for after too many failed logins, eg "30m".
+	LoginLockoutDuration = "login-lockout-duration"
+
+	// RequireMFA sets whether local users must enable multi-factor
+	// authentication. Users who have not enabled it may only use the
+	// API to do so.
+	RequireMFA = "require-mfa"
+
 	// SetNUMAControlPolicyKey stores the value for this setting
 	SetNUMAControlPolicyKey = "set-numa-control-policy"
 
@@ -93,6 +178,30 @@ const (
 	// "https://acme-staging.api.letsencrypt.org/directory".
 	AutocertURLKey = "autocert-url"
 
+	// ExternalAPICertKey holds a PEM-encoded certificate chain, issued
+	// by an external CA, which the controller serves to clients that
+	// connect to the API by one of the certificate's DNS names.
+	// Connecting by any other name or by address will use the usual
+	// self-generated certificate, so agents keep trusting the
+	// controller's own CA.
+	ExternalAPICertKey = "external-api-cert"
+
+	// ExternalAPIKeyKey holds the PEM-encoded private key for
+	// ExternalAPICertKey. It is never returned by the API; setting
+	// ExternalAPIKeyPathKey instead keeps the key out of the
+	// database altogether.
+	ExternalAPIKeyKey = "external-api-key"
+
+	// ExternalAPICertPathKey holds the path, on each controller
+	// machine, of a file holding an external certificate chain as
+	// for ExternalAPICertKey. The file is reread when it changes.
+	ExternalAPICertPathKey = "external-api-cert-path"
+
+	// ExternalAPIKeyPathKey holds the path, on each controller
+	// machine, of a file holding the private key for
+	// ExternalAPICertPathKey.
+	ExternalAPIKeyPathKey = "external-api-key-path"
+
 	// AllowModelAccessKey sets whether the controller will allow users to
 	// connect to models they have been authorized for even when
 	// they don't have any access rights to the controller itself.
@@ -130,6 +239,14 @@ const (
 	// keep.
 	DefaultAuditLogMaxBackups = 10
 
+	// DefaultOIDCUsernameClaim is the default identity token claim
+	// holding the user's name.
+	DefaultOIDCUsernameClaim = "preferred_username"
+
+	// DefaultOIDCGroupsClaim is the default identity token claim
+	// holding the user's groups.
+	DefaultOIDCGroupsClaim = "groups"
+
 	// DefaultNUMAControlPolicy should not be used by default.
 	// Only use numactl if user specifically requests it
 	DefaultNUMAControlPolicy = false
@@ -153,6 +270,10 @@ const (
 	// DefaultMaxTxnLogCollectionMB is the maximum size the txn log collection.
 	DefaultMaxTxnLogCollectionMB = 10 // 10 MB
 
+	// DefaultLoginLockoutDuration is how long a local user is locked
+	// out for after too many failed logins.
+	DefaultLoginLockoutDuration = 15 * time.Minute
+
 	// JujuHASpace is the network space within which the MongoDB replica-set
 	// should communicate.
 	JujuHASpace = "juju-ha-space"
@@ -171,9 +292,31 @@ var (
 		AutocertDNSNameKey,
 		AutocertURLKey,
 		CACertKey,
+		ExternalAPICertKey,
+		ExternalAPIKeyKey,
+		ExternalAPICertPathKey,
+		ExternalAPIKeyPathKey,
 		ControllerUUIDKey,
 		IdentityPublicKey,
 		IdentityURL,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCUsernameClaim,
+		OIDCGroupsClaim,
+		LDAPURL,
+		LDAPUserDNTemplate,
+		LDAPGroupBaseDN,
+		LDAPAutoCreateUsers,
+		LDAPStartTLS,
+		LDAPCACert,
+		LDAPBindDN,
+		LDAPBindPassword,
+		PasswordMinLength,
+		PasswordMinClasses,
+		PasswordMaxAge,
+		LoginLockoutThreshold,
+		LoginLockoutDuration,
+		RequireMFA,
 		SetNUMAControlPolicyKey,
 		StatePort,
 		MongoMemoryProfile,
@@ -199,8 +342,39 @@ var (
 		AuditingEnabled,
 		AuditLogCaptureArgs,
 		AuditLogExcludeMethods,
+		ExternalAPICertKey,
+		ExternalAPIKeyKey,
+		ExternalAPICertPathKey,
+		ExternalAPIKeyPathKey,
 		JujuHASpace,
 		JujuManagementSpace,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCUsernameClaim,
+		OIDCGroupsClaim,
+		LDAPURL,
+		LDAPUserDNTemplate,
+		LDAPGroupBaseDN,
+		LDAPAutoCreateUsers,
+		LDAPStartTLS,
+		LDAPCACert,
+		LDAPBindDN,
+		LDAPBindPassword,
+		PasswordMinLength,
+		PasswordMinClasses,
+		PasswordMaxAge,
+		LoginLockoutThreshold,
+		LoginLockoutDuration,
+		RequireMFA,
+	)
+
+	// SecretConfigAttributes are the controller config attributes that
+	// hold secrets. They are only ever read from state by the
+	// controllers themselves, and are stripped from the controller
+	// config returned by the API.
+	SecretConfigAttributes = set.NewStrings(
+		ExternalAPIKeyKey,
+		LDAPBindPassword,
 	)
 
 	// DefaultAuditLogExcludeMethods is the default list of methods to
@@ -266,6 +440,17 @@ func (c Config) mustInt(name string) int {
 	return value
 }
 
+// intOrZero returns the named attribute as an integer, returning 0
+// if it isn't found.
+func (c Config) intOrZero(name string) int {
+	// Values obtained over the api are encoded as float64.
+	if value, ok := c[name].(float64); ok {
+		return int(value)
+	}
+	value, _ := c[name].(int)
+	return value
+}
+
 // asString is a private helper method to keep the ugly string casting
 // in once place. It returns the given named attribute as a string,
 // returning "" if it isn't found.
@@ -355,6 +540,8 @@ func (c Config) ControllerUUID() string {
 
 // CACert returns the certificate of the CA that signed the controller
 // certificate, in PEM format, and whether the setting is available.
+// While the controller CA is being rotated it is a bundle of the old
+// and new CA certificates, the first of which is the signing CA.
 //
 // TODO(axw) once the controller config is completely constructed,
 // there will always be a CA certificate. Get rid of the bool result.
@@ -370,6 +557,125 @@ func (c Config) IdentityURL() string {
 	return c.asString(IdentityURL)
 }
 
+// OIDCIssuerURL returns the URL of the OpenID Connect provider, or
+// the empty string if OpenID Connect login is not enabled.
+func (c Config) OIDCIssuerURL() string {
+	return c.asString(OIDCIssuerURL)
+}
+
+// OIDCClientID returns the client ID the controller is registered
+// with at the OpenID Connect provider.
+func (c Config) OIDCClientID() string {
+	return c.asString(OIDCClientID)
+}
+
+// OIDCUsernameClaim returns the identity token claim holding the
+// user's name.
+func (c Config) OIDCUsernameClaim() string {
+	if v := c.asString(OIDCUsernameClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCUsernameClaim
+}
+
+// OIDCGroupsClaim returns the identity token claim holding the
+// user's groups.
+func (c Config) OIDCGroupsClaim() string {
+	if v := c.asString(OIDCGroupsClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCGroupsClaim
+}
+
+// LDAPURL returns the address of the LDAP directory, or the empty
+// string if LDAP login is not enabled.
+func (c Config) LDAPURL() string {
+	return c.asString(LDAPURL)
+}
+
+// LDAPUserDNTemplate returns the template used to build a user's
+// distinguished name from their username.
+func (c Config) LDAPUserDNTemplate() string {
+	return c.asString(LDAPUserDNTemplate)
+}
+
+// LDAPGroupBaseDN returns the base of the directory subtree searched
+// for a user's groups, or the empty string if groups are not synced.
+func (c Config) LDAPGroupBaseDN() string {
+	return c.asString(LDAPGroupBaseDN)
+}
+
+// LDAPAutoCreateUsers reports whether users authenticated by the LDAP
+// directory are added to the controller on first login.
+func (c Config) LDAPAutoCreateUsers() bool {
+	value, _ := c[LDAPAutoCreateUsers].(bool)
+	return value
+}
+
+// LDAPStartTLS reports whether connections to an "ldap" URL are
+// upgraded to TLS before binding.
+func (c Config) LDAPStartTLS() bool {
+	value, _ := c[LDAPStartTLS].(bool)
+	return value
+}
+
+// LDAPCACert returns the CA certificates trusted to sign the directory
+// server's certificate, or the empty string if the system's trusted
+// roots are used.
+func (c Config) LDAPCACert() string {
+	return c.asString(LDAPCACert)
+}
+
+// LDAPBindDN returns the distinguished name the controller binds as
+// when syncing groups, or the empty string for anonymous searches.
+func (c Config) LDAPBindDN() string {
+	return c.asString(LDAPBindDN)
+}
+
+// LDAPBindPassword returns the password for LDAPBindDN.
+func (c Config) LDAPBindPassword() string {
+	return c.asString(LDAPBindPassword)
+}
+
+// PasswordPolicy returns the requirements placed on the passwords of
+// local users.
+func (c Config) PasswordPolicy() PasswordPolicy {
+	policy := PasswordPolicy{
+		MinLength:  c.intOrZero(PasswordMinLength),
+		MinClasses: c.intOrZero(PasswordMinClasses),
+	}
+	if v := c.asString(PasswordMaxAge); v != "" {
+		// Value has already been validated.
+		policy.MaxAge, _ = time.ParseDuration(v)
+	}
+	return policy
+}
+
+// LoginLockoutThreshold returns the number of consecutive failed
+// password logins after which a local user is locked out, or zero if
+// users are never locked out.
+func (c Config) LoginLockoutThreshold() int {
+	return c.intOrZero(LoginLockoutThreshold)
+}
+
+// LoginLockoutDuration returns how long a local user is locked out for
+// after too many failed logins.
+func (c Config) LoginLockoutDuration() time.Duration {
+	if v := c.asString(LoginLockoutDuration); v != "" {
+		// Value has already been validated.
+		d, _ := time.ParseDuration(v)
+		return d
+	}
+	return DefaultLoginLockoutDuration
+}
+
+// RequireMFA reports whether local users must enable multi-factor
+// authentication before they may use the API.
+func (c Config) RequireMFA() bool {
+	value, _ := c[RequireMFA].(bool)
+	return value
+}
+
 // AutocertURL returns the URL used to obtain official TLS certificates
 // when a client connects to the API. See AutocertURLKey
 // for more details.
@@ -383,6 +689,20 @@ func (c Config) AutocertDNSName() string {
 	return c.asString(AutocertDNSNameKey)
 }
 
+// ExternalAPICert returns the PEM-encoded external API certificate
+// chain and private key, if set. See ExternalAPICertKey for more
+// details.
+func (c Config) ExternalAPICert() (certPEM, keyPEM string) {
+	return c.asString(ExternalAPICertKey), c.asString(ExternalAPIKeyKey)
+}
+
+// ExternalAPICertPath returns the paths of the files holding the
+// external API certificate chain and private key, if set. See
+// ExternalAPICertPathKey for more details.
+func (c Config) ExternalAPICertPath() (certPath, keyPath string) {
+	return c.asString(ExternalAPICertPathKey), c.asString(ExternalAPIKeyPathKey)
+}
+
 // IdentityPublicKey returns the public key of the identity manager.
 func (c Config) IdentityPublicKey() *bakery.PublicKey {
 	key := c.asString(IdentityPublicKey)
@@ -480,6 +800,73 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[OIDCIssuerURL].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid OIDC issuer URL")
+		}
+		if u.Scheme != "https" {
+			return errors.Errorf("%s needs to be https", OIDCIssuerURL)
+		}
+		if c.OIDCClientID() == "" {
+			return errors.Errorf("%s requires %s", OIDCIssuerURL, OIDCClientID)
+		}
+	}
+
+	if v, ok := c[LDAPURL].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid LDAP URL")
+		}
+		if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+			return errors.Errorf("%s needs to be ldap or ldaps", LDAPURL)
+		}
+		if strings.Count(c.LDAPUserDNTemplate(), "%s") != 1 {
+			return errors.Errorf(`%s requires %s containing "%%s"`, LDAPURL, LDAPUserDNTemplate)
+		}
+		if c.LDAPStartTLS() && u.Scheme != "ldap" {
+			return errors.Errorf("%s requires an ldap %s", LDAPStartTLS, LDAPURL)
+		}
+	}
+	if v := c.LDAPCACert(); v != "" {
+		if _, err := utilscert.ParseCert(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", LDAPCACert)
+		}
+	}
+
+	if v, ok := c[PasswordMinLength].(int); ok && v < 0 {
+		return errors.Errorf("%s should not be negative, got %d", PasswordMinLength, v)
+	}
+
+	if v, ok := c[PasswordMinClasses].(int); ok && (v < 0 || v > maxPasswordCharacterClasses) {
+		return errors.Errorf("%s should be between 0 and %d, got %d",
+			PasswordMinClasses, maxPasswordCharacterClasses, v)
+	}
+
+	if v, ok := c[PasswordMaxAge].(string); ok && v != "" {
+		age, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid password max age in configuration")
+		}
+		if age < 0 {
+			return errors.Errorf("%s should not be negative, got %v", PasswordMaxAge, age)
+		}
+	}
+
+	if v, ok := c[LoginLockoutThreshold].(int); ok && v < 0 {
+		return errors.Errorf("%s should not be negative, got %d", LoginLockoutThreshold, v)
+	}
+
+	if v, ok := c[LoginLockoutDuration].(string); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid login lockout duration in configuration")
+		}
+		if d <= 0 {
+			return errors.Errorf("%s should be positive, got %v", LoginLockoutDuration, d)
+		}
+	}
+
 	caCert, caCertOK := c.CACert()
 	if !caCertOK {
 		return errors.Errorf("missing CA certificate")
@@ -488,6 +875,10 @@ func Validate(c Config) error {
 		return errors.Annotate(err, "bad CA certificate in configuration")
 	}
 
+	if err := c.validateExternalAPICert(); err != nil {
+		return errors.Trace(err)
+	}
+
 	if uuid, ok := c[ControllerUUIDKey].(string); ok && !utils.IsValidUUIDString(uuid) {
 		return errors.Errorf("controller-uuid: expected UUID, got string(%q)", uuid)
 	}
@@ -561,6 +952,26 @@ func Validate(c Config) error {
 	return nil
 }
 
+func (c Config) validateExternalAPICert() error {
+	certPEM, keyPEM := c.ExternalAPICert()
+	certPath, keyPath := c.ExternalAPICertPath()
+	if (certPEM == "") != (keyPEM == "") {
+		return errors.Errorf("%s and %s must be set together", ExternalAPICertKey, ExternalAPIKeyKey)
+	}
+	if (certPath == "") != (keyPath == "") {
+		return errors.Errorf("%s and %s must be set together", ExternalAPICertPathKey, ExternalAPIKeyPathKey)
+	}
+	if certPEM != "" && certPath != "" {
+		return errors.Errorf("%s and %s cannot both be set", ExternalAPICertKey, ExternalAPICertPathKey)
+	}
+	if certPEM != "" {
+		if _, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM)); err != nil {
+			return errors.Annotate(err, "bad external API certificate in configuration")
+		}
+	}
+	return nil
+}
+
 func (c Config) validateSpaceConfig(key, topic string) error {
 	val := c[key]
 	if val == nil {
@@ -622,9 +1033,31 @@ var configChecker = schema.FieldMap(schema.Fields{
 	StatePort:               schema.ForceInt(),
 	IdentityURL:             schema.String(),
 	IdentityPublicKey:       schema.String(),
+	OIDCIssuerURL:           schema.String(),
+	OIDCClientID:            schema.String(),
+	OIDCUsernameClaim:       schema.String(),
+	OIDCGroupsClaim:         schema.String(),
+	LDAPURL:                 schema.String(),
+	LDAPUserDNTemplate:      schema.String(),
+	LDAPGroupBaseDN:         schema.String(),
+	LDAPAutoCreateUsers:     schema.Bool(),
+	LDAPStartTLS:            schema.Bool(),
+	LDAPCACert:              schema.String(),
+	LDAPBindDN:              schema.String(),
+	LDAPBindPassword:        schema.String(),
+	PasswordMinLength:       schema.ForceInt(),
+	PasswordMinClasses:      schema.ForceInt(),
+	PasswordMaxAge:          schema.String(),
+	LoginLockoutThreshold:   schema.ForceInt(),
+	LoginLockoutDuration:    schema.String(),
+	RequireMFA:              schema.Bool(),
 	SetNUMAControlPolicyKey: schema.Bool(),
 	AutocertURLKey:          schema.String(),
 	AutocertDNSNameKey:      schema.String(),
+	ExternalAPICertKey:      schema.String(),
+	ExternalAPIKeyKey:       schema.String(),
+	ExternalAPICertPathKey:  schema.String(),
+	ExternalAPIKeyPathKey:   schema.String(),
 	AllowModelAccessKey:     schema.Bool(),
 	MongoMemoryProfile:      schema.String(),
 	MaxLogsAge:              schema.String(),
@@ -642,9 +1075,31 @@ var configChecker = schema.FieldMap(schema.Fields{
 	StatePort:               DefaultStatePort,
 	IdentityURL:             schema.Omit,
 	IdentityPublicKey:       schema.Omit,
+	OIDCIssuerURL:           schema.Omit,
+	OIDCClientID:            schema.Omit,
+	OIDCUsernameClaim:       schema.Omit,
+	OIDCGroupsClaim:         schema.Omit,
+	LDAPURL:                 schema.Omit,
+	LDAPUserDNTemplate:      schema.Omit,
+	LDAPGroupBaseDN:         schema.Omit,
+	LDAPAutoCreateUsers:     schema.Omit,
+	LDAPStartTLS:            schema.Omit,
+	LDAPCACert:              schema.Omit,
+	LDAPBindDN:              schema.Omit,
+	LDAPBindPassword:        schema.Omit,
+	PasswordMinLength:       schema.Omit,
+	PasswordMinClasses:      schema.Omit,
+	PasswordMaxAge:          schema.Omit,
+	LoginLockoutThreshold:   schema.Omit,
+	LoginLockoutDuration:    schema.Omit,
+	RequireMFA:              schema.Omit,
 	SetNUMAControlPolicyKey: DefaultNUMAControlPolicy,
 	AutocertURLKey:          schema.Omit,
 	AutocertDNSNameKey:      schema.Omit,
+	ExternalAPICertKey:      schema.Omit,
+	ExternalAPIKeyKey:       schema.Omit,
+	ExternalAPICertPathKey:  schema.Omit,
+	ExternalAPIKeyPathKey:   schema.Omit,
 	AllowModelAccessKey:     schema.Omit,
 	MongoMemoryProfile:      schema.Omit,
 	MaxLogsAge:              fmt.Sprintf("%vh", DefaultMaxLogsAgeDays*24),
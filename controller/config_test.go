@@ -115,6 +115,140 @@ var validateTests = []struct {
 		controller.IdentityURL:       "http://0.1.2.3/foo",
 		controller.CACertKey:         testing.CACert,
 	},
+}, {
+	about: "OIDC issuer OK",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://login.example.com",
+		controller.OIDCClientID:  "juju",
+		controller.CACertKey:     testing.CACert,
+	},
+}, {
+	about: "OIDC issuer must be https",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "http://login.example.com",
+		controller.OIDCClientID:  "juju",
+		controller.CACertKey:     testing.CACert,
+	},
+	expectError: `oidc-issuer-url needs to be https`,
+}, {
+	about: "OIDC issuer requires client ID",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://login.example.com",
+		controller.CACertKey:     testing.CACert,
+	},
+	expectError: `oidc-issuer-url requires oidc-client-id`,
+}, {
+	about: "LDAP URL OK",
+	config: controller.Config{
+		controller.LDAPURL:            "ldaps://ldap.example.com",
+		controller.LDAPUserDNTemplate: "uid=%s,ou=people,dc=example,dc=com",
+		controller.CACertKey:          testing.CACert,
+	},
+}, {
+	about: "LDAP URL must be ldap or ldaps",
+	config: controller.Config{
+		controller.LDAPURL:            "https://ldap.example.com",
+		controller.LDAPUserDNTemplate: "uid=%s,ou=people,dc=example,dc=com",
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `ldap-url needs to be ldap or ldaps`,
+}, {
+	about: "LDAP URL requires user DN template",
+	config: controller.Config{
+		controller.LDAPURL:   "ldap://ldap.example.com",
+		controller.CACertKey: testing.CACert,
+	},
+	expectError: `ldap-url requires ldap-user-dn-template containing "%s"`,
+}, {
+	about: "LDAP StartTLS requires ldap URL",
+	config: controller.Config{
+		controller.LDAPURL:            "ldaps://ldap.example.com",
+		controller.LDAPUserDNTemplate: "uid=%s,ou=people,dc=example,dc=com",
+		controller.LDAPStartTLS:       true,
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `ldap-start-tls requires an ldap ldap-url`,
+}, {
+	about: "LDAP CA cert must be valid",
+	config: controller.Config{
+		controller.LDAPCACert: "not a certificate",
+		controller.CACertKey:  testing.CACert,
+	},
+	expectError: `invalid ldap-ca-cert: .*`,
+}, {
+	about: "password min character classes out of range",
+	config: controller.Config{
+		controller.PasswordMinClasses: 5,
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `password-min-character-classes should be between 0 and 4, got 5`,
+}, {
+	about: "invalid password max age",
+	config: controller.Config{
+		controller.PasswordMaxAge: "90 days",
+		controller.CACertKey:      testing.CACert,
+	},
+	expectError: `invalid password max age in configuration: time: .*`,
+}, {
+	about: "negative login lockout threshold",
+	config: controller.Config{
+		controller.LoginLockoutThreshold: -1,
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `login-lockout-threshold should not be negative, got -1`,
+}, {
+	about: "invalid login lockout duration",
+	config: controller.Config{
+		controller.LoginLockoutDuration: "a while",
+		controller.CACertKey:            testing.CACert,
+	},
+	expectError: `invalid login lockout duration in configuration: time: .*`,
+}, {
+	about: "zero login lockout duration",
+	config: controller.Config{
+		controller.LoginLockoutDuration: "0s",
+		controller.CACertKey:            testing.CACert,
+	},
+	expectError: `login-lockout-duration should be positive, got 0s`,
+}, {
+	about: "external API certificate OK",
+	config: controller.Config{
+		controller.ExternalAPICertKey: testing.ServerCert,
+		controller.ExternalAPIKeyKey:  testing.ServerKey,
+		controller.CACertKey:          testing.CACert,
+	},
+}, {
+	about: "external API certificate requires key",
+	config: controller.Config{
+		controller.ExternalAPICertKey: testing.ServerCert,
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `external-api-cert and external-api-key must be set together`,
+}, {
+	about: "external API certificate mismatched key",
+	config: controller.Config{
+		controller.ExternalAPICertKey: testing.ServerCert,
+		controller.ExternalAPIKeyKey:  testing.CAKey,
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `bad external API certificate in configuration: .*`,
+}, {
+	about: "external API certificate paths require both",
+	config: controller.Config{
+		controller.ExternalAPIKeyPathKey: "/etc/juju/api.key",
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `external-api-cert-path and external-api-key-path must be set together`,
+}, {
+	about: "external API certificate and path",
+	config: controller.Config{
+		controller.ExternalAPICertKey:     testing.ServerCert,
+		controller.ExternalAPIKeyKey:      testing.ServerKey,
+		controller.ExternalAPICertPathKey: "/etc/juju/api.crt",
+		controller.ExternalAPIKeyPathKey:  "/etc/juju/api.key",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `external-api-cert and external-api-cert-path cannot both be set`,
 }, {
 	about: "invalid identity public key",
 	config: controller.Config{
@@ -200,6 +334,96 @@ func (s *ConfigSuite) TestValidate(c *gc.C) {
 	}
 }
 
+func (s *ConfigSuite) TestOIDCConfigDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.OIDCIssuerURL(), gc.Equals, "")
+	c.Assert(cfg.OIDCUsernameClaim(), gc.Equals, "preferred_username")
+	c.Assert(cfg.OIDCGroupsClaim(), gc.Equals, "groups")
+}
+
+func (s *ConfigSuite) TestOIDCConfigValues(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, map[string]interface{}{
+		"oidc-issuer-url":     "https://login.example.com",
+		"oidc-client-id":      "juju",
+		"oidc-username-claim": "sub",
+		"oidc-groups-claim":   "roles",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.OIDCIssuerURL(), gc.Equals, "https://login.example.com")
+	c.Assert(cfg.OIDCClientID(), gc.Equals, "juju")
+	c.Assert(cfg.OIDCUsernameClaim(), gc.Equals, "sub")
+	c.Assert(cfg.OIDCGroupsClaim(), gc.Equals, "roles")
+}
+
+func (s *ConfigSuite) TestLDAPConfigValues(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, map[string]interface{}{
+		"ldap-url":               "ldap://ldap.example.com",
+		"ldap-user-dn-template":  "uid=%s,ou=people,dc=example,dc=com",
+		"ldap-group-base-dn":     "ou=groups,dc=example,dc=com",
+		"ldap-auto-create-users": true,
+		"ldap-start-tls":         true,
+		"ldap-ca-cert":           testing.CACert,
+		"ldap-bind-dn":           "cn=juju,dc=example,dc=com",
+		"ldap-bind-password":     "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LDAPURL(), gc.Equals, "ldap://ldap.example.com")
+	c.Assert(cfg.LDAPUserDNTemplate(), gc.Equals, "uid=%s,ou=people,dc=example,dc=com")
+	c.Assert(cfg.LDAPGroupBaseDN(), gc.Equals, "ou=groups,dc=example,dc=com")
+	c.Assert(cfg.LDAPAutoCreateUsers(), jc.IsTrue)
+	c.Assert(cfg.LDAPStartTLS(), jc.IsTrue)
+	c.Assert(cfg.LDAPCACert(), gc.Equals, testing.CACert)
+	c.Assert(cfg.LDAPBindDN(), gc.Equals, "cn=juju,dc=example,dc=com")
+	c.Assert(cfg.LDAPBindPassword(), gc.Equals, "secret")
+}
+
+func (s *ConfigSuite) TestRequireMFA(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.RequireMFA(), jc.IsFalse)
+
+	cfg, err = controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, map[string]interface{}{
+		"require-mfa": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.RequireMFA(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestPasswordPolicy(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.PasswordPolicy(), gc.Equals, controller.PasswordPolicy{})
+	c.Assert(cfg.LoginLockoutThreshold(), gc.Equals, 0)
+	c.Assert(cfg.LoginLockoutDuration(), gc.Equals, controller.DefaultLoginLockoutDuration)
+
+	cfg, err = controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, map[string]interface{}{
+		"password-min-length":            8,
+		"password-min-character-classes": 3,
+		"password-max-age":               "2160h",
+		"login-lockout-threshold":        5,
+		"login-lockout-duration":         "1h",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	policy := cfg.PasswordPolicy()
+	c.Assert(policy, gc.Equals, controller.PasswordPolicy{
+		MinLength:  8,
+		MinClasses: 3,
+		MaxAge:     90 * 24 * time.Hour,
+	})
+	c.Assert(cfg.LoginLockoutThreshold(), gc.Equals, 5)
+	c.Assert(cfg.LoginLockoutDuration(), gc.Equals, time.Hour)
+
+	c.Assert(policy.Validate("Sh0rt"), gc.ErrorMatches, "password must be at least 8 characters long")
+	c.Assert(policy.Validate("lowercaseonly"), gc.ErrorMatches, "password must contain at least 3 of .*")
+	c.Assert(policy.Validate("Mixed-case-1"), jc.ErrorIsNil)
+
+	set := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(policy.Expired(set, set.Add(90*24*time.Hour)), jc.IsFalse)
+	c.Assert(policy.Expired(set, set.Add(91*24*time.Hour)), jc.IsTrue)
+	c.Assert(controller.PasswordPolicy{}.Expired(set, set.Add(10000*time.Hour)), jc.IsFalse)
+}
+
 func (s *ConfigSuite) TestLogConfigDefaults(c *gc.C) {
 	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,75 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+)
+
+// maxPasswordCharacterClasses is the number of character classes
+// distinguished by PasswordPolicy.
+const maxPasswordCharacterClasses = 4
+
+// PasswordPolicy holds the requirements placed on the passwords of
+// local users.
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters in a password.
+	MinLength int
+
+	// MinClasses is the minimum number of character classes
+	// (lower case, upper case, digits and other characters) that a
+	// password must contain.
+	MinClasses int
+
+	// MaxAge is how long a password may be used after it was set,
+	// or zero if passwords do not expire.
+	MaxAge time.Duration
+}
+
+// Validate returns a NotValid error if the given password does not
+// meet the policy.
+func (p PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return errors.NewNotValid(nil, fmt.Sprintf(
+			"password must be at least %d characters long", p.MinLength,
+		))
+	}
+	if passwordCharacterClasses(password) < p.MinClasses {
+		return errors.NewNotValid(nil, fmt.Sprintf(
+			"password must contain at least %d of lower case letters, upper case letters, digits and other characters",
+			p.MinClasses,
+		))
+	}
+	return nil
+}
+
+// Expired reports whether a password set at the given time has
+// expired at the given time.
+func (p PasswordPolicy) Expired(set, now time.Time) bool {
+	return p.MaxAge > 0 && now.After(set.Add(p.MaxAge))
+}
+
+// passwordCharacterClasses returns the number of different character
+// classes in the given password.
+func passwordCharacterClasses(password string) int {
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
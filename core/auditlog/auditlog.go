@@ -90,12 +90,36 @@ type Error struct {
 	Code    string `json:"code"`
 }
 
+// Login records an attempt by a user to log in to the API, whether
+// or not it succeeded.
+type Login struct {
+	Who           string `json:"who"`
+	When          string `json:"when"`
+	ModelUUID     string `json:"model-uuid,omitempty"`
+	ConnectionID  string `json:"connection-id"`
+	RemoteAddress string `json:"remote-address"`
+	Success       bool   `json:"success"`
+	Error         string `json:"error,omitempty"`
+}
+
+// LoginArgs is the information about a login attempt that we want to
+// record.
+type LoginArgs struct {
+	Who           string
+	ModelUUID     string
+	ConnectionID  uint64
+	RemoteAddress string
+	Error         error
+}
+
 // Record is the top-level entry type in an audit log, which serves as
-// a type discriminator. Only one of Conversation/Request/Errors should be set.
+// a type discriminator. Only one of Conversation/Request/Errors/Login
+// should be set.
 type Record struct {
 	Conversation *Conversation   `json:"conversation,omitempty"`
 	Request      *Request        `json:"request,omitempty"`
 	Errors       *ResponseErrors `json:"errors,omitempty"`
+	Login        *Login          `json:"login,omitempty"`
 }
 
 // AuditLog represents something that can store calls, requests and
@@ -104,9 +128,26 @@ type AuditLog interface {
 	AddConversation(c Conversation) error
 	AddRequest(r Request) error
 	AddResponse(r ResponseErrors) error
+	AddLogin(l Login) error
 	Close() error
 }
 
+// RecordLogin stores details of a login attempt in the log.
+func RecordLogin(log AuditLog, clock clock.Clock, l LoginArgs) error {
+	login := Login{
+		Who:           l.Who,
+		When:          clock.Now().Format(time.RFC3339),
+		ModelUUID:     l.ModelUUID,
+		ConnectionID:  idString(l.ConnectionID),
+		RemoteAddress: l.RemoteAddress,
+		Success:       l.Error == nil,
+	}
+	if l.Error != nil {
+		login.Error = l.Error.Error()
+	}
+	return errors.Trace(log.AddLogin(login))
+}
+
 // Recorder records method calls for a specific API connection.
 type Recorder struct {
 	log          AuditLog
@@ -221,6 +262,11 @@ func (a *auditLogFile) AddResponse(m ResponseErrors) error {
 	return errors.Trace(a.addRecord(Record{Errors: &m}))
 }
 
+// AddLogin implements AuditLog.
+func (a *auditLogFile) AddLogin(l Login) error {
+	return errors.Trace(a.addRecord(Record{Login: &l}))
+}
+
 // Close implements AuditLog.
 func (a *auditLogFile) Close() error {
 	return errors.Trace(a.fileLogger.Close())
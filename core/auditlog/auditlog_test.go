@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -137,6 +138,66 @@ func (s *AuditLogSuite) TestRecorder(c *gc.C) {
 	})
 }
 
+func (s *AuditLogSuite) TestRecordLogin(c *gc.C) {
+	var log fakeLog
+	logTime, err := time.Parse(time.RFC3339, "2018-05-02T10:11:12Z")
+	c.Assert(err, jc.ErrorIsNil)
+	clock := testing.NewClock(logTime)
+
+	err = auditlog.RecordLogin(&log, clock, auditlog.LoginArgs{
+		Who:           "bob",
+		ModelUUID:     "1234",
+		ConnectionID:  687,
+		RemoteAddress: "10.0.0.1:53412",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = auditlog.RecordLogin(&log, clock, auditlog.LoginArgs{
+		Who:           "mallory",
+		ConnectionID:  688,
+		RemoteAddress: "10.0.0.2:53413",
+		Error:         errors.New("invalid entity name or password"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	log.stub.CheckCalls(c, []testing.StubCall{{
+		"AddLogin", []interface{}{auditlog.Login{
+			Who:           "bob",
+			When:          "2018-05-02T10:11:12Z",
+			ModelUUID:     "1234",
+			ConnectionID:  "2AF",
+			RemoteAddress: "10.0.0.1:53412",
+			Success:       true,
+		}},
+	}, {
+		"AddLogin", []interface{}{auditlog.Login{
+			Who:           "mallory",
+			When:          "2018-05-02T10:11:12Z",
+			ConnectionID:  "2B0",
+			RemoteAddress: "10.0.0.2:53413",
+			Error:         "invalid entity name or password",
+		}},
+	}})
+}
+
+func (s *AuditLogSuite) TestAuditLogFileLogin(c *gc.C) {
+	dir := c.MkDir()
+	logFile := auditlog.NewLogFile(dir, 300, 10)
+	err := logFile.AddLogin(auditlog.Login{
+		Who:           "mallory",
+		When:          "2018-05-02T10:11:12Z",
+		ConnectionID:  "2B0",
+		RemoteAddress: "10.0.0.2:53413",
+		Error:         "invalid entity name or password",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = logFile.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	bytes, err := ioutil.ReadFile(filepath.Join(dir, "audit.log"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(bytes), gc.Equals, `{"login":{"who":"mallory","when":"2018-05-02T10:11:12Z","connection-id":"2B0","remote-address":"10.0.0.2:53413","success":false,"error":"invalid entity name or password"}}`+"\n")
+}
+
 type fakeLog struct {
 	stub testing.Stub
 }
@@ -156,6 +217,11 @@ func (l *fakeLog) AddResponse(m auditlog.ResponseErrors) error {
 	return l.stub.NextErr()
 }
 
+func (l *fakeLog) AddLogin(m auditlog.Login) error {
+	l.stub.AddCall("AddLogin", m)
+	return l.stub.NextErr()
+}
+
 func (l *fakeLog) Close() error {
 	l.stub.AddCall("Close")
 	return l.stub.NextErr()
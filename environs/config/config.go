@@ -28,6 +28,7 @@ import (
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/network"
+	proxyconfig "github.com/juju/juju/utils/proxy"
 )
 
 var logger = loggo.GetLogger("juju.environs.config")
@@ -92,6 +93,11 @@ const (
 	// NoProxyKey stores the key for this setting.
 	NoProxyKey = "no-proxy"
 
+	// ControllerProxyKey is the key for the URL of an HTTP CONNECT
+	// or SOCKS5 proxy through which the agents of new machines
+	// connect to the controller.
+	ControllerProxyKey = "controller-proxy"
+
 	// AptHTTPProxyKey stores the key for this setting.
 	AptHTTPProxyKey = "apt-http-proxy"
 
@@ -119,7 +125,9 @@ const (
 	StorageDefaultFilesystemSourceKey = "storage-default-filesystem-source"
 
 	// ResourceTagsKey is an optional list or space-separated string
-	// of k=v pairs, defining the tags for ResourceTags.
+	// of k=v pairs, defining the tags for ResourceTags. Only the
+	// providers that implement environs.ResourceRetagger (EC2 and
+	// Azure) update existing resources when the tags change.
 	ResourceTagsKey = "resource-tags"
 
 	// LogForwardEnabled determines whether the log forward functionality is enabled.
@@ -178,6 +186,15 @@ const (
 	// FanConfig defines the configuration for FAN network running in the model.
 	FanConfig = "fan-config"
 
+	// PreferIPv6Key is the key for whether IPv6 addresses should be
+	// preferred over IPv4 addresses of the same scope when selecting
+	// the public and private addresses of machines in the model.
+	PreferIPv6Key = "prefer-ipv6"
+
+	// ExposeIPv6Key is the key for whether exposed applications are
+	// opened to IPv6 sources as well as IPv4 sources.
+	ExposeIPv6Key = "expose-ipv6"
+
 	// CloudInitUserDataKey is the key to specify cloud-init yaml the user
 	// wants to add into the cloud-config data produced by Juju when
 	// provisioning machines.
@@ -186,6 +203,22 @@ const (
 	// BackupDirKey specifies the backup working directory.
 	BackupDirKey = "backup-dir"
 
+	// MachineRecoveryKey is the key for whether Juju should recover
+	// machines whose agents have been lost, and whose instances the
+	// provider reports as stopped or missing, by restarting or
+	// replacing their instances.
+	MachineRecoveryKey = "machine-recovery"
+
+	// MachineRecoveryReplaceKey is the key for whether machines that
+	// cannot be recovered by restarting their instances are replaced
+	// by new machines, to which their units are moved.
+	MachineRecoveryReplaceKey = "machine-recovery-replace"
+
+	// MachineRecoveryTimeoutKey is how long a machine's agent must have
+	// been lost, and its instance stopped or missing, before Juju
+	// attempts to recover the machine, eg "15m".
+	MachineRecoveryTimeoutKey = "machine-recovery-timeout"
+
 	// ContainerInheritProperiesKey is the key to specify a list of properties
 	// to be copied from a machine to a container during provisioning.  The
 	// list will be comma separated.
@@ -354,6 +387,10 @@ const (
 	DefaultActionResultsAge = "336h" // 2 weeks
 
 	DefaultActionResultsSize = "5G"
+
+	// DefaultMachineRecoveryTimeout is the default value for
+	// MachineRecoveryTimeoutKey.
+	DefaultMachineRecoveryTimeout = "15m"
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -397,9 +434,14 @@ var defaultConfigValues = map[string]interface{}{
 	UpdateStatusHookInterval:     DefaultUpdateStatusHookInterval,
 	EgressSubnets:                "",
 	FanConfig:                    "",
+	PreferIPv6Key:                false,
+	ExposeIPv6Key:                false,
 	CloudInitUserDataKey:         "",
 	ContainerInheritProperiesKey: "",
 	BackupDirKey:                 "",
+	MachineRecoveryKey:           false,
+	MachineRecoveryReplaceKey:    false,
+	MachineRecoveryTimeoutKey:    DefaultMachineRecoveryTimeout,
 
 	// Image and agent streams and URLs.
 	"image-stream":       "released",
@@ -411,15 +453,16 @@ var defaultConfigValues = map[string]interface{}{
 	LogForwardEnabled: false,
 
 	// Proxy settings.
-	HTTPProxyKey:     "",
-	HTTPSProxyKey:    "",
-	FTPProxyKey:      "",
-	NoProxyKey:       "127.0.0.1,localhost,::1",
-	AptHTTPProxyKey:  "",
-	AptHTTPSProxyKey: "",
-	AptFTPProxyKey:   "",
-	AptNoProxyKey:    "",
-	"apt-mirror":     "",
+	HTTPProxyKey:       "",
+	HTTPSProxyKey:      "",
+	FTPProxyKey:        "",
+	NoProxyKey:         "127.0.0.1,localhost,::1",
+	ControllerProxyKey: "",
+	AptHTTPProxyKey:    "",
+	AptHTTPSProxyKey:   "",
+	AptFTPProxyKey:     "",
+	AptNoProxyKey:      "",
+	"apt-mirror":       "",
 
 	// Status history settings
 	MaxStatusHistoryAge:  DefaultStatusHistoryAge,
@@ -581,6 +624,20 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[MachineRecoveryTimeoutKey].(string); ok {
+		if d, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid machine recovery timeout in model configuration")
+		} else if d < time.Minute {
+			return errors.Errorf("machine recovery timeout %v cannot be less than 1m", d)
+		}
+	}
+
+	if v, ok := cfg.defined[ControllerProxyKey].(string); ok {
+		if _, err := proxyconfig.ParseControllerProxy(v); err != nil {
+			return errors.Annotate(err, "invalid controller proxy")
+		}
+	}
+
 	if v, ok := cfg.defined[EgressSubnets].(string); ok && v != "" {
 		cidrs := strings.Split(v, ",")
 		for _, cidr := range cidrs {
@@ -823,6 +880,13 @@ func (c *Config) NoProxy() string {
 	return c.asString(NoProxyKey)
 }
 
+// ControllerProxy returns the URL of the proxy through which the
+// agents of new machines connect to the controller, or the empty
+// string if they connect directly.
+func (c *Config) ControllerProxy() string {
+	return c.asString(ControllerProxyKey)
+}
+
 func (c *Config) getWithFallback(key, fallback string) string {
 	value := c.asString(key)
 	if value == "" {
@@ -1111,6 +1175,9 @@ func (c *Config) resourceTags() (map[string]string, error) {
 			return nil, errors.Errorf("tag %q uses reserved prefix %q", k, tags.JujuTagPrefix)
 		}
 	}
+	if err := tags.ValidateTemplates(v); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return v, nil
 }
 
@@ -1180,6 +1247,48 @@ func (c *Config) FanConfig() (network.FanConfig, error) {
 	return network.ParseFanConfig(c.asString(FanConfig))
 }
 
+// PreferIPv6 reports whether IPv6 addresses should be preferred over
+// IPv4 addresses of the same scope in this model.
+func (c *Config) PreferIPv6() bool {
+	v, _ := c.defined[PreferIPv6Key].(bool)
+	return v
+}
+
+// ExposeIPv6 reports whether exposed applications in this model are
+// opened to IPv6 sources as well as IPv4 sources.
+func (c *Config) ExposeIPv6() bool {
+	v, _ := c.defined[ExposeIPv6Key].(bool)
+	return v
+}
+
+// MachineRecovery reports whether Juju should recover machines whose
+// agents have been lost and whose instances have been stopped or
+// removed outside of Juju.
+func (c *Config) MachineRecovery() bool {
+	v, _ := c.defined[MachineRecoveryKey].(bool)
+	return v
+}
+
+// MachineRecoveryReplace reports whether Juju should replace lost
+// machines whose instances cannot be restarted.
+func (c *Config) MachineRecoveryReplace() bool {
+	v, _ := c.defined[MachineRecoveryReplaceKey].(bool)
+	return v
+}
+
+// MachineRecoveryTimeout is how long a machine's agent must have been
+// lost, and its instance stopped or missing, before Juju attempts to
+// recover the machine.
+func (c *Config) MachineRecoveryTimeout() time.Duration {
+	raw := c.asString(MachineRecoveryTimeoutKey)
+	if raw == "" {
+		raw = DefaultMachineRecoveryTimeout
+	}
+	// Value has already been validated.
+	val, _ := time.ParseDuration(raw)
+	return val
+}
+
 // CloudInitUserData returns a copy of the raw user data attributes
 // that were specified by the user.
 func (c *Config) CloudInitUserData() map[string]interface{} {
@@ -1281,6 +1390,7 @@ var alwaysOptional = schema.Defaults{
 	HTTPSProxyKey:                schema.Omit,
 	FTPProxyKey:                  schema.Omit,
 	NoProxyKey:                   schema.Omit,
+	ControllerProxyKey:           schema.Omit,
 	AptHTTPProxyKey:              schema.Omit,
 	AptHTTPSProxyKey:             schema.Omit,
 	AptFTPProxyKey:               schema.Omit,
@@ -1312,9 +1422,14 @@ var alwaysOptional = schema.Defaults{
 	UpdateStatusHookInterval:     schema.Omit,
 	EgressSubnets:                schema.Omit,
 	FanConfig:                    schema.Omit,
+	PreferIPv6Key:                schema.Omit,
+	ExposeIPv6Key:                schema.Omit,
 	CloudInitUserDataKey:         schema.Omit,
 	ContainerInheritProperiesKey: schema.Omit,
 	BackupDirKey:                 schema.Omit,
+	MachineRecoveryKey:           schema.Omit,
+	MachineRecoveryReplaceKey:    schema.Omit,
+	MachineRecoveryTimeoutKey:    schema.Omit,
 }
 
 func allowEmpty(attr string) bool {
@@ -1622,6 +1737,11 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	ControllerProxyKey: {
+		Description: "URL of an HTTP CONNECT (http://) or SOCKS5 (socks5://) proxy through which the agents of new machines connect to the controller",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	ProvisionerHarvestModeKey: {
 		// default: destroyed, but also depends on current setting of ProvisionerSafeModeKey
 		Description: "What to do with unknown machines. See https://jujucharms.com/docs/stable/config-general#juju-lifecycle-and-harvesting (default destroyed)",
@@ -1636,7 +1756,7 @@ global or per instance security groups.`,
 		Group:       environschema.EnvironGroup,
 	},
 	ResourceTagsKey: {
-		Description: "resource tags",
+		Description: "Space-separated list of key=value pairs used to tag resources in the cloud. Values may use the ${model}, ${machine}, ${application} and ${unit} placeholders. Tags are applied to resources when they are created; when the tags change, existing resources are retagged only on EC2 and Azure.",
 		Type:        environschema.Tattrs,
 		Group:       environschema.EnvironGroup,
 	},
@@ -1755,6 +1875,16 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	PreferIPv6Key: {
+		Description: "Whether IPv6 addresses are preferred over IPv4 addresses when selecting machine addresses",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	ExposeIPv6Key: {
+		Description: "Whether exposed applications are opened to all IPv6 sources (::/0) as well as all IPv4 sources",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 	CloudInitUserDataKey: {
 		Description: "Cloud-init user-data (in yaml format) to be added to userdata for new machines created in this model",
 		Type:        environschema.Tstring,
@@ -1770,4 +1900,19 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	MachineRecoveryKey: {
+		Description: "Whether to restart the instances of machines whose agents have been lost and whose instances have been stopped outside of Juju, or to replace the machines if enabled with machine-recovery-replace",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	MachineRecoveryReplaceKey: {
+		Description: "Whether machine recovery replaces lost machines whose instances cannot be restarted with new machines, redeploying their units (default false)",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	MachineRecoveryTimeoutKey: {
+		Description: "How long a machine must have been lost before it is recovered, in human-readable time format (default 15m)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 }
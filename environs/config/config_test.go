@@ -369,6 +369,40 @@ var configTests = []configTest{
 			"logging-config": "foo=bar",
 		}),
 		err: `unknown severity level "bar"`,
+	}, {
+		about:       "Invalid machine recovery timeout",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"machine-recovery-timeout": "soon",
+		}),
+		err: `invalid machine recovery timeout in model configuration: time: invalid duration "?soon"?`,
+	}, {
+		about:       "Machine recovery timeout too short",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"machine-recovery-timeout": "30s",
+		}),
+		err: `machine recovery timeout 30s cannot be less than 1m`,
+	}, {
+		about:       "Valid controller proxy",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"controller-proxy": "socks5://10.0.0.1:1080",
+		}),
+	}, {
+		about:       "Controller proxy with unsupported scheme",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"controller-proxy": "ftp://10.0.0.1:21",
+		}),
+		err: `invalid controller proxy: controller proxy scheme "ftp" not valid`,
+	}, {
+		about:       "Controller proxy without host",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"controller-proxy": "http://",
+		}),
+		err: `invalid controller proxy: controller proxy "http:" without host not valid`,
 	}, {
 		about:       "Sample configuration",
 		useDefaults: config.UseDefaults,
@@ -461,6 +495,13 @@ var configTests = []configTest{
 			"resource-tags": []string{"a"},
 		}),
 		err: `resource-tags: expected "key=value", got "a"`,
+	}, {
+		about:       "Resource tags contains unknown placeholder",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"resource-tags": []string{"owner=${user}"},
+		}),
+		err: `validating resource tags: placeholder "\$\{user\}" in tag "owner" not valid`,
 	}, {
 		about:       "Invalid syslog ca cert format",
 		useDefaults: config.UseDefaults,
@@ -914,7 +955,7 @@ func (s *ConfigSuite) TestValidateCloudInitUserData(c *gc.C) {
 func (test configValidateCloudInitUserDataTest) checkNew(c *gc.C) {
 	final := testing.Attrs{
 		"type": "my-type", "name": "my-name",
-		"uuid": testing.ModelTag.Id(),
+		"uuid":                      testing.ModelTag.Id(),
 		config.CloudInitUserDataKey: test.value,
 	}
 
@@ -1239,6 +1280,48 @@ func (s *ConfigSuite) TestEgressSubnets(c *gc.C) {
 	c.Assert(cfg.EgressSubnets(), gc.DeepEquals, []string{"10.0.0.1/32", "192.168.1.1/16"})
 }
 
+func (s *ConfigSuite) TestControllerProxy(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ControllerProxy(), gc.Equals, "")
+	cfg = newTestConfig(c, testing.Attrs{
+		"controller-proxy": "http://proxy.example.com:3128",
+	})
+	c.Assert(cfg.ControllerProxy(), gc.Equals, "http://proxy.example.com:3128")
+}
+
+func (s *ConfigSuite) TestPreferIPv6(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.PreferIPv6(), jc.IsFalse)
+	cfg = newTestConfig(c, testing.Attrs{
+		"prefer-ipv6": true,
+	})
+	c.Assert(cfg.PreferIPv6(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestExposeIPv6(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ExposeIPv6(), jc.IsFalse)
+	cfg = newTestConfig(c, testing.Attrs{
+		"expose-ipv6": true,
+	})
+	c.Assert(cfg.ExposeIPv6(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestMachineRecovery(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.MachineRecovery(), jc.IsFalse)
+	c.Assert(cfg.MachineRecoveryReplace(), jc.IsFalse)
+	c.Assert(cfg.MachineRecoveryTimeout(), gc.Equals, 15*time.Minute)
+	cfg = newTestConfig(c, testing.Attrs{
+		"machine-recovery":         true,
+		"machine-recovery-replace": true,
+		"machine-recovery-timeout": "1h",
+	})
+	c.Assert(cfg.MachineRecovery(), jc.IsTrue)
+	c.Assert(cfg.MachineRecoveryReplace(), jc.IsTrue)
+	c.Assert(cfg.MachineRecoveryTimeout(), gc.Equals, time.Hour)
+}
+
 func (s *ConfigSuite) TestCloudInitUserDataFromEnvironment(c *gc.C) {
 	cfg := newTestConfig(c, testing.Attrs{
 		config.CloudInitUserDataKey: validCloudInitUserData,
@@ -1318,6 +1401,17 @@ func (s *ConfigSuite) TestCoerceForStorage(c *gc.C) {
 	c.Assert(tagsMap, gc.DeepEquals, expectedTags)
 }
 
+func (s *ConfigSuite) TestResourceTagsTemplates(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"resource-tags": "owner=${unit} cost-centre=${model}-${application}"})
+	tags, ok := cfg.ResourceTags()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(tags, gc.DeepEquals, map[string]string{
+		"owner":       "${unit}",
+		"cost-centre": "${model}-${application}",
+	})
+}
+
 var specializeCharmRepoTests = []struct {
 	about    string
 	testMode bool
@@ -423,6 +423,40 @@ type InstanceTagger interface {
 	TagInstance(id instance.Id, tags map[string]string) error
 }
 
+// ResourceRetagger is an interface that can be used for updating the
+// tags of a model's resources after the tags they should have change,
+// e.g. because the model's resource tags have changed.
+//
+// The tags named in removed are removed from each resource, along with
+// those of the model's resource tags that have no value for it, unless
+// they are among the resource's new tags. Other existing tags are left
+// alone.
+//
+// Only the EC2 and Azure providers implement ResourceRetagger; with
+// other providers, changes to the model's resource tags only apply to
+// resources created after the change.
+type ResourceRetagger interface {
+	// RetagInstance tags the given instance, and the resources that
+	// belong to it such as its root disk, with the specified tags.
+	RetagInstance(id instance.Id, tags map[string]string, removed []string) error
+
+	// RetagResources tags the model's resources that do not belong
+	// to any one instance, such as volumes and security groups, with
+	// the model's resource tags.
+	RetagResources(controllerUUID string, removed []string) error
+}
+
+// InstanceRestarter is an interface that an Environ may implement
+// to restart instances that have been stopped outside of Juju, so
+// that lost machines can be recovered without reprovisioning them.
+type InstanceRestarter interface {
+	// RestartInstances starts the given stopped instances. An
+	// error is returned if any instance cannot be started, for
+	// example because it has been terminated; instances that are
+	// already running are left alone.
+	RestartInstances(ids ...instance.Id) error
+}
+
 // InstanceTypesFetcher is an interface that allows for instance information from
 // a provider to be obtained.
 type InstanceTypesFetcher interface {
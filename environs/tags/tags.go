@@ -3,7 +3,14 @@
 
 package tags
 
-import "gopkg.in/juju/names.v2"
+import (
+	"regexp"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+)
 
 const (
 	// JujuTagPrefix is the prefix for Juju-managed tags.
@@ -63,7 +70,144 @@ func ResourceTags(modelTag names.ModelTag, controllerTag names.ControllerTag, ta
 			allTags[k] = v
 		}
 	}
+	// Placeholders that were not expanded by a Templated
+	// tagger have no value for this resource.
+	allTags = TemplateValues{}.Expand(allTags)
 	allTags[JujuModel] = modelTag.Id()
 	allTags[JujuController] = controllerTag.Id()
 	return allTags
 }
+
+// The placeholders that may be used in the values of
+// user-defined resource tags.
+const (
+	// ModelPlaceholder is replaced with the name of the model.
+	ModelPlaceholder = "${model}"
+
+	// MachinePlaceholder is replaced with the ID of the machine
+	// that a resource belongs to.
+	MachinePlaceholder = "${machine}"
+
+	// ApplicationPlaceholder is replaced with the name of the
+	// application that a resource belongs to. For a machine
+	// instance, this is the space-separated list of the
+	// applications of the principal units deployed to it.
+	ApplicationPlaceholder = "${application}"
+
+	// UnitPlaceholder is replaced with the name of the unit that
+	// a resource belongs to. For a machine instance, this is the
+	// space-separated list of the principal units deployed to it.
+	UnitPlaceholder = "${unit}"
+)
+
+var placeholderPattern = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// TemplateValues holds the values that replace the placeholders in
+// user-defined resource tags. Empty values replace their placeholders
+// with the empty string.
+type TemplateValues struct {
+	Model       string
+	Machine     string
+	Application string
+	Unit        string
+}
+
+// Expand returns a copy of the given tags, with the placeholders in
+// their values replaced. Placeholders with no value are removed, and
+// tags whose values are then empty are omitted.
+func (v TemplateValues) Expand(tags map[string]string) map[string]string {
+	values := map[string]string{
+		ModelPlaceholder:       v.Model,
+		MachinePlaceholder:     v.Machine,
+		ApplicationPlaceholder: v.Application,
+		UnitPlaceholder:        v.Unit,
+	}
+	result := make(map[string]string, len(tags))
+	for key, value := range tags {
+		if !placeholderPattern.MatchString(value) {
+			result[key] = value
+			continue
+		}
+		value = placeholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			return values[placeholder]
+		})
+		if value != "" {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// ValidateTemplates returns an error if the values of any of the given
+// tags hold placeholders other than those known to Juju.
+func ValidateTemplates(tags map[string]string) error {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, placeholder := range placeholderPattern.FindAllString(tags[key], -1) {
+			switch placeholder {
+			case ModelPlaceholder, MachinePlaceholder, ApplicationPlaceholder, UnitPlaceholder:
+			default:
+				return errors.NotValidf("placeholder %q in tag %q", placeholder, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Templated returns a ResourceTagger whose resource tags are those of
+// the given tagger, with the placeholders in their values replaced
+// with the given values.
+func Templated(tagger ResourceTagger, values TemplateValues) ResourceTagger {
+	return templatedTagger{tagger, values}
+}
+
+type templatedTagger struct {
+	tagger ResourceTagger
+	values TemplateValues
+}
+
+// ResourceTags is part of the ResourceTagger interface.
+func (t templatedTagger) ResourceTags() (map[string]string, bool) {
+	tags, ok := t.tagger.ResourceTags()
+	if !ok {
+		return nil, false
+	}
+	return t.values.Expand(tags), true
+}
+
+// StorageTemplateValues returns the TemplateValues for a storage
+// resource in the named model, whose storage is owned by the unit
+// or application with the given name.
+func StorageTemplateValues(model, owner string) TemplateValues {
+	values := TemplateValues{Model: model}
+	switch {
+	case names.IsValidUnit(owner):
+		values.Unit = owner
+		values.Application, _ = names.UnitApplication(owner)
+	case names.IsValidApplication(owner):
+		values.Application = owner
+	}
+	return values
+}
+
+// Unset returns, in sorted order, the names in removed and the names
+// of the tagger's resource tags that are not set in the given tags.
+// These are the tags to remove from a resource when retagging it with
+// the given tags, after the names in removed have been removed from
+// the tagger's resource tags.
+func Unset(tags map[string]string, tagger ResourceTagger, removed []string) []string {
+	unset := set.NewStrings(removed...)
+	if resourceTags, ok := tagger.ResourceTags(); ok {
+		for key := range resourceTags {
+			unset.Add(key)
+		}
+	}
+	for key := range tags {
+		unset.Remove(key)
+	}
+	return unset.SortedValues()
+}
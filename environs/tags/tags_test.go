@@ -61,6 +61,94 @@ func (*tagsSuite) TestResourceTagsResourceTaggers(c *gc.C) {
 	})
 }
 
+func (*tagsSuite) TestResourceTagsTemplated(c *gc.C) {
+	tagger := resourceTagger(func() (map[string]string, bool) {
+		return map[string]string{
+			"owner":  "${unit}",
+			"cost":   "${model}/${application}",
+			"host":   "machine-${machine}",
+			"static": "value",
+		}, true
+	})
+	testResourceTags(c, testing.ControllerTag, testing.ModelTag, []tags.ResourceTagger{
+		tags.Templated(tagger, tags.TemplateValues{
+			Model:       "prod",
+			Machine:     "0",
+			Application: "mysql",
+			Unit:        "mysql/0",
+		}),
+	}, map[string]string{
+		"juju-model-uuid":      testing.ModelTag.Id(),
+		"juju-controller-uuid": testing.ControllerTag.Id(),
+		"owner":                "mysql/0",
+		"cost":                 "prod/mysql",
+		"host":                 "machine-0",
+		"static":               "value",
+	})
+}
+
+func (*tagsSuite) TestResourceTagsUnexpandedTemplates(c *gc.C) {
+	tagger := resourceTagger(func() (map[string]string, bool) {
+		return map[string]string{
+			"owner":  "${unit}",
+			"cost":   "${model}/${application}",
+			"static": "value",
+		}, true
+	})
+	testResourceTags(c, testing.ControllerTag, testing.ModelTag, []tags.ResourceTagger{
+		tags.Templated(tagger, tags.TemplateValues{Model: "prod"}),
+	}, map[string]string{
+		"juju-model-uuid":      testing.ModelTag.Id(),
+		"juju-controller-uuid": testing.ControllerTag.Id(),
+		"cost":                 "prod/",
+		"static":               "value",
+	})
+}
+
+func (*tagsSuite) TestValidateTemplates(c *gc.C) {
+	err := tags.ValidateTemplates(map[string]string{
+		"a": "${model}-${machine}",
+		"b": "${application}/${unit}",
+		"c": "$notaplaceholder",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = tags.ValidateTemplates(map[string]string{
+		"a": "${model}",
+		"b": "${region}",
+	})
+	c.Assert(err, gc.ErrorMatches, `placeholder "\$\{region\}" in tag "b" not valid`)
+}
+
+func (*tagsSuite) TestStorageTemplateValues(c *gc.C) {
+	c.Assert(tags.StorageTemplateValues("prod", "mysql/0"), jc.DeepEquals, tags.TemplateValues{
+		Model:       "prod",
+		Application: "mysql",
+		Unit:        "mysql/0",
+	})
+	c.Assert(tags.StorageTemplateValues("prod", "mysql"), jc.DeepEquals, tags.TemplateValues{
+		Model:       "prod",
+		Application: "mysql",
+	})
+	c.Assert(tags.StorageTemplateValues("prod", ""), jc.DeepEquals, tags.TemplateValues{
+		Model: "prod",
+	})
+}
+
+func (*tagsSuite) TestUnset(c *gc.C) {
+	tagger := resourceTagger(func() (map[string]string, bool) {
+		return map[string]string{
+			"owner": "${unit}",
+			"cost":  "${model}",
+		}, true
+	})
+	unset := tags.Unset(map[string]string{
+		"juju-model-uuid": testing.ModelTag.Id(),
+		"cost":            "prod",
+	}, tagger, []string{"old", "cost"})
+	c.Assert(unset, jc.DeepEquals, []string{"old", "owner"})
+}
+
 func testResourceTags(c *gc.C, controller names.ControllerTag, model names.ModelTag, taggers []tags.ResourceTagger, expectTags map[string]string) {
 	tags := tags.ResourceTags(model, controller, taggers...)
 	c.Assert(tags, jc.DeepEquals, expectTags)
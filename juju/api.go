@@ -5,6 +5,7 @@ package juju
 
 import (
 	"net"
+	"os"
 	"reflect"
 
 	"github.com/juju/errors"
@@ -12,6 +13,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/network"
 )
@@ -117,6 +119,9 @@ func NewAPIConnection(args NewAPIConnectionParams) (_ api.Connection, err error)
 	if host := st.PublicDNSName(); host != "" {
 		params.PublicDNSName = &host
 	}
+	if caCert := st.ControllerCACert(); caCert != "" {
+		params.CACert = &caCert
+	}
 	err = updateControllerDetailsFromLogin(args.Store, args.ControllerName, controller, params)
 	if err != nil {
 		logger.Errorf("cannot cache API addresses: %v", err)
@@ -135,7 +140,22 @@ func NewAPIConnection(args NewAPIConnectionParams) (_ api.Connection, err error)
 				accountDetails.LastKnownAccess = st.ControllerAccess()
 			}
 		}
-		if ok && !user.IsLocal() && apiInfo.Tag == nil {
+		if ok && apiInfo.Token != "" {
+			// We used an API token to login; save the username
+			// of the token's owner unless credentials for
+			// another user are already stored.
+			if accountDetails == nil {
+				accountDetails = &jujuclient.AccountDetails{
+					User:            user.Id(),
+					LastKnownAccess: st.ControllerAccess(),
+				}
+			} else if accountDetails.User != user.Id() {
+				accountDetails = nil
+			}
+		} else if ok && apiInfo.IDToken != "" {
+			// We used an identity token to login. It is held
+			// in the stored account details, which are kept.
+		} else if ok && !user.IsLocal() && apiInfo.Tag == nil {
 			// We used macaroon auth to login; save the username
 			// that we've logged in as.
 			accountDetails = &jujuclient.AccountDetails{
@@ -178,6 +198,12 @@ func connectionInfo(args NewAPIConnectionParams) (*api.Info, *jujuclient.Control
 		apiInfo.SkipLogin = true
 		return apiInfo, controller, nil
 	}
+	if token := os.Getenv(osenv.JujuTokenEnvKey); token != "" {
+		// An API token identifies its owner, so the stored
+		// account credentials are not used.
+		apiInfo.Token = token
+		return apiInfo, controller, nil
+	}
 	account := args.AccountDetails
 	if account.User != "" {
 		userTag := names.NewUserTag(account.User)
@@ -190,6 +216,10 @@ func connectionInfo(args NewAPIConnectionParams) (*api.Info, *jujuclient.Control
 		// If no password is recorded, we'll attempt to
 		// authenticate using macaroons.
 		apiInfo.Password = account.Password
+	} else if account.IDToken != "" {
+		// The user logged in with OpenID Connect; the
+		// identity token identifies them.
+		apiInfo.IDToken = account.IDToken
 	}
 	return apiInfo, controller, nil
 }
@@ -240,6 +270,10 @@ type UpdateControllerParams struct {
 	// PublicDNSName (when set) holds the public host name of the controller.
 	PublicDNSName *string
 
+	// CACert (when set) holds the controller's CA certificate, which
+	// changes when the controller CA is rotated.
+	CACert *string
+
 	// ControllerMachineCount (when set) is the total number of controller machines in the environment.
 	ControllerMachineCount *int
 
@@ -294,6 +328,9 @@ func updateControllerDetailsFromLogin(
 	if params.PublicDNSName != nil {
 		newDetails.PublicDNSName = *params.PublicDNSName
 	}
+	if params.CACert != nil {
+		newDetails.CACert = *params.CACert
+	}
 	if reflect.DeepEqual(newDetails, details) {
 		// Nothing has changed - no need to update the controller details.
 		return nil
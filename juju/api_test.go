@@ -137,6 +137,69 @@ func (s *NewAPIClientSuite) TestUpdatesLastKnownAccess(c *gc.C) {
 	)
 }
 
+func (s *NewAPIClientSuite) TestWithToken(c *gc.C) {
+	s.PatchEnvironment("JUJU_TOKEN", "Ym9iOmNp.s3cret")
+	store := jujuclient.NewMemStore()
+	err := store.AddController("noconfig", jujuclient.ControllerDetails{
+		ControllerUUID: fakeUUID,
+		CACert:         "certificate",
+		APIEndpoints:   []string{"0.1.2.3:5678"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectState := mockedAPIState(mockedHostPort)
+	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
+		c.Check(apiInfo.Token, gc.Equals, "Ym9iOmNp.s3cret")
+		c.Check(apiInfo.Tag, gc.IsNil)
+		c.Check(apiInfo.Password, gc.Equals, "")
+		return expectState, nil
+	}
+	st, err := juju.NewAPIConnection(juju.NewAPIConnectionParams{
+		Store:          store,
+		ControllerName: "noconfig",
+		AccountDetails: &jujuclient.AccountDetails{},
+		DialOpts:       api.DefaultDialOpts(),
+		OpenAPI:        apiOpen,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st, gc.Equals, expectState)
+
+	// The owner of the token is recorded as the logged in user.
+	account, err := store.AccountDetails("noconfig")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(account.User, gc.Equals, "admin")
+	c.Assert(account.Password, gc.Equals, "")
+}
+
+func (s *NewAPIClientSuite) TestWithIDToken(c *gc.C) {
+	store := newClientStore(c, "noconfig")
+	account := jujuclient.AccountDetails{User: "bob@oidc", IDToken: "id-token"}
+	err := store.UpdateAccount("noconfig", account)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectState := mockedAPIState(mockedHostPort)
+	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
+		c.Check(apiInfo.IDToken, gc.Equals, "id-token")
+		c.Check(apiInfo.Tag, gc.IsNil)
+		c.Check(apiInfo.Password, gc.Equals, "")
+		return expectState, nil
+	}
+	st, err := juju.NewAPIConnection(juju.NewAPIConnectionParams{
+		Store:          store,
+		ControllerName: "noconfig",
+		AccountDetails: &account,
+		DialOpts:       api.DefaultDialOpts(),
+		OpenAPI:        apiOpen,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st, gc.Equals, expectState)
+
+	// The stored identity token is kept for later connections.
+	stored, err := store.AccountDetails("noconfig")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stored.IDToken, gc.Equals, "id-token")
+}
+
 func (s *NewAPIClientSuite) TestUpdatesPublicDNSName(c *gc.C) {
 	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
 		conn := mockedAPIState(noFlags)
@@ -151,6 +214,20 @@ func (s *NewAPIClientSuite) TestUpdatesPublicDNSName(c *gc.C) {
 	c.Assert(store.Controllers["controllername"].PublicDNSName, gc.Equals, "somewhere.invalid")
 }
 
+func (s *NewAPIClientSuite) TestUpdatesCACert(c *gc.C) {
+	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
+		conn := mockedAPIState(noFlags)
+		conn.controllerCACert = "new-certificate"
+		conn.addr = "0.1.2.3:1234"
+		return conn, nil
+	}
+
+	store := newClientStore(c, "controllername")
+	_, err := newAPIConnectionFromNames(c, "controllername", "", store, apiOpen)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(store.Controllers["controllername"].CACert, gc.Equals, "new-certificate")
+}
+
 func (s *NewAPIClientSuite) TestWithInfoNoAddresses(c *gc.C) {
 	store := newClientStore(c, "noconfig")
 	err := store.UpdateController("noconfig", jujuclient.ControllerDetails{
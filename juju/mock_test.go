@@ -20,12 +20,13 @@ type mockAPIState struct {
 	// If non-nil, close is called when the Close method is called.
 	close func(api.Connection) error
 
-	addr          string
-	ipAddr        string
-	apiHostPorts  [][]network.HostPort
-	modelTag      string
-	controllerTag string
-	publicDNSName string
+	addr             string
+	ipAddr           string
+	apiHostPorts     [][]network.HostPort
+	modelTag         string
+	controllerTag    string
+	publicDNSName    string
+	controllerCACert string
 }
 
 type mockedStateFlags int
@@ -91,6 +92,10 @@ func (s *mockAPIState) PublicDNSName() string {
 	return s.publicDNSName
 }
 
+func (s *mockAPIState) ControllerCACert() string {
+	return s.controllerCACert
+}
+
 func (s *mockAPIState) APIHostPorts() [][]network.HostPort {
 	return s.apiHostPorts
 }
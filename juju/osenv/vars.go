@@ -22,6 +22,10 @@ const (
 	JujuLoggingConfigEnvKey = "JUJU_LOGGING_CONFIG"
 	JujuFeatureFlagEnvKey   = "JUJU_DEV_FEATURE_FLAGS"
 
+	// JujuTokenEnvKey, if set, holds an API token used to log in to
+	// the controller in place of the stored account credentials.
+	JujuTokenEnvKey = "JUJU_TOKEN"
+
 	// JujuStartupLoggingConfigEnvKey if set is used to configure the initial
 	// logging before the command objects are even created to allow debugging
 	// of the command creation and initialisation process.
@@ -498,6 +498,7 @@ func newState(controllerUUID string, environ environs.Environ, mongoInfo *mongo.
 		ControllerModelTag: modelTag,
 		MongoSession:       session,
 		NewPolicy:          newPolicyFunc,
+		SecretsKey:         testing.SecretsKey,
 	}
 	st, err := state.Open(args)
 	if errors.IsUnauthorized(errors.Cause(err)) {
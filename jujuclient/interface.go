@@ -82,6 +82,11 @@ type AccountDetails struct {
 	// Password is the password for the account.
 	Password string `yaml:"password,omitempty"`
 
+	// IDToken holds the OpenID Connect identity token the user
+	// logged in with, if any. It is used instead of a password
+	// until it expires.
+	IDToken string `yaml:"id-token,omitempty"`
+
 	// LastKnownAccess is the last known access level for the account.
 	LastKnownAccess string `yaml:"last-known-access,omitempty"`
 }
@@ -5,6 +5,7 @@ package migration
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/version"
@@ -12,6 +13,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/constraints"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/state"
@@ -34,6 +36,7 @@ type PrecheckBackend interface {
 	ControllerBackend() (PrecheckBackend, error)
 	CloudCredential(tag names.CloudCredentialTag) (state.Credential, error)
 	ListPendingResources(string) ([]resource.Resource, error)
+	ModelConstraints() (constraints.Value, error)
 }
 
 // Pool defines the interface to a StatePool used by the migration
@@ -63,6 +66,7 @@ type PrecheckMachine interface {
 	AgentPresence() (bool, error)
 	InstanceStatus() (status.StatusInfo, error)
 	ShouldRebootOrShutdown() (state.RebootAction, error)
+	Constraints() (constraints.Value, error)
 }
 
 // PrecheckApplication describes the state interface for an
@@ -73,6 +77,8 @@ type PrecheckApplication interface {
 	CharmURL() (*charm.URL, bool)
 	AllUnits() ([]PrecheckUnit, error)
 	MinUnits() int
+	SecretNames() ([]string, error)
+	Constraints() (constraints.Value, error)
 }
 
 // PrecheckUnit describes state interface for a unit needed by
@@ -161,6 +167,11 @@ func checkModel(backend PrecheckBackend) error {
 			return errors.New("model has revoked credentials")
 		}
 	}
+	if cons, err := backend.ModelConstraints(); err != nil {
+		return errors.Annotate(err, "retrieving model constraints")
+	} else if name := unmigratableConstraint(cons); name != "" {
+		return errors.Errorf("model has %s constraints, which cannot be migrated", name)
+	}
 	return nil
 }
 
@@ -281,6 +292,12 @@ func checkMachines(backend PrecheckBackend) error {
 			return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
 		}
 
+		if cons, err := machine.Constraints(); err != nil {
+			return errors.Annotatef(err, "retrieving machine %s constraints", machine.Id())
+		} else if name := unmigratableConstraint(cons); name != "" {
+			return errors.Errorf("machine %s has %s constraints, which cannot be migrated", machine.Id(), name)
+		}
+
 		if statusInfo, err := machine.InstanceStatus(); err != nil {
 			return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
 		} else if statusInfo.Status != status.Running {
@@ -322,6 +339,21 @@ func checkApplications(backend PrecheckBackend) (map[string][]PrecheckUnit, erro
 		if app.Life() != state.Alive {
 			return nil, errors.Errorf("application %s is %s", app.Name(), app.Life())
 		}
+		if cons, err := app.Constraints(); err != nil {
+			return nil, errors.Annotatef(err, "retrieving constraints for %s", app.Name())
+		} else if name := unmigratableConstraint(cons); name != "" {
+			return nil, errors.Errorf("application %s has %s constraints, which cannot be migrated", app.Name(), name)
+		}
+		// Secrets are encrypted with a key held by the source
+		// controller, and are not exported.
+		secrets, err := app.SecretNames()
+		if err != nil {
+			return nil, errors.Annotatef(err, "retrieving secrets for %s", app.Name())
+		}
+		if len(secrets) > 0 {
+			return nil, errors.Errorf("application %s has secrets, which cannot be migrated: %s",
+				app.Name(), strings.Join(secrets, ", "))
+		}
 		units, err := app.AllUnits()
 		if err != nil {
 			return nil, errors.Annotatef(err, "retrieving units for %s", app.Name())
@@ -395,6 +427,21 @@ type agentToolsGetter interface {
 	AgentTools() (*tools.Tools, error)
 }
 
+// unmigratableConstraint returns the name of a constraint in cons
+// that the model description format cannot yet represent, or the
+// empty string if all of them can be migrated.
+func unmigratableConstraint(cons constraints.Value) string {
+	switch {
+	case cons.HasZones():
+		return constraints.Zones
+	case cons.HasInterruptible():
+		return constraints.Interruptible
+	case cons.HasMaxPrice():
+		return constraints.MaxPrice
+	}
+	return ""
+}
+
 func newStatusError(format, id string, s status.Status) error {
 	msg := fmt.Sprintf(format, id)
 	if s != status.Empty {
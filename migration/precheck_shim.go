@@ -151,6 +151,19 @@ func (s *precheckAppShim) AllUnits() ([]PrecheckUnit, error) {
 	return out, nil
 }
 
+// SecretNames implements PrecheckApplication.
+func (s *precheckAppShim) SecretNames() ([]string, error) {
+	secrets, err := s.Application.Secrets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	names := make([]string, len(secrets))
+	for i, secret := range secrets {
+		names[i] = secret.Name()
+	}
+	return names, nil
+}
+
 // precheckRelationShim implements PrecheckRelation.
 type precheckRelationShim struct {
 	*state.Relation
@@ -11,6 +11,7 @@ import (
 	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/constraints"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
 	"github.com/juju/juju/resource"
@@ -167,6 +168,70 @@ func (s *SourcePrecheckSuite) TestDyingApplication(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "application foo is dying")
 }
 
+func (s *SourcePrecheckSuite) TestModelZonesConstraint(c *gc.C) {
+	backend := newHappyBackend()
+	backend.modelConstraints = constraints.MustParse("zones=az1")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "model has zones constraints, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestMachineZonesConstraint(c *gc.C) {
+	backend := newHappyBackend()
+	backend.machines = []migration.PrecheckMachine{
+		&fakeMachine{id: "0", constraints: constraints.MustParse("zones=az1,az2")},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "machine 0 has zones constraints, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestApplicationZonesConstraint(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:        "foo",
+				constraints: constraints.MustParse("zones=az1"),
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has zones constraints, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestMachineInterruptibleConstraint(c *gc.C) {
+	backend := newHappyBackend()
+	backend.machines = []migration.PrecheckMachine{
+		&fakeMachine{id: "0", constraints: constraints.MustParse("interruptible=true")},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "machine 0 has interruptible constraints, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestApplicationMaxPriceConstraint(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:        "foo",
+				constraints: constraints.MustParse("max-price=0.05"),
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has max-price constraints, which cannot be migrated")
+}
+
+func (s *SourcePrecheckSuite) TestApplicationWithSecrets(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name:    "foo",
+				secrets: []string{"root-password", "salt"},
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "application foo has secrets, which cannot be migrated: root-password, salt")
+}
+
 func (s *SourcePrecheckSuite) TestWithPendingMinUnits(c *gc.C) {
 	backend := &fakeBackend{
 		apps: []migration.PrecheckApplication{
@@ -739,9 +804,15 @@ type fakeBackend struct {
 	pendingResources    []resource.Resource
 	pendingResourcesErr error
 
+	modelConstraints constraints.Value
+
 	controllerBackend *fakeBackend
 }
 
+func (b *fakeBackend) ModelConstraints() (constraints.Value, error) {
+	return b.modelConstraints, nil
+}
+
 func (b *fakeBackend) Model() (migration.PrecheckModel, error) {
 	return &b.model, nil
 }
@@ -858,6 +929,11 @@ type fakeMachine struct {
 	instanceStatus status.Status
 	lost           bool
 	rebootAction   state.RebootAction
+	constraints    constraints.Value
+}
+
+func (m *fakeMachine) Constraints() (constraints.Value, error) {
+	return m.constraints, nil
 }
 
 func (m *fakeMachine) Id() string {
@@ -910,11 +986,17 @@ func (m *fakeMachine) ShouldRebootOrShutdown() (state.RebootAction, error) {
 }
 
 type fakeApp struct {
-	name     string
-	life     state.Life
-	charmURL string
-	units    []migration.PrecheckUnit
-	minunits int
+	name        string
+	life        state.Life
+	charmURL    string
+	units       []migration.PrecheckUnit
+	minunits    int
+	secrets     []string
+	constraints constraints.Value
+}
+
+func (a *fakeApp) Constraints() (constraints.Value, error) {
+	return a.constraints, nil
 }
 
 func (a *fakeApp) Name() string {
@@ -941,6 +1023,10 @@ func (a *fakeApp) MinUnits() int {
 	return a.minunits
 }
 
+func (a *fakeApp) SecretNames() ([]string, error) {
+	return a.secrets, nil
+}
+
 type fakeUnit struct {
 	name        string
 	version     version.Binary
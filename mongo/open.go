@@ -14,9 +14,10 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
-	"github.com/juju/utils/cert"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/cert"
 )
 
 // SocketTimeout should be long enough that even a slow mongo server
@@ -125,12 +126,16 @@ func DialInfo(info Info, opts DialOpts) (*mgo.DialInfo, error) {
 		if len(info.CACert) == 0 {
 			return nil, stderrors.New("missing CA certificate")
 		}
-		xcert, err := cert.ParseCert(info.CACert)
+		// The CA certificate may be a bundle while the
+		// controller CA is being rotated; trust all of it.
+		xcerts, err := cert.ParseCerts(info.CACert)
 		if err != nil {
 			return nil, fmt.Errorf("cannot parse CA certificate: %v", err)
 		}
 		pool := x509.NewCertPool()
-		pool.AddCert(xcert)
+		for _, xcert := range xcerts {
+			pool.AddCert(xcert)
+		}
 
 		tlsConfig = utils.SecureTLSConfig()
 		tlsConfig.RootCAs = pool
@@ -342,9 +342,16 @@ func SelectControllerAddress(addresses []Address, machineLocal bool) (Address, b
 // are no suitable addresses, then ok is false (and an empty address is
 // returned). If a suitable address is then ok is true.
 func SelectPublicAddress(addresses []Address) (Address, bool) {
+	return SelectPublicAddressPreferring(addresses, false)
+}
+
+// SelectPublicAddressPreferring behaves like SelectPublicAddress, except
+// that when preferIPv6 is true IPv6 addresses are ranked ahead of IPv4
+// addresses of the same scope.
+func SelectPublicAddressPreferring(addresses []Address, preferIPv6 bool) (Address, bool) {
 	index := bestAddressIndex(len(addresses), func(i int) Address {
 		return addresses[i]
-	}, publicMatch)
+	}, familyMatcher(publicMatch, preferIPv6))
 	if index < 0 {
 		return Address{}, false
 	}
@@ -369,9 +376,16 @@ func SelectPublicHostPort(hps []HostPort) string {
 // no suitable addresses, then ok is false (and an empty address is
 // returned). If a suitable address was found then ok is true.
 func SelectInternalAddress(addresses []Address, machineLocal bool) (Address, bool) {
+	return SelectInternalAddressPreferring(addresses, machineLocal, false)
+}
+
+// SelectInternalAddressPreferring behaves like SelectInternalAddress,
+// except that when preferIPv6 is true IPv6 addresses are ranked ahead
+// of IPv4 addresses of the same scope.
+func SelectInternalAddressPreferring(addresses []Address, machineLocal, preferIPv6 bool) (Address, bool) {
 	index := bestAddressIndex(len(addresses), func(i int) Address {
 		return addresses[i]
-	}, internalAddressMatcher(machineLocal))
+	}, familyMatcher(internalAddressMatcher(machineLocal), preferIPv6))
 	if index < 0 {
 		return Address{}, false
 	}
@@ -501,6 +515,40 @@ func cloudOrMachineLocalMatch(addr Address) scopeMatch {
 	return cloudLocalMatch(addr)
 }
 
+// familyMatcher returns matchFunc unchanged when preferIPv6 is false.
+// Otherwise it returns a matcher which swaps the ranking of IPv4 and
+// IPv6 addresses within each scope, so that IPv6 addresses are chosen
+// first. Hostnames keep their position after the preferred family.
+func familyMatcher(matchFunc scopeMatchFunc, preferIPv6 bool) scopeMatchFunc {
+	if !preferIPv6 {
+		return matchFunc
+	}
+	return func(addr Address) scopeMatch {
+		match := matchFunc(addr)
+		switch addr.Type {
+		case IPv6Address:
+			switch match {
+			case exactScope:
+				return exactScopeIPv4
+			case firstFallbackScope:
+				return firstFallbackScopeIPv4
+			case secondFallbackScope:
+				return secondFallbackScopeIPv4
+			}
+		case IPv4Address:
+			switch match {
+			case exactScopeIPv4:
+				return exactScope
+			case firstFallbackScopeIPv4:
+				return firstFallbackScope
+			case secondFallbackScopeIPv4:
+				return secondFallbackScope
+			}
+		}
+		return match
+	}
+}
+
 type scopeMatch int
 
 const (
@@ -384,6 +384,49 @@ func (s *AddressSuite) TestSelectPublicAddress(c *gc.C) {
 	}
 }
 
+var selectPublicPreferringIPv6Tests = []selectTest{{
+	"a public IPv6 address is preferred to a public IPv4 address",
+	[]network.Address{
+		network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+		network.NewScopedAddress("2001:db8::1", network.ScopePublic),
+	},
+	1,
+}, {
+	"a public IPv4 address is still preferred to a cloud local IPv6 address",
+	[]network.Address{
+		network.NewScopedAddress("fc00::1", network.ScopeCloudLocal),
+		network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+	},
+	1,
+}, {
+	"an IPv4 address is selected when no IPv6 address is available",
+	[]network.Address{
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+		network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+	},
+	1,
+}}
+
+func (s *AddressSuite) TestSelectPublicAddressPreferringIPv6(c *gc.C) {
+	for i, t := range selectPublicPreferringIPv6Tests {
+		c.Logf("test %d: %s", i, t.about)
+		expectAddr, expectOK := t.expected()
+		actualAddr, actualOK := network.SelectPublicAddressPreferring(t.addresses, true)
+		c.Check(actualOK, gc.Equals, expectOK)
+		c.Check(actualAddr, gc.Equals, expectAddr)
+	}
+}
+
+func (s *AddressSuite) TestSelectPublicAddressPreferringIPv4(c *gc.C) {
+	for i, t := range selectPublicTests {
+		c.Logf("test %d: %s", i, t.about)
+		expectAddr, expectOK := t.expected()
+		actualAddr, actualOK := network.SelectPublicAddressPreferring(t.addresses, false)
+		c.Check(actualOK, gc.Equals, expectOK)
+		c.Check(actualAddr, gc.Equals, expectAddr)
+	}
+}
+
 var selectInternalTests = []selectTest{{
 	"no addresses gives empty string result",
 	[]network.Address{},
@@ -450,6 +493,32 @@ func (s *AddressSuite) TestSelectInternalAddress(c *gc.C) {
 	}
 }
 
+var selectInternalPreferringIPv6Tests = []selectTest{{
+	"a cloud local IPv6 address is preferred to a cloud local IPv4 address",
+	[]network.Address{
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+		network.NewScopedAddress("fc00::1", network.ScopeCloudLocal),
+	},
+	1,
+}, {
+	"a cloud local IPv4 address is preferred to a public IPv6 address",
+	[]network.Address{
+		network.NewScopedAddress("2001:db8::1", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.1", network.ScopeCloudLocal),
+	},
+	1,
+}}
+
+func (s *AddressSuite) TestSelectInternalAddressPreferringIPv6(c *gc.C) {
+	for i, t := range selectInternalPreferringIPv6Tests {
+		c.Logf("test %d: %s", i, t.about)
+		expectAddr, expectOK := t.expected()
+		actualAddr, actualOK := network.SelectInternalAddressPreferring(t.addresses, false, true)
+		c.Check(actualOK, gc.Equals, expectOK)
+		c.Check(actualAddr, gc.Equals, expectAddr)
+	}
+}
+
 var selectInternalMachineTests = []selectTest{{
 	"first cloud local IPv4 address is selected",
 	[]network.Address{
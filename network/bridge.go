@@ -4,6 +4,7 @@
 package network
 
 import (
+	"net"
 	"time"
 
 	"github.com/juju/errors"
@@ -85,6 +86,10 @@ type netplanBridger struct {
 	Clock     clock.Clock
 	Directory string
 	Timeout   time.Duration
+
+	// CheckAddresses holds the host:port addresses of which at least
+	// one must still be reachable once the bridges are activated.
+	CheckAddresses []string
 }
 
 var _ Bridger = (*netplanBridger)(nil)
@@ -95,10 +100,16 @@ func (b *netplanBridger) Bridge(devices []DeviceToBridge, reconfigureDelay int)
 		npDevices[i] = netplan.DeviceToBridge(device)
 	}
 	params := netplan.ActivationParams{
-		Clock:     clock.WallClock,
-		Directory: b.Directory,
-		Devices:   npDevices,
-		Timeout:   b.Timeout,
+		Clock:            clock.WallClock,
+		Directory:        b.Directory,
+		Devices:          npDevices,
+		Timeout:          b.Timeout,
+		ReconfigureDelay: reconfigureDelay,
+	}
+	if len(b.CheckAddresses) > 0 {
+		params.CheckConnectivity = func() error {
+			return checkReachable(b.CheckAddresses, connectivityCheckTimeout)
+		}
 	}
 
 	result, err := netplan.BridgeAndActivate(params)
@@ -121,16 +132,39 @@ func (b *netplanBridger) Bridge(devices []DeviceToBridge, reconfigureDelay int)
 	return nil
 }
 
-func newNetplanBridger(clock clock.Clock, timeout time.Duration, directory string) Bridger {
+func newNetplanBridger(clock clock.Clock, timeout time.Duration, directory string, checkAddresses []string) Bridger {
 	return &netplanBridger{
-		Clock:     clock,
-		Directory: directory,
-		Timeout:   timeout,
+		Clock:          clock,
+		Directory:      directory,
+		Timeout:        timeout,
+		CheckAddresses: checkAddresses,
 	}
 }
 
 // DefaultNetplanBridger returns a Bridger instance that can parse a set
 // of netplan yaml files to transform existing devices into bridged devices.
-func DefaultNetplanBridger(timeout time.Duration, directory string) (Bridger, error) {
-	return newNetplanBridger(clock.WallClock, timeout, directory), nil
+// If any checkAddresses are supplied, the previous configuration is
+// restored when none of them can be reached after bridging.
+func DefaultNetplanBridger(timeout time.Duration, directory string, checkAddresses ...string) (Bridger, error) {
+	return newNetplanBridger(clock.WallClock, timeout, directory, checkAddresses), nil
+}
+
+// connectivityCheckTimeout is how long checkReachable waits for each
+// address to accept a connection.
+const connectivityCheckTimeout = 10 * time.Second
+
+// checkReachable returns nil if a TCP connection can be established
+// to at least one of the given host:port addresses.
+func checkReachable(addresses []string, timeout time.Duration) error {
+	var lastErr error
+	for _, addr := range addresses {
+		conn, err := net.DialTimeout("tcp", addr, timeout)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		logger.Debugf("cannot reach %q: %v", addr, err)
+		lastErr = err
+	}
+	return errors.Annotatef(lastErr, "none of %v reachable", addresses)
 }
@@ -4,10 +4,12 @@
 package network_test
 
 import (
+	"net"
 	"runtime"
 	"time"
 
 	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/clock"
 	gc "gopkg.in/check.v1"
 
@@ -97,3 +99,19 @@ func (*BridgeSuite) TestENIBridgerWithDryRun(c *gc.C) {
 	err := bridger.Bridge(devices, 0)
 	c.Assert(err, gc.IsNil)
 }
+
+func (*BridgeSuite) TestCheckReachable(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+	unreachable, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	unreachableAddr := unreachable.Addr().String()
+	unreachable.Close()
+
+	err = network.CheckReachable([]string{unreachableAddr, listener.Addr().String()}, time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = network.CheckReachable([]string{unreachableAddr}, time.Second)
+	c.Assert(err, gc.ErrorMatches, `none of \[.*\] reachable: .*`)
+}
@@ -11,4 +11,5 @@ var (
 	SimulatedOS                    = &simulatedOS
 	LaunchIpRouteShow              = &launchIpRouteShow
 	LaunchIpRouteShowReal          = launchIpRouteShowReal
+	CheckReachable                 = checkReachable
 )
@@ -37,6 +37,9 @@ func ParseFanConfig(line string) (config FanConfig, err error) {
 		if _, config[i].Overlay, err = net.ParseCIDR(strings.TrimSpace(cidrs[1])); err != nil {
 			return nil, errors.Annotatef(err, "invalid address in FAN config")
 		}
+		if config[i].Underlay.IP.To4() == nil || config[i].Overlay.IP.To4() == nil {
+			return nil, fmt.Errorf("invalid FAN config, only IPv4 networks are supported: %s", line)
+		}
 		underlaySize, _ := config[i].Underlay.Mask.Size()
 		overlaySize, _ := config[i].Overlay.Mask.Size()
 		if underlaySize <= overlaySize {
@@ -63,6 +66,10 @@ func CalculateOverlaySegment(underlayCIDR string, fan FanConfigEntry) (*net.IPNe
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if underlayNet.IP.To4() == nil {
+		// FAN networking is only defined for IPv4 underlays.
+		return nil, nil
+	}
 	subnetSize, _ := underlayNet.Mask.Size()
 	underlaySize, _ := fan.Underlay.Mask.Size()
 	if underlaySize <= subnetSize && fan.Underlay.Contains(underlayNet.IP) {
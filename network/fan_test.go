@@ -68,6 +68,11 @@ func (*FanConfigSuite) TestFanConfigErrors(c *gc.C) {
 	config, err = network.ParseFanConfig("1.0.0.0/8=2.0.0.0/16")
 	c.Check(config, gc.IsNil)
 	c.Check(err, gc.ErrorMatches, "invalid FAN config, underlay mask must be larger than overlay:.*")
+
+	// IPv6 networks.
+	config, err = network.ParseFanConfig("fd00::/64=253.0.0.0/8")
+	c.Check(config, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "invalid FAN config, only IPv4 networks are supported:.*")
 }
 
 func (*FanConfigSuite) TestCalculateOverlaySegment(c *gc.C) {
@@ -85,6 +90,11 @@ func (*FanConfigSuite) TestCalculateOverlaySegment(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(net, gc.IsNil)
 
+	// IPv6 underlay
+	net, err = network.CalculateOverlaySegment("fd00::/64", config[0])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(net, gc.IsNil)
+
 	// Garbage in underlay
 	net, err = network.CalculateOverlaySegment("something", config[0])
 	c.Check(net, gc.IsNil)
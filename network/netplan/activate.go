@@ -21,8 +21,29 @@ type ActivationParams struct {
 	RunPrefix string
 	Directory string
 	Timeout   time.Duration
+
+	// ReconfigureDelay is how many seconds to wait after applying the
+	// new configuration, so that devices such as bonds can settle
+	// before they are used. The delay is never less than
+	// minReconfigureDelay.
+	ReconfigureDelay int
+
+	// CheckConnectivity, if set, is called after the new configuration
+	// has been applied. If it returns an error the previous configuration
+	// is restored and applied again, so that the machine does not lose
+	// its connection to the controller.
+	CheckConnectivity func() error
 }
 
+// minReconfigureDelay is the least number of seconds waited after
+// applying a new configuration.
+const minReconfigureDelay = 10
+
+// RolledBackCode is the code of the ActivationResult returned when
+// the new configuration was applied but then rolled back, because the
+// machine lost connectivity with it.
+const RolledBackCode = 1
+
 // ActivationResult captures the result of actively bridging the
 // interfaces using ifup/ifdown.
 type ActivationResult struct {
@@ -49,7 +70,17 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 	for _, device := range params.Devices {
 		var deviceId string
 		err := errors.NotFoundf("No such device - name %q MAC %q", device.DeviceName, device.MACAddress)
-		if device.MACAddress != "" {
+		// Bonds and VLANs share the MAC address of an underlying
+		// ethernet device, so they are looked for by name first,
+		// to bridge the device that was asked for rather than the
+		// ethernet device beneath it.
+		if device.DeviceName != "" {
+			deviceId, err = netplan.FindBondByName(device.DeviceName)
+		}
+		if err != nil && device.DeviceName != "" {
+			deviceId, err = netplan.FindVLANByName(device.DeviceName)
+		}
+		if err != nil && device.MACAddress != "" {
 			deviceId, err = netplan.FindEthernetByMAC(device.MACAddress)
 		}
 		if err != nil && device.DeviceName != "" {
@@ -58,7 +89,7 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 		if err != nil {
 			return nil, err
 		}
-		err = netplan.BridgeEthernetById(deviceId, device.BridgeName)
+		err = netplan.BridgeDeviceById(deviceId, device.BridgeName)
 		if err != nil {
 			return nil, err
 		}
@@ -77,7 +108,11 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 	environ := os.Environ()
 	// TODO(wpk) 2017-06-21 Is there a way to verify that apply is finished?
 	// https://bugs.launchpad.net/netplan/+bug/1701436
-	command := fmt.Sprintf("%snetplan generate && netplan apply && sleep 10", params.RunPrefix)
+	delay := params.ReconfigureDelay
+	if delay < minReconfigureDelay {
+		delay = minReconfigureDelay
+	}
+	command := fmt.Sprintf("%snetplan generate && netplan apply && sleep %d", params.RunPrefix, delay)
 
 	result, err := scriptrunner.RunCommand(command, environ, params.Clock, params.Timeout)
 
@@ -97,5 +132,21 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 		netplan.Rollback()
 		return &activationResult, errors.Errorf("bridge activation error code %d", result.Code)
 	}
+	if params.CheckConnectivity != nil {
+		if checkErr := params.CheckConnectivity(); checkErr != nil {
+			logger.Errorf("connectivity lost after bridge activation, restoring previous configuration: %v", checkErr)
+			netplan.Rollback()
+			result, err := scriptrunner.RunCommand(command, environ, params.Clock, params.Timeout)
+			if err == nil && result.Code != 0 {
+				err = errors.Errorf("exit code %d", result.Code)
+			}
+			if err != nil {
+				logger.Errorf("cannot re-apply previous netplan configuration: %v", err)
+			}
+			activationResult.Code = RolledBackCode
+			activationResult.Stderr = append(activationResult.Stderr, []byte(checkErr.Error())...)
+			return &activationResult, errors.Annotate(checkErr, "bridge activation rolled back")
+		}
+	}
 	return nil, nil
 }
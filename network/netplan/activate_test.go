@@ -5,6 +5,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/clock"
@@ -156,3 +157,113 @@ func (s *ActivateSuite) TestActivateTimeout(c *gc.C) {
 	c.Assert(result, gc.NotNil)
 	c.Check(err, gc.ErrorMatches, "bridge activation error: command cancelled")
 }
+
+func (s *ActivateSuite) TestActivateConnectivityLost(c *gc.C) {
+	tempDir := c.MkDir()
+	checks := 0
+	params := netplan.ActivationParams{
+		Devices: []netplan.DeviceToBridge{
+			{
+				DeviceName: "eno1",
+				MACAddress: "00:11:22:33:44:55",
+				BridgeName: "br-eno1",
+			},
+		},
+		Directory: tempDir,
+		RunPrefix: "exit 0 &&",
+		CheckConnectivity: func() error {
+			checks++
+			return errors.New("controller unreachable")
+		},
+	}
+	files := []string{"00.yaml", "01.yaml"}
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		var err error
+		contents[i], err = ioutil.ReadFile(path.Join("testdata/TestReadWriteBackup", file))
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(path.Join(tempDir, file), contents[i], 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	result, err := netplan.BridgeAndActivate(params)
+	c.Assert(result, gc.NotNil)
+	c.Check(result.Code, gc.Equals, netplan.RolledBackCode)
+	c.Check(string(result.Stderr), jc.Contains, "controller unreachable")
+	c.Check(err, gc.ErrorMatches, "bridge activation rolled back: controller unreachable")
+	c.Check(checks, gc.Equals, 1)
+
+	// old files are in place and unchanged
+	for i, file := range files {
+		content, err := ioutil.ReadFile(path.Join(tempDir, file))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(content), gc.Equals, string(contents[i]))
+	}
+	fileInfos, err := ioutil.ReadDir(tempDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fileInfos, gc.HasLen, len(files))
+}
+
+func (s *ActivateSuite) TestActivateConnectivityKept(c *gc.C) {
+	tempDir := c.MkDir()
+	params := netplan.ActivationParams{
+		Devices: []netplan.DeviceToBridge{
+			{
+				DeviceName: "eno1",
+				MACAddress: "00:11:22:33:44:55",
+				BridgeName: "br-eno1",
+			},
+		},
+		Directory:         tempDir,
+		RunPrefix:         "exit 0 &&",
+		CheckConnectivity: func() error { return nil },
+	}
+	for _, file := range []string{"00.yaml", "01.yaml"} {
+		content, err := ioutil.ReadFile(path.Join("testdata/TestReadWriteBackup", file))
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(path.Join(tempDir, file), content, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	result, err := netplan.BridgeAndActivate(params)
+	c.Check(result, gc.IsNil)
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ActivateSuite) TestActivateBondSharingMAC(c *gc.C) {
+	tempDir := c.MkDir()
+	input := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+  bonds:
+    bond0:
+      interfaces: [eno1]
+      addresses:
+      - 1.2.3.4/24
+`[1:]
+	err := ioutil.WriteFile(path.Join(tempDir, "00.yaml"), []byte(input), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The bond has the MAC address of its member, but it is the
+	// bond that is bridged.
+	params := netplan.ActivationParams{
+		Devices: []netplan.DeviceToBridge{{
+			DeviceName: "bond0",
+			MACAddress: "00:11:22:33:44:55",
+			BridgeName: "br-bond0",
+		}},
+		Directory: tempDir,
+		RunPrefix: "exit 0 &&",
+	}
+	result, err := netplan.BridgeAndActivate(params)
+	c.Check(result, gc.IsNil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	np, err := netplan.ReadDirectory(tempDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(np.Network.Bridges["br-bond0"].Interfaces, jc.DeepEquals, []string{"bond0"})
+	_, ok := np.Network.Bridges["br-eno1"]
+	c.Check(ok, jc.IsFalse)
+}
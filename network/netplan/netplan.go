@@ -53,6 +53,44 @@ type Bridge struct {
 	Interface  `yaml:",inline"`
 }
 
+// BondParameters holds the bonding options of a netplan bond.
+// Scalar options which netplan accepts as either numbers or strings
+// are kept as interface{} so that they are written back unchanged.
+type BondParameters struct {
+	Mode                  string      `yaml:"mode,omitempty"`
+	LACPRate              string      `yaml:"lacp-rate,omitempty"`
+	MIIMonitorInterval    interface{} `yaml:"mii-monitor-interval,omitempty"`
+	MinLinks              int         `yaml:"min-links,omitempty"`
+	TransmitHashPolicy    string      `yaml:"transmit-hash-policy,omitempty"`
+	ADSelect              string      `yaml:"ad-select,omitempty"`
+	AllSlavesActive       bool        `yaml:"all-slaves-active,omitempty"`
+	ARPInterval           interface{} `yaml:"arp-interval,omitempty"`
+	ARPIPTargets          []string    `yaml:"arp-ip-targets,omitempty"`
+	ARPValidate           string      `yaml:"arp-validate,omitempty"`
+	ARPAllTargets         string      `yaml:"arp-all-targets,omitempty"`
+	UpDelay               interface{} `yaml:"up-delay,omitempty"`
+	DownDelay             interface{} `yaml:"down-delay,omitempty"`
+	FailOverMACPolicy     string      `yaml:"fail-over-mac-policy,omitempty"`
+	GratuitousARP         int         `yaml:"gratuitious-arp,omitempty"`
+	PacketsPerSlave       int         `yaml:"packets-per-slave,omitempty"`
+	PrimaryReselectPolicy string      `yaml:"primary-reselect-policy,omitempty"`
+	ResendIGMP            int         `yaml:"resend-igmp,omitempty"`
+	LearnPacketInterval   interface{} `yaml:"learn-packet-interval,omitempty"`
+	Primary               string      `yaml:"primary,omitempty"`
+}
+
+type Bond struct {
+	Interfaces []string       `yaml:"interfaces,omitempty,flow"`
+	Parameters BondParameters `yaml:"parameters,omitempty"`
+	Interface  `yaml:",inline"`
+}
+
+type VLAN struct {
+	Id        int    `yaml:"id"`
+	Link      string `yaml:"link"`
+	Interface `yaml:",inline"`
+}
+
 type Route struct {
 	To     string `yaml:"to,omitempty"`
 	Via    string `yaml:"via,omitempty"`
@@ -65,6 +103,8 @@ type Network struct {
 	Ethernets map[string]Ethernet `yaml:"ethernets,omitempty"`
 	Wifis     map[string]Wifi     `yaml:"wifis,omitempty"`
 	Bridges   map[string]Bridge   `yaml:"bridges,omitempty"`
+	Bonds     map[string]Bond     `yaml:"bonds,omitempty"`
+	VLANs     map[string]VLAN     `yaml:"vlans,omitempty"`
 	Routes    []Route             `yaml:"routes,omitempty"`
 }
 
@@ -76,32 +116,101 @@ type Netplan struct {
 	writtenFile     string
 }
 
-// BridgeDeviceById takes a deviceId and creates a bridge with this device
+// BridgeEthernetById takes a deviceId and creates a bridge with this device
 // using this devices config
 func (np *Netplan) BridgeEthernetById(deviceId string, bridgeName string) (err error) {
 	ethernet, ok := np.Network.Ethernets[deviceId]
 	if !ok {
 		return errors.NotFoundf("Device with id %q for bridge %q", deviceId, bridgeName)
 	}
+	bridged, err := np.checkCanBridge(deviceId, bridgeName)
+	if err != nil || bridged {
+		return err
+	}
+	ethernet.Interface = np.moveToBridge(deviceId, bridgeName, ethernet.Interface)
+	np.Network.Ethernets[deviceId] = ethernet
+	return nil
+}
+
+// BridgeBondById takes the id of a bond and creates a bridge over
+// the bond, moving the bond's IP settings to the bridge.
+func (np *Netplan) BridgeBondById(deviceId string, bridgeName string) (err error) {
+	bond, ok := np.Network.Bonds[deviceId]
+	if !ok {
+		return errors.NotFoundf("Bond with id %q for bridge %q", deviceId, bridgeName)
+	}
+	bridged, err := np.checkCanBridge(deviceId, bridgeName)
+	if err != nil || bridged {
+		return err
+	}
+	bond.Interface = np.moveToBridge(deviceId, bridgeName, bond.Interface)
+	np.Network.Bonds[deviceId] = bond
+	return nil
+}
+
+// BridgeVLANById takes the id of a VLAN and creates a bridge over
+// the VLAN, moving the VLAN's IP settings to the bridge.
+func (np *Netplan) BridgeVLANById(deviceId string, bridgeName string) (err error) {
+	vlan, ok := np.Network.VLANs[deviceId]
+	if !ok {
+		return errors.NotFoundf("VLAN with id %q for bridge %q", deviceId, bridgeName)
+	}
+	bridged, err := np.checkCanBridge(deviceId, bridgeName)
+	if err != nil || bridged {
+		return err
+	}
+	vlan.Interface = np.moveToBridge(deviceId, bridgeName, vlan.Interface)
+	np.Network.VLANs[deviceId] = vlan
+	return nil
+}
+
+// BridgeDeviceById creates a bridge over the ethernet, bond or VLAN
+// with the given id.
+func (np *Netplan) BridgeDeviceById(deviceId string, bridgeName string) (err error) {
+	if _, ok := np.Network.Ethernets[deviceId]; ok {
+		return np.BridgeEthernetById(deviceId, bridgeName)
+	}
+	if _, ok := np.Network.Bonds[deviceId]; ok {
+		return np.BridgeBondById(deviceId, bridgeName)
+	}
+	if _, ok := np.Network.VLANs[deviceId]; ok {
+		return np.BridgeVLANById(deviceId, bridgeName)
+	}
+	return errors.NotFoundf("Device with id %q for bridge %q", deviceId, bridgeName)
+}
+
+// checkCanBridge verifies that deviceId can be bridged on bridgeName. It
+// returns true if the device is already bridged on the requested bridge.
+func (np *Netplan) checkCanBridge(deviceId string, bridgeName string) (bool, error) {
 	for bName, bridge := range np.Network.Bridges {
 		for _, i := range bridge.Interfaces {
 			if i == deviceId {
 				// The device is already properly bridged, we're not doing anything
 				if bridgeName == bName {
-					return nil
+					return true, nil
 				} else {
-					return errors.AlreadyExistsf("Device %q is already bridged in bridge %q instead of %q", deviceId, bName, bridgeName)
+					return false, errors.AlreadyExistsf("Device %q is already bridged in bridge %q instead of %q", deviceId, bName, bridgeName)
 				}
 			}
 		}
 		if bridgeName == bName {
-			return errors.AlreadyExistsf("Cannot bridge device %q on bridge %q - bridge named %q", deviceId, bridgeName, bridgeName)
+			return false, errors.AlreadyExistsf("Cannot bridge device %q on bridge %q - bridge named %q", deviceId, bridgeName, bridgeName)
 		}
 	}
-	// copy aside and clear the IP settings from the original Ethernet device, except for MTU
-	intf := ethernet.Interface
-	ethernet.Interface = Interface{MTU: intf.MTU}
-	// create a bridge
+	for bondName, bond := range np.Network.Bonds {
+		for _, i := range bond.Interfaces {
+			if i == deviceId {
+				return false, errors.Errorf("Cannot bridge device %q on bridge %q - device is a member of bond %q", deviceId, bridgeName, bondName)
+			}
+		}
+	}
+	return false, nil
+}
+
+// moveToBridge creates a bridge named bridgeName over deviceId, using the
+// IP settings from intf. It returns the settings the device should be left
+// with, which are cleared except for MTU.
+func (np *Netplan) moveToBridge(deviceId string, bridgeName string, intf Interface) Interface {
 	if np.Network.Bridges == nil {
 		np.Network.Bridges = make(map[string]Bridge)
 	}
@@ -109,8 +218,7 @@ func (np *Netplan) BridgeEthernetById(deviceId string, bridgeName string) (err e
 		Interfaces: []string{deviceId},
 		Interface:  intf,
 	}
-	np.Network.Ethernets[deviceId] = ethernet
-	return nil
+	return Interface{MTU: intf.MTU}
 }
 
 func Unmarshal(in []byte, out interface{}) (err error) {
@@ -249,6 +357,24 @@ func (np *Netplan) Rollback() (err error) {
 	return nil
 }
 
+// FindBondByName returns the id of the bond with the given name. Bonds
+// in netplan are named by their id.
+func (np *Netplan) FindBondByName(name string) (device string, err error) {
+	if _, ok := np.Network.Bonds[name]; ok {
+		return name, nil
+	}
+	return "", errors.NotFoundf("Bond device with name %q", name)
+}
+
+// FindVLANByName returns the id of the VLAN with the given name. VLANs
+// in netplan are named by their id.
+func (np *Netplan) FindVLANByName(name string) (device string, err error) {
+	if _, ok := np.Network.VLANs[name]; ok {
+		return name, nil
+	}
+	return "", errors.NotFoundf("VLAN device with name %q", name)
+}
+
 func (np *Netplan) FindEthernetByMAC(mac string) (device string, err error) {
 	for id, ethernet := range np.Network.Ethernets {
 		if v, ok := ethernet.Match["macaddress"]; ok && v == mac {
@@ -284,6 +284,150 @@ network:
 	c.Check(err, gc.ErrorMatches, `Device with id "id7" for bridge "juju-bridge" not found`)
 }
 
+func (s *NetplanSuite) TestBridgeBond(c *gc.C) {
+	input := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+    eno2:
+      match:
+        macaddress: "00:11:22:33:44:66"
+  bonds:
+    bond0:
+      interfaces: [eno1, eno2]
+      parameters:
+        mode: 802.3ad
+        lacp-rate: fast
+        mii-monitor-interval: 100
+        transmit-hash-policy: layer3+4
+      addresses:
+      - 1.2.3.4/24
+      gateway4: 1.2.3.5
+      mtu: 9000
+`[1:]
+	expected := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+    eno2:
+      match:
+        macaddress: "00:11:22:33:44:66"
+  bridges:
+    br-bond0:
+      interfaces: [bond0]
+      addresses:
+      - 1.2.3.4/24
+      gateway4: 1.2.3.5
+      mtu: 9000
+  bonds:
+    bond0:
+      interfaces: [eno1, eno2]
+      parameters:
+        mode: 802.3ad
+        lacp-rate: fast
+        mii-monitor-interval: 100
+        transmit-hash-policy: layer3+4
+      mtu: 9000
+`[1:]
+	var np netplan.Netplan
+	err := netplan.Unmarshal([]byte(input), &np)
+	c.Assert(err, jc.ErrorIsNil)
+
+	id, err := np.FindBondByName("bond0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = np.BridgeDeviceById(id, "br-bond0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := netplan.Marshal(np)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), gc.Equals, expected)
+}
+
+func (s *NetplanSuite) TestBridgeBondMember(c *gc.C) {
+	input := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+  bonds:
+    bond0:
+      interfaces: [eno1]
+`[1:]
+	var np netplan.Netplan
+	err := netplan.Unmarshal([]byte(input), &np)
+	c.Assert(err, jc.ErrorIsNil)
+	err = np.BridgeDeviceById("eno1", "br-eno1")
+	c.Check(err, gc.ErrorMatches, `Cannot bridge device "eno1" on bridge "br-eno1" - device is a member of bond "bond0"`)
+}
+
+func (s *NetplanSuite) TestBridgeVLAN(c *gc.C) {
+	input := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      addresses:
+      - 10.0.0.2/24
+  vlans:
+    eno1.100:
+      id: 100
+      link: eno1
+      addresses:
+      - 10.100.0.2/24
+`[1:]
+	expected := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      addresses:
+      - 10.0.0.2/24
+  bridges:
+    br-eno1-100:
+      interfaces: [eno1.100]
+      addresses:
+      - 10.100.0.2/24
+  vlans:
+    eno1.100:
+      id: 100
+      link: eno1
+`[1:]
+	var np netplan.Netplan
+	err := netplan.Unmarshal([]byte(input), &np)
+	c.Assert(err, jc.ErrorIsNil)
+
+	id, err := np.FindVLANByName("eno1.100")
+	c.Assert(err, jc.ErrorIsNil)
+	err = np.BridgeDeviceById(id, "br-eno1-100")
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := netplan.Marshal(np)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), gc.Equals, expected)
+}
+
+func (s *NetplanSuite) TestBridgeDeviceMissing(c *gc.C) {
+	var np netplan.Netplan
+	err := np.BridgeDeviceById("bond7", "br-bond7")
+	c.Check(err, gc.ErrorMatches, `Device with id "bond7" for bridge "br-bond7" not found`)
+	_, err = np.FindBondByName("bond7")
+	c.Check(err, gc.ErrorMatches, `Bond device with name "bond7" not found`)
+	_, err = np.FindVLANByName("eno1.7")
+	c.Check(err, gc.ErrorMatches, `VLAN device with name "eno1.7" not found`)
+}
+
 func (s *NetplanSuite) TestFindEthernetBySetName(c *gc.C) {
 	input := `
 network:
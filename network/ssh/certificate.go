@@ -0,0 +1,231 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ssh
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// DefaultUserCertificateValidity is how long user certificates
+	// are valid for when no explicit validity is requested. It is
+	// kept short so that revoking a user's access to a model stops
+	// them from using SSH shortly after, without needing to
+	// distribute revocation lists to machines.
+	DefaultUserCertificateValidity = 5 * time.Minute
+
+	// MaxUserCertificateValidity is the longest validity that may
+	// be requested for a user certificate.
+	MaxUserCertificateValidity = time.Hour
+
+	// DefaultHostCertificateValidity is how long host certificates
+	// are valid for.
+	DefaultHostCertificateValidity = 365 * 24 * time.Hour
+
+	// TrustedUserCAKeysPath is the path on machines of the file
+	// holding the user CA public key trusted by sshd.
+	TrustedUserCAKeysPath = "/etc/ssh/juju_user_ca.pub"
+
+	// AuthorizedPrincipalsDir is the directory on machines holding,
+	// for each user, the certificate principals sshd accepts when
+	// logging in as that user.
+	AuthorizedPrincipalsDir = "/etc/ssh/juju_principals"
+
+	// clockSkew is subtracted from the start of a certificate's
+	// validity period to allow for machines with slow clocks.
+	clockSkew = time.Minute
+)
+
+// CertificateAuthority signs SSH user and host certificates on behalf
+// of the controller.
+type CertificateAuthority struct {
+	signer ssh.Signer
+	clock  clock.Clock
+	rand   io.Reader
+}
+
+// NewCertificateAuthority returns a CertificateAuthority signing
+// certificates with the given key.
+func NewCertificateAuthority(signer ssh.Signer, clock clock.Clock) (*CertificateAuthority, error) {
+	if signer == nil {
+		return nil, errors.NotValidf("nil signer")
+	}
+	if clock == nil {
+		return nil, errors.NotValidf("nil clock")
+	}
+	return &CertificateAuthority{
+		signer: signer,
+		clock:  clock,
+		rand:   rand.Reader,
+	}, nil
+}
+
+// ParseCertificateAuthority returns a CertificateAuthority signing
+// certificates with the given PEM-encoded private key.
+func ParseCertificateAuthority(privateKey string, clock clock.Clock) (*CertificateAuthority, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing CA private key")
+	}
+	return NewCertificateAuthority(signer, clock)
+}
+
+// PublicKey returns the public key of the certificate authority.
+func (ca *CertificateAuthority) PublicKey() ssh.PublicKey {
+	return ca.signer.PublicKey()
+}
+
+// UserCertificateParams holds the parameters for signing a user
+// certificate.
+type UserCertificateParams struct {
+	// PublicKey is the user's public key to sign.
+	PublicKey ssh.PublicKey
+
+	// KeyId identifies the Juju user the certificate is issued to,
+	// and is recorded in the machine's sshd logs on login.
+	KeyId string
+
+	// Principals holds the names the certificate is valid for.
+	// Machines accept a user certificate only if one of its
+	// principals is listed in their authorized principals file.
+	Principals []string
+
+	// ValidFor is how long the certificate is valid for. If zero,
+	// DefaultUserCertificateValidity is used.
+	ValidFor time.Duration
+}
+
+// Validate returns an error if the parameters are not valid.
+func (p UserCertificateParams) Validate() error {
+	if p.PublicKey == nil {
+		return errors.NotValidf("missing public key")
+	}
+	if p.KeyId == "" {
+		return errors.NotValidf("empty key id")
+	}
+	if len(p.Principals) == 0 {
+		return errors.NotValidf("user certificate without principals")
+	}
+	if p.ValidFor < 0 || p.ValidFor > MaxUserCertificateValidity {
+		return errors.NotValidf("validity %v", p.ValidFor)
+	}
+	return nil
+}
+
+// SignUserKey returns a user certificate for the given public key.
+// The certificate permits port, agent and X11 forwarding and the
+// allocation of a pty, matching what "juju ssh" needs.
+func (ca *CertificateAuthority) SignUserKey(p UserCertificateParams) (*ssh.Certificate, error) {
+	if err := p.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	validFor := p.ValidFor
+	if validFor == 0 {
+		validFor = DefaultUserCertificateValidity
+	}
+	cert := &ssh.Certificate{
+		Key:             p.PublicKey,
+		CertType:        ssh.UserCert,
+		KeyId:           p.KeyId,
+		ValidPrincipals: p.Principals,
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{
+				"permit-agent-forwarding": "",
+				"permit-port-forwarding":  "",
+				"permit-pty":              "",
+				"permit-X11-forwarding":   "",
+			},
+		},
+	}
+	if err := ca.sign(cert, validFor); err != nil {
+		return nil, errors.Annotate(err, "signing user certificate")
+	}
+	return cert, nil
+}
+
+// SignHostKey returns a host certificate for the given public key,
+// valid for the given host names and addresses.
+func (ca *CertificateAuthority) SignHostKey(key ssh.PublicKey, keyId string, hosts []string) (*ssh.Certificate, error) {
+	if key == nil {
+		return nil, errors.NotValidf("missing public key")
+	}
+	if len(hosts) == 0 {
+		return nil, errors.NotValidf("host certificate without hosts")
+	}
+	cert := &ssh.Certificate{
+		Key:             key,
+		CertType:        ssh.HostCert,
+		KeyId:           keyId,
+		ValidPrincipals: hosts,
+	}
+	if err := ca.sign(cert, DefaultHostCertificateValidity); err != nil {
+		return nil, errors.Annotate(err, "signing host certificate")
+	}
+	return cert, nil
+}
+
+// KnownHostsLine returns a line for a known_hosts file trusting
+// host certificates signed by the certificate authority for the
+// given host patterns.
+func (ca *CertificateAuthority) KnownHostsLine(patterns ...string) string {
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+	return fmt.Sprintf("@cert-authority %s %s",
+		strings.Join(patterns, ","),
+		strings.TrimSpace(string(ssh.MarshalAuthorizedKey(ca.PublicKey()))),
+	)
+}
+
+func (ca *CertificateAuthority) sign(cert *ssh.Certificate, validFor time.Duration) error {
+	var serial [8]byte
+	if _, err := io.ReadFull(ca.rand, serial[:]); err != nil {
+		return errors.Trace(err)
+	}
+	cert.Serial = binary.BigEndian.Uint64(serial[:])
+	now := ca.clock.Now()
+	cert.ValidAfter = uint64(now.Add(-clockSkew).Unix())
+	cert.ValidBefore = uint64(now.Add(validFor).Unix())
+	return cert.SignCert(ca.rand, ca.signer)
+}
+
+// TrustUserCAScript returns a shell script configuring sshd to accept
+// user certificates signed by the CA with the given public key, in
+// authorized_keys format, for logging in as the ubuntu user. Only
+// certificates listing the given principal are accepted, so that a
+// certificate issued for one model cannot be used on the machines of
+// another model trusting the same CA.
+func TrustUserCAScript(caPublicKey, principal string) string {
+	path := utils.ShQuote(TrustedUserCAKeysPath)
+	principalsDir := utils.ShQuote(AuthorizedPrincipalsDir)
+	principalsPath := utils.ShQuote(AuthorizedPrincipalsDir + "/ubuntu")
+	return fmt.Sprintf(`
+install -m 644 /dev/null %[1]s
+printf '%%s\n' %[2]s > %[1]s
+install -d -m 755 %[3]s
+install -m 644 /dev/null %[4]s
+printf '%%s\n' %[5]s > %[4]s
+if ! grep -q %[6]s /etc/ssh/sshd_config; then
+    printf '\nTrustedUserCAKeys %%s\nAuthorizedPrincipalsFile %%s\n' %[1]s %[7]s >> /etc/ssh/sshd_config
+    (systemctl reload ssh || service ssh reload) >/dev/null 2>&1 || true
+fi`[1:],
+		path,
+		utils.ShQuote(strings.TrimSpace(caPublicKey)),
+		principalsDir,
+		principalsPath,
+		utils.ShQuote(principal),
+		utils.ShQuote("^TrustedUserCAKeys "+TrustedUserCAKeysPath+"$"),
+		utils.ShQuote(AuthorizedPrincipalsDir+"/%u"),
+	)
+}
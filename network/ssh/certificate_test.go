@@ -0,0 +1,184 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ssh_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	jujussh "github.com/juju/utils/ssh"
+	cryptossh "golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network/ssh"
+)
+
+type certificateSuite struct {
+	testing.IsolationSuite
+	clock *testing.Clock
+	ca    *ssh.CertificateAuthority
+	key   cryptossh.PublicKey
+}
+
+var _ = gc.Suite(&certificateSuite{})
+
+func newSigner(c *gc.C) cryptossh.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, jc.ErrorIsNil)
+	signer, err := cryptossh.NewSignerFromKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	return signer
+}
+
+func (s *certificateSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC))
+	ca, err := ssh.NewCertificateAuthority(newSigner(c), s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	s.ca = ca
+	s.key = newSigner(c).PublicKey()
+}
+
+func (s *certificateSuite) checker() *cryptossh.CertChecker {
+	isCA := func(auth cryptossh.PublicKey) bool {
+		return string(auth.Marshal()) == string(s.ca.PublicKey().Marshal())
+	}
+	return &cryptossh.CertChecker{
+		IsUserAuthority: isCA,
+		IsHostAuthority: func(auth cryptossh.PublicKey, _ string) bool {
+			return isCA(auth)
+		},
+		Clock: s.clock.Now,
+	}
+}
+
+func (s *certificateSuite) TestNewCertificateAuthorityValidation(c *gc.C) {
+	_, err := ssh.NewCertificateAuthority(nil, s.clock)
+	c.Assert(err, gc.ErrorMatches, "nil signer not valid")
+	_, err = ssh.NewCertificateAuthority(newSigner(c), nil)
+	c.Assert(err, gc.ErrorMatches, "nil clock not valid")
+}
+
+func (s *certificateSuite) TestParseCertificateAuthority(c *gc.C) {
+	private, public, err := jujussh.GenerateKey("juju-ssh-ca")
+	c.Assert(err, jc.ErrorIsNil)
+	ca, err := ssh.ParseCertificateAuthority(private, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(cryptossh.MarshalAuthorizedKey(ca.PublicKey())), gc.Equals,
+		strings.Join(strings.Fields(public)[:2], " ")+"\n")
+
+	_, err = ssh.ParseCertificateAuthority("rubbish", s.clock)
+	c.Assert(err, gc.ErrorMatches, "parsing CA private key: .*")
+}
+
+func (s *certificateSuite) TestSignUserKey(c *gc.C) {
+	cert, err := s.ca.SignUserKey(ssh.UserCertificateParams{
+		PublicKey:  s.key,
+		KeyId:      "user-bob",
+		Principals: []string{"ubuntu"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cert.CertType, gc.Equals, uint32(cryptossh.UserCert))
+	c.Assert(cert.KeyId, gc.Equals, "user-bob")
+	c.Assert(cert.ValidPrincipals, jc.DeepEquals, []string{"ubuntu"})
+	c.Assert(cert.Permissions.Extensions, jc.DeepEquals, map[string]string{
+		"permit-agent-forwarding": "",
+		"permit-port-forwarding":  "",
+		"permit-pty":              "",
+		"permit-X11-forwarding":   "",
+	})
+	now := s.clock.Now()
+	c.Assert(cert.ValidAfter, gc.Equals, uint64(now.Add(-time.Minute).Unix()))
+	c.Assert(cert.ValidBefore, gc.Equals, uint64(now.Add(ssh.DefaultUserCertificateValidity).Unix()))
+
+	err = s.checker().CheckCert("ubuntu", cert)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.checker().CheckCert("root", cert)
+	c.Assert(err, gc.ErrorMatches, `ssh: principal "root" not in the set of valid principals for given certificate: .*`)
+}
+
+func (s *certificateSuite) TestSignUserKeyExpires(c *gc.C) {
+	cert, err := s.ca.SignUserKey(ssh.UserCertificateParams{
+		PublicKey:  s.key,
+		KeyId:      "user-bob",
+		Principals: []string{"ubuntu"},
+		ValidFor:   time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.clock.Advance(2 * time.Minute)
+	err = s.checker().CheckCert("ubuntu", cert)
+	c.Assert(err, gc.ErrorMatches, "ssh: cert has expired")
+}
+
+func (s *certificateSuite) TestSignUserKeyValidation(c *gc.C) {
+	for _, test := range []struct {
+		params ssh.UserCertificateParams
+		err    string
+	}{{
+		params: ssh.UserCertificateParams{KeyId: "user-bob", Principals: []string{"ubuntu"}},
+		err:    "missing public key not valid",
+	}, {
+		params: ssh.UserCertificateParams{PublicKey: s.key, Principals: []string{"ubuntu"}},
+		err:    "empty key id not valid",
+	}, {
+		params: ssh.UserCertificateParams{PublicKey: s.key, KeyId: "user-bob"},
+		err:    "user certificate without principals not valid",
+	}, {
+		params: ssh.UserCertificateParams{
+			PublicKey:  s.key,
+			KeyId:      "user-bob",
+			Principals: []string{"ubuntu"},
+			ValidFor:   2 * time.Hour,
+		},
+		err: "validity 2h0m0s not valid",
+	}} {
+		_, err := s.ca.SignUserKey(test.params)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *certificateSuite) TestSignHostKey(c *gc.C) {
+	cert, err := s.ca.SignHostKey(s.key, "machine-0", []string{"10.0.0.1", "machine-0.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cert.CertType, gc.Equals, uint32(cryptossh.HostCert))
+	c.Assert(cert.KeyId, gc.Equals, "machine-0")
+
+	err = s.checker().CheckHostKey("10.0.0.1:22", nil, cert)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.checker().CheckHostKey("10.0.0.2:22", nil, cert)
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *certificateSuite) TestSignHostKeyValidation(c *gc.C) {
+	_, err := s.ca.SignHostKey(nil, "machine-0", []string{"10.0.0.1"})
+	c.Assert(err, gc.ErrorMatches, "missing public key not valid")
+	_, err = s.ca.SignHostKey(s.key, "machine-0", nil)
+	c.Assert(err, gc.ErrorMatches, "host certificate without hosts not valid")
+}
+
+func (s *certificateSuite) TestKnownHostsLine(c *gc.C) {
+	caKey := strings.TrimSpace(string(cryptossh.MarshalAuthorizedKey(s.ca.PublicKey())))
+	c.Assert(s.ca.KnownHostsLine(), gc.Equals, "@cert-authority * "+caKey)
+	c.Assert(s.ca.KnownHostsLine("10.0.0.1", "*.example.com"), gc.Equals,
+		"@cert-authority 10.0.0.1,*.example.com "+caKey)
+}
+
+func (s *certificateSuite) TestTrustUserCAScript(c *gc.C) {
+	script := ssh.TrustUserCAScript("ecdsa-sha2-nistp256 AAAA juju's-ca\n", "deadbeef-0bad-400d-8000-4b1d0d06f00d")
+	c.Assert(script, gc.Equals, `
+install -m 644 /dev/null '/etc/ssh/juju_user_ca.pub'
+printf '%s\n' 'ecdsa-sha2-nistp256 AAAA juju'"'"'s-ca' > '/etc/ssh/juju_user_ca.pub'
+install -d -m 755 '/etc/ssh/juju_principals'
+install -m 644 /dev/null '/etc/ssh/juju_principals/ubuntu'
+printf '%s\n' 'deadbeef-0bad-400d-8000-4b1d0d06f00d' > '/etc/ssh/juju_principals/ubuntu'
+if ! grep -q '^TrustedUserCAKeys /etc/ssh/juju_user_ca.pub$' /etc/ssh/sshd_config; then
+    printf '\nTrustedUserCAKeys %s\nAuthorizedPrincipalsFile %s\n' '/etc/ssh/juju_user_ca.pub' '/etc/ssh/juju_principals/%u' >> /etc/ssh/sshd_config
+    (systemctl reload ssh || service ssh reload) >/dev/null 2>&1 || true
+fi`[1:])
+}
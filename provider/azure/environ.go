@@ -26,6 +26,7 @@ import (
 	"github.com/juju/utils/arch"
 	"github.com/juju/utils/os"
 	jujuseries "github.com/juju/utils/series"
+	"github.com/juju/utils/set"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
@@ -52,6 +53,11 @@ import (
 const (
 	jujuMachineNameTag = tags.JujuTagPrefix + "machine-name"
 
+	// jujuAvailabilityZoneTag records the availability zone of zonal
+	// virtual machines and managed disks. The Azure SDK version in use
+	// does not expose the zones of resources, so we record it ourselves.
+	jujuAvailabilityZoneTag = tags.JujuTagPrefix + "availability-zone"
+
 	// minRootDiskSize is the minimum root disk size Azure
 	// accepts for a VM's OS disk.
 	// It will be used if none is specified by the user.
@@ -69,6 +75,10 @@ const (
 	computeAPIVersion = "2016-04-30-preview"
 	networkAPIVersion = "2017-03-01"
 	storageAPIVersion = "2016-12-01"
+
+	// computeZonesAPIVersion is the compute API version used for
+	// resources placed in availability zones.
+	computeZonesAPIVersion = "2017-03-30"
 )
 
 type azureEnviron struct {
@@ -111,6 +121,8 @@ type azureEnviron struct {
 	mu                     sync.Mutex
 	config                 *azureModelConfig
 	instanceTypes          map[string]instances.InstanceType
+	availabilityZones      []string
+	instanceTypeZones      map[string]set.Strings
 	storageAccount         **storage.Account
 	storageAccountKey      *storage.AccountKey
 	commonResourcesCreated bool
@@ -251,7 +263,7 @@ func (env *azureEnviron) initResourceGroup(controllerUUID string, controller boo
 	tags := tags.ResourceTags(
 		names.NewModelTag(env.config.Config.UUID()),
 		names.NewControllerTag(controllerUUID),
-		env.config,
+		tags.Templated(env.config, tags.TemplateValues{Model: env.config.Name()}),
 	)
 	env.mu.Unlock()
 
@@ -371,6 +383,8 @@ func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		constraints.CpuPower,
 		constraints.Tags,
 		constraints.VirtType,
+		constraints.Interruptible,
+		constraints.MaxPrice,
 	})
 	validator.RegisterVocabulary(
 		constraints.Arch,
@@ -393,8 +407,11 @@ func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 
 // PrecheckInstance is defined on the environs.InstancePrechecker interface.
 func (env *azureEnviron) PrecheckInstance(args environs.PrecheckInstanceParams) error {
-	if args.Placement != "" {
-		return fmt.Errorf("unknown placement directive: %s", args.Placement)
+	if _, err := env.parsePlacement(args.Placement); err != nil {
+		return errors.Trace(err)
+	}
+	if err := common.ValidateZonesConstraint(env, args); err != nil {
+		return errors.Trace(err)
 	}
 	if !args.Constraints.HasInstanceType() {
 		return nil
@@ -423,6 +440,18 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 		return nil, errors.New("missing controller UUID")
 	}
 
+	// Determine the availability zone to create the instance in. The
+	// zone chosen by the provisioner takes precedence; otherwise the
+	// zone may be implied by placement or by volumes to attach.
+	zone := args.AvailabilityZone
+	if zone == "" {
+		var err error
+		zone, err = env.deriveAvailabilityZone(args.Placement, args.VolumeAttachments)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	// Get the required configuration and config-dependent information
 	// required to create the instance. We take the lock just once, to
 	// ensure we obtain all information based on the same configuration.
@@ -430,7 +459,7 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	envTags := tags.ResourceTags(
 		names.NewModelTag(env.config.Config.UUID()),
 		names.NewControllerTag(args.ControllerUUID),
-		env.config,
+		tags.Templated(env.config, tags.TemplateValues{Model: env.config.Name()}),
 	)
 	storageAccountType := env.config.storageAccountType
 	imageStream := env.config.ImageStream()
@@ -441,6 +470,15 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	}
 	env.mu.Unlock()
 
+	// Only instance types offered in the zone, and not restricted
+	// from the subscription there, can be created in it.
+	if zone != "" {
+		instanceTypes, err = env.instanceTypesInZone(instanceTypes, zone)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	// If the user has not specified a root-disk size, then
 	// set a sensible default.
 	var rootDisk uint64
@@ -518,11 +556,14 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	// the Juju machine name. We tag all resources related to the
 	// machine with this.
 	vmTags[jujuMachineNameTag] = vmName
+	if zone != "" {
+		vmTags[jujuAvailabilityZoneTag] = zone
+	}
 
 	if err := env.createVirtualMachine(
 		vmName, vmTags, envTags,
 		instanceSpec, args.InstanceConfig,
-		storageAccountType,
+		storageAccountType, zone,
 	); err != nil {
 		logger.Errorf("creating instance failed, destroying: %v", err)
 		if err := env.StopInstances(instance.Id(vmName)); err != nil {
@@ -542,6 +583,9 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 		RootDisk: &instanceSpec.InstanceType.RootDisk,
 		CpuCores: &instanceSpec.InstanceType.CpuCores,
 	}
+	if zone != "" {
+		hc.AvailabilityZone = &zone
+	}
 	return &environs.StartInstanceResult{
 		Instance: inst,
 		Hardware: hc,
@@ -549,6 +593,8 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 }
 
 // createVirtualMachine creates a virtual machine and related resources.
+// If zone is non-empty, the virtual machine is created in that
+// availability zone rather than in an availability set.
 //
 // All resources created are tagged with the specified "vmTags", so if
 // this function fails then all resources can be deleted by tag.
@@ -558,6 +604,7 @@ func (env *azureEnviron) createVirtualMachine(
 	instanceSpec *instances.InstanceSpec,
 	instanceConfig *instancecfg.InstanceConfig,
 	storageAccountType string,
+	zone string,
 ) error {
 	deploymentsClient := resources.DeploymentsClient{
 		ManagementClient: env.resources,
@@ -618,6 +665,9 @@ func (env *azureEnviron) createVirtualMachine(
 	if err != nil {
 		return errors.Annotate(err, "creating OS profile")
 	}
+	if zone != "" && maybeStorageAccount != nil {
+		return errors.NotSupportedf("availability zones for models using unmanaged disks")
+	}
 	storageProfile, err := newStorageProfile(
 		vmName,
 		maybeStorageAccount,
@@ -635,6 +685,11 @@ func (env *azureEnviron) createVirtualMachine(
 	if err != nil {
 		return errors.Annotate(err, "getting availability set name")
 	}
+	if zone != "" {
+		// A virtual machine may be in an availability zone or an
+		// availability set, but not both.
+		availabilitySetName = ""
+	}
 	if availabilitySetName != "" {
 		availabilitySetId := fmt.Sprintf(
 			`[resourceId('Microsoft.Compute/availabilitySets','%s')]`,
@@ -738,8 +793,14 @@ func (env *azureEnviron) createVirtualMachine(
 		},
 	}}
 	vmDependsOn = append(vmDependsOn, nicId)
+	vmAPIVersion := computeAPIVersion
+	var vmZones []string
+	if zone != "" {
+		vmAPIVersion = computeZonesAPIVersion
+		vmZones = []string{zone}
+	}
 	resources = append(resources, armtemplates.Resource{
-		APIVersion: computeAPIVersion,
+		APIVersion: vmAPIVersion,
 		Type:       "Microsoft.Compute/virtualMachines",
 		Name:       vmName,
 		Location:   env.location,
@@ -758,6 +819,7 @@ func (env *azureEnviron) createVirtualMachine(
 			AvailabilitySet: availabilitySetSubResource,
 		},
 		DependsOn: vmDependsOn,
+		Zones:     vmZones,
 	})
 
 	// On Windows and CentOS, we must add the CustomScript VM
@@ -1466,6 +1528,7 @@ func (env *azureEnviron) allInstances(
 	}
 
 	azureInstances := make([]*azureInstance, 0, len(*deploymentsResult.Value))
+	var zonalInstances []*azureInstance
 	for _, deployment := range *deploymentsResult.Value {
 		name := to.String(deployment.Name)
 		if _, err := names.ParseMachineTag(name); err != nil {
@@ -1478,14 +1541,32 @@ func (env *azureEnviron) allInstances(
 		if deployment.Properties == nil || deployment.Properties.Dependencies == nil {
 			continue
 		}
-		if controllerOnly && !isControllerDeployment(deployment) {
-			continue
-		}
 		provisioningState := to.String(deployment.Properties.ProvisioningState)
 		inst := &azureInstance{name, provisioningState, env, nil, nil}
+		if controllerOnly {
+			isController, inAvailabilitySet := isControllerDeployment(deployment)
+			if !inAvailabilitySet {
+				// Machines in availability zones are not in
+				// an availability set, so we must check the
+				// VM's tags to determine if it is a controller.
+				zonalInstances = append(zonalInstances, inst)
+				continue
+			}
+			if !isController {
+				continue
+			}
+		}
 		azureInstances = append(azureInstances, inst)
 	}
 
+	if len(zonalInstances) > 0 {
+		controllers, err := env.zonalControllerInstances(resourceGroup, zonalInstances)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		azureInstances = append(azureInstances, controllers...)
+	}
+
 	if len(azureInstances) > 0 && refreshAddresses {
 		if err := setInstanceAddresses(
 			resourceGroup,
@@ -1504,7 +1585,39 @@ func (env *azureEnviron) allInstances(
 	return instances, nil
 }
 
-func isControllerDeployment(deployment resources.DeploymentExtended) bool {
+// zonalControllerInstances returns those of the given instances whose
+// virtual machines are tagged as controllers.
+func (env *azureEnviron) zonalControllerInstances(
+	resourceGroup string,
+	instances []*azureInstance,
+) ([]*azureInstance, error) {
+	client := compute.VirtualMachinesClient{env.compute}
+	result, err := client.List(resourceGroup)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing virtual machines")
+	}
+	if result.Value == nil {
+		return nil, nil
+	}
+	controllers := set.NewStrings()
+	for _, vm := range *result.Value {
+		if toTags(vm.Tags)[tags.JujuIsController] == "true" {
+			controllers.Add(to.String(vm.Name))
+		}
+	}
+	var controllerInstances []*azureInstance
+	for _, inst := range instances {
+		if controllers.Contains(inst.vmName) {
+			controllerInstances = append(controllerInstances, inst)
+		}
+	}
+	return controllerInstances, nil
+}
+
+// isControllerDeployment reports whether the deployment's virtual
+// machine is in the controller availability set, and whether it is
+// in any availability set at all.
+func isControllerDeployment(deployment resources.DeploymentExtended) (isController, inAvailabilitySet bool) {
 	for _, d := range *deployment.Properties.Dependencies {
 		if d.DependsOn == nil {
 			continue
@@ -1516,12 +1629,13 @@ func isControllerDeployment(deployment resources.DeploymentExtended) bool {
 			if to.String(on.ResourceType) != "Microsoft.Compute/availabilitySets" {
 				continue
 			}
+			inAvailabilitySet = true
 			if to.String(on.ResourceName) == controllerAvailabilitySet {
-				return true
+				return true, true
 			}
 		}
 	}
-	return false
+	return false, inAvailabilitySet
 }
 
 // Destroy is specified in the Environ interface.
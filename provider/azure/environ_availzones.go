@@ -0,0 +1,279 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/arm/compute"
+	"github.com/Azure/azure-sdk-for-go/arm/disk"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/azure/internal/resourceskus"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/storage"
+)
+
+type azureAvailabilityZone struct {
+	name string
+}
+
+// Name is specified in the common.AvailabilityZone interface.
+func (z *azureAvailabilityZone) Name() string {
+	return z.name
+}
+
+// Available is specified in the common.AvailabilityZone interface.
+func (z *azureAvailabilityZone) Available() bool {
+	return true
+}
+
+// AvailabilityZones is specified in the common.ZonedEnviron interface.
+//
+// The zones are those that virtual machines can be created in, in the
+// model's location. Models created before Juju 2.3 use unmanaged disks,
+// which cannot be placed in zones; such models have no zones.
+func (env *azureEnviron) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	_, err := env.getStorageAccount()
+	if err == nil {
+		return nil, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+
+	names, err := env.availabilityZoneNames()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zones := make([]common.AvailabilityZone, len(names))
+	for i, name := range names {
+		zones[i] = &azureAvailabilityZone{name}
+	}
+	return zones, nil
+}
+
+// availabilityZoneNames returns the names of the availability zones in
+// the model's location that virtual machines of some size can be
+// created in.
+func (env *azureEnviron) availabilityZoneNames() ([]string, error) {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	if err := env.loadAvailabilityZonesLocked(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return env.availabilityZones, nil
+}
+
+// instanceTypesInZone returns those of the given instance types that
+// virtual machines can be created with in the given availability zone.
+func (env *azureEnviron) instanceTypesInZone(
+	instanceTypes map[string]instances.InstanceType,
+	zone string,
+) (map[string]instances.InstanceType, error) {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	if err := env.loadAvailabilityZonesLocked(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	zonal := make(map[string]instances.InstanceType)
+	for name, instanceType := range instanceTypes {
+		if env.instanceTypeZones[name].Contains(zone) {
+			zonal[name] = instanceType
+		}
+	}
+	return zonal, nil
+}
+
+// loadAvailabilityZonesLocked records the availability zones in the
+// model's location that each virtual machine size can be created in,
+// querying the Resource SKUs API the first time it is called. It must
+// be called with env.mu held.
+func (env *azureEnviron) loadAvailabilityZonesLocked() error {
+	if env.instanceTypeZones != nil {
+		return nil
+	}
+	client := resourceskus.Client{env.compute}
+	skus, err := client.List()
+	if err != nil {
+		return errors.Annotate(err, "listing resource SKUs")
+	}
+	allZones := set.NewStrings()
+	instanceTypeZones := make(map[string]set.Strings)
+	for _, sku := range skus {
+		if sku.ResourceType != "virtualMachines" {
+			continue
+		}
+		zones := skuZones(sku, env.location)
+		if existing, ok := instanceTypeZones[sku.Name]; ok {
+			zones = zones.Union(existing)
+		}
+		instanceTypeZones[sku.Name] = zones
+		allZones = allZones.Union(zones)
+	}
+	env.availabilityZones = allZones.SortedValues()
+	env.instanceTypeZones = instanceTypeZones
+	return nil
+}
+
+// skuZones returns the availability zones in the given location that
+// the SKU is offered in, and not restricted from the subscription.
+func skuZones(sku resourceskus.ResourceSku, location string) set.Strings {
+	zones := set.NewStrings()
+	for _, info := range sku.LocationInfo {
+		if canonicalLocation(info.Location) == location {
+			zones = zones.Union(set.NewStrings(info.Zones...))
+		}
+	}
+	for _, restriction := range sku.Restrictions {
+		if !restriction.AppliesTo(location, canonicalLocation) {
+			continue
+		}
+		switch restriction.Type {
+		case resourceskus.RestrictionTypeLocation:
+			return set.NewStrings()
+		case resourceskus.RestrictionTypeZone:
+			zones = zones.Difference(set.NewStrings(restriction.RestrictionInfo.Zones...))
+		}
+	}
+	return zones
+}
+
+// InstanceAvailabilityZoneNames is specified in the common.ZonedEnviron
+// interface.
+func (env *azureEnviron) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	instances, err := env.instances(env.resourceGroup, ids, false /* refresh addresses */)
+	if err != nil && err != environs.ErrPartialInstances && err != environs.ErrNoInstances {
+		return nil, errors.Trace(err)
+	}
+	// We let the two environs errors pass on through. However, we do
+	// not use errors.Trace in that case since callers may not call
+	// errors.Cause.
+
+	results := make([]string, len(ids))
+	if err == environs.ErrNoInstances {
+		return results, err
+	}
+	vmsClient := compute.VirtualMachinesClient{env.compute}
+	result, listErr := vmsClient.List(env.resourceGroup)
+	if listErr != nil {
+		return nil, errors.Annotate(listErr, "listing virtual machines")
+	}
+	vmZones := make(map[string]string)
+	if result.Value != nil {
+		for _, vm := range *result.Value {
+			vmZones[to.String(vm.Name)] = toTags(vm.Tags)[jujuAvailabilityZoneTag]
+		}
+	}
+	for i, inst := range instances {
+		if inst != nil {
+			results[i] = vmZones[string(inst.Id())]
+		}
+	}
+	return results, err
+}
+
+// DeriveAvailabilityZones is specified in the common.ZonedEnviron
+// interface.
+func (env *azureEnviron) DeriveAvailabilityZones(args environs.StartInstanceParams) ([]string, error) {
+	zone, err := env.deriveAvailabilityZone(args.Placement, args.VolumeAttachments)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zone == "" {
+		return nil, nil
+	}
+	return []string{zone}, nil
+}
+
+// deriveAvailabilityZone returns the availability zone implied by the
+// placement directive and the zones of the volumes to attach, or the
+// empty string if neither implies a zone.
+func (env *azureEnviron) deriveAvailabilityZone(
+	placement string,
+	volumeAttachments []storage.VolumeAttachmentParams,
+) (string, error) {
+	volumesZone, err := env.volumeAttachmentsZone(volumeAttachments)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	placementZone, err := env.parsePlacement(placement)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if placementZone == "" {
+		return volumesZone, nil
+	}
+	if volumesZone != "" && placementZone != volumesZone {
+		return "", errors.Errorf(
+			"cannot create instance with placement %q, as this will prevent attaching disks in zone %q",
+			placement, volumesZone,
+		)
+	}
+	return placementZone, nil
+}
+
+// parsePlacement parses the placement directive, returning the name of
+// the availability zone it specifies. An empty placement directive
+// specifies no zone.
+func (env *azureEnviron) parsePlacement(placement string) (string, error) {
+	if placement == "" {
+		return "", nil
+	}
+	pos := strings.IndexRune(placement, '=')
+	if pos == -1 {
+		return "", errors.Errorf("unknown placement directive: %s", placement)
+	}
+	switch key, value := placement[:pos], placement[pos+1:]; key {
+	case "zone":
+		if err := common.ValidateAvailabilityZone(env, value); err != nil {
+			return "", errors.Trace(err)
+		}
+		return value, nil
+	}
+	return "", errors.Errorf("unknown placement directive: %s", placement)
+}
+
+// volumeAttachmentsZone returns the availability zone of the managed
+// disks to attach, checking that they are all in the same zone.
+func (env *azureEnviron) volumeAttachmentsZone(volumeAttachments []storage.VolumeAttachmentParams) (string, error) {
+	diskClient := disk.DisksClient{env.disk}
+	var zone, zoneVolumeId string
+	for _, a := range volumeAttachments {
+		if a.VolumeId == "" {
+			// The volume has not been created yet.
+			continue
+		}
+		result, err := diskClient.Get(env.resourceGroup, a.VolumeId)
+		if err != nil {
+			if isNotFoundResponse(result.Response) {
+				// Unmanaged disks are not zonal.
+				continue
+			}
+			return "", errors.Annotatef(err, "getting disk %q", a.VolumeId)
+		}
+		volumeZone := toTags(result.Tags)[jujuAvailabilityZoneTag]
+		if volumeZone == "" {
+			continue
+		}
+		if zone == "" {
+			zone, zoneVolumeId = volumeZone, a.VolumeId
+		} else if zone != volumeZone {
+			return "", errors.Errorf(
+				"cannot attach volumes from multiple availability zones: %s is in %s, %s is in %s",
+				zoneVolumeId, zone, a.VolumeId, volumeZone,
+			)
+		}
+	}
+	return zone, nil
+}
+
+// DistributeInstances implements the state.InstanceDistributor policy.
+func (env *azureEnviron) DistributeInstances(candidates, distributionGroup []instance.Id) ([]instance.Id, error) {
+	return common.DistributeInstances(env, candidates, distributionGroup)
+}
@@ -0,0 +1,380 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure_test
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/arm/compute"
+	"github.com/Azure/azure-sdk-for-go/arm/disk"
+	"github.com/Azure/azure-sdk-for-go/arm/resources/resources"
+	"github.com/Azure/go-autorest/autorest/mocks"
+	"github.com/Azure/go-autorest/autorest/to"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/azure/internal/azuretesting"
+	"github.com/juju/juju/provider/azure/internal/resourceskus"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/storage"
+)
+
+func (s *environSuite) storageAccountNotFoundSender() *azuretesting.MockSender {
+	sender := mocks.NewSender()
+	sender.AppendResponse(mocks.NewResponseWithStatus(
+		"storage account not found", http.StatusNotFound,
+	))
+	return &azuretesting.MockSender{
+		Sender:      sender,
+		PathPattern: ".*/storageAccounts/" + storageAccountName,
+	}
+}
+
+func (s *environSuite) resourceSkusSender() *azuretesting.MockSender {
+	return s.makeSender(".*/providers/Microsoft.Compute/skus", map[string]interface{}{
+		"value": []resourceskus.ResourceSku{{
+			ResourceType: "virtualMachines",
+			Name:         "Standard_A1",
+			Locations:    []string{"westus", "eastus"},
+			LocationInfo: []resourceskus.LocationInfo{{
+				Location: "WestUS",
+				Zones:    []string{"2", "1"},
+			}, {
+				Location: "EastUS",
+				Zones:    []string{"1", "2", "3"},
+			}},
+		}, {
+			ResourceType: "virtualMachines",
+			Name:         "Standard_D1",
+			Locations:    []string{"westus"},
+			LocationInfo: []resourceskus.LocationInfo{{
+				Location: "westus",
+				Zones:    []string{"3"},
+			}},
+		}, {
+			// Zone 5 is restricted from the subscription.
+			ResourceType: "virtualMachines",
+			Name:         "Standard_D2",
+			Locations:    []string{"westus"},
+			LocationInfo: []resourceskus.LocationInfo{{
+				Location: "westus",
+				Zones:    []string{"1", "5"},
+			}},
+			Restrictions: []resourceskus.Restriction{{
+				Type:   resourceskus.RestrictionTypeZone,
+				Values: []string{"westus"},
+				RestrictionInfo: resourceskus.RestrictionInfo{
+					Locations: []string{"westus"},
+					Zones:     []string{"5"},
+				},
+				ReasonCode: "NotAvailableForSubscription",
+			}},
+		}, {
+			// The whole location is restricted from the subscription.
+			ResourceType: "virtualMachines",
+			Name:         "Standard_D3",
+			Locations:    []string{"westus"},
+			LocationInfo: []resourceskus.LocationInfo{{
+				Location: "westus",
+				Zones:    []string{"6"},
+			}},
+			Restrictions: []resourceskus.Restriction{{
+				Type:   resourceskus.RestrictionTypeLocation,
+				Values: []string{"westus"},
+				RestrictionInfo: resourceskus.RestrictionInfo{
+					Locations: []string{"westus"},
+				},
+				ReasonCode: "NotAvailableForSubscription",
+			}},
+		}, {
+			ResourceType: "disks",
+			Name:         "Premium_LRS",
+			Locations:    []string{"westus"},
+			LocationInfo: []resourceskus.LocationInfo{{
+				Location: "westus",
+				Zones:    []string{"4"},
+			}},
+		}},
+	})
+}
+
+func (s *environSuite) zonedEnviron(c *gc.C) common.ZonedEnviron {
+	env := s.openEnviron(c)
+	zonedEnv, ok := env.(common.ZonedEnviron)
+	c.Assert(ok, jc.IsTrue)
+	return zonedEnv
+}
+
+func (s *environSuite) TestAvailabilityZones(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.storageAccountNotFoundSender(),
+		s.resourceSkusSender(),
+	}
+	zones, err := env.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var names []string
+	for _, zone := range zones {
+		c.Check(zone.Available(), jc.IsTrue)
+		names = append(names, zone.Name())
+	}
+	c.Assert(names, jc.DeepEquals, []string{"1", "2", "3"})
+
+	// The zones are cached, so there should be no further requests.
+	s.requests = nil
+	zones, err = env.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 3)
+	c.Assert(s.requests, gc.HasLen, 0)
+}
+
+func (s *environSuite) TestAvailabilityZonesUnmanagedStorage(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.sender = azuretesting.Senders{s.storageAccountSender()}
+	zones, err := env.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+}
+
+func (s *environSuite) TestAvailabilityZonesError(c *gc.C) {
+	env := s.zonedEnviron(c)
+	sender := mocks.NewSender()
+	sender.AppendResponse(mocks.NewResponseWithStatus("oh noes", http.StatusBadRequest))
+	s.sender = azuretesting.Senders{
+		s.storageAccountNotFoundSender(),
+		sender,
+	}
+	_, err := env.AvailabilityZones()
+	c.Assert(err, gc.ErrorMatches, "listing resource SKUs: .*")
+}
+
+func (s *environSuite) TestInstanceAvailabilityZoneNames(c *gc.C) {
+	env := s.zonedEnviron(c)
+	deployments := []resources.DeploymentExtended{
+		makeDeployment("machine-0"),
+		makeDeployment("machine-1"),
+	}
+	vm0Tags := map[string]*string{"juju-availability-zone": to.StringPtr("2")}
+	virtualMachines := []compute.VirtualMachine{{
+		Name: to.StringPtr("machine-0"),
+		Tags: &vm0Tags,
+	}, {
+		Name: to.StringPtr("machine-1"),
+	}}
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/deployments", resources.DeploymentListResult{Value: &deployments}),
+		s.makeSender(".*/virtualMachines", compute.VirtualMachineListResult{Value: &virtualMachines}),
+	}
+	zones, err := env.InstanceAvailabilityZoneNames([]instance.Id{
+		"machine-0", "machine-1", "machine-2",
+	})
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(zones, jc.DeepEquals, []string{"2", "", ""})
+}
+
+func (s *environSuite) TestInstanceAvailabilityZoneNamesNoInstances(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/deployments", resources.DeploymentListResult{}),
+	}
+	zones, err := env.InstanceAvailabilityZoneNames([]instance.Id{"machine-0"})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+	c.Assert(zones, jc.DeepEquals, []string{""})
+}
+
+func (s *environSuite) TestDeriveAvailabilityZones(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.storageAccountNotFoundSender(),
+		s.resourceSkusSender(),
+	}
+	zones, err := env.DeriveAvailabilityZones(environs.StartInstanceParams{
+		Placement: "zone=3",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"3"})
+}
+
+func (s *environSuite) TestDeriveAvailabilityZonesNoPlacement(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.requests = nil
+	zones, err := env.DeriveAvailabilityZones(environs.StartInstanceParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+	c.Assert(s.requests, gc.HasLen, 0)
+}
+
+func (s *environSuite) TestDeriveAvailabilityZonesInvalidPlacement(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.storageAccountNotFoundSender(),
+		s.resourceSkusSender(),
+	}
+	_, err := env.DeriveAvailabilityZones(environs.StartInstanceParams{
+		Placement: "zone=4",
+	})
+	c.Assert(err, gc.ErrorMatches, `availability zone "4" not valid`)
+}
+
+func (s *environSuite) TestDeriveAvailabilityZonesVolumeAttachments(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/disks/volume-0", s.zonalDisk("volume-0", "1")),
+	}
+	zones, err := env.DeriveAvailabilityZones(environs.StartInstanceParams{
+		VolumeAttachments: []storage.VolumeAttachmentParams{
+			makeVolumeAttachmentParams("0", "volume-0"),
+			makeVolumeAttachmentParams("1", ""),
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"1"})
+}
+
+func (s *environSuite) TestDeriveAvailabilityZonesVolumeAttachmentsConflict(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/disks/volume-0", s.zonalDisk("volume-0", "1")),
+		s.makeSender(".*/disks/volume-1", s.zonalDisk("volume-1", "2")),
+	}
+	_, err := env.DeriveAvailabilityZones(environs.StartInstanceParams{
+		VolumeAttachments: []storage.VolumeAttachmentParams{
+			makeVolumeAttachmentParams("0", "volume-0"),
+			makeVolumeAttachmentParams("1", "volume-1"),
+		},
+	})
+	c.Assert(err, gc.ErrorMatches,
+		"cannot attach volumes from multiple availability zones: volume-0 is in 1, volume-1 is in 2",
+	)
+}
+
+func (s *environSuite) TestDeriveAvailabilityZonesPlacementConflict(c *gc.C) {
+	env := s.zonedEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/disks/volume-0", s.zonalDisk("volume-0", "1")),
+		s.storageAccountNotFoundSender(),
+		s.resourceSkusSender(),
+	}
+	_, err := env.DeriveAvailabilityZones(environs.StartInstanceParams{
+		Placement: "zone=2",
+		VolumeAttachments: []storage.VolumeAttachmentParams{
+			makeVolumeAttachmentParams("0", "volume-0"),
+		},
+	})
+	c.Assert(err, gc.ErrorMatches,
+		`cannot create instance with placement "zone=2", as this will prevent attaching disks in zone "1"`,
+	)
+}
+
+func (s *environSuite) TestPrecheckInstanceZonePlacement(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.storageAccountNotFoundSender(),
+		s.resourceSkusSender(),
+	}
+	err := env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Series:    "quantal",
+		Placement: "zone=1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Series:    "quantal",
+		Placement: "zone=4",
+	})
+	c.Assert(err, gc.ErrorMatches, `availability zone "4" not valid`)
+}
+
+func (s *environSuite) TestStartInstanceAvailabilityZone(c *gc.C) {
+	env := s.openEnviron(c)
+	s.vmTags["juju-availability-zone"] = to.StringPtr("2")
+	s.sender = s.zonalStartInstanceSenders()
+	s.requests = nil
+	params := makeStartInstanceParams(c, s.controllerUUID, "quantal")
+	params.AvailabilityZone = "2"
+
+	result, err := env.StartInstance(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Hardware.AvailabilityZone, gc.NotNil)
+	c.Assert(*result.Hardware.AvailabilityZone, gc.Equals, "2")
+	s.assertStartInstanceRequests(c, s.zonalStartInstanceRequests(), assertStartInstanceRequestsParams{
+		availabilityZone: "2",
+		imageReference:   &quantalImageReference,
+		diskSizeGB:       32,
+		osProfile:        &s.linuxOsProfile,
+		instanceType:     "Standard_A1",
+	})
+}
+
+func (s *environSuite) TestStartInstanceAvailabilityZoneInstanceTypes(c *gc.C) {
+	// Standard_A1 is not offered in zone 3, so the smallest
+	// instance type that is must be chosen instead.
+	env := s.openEnviron(c)
+	s.vmTags["juju-availability-zone"] = to.StringPtr("3")
+	s.sender = s.zonalStartInstanceSenders()
+	s.requests = nil
+	params := makeStartInstanceParams(c, s.controllerUUID, "quantal")
+	params.AvailabilityZone = "3"
+
+	_, err := env.StartInstance(params)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStartInstanceRequests(c, s.zonalStartInstanceRequests(), assertStartInstanceRequestsParams{
+		availabilityZone: "3",
+		imageReference:   &quantalImageReference,
+		diskSizeGB:       32,
+		osProfile:        &s.linuxOsProfile,
+		instanceType:     "Standard_D1",
+	})
+}
+
+func (s *environSuite) TestStartInstanceAvailabilityZoneRestricted(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = s.zonalStartInstanceSenders()
+	params := makeStartInstanceParams(c, s.controllerUUID, "quantal")
+	params.Constraints = constraints.MustParse("instance-type=Standard_D2")
+	params.AvailabilityZone = "5"
+
+	_, err := env.StartInstance(params)
+	c.Assert(err, gc.ErrorMatches, `no instance types in westus matching constraints ".*instance-type=Standard_D2.*"`)
+	c.Assert(environs.IsAvailabilityZoneIndependent(err), jc.IsFalse)
+}
+
+// zonalStartInstanceSenders returns the senders for starting an
+// instance in an availability zone, which lists the resource SKUs
+// after the VM sizes.
+func (s *environSuite) zonalStartInstanceSenders() azuretesting.Senders {
+	senders := s.startInstanceSenders(false)
+	return append(azuretesting.Senders{senders[0], s.resourceSkusSender()}, senders[1:]...)
+}
+
+// zonalStartInstanceRequests returns the requests made when starting
+// an instance in an availability zone, without the request listing
+// the resource SKUs.
+func (s *environSuite) zonalStartInstanceRequests() []*http.Request {
+	return append(s.requests[:1:1], s.requests[2:]...)
+}
+
+func (s *environSuite) zonalDisk(name, zone string) *disk.Model {
+	tags := map[string]*string{"juju-availability-zone": to.StringPtr(zone)}
+	return &disk.Model{
+		Name: to.StringPtr(name),
+		Tags: &tags,
+	}
+}
+
+func makeVolumeAttachmentParams(volume, volumeId string) storage.VolumeAttachmentParams {
+	return storage.VolumeAttachmentParams{
+		AttachmentParams: storage.AttachmentParams{
+			Provider: "azure",
+			Machine:  names.NewMachineTag("0"),
+		},
+		Volume:   names.NewVolumeTag(volume),
+		VolumeId: volumeId,
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package azure
+
+import (
+	"github.com/Azure/azure-sdk-for-go/arm/resources/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	internalazureresources "github.com/juju/juju/provider/azure/internal/azureresources"
+)
+
+var _ environs.InstanceTagger = (*azureEnviron)(nil)
+var _ environs.ResourceRetagger = (*azureEnviron)(nil)
+
+// TagInstance is part of the environs.InstanceTagger interface. The
+// instance's virtual machine is tagged, along with the resources that
+// belong to it: its network interfaces, public IP address and OS disk.
+func (env *azureEnviron) TagInstance(id instance.Id, instanceTags map[string]string) error {
+	return env.retagInstance(id, instanceTags, nil)
+}
+
+// RetagInstance is part of the environs.ResourceRetagger interface.
+func (env *azureEnviron) RetagInstance(id instance.Id, instanceTags map[string]string, removed []string) error {
+	return env.retagInstance(id, instanceTags, tags.Unset(instanceTags, env.Config(), removed))
+}
+
+func (env *azureEnviron) retagInstance(id instance.Id, instanceTags map[string]string, unset []string) error {
+	vmName := string(id)
+	err := env.retagResources(func(resource resources.GenericResource) (map[string]string, []string) {
+		// The managed OS disk is not tagged when it is created
+		// with the virtual machine, but is named after it.
+		if toTags(resource.Tags)[jujuMachineNameTag] != vmName && to.String(resource.Name) != vmName {
+			return nil, nil
+		}
+		return instanceTags, unset
+	})
+	return errors.Annotate(err, "tagging instance")
+}
+
+// RetagResources is part of the environs.ResourceRetagger interface.
+// The model's resource group is retagged, along with the resources in
+// it that were created for the model and do not belong to a machine,
+// such as its virtual network, security group and volumes.
+func (env *azureEnviron) RetagResources(controllerUUID string, removed []string) error {
+	cfg := env.Config()
+	modelTag := names.NewModelTag(cfg.UUID())
+	controllerTag := names.NewControllerTag(controllerUUID)
+	modelTags := tags.ResourceTags(modelTag, controllerTag, tags.Templated(cfg, tags.TemplateValues{
+		Model: cfg.Name(),
+	}))
+	if err := env.retagGroup(modelTags, tags.Unset(modelTags, cfg, removed)); err != nil {
+		return errors.Trace(err)
+	}
+	return env.retagResources(func(resource resources.GenericResource) (map[string]string, []string) {
+		resourceTags := toTags(resource.Tags)
+		if resourceTags[tags.JujuModel] != cfg.UUID() || resourceTags[jujuMachineNameTag] != "" {
+			return nil, nil
+		}
+		newTags := modelTags
+		if _, ok := resourceTags[tags.JujuStorageInstance]; ok {
+			newTags = tags.ResourceTags(modelTag, controllerTag, tags.Templated(
+				cfg, tags.StorageTemplateValues(cfg.Name(), resourceTags[tags.JujuStorageOwner]),
+			))
+		}
+		return newTags, tags.Unset(newTags, cfg, removed)
+	})
+}
+
+// retagGroup sets the given tags on the model's resource group, and
+// removes the tags with the given names from it.
+func (env *azureEnviron) retagGroup(set map[string]string, unset []string) error {
+	client := resources.GroupsClient{env.resources}
+	group, err := client.Get(env.resourceGroup)
+	if err != nil {
+		return errors.Annotate(err, "getting resource group")
+	}
+	groupTags, changed := updatedTags(toTags(group.Tags), set, unset)
+	if !changed {
+		return nil
+	}
+	group.Tags = to.StringMapPtr(groupTags)
+
+	// The Azure API forbids specifying ProvisioningState on the update.
+	if group.Properties != nil {
+		(*group.Properties).ProvisioningState = nil
+	}
+
+	_, err = client.CreateOrUpdate(env.resourceGroup, group)
+	return errors.Annotate(err, "updating tags for resource group")
+}
+
+// retagResources updates the tags of the resources in the model's
+// resource group. For each resource, retag returns the tags to set on
+// it and the names of the tags to remove from it; resources for which
+// it returns neither are left alone.
+func (env *azureEnviron) retagResources(
+	retag func(resources.GenericResource) (map[string]string, []string),
+) error {
+	apiVersions, err := collectAPIVersions(resources.ProvidersClient{env.resources})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	groupClient := resources.GroupsClient{env.resources}
+	resourceClient := resources.GroupClient{env.resources}
+	res, err := groupClient.ListResources(env.resourceGroup, "", "", nil)
+	if err != nil {
+		return errors.Annotate(err, "listing resources")
+	}
+	var failed []string
+	for res.Value != nil {
+		for _, resource := range *res.Value {
+			set, unset := retag(resource)
+			if _, changed := updatedTags(toTags(resource.Tags), set, unset); !changed {
+				continue
+			}
+			err := env.retagResource(
+				internalazureresources.ResourcesClient{&resourceClient},
+				resource, apiVersions[to.String(resource.Type)], set, unset,
+			)
+			if err != nil {
+				name := to.String(resource.Name)
+				logger.Errorf("error updating resource tags for %q: %v", name, err)
+				failed = append(failed, name)
+			}
+		}
+		res, err = groupClient.ListResourcesNextResults(res)
+		if err != nil {
+			return errors.Annotate(err, "getting next page of resources")
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("failed to update tags for some resources: %v", failed)
+	}
+	return nil
+}
+
+func (env *azureEnviron) retagResource(
+	client internalazureresources.ResourcesClient,
+	stubResource resources.GenericResource,
+	apiVersion string,
+	set map[string]string,
+	unset []string,
+) error {
+	// Need to get the resource individually to ensure that the
+	// properties are populated.
+	resource, err := client.GetByID(to.String(stubResource.ID), apiVersion)
+	if err != nil {
+		return errors.Annotatef(err, "getting full resource %q", to.String(stubResource.Name))
+	}
+
+	logger.Debugf("updating %s tags", to.String(stubResource.ID))
+	resourceTags, _ := updatedTags(toTags(resource.Tags), set, unset)
+	resource.Tags = to.StringMapPtr(resourceTags)
+	_, errCh := client.CreateOrUpdateByID(
+		to.String(stubResource.ID),
+		resource,
+		nil, // cancel channel
+		apiVersion,
+	)
+	err = <-errCh
+	return errors.Annotatef(err, "updating tags for %q", to.String(resource.Name))
+}
+
+// updatedTags returns a copy of resourceTags with the given tags set
+// and the named tags removed, and reports whether it differs from
+// resourceTags.
+func updatedTags(resourceTags, set map[string]string, unset []string) (map[string]string, bool) {
+	result := make(map[string]string)
+	for k, v := range resourceTags {
+		result[k] = v
+	}
+	var changed bool
+	for k, v := range set {
+		if old, ok := result[k]; !ok || old != v {
+			result[k] = v
+			changed = true
+		}
+	}
+	for _, k := range unset {
+		if _, ok := result[k]; ok {
+			delete(result, k)
+			changed = true
+		}
+	}
+	return result, changed
+}
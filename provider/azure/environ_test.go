@@ -57,6 +57,8 @@ const (
 	computeAPIVersion = "2016-04-30-preview"
 	networkAPIVersion = "2017-03-01"
 	storageAPIVersion = "2016-12-01"
+
+	computeZonesAPIVersion = "2017-03-30"
 )
 
 var (
@@ -750,6 +752,7 @@ const numExpectedStartInstanceRequests = 4
 type assertStartInstanceRequestsParams struct {
 	autocert            bool
 	availabilitySetName string
+	availabilityZone    string
 	imageReference      *compute.ImageReference
 	vmExtension         *compute.VirtualMachineExtensionProperties
 	diskSizeGB          int
@@ -969,6 +972,13 @@ func (s *environSuite) assertStartInstanceRequests(
 		}
 	}
 
+	vmAPIVersion := computeAPIVersion
+	var vmZones []string
+	if args.availabilityZone != "" {
+		vmAPIVersion = computeZonesAPIVersion
+		vmZones = []string{args.availabilityZone}
+	}
+
 	templateResources = append(templateResources, []armtemplates.Resource{{
 		APIVersion: networkAPIVersion,
 		Type:       "Microsoft.Network/publicIPAddresses",
@@ -989,7 +999,7 @@ func (s *environSuite) assertStartInstanceRequests(
 		},
 		DependsOn: append(nicDependsOn, publicIPAddressId),
 	}, {
-		APIVersion: computeAPIVersion,
+		APIVersion: vmAPIVersion,
 		Type:       "Microsoft.Compute/virtualMachines",
 		Name:       "machine-0",
 		Location:   "westus",
@@ -1007,6 +1017,7 @@ func (s *environSuite) assertStartInstanceRequests(
 			AvailabilitySet: availabilitySetSubResource,
 		},
 		DependsOn: append(vmDependsOn, nicId),
+		Zones:     vmZones,
 	}}...)
 	if args.vmExtension != nil {
 		templateResources = append(templateResources, armtemplates.Resource{
@@ -1436,10 +1447,10 @@ func (s *environSuite) TestStopInstancesStorageAccountKeysError(c *gc.C) {
 func (s *environSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	validator := s.constraintsValidator(c)
 	unsupported, err := validator.Validate(constraints.MustParse(
-		"arch=amd64 tags=foo cpu-power=100 virt-type=kvm",
+		"arch=amd64 tags=foo cpu-power=100 virt-type=kvm interruptible=true max-price=0.05",
 	))
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"tags", "cpu-power", "virt-type"})
+	c.Assert(unsupported, jc.SameContents, []string{"tags", "cpu-power", "virt-type", "interruptible", "max-price"})
 }
 
 func (s *environSuite) TestConstraintsValidatorVocabulary(c *gc.C) {
@@ -1669,6 +1680,103 @@ func (s *environSuite) TestAdoptResources(c *gc.C) {
 	c.Check(gTags[tags.JujuController], gc.Equals, "new-controller")
 }
 
+func (s *environSuite) TestRetagInstance(c *gc.C) {
+	providersResult := makeProvidersResult()
+	resourcesResult := makeResourcesResult()
+
+	// Only the first resource belongs to the instance.
+	res1 := (*resourcesResult.Value)[0]
+	(*res1.Tags)["juju-machine-name"] = to.StringPtr("machine-0")
+	res1.Properties = &map[string]interface{}{"has-properties": true}
+
+	env := s.openEnviron(c)
+
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/providers", providersResult),
+		s.makeSender(".*/resourceGroups/juju-testenv-.*/resources", resourcesResult),
+		s.makeSender(".*/resourcegroups/.*/providers/Beck.Replica/liars/scissor/boxing-day-blues", res1),
+		s.makeSender(".*/resourcegroups/.*/providers/Beck.Replica/liars/scissor/boxing-day-blues", res1),
+	}
+
+	err := env.(environs.ResourceRetagger).RetagInstance(
+		"machine-0", map[string]string{"owner": "ops"}, []string{"something else"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 4)
+	c.Check(s.requests[3].Method, gc.Equals, "PUT")
+
+	req := s.requests[3]
+	data := make([]byte, req.ContentLength)
+	_, err = req.Body.Read(data)
+	c.Assert(err, jc.ErrorIsNil)
+	var resource resources.GenericResource
+	err = json.Unmarshal(data, &resource)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(to.StringMap(*resource.Tags), jc.DeepEquals, map[string]string{
+		tags.JujuController: "old-controller",
+		"juju-machine-name": "machine-0",
+		"owner":             "ops",
+	})
+	c.Check(*resource.Properties, gc.DeepEquals, map[string]interface{}{"has-properties": true})
+}
+
+func (s *environSuite) TestRetagResources(c *gc.C) {
+	providersResult := makeProvidersResult()
+	resourcesResult := makeResourcesResult()
+
+	// The first resource is a volume, owned by a unit; the
+	// second was not created for the model.
+	res1 := (*resourcesResult.Value)[0]
+	(*res1.Tags)[tags.JujuModel] = to.StringPtr(testing.ModelTag.Id())
+	(*res1.Tags)[tags.JujuStorageInstance] = to.StringPtr("data/0")
+	(*res1.Tags)[tags.JujuStorageOwner] = to.StringPtr("mysql/0")
+
+	env := s.openEnviron(c, testing.Attrs{
+		"resource-tags": "cost=${model}-${application}",
+	})
+
+	s.sender = azuretesting.Senders{
+		s.makeSender(".*/resourcegroups/juju-testenv-.*", makeResourceGroupResult()),
+		s.makeSender(".*/resourcegroups/juju-testenv-.*", nil),
+		s.makeSender(".*/providers", providersResult),
+		s.makeSender(".*/resourceGroups/juju-testenv-.*/resources", resourcesResult),
+		s.makeSender(".*/resourcegroups/.*/providers/Beck.Replica/liars/scissor/boxing-day-blues", res1),
+		s.makeSender(".*/resourcegroups/.*/providers/Beck.Replica/liars/scissor/boxing-day-blues", res1),
+	}
+
+	err := env.(environs.ResourceRetagger).RetagResources("new-controller", []string{"something else"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 6)
+
+	readTags := func(ix int, v interface{}) {
+		req := s.requests[ix]
+		c.Check(req.Method, gc.Equals, "PUT")
+		data := make([]byte, req.ContentLength)
+		_, err := req.Body.Read(data)
+		c.Assert(err, jc.ErrorIsNil)
+		err = json.Unmarshal(data, v)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	var group resources.Group
+	readTags(1, &group)
+	c.Check(to.StringMap(*group.Tags), jc.DeepEquals, map[string]string{
+		tags.JujuController: "new-controller",
+		tags.JujuModel:      testing.ModelTag.Id(),
+		"cost":              "testenv-",
+	})
+
+	var resource resources.GenericResource
+	readTags(5, &resource)
+	c.Check(to.StringMap(*resource.Tags), jc.DeepEquals, map[string]string{
+		tags.JujuController:      "new-controller",
+		tags.JujuModel:           testing.ModelTag.Id(),
+		tags.JujuStorageInstance: "data/0",
+		tags.JujuStorageOwner:    "mysql/0",
+		"cost":                   "testenv-mysql",
+	})
+}
+
 func makeProvidersResult() resources.ProviderListResult {
 	providers := []resources.Provider{{
 		Namespace: to.StringPtr("Beck.Replica"),
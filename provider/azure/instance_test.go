@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"path"
 
+	"github.com/Azure/azure-sdk-for-go/arm/compute"
 	"github.com/Azure/azure-sdk-for-go/arm/network"
 	"github.com/Azure/azure-sdk-for-go/arm/resources/resources"
 	"github.com/Azure/go-autorest/autorest/mocks"
@@ -594,6 +595,34 @@ func (s *instanceSuite) TestControllerInstances(c *gc.C) {
 	c.Assert(ids[0], gc.Equals, instance.Id("machine-0"))
 }
 
+func (s *instanceSuite) TestControllerInstancesAvailabilityZones(c *gc.C) {
+	// Machines in availability zones are not in availability sets,
+	// so controllers are identified by their VM tags instead.
+	*s.deployments[0].Properties.Dependencies = []resources.Dependency{{
+		ResourceType: to.StringPtr("Microsoft.Compute/virtualMachines"),
+		DependsOn:    &[]resources.BasicDependency{},
+	}}
+	controllerTags := map[string]*string{"juju-is-controller": to.StringPtr("true")}
+	virtualMachines := []compute.VirtualMachine{{
+		Name: to.StringPtr("machine-0"),
+		Tags: &controllerTags,
+	}, {
+		Name: to.StringPtr("machine-1"),
+	}}
+	virtualMachinesSender := azuretesting.NewSenderWithValue(compute.VirtualMachineListResult{
+		Value: &virtualMachines,
+	})
+	virtualMachinesSender.PathPattern = ".*/virtualMachines"
+	s.sender = azuretesting.Senders{
+		s.getInstancesSender()[0],
+		virtualMachinesSender,
+	}
+	ids, err := s.env.ControllerInstances("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, gc.HasLen, 1)
+	c.Assert(ids[0], gc.Equals, instance.Id("machine-0"))
+}
+
 var internalSecurityGroupPath = path.Join(
 	"/subscriptions", fakeSubscriptionId,
 	"resourceGroups", "juju-testenv-model-"+testing.ModelTag.Id(),
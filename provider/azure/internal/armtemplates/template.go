@@ -38,6 +38,7 @@ type Resource struct {
 	DependsOn  []string          `json:"dependsOn,omitempty"`
 	Properties interface{}       `json:"properties,omitempty"`
 	Resources  []Resource        `json:"resources,omitempty"`
+	Zones      []string          `json:"zones,omitempty"`
 
 	// Non-uniform attributes.
 	StorageSku *storage.Sku `json:"sku,omitempty"`
@@ -0,0 +1,166 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package resourceskus provides a client for the Azure Compute
+// Resource SKUs API, which is not included in the version of the
+// Azure SDK that Juju uses.
+package resourceskus
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/arm/compute"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+// APIVersion is the version of the Resource SKUs API used by Client.
+const APIVersion = "2017-09-01"
+
+// ResourceSku describes a SKU of a compute resource, such as a
+// virtual machine size.
+type ResourceSku struct {
+	// ResourceType is the type of resource the SKU applies
+	// to, e.g. "virtualMachines" or "disks".
+	ResourceType string `json:"resourceType"`
+
+	// Name is the name of the SKU, e.g. "Standard_D2_v2".
+	Name string `json:"name"`
+
+	// Locations holds the locations the SKU is offered in.
+	Locations []string `json:"locations"`
+
+	// LocationInfo describes the availability zones the
+	// SKU is offered in, for each location.
+	LocationInfo []LocationInfo `json:"locationInfo"`
+
+	// Restrictions describes the locations and availability
+	// zones in which the SKU is offered, but may not be used
+	// by the subscription.
+	Restrictions []Restriction `json:"restrictions"`
+}
+
+// LocationInfo describes the availability zones that a SKU is
+// offered in for a location.
+type LocationInfo struct {
+	Location string   `json:"location"`
+	Zones    []string `json:"zones"`
+}
+
+const (
+	// RestrictionTypeLocation is the type of a restriction
+	// on using a SKU anywhere in a location.
+	RestrictionTypeLocation = "Location"
+
+	// RestrictionTypeZone is the type of a restriction on
+	// using a SKU in some availability zones of a location.
+	RestrictionTypeZone = "Zone"
+)
+
+// Restriction describes where a SKU may not be used by the
+// subscription.
+type Restriction struct {
+	// Type is the type of the restriction, RestrictionTypeLocation
+	// or RestrictionTypeZone.
+	Type string `json:"type"`
+
+	// Values holds the locations the restriction applies to.
+	Values []string `json:"values"`
+
+	// RestrictionInfo holds the locations and, for zone
+	// restrictions, the availability zones restricted.
+	RestrictionInfo RestrictionInfo `json:"restrictionInfo"`
+
+	// ReasonCode holds the reason for the restriction, e.g.
+	// "NotAvailableForSubscription".
+	ReasonCode string `json:"reasonCode"`
+}
+
+// RestrictionInfo holds the locations and availability zones that
+// a restriction applies to.
+type RestrictionInfo struct {
+	Locations []string `json:"locations"`
+	Zones     []string `json:"zones"`
+}
+
+// AppliesTo reports whether the restriction applies to the given
+// location, comparing locations after canonicalising them with the
+// given function.
+func (r Restriction) AppliesTo(location string, canonical func(string) string) bool {
+	for _, values := range [][]string{r.Values, r.RestrictionInfo.Locations} {
+		for _, value := range values {
+			if canonical(value) == location {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type listResult struct {
+	Value    []ResourceSku `json:"value"`
+	NextLink string        `json:"nextLink"`
+}
+
+// Client is a client for the Resource SKUs API.
+type Client struct {
+	compute.ManagementClient
+}
+
+// List returns all of the resource SKUs available to the subscription.
+func (client Client) List() ([]ResourceSku, error) {
+	pathParameters := map[string]interface{}{
+		"subscriptionId": autorest.Encode("path", client.SubscriptionID),
+	}
+	queryParameters := map[string]interface{}{
+		"api-version": APIVersion,
+	}
+	req, err := autorest.Prepare(&http.Request{},
+		autorest.AsGet(),
+		autorest.WithBaseURL(client.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/providers/Microsoft.Compute/skus", pathParameters),
+		autorest.WithQueryParameters(queryParameters),
+	)
+	if err != nil {
+		return nil, autorest.NewErrorWithError(err, "resourceskus.Client", "List", nil, "Failure preparing request")
+	}
+
+	var skus []ResourceSku
+	for req != nil {
+		result, err := client.list(req)
+		if err != nil {
+			return nil, err
+		}
+		skus = append(skus, result.Value...)
+		req = nil
+		if result.NextLink != "" {
+			req, err = autorest.Prepare(&http.Request{},
+				autorest.AsGet(),
+				autorest.WithBaseURL(result.NextLink),
+			)
+			if err != nil {
+				return nil, autorest.NewErrorWithError(err, "resourceskus.Client", "List", nil, "Failure preparing next results request")
+			}
+		}
+	}
+	return skus, nil
+}
+
+func (client Client) list(req *http.Request) (result listResult, err error) {
+	resp, err := autorest.SendWithSender(client, req)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "resourceskus.Client", "List", resp, "Failure sending request")
+		return
+	}
+	err = autorest.Respond(
+		resp,
+		client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&result),
+		autorest.ByClosing(),
+	)
+	if err != nil {
+		err = autorest.NewErrorWithError(err, "resourceskus.Client", "List", resp, "Failure responding to request")
+	}
+	return
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/arm/compute"
 	"github.com/Azure/azure-sdk-for-go/arm/disk"
+	"github.com/Azure/azure-sdk-for-go/arm/resources/resources"
 	armstorage "github.com/Azure/azure-sdk-for-go/arm/storage"
 	azurestorage "github.com/Azure/azure-sdk-for-go/storage"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -177,11 +178,37 @@ func (v *azureVolumeSource) CreateVolumes(params []storage.VolumeParams) (_ []st
 
 // createManagedDiskVolumes creates volumes with associated managed disks.
 func (v *azureVolumeSource) createManagedDiskVolumes(params []storage.VolumeParams, results []storage.CreateVolumesResult) {
+	// Disks to be attached to a machine in an availability zone
+	// must be created in the same zone.
+	var attaching bool
+	for i, p := range params {
+		if results[i].Error == nil && p.Attachment != nil {
+			attaching = true
+		}
+	}
+	var zones map[instance.Id]string
+	if attaching {
+		var err error
+		zones, err = v.virtualMachineZones()
+		if err != nil {
+			err = errors.Annotate(err, "getting availability zones")
+			for i := range results {
+				if results[i].Error == nil {
+					results[i].Error = err
+				}
+			}
+			return
+		}
+	}
 	for i, p := range params {
 		if results[i].Error != nil {
 			continue
 		}
-		volume, err := v.createManagedDiskVolume(p)
+		var zone string
+		if p.Attachment != nil {
+			zone = zones[p.Attachment.InstanceId]
+		}
+		volume, err := v.createManagedDiskVolume(p, zone)
 		if err != nil {
 			results[i].Error = err
 			continue
@@ -190,8 +217,9 @@ func (v *azureVolumeSource) createManagedDiskVolumes(params []storage.VolumePara
 	}
 }
 
-// createManagedDiskVolume creates a managed disk.
-func (v *azureVolumeSource) createManagedDiskVolume(p storage.VolumeParams) (*storage.Volume, error) {
+// createManagedDiskVolume creates a managed disk, in the specified
+// availability zone if it is non-empty.
+func (v *azureVolumeSource) createManagedDiskVolume(p storage.VolumeParams, zone string) (*storage.Volume, error) {
 	cfg, err := newAzureStorageConfig(p.Attributes)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -199,6 +227,21 @@ func (v *azureVolumeSource) createManagedDiskVolume(p storage.VolumeParams) (*st
 
 	diskName := p.Tag.String()
 	sizeInGib := mibToGib(p.Size)
+	if zone != "" {
+		if err := v.createZonalManagedDisk(diskName, sizeInGib, zone, cfg.storageType, p.ResourceTags); err != nil {
+			return nil, errors.Annotatef(err, "creating disk for volume %q", p.Tag.Id())
+		}
+		volume := storage.Volume{
+			p.Tag,
+			storage.VolumeInfo{
+				VolumeId:   diskName,
+				Size:       gibToMib(sizeInGib),
+				Persistent: true,
+			},
+		}
+		return &volume, nil
+	}
+
 	diskModel := disk.Model{
 		Name:     to.StringPtr(diskName),
 		Location: to.StringPtr(v.env.location),
@@ -228,6 +271,67 @@ func (v *azureVolumeSource) createManagedDiskVolume(p storage.VolumeParams) (*st
 	return &volume, nil
 }
 
+// virtualMachineZones returns the availability zones of the virtual
+// machines in the model that are in a zone, keyed by instance ID.
+func (v *azureVolumeSource) virtualMachineZones() (map[instance.Id]string, error) {
+	vmsClient := compute.VirtualMachinesClient{v.env.compute}
+	result, err := vmsClient.List(v.env.resourceGroup)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing virtual machines")
+	}
+	zones := make(map[instance.Id]string)
+	if result.Value == nil {
+		return zones, nil
+	}
+	for _, vm := range *result.Value {
+		if zone := toTags(vm.Tags)[jujuAvailabilityZoneTag]; zone != "" {
+			zones[instance.Id(to.String(vm.Name))] = zone
+		}
+	}
+	return zones, nil
+}
+
+// createZonalManagedDisk creates a managed disk in the specified
+// availability zone. The version of the disks API supported by the
+// Azure SDK does not support zones, so we create the disk using a
+// template deployment instead.
+func (v *azureVolumeSource) createZonalManagedDisk(
+	diskName string,
+	sizeInGib uint64,
+	zone string,
+	storageType disk.StorageAccountTypes,
+	resourceTags map[string]string,
+) error {
+	diskTags := make(map[string]string)
+	for k, v := range resourceTags {
+		diskTags[k] = v
+	}
+	diskTags[jujuAvailabilityZoneTag] = zone
+
+	template := armtemplates.Template{Resources: []armtemplates.Resource{{
+		APIVersion: computeZonesAPIVersion,
+		Type:       "Microsoft.Compute/disks",
+		Name:       diskName,
+		Location:   v.env.location,
+		Tags:       diskTags,
+		Properties: &disk.Properties{
+			CreationData: &disk.CreationData{CreateOption: disk.Empty},
+			DiskSizeGB:   to.Int32Ptr(int32(sizeInGib)),
+		},
+		Zones: []string{zone},
+		StorageSku: &armstorage.Sku{
+			Name: armstorage.SkuName(storageType),
+		},
+	}}}
+	deploymentsClient := resources.DeploymentsClient{v.env.resources}
+	return createDeployment(
+		deploymentsClient,
+		v.env.resourceGroup,
+		diskName, // deployment name
+		template,
+	)
+}
+
 // createUnmanagedDiskVolumes creates volumes with associated unmanaged disks (blobs).
 func (v *azureVolumeSource) createUnmanagedDiskVolumes(params []storage.VolumeParams, results []storage.CreateVolumesResult) error {
 	var instanceIds []instance.Id
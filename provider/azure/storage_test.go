@@ -9,6 +9,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/arm/compute"
 	"github.com/Azure/azure-sdk-for-go/arm/disk"
+	"github.com/Azure/azure-sdk-for-go/arm/resources/resources"
 	armstorage "github.com/Azure/azure-sdk-for-go/arm/storage"
 	azurestorage "github.com/Azure/azure-sdk-for-go/storage"
 	autorestazure "github.com/Azure/go-autorest/autorest/azure"
@@ -187,9 +188,15 @@ func (s *storageSuite) TestCreateVolumes(c *gc.C) {
 		return sender
 	}
 
+	// The virtual machines are listed to determine their
+	// availability zones; these machines are not in zones.
+	virtualMachinesSender := azuretesting.NewSenderWithValue(compute.VirtualMachineListResult{})
+	virtualMachinesSender.PathPattern = `.*/Microsoft\.Compute/virtualMachines`
+
 	volumeSource := s.volumeSource(c, false)
 	s.requests = nil
 	s.sender = azuretesting.Senders{
+		virtualMachinesSender,
 		makeSender("volume-0", 32),
 		makeSender("volume-1", 2),
 		makeSender("volume-2", 1),
@@ -222,10 +229,11 @@ func (s *storageSuite) TestCreateVolumes(c *gc.C) {
 	c.Check(results[2].Volume, jc.DeepEquals, makeVolume("2", 1*1024))
 
 	// Validate HTTP request bodies.
-	c.Assert(s.requests, gc.HasLen, 3)
-	c.Assert(s.requests[0].Method, gc.Equals, "PUT") // create volume-0
-	c.Assert(s.requests[1].Method, gc.Equals, "PUT") // create volume-1
-	c.Assert(s.requests[2].Method, gc.Equals, "PUT") // create volume-2
+	c.Assert(s.requests, gc.HasLen, 4)
+	c.Assert(s.requests[0].Method, gc.Equals, "GET") // list virtual machines
+	c.Assert(s.requests[1].Method, gc.Equals, "PUT") // create volume-0
+	c.Assert(s.requests[2].Method, gc.Equals, "PUT") // create volume-1
+	c.Assert(s.requests[3].Method, gc.Equals, "PUT") // create volume-2
 
 	makeDisk := func(name string, size int32) *disk.Model {
 		tags := map[string]*string{
@@ -244,9 +252,85 @@ func (s *storageSuite) TestCreateVolumes(c *gc.C) {
 			},
 		}
 	}
-	assertRequestBody(c, s.requests[0], makeDisk("volume-0", 1))
-	assertRequestBody(c, s.requests[1], makeDisk("volume-1", 2))
-	assertRequestBody(c, s.requests[2], makeDisk("volume-2", 1))
+	assertRequestBody(c, s.requests[1], makeDisk("volume-0", 1))
+	assertRequestBody(c, s.requests[2], makeDisk("volume-1", 2))
+	assertRequestBody(c, s.requests[3], makeDisk("volume-2", 1))
+}
+
+func (s *storageSuite) TestCreateVolumesZonal(c *gc.C) {
+	params := []storage.VolumeParams{{
+		Tag:          names.NewVolumeTag("0"),
+		Size:         1024,
+		Provider:     "azure",
+		ResourceTags: map[string]string{"foo": "bar"},
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Provider:   "azure",
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: instance.Id("machine-0"),
+			},
+			Volume: names.NewVolumeTag("0"),
+		},
+	}}
+
+	vmTags := map[string]*string{"juju-availability-zone": to.StringPtr("2")}
+	virtualMachines := []compute.VirtualMachine{{
+		Name: to.StringPtr("machine-0"),
+		Tags: &vmTags,
+	}}
+	virtualMachinesSender := azuretesting.NewSenderWithValue(compute.VirtualMachineListResult{
+		Value: &virtualMachines,
+	})
+	virtualMachinesSender.PathPattern = `.*/Microsoft\.Compute/virtualMachines`
+	deploymentSender := azuretesting.NewSenderWithValue(&resources.DeploymentExtended{})
+	deploymentSender.PathPattern = `.*/deployments/volume-0`
+
+	volumeSource := s.volumeSource(c, false)
+	s.requests = nil
+	s.sender = azuretesting.Senders{
+		virtualMachinesSender,
+		deploymentSender,
+	}
+
+	results, err := volumeSource.CreateVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Check(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		Tag: names.NewVolumeTag("0"),
+		VolumeInfo: storage.VolumeInfo{
+			Size:       1024,
+			VolumeId:   "volume-0",
+			Persistent: true,
+		},
+	})
+
+	// The disk is created in the machine's zone by deploying
+	// a template, as the disks API does not support zones.
+	c.Assert(s.requests, gc.HasLen, 2)
+	c.Assert(s.requests[0].Method, gc.Equals, "GET") // list virtual machines
+	c.Assert(s.requests[1].Method, gc.Equals, "PUT") // create deployment
+
+	var deployment resources.Deployment
+	unmarshalRequestBody(c, s.requests[1], &deployment)
+	resourcesList := (*deployment.Properties.Template)["resources"].([]interface{})
+	c.Assert(resourcesList, gc.HasLen, 1)
+	c.Assert(resourcesList[0], jc.DeepEquals, map[string]interface{}{
+		"apiVersion": "2017-03-30",
+		"type":       "Microsoft.Compute/disks",
+		"name":       "volume-0",
+		"location":   "westus",
+		"tags": map[string]interface{}{
+			"foo":                    "bar",
+			"juju-availability-zone": "2",
+		},
+		"properties": map[string]interface{}{
+			"creationData": map[string]interface{}{"createOption": "Empty"},
+			"diskSizeGB":   float64(1),
+		},
+		"zones": []interface{}{"2"},
+		"sku":   map[string]interface{}{"name": "Standard_LRS"},
+	})
 }
 
 func (s *storageSuite) TestCreateVolumesLegacy(c *gc.C) {
@@ -80,8 +80,8 @@ func (s *environSuite) TestUnsupportedConstraints(c *gc.C) {
 	c.Check(validator, gc.NotNil)
 
 	unsupported, err := validator.Validate(constraints.MustParse(
-		"arch=amd64 tags=foo cpu-power=100 virt-type=kvm",
+		"arch=amd64 tags=foo cpu-power=100 virt-type=kvm zones=az1 interruptible=true max-price=0.05",
 	))
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"tags", "virt-type"})
+	c.Assert(unsupported, jc.SameContents, []string{"tags", "virt-type", "zones", "interruptible", "max-price"})
 }
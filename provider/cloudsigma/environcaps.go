@@ -12,6 +12,9 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Zones,
+	constraints.Interruptible,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator returns a Validator instance which
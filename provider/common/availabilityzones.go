@@ -7,6 +7,7 @@ import (
 	"sort"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
@@ -196,3 +197,41 @@ func ValidateAvailabilityZone(env ZonedEnviron, zone string) error {
 	}
 	return errors.NotValidf("availability zone %q", zone)
 }
+
+// ValidateZonesConstraint returns an error if the zones constraint in
+// the given parameters names an unknown availability zone, or excludes
+// all of the zones implied by the placement directive and volumes.
+func ValidateZonesConstraint(env ZonedEnviron, args environs.PrecheckInstanceParams) error {
+	if !args.Constraints.HasZones() {
+		return nil
+	}
+	zones, err := env.AvailabilityZones()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	known := set.NewStrings()
+	for _, zone := range zones {
+		known.Add(zone.Name())
+	}
+	allowed := set.NewStrings(*args.Constraints.Zones...)
+	for _, name := range allowed.SortedValues() {
+		if !known.Contains(name) {
+			return errors.NotValidf("availability zone %q in zones constraint", name)
+		}
+	}
+
+	derivedZones, err := env.DeriveAvailabilityZones(environs.StartInstanceParams{
+		Placement:         args.Placement,
+		VolumeAttachments: args.VolumeAttachments,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(derivedZones) > 0 && allowed.Intersection(set.NewStrings(derivedZones...)).IsEmpty() {
+		return errors.Errorf(
+			"availability zones %q are not in zones constraint %q",
+			derivedZones, *args.Constraints.Zones,
+		)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/common"
@@ -182,6 +183,44 @@ func (s *AvailabilityZoneSuite) TestValidateAvailabilityZone(c *gc.C) {
 	}
 }
 
+func (s *AvailabilityZoneSuite) TestValidateZonesConstraint(c *gc.C) {
+	var derived []string
+	s.PatchValue(&s.env.deriveAvailabilityZones, func(args environs.StartInstanceParams) ([]string, error) {
+		c.Assert(args.Placement, gc.Equals, "zone=az1")
+		return derived, nil
+	})
+	args := environs.PrecheckInstanceParams{
+		Placement:   "zone=az1",
+		Constraints: constraints.MustParse("zones=az0,az1"),
+	}
+	err := common.ValidateZonesConstraint(&s.env, args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	derived = []string{"az1"}
+	err = common.ValidateZonesConstraint(&s.env, args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	derived = []string{"az2"}
+	err = common.ValidateZonesConstraint(&s.env, args)
+	c.Assert(err, gc.ErrorMatches, `availability zones \["az2"\] are not in zones constraint \["az0" "az1"\]`)
+
+	args.Constraints = constraints.MustParse("zones=az1,az3")
+	err = common.ValidateZonesConstraint(&s.env, args)
+	c.Assert(err, gc.ErrorMatches, `availability zone "az3" in zones constraint not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *AvailabilityZoneSuite) TestValidateZonesConstraintNoZones(c *gc.C) {
+	s.PatchValue(&s.env.availabilityZones, func() ([]common.AvailabilityZone, error) {
+		c.Fatalf("unexpected call to AvailabilityZones")
+		return nil, nil
+	})
+	err := common.ValidateZonesConstraint(&s.env, environs.PrecheckInstanceParams{
+		Constraints: constraints.MustParse("zones="),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *AvailabilityZoneSuite) TestDistributeInstancesGroup(c *gc.C) {
 	expectedGroup := []instance.Id{"0", "1", "2"}
 	var called bool
@@ -804,6 +804,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 				MongoSession:     session,
 				NewPolicy:        estate.newStatePolicy,
 				AdminPassword:    icfg.Controller.MongoInfo.Password,
+				SecretsKey:       testing.SecretsKey,
 			})
 			if err != nil {
 				return err
@@ -80,6 +80,8 @@ type environ struct {
 
 var _ environs.Environ = (*environ)(nil)
 var _ environs.Networking = (*environ)(nil)
+var _ environs.InstanceTagger = (*environ)(nil)
+var _ environs.ResourceRetagger = (*environ)(nil)
 
 func (e *environ) Config() *config.Config {
 	return e.ecfg().Config
@@ -182,6 +184,16 @@ func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
+// validateMaxPrice returns an error if the constraints set a maximum
+// price without asking for interruptible capacity, since only spot
+// instances are bid for.
+func validateMaxPrice(cons constraints.Value) error {
+	if cons.HasMaxPrice() && !cons.HasInterruptible() {
+		return errors.NotValidf("max-price constraint without interruptible=true")
+	}
+	return nil
+}
+
 func archMatches(arches []string, arch *string) bool {
 	if arch == nil {
 		return true
@@ -326,6 +338,12 @@ func (e *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error {
 	); err != nil {
 		return errors.Trace(err)
 	}
+	if err := common.ValidateZonesConstraint(e, args); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateMaxPrice(args.Constraints); err != nil {
+		return errors.Trace(err)
+	}
 	if !args.Constraints.HasInstanceType() {
 		return nil
 	}
@@ -414,6 +432,10 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 		}
 	}()
 
+	if err := validateMaxPrice(args.Constraints); err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
+
 	callback(status.Allocating, "Verifying availability zone", nil)
 
 	// Verify the provided availability zone to start the instance in.  It's
@@ -577,7 +599,16 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 	}
 
 	callback(status.Allocating, fmt.Sprintf("Trying to start instance in availability zone %q", availabilityZone), nil)
-	instResp, err = runInstances(e.ec2, runArgs, callback)
+	if args.Constraints.HasInterruptible() {
+		var maxPrice string
+		if args.Constraints.HasMaxPrice() {
+			maxPrice = *args.Constraints.MaxPrice
+		}
+		logger.Infof("requesting spot instance (max price %q)", maxPrice)
+		instResp, err = runSpotInstances(e.ec2, runArgs, maxPrice, callback)
+	} else {
+		instResp, err = runInstances(e.ec2, runArgs, callback)
+	}
 	if err != nil {
 		err := errors.Annotate(err, "cannot run instances")
 		if !isZoneOrSubnetConstrainedError(err) {
@@ -615,12 +646,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 
 	// Tag the machine's root EBS volume, if it has one.
 	if inst.Instance.RootDeviceType == "ebs" {
-		cfg := e.Config()
-		tags := tags.ResourceTags(
-			names.NewModelTag(cfg.UUID()),
-			names.NewControllerTag(args.ControllerUUID),
-			cfg,
-		)
+		tags := rootDiskTags(args.InstanceConfig.Tags)
 		tags[tagName] = instanceName + "-root"
 		if err := tagRootDisk(e.ec2, tags, inst.Instance); err != nil {
 			return nil, common.ZoneIndependentError(
@@ -759,7 +785,7 @@ func volumeAttachmentsZone(ec2 *ec2.EC2, attachments []storage.VolumeAttachmentP
 // with the given tags. tagResources will retry for a short period of time
 // if it receives a *.NotFound error response from EC2.
 func tagResources(e *ec2.EC2, tags map[string]string, resourceIds ...string) error {
-	if len(tags) == 0 {
+	if len(tags) == 0 || len(resourceIds) == 0 {
 		return nil
 	}
 	ec2Tags := make([]ec2.Tag, 0, len(tags))
@@ -780,18 +806,9 @@ func tagRootDisk(e *ec2.EC2, tags map[string]string, inst *ec2.Instance) error {
 	if len(tags) == 0 {
 		return nil
 	}
-	findVolumeId := func(inst *ec2.Instance) string {
-		for _, m := range inst.BlockDeviceMappings {
-			if m.DeviceName != inst.RootDeviceName {
-				continue
-			}
-			return m.VolumeId
-		}
-		return ""
-	}
 	// Wait until the instance has an associated EBS volume in the
 	// block-device-mapping.
-	volumeId := findVolumeId(inst)
+	volumeId := rootDiskVolumeId(inst)
 	// TODO(katco): 2016-08-09: lp:1611427
 	waitRootDiskAttempt := utils.AttemptStrategy{
 		Total: 5 * time.Minute,
@@ -809,7 +826,7 @@ func tagRootDisk(e *ec2.EC2, tags map[string]string, inst *ec2.Instance) error {
 		}
 		if len(resp.Reservations) > 0 && len(resp.Reservations[0].Instances) > 0 {
 			inst = &resp.Reservations[0].Instances[0]
-			volumeId = findVolumeId(inst)
+			volumeId = rootDiskVolumeId(inst)
 		}
 	}
 	if volumeId == "" {
@@ -818,6 +835,19 @@ func tagRootDisk(e *ec2.EC2, tags map[string]string, inst *ec2.Instance) error {
 	return tagResources(e, tags, volumeId)
 }
 
+// rootDiskVolumeId returns the ID of the EBS volume that is the root
+// disk of the given instance, or "" if it has none, or none is
+// associated with it yet.
+func rootDiskVolumeId(inst *ec2.Instance) string {
+	for _, m := range inst.BlockDeviceMappings {
+		if m.DeviceName != inst.RootDeviceName {
+			continue
+		}
+		return m.VolumeId
+	}
+	return ""
+}
+
 var runInstances = _runInstances
 
 // runInstances calls ec2.RunInstances for a fixed number of attempts until
@@ -1328,22 +1358,29 @@ func (e *environ) allModelVolumes(includeRootDisks bool) ([]string, error) {
 	return listVolumes(e.ec2, filter, includeRootDisks)
 }
 
-func rulesToIPPerms(rules []network.IngressRule) []ec2.IPPerm {
-	ipPerms := make([]ec2.IPPerm, len(rules))
-	for i, r := range rules {
-		ipPerms[i] = ec2.IPPerm{
+// rulesToIPPerms returns the EC2 permissions for the given rules.
+// The EC2 client only supports IPv4 source ranges, so an error
+// satisfying errors.IsNotSupported is returned for IPv6 source ranges.
+func rulesToIPPerms(rules []network.IngressRule) ([]ec2.IPPerm, error) {
+	ipPerms := make([]ec2.IPPerm, 0, len(rules))
+	for _, r := range rules {
+		ipPerm := ec2.IPPerm{
 			Protocol: r.Protocol,
 			FromPort: r.FromPort,
 			ToPort:   r.ToPort,
 		}
 		if len(r.SourceCIDRs) == 0 {
-			ipPerms[i].SourceIPs = []string{defaultRouteCIDRBlock}
-		} else {
-			ipPerms[i].SourceIPs = make([]string, len(r.SourceCIDRs))
-			copy(ipPerms[i].SourceIPs, r.SourceCIDRs)
+			ipPerm.SourceIPs = []string{defaultRouteCIDRBlock}
+		}
+		for _, cidr := range r.SourceCIDRs {
+			if ip, _, err := net.ParseCIDR(cidr); err == nil && ip.To4() == nil {
+				return nil, errors.NotSupportedf("IPv6 source range %q for ports %v", cidr, r.PortRange)
+			}
+			ipPerm.SourceIPs = append(ipPerm.SourceIPs, cidr)
 		}
+		ipPerms = append(ipPerms, ipPerm)
 	}
-	return ipPerms
+	return ipPerms, nil
 }
 
 func (e *environ) openPortsInGroup(name string, rules []network.IngressRule) error {
@@ -1355,10 +1392,13 @@ func (e *environ) openPortsInGroup(name string, rules []network.IngressRule) err
 	if err != nil {
 		return err
 	}
-	ipPerms := rulesToIPPerms(rules)
+	ipPerms, err := rulesToIPPerms(rules)
+	if err != nil {
+		return errors.Annotate(err, "cannot open ports")
+	}
 	_, err = e.ec2.AuthorizeSecurityGroup(g, ipPerms)
 	if err != nil && ec2ErrCode(err) == "InvalidPermission.Duplicate" {
-		if len(rules) == 1 {
+		if len(ipPerms) == 1 {
 			return nil
 		}
 		// If there's more than one port and we get a duplicate error,
@@ -1390,7 +1430,11 @@ func (e *environ) closePortsInGroup(name string, rules []network.IngressRule) er
 	if err != nil {
 		return err
 	}
-	_, err = e.ec2.RevokeSecurityGroup(g, rulesToIPPerms(rules))
+	ipPerms, err := rulesToIPPerms(rules)
+	if err != nil {
+		return errors.Annotate(err, "cannot close ports")
+	}
+	_, err = e.ec2.RevokeSecurityGroup(g, ipPerms)
 	if err != nil {
 		return fmt.Errorf("cannot close ports: %v", err)
 	}
@@ -1785,7 +1829,7 @@ func (e *environ) ensureGroup(controllerUUID, name string, perms []ec2.IPPerm) (
 		tags := tags.ResourceTags(
 			names.NewModelTag(cfg.UUID()),
 			names.NewControllerTag(controllerUUID),
-			cfg,
+			tags.Templated(cfg, tags.TemplateValues{Model: cfg.Name()}),
 		)
 		if err := tagResources(e.ec2, tags, g.Id); err != nil {
 			return g, errors.Annotate(err, "tagging security group")
@@ -0,0 +1,171 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+)
+
+// TagInstance implements environs.InstanceTagger.
+// The instance's root disk is given the same tags.
+func (e *environ) TagInstance(id instance.Id, tags map[string]string) error {
+	return e.retagInstance(id, tags, nil)
+}
+
+// RetagInstance implements environs.ResourceRetagger.
+func (e *environ) RetagInstance(id instance.Id, instanceTags map[string]string, removed []string) error {
+	return e.retagInstance(id, instanceTags, tags.Unset(instanceTags, e.Config(), removed))
+}
+
+func (e *environ) retagInstance(id instance.Id, instanceTags map[string]string, unset []string) error {
+	resp, err := e.ec2.Instances([]string{string(id)}, nil)
+	if err != nil {
+		return errors.Annotate(err, "getting instance")
+	}
+	var volumeId string
+	for _, r := range resp.Reservations {
+		for i, inst := range r.Instances {
+			if inst.InstanceId == string(id) && inst.RootDeviceType == "ebs" {
+				volumeId = rootDiskVolumeId(&r.Instances[i])
+			}
+		}
+	}
+
+	if err := tagResources(e.ec2, instanceTags, string(id)); err != nil {
+		return errors.Annotate(err, "tagging instance")
+	}
+	resourceIds := []string{string(id)}
+	if volumeId != "" {
+		if err := tagResources(e.ec2, rootDiskTags(instanceTags), volumeId); err != nil {
+			return errors.Annotate(err, "tagging root disk")
+		}
+		resourceIds = append(resourceIds, volumeId)
+	}
+	if err := untagResources(e.ec2, unset, resourceIds...); err != nil {
+		return errors.Annotate(err, "untagging instance")
+	}
+	return nil
+}
+
+// RetagResources implements environs.ResourceRetagger. The model's
+// security groups and volumes are retagged; root disks are retagged
+// along with their instances.
+func (e *environ) RetagResources(controllerUUID string, removed []string) error {
+	cfg := e.Config()
+	modelTag := names.NewModelTag(cfg.UUID())
+	controllerTag := names.NewControllerTag(controllerUUID)
+
+	groupIds, err := e.modelSecurityGroupIDs()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	groupTags := tags.ResourceTags(modelTag, controllerTag, tags.Templated(cfg, tags.TemplateValues{
+		Model: cfg.Name(),
+	}))
+	if err := tagResources(e.ec2, groupTags, groupIds...); err != nil {
+		return errors.Annotate(err, "tagging security groups")
+	}
+	if err := untagResources(e.ec2, tags.Unset(groupTags, cfg, removed), groupIds...); err != nil {
+		return errors.Annotate(err, "untagging security groups")
+	}
+
+	filter := ec2.NewFilter()
+	e.addModelFilter(filter)
+	resp, err := e.ec2.Volumes(nil, filter)
+	if err != nil {
+		return errors.Annotate(err, "listing volumes")
+	}
+	for _, vol := range resp.Volumes {
+		if isRootDisk(vol) {
+			continue
+		}
+		var owner string
+		for _, tag := range vol.Tags {
+			if tag.Key == tags.JujuStorageOwner {
+				owner = tag.Value
+			}
+		}
+		volumeTags := tags.ResourceTags(modelTag, controllerTag, tags.Templated(
+			cfg, tags.StorageTemplateValues(cfg.Name(), owner),
+		))
+		if err := tagResources(e.ec2, volumeTags, vol.Id); err != nil {
+			return errors.Annotatef(err, "tagging volume %q", vol.Id)
+		}
+		if err := untagResources(e.ec2, tags.Unset(volumeTags, cfg, removed), vol.Id); err != nil {
+			return errors.Annotatef(err, "untagging volume %q", vol.Id)
+		}
+	}
+	return nil
+}
+
+// rootDiskTags returns the tags for the root disk of an instance with
+// the given tags: the instance's user-defined tags, whose placeholders
+// have the instance's values, and the model and controller tags.
+func rootDiskTags(instanceTags map[string]string) map[string]string {
+	rootTags := make(map[string]string)
+	for k, v := range instanceTags {
+		switch {
+		case k == tags.JujuModel, k == tags.JujuController:
+			// The root disk belongs to the same model and
+			// controller as the instance, but the other
+			// Juju tags describe the machine itself.
+		case k == tagName, strings.HasPrefix(k, tags.JujuTagPrefix):
+			continue
+		}
+		rootTags[k] = v
+	}
+	return rootTags
+}
+
+// untagResources removes the tags with the given names from each of
+// the specified resources.
+//
+// The EC2 client does not support deleting tags, so the request is
+// made with a client of its own, whose signer turns the CreateTags
+// request the client makes into a DeleteTags request.
+func untagResources(e *ec2.EC2, keys []string, resourceIds ...string) error {
+	if len(keys) == 0 || len(resourceIds) == 0 {
+		return nil
+	}
+	client := ec2.New(e.Auth, e.Region, untagSigner(e.Sign))
+	ec2Tags := make([]ec2.Tag, len(keys))
+	for i, key := range keys {
+		ec2Tags[i] = ec2.Tag{Key: key}
+	}
+	_, err := client.CreateTags(resourceIds, ec2Tags)
+	return err
+}
+
+var tagValueParam = regexp.MustCompile(`^Tag\.[0-9]+\.Value$`)
+
+// untagSigner returns an aws.Signer that turns CreateTags requests
+// into DeleteTags requests for the same tag keys, before signing them
+// with sign. Tag values are left out, so that the tags are deleted
+// whatever their values. The signer refuses to sign any other request.
+func untagSigner(sign aws.Signer) aws.Signer {
+	return func(req *http.Request, auth aws.Auth) error {
+		query := req.URL.Query()
+		if action := query.Get("Action"); action != "CreateTags" {
+			return errors.Errorf("cannot make %q request with untagging client", action)
+		}
+		query.Set("Action", "DeleteTags")
+		for key := range query {
+			if tagValueParam.MatchString(key) {
+				query.Del(key)
+			}
+		}
+		req.URL.RawQuery = query.Encode()
+		return sign(req, auth)
+	}
+}
@@ -159,11 +159,20 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
-		ipperms := rulesToIPPerms(t.rules)
+		ipperms, err := rulesToIPPerms(t.rules)
+		c.Assert(err, jc.ErrorIsNil)
 		c.Assert(ipperms, gc.DeepEquals, t.expected)
 	}
 }
 
+func (*Suite) TestPortsToIPPermsIPv6(c *gc.C) {
+	_, err := rulesToIPPerms([]network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0", "::/0"),
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `IPv6 source range "::/0" for ports 80/tcp not supported`)
+}
+
 // These Support checks are currently valid with a 'nil' environ pointer. If
 // that changes, the tests will need to be updated. (we know statically what is
 // supported.)
@@ -60,6 +60,7 @@ var (
 	GetBlockDeviceMappings   = getBlockDeviceMappings
 	IsVPCNotUsableError      = isVPCNotUsableError
 	IsVPCNotRecommendedError = isVPCNotRecommendedError
+	UntagSigner              = untagSigner
 )
 
 const VPCIDNone = vpcIDNone
@@ -5,6 +5,7 @@ package ec2_test
 
 import (
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
@@ -1115,6 +1116,53 @@ func (t *localServerSuite) testStartInstanceAvailZoneAllConstrained(c *gc.C, run
 	c.Assert(errors.Details(err), jc.Contains, runInstancesError.Message)
 }
 
+func (t *localServerSuite) TestStartInstanceInterruptible(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+
+	var query url.Values
+	var signErr error
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances, c environs.StatusCallbackFunc) (*amzec2.RunInstancesResp, error) {
+		// Sign a request with the client's signer to
+		// see the parameters it adds to RunInstances.
+		req, err := http.NewRequest("GET", "https://ec2.invalid/?Action=RunInstances", nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.Sign(req, e.Auth); err != nil {
+			return nil, err
+		}
+		query = req.URL.Query()
+
+		// Other requests must not be made with the spot client.
+		req, err = http.NewRequest("GET", "https://ec2.invalid/?Action=DescribeInstances", nil)
+		if err != nil {
+			return nil, err
+		}
+		signErr = e.Sign(req, e.Auth)
+		return nil, errors.New("no capacity")
+	})
+
+	params := environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		StatusCallback: fakeCallback,
+		Constraints:    constraints.MustParse("interruptible=true max-price=0.05"),
+	}
+	_, err := testing.StartInstanceWithParams(env, "1", params)
+	c.Assert(err, gc.ErrorMatches, ".*cannot run instances: no capacity")
+	c.Assert(query.Get("InstanceMarketOptions.MarketType"), gc.Equals, "spot")
+	c.Assert(query.Get("InstanceMarketOptions.SpotOptions.SpotInstanceType"), gc.Equals, "one-time")
+	c.Assert(query.Get("InstanceMarketOptions.SpotOptions.MaxPrice"), gc.Equals, "0.05")
+	c.Assert(query.Get("Version"), gc.Equals, "2016-11-15")
+	c.Assert(signErr, gc.ErrorMatches, `cannot make "DescribeInstances" request with spot instance client`)
+
+	params.Constraints = constraints.MustParse("interruptible=false")
+	_, err = testing.StartInstanceWithParams(env, "1", params)
+	c.Assert(err, gc.ErrorMatches, ".*cannot run instances: no capacity")
+	c.Assert(query.Get("InstanceMarketOptions.MarketType"), gc.Equals, "")
+	c.Assert(query.Get("Version"), gc.Equals, "")
+	c.Assert(signErr, jc.ErrorIsNil)
+}
+
 // addTestingSubnets adds a testing default VPC with 3 subnets in the EC2 test
 // server: 2 of the subnets are in the "test-available" AZ, the remaining - in
 // "test-unavailable". Returns a slice with the IDs of the created subnets and
@@ -1434,6 +1482,22 @@ func (t *localServerSuite) TestPrecheckInstanceValidInstanceType(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (t *localServerSuite) TestPrecheckInstanceMaxPriceWithoutInterruptible(c *gc.C) {
+	env := t.Prepare(c)
+	for _, cons := range []string{"max-price=0.05", "interruptible=false max-price=0.05"} {
+		err := env.PrecheckInstance(environs.PrecheckInstanceParams{
+			Series:      series.LatestLts(),
+			Constraints: constraints.MustParse(cons),
+		})
+		c.Assert(err, gc.ErrorMatches, `max-price constraint without interruptible=true not valid`)
+	}
+	err := env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Series:      series.LatestLts(),
+		Constraints: constraints.MustParse("interruptible=true max-price=0.05"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (t *localServerSuite) TestPrecheckInstanceInvalidInstanceType(c *gc.C) {
 	env := t.Prepare(c)
 	cons := constraints.MustParse("instance-type=m1.invalid")
@@ -1755,6 +1819,121 @@ func (t *localServerSuite) TestInstanceTags(c *gc.C) {
 	})
 }
 
+func (t *localServerSuite) TestTagInstance(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+
+	instances, err := env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+
+	err = env.(environs.InstanceTagger).TagInstance(instances[0].Id(), map[string]string{
+		"owner": "ops",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	instances, err = env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	ec2Inst := ec2.InstanceEC2(instances[0])
+	c.Assert(ec2Inst.Tags, jc.SameContents, []amzec2.Tag{
+		{"Name", "juju-sample-machine-0"},
+		{"juju-model-uuid", coretesting.ModelTag.Id()},
+		{"juju-controller-uuid", t.ControllerUUID},
+		{"juju-is-controller", "true"},
+		{"owner", "ops"},
+	})
+
+	// The instance's root disk is given the same tags.
+	resp, err := ec2.EnvironEC2(env).Volumes(nil, makeFilter("tag:owner", "ops"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Volumes, gc.HasLen, 1)
+	c.Assert(resp.Volumes[0].Tags, jc.SameContents, []amzec2.Tag{
+		{"Name", "juju-sample-machine-0-root"},
+		{"juju-model-uuid", coretesting.ModelTag.Id()},
+		{"juju-controller-uuid", t.ControllerUUID},
+		{"owner", "ops"},
+	})
+}
+
+func (t *localServerSuite) TestRetagResources(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	instances, err := env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+
+	ebsProvider, err := env.StorageProvider(ec2.EBS_ProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	vs, err := ebsProvider.VolumeSource(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeResults, err := vs.CreateVolumes([]storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1024,
+		Provider: ec2.EBS_ProviderType,
+		ResourceTags: map[string]string{
+			tags.JujuController:   t.ControllerUUID,
+			tags.JujuModel:        coretesting.ModelTag.Id(),
+			tags.JujuStorageOwner: "mysql/0",
+		},
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				InstanceId: instances[0].Id(),
+			},
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeResults, gc.HasLen, 1)
+	c.Assert(volumeResults[0].Error, jc.ErrorIsNil)
+
+	cfg, err := env.Config().Apply(map[string]interface{}{
+		"resource-tags": "cost=${model}-${application}",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = env.(environs.ResourceRetagger).RetagResources(t.ControllerUUID, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ec2conn := ec2.EnvironEC2(env)
+	groupIds, err := ec2.AllModelGroups(env)
+	c.Assert(err, jc.ErrorIsNil)
+	groups, err := ec2conn.SecurityGroups(nil, makeFilter("tag:cost", "sample-"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups.Groups, gc.HasLen, len(groupIds))
+
+	volumes, err := ec2conn.Volumes(nil, makeFilter("tag:cost", "sample-mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes.Volumes, gc.HasLen, 1)
+	c.Assert(volumes.Volumes[0].Id, gc.Equals, volumeResults[0].Volume.VolumeId)
+
+	// Root disks are retagged along with their instances.
+	volumes, err = ec2conn.Volumes(nil, makeFilter("tag:cost", "sample-"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes.Volumes, gc.HasLen, 0)
+}
+
+func (t *localServerSuite) TestUntagSigner(c *gc.C) {
+	var signed *http.Request
+	sign := ec2.UntagSigner(func(req *http.Request, auth aws.Auth) error {
+		signed = req
+		return nil
+	})
+
+	req, err := http.NewRequest("GET", "https://ec2.invalid/?Action=CreateTags&ResourceId.1=i-0&Tag.1.Key=owner&Tag.1.Value=", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sign(req, aws.Auth{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(signed.URL.Query(), jc.DeepEquals, url.Values{
+		"Action":       {"DeleteTags"},
+		"ResourceId.1": {"i-0"},
+		"Tag.1.Key":    {"owner"},
+	})
+
+	req, err = http.NewRequest("GET", "https://ec2.invalid/?Action=DescribeInstances", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sign(req, aws.Auth{})
+	c.Assert(err, gc.ErrorMatches, `cannot make "DescribeInstances" request with untagging client`)
+}
+
 func (t *localServerSuite) TestRootDiskTags(c *gc.C) {
 	env := t.prepareAndBootstrap(c)
 
@@ -0,0 +1,53 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+)
+
+// spotAPIVersion is the version of the EC2 API that supports
+// requesting spot capacity with RunInstances.
+const spotAPIVersion = "2016-11-15"
+
+// runSpotInstances runs instances as runInstances does, but asks for
+// one-time spot capacity, at no more than maxPrice dollars per hour
+// if maxPrice is non-empty.
+//
+// The EC2 client does not support instance market options, so the
+// RunInstances request is made with a client of its own, whose signer
+// adds them; the environ's client is left untouched, and continues to
+// use the API version the EC2 package was written against.
+func runSpotInstances(e *ec2.EC2, ri *ec2.RunInstances, maxPrice string, c environs.StatusCallbackFunc) (*ec2.RunInstancesResp, error) {
+	client := ec2.New(e.Auth, e.Region, spotSigner(e.Sign, maxPrice))
+	return runInstances(client, ri, c)
+}
+
+// spotSigner returns an aws.Signer that adds spot instance market
+// options to RunInstances requests before signing them with sign.
+// The signer refuses to sign any other request, so that the newer
+// API version it requires is only ever used for RunInstances.
+func spotSigner(sign aws.Signer, maxPrice string) aws.Signer {
+	return func(req *http.Request, auth aws.Auth) error {
+		query := req.URL.Query()
+		if action := query.Get("Action"); action != "RunInstances" {
+			return errors.Errorf("cannot make %q request with spot instance client", action)
+		}
+		query.Set("Version", spotAPIVersion)
+		query.Set("InstanceMarketOptions.MarketType", "spot")
+		query.Set("InstanceMarketOptions.SpotOptions.SpotInstanceType", "one-time")
+		query.Set("InstanceMarketOptions.SpotOptions.InstanceInterruptionBehavior", "terminate")
+		if maxPrice != "" {
+			query.Set("InstanceMarketOptions.SpotOptions.MaxPrice", maxPrice)
+		}
+		req.URL.RawQuery = query.Encode()
+		return sign(req, auth)
+	}
+}
@@ -176,6 +176,7 @@ func (env *environ) newRawInstance(args environs.StartInstanceParams, spec *inst
 		Metadata:          metadata,
 		Tags:              tags,
 		AvailabilityZone:  args.AvailabilityZone,
+		Preemptible:       args.Constraints.HasInterruptible(),
 		// Network is omitted (left empty).
 	})
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/common"
 )
 
 // PrecheckInstance verifies that the provided series and constraints
@@ -20,6 +21,9 @@ func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error
 	if _, err := env.instancePlacementZone(args.Placement, volumeAttachmentsZone); err != nil {
 		return errors.Trace(err)
 	}
+	if err := common.ValidateZonesConstraint(env, args); err != nil {
+		return errors.Trace(err)
+	}
 
 	if args.Constraints.HasInstanceType() {
 		if !checkInstanceType(args.Constraints) {
@@ -33,6 +37,8 @@ func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	// Preemptible instances have a fixed price.
+	constraints.MaxPrice,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
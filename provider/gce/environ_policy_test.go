@@ -169,11 +169,11 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	validator, err := s.Env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
 
-	cons := constraints.MustParse("arch=amd64 tags=foo virt-type=kvm")
+	cons := constraints.MustParse("arch=amd64 tags=foo virt-type=kvm interruptible=true max-price=0.05")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(unsupported, jc.SameContents, []string{"tags", "virt-type"})
+	c.Check(unsupported, jc.SameContents, []string{"tags", "virt-type", "max-price"})
 }
 
 func (s *environPolSuite) TestConstraintsValidatorVocabInstType(c *gc.C) {
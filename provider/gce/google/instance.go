@@ -52,6 +52,11 @@ type InstanceSpec struct {
 	// AvailabilityZone holds the name of the availability zone in which
 	// to create the instance.
 	AvailabilityZone string
+
+	// Preemptible indicates whether the instance is preemptible, in
+	// which case GCE may stop it at any time, and will stop it after
+	// 24 hours.
+	Preemptible bool
 }
 
 func (is InstanceSpec) raw() *compute.Instance {
@@ -61,10 +66,27 @@ func (is InstanceSpec) raw() *compute.Instance {
 		NetworkInterfaces: is.networkInterfaces(),
 		Metadata:          packMetadata(is.Metadata),
 		Tags:              &compute.Tags{Items: is.Tags},
+		Scheduling:        is.scheduling(),
 		// MachineType is set in the addInstance call.
 	}
 }
 
+// scheduling returns the scheduling options for the instance, or
+// nil if the default options should be used.
+func (is InstanceSpec) scheduling() *compute.Scheduling {
+	if !is.Preemptible {
+		return nil
+	}
+	// Preemptible instances cannot be restarted automatically,
+	// or live migrated for host maintenance.
+	automaticRestart := false
+	return &compute.Scheduling{
+		Preemptible:       true,
+		AutomaticRestart:  &automaticRestart,
+		OnHostMaintenance: "TERMINATE",
+	}
+}
+
 // Summary builds an InstanceSummary based on the spec and returns it.
 func (is InstanceSpec) Summary() InstanceSummary {
 	raw := is.raw()
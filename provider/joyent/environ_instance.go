@@ -48,6 +48,9 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Zones,
+	constraints.Interruptible,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
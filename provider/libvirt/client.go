@@ -0,0 +1,417 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+// This file contains the client used to drive a libvirt host, which
+// wraps the virsh executable (from the libvirt-clients package). Every
+// command is run against the host's connection URI, e.g.
+// qemu+ssh://user@host/system, so the host may be local or remote.
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+)
+
+// Client provides the operations on a libvirt host that the
+// provider requires.
+type Client interface {
+	// Ping checks that the host can be reached.
+	Ping() error
+
+	// Domains returns the names and states of all domains on the host.
+	Domains() ([]DomainState, error)
+
+	// Domain returns the definition of the named domain.
+	Domain(name string) (*kvmlibvirt.Domain, error)
+
+	// DefineDomain defines a new, persistent domain.
+	DefineDomain(domain kvmlibvirt.Domain) error
+
+	// SetDescription sets the description of the named domain.
+	SetDescription(name, description string) error
+
+	// StartDomain starts the named domain, and marks it to be
+	// started automatically when the host restarts.
+	StartDomain(name string) error
+
+	// UndefineDomain stops the named domain if it is running, and
+	// then removes its definition. The domain's disks are not
+	// removed.
+	UndefineDomain(name string) error
+
+	// DomainAddresses returns the IP addresses of the named
+	// domain's network interfaces.
+	DomainAddresses(name string) ([]string, error)
+
+	// AttachDisk attaches the disk at source to the named domain,
+	// as the given target device and with the given serial.
+	AttachDisk(domain, source, target, serial string) error
+
+	// DetachDisk detaches the disk with the given target device
+	// from the named domain.
+	DetachDisk(domain, target string) error
+
+	// NetworkBridge returns the name of the bridge device of the
+	// named network.
+	NetworkBridge(network string) (string, error)
+
+	// Volumes returns the volumes in the named storage pool. The
+	// volumes' sizes are not reported.
+	Volumes(pool string) ([]Volume, error)
+
+	// Volume returns the named volume in the named storage pool.
+	Volume(pool, name string) (Volume, error)
+
+	// CreateVolume creates a volume in the named storage pool.
+	CreateVolume(pool string, params CreateVolumeParams) error
+
+	// UploadVolume replaces the content of the named volume in the
+	// named storage pool with that of the local file at path.
+	UploadVolume(pool, name, path string) error
+
+	// DeleteVolume deletes the volume with the given path.
+	DeleteVolume(path string) error
+}
+
+// DomainState holds the name and state of a domain.
+type DomainState struct {
+	Name string
+
+	// State is one of: running, idle, paused, in shutdown,
+	// shut off, crashed, pmsuspended.
+	State string
+}
+
+// Volume describes a volume in a storage pool.
+type Volume struct {
+	Name string
+	Path string
+
+	// Size is the capacity of the volume in bytes.
+	Size uint64
+}
+
+// CreateVolumeParams holds the parameters for creating a volume.
+type CreateVolumeParams struct {
+	Name string
+
+	// Size is the capacity of the volume in bytes.
+	Size uint64
+
+	// Format is the format of the volume, e.g. raw or qcow2. If
+	// it is empty, the storage pool's default format is used.
+	Format string
+
+	// BackingVolume, if non-empty, is the name of the qcow2 volume
+	// in the same storage pool that backs the new volume.
+	BackingVolume string
+}
+
+var (
+	// domainListPattern matches the lines of 'virsh -q list --all',
+	// which are the domain's ID (or "-" if it is not running), name
+	// and state.
+	domainListPattern = regexp.MustCompile(`(?m)^\s*(?:\d+|-)\s+(\S+)\s+(.+?)\s*$`)
+
+	// volumeListPattern matches the lines of 'virsh -q vol-list',
+	// which are the volume's name and path.
+	volumeListPattern = regexp.MustCompile(`(?m)^\s*(\S+)\s+(\S+)\s*$`)
+
+	// addressListPattern matches the lines of 'virsh -q domifaddr',
+	// which are the interface's name, MAC address, protocol and
+	// address. Only the first line for an interface holds its name
+	// and MAC address.
+	addressListPattern = regexp.MustCompile(`(?m)^\s*(?:\S+\s+\S+\s+)?ipv[46]\s+(\S+?)(?:/\d+)?\s*$`)
+)
+
+// runFunc provides the signature for running an external command and
+// returning the combined output.
+type runFunc func(string, ...string) (string, error)
+
+// run the command and return the combined output.
+func run(command string, args ...string) (output string, err error) {
+	logger.Debugf("%s %v", command, args)
+	output, err = utils.RunCommand(command, args...)
+	logger.Debugf("output: %v", output)
+	return output, err
+}
+
+// NewClient returns a Client for the libvirt host with the given
+// connection URI.
+func NewClient(uri string) Client {
+	return &virshClient{uri: uri, runCmd: run}
+}
+
+// virshClient is a Client that runs virsh commands.
+type virshClient struct {
+	uri    string
+	runCmd runFunc
+}
+
+func (c *virshClient) virsh(args ...string) (string, error) {
+	output, err := c.runCmd("virsh", append([]string{"-c", c.uri}, args...)...)
+	if err != nil {
+		// The output holds virsh's error message, which
+		// is more useful than the exit status.
+		if msg := strings.TrimSpace(output); msg != "" {
+			return "", errors.Errorf("%s", msg)
+		}
+		return "", errors.Trace(err)
+	}
+	return output, nil
+}
+
+// Ping is part of the Client interface.
+func (c *virshClient) Ping() error {
+	_, err := c.virsh("uri")
+	return errors.Annotatef(err, "connecting to %q", c.uri)
+}
+
+// Domains is part of the Client interface.
+func (c *virshClient) Domains() ([]DomainState, error) {
+	output, err := c.virsh("-q", "list", "--all")
+	if err != nil {
+		return nil, errors.Annotate(err, "listing domains")
+	}
+	var result []DomainState
+	for _, match := range domainListPattern.FindAllStringSubmatch(output, -1) {
+		result = append(result, DomainState{Name: match[1], State: match[2]})
+	}
+	return result, nil
+}
+
+// Domain is part of the Client interface.
+func (c *virshClient) Domain(name string) (*kvmlibvirt.Domain, error) {
+	output, err := c.virsh("dumpxml", name)
+	if err != nil {
+		if strings.Contains(err.Error(), "failed to get domain") {
+			return nil, errors.NotFoundf("domain %q", name)
+		}
+		return nil, errors.Annotatef(err, "getting domain %q", name)
+	}
+	var domain kvmlibvirt.Domain
+	if err := xml.Unmarshal([]byte(output), &domain); err != nil {
+		return nil, errors.Annotatef(err, "parsing domain %q", name)
+	}
+	return &domain, nil
+}
+
+// DefineDomain is part of the Client interface.
+func (c *virshClient) DefineDomain(domain kvmlibvirt.Domain) error {
+	data, err := xml.MarshalIndent(&domain, "", "    ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// virsh reads the domain definition from a local file,
+	// and sends it on to the host.
+	f, err := ioutil.TempFile("", "juju-libvirt-domain-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := c.virsh("define", f.Name()); err != nil {
+		return errors.Annotatef(err, "defining domain %q", domain.Name)
+	}
+	return nil
+}
+
+// SetDescription is part of the Client interface.
+func (c *virshClient) SetDescription(name, description string) error {
+	if _, err := c.virsh("desc", name, "--config", "--new-desc", description); err != nil {
+		return errors.Annotatef(err, "setting description of domain %q", name)
+	}
+	// The running domain's description is updated separately;
+	// this fails if the domain is not running, which is fine.
+	if _, err := c.virsh("desc", name, "--live", "--new-desc", description); err != nil {
+		logger.Debugf("setting live description of domain %q: %v", name, err)
+	}
+	return nil
+}
+
+// StartDomain is part of the Client interface.
+func (c *virshClient) StartDomain(name string) error {
+	if _, err := c.virsh("start", name); err != nil {
+		return errors.Annotatef(err, "starting domain %q", name)
+	}
+	if _, err := c.virsh("autostart", name); err != nil {
+		return errors.Annotatef(err, "autostarting domain %q", name)
+	}
+	return nil
+}
+
+// UndefineDomain is part of the Client interface.
+func (c *virshClient) UndefineDomain(name string) error {
+	// We don't return the error from destroying the domain, as it
+	// fails if the domain is not running. If the domain could not
+	// be destroyed for any other reason, undefining it will fail.
+	if _, err := c.virsh("destroy", name); err != nil {
+		logger.Debugf("destroying domain %q: %v", name, err)
+	}
+	// The nvram flag removes the pflash drive of UEFI domains.
+	if _, err := c.virsh("undefine", "--nvram", name); err != nil {
+		if strings.Contains(err.Error(), "failed to get domain") {
+			return nil
+		}
+		return errors.Annotatef(err, "undefining domain %q", name)
+	}
+	return nil
+}
+
+// DomainAddresses is part of the Client interface.
+func (c *virshClient) DomainAddresses(name string) ([]string, error) {
+	// The addresses are taken from the DHCP leases of networks
+	// managed by libvirt; for domains on other bridges we fall
+	// back to the host's ARP table.
+	for _, source := range []string{"lease", "arp"} {
+		output, err := c.virsh("-q", "domifaddr", name, "--source", source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting addresses of domain %q", name)
+		}
+		var addresses []string
+		for _, match := range addressListPattern.FindAllStringSubmatch(output, -1) {
+			addresses = append(addresses, match[1])
+		}
+		if len(addresses) > 0 {
+			return addresses, nil
+		}
+	}
+	return nil, nil
+}
+
+// AttachDisk is part of the Client interface.
+func (c *virshClient) AttachDisk(domain, source, target, serial string) error {
+	if _, err := c.virsh(
+		"attach-disk", domain, source, target,
+		"--persistent", "--serial", serial,
+	); err != nil {
+		return errors.Annotatef(err, "attaching %q to domain %q", source, domain)
+	}
+	return nil
+}
+
+// DetachDisk is part of the Client interface.
+func (c *virshClient) DetachDisk(domain, target string) error {
+	if _, err := c.virsh("detach-disk", domain, target, "--persistent"); err != nil {
+		return errors.Annotatef(err, "detaching %q from domain %q", target, domain)
+	}
+	return nil
+}
+
+// NetworkBridge is part of the Client interface.
+func (c *virshClient) NetworkBridge(network string) (string, error) {
+	output, err := c.virsh("net-info", network)
+	if err != nil {
+		return "", errors.Annotatef(err, "getting network %q", network)
+	}
+	bridge := infoValue(output, "Bridge")
+	if bridge == "" {
+		return "", errors.NotFoundf("bridge for network %q", network)
+	}
+	return bridge, nil
+}
+
+// Volumes is part of the Client interface.
+func (c *virshClient) Volumes(pool string) ([]Volume, error) {
+	output, err := c.virsh("-q", "vol-list", pool)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing volumes in storage pool %q", pool)
+	}
+	var result []Volume
+	for _, match := range volumeListPattern.FindAllStringSubmatch(output, -1) {
+		result = append(result, Volume{Name: match[1], Path: match[2]})
+	}
+	return result, nil
+}
+
+// Volume is part of the Client interface.
+func (c *virshClient) Volume(pool, name string) (Volume, error) {
+	output, err := c.virsh("vol-info", "--bytes", "--pool", pool, name)
+	if err != nil {
+		if strings.Contains(err.Error(), "Storage volume not found") {
+			return Volume{}, errors.NotFoundf("volume %q in storage pool %q", name, pool)
+		}
+		return Volume{}, errors.Annotatef(err, "getting volume %q", name)
+	}
+	capacity := strings.TrimSuffix(infoValue(output, "Capacity"), " bytes")
+	size, err := strconv.ParseUint(capacity, 10, 64)
+	if err != nil {
+		return Volume{}, errors.Annotatef(err, "parsing capacity of volume %q", name)
+	}
+	path, err := c.virsh("vol-path", "--pool", pool, name)
+	if err != nil {
+		return Volume{}, errors.Annotatef(err, "getting path of volume %q", name)
+	}
+	return Volume{
+		Name: name,
+		Path: strings.TrimSpace(path),
+		Size: size,
+	}, nil
+}
+
+// CreateVolume is part of the Client interface.
+func (c *virshClient) CreateVolume(pool string, params CreateVolumeParams) error {
+	args := []string{"vol-create-as", pool, params.Name, fmt.Sprint(params.Size)}
+	if params.Format != "" {
+		args = append(args, "--format", params.Format)
+	}
+	if params.BackingVolume != "" {
+		args = append(args,
+			"--backing-vol", params.BackingVolume,
+			"--backing-vol-format", "qcow2",
+		)
+	}
+	if _, err := c.virsh(args...); err != nil {
+		return errors.Annotatef(err, "creating volume %q", params.Name)
+	}
+	return nil
+}
+
+// UploadVolume is part of the Client interface.
+func (c *virshClient) UploadVolume(pool, name, path string) error {
+	if _, err := c.virsh("vol-upload", "--pool", pool, name, path); err != nil {
+		return errors.Annotatef(err, "uploading volume %q", name)
+	}
+	return nil
+}
+
+// DeleteVolume is part of the Client interface.
+func (c *virshClient) DeleteVolume(path string) error {
+	if _, err := c.virsh("vol-delete", path); err != nil {
+		if strings.Contains(err.Error(), "Storage volume not found") {
+			return nil
+		}
+		return errors.Annotatef(err, "deleting volume %q", path)
+	}
+	return nil
+}
+
+// infoValue returns the value of the given key in the output of a
+// virsh info command, e.g. 'virsh net-info', whose lines have the
+// form "Key: value".
+func infoValue(output, key string) string {
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == key {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"errors"
+	"strings"
+
+	jujuerrors "github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/libvirt"
+)
+
+type clientSuite struct {
+	testing.IsolationSuite
+	stub    testing.Stub
+	outputs []string
+	client  libvirt.Client
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub.ResetCalls()
+	s.outputs = nil
+	s.client = libvirt.NewVirshClient(host1URI, s.run)
+}
+
+func (s *clientSuite) run(command string, args ...string) (string, error) {
+	s.stub.AddCall(command, strings.Join(args, " "))
+	var output string
+	if len(s.outputs) > 0 {
+		output, s.outputs = s.outputs[0], s.outputs[1:]
+	}
+	return output, s.stub.NextErr()
+}
+
+func (s *clientSuite) TestDomains(c *gc.C) {
+	s.outputs = []string{`
+ 1    juju-f75cba-0   running
+ -    juju-f75cba-1   shut off
+`}
+	domains, err := s.client.Domains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(domains, jc.DeepEquals, []libvirt.DomainState{
+		{Name: "juju-f75cba-0", State: "running"},
+		{Name: "juju-f75cba-1", State: "shut off"},
+	})
+	s.stub.CheckCall(c, 0, "virsh", "-c "+host1URI+" -q list --all")
+}
+
+func (s *clientSuite) TestDomainNotFound(c *gc.C) {
+	s.outputs = []string{"error: failed to get domain 'juju-f75cba-0'\n"}
+	s.stub.SetErrors(errors.New("exit status 1"))
+	_, err := s.client.Domain("juju-f75cba-0")
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+}
+
+func (s *clientSuite) TestDomainAddresses(c *gc.C) {
+	s.outputs = []string{`
+ vnet0      52:54:00:12:34:56    ipv4         192.168.122.10/24
+ -          -                    ipv6         fd00::10/64
+`}
+	addresses, err := s.client.DomainAddresses("juju-f75cba-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"192.168.122.10", "fd00::10"})
+	s.stub.CheckCall(c, 0, "virsh", "-c "+host1URI+" -q domifaddr juju-f75cba-0 --source lease")
+}
+
+func (s *clientSuite) TestDomainAddressesFallsBackToARP(c *gc.C) {
+	s.outputs = []string{"", `
+ vnet0      52:54:00:12:34:56    ipv4         10.0.0.10/0
+`}
+	addresses, err := s.client.DomainAddresses("juju-f75cba-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"10.0.0.10"})
+	s.stub.CheckCall(c, 1, "virsh", "-c "+host1URI+" -q domifaddr juju-f75cba-0 --source arp")
+}
+
+func (s *clientSuite) TestNetworkBridge(c *gc.C) {
+	s.outputs = []string{`
+Name:           default
+UUID:           4a9cf2b4-5ea3-4e7c-9ad4-1d6c1c34e0f5
+Active:         yes
+Persistent:     yes
+Autostart:      yes
+Bridge:         virbr0
+`}
+	bridge, err := s.client.NetworkBridge("default")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bridge, gc.Equals, "virbr0")
+}
+
+func (s *clientSuite) TestVolume(c *gc.C) {
+	s.outputs = []string{`
+Name:           juju-f75cba-volume-0
+Type:           file
+Capacity:       1073741824 bytes
+Allocation:     200704 bytes
+`, "/var/lib/libvirt/images/juju-f75cba-volume-0\n"}
+	volume, err := s.client.Volume("default", "juju-f75cba-volume-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume, jc.DeepEquals, libvirt.Volume{
+		Name: "juju-f75cba-volume-0",
+		Path: "/var/lib/libvirt/images/juju-f75cba-volume-0",
+		Size: 1024 * 1024 * 1024,
+	})
+}
+
+func (s *clientSuite) TestVolumeNotFound(c *gc.C) {
+	s.outputs = []string{"error: Storage volume not found: no storage vol with matching path\n"}
+	s.stub.SetErrors(errors.New("exit status 1"))
+	_, err := s.client.Volume("default", "juju-f75cba-volume-0")
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+}
+
+func (s *clientSuite) TestCreateVolumeWithBackingVolume(c *gc.C) {
+	err := s.client.CreateVolume("default", libvirt.CreateVolumeParams{
+		Name:          "juju-f75cba-0-root.img",
+		Size:          8589934592,
+		Format:        "qcow2",
+		BackingVolume: "juju-image-bionic-amd64.img",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCall(c, 0, "virsh", "-c "+host1URI+
+		" vol-create-as default juju-f75cba-0-root.img 8589934592 --format qcow2"+
+		" --backing-vol juju-image-bionic-amd64.img --backing-vol-format qcow2",
+	)
+}
+
+func (s *clientSuite) TestErrorHoldsOutput(c *gc.C) {
+	s.outputs = []string{"error: failed to connect to the hypervisor\n"}
+	s.stub.SetErrors(errors.New("exit status 1"))
+	err := s.client.Ping()
+	c.Assert(err, gc.ErrorMatches, `connecting to "`+host1URI+`": error: failed to connect to the hypervisor`)
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/environs/config"
+)
+
+// The libvirt-specific config keys.
+const (
+	cfgNetwork     = "network"
+	cfgStoragePool = "storage-pool"
+)
+
+var configSchema = environschema.Fields{
+	cfgNetwork: {
+		Description: "The name of the libvirt network to which instances are connected.",
+		Type:        environschema.Tstring,
+	},
+	cfgStoragePool: {
+		Description: "The name of the libvirt storage pool in which instance disks and images are stored.",
+		Type:        environschema.Tstring,
+	},
+}
+
+// configFields is the spec for each libvirt config value's type.
+var configFields = func() schema.Fields {
+	fs, _, err := configSchema.ValidationSchema()
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}()
+
+var (
+	configDefaults = schema.Defaults{
+		cfgNetwork:     "default",
+		cfgStoragePool: "default",
+	}
+
+	configRequiredFields = []string{
+		cfgNetwork,
+		cfgStoragePool,
+	}
+
+	// The storage pool holds the disks of existing instances,
+	// so it cannot be changed.
+	configImmutableFields = []string{
+		cfgStoragePool,
+	}
+)
+
+type environConfig struct {
+	*config.Config
+	attrs map[string]interface{}
+}
+
+// newConfig builds a new environConfig from the provided Config and
+// returns it.
+func newConfig(cfg *config.Config) *environConfig {
+	return &environConfig{
+		Config: cfg,
+		attrs:  cfg.UnknownAttrs(),
+	}
+}
+
+// newValidConfig builds a new environConfig from the provided Config
+// and returns it. The resulting config values are validated.
+func newValidConfig(cfg *config.Config) (*environConfig, error) {
+	// Ensure that the provided config is valid.
+	if err := config.Validate(cfg, nil); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Apply the defaults and coerce/validate the custom config attrs.
+	validated, err := cfg.ValidateUnknownAttrs(configFields, configDefaults)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	validCfg, err := cfg.Apply(validated)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Build the config.
+	ecfg := newConfig(validCfg)
+
+	// Do final validation.
+	if err := ecfg.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return ecfg, nil
+}
+
+func (c *environConfig) network() string {
+	return c.attrs[cfgNetwork].(string)
+}
+
+func (c *environConfig) storagePool() string {
+	return c.attrs[cfgStoragePool].(string)
+}
+
+// validate checks libvirt-specific config values.
+func (c environConfig) validate() error {
+	for _, field := range configRequiredFields {
+		if c.attrs[field].(string) == "" {
+			return errors.Errorf("%s: must not be empty", field)
+		}
+	}
+	return nil
+}
+
+// update applies changes from the provided config to the env config.
+// Changes to any immutable attributes result in an error.
+func (c *environConfig) update(cfg *config.Config) error {
+	// Validate the updates. newValidConfig does not modify the "known"
+	// config attributes so it is safe to call Validate here first.
+	if err := config.Validate(cfg, c.Config); err != nil {
+		return errors.Trace(err)
+	}
+
+	updates, err := newValidConfig(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Check that no immutable fields have changed.
+	attrs := updates.UnknownAttrs()
+	for _, field := range configImmutableFields {
+		if attrs[field] != c.attrs[field] {
+			return errors.Errorf("%s: cannot change from %v to %v", field, c.attrs[field], attrs[field])
+		}
+	}
+
+	// Apply the updates.
+	c.Config = updates.Config
+	c.attrs = attrs
+	return nil
+}
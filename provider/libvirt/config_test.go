@@ -0,0 +1,73 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+func fakeConfig(c *gc.C, attrs ...testing.Attrs) *config.Config {
+	cfg, err := testing.ModelConfig(c).Apply(fakeConfigAttrs(attrs...))
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func fakeConfigAttrs(attrs ...testing.Attrs) testing.Attrs {
+	merged := testing.FakeConfig().Merge(testing.Attrs{
+		"type": "libvirt",
+		"uuid": "2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+	})
+	for _, attrs := range attrs {
+		merged = merged.Merge(attrs)
+	}
+	return merged
+}
+
+func fakeCloudSpec() environs.CloudSpec {
+	cred := cloud.NewEmptyCredential()
+	return environs.CloudSpec{
+		Type:       "libvirt",
+		Name:       "libvirt",
+		Endpoint:   host1URI + "," + host2URI,
+		Credential: &cred,
+	}
+}
+
+type ConfigSuite struct {
+	ProviderFixture
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestValidateNewConfig(c *gc.C) {
+	cfg, err := s.provider.Validate(fakeConfig(c), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := cfg.UnknownAttrs()
+	c.Assert(attrs["network"], gc.Equals, "default")
+	c.Assert(attrs["storage-pool"], gc.Equals, "default")
+}
+
+func (s *ConfigSuite) TestValidateEmptyNetwork(c *gc.C) {
+	_, err := s.provider.Validate(fakeConfig(c, testing.Attrs{"network": ""}), nil)
+	c.Assert(err, gc.ErrorMatches, "invalid config: network: must not be empty")
+}
+
+func (s *ConfigSuite) TestValidateChangeNetwork(c *gc.C) {
+	old := fakeConfig(c)
+	cfg, err := s.provider.Validate(fakeConfig(c, testing.Attrs{"network": "juju"}), old)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.UnknownAttrs()["network"], gc.Equals, "juju")
+}
+
+func (s *ConfigSuite) TestValidateChangeStoragePool(c *gc.C) {
+	old := fakeConfig(c)
+	_, err := s.provider.Validate(fakeConfig(c, testing.Attrs{"storage-pool": "ssd"}), old)
+	c.Assert(err, gc.ErrorMatches, "invalid config change: storage-pool: cannot change from default to ssd")
+}
@@ -0,0 +1,233 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/series"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/juju/paths"
+)
+
+const (
+	// sshKeyAuthType is the auth-type of credentials holding an SSH
+	// private key, used to connect to hosts with qemu+ssh URIs.
+	sshKeyAuthType cloud.AuthType = "ssh-key"
+
+	credAttrPrivateKey = "private-key"
+	credAttrKnownHosts = "known-hosts"
+	credAttrCACert     = "ca-cert"
+	credAttrClientCert = "client-cert"
+	credAttrClientKey  = "client-key"
+)
+
+// environProviderCredentials implements environs.ProviderCredentials.
+//
+// Connections to libvirt hosts may be authenticated with an SSH key,
+// for qemu+ssh URIs, or a TLS client certificate, for qemu+tls URIs.
+// With the empty auth-type, the connection is authenticated as
+// configured for virsh on the client, which only works on the
+// controller for local hosts.
+type environProviderCredentials struct{}
+
+// CredentialSchemas is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) CredentialSchemas() map[cloud.AuthType]cloud.CredentialSchema {
+	return map[cloud.AuthType]cloud.CredentialSchema{
+		cloud.EmptyAuthType: {},
+
+		sshKeyAuthType: {{
+			credAttrPrivateKey,
+			cloud.CredentialAttr{
+				Description: "The SSH private key used to connect to the libvirt hosts, PEM-encoded.",
+				Hidden:      true,
+				FileAttr:    "private-key-path",
+			},
+		}, {
+			credAttrKnownHosts,
+			cloud.CredentialAttr{
+				Description: "The SSH host keys of the libvirt hosts, in known_hosts format. If not set, host keys are recorded when a host is first connected to, and verified thereafter.",
+				FileAttr:    "known-hosts-path",
+				Optional:    true,
+			},
+		}},
+
+		cloud.CertificateAuthType: {{
+			credAttrCACert,
+			cloud.CredentialAttr{
+				Description: "The CA certificate of the libvirt hosts, PEM-encoded.",
+				FileAttr:    "ca-cert-path",
+			},
+		}, {
+			credAttrClientCert,
+			cloud.CredentialAttr{
+				Description: "The libvirt client certificate, PEM-encoded.",
+				FileAttr:    "client-cert-path",
+			},
+		}, {
+			credAttrClientKey,
+			cloud.CredentialAttr{
+				Description: "The libvirt client key, PEM-encoded.",
+				Hidden:      true,
+				FileAttr:    "client-key-path",
+			},
+		}},
+	}
+}
+
+// DetectCredentials is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) DetectCredentials() (*cloud.CloudCredential, error) {
+	return cloud.NewEmptyCloudCredential(), nil
+}
+
+// FinalizeCredential is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) FinalizeCredential(_ environs.FinalizeCredentialContext, args environs.FinalizeCredentialParams) (*cloud.Credential, error) {
+	return &args.Credential, nil
+}
+
+// validateCredential checks that the credential can be used to
+// connect to all of the given hosts.
+func validateCredential(credential *cloud.Credential, hosts []hostSpec) error {
+	var scheme string
+	switch authType := credential.AuthType(); authType {
+	case cloud.EmptyAuthType:
+		return nil
+	case sshKeyAuthType:
+		scheme = "qemu+ssh"
+	case cloud.CertificateAuthType:
+		scheme = "qemu+tls"
+	default:
+		return errors.NotSupportedf("%q auth-type", authType)
+	}
+	for _, host := range hosts {
+		if host.scheme != scheme {
+			return errors.NotValidf(
+				"%q auth-type with libvirt URI %q (expected a %s URI)",
+				credential.AuthType(), host.uri, scheme,
+			)
+		}
+	}
+	return nil
+}
+
+// defaultCredentialsDir returns the directory in which credentials
+// are written out for virsh: the agent data directory when running
+// as a machine agent, and the Juju data directory otherwise.
+func defaultCredentialsDir() string {
+	dataDir := paths.MustSucceed(paths.DataDir(series.MustHostSeries()))
+	if os.Geteuid() == 0 {
+		if _, err := os.Stat(filepath.Join(dataDir, "agents")); err == nil {
+			return filepath.Join(dataDir, "libvirt")
+		}
+	}
+	return osenv.JujuXDGDataHomePath("libvirt")
+}
+
+// writeCredential writes out the files that virsh needs to connect
+// with the given credential to a directory of their own within dir,
+// and returns the connection URI parameters that tell virsh to use
+// them. The files are written whenever the credential is used, so
+// that they are recreated on the controller if they are removed;
+// the known_hosts file is only written if the credential specifies
+// host keys, so that any host keys ssh has learned are kept.
+func writeCredential(dir string, credential *cloud.Credential) (url.Values, error) {
+	attrs := credential.Attributes()
+	files := make(map[string]string)
+	switch credential.AuthType() {
+	case sshKeyAuthType:
+		files["id"] = attrs[credAttrPrivateKey]
+		if knownHosts := attrs[credAttrKnownHosts]; knownHosts != "" {
+			files["known_hosts"] = knownHosts
+		}
+	case cloud.CertificateAuthType:
+		// These are the names virsh expects in its pkipath.
+		files["cacert.pem"] = attrs[credAttrCACert]
+		files["clientcert.pem"] = attrs[credAttrClientCert]
+		files["clientkey.pem"] = attrs[credAttrClientKey]
+	default:
+		return nil, nil
+	}
+
+	credDir := filepath.Join(dir, credentialId(credential))
+	if err := os.MkdirAll(credDir, 0700); err != nil {
+		return nil, errors.Annotate(err, "creating credentials directory")
+	}
+	for name, data := range files {
+		if err := utils.AtomicWriteFile(filepath.Join(credDir, name), []byte(data), 0600); err != nil {
+			return nil, errors.Annotatef(err, "writing %s", name)
+		}
+	}
+
+	params := make(url.Values)
+	switch credential.AuthType() {
+	case sshKeyAuthType:
+		// virsh runs ssh to connect to the host; the command it
+		// runs is a script that has ssh use the credential's key
+		// and host keys. Hosts that are not yet known have their
+		// keys added to the known_hosts file on first connection,
+		// and any connection to a host whose key has changed is
+		// refused.
+		script := fmt.Sprintf(
+			"#!/bin/sh\nexec ssh -o IdentityFile=%s -o IdentitiesOnly=yes -o UserKnownHostsFile=%s -o StrictHostKeyChecking=accept-new \"$@\"\n",
+			utils.ShQuote(filepath.Join(credDir, "id")),
+			utils.ShQuote(filepath.Join(credDir, "known_hosts")),
+		)
+		command := filepath.Join(credDir, "ssh")
+		if err := utils.AtomicWriteFile(command, []byte(script), 0700); err != nil {
+			return nil, errors.Annotate(err, "writing ssh command")
+		}
+		params.Set("command", command)
+		params.Set("no_tty", "1")
+	case cloud.CertificateAuthType:
+		params.Set("pkipath", credDir)
+	}
+	return params, nil
+}
+
+// credentialId returns an identifier for the credential that is
+// derived from its content, so that each credential's files are
+// written to a directory of their own.
+func credentialId(credential *cloud.Credential) string {
+	attrs := credential.Attributes()
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n", credential.AuthType())
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s=%q\n", key, attrs[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))[:16]
+}
+
+// withParams returns the connection URI with the given parameters
+// added to its query.
+func withParams(uri string, params url.Values) string {
+	if len(params) == 0 {
+		return uri
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		// The URI has already been validated.
+		return uri
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
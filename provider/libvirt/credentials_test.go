@@ -0,0 +1,131 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/provider/libvirt"
+)
+
+type credentialsSuite struct {
+	testing.IsolationSuite
+	dir string
+}
+
+var _ = gc.Suite(&credentialsSuite{})
+
+func (s *credentialsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+}
+
+func (s *credentialsSuite) TestWriteEmptyCredential(c *gc.C) {
+	credential := cloud.NewEmptyCredential()
+	params, err := libvirt.WriteCredential(s.dir, &credential)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params, gc.HasLen, 0)
+
+	entries, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
+
+func (s *credentialsSuite) TestWriteSSHKeyCredential(c *gc.C) {
+	credential := cloud.NewCredential("ssh-key", map[string]string{
+		"private-key": "key",
+		"known-hosts": "host1 ssh-rsa AAAA",
+	})
+	params, err := libvirt.WriteCredential(s.dir, &credential)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params.Get("no_tty"), gc.Equals, "1")
+
+	command := params.Get("command")
+	credDir := filepath.Dir(command)
+	c.Assert(filepath.Dir(credDir), gc.Equals, s.dir)
+	s.assertFile(c, filepath.Join(credDir, "id"), "key", 0600)
+	s.assertFile(c, filepath.Join(credDir, "known_hosts"), "host1 ssh-rsa AAAA", 0600)
+
+	script, err := ioutil.ReadFile(command)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(script), gc.Equals, "#!/bin/sh\nexec ssh"+
+		" -o IdentityFile='"+filepath.Join(credDir, "id")+"'"+
+		" -o IdentitiesOnly=yes"+
+		" -o UserKnownHostsFile='"+filepath.Join(credDir, "known_hosts")+"'"+
+		" -o StrictHostKeyChecking=accept-new \"$@\"\n",
+	)
+	info, err := os.Stat(command)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0700))
+}
+
+func (s *credentialsSuite) TestWriteSSHKeyCredentialNoKnownHosts(c *gc.C) {
+	credential := cloud.NewCredential("ssh-key", map[string]string{
+		"private-key": "key",
+	})
+	params, err := libvirt.WriteCredential(s.dir, &credential)
+	c.Assert(err, jc.ErrorIsNil)
+
+	script, err := ioutil.ReadFile(params.Get("command"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(script), jc.Contains, "-o StrictHostKeyChecking=accept-new")
+
+	// Host keys learned by ssh are kept when the credential is
+	// written out again.
+	knownHosts := filepath.Join(filepath.Dir(params.Get("command")), "known_hosts")
+	_, err = os.Stat(knownHosts)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	err = ioutil.WriteFile(knownHosts, []byte("host1 ssh-rsa AAAA"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = libvirt.WriteCredential(s.dir, &credential)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFile(c, knownHosts, "host1 ssh-rsa AAAA", 0600)
+}
+
+func (s *credentialsSuite) TestWriteCertificateCredential(c *gc.C) {
+	credential := cloud.NewCredential(cloud.CertificateAuthType, map[string]string{
+		"ca-cert":     "ca",
+		"client-cert": "cert",
+		"client-key":  "key",
+	})
+	params, err := libvirt.WriteCredential(s.dir, &credential)
+	c.Assert(err, jc.ErrorIsNil)
+
+	pkiPath := params.Get("pkipath")
+	c.Assert(filepath.Dir(pkiPath), gc.Equals, s.dir)
+	s.assertFile(c, filepath.Join(pkiPath, "cacert.pem"), "ca", 0600)
+	s.assertFile(c, filepath.Join(pkiPath, "clientcert.pem"), "cert", 0600)
+	s.assertFile(c, filepath.Join(pkiPath, "clientkey.pem"), "key", 0600)
+}
+
+func (s *credentialsSuite) TestWriteCredentialsSeparately(c *gc.C) {
+	credential1 := cloud.NewCredential("ssh-key", map[string]string{"private-key": "key1"})
+	credential2 := cloud.NewCredential("ssh-key", map[string]string{"private-key": "key2"})
+	params1, err := libvirt.WriteCredential(s.dir, &credential1)
+	c.Assert(err, jc.ErrorIsNil)
+	params2, err := libvirt.WriteCredential(s.dir, &credential2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params1.Get("command"), gc.Not(gc.Equals), params2.Get("command"))
+
+	// Writing a credential again uses the same files.
+	params, err := libvirt.WriteCredential(s.dir, &credential1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params.Get("command"), gc.Equals, params1.Get("command"))
+}
+
+func (s *credentialsSuite) assertFile(c *gc.C, path, content string, perm os.FileMode) {
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, content)
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, perm)
+}
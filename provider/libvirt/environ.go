@@ -0,0 +1,248 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+// libvirtHost is a libvirt host, which is one of the model's
+// availability zones.
+type libvirtHost struct {
+	hostSpec
+	client Client
+}
+
+type environ struct {
+	name     string
+	cloud    environs.CloudSpec
+	provider *environProvider
+
+	// hosts are the libvirt hosts on which instances are created.
+	hosts []*libvirtHost
+
+	// namespace is used to create the domain and volume names.
+	namespace instance.Namespace
+
+	// imageMutex serialises the uploading of cloud images.
+	imageMutex sync.Mutex
+
+	lock sync.Mutex // lock protects access the following fields.
+	ecfg *environConfig
+}
+
+func newEnviron(
+	provider *environProvider,
+	cloud environs.CloudSpec,
+	cfg *config.Config,
+) (*environ, error) {
+	ecfg, err := newValidConfig(cfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid config")
+	}
+
+	namespace, err := instance.NewNamespace(cfg.UUID())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	specs, err := parseHosts(cloud.Endpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The credential is written out for virsh wherever the
+	// environ is opened, including on the controller.
+	params, err := writeCredential(provider.credentialsDir(), cloud.Credential)
+	if err != nil {
+		return nil, errors.Annotate(err, "writing credential")
+	}
+	hosts := make([]*libvirtHost, len(specs))
+	for i, spec := range specs {
+		hosts[i] = &libvirtHost{
+			hostSpec: spec,
+			client:   provider.newClient(withParams(spec.uri, params)),
+		}
+	}
+
+	env := &environ{
+		name:      ecfg.Name(),
+		cloud:     cloud,
+		provider:  provider,
+		hosts:     hosts,
+		ecfg:      ecfg,
+		namespace: namespace,
+	}
+	return env, nil
+}
+
+// host returns the host corresponding to the named availability zone.
+func (env *environ) host(zone string) (*libvirtHost, error) {
+	for _, host := range env.hosts {
+		if host.zone == zone {
+			return host, nil
+		}
+	}
+	return nil, errors.NotFoundf("availability zone %q", zone)
+}
+
+// Name is part of the environs.Environ interface.
+func (env *environ) Name() string {
+	return env.name
+}
+
+// Provider is part of the environs.Environ interface.
+func (env *environ) Provider() environs.EnvironProvider {
+	return env.provider
+}
+
+// SetConfig is part of the environs.Environ interface.
+func (env *environ) SetConfig(cfg *config.Config) error {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	if env.ecfg == nil {
+		return errors.New("cannot set config on uninitialized env")
+	}
+
+	if err := env.ecfg.update(cfg); err != nil {
+		return errors.Annotate(err, "invalid config change")
+	}
+	return nil
+}
+
+// Config is part of the environs.Environ interface.
+func (env *environ) Config() *config.Config {
+	return env.envConfig().Config
+}
+
+func (env *environ) envConfig() *environConfig {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+	return env.ecfg
+}
+
+// PrepareForBootstrap implements environs.Environ.
+func (env *environ) PrepareForBootstrap(ctx environs.BootstrapContext) error {
+	return nil
+}
+
+// Create implements environs.Environ.
+func (env *environ) Create(args environs.CreateParams) error {
+	return nil
+}
+
+// this variable is exported, because it has to be rewritten in external unit tests
+var Bootstrap = common.Bootstrap
+
+// Bootstrap is part of the environs.Environ interface.
+func (env *environ) Bootstrap(
+	ctx environs.BootstrapContext,
+	args environs.BootstrapParams,
+) (*environs.BootstrapResult, error) {
+	return Bootstrap(ctx, env, args)
+}
+
+// AdoptResources is part of the Environ interface.
+func (env *environ) AdoptResources(controllerUUID string, fromVersion version.Number) error {
+	// The controller UUID is recorded in the description of
+	// each of the model's domains.
+	for _, host := range env.hosts {
+		domains, err := env.hostDomains(host)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, domain := range domains {
+			def, err := host.client.Domain(domain.Name)
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			domainTags := parseDescription(def.Description)
+			if domainTags[tags.JujuController] == controllerUUID {
+				continue
+			}
+			domainTags[tags.JujuController] = controllerUUID
+			if err := host.client.SetDescription(domain.Name, formatDescription(domainTags)); err != nil {
+				return errors.Annotatef(err, "updating domain %q", domain.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// this variable is exported, because it has to be rewritten in external unit tests
+var DestroyEnv = common.Destroy
+
+// Destroy is part of the environs.Environ interface.
+func (env *environ) Destroy() error {
+	return errors.Trace(DestroyEnv(env))
+}
+
+// DestroyController implements the Environ interface.
+func (env *environ) DestroyController(controllerUUID string) error {
+	if err := env.Destroy(); err != nil {
+		return errors.Trace(err)
+	}
+	// Remove the domains of all of the controller's hosted models.
+	for _, host := range env.hosts {
+		domains, err := host.client.Domains()
+		if err != nil {
+			return errors.Annotatef(err, "listing domains on %q", host.zone)
+		}
+		for _, domain := range domains {
+			if !strings.HasPrefix(domain.Name, tags.JujuTagPrefix) {
+				continue
+			}
+			def, err := host.client.Domain(domain.Name)
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			if parseDescription(def.Description)[tags.JujuController] != controllerUUID {
+				continue
+			}
+			if err := destroyDomain(host.client, domain.Name); err != nil {
+				return errors.Annotatef(err, "destroying domain %q", domain.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// formatDescription returns a domain description that records the
+// given tags, one "key=value" pair per line.
+func formatDescription(tags map[string]string) string {
+	lines := make([]string, 0, len(tags))
+	for k, v := range tags {
+		lines = append(lines, k+"="+v)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// parseDescription returns the tags recorded in a domain description
+// by formatDescription.
+func parseDescription(description string) map[string]string {
+	tags := make(map[string]string)
+	for _, line := range strings.Split(description, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) == 2 {
+			tags[parts[0]] = parts[1]
+		}
+	}
+	return tags
+}
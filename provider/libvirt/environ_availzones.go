@@ -0,0 +1,116 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+// libvirtAvailZone is a libvirt host, which the provider exposes
+// as an availability zone.
+type libvirtAvailZone struct {
+	host      *libvirtHost
+	available bool
+}
+
+// Name implements common.AvailabilityZone
+func (z *libvirtAvailZone) Name() string {
+	return z.host.zone
+}
+
+// Available implements common.AvailabilityZone
+func (z *libvirtAvailZone) Available() bool {
+	return z.available
+}
+
+// AvailabilityZones is part of the common.ZonedEnviron interface.
+// Each of the cloud's libvirt hosts is an availability zone, which
+// is available if the host can be reached.
+func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	zones := make([]common.AvailabilityZone, len(env.hosts))
+	for i, host := range env.hosts {
+		err := host.client.Ping()
+		if err != nil {
+			logger.Warningf("libvirt host %q is unavailable: %v", host.zone, err)
+		}
+		zones[i] = &libvirtAvailZone{host: host, available: err == nil}
+	}
+	return zones, nil
+}
+
+// InstanceAvailabilityZoneNames is part of the common.ZonedEnviron interface.
+func (env *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	instances, err := env.Instances(ids)
+	if err != nil && err != environs.ErrPartialInstances {
+		return nil, err
+	}
+	results := make([]string, len(ids))
+	for i, inst := range instances {
+		if inst != nil {
+			results[i] = inst.(*environInstance).host.zone
+		}
+	}
+	return results, err
+}
+
+// DeriveAvailabilityZones is part of the common.ZonedEnviron interface.
+func (env *environ) DeriveAvailabilityZones(args environs.StartInstanceParams) ([]string, error) {
+	zone, err := env.parsePlacement(args.Placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Volumes live in the storage pools of a particular host,
+	// and so can only be attached to instances on that host.
+	for _, a := range args.VolumeAttachments {
+		volumeZone, _, err := parseVolumeId(a.VolumeId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if zone != "" && zone != volumeZone {
+			return nil, errors.Errorf(
+				"cannot create instance with placement %q, as this will prevent attaching the requested volumes in zone %q",
+				args.Placement, volumeZone,
+			)
+		}
+		zone = volumeZone
+	}
+	if zone == "" {
+		return nil, nil
+	}
+	return []string{zone}, nil
+}
+
+// parsePlacement extracts the availability zone from the placement
+// string and returns it. If the placement is not empty and no zone is
+// found there then an error is returned.
+func (env *environ) parsePlacement(placement string) (string, error) {
+	if placement == "" {
+		return "", nil
+	}
+
+	pos := strings.IndexRune(placement, '=')
+	if pos == -1 {
+		return "", errors.Errorf("unknown placement directive: %v", placement)
+	}
+
+	switch key, value := placement[:pos], placement[pos+1:]; key {
+	case "zone":
+		if _, err := env.host(value); err != nil {
+			return "", errors.Trace(err)
+		}
+		return value, nil
+	}
+	return "", errors.Errorf("unknown placement directive: %v", placement)
+}
+
+// DistributeInstances implements the state.InstanceDistributor policy.
+func (env *environ) DistributeInstances(candidates, distributionGroup []instance.Id) ([]instance.Id, error) {
+	return common.DistributeInstances(env, candidates, distributionGroup)
+}
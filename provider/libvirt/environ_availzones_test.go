@@ -0,0 +1,80 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/storage"
+)
+
+type environAvailzonesSuite struct {
+	EnvironFixture
+}
+
+var _ = gc.Suite(&environAvailzonesSuite{})
+
+func (s *environAvailzonesSuite) TestAvailabilityZones(c *gc.C) {
+	s.client2.SetErrors(errors.New("unreachable"))
+	zonedEnviron := s.env.(common.ZonedEnviron)
+	zones, err := zonedEnviron.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 2)
+	c.Assert(zones[0].Name(), gc.Equals, "host1")
+	c.Assert(zones[0].Available(), jc.IsTrue)
+	c.Assert(zones[1].Name(), gc.Equals, "host2")
+	c.Assert(zones[1].Available(), jc.IsFalse)
+}
+
+func (s *environAvailzonesSuite) TestPrecheckInstanceUnknownZone(c *gc.C) {
+	err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Series:    "bionic",
+		Placement: "zone=host3",
+	})
+	c.Assert(err, gc.ErrorMatches, `availability zone "host3" not found`)
+}
+
+func (s *environAvailzonesSuite) TestPrecheckInstanceUnknownPlacement(c *gc.C) {
+	err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Series:    "bionic",
+		Placement: "host=host1",
+	})
+	c.Assert(err, gc.ErrorMatches, `unknown placement directive: host=host1`)
+}
+
+func (s *environAvailzonesSuite) TestDeriveAvailabilityZones(c *gc.C) {
+	zonedEnviron := s.env.(common.ZonedEnviron)
+	zones, err := zonedEnviron.DeriveAvailabilityZones(environs.StartInstanceParams{
+		Placement: "zone=host2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"host2"})
+}
+
+func (s *environAvailzonesSuite) TestDeriveAvailabilityZonesVolumeAttachments(c *gc.C) {
+	zonedEnviron := s.env.(common.ZonedEnviron)
+	zones, err := zonedEnviron.DeriveAvailabilityZones(environs.StartInstanceParams{
+		VolumeAttachments: []storage.VolumeAttachmentParams{{
+			VolumeId: "host2/juju-f75cba-volume-0",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"host2"})
+}
+
+func (s *environAvailzonesSuite) TestDeriveAvailabilityZonesConflict(c *gc.C) {
+	zonedEnviron := s.env.(common.ZonedEnviron)
+	_, err := zonedEnviron.DeriveAvailabilityZones(environs.StartInstanceParams{
+		Placement: "zone=host1",
+		VolumeAttachments: []storage.VolumeAttachmentParams{{
+			VolumeId: "host2/juju-f75cba-volume-0",
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot create instance with placement "zone=host1", as this will prevent attaching the requested volumes in zone "host2"`)
+}
@@ -0,0 +1,437 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/tools"
+)
+
+const (
+	// defaultMemMiB is the memory of instances
+	// without a mem constraint.
+	defaultMemMiB = 2048
+
+	// defaultCores is the number of CPU cores of
+	// instances without a cores constraint.
+	defaultCores = 1
+
+	// nvramCode is the path on the host of the UEFI firmware
+	// used to boot ARM64 instances.
+	nvramCode = "/usr/share/AAVMF/AAVMF_CODE.fd"
+
+	// macAddressTemplate is used to generate the MAC addresses of
+	// instances' network interfaces, within the range used by QEMU.
+	macAddressTemplate = "52:54:00:%02x:%02x:%02x"
+)
+
+// MaintainInstance is specified in the InstanceBroker interface.
+func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
+	return nil
+}
+
+// StartInstance implements environs.InstanceBroker.
+func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	host, err := env.instanceHost(args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	imageArch, err := env.finishInstanceConfig(args)
+	if err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
+
+	name, hw, err := env.newDomain(host, imageArch, args)
+	if err != nil {
+		if args.StatusCallback != nil {
+			args.StatusCallback(status.ProvisioningError, err.Error(), nil)
+		}
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("started instance %q on %q", name, host.zone)
+
+	inst := newInstance(DomainState{Name: name, State: "running"}, host)
+	result := environs.StartInstanceResult{
+		Instance: inst,
+		Hardware: hw,
+	}
+	return &result, nil
+}
+
+// instanceHost returns the host, i.e. the availability zone, that the
+// instance should be started on. The zone chosen by the provisioner
+// takes precedence over one given by placement.
+func (env *environ) instanceHost(args environs.StartInstanceParams) (*libvirtHost, error) {
+	zone := args.AvailabilityZone
+	if zone == "" {
+		var err error
+		zone, err = env.parsePlacement(args.Placement)
+		if err != nil {
+			return nil, common.ZoneIndependentError(err)
+		}
+	}
+	if zone == "" {
+		return env.hosts[0], nil
+	}
+	return env.host(zone)
+}
+
+// finishInstanceConfig updates args.InstanceConfig in place, and returns
+// the architecture of the instance to start.
+func (env *environ) finishInstanceConfig(args environs.StartInstanceParams) (string, error) {
+	imageArch := arch.AMD64
+	if args.Constraints.HasArch() {
+		imageArch = *args.Constraints.Arch
+	}
+	envTools, err := args.Tools.Match(tools.Filter{Arch: imageArch})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := args.InstanceConfig.SetTools(envTools); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := instancecfg.FinishInstanceConfig(args.InstanceConfig, env.Config()); err != nil {
+		return "", errors.Trace(err)
+	}
+	return imageArch, nil
+}
+
+// newDomain creates and starts a domain on the host for the instance,
+// and returns its name and hardware characteristics.
+func (env *environ) newDomain(
+	host *libvirtHost,
+	imageArch string,
+	args environs.StartInstanceParams,
+) (_ string, _ *instance.HardwareCharacteristics, err error) {
+	name, err := env.namespace.Hostname(args.InstanceConfig.MachineId)
+	if err != nil {
+		return "", nil, common.ZoneIndependentError(err)
+	}
+	series := args.InstanceConfig.Series
+	cloudcfg, err := cloudinit.New(series)
+	if err != nil {
+		return "", nil, common.ZoneIndependentError(err)
+	}
+	// Make sure the hostname is resolvable by adding it to /etc/hosts.
+	cloudcfg.ManageEtcHosts(true)
+	if args.InstanceConfig.Controller != nil {
+		// The controller drives the libvirt hosts with virsh,
+		// and creates the cloud-init data source images of the
+		// instances it starts with genisoimage.
+		cloudcfg.AddPackage("libvirt-clients")
+		cloudcfg.AddPackage("genisoimage")
+	}
+	userData, err := providerinit.ComposeUserData(args.InstanceConfig, cloudcfg, LibvirtRenderer{})
+	if err != nil {
+		return "", nil, common.ZoneIndependentError(
+			errors.Annotate(err, "cannot make user data"),
+		)
+	}
+	logger.Debugf("libvirt user data; %d bytes", len(userData))
+
+	// Obtain the final constraints by merging with defaults.
+	cons := args.Constraints
+	minRootDisk := common.MinRootDiskSizeGiB(series) * 1024
+	if cons.RootDisk == nil || *cons.RootDisk < minRootDisk {
+		cons.RootDisk = &minRootDisk
+	}
+	if cons.Mem == nil {
+		mem := uint64(defaultMemMiB)
+		cons.Mem = &mem
+	}
+	if cons.CpuCores == nil {
+		cores := uint64(defaultCores)
+		cons.CpuCores = &cores
+	}
+
+	updateProgress := func(message string) {
+		if args.StatusCallback != nil {
+			args.StatusCallback(status.Provisioning, message, nil)
+		}
+	}
+	ecfg := env.envConfig()
+	pool := ecfg.storagePool()
+	bridge, err := host.client.NetworkBridge(ecfg.network())
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	image, err := env.ensureImage(host, pool, series, imageArch, updateProgress)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+
+	// Remove anything we have created if we fail
+	// to start the domain.
+	var created []string
+	var defined bool
+	defer func() {
+		if err == nil {
+			return
+		}
+		if defined {
+			if err := host.client.UndefineDomain(name); err != nil {
+				logger.Errorf("failed to undefine domain %q: %v", name, err)
+			}
+		}
+		for _, path := range created {
+			if err := host.client.DeleteVolume(path); err != nil {
+				logger.Errorf("failed to delete volume %q: %v", path, err)
+			}
+		}
+	}()
+
+	updateProgress("creating root disk")
+	rootDisk, err := createVolume(host.client, pool, CreateVolumeParams{
+		Name:          name + "-root.img",
+		Size:          *cons.RootDisk * bytesPerMiB,
+		Format:        "qcow2",
+		BackingVolume: image,
+	}, "")
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	created = append(created, rootDisk.Path)
+
+	dataSource, err := uploadDataSource(host.client, pool, name, userData)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	created = append(created, dataSource.Path)
+
+	params := domainParams{
+		name:   name,
+		arch:   imageArch,
+		cpus:   *cons.CpuCores,
+		memMiB: *cons.Mem,
+		disks: []kvmlibvirt.DiskInfo{
+			diskInfo{source: rootDisk.Path, driver: "qcow2"},
+			diskInfo{source: dataSource.Path, driver: "raw"},
+		},
+		interfaces: []kvmlibvirt.InterfaceInfo{
+			interfaceInfo{
+				macAddress: generateMACAddress(),
+				bridge:     bridge,
+				name:       "eth0",
+			},
+		},
+	}
+	domain, err := kvmlibvirt.NewDomain(params)
+	if err != nil {
+		return "", nil, common.ZoneIndependentError(err)
+	}
+	domain.Description = formatDescription(args.InstanceConfig.Tags)
+
+	updateProgress("starting domain")
+	if err := host.client.DefineDomain(domain); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	defined = true
+	if err := host.client.StartDomain(name); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+
+	zone := host.zone
+	hw := &instance.HardwareCharacteristics{
+		Arch:             &imageArch,
+		Mem:              cons.Mem,
+		CpuCores:         cons.CpuCores,
+		RootDisk:         cons.RootDisk,
+		AvailabilityZone: &zone,
+	}
+	return name, hw, nil
+}
+
+// createVolume creates a volume in the storage pool, uploads the
+// content of the local file at path to it if path is non-empty, and
+// returns the volume.
+func createVolume(client Client, pool string, params CreateVolumeParams, path string) (Volume, error) {
+	if err := client.CreateVolume(pool, params); err != nil {
+		return Volume{}, errors.Trace(err)
+	}
+	volume, err := client.Volume(pool, params.Name)
+	if err != nil {
+		return Volume{}, errors.Trace(err)
+	}
+	if path != "" {
+		if err := client.UploadVolume(pool, params.Name, path); err != nil {
+			if err := client.DeleteVolume(volume.Path); err != nil {
+				logger.Errorf("failed to delete volume %q: %v", volume.Path, err)
+			}
+			return Volume{}, errors.Trace(err)
+		}
+	}
+	return volume, nil
+}
+
+// uploadDataSource creates the cloud-init data source image holding
+// the user data for the named domain, and uploads it to a volume in
+// the storage pool.
+func uploadDataSource(client Client, pool, name string, userData []byte) (Volume, error) {
+	dir, err := ioutil.TempDir("", "juju-libvirt-")
+	if err != nil {
+		return Volume{}, errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+	path, err := newDataSourceImage(dir, name, userData)
+	if err != nil {
+		return Volume{}, errors.Trace(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Volume{}, errors.Trace(err)
+	}
+	return createVolume(client, pool, CreateVolumeParams{
+		Name:   name + "-cidata.iso",
+		Size:   uint64(info.Size()),
+		Format: "raw",
+	}, path)
+}
+
+// StopInstances implements environs.InstanceBroker.
+func (env *environ) StopInstances(ids ...instance.Id) error {
+	var errIds []instance.Id
+	var errs []error
+	for _, id := range ids {
+		inst, err := env.instance(id)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err == nil {
+			err = destroyDomain(inst.host.client, string(id))
+		}
+		if err != nil {
+			errIds = append(errIds, id)
+			errs = append(errs, err)
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errors.Annotatef(errs[0], "failed to stop instance %s", errIds[0])
+	default:
+		return errors.Errorf(
+			"failed to stop instances %s: %s",
+			errIds, errs,
+		)
+	}
+}
+
+// generateMACAddress creates a random MAC address within the space
+// defined by macAddressTemplate.
+func generateMACAddress() string {
+	digits := make([]interface{}, 3)
+	for i := range digits {
+		digits[i] = rand.Intn(256)
+	}
+	return fmt.Sprintf(macAddressTemplate, digits...)
+}
+
+// domainParams implements the domain parameters required by
+// kvmlibvirt.NewDomain.
+type domainParams struct {
+	name       string
+	arch       string
+	cpus       uint64
+	memMiB     uint64
+	disks      []kvmlibvirt.DiskInfo
+	interfaces []kvmlibvirt.InterfaceInfo
+}
+
+// Arch is part of the kvmlibvirt domain parameters.
+func (p domainParams) Arch() string {
+	return p.arch
+}
+
+// CPUs is part of the kvmlibvirt domain parameters.
+func (p domainParams) CPUs() uint64 {
+	return p.cpus
+}
+
+// DiskInfo is part of the kvmlibvirt domain parameters.
+func (p domainParams) DiskInfo() []kvmlibvirt.DiskInfo {
+	return p.disks
+}
+
+// Host is part of the kvmlibvirt domain parameters.
+func (p domainParams) Host() string {
+	return p.name
+}
+
+// Loader is part of the kvmlibvirt domain parameters.
+func (p domainParams) Loader() string {
+	return nvramCode
+}
+
+// NetworkInfo is part of the kvmlibvirt domain parameters.
+func (p domainParams) NetworkInfo() []kvmlibvirt.InterfaceInfo {
+	return p.interfaces
+}
+
+// RAM is part of the kvmlibvirt domain parameters.
+func (p domainParams) RAM() uint64 {
+	return p.memMiB
+}
+
+// ValidateDomainParams is part of the kvmlibvirt domain parameters.
+func (p domainParams) ValidateDomainParams() error {
+	if p.name == "" {
+		return errors.Errorf("missing required hostname")
+	}
+	if len(p.disks) < 2 {
+		// We need at least the root disk and the data source disk.
+		return errors.Errorf("got %d disks, need at least 2", len(p.disks))
+	}
+	return nil
+}
+
+// diskInfo implements kvmlibvirt.DiskInfo.
+type diskInfo struct {
+	driver, source string
+}
+
+// Driver implements kvmlibvirt.DiskInfo.
+func (d diskInfo) Driver() string {
+	return d.driver
+}
+
+// Source implements kvmlibvirt.DiskInfo.
+func (d diskInfo) Source() string {
+	return d.source
+}
+
+// interfaceInfo implements kvmlibvirt.InterfaceInfo.
+type interfaceInfo struct {
+	macAddress, bridge, name string
+}
+
+// MACAddress implements kvmlibvirt.InterfaceInfo.
+func (i interfaceInfo) MACAddress() string {
+	return i.macAddress
+}
+
+// ParentInterfaceName implements kvmlibvirt.InterfaceInfo.
+func (i interfaceInfo) ParentInterfaceName() string {
+	return i.bridge
+}
+
+// InterfaceName implements kvmlibvirt.InterfaceInfo.
+func (i interfaceInfo) InterfaceName() string {
+	return i.name
+}
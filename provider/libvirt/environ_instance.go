@@ -0,0 +1,168 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/status"
+)
+
+var _ environs.InstanceRestarter = (*environ)(nil)
+
+// hostDomains returns the model's domains on the given host.
+func (env *environ) hostDomains(host *libvirtHost) ([]DomainState, error) {
+	domains, err := host.client.Domains()
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing domains on %q", host.zone)
+	}
+	prefix := env.namespace.Prefix()
+	var result []DomainState
+	for _, domain := range domains {
+		if strings.HasPrefix(domain.Name, prefix) {
+			result = append(result, domain)
+		}
+	}
+	return result, nil
+}
+
+// AllInstances implements environs.InstanceBroker.
+func (env *environ) AllInstances() ([]instance.Instance, error) {
+	var results []instance.Instance
+	for _, host := range env.hosts {
+		domains, err := env.hostDomains(host)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, domain := range domains {
+			results = append(results, newInstance(domain, host))
+		}
+	}
+	return results, nil
+}
+
+// Instances is part of the environs.Environ interface.
+func (env *environ) Instances(ids []instance.Id) ([]instance.Instance, error) {
+	if len(ids) == 0 {
+		return nil, environs.ErrNoInstances
+	}
+
+	allInstances, err := env.AllInstances()
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to get instances")
+	}
+	findInst := func(id instance.Id) instance.Instance {
+		for _, inst := range allInstances {
+			if id == inst.Id() {
+				return inst
+			}
+		}
+		return nil
+	}
+
+	var numFound int
+	results := make([]instance.Instance, len(ids))
+	for i, id := range ids {
+		if inst := findInst(id); inst != nil {
+			results[i] = inst
+			numFound++
+		}
+	}
+	if numFound == 0 {
+		return nil, environs.ErrNoInstances
+	} else if numFound != len(ids) {
+		err = environs.ErrPartialInstances
+	}
+	return results, err
+}
+
+// instance returns the instance with the given ID.
+func (env *environ) instance(id instance.Id) (*environInstance, error) {
+	instances, err := env.Instances([]instance.Id{id})
+	if err == environs.ErrNoInstances {
+		return nil, errors.NotFoundf("instance %q", id)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return instances[0].(*environInstance), nil
+}
+
+// ControllerInstances is part of the environs.Environ interface.
+func (env *environ) ControllerInstances(controllerUUID string) ([]instance.Id, error) {
+	instances, err := env.AllInstances()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var results []instance.Id
+	for _, inst := range instances {
+		inst := inst.(*environInstance)
+		def, err := inst.host.client.Domain(string(inst.Id()))
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		domainTags := parseDescription(def.Description)
+		if domainTags[tags.JujuIsController] == "true" &&
+			domainTags[tags.JujuController] == controllerUUID {
+			results = append(results, inst.Id())
+		}
+	}
+	if len(results) == 0 {
+		return nil, environs.ErrNotBootstrapped
+	}
+	return results, nil
+}
+
+// destroyDomain undefines the named domain, and deletes the volumes
+// that were created for it. Any other volumes attached to the domain,
+// i.e. those of Juju storage, are left alone.
+func destroyDomain(client Client, name string) error {
+	def, err := client.Domain(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if err := client.UndefineDomain(name); err != nil {
+		return errors.Trace(err)
+	}
+	for _, disk := range def.Disk {
+		source := disk.Source.File
+		if source == "" {
+			source = disk.Source.Dev
+		}
+		if !strings.HasPrefix(path.Base(source), name+"-") {
+			continue
+		}
+		if err := client.DeleteVolume(source); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// RestartInstances is part of the environs.InstanceRestarter interface.
+func (env *environ) RestartInstances(ids ...instance.Id) error {
+	for _, id := range ids {
+		inst, err := env.instance(id)
+		if err != nil {
+			return errors.Annotatef(err, "failed to restart instance %s", id)
+		}
+		if inst.Status().Status == status.Running {
+			continue
+		}
+		if err := inst.host.client.StartDomain(string(id)); err != nil {
+			return errors.Annotatef(err, "failed to restart instance %s", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/libvirt"
+	"github.com/juju/juju/status"
+)
+
+type environInstanceSuite struct {
+	EnvironFixture
+}
+
+var _ = gc.Suite(&environInstanceSuite{})
+
+func (s *environInstanceSuite) SetUpTest(c *gc.C) {
+	s.EnvironFixture.SetUpTest(c)
+	s.client1.domains = []libvirt.DomainState{
+		{Name: "juju-f75cba-0", State: "running"},
+		{Name: "not-juju", State: "running"},
+	}
+	s.client2.domains = []libvirt.DomainState{
+		{Name: "juju-f75cba-1", State: "shut off"},
+		{Name: "juju-abcdef-0", State: "running"},
+	}
+}
+
+func (s *environInstanceSuite) TestAllInstances(c *gc.C) {
+	instances, err := s.env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[0].Id(), gc.Equals, instance.Id("juju-f75cba-0"))
+	c.Assert(instances[0].Status().Status, gc.Equals, status.Running)
+	c.Assert(instances[1].Id(), gc.Equals, instance.Id("juju-f75cba-1"))
+	c.Assert(instances[1].Status().Status, gc.Equals, status.Empty)
+	c.Assert(instances[1].Status().Message, gc.Equals, "shut off")
+}
+
+func (s *environInstanceSuite) TestInstancesPartial(c *gc.C) {
+	instances, err := s.env.Instances([]instance.Id{"juju-f75cba-1", "juju-f75cba-2"})
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[0].Id(), gc.Equals, instance.Id("juju-f75cba-1"))
+	c.Assert(instances[1], gc.IsNil)
+}
+
+func (s *environInstanceSuite) TestInstancesNone(c *gc.C) {
+	_, err := s.env.Instances([]instance.Id{"juju-f75cba-2"})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environInstanceSuite) TestInstanceAddresses(c *gc.C) {
+	s.client1.addresses = []string{"192.168.122.10"}
+	instances, err := s.env.Instances([]instance.Id{"juju-f75cba-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	addresses, err := instances[0].Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, gc.HasLen, 1)
+	c.Assert(addresses[0].Value, gc.Equals, "192.168.122.10")
+}
+
+func (s *environInstanceSuite) TestControllerInstances(c *gc.C) {
+	s.client1.domainDef = map[string]*kvmlibvirt.Domain{
+		"juju-f75cba-0": {
+			Name:        "juju-f75cba-0",
+			Description: "juju-controller-uuid=deadbeef\njuju-is-controller=true",
+		},
+	}
+	s.client2.domainDef = map[string]*kvmlibvirt.Domain{
+		"juju-f75cba-1": {
+			Name:        "juju-f75cba-1",
+			Description: "juju-controller-uuid=deadbeef",
+		},
+	}
+	ids, err := s.env.ControllerInstances("deadbeef")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []instance.Id{"juju-f75cba-0"})
+}
+
+func (s *environInstanceSuite) TestControllerInstancesNotBootstrapped(c *gc.C) {
+	_, err := s.env.ControllerInstances("deadbeef")
+	c.Assert(err, gc.Equals, environs.ErrNotBootstrapped)
+}
+
+func (s *environInstanceSuite) TestStopInstances(c *gc.C) {
+	domain := &kvmlibvirt.Domain{Name: "juju-f75cba-1"}
+	for _, path := range []string{
+		"/var/lib/libvirt/images/juju-f75cba-1-root.img",
+		"/var/lib/libvirt/images/juju-f75cba-1-cidata.iso",
+		"/var/lib/libvirt/images/juju-f75cba-volume-0",
+	} {
+		domain.Disk = append(domain.Disk, kvmlibvirt.Disk{
+			Source: kvmlibvirt.DiskSource{File: path},
+		})
+	}
+	s.client2.domainDef = map[string]*kvmlibvirt.Domain{"juju-f75cba-1": domain}
+
+	err := s.env.StopInstances("juju-f75cba-1", "juju-f75cba-2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volumes of Juju storage are left alone.
+	s.client2.CheckCallNames(c,
+		"Domains", "Domain", "UndefineDomain", "DeleteVolume", "DeleteVolume",
+		"Domains",
+	)
+	s.client2.CheckCall(c, 2, "UndefineDomain", "juju-f75cba-1")
+	s.client2.CheckCall(c, 3, "DeleteVolume", "/var/lib/libvirt/images/juju-f75cba-1-root.img")
+	s.client2.CheckCall(c, 4, "DeleteVolume", "/var/lib/libvirt/images/juju-f75cba-1-cidata.iso")
+}
+
+func (s *environInstanceSuite) TestAdoptResources(c *gc.C) {
+	s.client1.domainDef = map[string]*kvmlibvirt.Domain{
+		"juju-f75cba-0": {
+			Name:        "juju-f75cba-0",
+			Description: "juju-controller-uuid=deadbeef\njuju-model-uuid=2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+		},
+	}
+	s.client2.domainDef = map[string]*kvmlibvirt.Domain{
+		"juju-f75cba-1": {
+			Name:        "juju-f75cba-1",
+			Description: "juju-controller-uuid=feedface",
+		},
+	}
+	err := s.env.AdoptResources("feedface", version.MustParse("2.4.0"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.client1.CheckCallNames(c, "Domains", "Domain", "SetDescription")
+	s.client1.CheckCall(c, 2, "SetDescription", "juju-f75cba-0",
+		"juju-controller-uuid=feedface\njuju-model-uuid=2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+	)
+	s.client2.CheckCallNames(c, "Domains", "Domain")
+}
+
+func (s *environInstanceSuite) TestRestartInstances(c *gc.C) {
+	err := s.env.(environs.InstanceRestarter).RestartInstances("juju-f75cba-0", "juju-f75cba-1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Running instances are left alone.
+	s.client1.CheckCallNames(c, "Domains", "Domains")
+	s.client2.CheckCallNames(c, "Domains", "Domains", "StartDomain")
+	s.client2.CheckCall(c, 2, "StartDomain", "juju-f75cba-1")
+}
+
+func (s *environInstanceSuite) TestRestartInstancesNotFound(c *gc.C) {
+	err := s.env.(environs.InstanceRestarter).RestartInstances("juju-f75cba-2")
+	c.Assert(err, gc.ErrorMatches, `failed to restart instance juju-f75cba-2: instance "juju-f75cba-2" not found`)
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/common"
+)
+
+// PrecheckInstance is part of the environs.Environ interface.
+func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error {
+	if _, err := env.parsePlacement(args.Placement); err != nil {
+		return errors.Trace(err)
+	}
+	return common.ValidateZonesConstraint(env, args)
+}
+
+var unsupportedConstraints = []string{
+	constraints.CpuPower,
+	constraints.InstanceType,
+	constraints.Tags,
+	constraints.VirtType,
+	constraints.Interruptible,
+	constraints.MaxPrice,
+}
+
+// ConstraintsValidator returns a Validator value which is used to
+// validate and merge constraints.
+func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
+	validator := constraints.NewValidator()
+	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterVocabulary(constraints.Arch, []string{
+		arch.AMD64, arch.ARM64,
+	})
+	return validator, nil
+}
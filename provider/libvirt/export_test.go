@@ -0,0 +1,12 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+// NewVirshClient returns a Client for the libvirt host with the given
+// connection URI, which runs virsh commands with the given function.
+func NewVirshClient(uri string, run func(string, ...string) (string, error)) Client {
+	return &virshClient{uri: uri, runCmd: run}
+}
+
+var WriteCredential = writeCredential
@@ -0,0 +1,55 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/libvirt"
+)
+
+const (
+	host1URI = "qemu+ssh://ubuntu@host1/system"
+	host2URI = "qemu+ssh://ubuntu@host2/system"
+)
+
+type ProviderFixture struct {
+	testing.IsolationSuite
+	client1        *mockClient
+	client2        *mockClient
+	credentialsDir string
+	provider       environs.CloudEnvironProvider
+}
+
+func (s *ProviderFixture) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.client1 = &mockClient{bridge: "virbr0"}
+	s.client2 = &mockClient{bridge: "virbr0"}
+	s.credentialsDir = c.MkDir()
+	s.provider = libvirt.NewEnvironProvider(libvirt.EnvironProviderConfig{
+		NewClient: newMockClientFunc(map[string]*mockClient{
+			host1URI: s.client1,
+			host2URI: s.client2,
+		}),
+		CredentialsDir: func() string { return s.credentialsDir },
+	})
+}
+
+type EnvironFixture struct {
+	ProviderFixture
+	env environs.Environ
+}
+
+func (s *EnvironFixture) SetUpTest(c *gc.C) {
+	s.ProviderFixture.SetUpTest(c)
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud:  fakeCloudSpec(),
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.env = env
+}
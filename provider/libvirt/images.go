@@ -0,0 +1,147 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/environs/imagedownloads"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+const (
+	// biosImageFileType is the file type of the cloud images
+	// for instances which boot using a legacy BIOS boot loader.
+	biosImageFileType = "disk1.img"
+
+	// uefiImageFileType is the file type of the cloud images
+	// for instances which boot using UEFI, i.e. ARM64.
+	uefiImageFileType = "uefi1.img"
+)
+
+// imageVolumeName returns the name of the volume holding the cloud
+// image for the given series and architecture. The volume backs the
+// root disks of all instances with that series and architecture.
+func imageVolumeName(series, arch string) string {
+	return fmt.Sprintf("juju-image-%s-%s.img", series, arch)
+}
+
+// findImageMetadata returns the metadata of the cloud image for the
+// given series and architecture, from the image-downloads simplestreams
+// data of the given image stream.
+var findImageMetadata = func(series, imageArch, stream string) (*imagedownloads.Metadata, error) {
+	ftype := biosImageFileType
+	if imageArch == arch.ARM64 {
+		ftype = uefiImageFileType
+	}
+	baseURL := imagemetadata.UbuntuCloudImagesURL + "/" + stream
+	src := func() simplestreams.DataSource {
+		return imagedownloads.NewDataSource(baseURL)
+	}
+	return imagedownloads.One(imageArch, series, ftype, src)
+}
+
+// ensureImage ensures that the cloud image for the given series and
+// architecture is in the host's storage pool, downloading it from
+// cloud-images.ubuntu.com and uploading it to the host if necessary,
+// and returns the name of its volume.
+func (env *environ) ensureImage(host *libvirtHost, pool, series, imageArch string, progress func(string)) (string, error) {
+	env.imageMutex.Lock()
+	defer env.imageMutex.Unlock()
+
+	name := imageVolumeName(series, imageArch)
+	volumes, err := host.client.Volumes(pool)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, volume := range volumes {
+		if volume.Name == name {
+			return name, nil
+		}
+	}
+
+	md, err := findImageMetadata(series, imageArch, env.Config().ImageStream())
+	if err != nil {
+		return "", errors.Annotatef(err, "finding %s %s image", series, imageArch)
+	}
+	url, err := md.DownloadURL()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	progress(fmt.Sprintf("downloading image %s", url))
+	path, err := downloadImage(url.String(), md.SHA256)
+	if err != nil {
+		return "", errors.Annotatef(err, "downloading image %s", url)
+	}
+	defer os.Remove(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	// The cloud images are qcow2 images, and the volume is the
+	// qcow2 backing volume of the instances' root disks.
+	progress(fmt.Sprintf("uploading image to %s", host.zone))
+	if err := host.client.CreateVolume(pool, CreateVolumeParams{
+		Name:   name,
+		Size:   uint64(info.Size()),
+		Format: "qcow2",
+	}); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := host.client.UploadVolume(pool, name, path); err != nil {
+		// Don't leave a partial image behind.
+		if volume, err := host.client.Volume(pool, name); err == nil {
+			if err := host.client.DeleteVolume(volume.Path); err != nil {
+				logger.Errorf("failed to delete volume %q: %v", name, err)
+			}
+		}
+		return "", errors.Trace(err)
+	}
+	return name, nil
+}
+
+// downloadImage downloads the image at the given URL to a temporary
+// file, verifying its SHA256 hash, and returns the file's path.
+func downloadImage(url, sha256sum string) (_ string, err error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("got %s fetching image", resp.Status)
+	}
+
+	f, err := ioutil.TempFile("", "juju-libvirt-image-")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = errors.Trace(closeErr)
+		}
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), resp.Body); err != nil {
+		return "", errors.Trace(err)
+	}
+	if result := fmt.Sprintf("%x", hash.Sum(nil)); result != sha256sum {
+		return "", errors.Errorf("hash sum mismatch: %s != %s", result, sha256sum)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/status"
+)
+
+type environInstance struct {
+	domain DomainState
+	host   *libvirtHost
+}
+
+var _ instance.Instance = (*environInstance)(nil)
+
+func newInstance(domain DomainState, host *libvirtHost) *environInstance {
+	return &environInstance{
+		domain: domain,
+		host:   host,
+	}
+}
+
+// Id implements instance.Instance.
+func (inst *environInstance) Id() instance.Id {
+	return instance.Id(inst.domain.Name)
+}
+
+// Status implements instance.Instance.
+func (inst *environInstance) Status() instance.InstanceStatus {
+	instanceStatus := instance.InstanceStatus{
+		Status:  status.Empty,
+		Message: inst.domain.State,
+	}
+	switch inst.domain.State {
+	case "running", "idle":
+		instanceStatus.Status = status.Running
+	}
+	return instanceStatus
+}
+
+// Addresses implements instance.Instance.
+func (inst *environInstance) Addresses() ([]network.Address, error) {
+	addresses, err := inst.host.client.DomainAddresses(inst.domain.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return network.NewAddresses(addresses...), nil
+}
+
+// OpenPorts opens the given ports on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) OpenPorts(machineID string, rules []network.IngressRule) error {
+	// Instances are connected directly to the host's networks,
+	// and there is no firewall to configure.
+	return nil
+}
+
+// ClosePorts closes the given ports on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) ClosePorts(machineID string, rules []network.IngressRule) error {
+	return nil
+}
+
+// IngressRules returns the set of ports open on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) IngressRules(machineID string) ([]network.IngressRule, error) {
+	return nil, nil
+}
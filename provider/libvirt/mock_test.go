@@ -0,0 +1,168 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/provider/libvirt"
+)
+
+func newMockClientFunc(clients map[string]*mockClient) libvirt.NewClientFunc {
+	return func(uri string) libvirt.Client {
+		return clients[uri]
+	}
+}
+
+type mockClient struct {
+	// mu guards testing.Stub access, to ensure that the recorded
+	// method calls correspond to the errors returned.
+	mu sync.Mutex
+	testing.Stub
+
+	domains   []libvirt.DomainState
+	domainDef map[string]*kvmlibvirt.Domain
+	addresses []string
+	bridge    string
+	volumes   []libvirt.Volume
+}
+
+func (c *mockClient) Ping() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Ping")
+	return c.NextErr()
+}
+
+func (c *mockClient) Domains() ([]libvirt.DomainState, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Domains")
+	return c.domains, c.NextErr()
+}
+
+func (c *mockClient) Domain(name string) (*kvmlibvirt.Domain, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Domain", name)
+	if err := c.NextErr(); err != nil {
+		return nil, err
+	}
+	domain, ok := c.domainDef[name]
+	if !ok {
+		return nil, errors.NotFoundf("domain %q", name)
+	}
+	return domain, nil
+}
+
+func (c *mockClient) DefineDomain(domain kvmlibvirt.Domain) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DefineDomain", domain)
+	return c.NextErr()
+}
+
+func (c *mockClient) SetDescription(name, description string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "SetDescription", name, description)
+	return c.NextErr()
+}
+
+func (c *mockClient) StartDomain(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "StartDomain", name)
+	return c.NextErr()
+}
+
+func (c *mockClient) UndefineDomain(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "UndefineDomain", name)
+	return c.NextErr()
+}
+
+func (c *mockClient) DomainAddresses(name string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DomainAddresses", name)
+	return c.addresses, c.NextErr()
+}
+
+func (c *mockClient) AttachDisk(domain, source, target, serial string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "AttachDisk", domain, source, target, serial)
+	return c.NextErr()
+}
+
+func (c *mockClient) DetachDisk(domain, target string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DetachDisk", domain, target)
+	return c.NextErr()
+}
+
+func (c *mockClient) NetworkBridge(network string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "NetworkBridge", network)
+	return c.bridge, c.NextErr()
+}
+
+func (c *mockClient) Volumes(pool string) ([]libvirt.Volume, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Volumes", pool)
+	return c.volumes, c.NextErr()
+}
+
+func (c *mockClient) Volume(pool, name string) (libvirt.Volume, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Volume", pool, name)
+	if err := c.NextErr(); err != nil {
+		return libvirt.Volume{}, err
+	}
+	for _, volume := range c.volumes {
+		if volume.Name == name {
+			return volume, nil
+		}
+	}
+	return libvirt.Volume{}, errors.NotFoundf("volume %q in storage pool %q", name, pool)
+}
+
+func (c *mockClient) CreateVolume(pool string, params libvirt.CreateVolumeParams) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "CreateVolume", pool, params)
+	if err := c.NextErr(); err != nil {
+		return err
+	}
+	c.volumes = append(c.volumes, libvirt.Volume{
+		Name: params.Name,
+		Path: "/var/lib/libvirt/images/" + params.Name,
+		Size: params.Size,
+	})
+	return nil
+}
+
+func (c *mockClient) UploadVolume(pool, name, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "UploadVolume", pool, name, path)
+	return c.NextErr()
+}
+
+func (c *mockClient) DeleteVolume(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DeleteVolume", path)
+	return c.NextErr()
+}
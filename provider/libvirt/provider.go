@@ -0,0 +1,233 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/jsonschema"
+	"github.com/juju/loggo"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
+
+var logger = loggo.GetLogger("juju.provider.libvirt")
+
+// NewClientFunc returns a Client for the libvirt host with the given
+// connection URI.
+type NewClientFunc func(uri string) Client
+
+type environProvider struct {
+	environProviderCredentials
+	newClient      NewClientFunc
+	credentialsDir func() string
+}
+
+// EnvironProviderConfig contains configuration for the EnvironProvider.
+type EnvironProviderConfig struct {
+	// NewClient is a function used to create clients for
+	// libvirt hosts.
+	NewClient NewClientFunc
+
+	// CredentialsDir is a function that returns the directory
+	// in which credentials are written out for the clients.
+	CredentialsDir func() string
+}
+
+// NewEnvironProvider returns a new environs.EnvironProvider that will
+// drive libvirt hosts with clients created by the given function.
+func NewEnvironProvider(config EnvironProviderConfig) environs.CloudEnvironProvider {
+	return &environProvider{
+		newClient:      config.NewClient,
+		credentialsDir: config.CredentialsDir,
+	}
+}
+
+// Version implements environs.EnvironProvider.
+func (p *environProvider) Version() int {
+	return 0
+}
+
+// Open implements environs.EnvironProvider.
+func (p *environProvider) Open(args environs.OpenParams) (environs.Environ, error) {
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	env, err := newEnviron(p, args.Cloud, args.Config)
+	return env, errors.Trace(err)
+}
+
+var cloudSchema = &jsonschema.Schema{
+	Type:     []jsonschema.Type{jsonschema.ObjectType},
+	Required: []string{cloud.EndpointKey, cloud.AuthTypesKey},
+	Order:    []string{cloud.EndpointKey, cloud.AuthTypesKey},
+	Properties: map[string]*jsonschema.Schema{
+		cloud.EndpointKey: {
+			Singular: "the libvirt connection URI, or a comma-separated list of URIs",
+			Type:     []jsonschema.Type{jsonschema.StringType},
+		},
+		cloud.AuthTypesKey: &jsonschema.Schema{
+			Singular:    "auth type",
+			Plural:      "auth types",
+			Type:        []jsonschema.Type{jsonschema.ArrayType},
+			UniqueItems: jsonschema.Bool(true),
+			Items: &jsonschema.ItemSpec{
+				Schemas: []*jsonschema.Schema{{
+					Type: []jsonschema.Type{jsonschema.StringType},
+					Enum: []interface{}{
+						string(cloud.EmptyAuthType),
+						string(sshKeyAuthType),
+						string(cloud.CertificateAuthType),
+					},
+				}},
+			},
+		},
+	},
+}
+
+// CloudSchema returns the schema for adding new clouds of this type.
+func (p *environProvider) CloudSchema() *jsonschema.Schema {
+	return cloudSchema
+}
+
+// Ping tests the connection to the cloud, to verify the endpoint is valid.
+func (p *environProvider) Ping(endpoint string) error {
+	hosts, err := parseHosts(endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, host := range hosts {
+		if err := p.newClient(host.uri).Ping(); err != nil {
+			logger.Errorf("unexpected error connecting to libvirt host: %v", err)
+			return errors.Errorf("no libvirt host available at %s", host.uri)
+		}
+	}
+	return nil
+}
+
+// PrepareConfig implements environs.EnvironProvider.
+func (p *environProvider) PrepareConfig(args environs.PrepareConfigParams) (*config.Config, error) {
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	return configWithDefaults(args.Config)
+}
+
+// ConfigSchema returns extra config attributes specific
+// to this provider only.
+func (p *environProvider) ConfigSchema() schema.Fields {
+	return configFields
+}
+
+// ConfigDefaults returns the default values for the
+// provider specific config attributes.
+func (p *environProvider) ConfigDefaults() schema.Defaults {
+	return configDefaults
+}
+
+func configWithDefaults(cfg *config.Config) (*config.Config, error) {
+	defaults := make(map[string]interface{})
+	if _, ok := cfg.StorageDefaultBlockSource(); !ok {
+		// Set the default block source.
+		defaults[config.StorageDefaultBlockSourceKey] = libvirtStorageProviderType
+	}
+	if len(defaults) == 0 {
+		return cfg, nil
+	}
+	return cfg.Apply(defaults)
+}
+
+// Validate implements environs.EnvironProvider.
+func (*environProvider) Validate(cfg, old *config.Config) (valid *config.Config, err error) {
+	if old == nil {
+		ecfg, err := newValidConfig(cfg)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid config")
+		}
+		return ecfg.Config, nil
+	}
+
+	ecfg, err := newValidConfig(old)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid base config")
+	}
+
+	if err := ecfg.update(cfg); err != nil {
+		return nil, errors.Annotate(err, "invalid config change")
+	}
+
+	return ecfg.Config, nil
+}
+
+func validateCloudSpec(spec environs.CloudSpec) error {
+	if err := spec.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	hosts, err := parseHosts(spec.Endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if spec.Credential == nil {
+		return errors.NotValidf("missing credential")
+	}
+	return errors.Trace(validateCredential(spec.Credential, hosts))
+}
+
+// hostSpec identifies a libvirt host.
+type hostSpec struct {
+	// zone is the name of the availability zone
+	// corresponding to the host.
+	zone string
+
+	// uri is the host's libvirt connection URI.
+	uri string
+
+	// scheme is the scheme of the host's connection URI,
+	// which identifies the transport used to connect to it.
+	scheme string
+}
+
+// parseHosts parses the cloud endpoint, which holds a comma-separated
+// list of libvirt connection URIs, and returns the hosts it identifies.
+// Each host is an availability zone, named after the host name in its
+// URI.
+func parseHosts(endpoint string) ([]hostSpec, error) {
+	var hosts []hostSpec
+	zones := make(map[string]bool)
+	for _, uri := range strings.Split(endpoint, ",") {
+		uri = strings.TrimSpace(uri)
+		if uri == "" {
+			continue
+		}
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, errors.NotValidf("libvirt URI %q", uri)
+		}
+		switch u.Scheme {
+		case "qemu", "qemu+ssh", "qemu+tls":
+		default:
+			return nil, errors.NotValidf(
+				"libvirt URI %q (expected a qemu, qemu+ssh or qemu+tls URI)", uri,
+			)
+		}
+		zone := u.Hostname()
+		if zone == "" {
+			zone = "localhost"
+		}
+		if zones[zone] {
+			return nil, errors.NotValidf("libvirt URIs with duplicate host %q", zone)
+		}
+		zones[zone] = true
+		hosts = append(hosts, hostSpec{zone: zone, uri: uri, scheme: u.Scheme})
+	}
+	if len(hosts) == 0 {
+		return nil, errors.NotValidf("missing libvirt URI")
+	}
+	return hosts, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	"errors"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/provider/libvirt"
+)
+
+type providerSuite struct {
+	ProviderFixture
+}
+
+var _ = gc.Suite(&providerSuite{})
+
+func (s *providerSuite) TestRegistered(c *gc.C) {
+	provider, err := environs.Provider("libvirt")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(provider, gc.NotNil)
+}
+
+func (s *providerSuite) TestOpen(c *gc.C) {
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud:  fakeCloudSpec(),
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Config().Name(), gc.Equals, "testenv")
+}
+
+func (s *providerSuite) TestOpenMissingCredential(c *gc.C) {
+	spec := fakeCloudSpec()
+	spec.Credential = nil
+	s.testOpenError(c, spec, `validating cloud spec: missing credential not valid`)
+}
+
+func (s *providerSuite) TestOpenUnsupportedCredential(c *gc.C) {
+	credential := cloud.NewCredential(cloud.UserPassAuthType, map[string]string{})
+	spec := fakeCloudSpec()
+	spec.Credential = &credential
+	s.testOpenError(c, spec, `validating cloud spec: "userpass" auth-type not supported`)
+}
+
+func (s *providerSuite) TestOpenCredentialWrongTransport(c *gc.C) {
+	credential := cloud.NewCredential(cloud.CertificateAuthType, map[string]string{
+		"ca-cert":     "ca",
+		"client-cert": "cert",
+		"client-key":  "key",
+	})
+	spec := fakeCloudSpec()
+	spec.Credential = &credential
+	s.testOpenError(c, spec, `validating cloud spec: "certificate" auth-type with libvirt URI "`+host1URI+`" \(expected a qemu\+tls URI\) not valid`)
+}
+
+func (s *providerSuite) TestOpenSSHKeyCredential(c *gc.C) {
+	var uris []string
+	provider := libvirt.NewEnvironProvider(libvirt.EnvironProviderConfig{
+		NewClient: func(uri string) libvirt.Client {
+			uris = append(uris, uri)
+			return s.client1
+		},
+		CredentialsDir: func() string { return s.credentialsDir },
+	})
+	credential := cloud.NewCredential("ssh-key", map[string]string{
+		"private-key": "key",
+	})
+	spec := fakeCloudSpec()
+	spec.Credential = &credential
+	_, err := provider.Open(environs.OpenParams{
+		Cloud:  spec,
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uris, gc.HasLen, 2)
+	for i, host := range []string{"host1", "host2"} {
+		u, err := url.Parse(uris[i])
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(u.Hostname(), gc.Equals, host)
+		c.Check(u.Query().Get("no_tty"), gc.Equals, "1")
+		c.Check(u.Query().Get("command"), jc.HasPrefix, s.credentialsDir)
+	}
+}
+
+func (s *providerSuite) TestOpenInvalidEndpoint(c *gc.C) {
+	spec := fakeCloudSpec()
+	spec.Endpoint = "https://host1"
+	s.testOpenError(c, spec, `validating cloud spec: libvirt URI "https://host1" \(expected a qemu, qemu\+ssh or qemu\+tls URI\) not valid`)
+}
+
+func (s *providerSuite) TestOpenDuplicateHost(c *gc.C) {
+	spec := fakeCloudSpec()
+	spec.Endpoint = "qemu+ssh://host1/system, qemu+tls://host1/system"
+	s.testOpenError(c, spec, `validating cloud spec: libvirt URIs with duplicate host "host1" not valid`)
+}
+
+func (s *providerSuite) TestOpenMissingEndpoint(c *gc.C) {
+	spec := fakeCloudSpec()
+	spec.Endpoint = " , "
+	s.testOpenError(c, spec, `validating cloud spec: missing libvirt URI not valid`)
+}
+
+func (s *providerSuite) testOpenError(c *gc.C, spec environs.CloudSpec, expect string) {
+	_, err := s.provider.Open(environs.OpenParams{
+		Cloud:  spec,
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, gc.ErrorMatches, expect)
+}
+
+func (s *providerSuite) TestPing(c *gc.C) {
+	err := s.provider.Ping(host1URI + "," + host2URI)
+	c.Assert(err, jc.ErrorIsNil)
+	s.client1.CheckCallNames(c, "Ping")
+	s.client2.CheckCallNames(c, "Ping")
+}
+
+func (s *providerSuite) TestPingUnreachableHost(c *gc.C) {
+	s.client2.SetErrors(errors.New("boom"))
+	err := s.provider.Ping(host1URI + "," + host2URI)
+	c.Assert(err, gc.ErrorMatches, "no libvirt host available at "+host2URI)
+}
+
+func (s *providerSuite) TestPrepareConfig(c *gc.C) {
+	cfg, err := s.provider.PrepareConfig(environs.PrepareConfigParams{
+		Cloud:  fakeCloudSpec(),
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	source, ok := cfg.StorageDefaultBlockSource()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(source, gc.Equals, "libvirt")
+	c.Assert(config.Validate(cfg, nil), jc.ErrorIsNil)
+}
@@ -0,0 +1,397 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	libvirtStorageProviderType = "libvirt"
+
+	// attrLibvirtStoragePool is the attribute name for the
+	// storage pool's corresponding libvirt storage pool name.
+	// If this is not provided, the model's storage-pool is used.
+	attrLibvirtStoragePool = "libvirt-pool"
+
+	// volumeSerialMaxLength is the maximum length of the serial
+	// of a virtio disk.
+	volumeSerialMaxLength = 20
+
+	// bytesPerMiB is the number of bytes in a mebibyte; Juju
+	// volume sizes are in MiB, libvirt's are in bytes.
+	bytesPerMiB = 1024 * 1024
+)
+
+// StorageProviderTypes implements storage.ProviderRegistry.
+func (env *environ) StorageProviderTypes() ([]storage.ProviderType, error) {
+	return []storage.ProviderType{libvirtStorageProviderType}, nil
+}
+
+// StorageProvider implements storage.ProviderRegistry.
+func (env *environ) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	if t == libvirtStorageProviderType {
+		return &libvirtStorageProvider{env}, nil
+	}
+	return nil, errors.NotFoundf("storage provider %q", t)
+}
+
+// libvirtStorageProvider is a storage provider for volumes in the
+// libvirt hosts' storage pools.
+type libvirtStorageProvider struct {
+	env *environ
+}
+
+var _ storage.Provider = (*libvirtStorageProvider)(nil)
+
+var libvirtStorageConfigChecker = schema.FieldMap(
+	schema.Fields{
+		attrLibvirtStoragePool: schema.String(),
+	},
+	schema.Defaults{
+		attrLibvirtStoragePool: schema.Omit,
+	},
+)
+
+// ValidateConfig is part of the Provider interface.
+func (p *libvirtStorageProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := libvirtStorageConfigChecker.Coerce(cfg.Attrs(), nil)
+	return errors.Annotate(err, "validating libvirt storage config")
+}
+
+// Supports is part of the Provider interface.
+func (p *libvirtStorageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is part of the Provider interface.
+func (p *libvirtStorageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is part of the Provider interface.
+func (p *libvirtStorageProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is part of the Provider interface.
+func (p *libvirtStorageProvider) Releasable() bool {
+	// Volumes have no metadata to record their model,
+	// so they cannot be imported into another model.
+	return false
+}
+
+// DefaultPools is part of the Provider interface.
+func (p *libvirtStorageProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// VolumeSource is part of the Provider interface.
+func (p *libvirtStorageProvider) VolumeSource(cfg *storage.Config) (storage.VolumeSource, error) {
+	attrs, err := libvirtStorageConfigChecker.Coerce(cfg.Attrs(), nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating libvirt storage config")
+	}
+	pool, _ := attrs.(map[string]interface{})[attrLibvirtStoragePool].(string)
+	if pool == "" {
+		pool = p.env.envConfig().storagePool()
+	}
+	return &libvirtVolumeSource{env: p.env, pool: pool}, nil
+}
+
+// FilesystemSource is part of the Provider interface.
+func (p *libvirtStorageProvider) FilesystemSource(cfg *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// libvirtVolumeSource creates volumes in the storage pool with the
+// same name on each of the libvirt hosts. Volumes can only be attached
+// to instances on the same host, so volume IDs have the form
+// <zone>/<volume-name>.
+type libvirtVolumeSource struct {
+	env  *environ
+	pool string
+}
+
+var _ storage.VolumeSource = (*libvirtVolumeSource)(nil)
+
+// volumeName returns the name of the libvirt volume
+// for the volume with the given tag ID.
+func (s *libvirtVolumeSource) volumeName(id string) string {
+	return s.env.namespace.Value("volume-" + strings.Replace(id, "/", "-", -1))
+}
+
+func makeVolumeId(zone, name string) string {
+	return zone + "/" + name
+}
+
+// parseVolumeId returns the availability zone and libvirt
+// volume name from the given volume ID.
+func parseVolumeId(volumeId string) (zone, name string, _ error) {
+	parts := strings.SplitN(volumeId, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("volume ID %q", volumeId)
+	}
+	return parts[0], parts[1], nil
+}
+
+// volume returns the host and libvirt volume
+// with the given volume ID.
+func (s *libvirtVolumeSource) volume(volumeId string) (*libvirtHost, Volume, error) {
+	zone, name, err := parseVolumeId(volumeId)
+	if err != nil {
+		return nil, Volume{}, errors.Trace(err)
+	}
+	host, err := s.env.host(zone)
+	if err != nil {
+		return nil, Volume{}, errors.Trace(err)
+	}
+	volume, err := host.client.Volume(s.pool, name)
+	if err != nil {
+		return nil, Volume{}, errors.Trace(err)
+	}
+	return host, volume, nil
+}
+
+func volumeInfo(volumeId string, volume Volume) storage.VolumeInfo {
+	return storage.VolumeInfo{
+		VolumeId:   volumeId,
+		Size:       volume.Size / bytesPerMiB,
+		Persistent: true,
+	}
+}
+
+// CreateVolumes is part of the VolumeSource interface.
+func (s *libvirtVolumeSource) CreateVolumes(params []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(params))
+	for i, p := range params {
+		volume, err := s.createVolume(p)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "creating volume %s", p.Tag.Id())
+			continue
+		}
+		results[i].Volume = volume
+	}
+	return results, nil
+}
+
+func (s *libvirtVolumeSource) createVolume(p storage.VolumeParams) (*storage.Volume, error) {
+	host, err := s.volumeHost(p)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	name := s.volumeName(p.Tag.Id())
+	volume, err := createVolume(host.client, s.pool, CreateVolumeParams{
+		Name: name,
+		Size: p.Size * bytesPerMiB,
+	}, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.Volume{
+		Tag:        p.Tag,
+		VolumeInfo: volumeInfo(makeVolumeId(host.zone, name), volume),
+	}, nil
+}
+
+// volumeHost returns the host on which to create the volume, which is
+// that of the instance the volume is to be attached to, if any.
+func (s *libvirtVolumeSource) volumeHost(p storage.VolumeParams) (*libvirtHost, error) {
+	if p.Attachment != nil && p.Attachment.InstanceId != "" {
+		inst, err := s.env.instance(p.Attachment.InstanceId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return inst.host, nil
+	}
+	if len(s.env.hosts) == 1 {
+		return s.env.hosts[0], nil
+	}
+	return nil, errors.New("cannot choose a libvirt host for a volume without an attachment")
+}
+
+// ListVolumes is part of the VolumeSource interface.
+func (s *libvirtVolumeSource) ListVolumes() ([]string, error) {
+	prefix := s.env.namespace.Value("volume-")
+	var volumeIds []string
+	for _, host := range s.env.hosts {
+		volumes, err := host.client.Volumes(s.pool)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, volume := range volumes {
+			if strings.HasPrefix(volume.Name, prefix) {
+				volumeIds = append(volumeIds, makeVolumeId(host.zone, volume.Name))
+			}
+		}
+	}
+	return volumeIds, nil
+}
+
+// DescribeVolumes is part of the VolumeSource interface.
+func (s *libvirtVolumeSource) DescribeVolumes(volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		_, volume, err := s.volume(volumeId)
+		if err != nil {
+			results[i].Error = errors.Trace(err)
+			continue
+		}
+		info := volumeInfo(volumeId, volume)
+		results[i].VolumeInfo = &info
+	}
+	return results, nil
+}
+
+// DestroyVolumes is part of the VolumeSource interface.
+func (s *libvirtVolumeSource) DestroyVolumes(volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		host, volume, err := s.volume(volumeId)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			results[i] = errors.Trace(err)
+			continue
+		}
+		results[i] = host.client.DeleteVolume(volume.Path)
+	}
+	return results, nil
+}
+
+// ReleaseVolumes is part of the VolumeSource interface.
+func (s *libvirtVolumeSource) ReleaseVolumes(volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i := range volumeIds {
+		results[i] = errors.NotSupportedf("releasing libvirt volumes")
+	}
+	return results, nil
+}
+
+// ValidateVolumeParams is part of the VolumeSource interface.
+func (s *libvirtVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	return nil
+}
+
+// AttachVolumes is part of the VolumeSource interface.
+func (s *libvirtVolumeSource) AttachVolumes(params []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(params))
+	for i, p := range params {
+		info, err := s.attachVolume(p)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %s", p.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = &storage.VolumeAttachment{
+			Volume:               p.Volume,
+			Machine:              p.Machine,
+			VolumeAttachmentInfo: *info,
+		}
+	}
+	return results, nil
+}
+
+func (s *libvirtVolumeSource) attachVolume(p storage.VolumeAttachmentParams) (*storage.VolumeAttachmentInfo, error) {
+	host, volume, err := s.volume(p.VolumeId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	inst, err := s.env.instance(p.InstanceId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if inst.host != host {
+		return nil, errors.Errorf(
+			"volume is in zone %q, instance %q is in zone %q",
+			host.zone, p.InstanceId, inst.host.zone,
+		)
+	}
+	domain, err := host.client.Domain(string(p.InstanceId))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	serial := volumeSerial(p.Volume.Id())
+	info := &storage.VolumeAttachmentInfo{
+		// The guest's udev rules link virtio disks by serial.
+		DeviceLink: "/dev/disk/by-id/virtio-" + serial,
+		ReadOnly:   p.ReadOnly,
+	}
+	targets := make(map[string]bool)
+	for _, disk := range domain.Disk {
+		if disk.Source.File == volume.Path || disk.Source.Dev == volume.Path {
+			// Already attached.
+			return info, nil
+		}
+		targets[disk.Target.Dev] = true
+	}
+	target, err := freeTarget(targets)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := host.client.AttachDisk(domain.Name, volume.Path, target, serial); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// volumeSerial returns the disk serial for the volume
+// with the given tag ID.
+func volumeSerial(id string) string {
+	serial := "juju-" + strings.Replace(id, "/", "-", -1)
+	if len(serial) > volumeSerialMaxLength {
+		serial = serial[:volumeSerialMaxLength]
+	}
+	return serial
+}
+
+// freeTarget returns the first virtio disk target device
+// that is not in use.
+func freeTarget(inUse map[string]bool) (string, error) {
+	for c := 'a'; c <= 'z'; c++ {
+		target := fmt.Sprintf("vd%c", c)
+		if !inUse[target] {
+			return target, nil
+		}
+	}
+	return "", errors.New("no free disk targets")
+}
+
+// DetachVolumes is part of the VolumeSource interface.
+func (s *libvirtVolumeSource) DetachVolumes(params []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(params))
+	for i, p := range params {
+		if err := s.detachVolume(p.InstanceId, p.VolumeId); err != nil {
+			results[i] = errors.Annotatef(err, "detaching volume %s", p.Volume.Id())
+		}
+	}
+	return results, nil
+}
+
+func (s *libvirtVolumeSource) detachVolume(instanceId instance.Id, volumeId string) error {
+	host, volume, err := s.volume(volumeId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	domain, err := host.client.Domain(string(instanceId))
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, disk := range domain.Disk {
+		if disk.Source.File == volume.Path || disk.Source.Dev == volume.Path {
+			return host.client.DetachDisk(domain.Name, disk.Target.Dev)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/provider/libvirt"
+	"github.com/juju/juju/storage"
+)
+
+type storageSuite struct {
+	EnvironFixture
+	source storage.VolumeSource
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) SetUpTest(c *gc.C) {
+	s.EnvironFixture.SetUpTest(c)
+	s.client2.domains = []libvirt.DomainState{
+		{Name: "juju-f75cba-1", State: "running"},
+	}
+
+	provider, err := s.env.StorageProvider("libvirt")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := storage.NewConfig("libvirt", "libvirt", map[string]interface{}{
+		"libvirt-pool": "juju",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.source, err = provider.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestValidateConfig(c *gc.C) {
+	provider, err := s.env.StorageProvider("libvirt")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := storage.NewConfig("libvirt", "libvirt", map[string]interface{}{
+		"libvirt-pool": 123,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = provider.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `validating libvirt storage config: libvirt-pool: expected string, got int\(123\)`)
+}
+
+func (s *storageSuite) TestSupports(c *gc.C) {
+	provider, err := s.env.StorageProvider("libvirt")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(provider.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(provider.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+}
+
+func (s *storageSuite) TestCreateVolumes(c *gc.C) {
+	results, err := s.source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				InstanceId: "juju-f75cba-1",
+			},
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		Tag: names.NewVolumeTag("0"),
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId:   "host2/juju-f75cba-volume-0",
+			Size:       1024,
+			Persistent: true,
+		},
+	})
+	s.client2.CheckCall(c, 1, "CreateVolume", "juju", libvirt.CreateVolumeParams{
+		Name: "juju-f75cba-volume-0",
+		Size: 1024 * 1024 * 1024,
+	})
+}
+
+func (s *storageSuite) TestCreateVolumesNoAttachment(c *gc.C) {
+	results, err := s.source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		"creating volume 0: cannot choose a libvirt host for a volume without an attachment",
+	)
+}
+
+func (s *storageSuite) TestListVolumes(c *gc.C) {
+	s.client1.volumes = []libvirt.Volume{
+		{Name: "juju-f75cba-volume-0"},
+		{Name: "juju-image-bionic-amd64.img"},
+	}
+	s.client2.volumes = []libvirt.Volume{
+		{Name: "juju-f75cba-volume-1"},
+		{Name: "juju-abcdef-volume-0"},
+	}
+	volumeIds, err := s.source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{
+		"host1/juju-f75cba-volume-0",
+		"host2/juju-f75cba-volume-1",
+	})
+}
+
+func (s *storageSuite) TestAttachVolumes(c *gc.C) {
+	s.client2.volumes = []libvirt.Volume{{
+		Name: "juju-f75cba-volume-0",
+		Path: "/var/lib/libvirt/images/juju-f75cba-volume-0",
+	}}
+	s.client2.domainDef = map[string]*kvmlibvirt.Domain{
+		"juju-f75cba-1": {
+			Name: "juju-f75cba-1",
+			Disk: []kvmlibvirt.Disk{
+				{Target: kvmlibvirt.DiskTarget{Dev: "vda"}},
+				{Target: kvmlibvirt.DiskTarget{Dev: "vdb"}},
+			},
+		},
+	}
+	results, err := s.source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("1"),
+			InstanceId: "juju-f75cba-1",
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "host2/juju-f75cba-volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment.DeviceLink, gc.Equals, "/dev/disk/by-id/virtio-juju-0")
+	s.client2.CheckCall(c, 3, "AttachDisk",
+		"juju-f75cba-1", "/var/lib/libvirt/images/juju-f75cba-volume-0", "vdc", "juju-0",
+	)
+}
+
+func (s *storageSuite) TestAttachVolumesWrongZone(c *gc.C) {
+	s.client1.volumes = []libvirt.Volume{{Name: "juju-f75cba-volume-0"}}
+	results, err := s.source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("1"),
+			InstanceId: "juju-f75cba-1",
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "host1/juju-f75cba-volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		`attaching volume 0: volume is in zone "host1", instance "juju-f75cba-1" is in zone "host2"`,
+	)
+}
+
+func (s *storageSuite) TestDetachVolumes(c *gc.C) {
+	s.client2.volumes = []libvirt.Volume{{
+		Name: "juju-f75cba-volume-0",
+		Path: "/var/lib/libvirt/images/juju-f75cba-volume-0",
+	}}
+	s.client2.domainDef = map[string]*kvmlibvirt.Domain{
+		"juju-f75cba-1": {
+			Name: "juju-f75cba-1",
+			Disk: []kvmlibvirt.Disk{{
+				Source: kvmlibvirt.DiskSource{File: "/var/lib/libvirt/images/juju-f75cba-volume-0"},
+				Target: kvmlibvirt.DiskTarget{Dev: "vdc"},
+			}},
+		},
+	}
+	results, err := s.source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			InstanceId: "juju-f75cba-1",
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "host2/juju-f75cba-volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil})
+	s.client2.CheckCall(c, 2, "DetachDisk", "juju-f75cba-1", "vdc")
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jujuos "github.com/juju/utils/os"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
+)
+
+// LibvirtRenderer renders user data for instances, which read it
+// from a NoCloud data source.
+type LibvirtRenderer struct{}
+
+// Render implements renderers.ProviderRenderer.
+func (LibvirtRenderer) Render(cfg cloudinit.CloudConfig, os jujuos.OSType) ([]byte, error) {
+	switch os {
+	case jujuos.Ubuntu, jujuos.CentOS:
+		return renderers.RenderYAML(cfg)
+	default:
+		return nil, errors.Errorf("Cannot encode userdata for OS: %s", os.String())
+	}
+}
+
+const (
+	userDataFile = "user-data"
+	metaDataFile = "meta-data"
+)
+
+// runCommand is used to run genisoimage; it is replaced in tests.
+var runCommand = run
+
+// newDataSourceImage writes a cloud-init NoCloud data source holding
+// the given user data to an ISO image in dir, and returns its path.
+// See: http://cloudinit.readthedocs.io/en/latest/topics/datasources/nocloud.html
+func newDataSourceImage(dir, hostname string, userData []byte) (string, error) {
+	metaData := fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", hostname, hostname)
+	if err := ioutil.WriteFile(filepath.Join(dir, userDataFile), userData, 0600); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, metaDataFile), []byte(metaData), 0600); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	// NoCloud requires the files to be at the root of a volume
+	// labelled "cidata", named exactly "user-data" and "meta-data".
+	// The graft points place them there whatever dir is.
+	path := filepath.Join(dir, "cidata.iso")
+	if _, err := runCommand(
+		"genisoimage",
+		"-output", path,
+		"-volid", "cidata",
+		"-joliet", "-rock",
+		"-graft-points",
+		userDataFile+"="+filepath.Join(dir, userDataFile),
+		metaDataFile+"="+filepath.Join(dir, metaDataFile),
+	); err != nil {
+		return "", errors.Annotate(err, "creating data source image")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.Trace(err)
+	}
+	return path, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/tools/lxdclient"
+)
+
+// lxdAvailabilityZone is a member of an LXD cluster, which the
+// provider exposes as an availability zone.
+type lxdAvailabilityZone struct {
+	member lxdclient.ClusterMember
+}
+
+// Name implements common.AvailabilityZone.
+func (z *lxdAvailabilityZone) Name() string {
+	return z.member.Name
+}
+
+// Available implements common.AvailabilityZone.
+func (z *lxdAvailabilityZone) Available() bool {
+	return z.member.Status == lxdclient.ClusterMemberOnline
+}
+
+// AvailabilityZones returns all availability zones in the environment.
+// Each member of a clustered LXD is an availability zone; a standalone
+// LXD host has none.
+func (env *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	clustered, err := env.raw.IsClustered()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !clustered {
+		return nil, nil
+	}
+	members, err := env.raw.ClusterMembers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zones := make([]common.AvailabilityZone, len(members))
+	for i, member := range members {
+		zones[i] = &lxdAvailabilityZone{member}
+	}
+	return zones, nil
+}
+
+// InstanceAvailabilityZoneNames returns the names of the availability
+// zones for the specified instances. The error returned follows the same
+// rules as Environ.Instances.
+func (env *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	instances, err := env.Instances(ids)
+	if err != nil && err != environs.ErrPartialInstances && err != environs.ErrNoInstances {
+		return nil, errors.Trace(err)
+	}
+	// We let the two environs errors pass on through. However, we do
+	// not use errors.Trace in that case since callers may not call
+	// errors.Cause.
+
+	results := make([]string, len(ids))
+	clustered, clusterErr := env.raw.IsClustered()
+	if clusterErr != nil {
+		return nil, errors.Trace(clusterErr)
+	}
+	if !clustered {
+		return results, err
+	}
+	locations, clusterErr := env.raw.ContainerLocations()
+	if clusterErr != nil {
+		return nil, errors.Trace(clusterErr)
+	}
+	for i, inst := range instances {
+		if inst != nil {
+			results[i] = locations[string(inst.Id())]
+		}
+	}
+	return results, err
+}
+
+// DeriveAvailabilityZones is part of the common.ZonedEnviron interface.
+func (env *environ) DeriveAvailabilityZones(args environs.StartInstanceParams) ([]string, error) {
+	placement, err := env.parsePlacement(args.Placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if placement.zone == "" {
+		return nil, nil
+	}
+	return []string{placement.zone}, nil
+}
+
+// DistributeInstances implements the state.InstanceDistributor policy.
+func (env *environ) DistributeInstances(candidates, distributionGroup []instance.Id) ([]instance.Id, error) {
+	return common.DistributeInstances(env, candidates, distributionGroup)
+}
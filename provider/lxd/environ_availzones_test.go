@@ -0,0 +1,97 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxd_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/lxd"
+	"github.com/juju/juju/tools/lxdclient"
+)
+
+type environAZSuite struct {
+	lxd.BaseSuite
+}
+
+var _ = gc.Suite(&environAZSuite{})
+
+func (s *environAZSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.Client.Clustered = true
+	s.Client.Members = []lxdclient.ClusterMember{{
+		Name:   "node1",
+		Status: lxdclient.ClusterMemberOnline,
+	}, {
+		Name:   "node2",
+		Status: "Offline",
+	}}
+	s.Client.Insts = []lxdclient.Instance{*s.RawInstance}
+	s.Client.Locations = map[string]string{"spam": "node1"}
+}
+
+func (s *environAZSuite) TestAvailabilityZones(c *gc.C) {
+	zones, err := s.Env.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 2)
+	c.Check(zones[0].Name(), gc.Equals, "node1")
+	c.Check(zones[0].Available(), jc.IsTrue)
+	c.Check(zones[1].Name(), gc.Equals, "node2")
+	c.Check(zones[1].Available(), jc.IsFalse)
+
+	s.Stub.CheckCallNames(c, "IsClustered", "ClusterMembers")
+}
+
+func (s *environAZSuite) TestAvailabilityZonesNotClustered(c *gc.C) {
+	s.Client.Clustered = false
+	zones, err := s.Env.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+
+	s.Stub.CheckCallNames(c, "IsClustered")
+}
+
+func (s *environAZSuite) TestInstanceAvailabilityZoneNames(c *gc.C) {
+	ids := []instance.Id{"spam", "eggs"}
+	zones, err := s.Env.InstanceAvailabilityZoneNames(ids)
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(zones, jc.DeepEquals, []string{"node1", ""})
+
+	s.Stub.CheckCallNames(c, "Instances", "IsClustered", "ContainerLocations")
+}
+
+func (s *environAZSuite) TestInstanceAvailabilityZoneNamesNotClustered(c *gc.C) {
+	s.Client.Clustered = false
+	zones, err := s.Env.InstanceAvailabilityZoneNames([]instance.Id{"spam"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{""})
+
+	s.Stub.CheckCallNames(c, "Instances", "IsClustered")
+}
+
+func (s *environAZSuite) TestDeriveAvailabilityZones(c *gc.C) {
+	zones, err := s.Env.DeriveAvailabilityZones(environs.StartInstanceParams{
+		Placement: "zone=node1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"node1"})
+}
+
+func (s *environAZSuite) TestDeriveAvailabilityZonesNoPlacement(c *gc.C) {
+	zones, err := s.Env.DeriveAvailabilityZones(environs.StartInstanceParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+}
+
+func (s *environAZSuite) TestDeriveAvailabilityZonesUnavailable(c *gc.C) {
+	_, err := s.Env.DeriveAvailabilityZones(environs.StartInstanceParams{
+		Placement: "zone=node2",
+	})
+	c.Assert(err, gc.ErrorMatches, `availability zone "node2" is unavailable`)
+}
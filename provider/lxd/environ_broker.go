@@ -41,7 +41,12 @@ func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.
 
 	// TODO(ericsnow) Handle constraints?
 
-	raw, err := env.newRawInstance(args, arch)
+	zone, err := env.instanceZone(args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	raw, err := env.newRawInstance(args, arch, zone)
 	if err != nil {
 		if args.StatusCallback != nil {
 			args.StatusCallback(status.ProvisioningError, err.Error(), nil)
@@ -53,6 +58,9 @@ func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.
 
 	// Build the result.
 	hwc := env.getHardwareCharacteristics(args, inst)
+	if zone != "" {
+		hwc.AvailabilityZone = &zone
+	}
 	result := environs.StartInstanceResult{
 		Instance: inst,
 		Hardware: hwc,
@@ -60,6 +68,21 @@ func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.
 	return &result, nil
 }
 
+// instanceZone returns the availability zone, i.e. the cluster member,
+// that the instance should be started in. The zone chosen by the
+// provisioner takes precedence over one given by placement. An empty
+// result leaves the choice of member to LXD.
+func (env *environ) instanceZone(args environs.StartInstanceParams) (string, error) {
+	if args.AvailabilityZone != "" {
+		return args.AvailabilityZone, nil
+	}
+	placement, err := env.parsePlacement(args.Placement)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return placement.zone, nil
+}
+
 func (env *environ) finishInstanceConfig(args environs.StartInstanceParams) (string, error) {
 	// TODO(natefinch): This is only correct so long as the lxd is running on
 	// the local machine.  If/when we support a remote lxd environment, we'll
@@ -123,6 +146,7 @@ func (env *environ) getImageSources() ([]lxdclient.Remote, error) {
 func (env *environ) newRawInstance(
 	args environs.StartInstanceParams,
 	arch string,
+	zone string,
 ) (*lxdclient.Instance, error) {
 	hostname, err := env.namespace.Hostname(args.InstanceConfig.MachineId)
 	if err != nil {
@@ -201,9 +225,14 @@ func (env *environ) newRawInstance(
 			env.profileName(),
 		},
 		// Network is omitted (left empty).
+		Target: zone,
 	}
 
-	logger.Infof("starting instance %q (image %q)...", instSpec.Name, instSpec.Image)
+	if zone != "" {
+		logger.Infof("starting instance %q (image %q) on cluster member %q...", instSpec.Name, instSpec.Image, zone)
+	} else {
+		logger.Infof("starting instance %q (image %q)...", instSpec.Name, instSpec.Image)
+	}
 
 	statusCallback(status.Allocating, "preparing image")
 	inst, err := env.raw.AddInstance(instSpec)
@@ -12,6 +12,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/provider/lxd"
+	"github.com/juju/juju/tools/lxdclient"
 )
 
 type environBrokerSuite struct {
@@ -40,6 +41,43 @@ func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
 	s.Stub.CheckCall(c, 0, "EnsureImageExists", "trusty", "arm64")
 }
 
+func (s *environBrokerSuite) TestStartInstanceAvailabilityZone(c *gc.C) {
+	s.Client.Inst = s.RawInstance
+	s.PatchValue(&arch.HostArch, func() string { return arch.ARM64 })
+
+	args := s.StartInstArgs
+	args.AvailabilityZone = "node2"
+	result, err := s.Env.StartInstance(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Hardware.AvailabilityZone, gc.NotNil)
+	c.Check(*result.Hardware.AvailabilityZone, gc.Equals, "node2")
+
+	s.Stub.CheckCallNames(c, "EnsureImageExists", "AddInstance")
+	spec := s.Stub.Calls()[1].Args[0].(lxdclient.InstanceSpec)
+	c.Check(spec.Target, gc.Equals, "node2")
+}
+
+func (s *environBrokerSuite) TestStartInstancePlacementZone(c *gc.C) {
+	s.Client.Inst = s.RawInstance
+	s.Client.Clustered = true
+	s.Client.Members = []lxdclient.ClusterMember{{
+		Name:   "node1",
+		Status: lxdclient.ClusterMemberOnline,
+	}}
+	s.PatchValue(&arch.HostArch, func() string { return arch.ARM64 })
+
+	args := s.StartInstArgs
+	args.Placement = "zone=node1"
+	result, err := s.Env.StartInstance(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Hardware.AvailabilityZone, gc.NotNil)
+	c.Check(*result.Hardware.AvailabilityZone, gc.Equals, "node1")
+
+	s.Stub.CheckCallNames(c, "IsClustered", "ClusterMembers", "EnsureImageExists", "AddInstance")
+	spec := s.Stub.Calls()[3].Args[0].(lxdclient.InstanceSpec)
+	c.Check(spec.Target, gc.Equals, "node1")
+}
+
 func (s *environBrokerSuite) TestStartInstanceNoTools(c *gc.C) {
 	s.Client.Inst = s.RawInstance
 
@@ -6,12 +6,15 @@
 package lxd
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/version"
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/tools/lxdclient"
 )
 
@@ -115,13 +118,29 @@ func (env *environ) ControllerInstances(controllerUUID string) ([]instance.Id, e
 	return results, nil
 }
 
-type instPlacement struct{}
+type instPlacement struct {
+	// zone is the name of the cluster member to place the
+	// instance on, if any.
+	zone string
+}
 
 func (env *environ) parsePlacement(placement string) (*instPlacement, error) {
 	if placement == "" {
 		return &instPlacement{}, nil
 	}
 
+	pos := strings.IndexRune(placement, '=')
+	if pos == -1 {
+		return nil, errors.Errorf("unknown placement directive: %v", placement)
+	}
+
+	switch key, value := placement[:pos], placement[pos+1:]; key {
+	case "zone":
+		if err := common.ValidateAvailabilityZone(env, value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &instPlacement{zone: value}, nil
+	}
 	return nil, errors.Errorf("unknown placement directive: %v", placement)
 }
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/common"
 )
 
 // PrecheckInstance verifies that the provided series and constraints
@@ -19,6 +20,9 @@ func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error
 	if _, err := env.parsePlacement(args.Placement); err != nil {
 		return errors.Trace(err)
 	}
+	if err := common.ValidateZonesConstraint(env, args); err != nil {
+		return errors.Trace(err)
+	}
 
 	if args.Constraints.HasInstanceType() {
 		return errors.Errorf("LXD does not support instance types (got %q)", *args.Constraints.InstanceType)
@@ -34,6 +38,8 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Interruptible,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
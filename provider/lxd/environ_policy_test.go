@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/provider/lxd"
+	"github.com/juju/juju/tools/lxdclient"
 )
 
 type environPolSuite struct {
@@ -55,9 +56,40 @@ func (s *environPolSuite) TestPrecheckInstanceUnsupportedArch(c *gc.C) {
 }
 
 func (s *environPolSuite) TestPrecheckInstanceAvailZone(c *gc.C) {
+	s.Client.Clustered = true
+	s.Client.Members = []lxdclient.ClusterMember{{
+		Name:   "a-zone",
+		Status: lxdclient.ClusterMemberOnline,
+	}}
 	placement := "zone=a-zone"
 	err := s.Env.PrecheckInstance(environs.PrecheckInstanceParams{Series: series.LatestLts(), Placement: placement})
 
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *environPolSuite) TestPrecheckInstanceAvailZoneUnavailable(c *gc.C) {
+	s.Client.Clustered = true
+	s.Client.Members = []lxdclient.ClusterMember{{
+		Name:   "a-zone",
+		Status: "Offline",
+	}}
+	placement := "zone=a-zone"
+	err := s.Env.PrecheckInstance(environs.PrecheckInstanceParams{Series: series.LatestLts(), Placement: placement})
+
+	c.Check(err, gc.ErrorMatches, `availability zone "a-zone" is unavailable`)
+}
+
+func (s *environPolSuite) TestPrecheckInstanceAvailZoneNotClustered(c *gc.C) {
+	placement := "zone=a-zone"
+	err := s.Env.PrecheckInstance(environs.PrecheckInstanceParams{Series: series.LatestLts(), Placement: placement})
+
+	c.Check(err, gc.ErrorMatches, `availability zone "a-zone" not valid`)
+}
+
+func (s *environPolSuite) TestPrecheckInstanceUnknownPlacement(c *gc.C) {
+	placement := "node=a-zone"
+	err := s.Env.PrecheckInstance(environs.PrecheckInstanceParams{Series: series.LatestLts(), Placement: placement})
+
 	c.Check(err, gc.ErrorMatches, `unknown placement directive: .*`)
 }
 
@@ -98,6 +130,8 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 		"cores=2",
 		"cpu-power=250",
 		"virt-type=kvm",
+		"interruptible=true",
+		"max-price=0.05",
 	}, " "))
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
@@ -108,6 +142,8 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 		"cores",
 		"cpu-power",
 		"virt-type",
+		"interruptible",
+		"max-price",
 	}
 	c.Check(unsupported, jc.SameContents, expected)
 }
@@ -21,6 +21,7 @@ type rawProvider struct {
 	lxdProfiles
 	lxdImages
 	lxdStorage
+	lxdCluster
 
 	remote lxdclient.Remote
 }
@@ -71,6 +72,12 @@ type lxdStorage interface {
 	VolumeList(pool string) ([]lxdapi.StorageVolume, error)
 }
 
+type lxdCluster interface {
+	IsClustered() (bool, error)
+	ClusterMembers() ([]lxdclient.ClusterMember, error)
+	ContainerLocations() (map[string]string, error)
+}
+
 func newRawProvider(spec environs.CloudSpec, local bool) (*rawProvider, error) {
 	if local {
 		return newLocalRawProvider()
@@ -103,6 +110,7 @@ func newRawProviderFromConfig(config lxdclient.Config) (*rawProvider, error) {
 		lxdProfiles:  client,
 		lxdImages:    client,
 		lxdStorage:   client,
+		lxdCluster:   client,
 		remote:       config.Remote,
 	}, nil
 }
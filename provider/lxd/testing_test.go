@@ -27,6 +27,7 @@ import (
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/tools/lxdclient"
@@ -35,6 +36,7 @@ import (
 // Ensure LXD provider supports the expected interfaces.
 var (
 	_ config.ConfigSchemaSource = (*environProvider)(nil)
+	_ common.ZonedEnviron       = (*environ)(nil)
 )
 
 // These values are stub LXD client credentials for use in tests.
@@ -348,6 +350,7 @@ func (s *BaseSuite) SetUpTest(c *gc.C) {
 		lxdProfiles:  s.Client,
 		lxdImages:    s.Client,
 		lxdStorage:   s.Client,
+		lxdCluster:   s.Client,
 		remote: lxdclient.Remote{
 			Cert: &lxdclient.Cert{
 				Name:    "juju",
@@ -473,6 +476,9 @@ type StubClient struct {
 	Server             *api.Server
 	StorageIsSupported bool
 	Volumes            map[string][]api.StorageVolume
+	Clustered          bool
+	Members            []lxdclient.ClusterMember
+	Locations          map[string]string
 }
 
 func (conn *StubClient) Instances(prefix string, statuses ...string) ([]lxdclient.Instance, error) {
@@ -658,3 +664,24 @@ func (conn *StubClient) VolumeUpdate(pool, volume string, update api.StorageVolu
 	conn.AddCall("VolumeUpdate", pool, volume, update)
 	return conn.NextErr()
 }
+
+func (conn *StubClient) IsClustered() (bool, error) {
+	conn.AddCall("IsClustered")
+	return conn.Clustered, conn.NextErr()
+}
+
+func (conn *StubClient) ClusterMembers() ([]lxdclient.ClusterMember, error) {
+	conn.AddCall("ClusterMembers")
+	if err := conn.NextErr(); err != nil {
+		return nil, err
+	}
+	return conn.Members, nil
+}
+
+func (conn *StubClient) ContainerLocations() (map[string]string, error) {
+	conn.AddCall("ContainerLocations")
+	if err := conn.NextErr(); err != nil {
+		return nil, err
+	}
+	return conn.Locations, nil
+}
@@ -20,6 +20,8 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.VirtType,
+	constraints.Interruptible,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -622,11 +622,12 @@ func (e *maasEnviron) parsePlacement(placement string) (*maasPlacement, error) {
 }
 
 func (env *maasEnviron) PrecheckInstance(args environs.PrecheckInstanceParams) error {
-	if args.Placement == "" {
-		return nil
+	if args.Placement != "" {
+		if _, err := env.parsePlacement(args.Placement); err != nil {
+			return err
+		}
 	}
-	_, err := env.parsePlacement(args.Placement)
-	return err
+	return common.ValidateZonesConstraint(env, args)
 }
 
 const (
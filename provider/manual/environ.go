@@ -323,6 +323,9 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Zones,
+	constraints.Interruptible,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
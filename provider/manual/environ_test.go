@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/manual/sshprovisioner"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -155,6 +156,20 @@ func (s *environSuite) TestSupportsNetworking(c *gc.C) {
 	c.Assert(ok, jc.IsFalse)
 }
 
+func (s *environSuite) TestStorageProviders(c *gc.C) {
+	types, err := s.env.StorageProviderTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(types, jc.DeepEquals, []storage.ProviderType{"device"})
+
+	p, err := s.env.StorageProvider("device")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+
+	_, err = s.env.StorageProvider("loop")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *environSuite) TestConstraintsValidator(c *gc.C) {
 	s.PatchValue(&sshprovisioner.DetectSeriesAndHardwareCharacteristics,
 		func(string) (instance.HardwareCharacteristics, string, error) {
@@ -167,10 +182,10 @@ func (s *environSuite) TestConstraintsValidator(c *gc.C) {
 
 	validator, err := s.env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 instance-type=foo tags=bar cpu-power=10 cores=2 mem=1G virt-type=kvm")
+	cons := constraints.MustParse("arch=amd64 instance-type=foo tags=bar cpu-power=10 cores=2 mem=1G virt-type=kvm zones=az1 interruptible=true max-price=0.05")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "instance-type", "tags", "virt-type"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "instance-type", "tags", "virt-type", "zones", "interruptible", "max-price"})
 }
 
 func (s *environSuite) TestConstraintsValidatorInsideController(c *gc.C) {
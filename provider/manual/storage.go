@@ -7,14 +7,23 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
 )
 
 // StorageProviderTypes implements storage.ProviderRegistry.
 func (*manualEnviron) StorageProviderTypes() ([]storage.ProviderType, error) {
-	return nil, nil
+	return []storage.ProviderType{provider.DeviceProviderType}, nil
 }
 
 // StorageProvider implements storage.ProviderRegistry.
+//
+// The device provider allocates block devices on the machines to
+// volumes. Its volumes are managed by the machine agents, which list
+// the devices themselves, so the provider returned here is only used
+// for validating storage pools.
 func (*manualEnviron) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	if t == provider.DeviceProviderType {
+		return provider.NewDeviceProvider(nil), nil
+	}
 	return nil, errors.NotFoundf("storage provider %q", t)
 }
@@ -930,6 +930,9 @@ func (c *neutronFirewaller) ingressRulesInGroup(nameRegexp string) (rules []netw
 		remotePrefix := p.RemoteIPPrefix
 		if remotePrefix == "" {
 			remotePrefix = "0.0.0.0/0"
+			if p.EthernetType == "IPv6" {
+				remotePrefix = "::/0"
+			}
 		}
 		sourceCIDRs, ok := portSourceCIDRs[portRange]
 		if !ok {
@@ -1205,10 +1205,10 @@ func (s *localServerSuite) TestConstraintsValidator(c *gc.C) {
 	env := s.Open(c, s.env.Config())
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 cpu-power=10 virt-type=lxd")
+	cons := constraints.MustParse("arch=amd64 cpu-power=10 virt-type=lxd interruptible=true max-price=0.05")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "interruptible", "max-price"})
 }
 
 func (s *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
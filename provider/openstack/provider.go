@@ -7,6 +7,7 @@ package openstack
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
 	"sync"
@@ -496,6 +497,8 @@ func (e *Environ) neutron() *neutron.Client {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Interruptible,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -608,6 +611,9 @@ func (e *Environ) PrecheckInstance(args environs.PrecheckInstanceParams) error {
 	if _, err := e.deriveAvailabilityZone(args.Placement, args.VolumeAttachments); err != nil {
 		return errors.Trace(err)
 	}
+	if err := common.ValidateZonesConstraint(e, args); err != nil {
+		return errors.Trace(err)
+	}
 	if !args.Constraints.HasInstanceType() {
 		return nil
 	}
@@ -1630,6 +1636,12 @@ func rulesToRuleInfo(groupId string, rules []network.IngressRule) []neutron.Rule
 		}
 		for _, sr := range sourceCIDRs {
 			ruleInfo.RemoteIPPrefix = sr
+			// Neutron requires the ethertype of a rule to
+			// match the family of its remote prefix.
+			ruleInfo.EthernetType = ""
+			if ip, _, err := net.ParseCIDR(sr); err == nil && ip.To4() == nil {
+				ruleInfo.EthernetType = "IPv6"
+			}
 			result = append(result, ruleInfo)
 		}
 	}
@@ -99,6 +99,11 @@ func (o *OracleEnviron) InstanceAvailabilityZoneNames(ids []instance.Id) ([]stri
 	return zones, nil
 }
 
+// DeriveAvailabilityZones is defined in the common.ZonedEnviron interface
+func (o *OracleEnviron) DeriveAvailabilityZones(args environs.StartInstanceParams) ([]string, error) {
+	return nil, nil
+}
+
 // NewOracleEnviron returns a new OracleEnviron
 func NewOracleEnviron(p *EnvironProvider, args environs.OpenParams, client EnvironAPI, c clock.Clock) (env *OracleEnviron, err error) {
 	if client == nil {
@@ -650,6 +655,8 @@ func (o *OracleEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		constraints.CpuPower,
 		constraints.RootDisk,
 		constraints.VirtType,
+		constraints.Interruptible,
+		constraints.MaxPrice,
 	}
 
 	// we choose to use the default validator implementation
@@ -765,8 +772,8 @@ func (o *OracleEnviron) Provider() environs.EnvironProvider {
 }
 
 // PrecheckInstance is part of the environs.Environ interface.
-func (o *OracleEnviron) PrecheckInstance(environs.PrecheckInstanceParams) error {
-	return nil
+func (o *OracleEnviron) PrecheckInstance(args environs.PrecheckInstanceParams) error {
+	return common.ValidateZonesConstraint(o, args)
 }
 
 // InstanceTypes is part of the environs.InstanceTypesFetcher interface.
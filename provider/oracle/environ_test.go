@@ -276,6 +276,18 @@ func (e *environSuite) TestPrecheckInstance(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 }
 
+func (e *environSuite) TestPrecheckInstanceZonesConstraint(c *gc.C) {
+	err := e.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Constraints: constraints.MustParse("zones=default"),
+	})
+	c.Assert(err, gc.IsNil)
+
+	err = e.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Constraints: constraints.MustParse("zones=elsewhere"),
+	})
+	c.Assert(err, gc.ErrorMatches, `availability zone "elsewhere" in zones constraint not valid`)
+}
+
 func (e *environSuite) TestInstanceTypes(c *gc.C) {
 	types, err := e.env.InstanceTypes(constraints.Value{})
 	c.Assert(err, gc.IsNil)
@@ -8,16 +8,19 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/common"
 )
 
 // PrecheckInstance is part of the environs.Environ interface.
 func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error {
-	if args.Placement == "" {
-		return nil
+	if args.Placement != "" {
+		if err := env.withSession(func(env *sessionEnviron) error {
+			return env.PrecheckInstance(args)
+		}); err != nil {
+			return err
+		}
 	}
-	return env.withSession(func(env *sessionEnviron) error {
-		return env.PrecheckInstance(args)
-	})
+	return common.ValidateZonesConstraint(env, args)
 }
 
 // PrecheckInstance is part of the environs.Environ interface.
@@ -29,6 +32,8 @@ func (env *sessionEnviron) PrecheckInstance(args environs.PrecheckInstanceParams
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Interruptible,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
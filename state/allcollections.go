@@ -148,6 +148,20 @@ func allCollections() collectionSchema {
 			global: true,
 		},
 
+		// This collection holds groups of local users, through which
+		// access may be granted to all members at once.
+		userGroupsC: {
+			global:  true,
+			indexes: []mgo.Index{{Key: []string{"members"}}},
+		},
+
+		// This collection holds the API tokens users have created
+		// for automation to log in with.
+		apiTokensC: {
+			global:  true,
+			indexes: []mgo.Index{{Key: []string{"owner"}}},
+		},
+
 		// This collection holds the last time the user connected to the API server.
 		userLastLoginC: {
 			global:    true,
@@ -303,6 +317,15 @@ func allCollections() collectionSchema {
 		rebootC:      {},
 		sshHostKeysC: {},
 
+		// This collection holds samples of machine resource usage
+		// reported by machine agents.
+		machineUsageC: {
+			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "machine-id", "time"},
+			}},
+		},
+
 		// This collection contains information from removed machines
 		// that needs to be cleaned up in the provider.
 		machineRemovalsC: {},
@@ -471,6 +494,14 @@ func allCollections() collectionSchema {
 		// eg addresses.
 		cloudServicesC: {},
 
+		// secretsC holds the encrypted secrets owned by
+		// applications.
+		secretsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "application"},
+			}},
+		},
+
 		// ----------------------
 
 		// Raw-access collections
@@ -491,6 +522,7 @@ const (
 	actionresultsC           = "actionresults"
 	actionsC                 = "actions"
 	annotationsC             = "annotations"
+	apiTokensC               = "apitokens"
 	autocertCacheC           = "autocertCache"
 	assignUnitC              = "assignUnits"
 	bakeryStorageItemsC      = "bakeryStorageItems"
@@ -538,12 +570,14 @@ const (
 	relationScopesC          = "relationscopes"
 	relationsC               = "relations"
 	restoreInfoC             = "restoreInfo"
+	secretsC                 = "secrets"
 	sequenceC                = "sequence"
 	applicationsC            = "applications"
 	endpointBindingsC        = "endpointbindings"
 	settingsC                = "settings"
 	refcountsC               = "refcounts"
 	sshHostKeysC             = "sshhostkeys"
+	machineUsageC            = "machineusage"
 	spacesC                  = "spaces"
 	statusesC                = "statuses"
 	statusesHistoryC         = "statuseshistory"
@@ -559,6 +593,7 @@ const (
 	txnsC                    = "txns"
 	unitsC                   = "units"
 	upgradeInfoC             = "upgradeInfo"
+	userGroupsC              = "usergroups"
 	userLastLoginC           = "userLastLogin"
 	usermodelnameC           = "usermodelname"
 	usersC                   = "users"
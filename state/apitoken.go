@@ -0,0 +1,258 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// APIToken represents a named, expiring credential that a user can
+// hand to automation in place of their password. A token may be
+// limited to particular models and facades, or to read-only calls.
+type APIToken struct {
+	st  *State
+	doc apiTokenDoc
+}
+
+type apiTokenDoc struct {
+	DocID       string    `bson:"_id"`
+	Name        string    `bson:"name"`
+	Owner       string    `bson:"owner"`
+	SecretHash  string    `bson:"secrethash"`
+	Models      []string  `bson:"models,omitempty"`
+	Facades     []string  `bson:"facades,omitempty"`
+	ReadOnly    bool      `bson:"readonly"`
+	DateCreated time.Time `bson:"datecreated"`
+	Expires     time.Time `bson:"expires"`
+}
+
+// APITokenSpec holds the parameters for creating an API token.
+type APITokenSpec struct {
+	// Name identifies the token among those owned by the same user.
+	Name string
+
+	// Owner is the user the token acts as.
+	Owner names.UserTag
+
+	// Expires is the time after which the token can no longer be
+	// used. It is required.
+	Expires time.Time
+
+	// Models, if non-empty, limits the token to logging in to the
+	// given models.
+	Models []names.ModelTag
+
+	// Facades, if non-empty, limits the token to calling the
+	// named facades.
+	Facades []string
+
+	// ReadOnly limits the token to read-only calls.
+	ReadOnly bool
+}
+
+func apiTokenID(owner names.UserTag, name string) string {
+	return userAccessID(owner) + ":" + name
+}
+
+// FormatAPIToken returns the value presented by a client to log in
+// with the token with the given ID and secret.
+func FormatAPIToken(id, secret string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id)) + "." + secret
+}
+
+// ParseAPIToken splits the value of a token, as returned by
+// FormatAPIToken, into the token's ID and secret.
+func ParseAPIToken(value string) (id, secret string, err error) {
+	parts := strings.SplitN(value, ".", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", errors.NotValidf("API token")
+	}
+	rawID, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(rawID) == 0 {
+		return "", "", errors.NotValidf("API token")
+	}
+	return string(rawID), parts[1], nil
+}
+
+// AddAPIToken creates a new API token from the given spec, returning
+// the token and the value to be presented by clients to log in with
+// it. The value is not stored and cannot be retrieved again.
+func (st *State) AddAPIToken(spec APITokenSpec) (*APIToken, string, error) {
+	if !names.IsValidUserName(spec.Name) {
+		return nil, "", errors.NotValidf("token name %q", spec.Name)
+	}
+	if spec.Expires.IsZero() {
+		return nil, "", errors.NotValidf("token without expiry time")
+	}
+	now := st.nowToTheSecond()
+	if !spec.Expires.After(now) {
+		return nil, "", errors.NotValidf("expiry time %s in the past", spec.Expires.UTC().Format(time.RFC3339))
+	}
+	var ops []txn.Op
+	if spec.Owner.IsLocal() {
+		if _, err := st.User(spec.Owner); err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      usersC,
+			Id:     userAccessID(spec.Owner),
+			Assert: bson.D{{"deleted", bson.D{{"$ne", true}}}},
+		})
+	}
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	models := make([]string, len(spec.Models))
+	for i, m := range spec.Models {
+		models[i] = m.Id()
+	}
+	token := &APIToken{
+		st: st,
+		doc: apiTokenDoc{
+			DocID:       apiTokenID(spec.Owner, spec.Name),
+			Name:        spec.Name,
+			Owner:       spec.Owner.Id(),
+			SecretHash:  utils.AgentPasswordHash(secret),
+			Models:      models,
+			Facades:     spec.Facades,
+			ReadOnly:    spec.ReadOnly,
+			DateCreated: now,
+			Expires:     spec.Expires.UTC(),
+		},
+	}
+	ops = append(ops, txn.Op{
+		C:      apiTokensC,
+		Id:     token.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &token.doc,
+	})
+	if err := st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			if _, err := st.APIToken(token.doc.DocID); err == nil {
+				return nil, "", errors.AlreadyExistsf("token %q for %q", spec.Name, spec.Owner.Id())
+			}
+			err = errors.Errorf("user %q no longer exists", spec.Owner.Id())
+		}
+		return nil, "", errors.Annotatef(err, "adding token %q", spec.Name)
+	}
+	return token, FormatAPIToken(token.doc.DocID, secret), nil
+}
+
+// APIToken returns the API token with the given ID.
+func (st *State) APIToken(id string) (*APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	token := &APIToken{st: st}
+	err := tokens.FindId(id).One(&token.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("API token %q", id)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	token.doc.DateCreated = token.doc.DateCreated.UTC()
+	token.doc.Expires = token.doc.Expires.UTC()
+	return token, nil
+}
+
+// APITokensForUser returns the API tokens owned by the given user,
+// sorted by name.
+func (st *State) APITokensForUser(owner names.UserTag) ([]*APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	var docs []apiTokenDoc
+	if err := tokens.Find(bson.D{{"owner", owner.Id()}}).Sort("name").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]*APIToken, len(docs))
+	for i, doc := range docs {
+		doc.DateCreated = doc.DateCreated.UTC()
+		doc.Expires = doc.Expires.UTC()
+		result[i] = &APIToken{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RevokeAPIToken removes the named token owned by the given user, so
+// that it can no longer be used to log in. Connections already made
+// with the token are not affected.
+func (st *State) RevokeAPIToken(owner names.UserTag, name string) error {
+	ops := []txn.Op{{
+		C:      apiTokensC,
+		Id:     apiTokenID(owner, name),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("token %q for %q", name, owner.Id())
+	}
+	return errors.Trace(err)
+}
+
+// ID returns the unique ID of the token.
+func (t *APIToken) ID() string {
+	return t.doc.DocID
+}
+
+// Name returns the name of the token.
+func (t *APIToken) Name() string {
+	return t.doc.Name
+}
+
+// Owner returns the tag of the user the token acts as.
+func (t *APIToken) Owner() names.UserTag {
+	return names.NewUserTag(t.doc.Owner)
+}
+
+// Models returns the models the token is limited to. If empty, the
+// token may be used with any model the owner has access to.
+func (t *APIToken) Models() []names.ModelTag {
+	result := make([]names.ModelTag, len(t.doc.Models))
+	for i, m := range t.doc.Models {
+		result[i] = names.NewModelTag(m)
+	}
+	return result
+}
+
+// Facades returns the names of the facades the token is limited to.
+// If empty, the token may be used with any facade.
+func (t *APIToken) Facades() []string {
+	result := make([]string, len(t.doc.Facades))
+	copy(result, t.doc.Facades)
+	return result
+}
+
+// ReadOnly reports whether the token is limited to read-only calls.
+func (t *APIToken) ReadOnly() bool {
+	return t.doc.ReadOnly
+}
+
+// DateCreated returns when the token was created in UTC.
+func (t *APIToken) DateCreated() time.Time {
+	return t.doc.DateCreated
+}
+
+// Expires returns the time after which the token can no longer be
+// used, in UTC.
+func (t *APIToken) Expires() time.Time {
+	return t.doc.Expires
+}
+
+// SecretValid reports whether the given secret matches the token's.
+func (t *APIToken) SecretValid(secret string) bool {
+	return utils.AgentPasswordHash(secret) == t.doc.SecretHash
+}
@@ -0,0 +1,135 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type APITokenSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&APITokenSuite{})
+
+func (s *APITokenSuite) spec(owner names.UserTag, name string) state.APITokenSpec {
+	return state.APITokenSpec{
+		Name:    name,
+		Owner:   owner,
+		Expires: state.NowToTheSecond(s.State).Add(24 * time.Hour),
+	}
+}
+
+func (s *APITokenSuite) TestAddAPIToken(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	spec := s.spec(bob.UserTag(), "ci")
+	spec.Models = []names.ModelTag{s.Model.ModelTag()}
+	spec.Facades = []string{"Application"}
+	spec.ReadOnly = true
+	token, value, err := s.State.AddAPIToken(spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Name(), gc.Equals, "ci")
+	c.Assert(token.Owner(), gc.Equals, bob.UserTag())
+	c.Assert(token.Models(), jc.DeepEquals, []names.ModelTag{s.Model.ModelTag()})
+	c.Assert(token.Facades(), jc.DeepEquals, []string{"Application"})
+	c.Assert(token.ReadOnly(), jc.IsTrue)
+	c.Assert(token.Expires(), gc.Equals, spec.Expires.UTC())
+
+	id, secret, err := state.ParseAPIToken(value)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, token.ID())
+
+	token, err = s.State.APIToken(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Name(), gc.Equals, "ci")
+	c.Assert(token.SecretValid(secret), jc.IsTrue)
+	c.Assert(token.SecretValid(secret+"x"), jc.IsFalse)
+}
+
+func (s *APITokenSuite) TestAddAPITokenDuplicate(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	_, _, err := s.State.AddAPIToken(s.spec(bob.UserTag(), "ci"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.State.AddAPIToken(s.spec(bob.UserTag(), "ci"))
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `token "ci" for "bob" already exists`)
+
+	// Different users may use the same name.
+	mary := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary", NoModelUser: true})
+	_, _, err = s.State.AddAPIToken(s.spec(mary.UserTag(), "ci"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *APITokenSuite) TestAddAPITokenInvalid(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+
+	spec := s.spec(bob.UserTag(), "not valid")
+	_, _, err := s.State.AddAPIToken(spec)
+	c.Assert(err, gc.ErrorMatches, `token name "not valid" not valid`)
+
+	spec = s.spec(bob.UserTag(), "ci")
+	spec.Expires = time.Time{}
+	_, _, err = s.State.AddAPIToken(spec)
+	c.Assert(err, gc.ErrorMatches, `token without expiry time not valid`)
+
+	spec.Expires = state.NowToTheSecond(s.State).Add(-time.Hour)
+	_, _, err = s.State.AddAPIToken(spec)
+	c.Assert(err, gc.ErrorMatches, `expiry time .* in the past not valid`)
+
+	_, _, err = s.State.AddAPIToken(s.spec(names.NewUserTag("ghost"), "ci"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *APITokenSuite) TestAPITokensForUser(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	for _, name := range []string{"deploy", "ci", "monitoring"} {
+		_, _, err := s.State.AddAPIToken(s.spec(bob.UserTag(), name))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, _, err := s.State.AddAPIToken(s.spec(names.NewUserTag("mary@external"), "ci"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	tokens, err := s.State.APITokensForUser(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	var tokenNames []string
+	for _, t := range tokens {
+		tokenNames = append(tokenNames, t.Name())
+	}
+	c.Assert(tokenNames, jc.DeepEquals, []string{"ci", "deploy", "monitoring"})
+}
+
+func (s *APITokenSuite) TestRevokeAPIToken(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	token, _, err := s.State.AddAPIToken(s.spec(bob.UserTag(), "ci"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RevokeAPIToken(bob.UserTag(), "ci")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.APIToken(token.ID())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RevokeAPIToken(bob.UserTag(), "ci")
+	c.Assert(err, gc.ErrorMatches, `token "ci" for "bob" not found`)
+}
+
+func (s *APITokenSuite) TestParseAPIToken(c *gc.C) {
+	value := state.FormatAPIToken("bob:ci", "s3cret")
+	id, secret, err := state.ParseAPIToken(value)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "bob:ci")
+	c.Assert(secret, gc.Equals, "s3cret")
+
+	for _, bad := range []string{"", "abc", ".secret", "YWJj.", "!!!.secret"} {
+		_, _, err := state.ParseAPIToken(bad)
+		c.Check(err, gc.ErrorMatches, "API token not valid", gc.Commentf("%q", bad))
+	}
+}
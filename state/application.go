@@ -343,6 +343,20 @@ func (a *Application) removeOps(asserts bson.D) ([]txn.Op, error) {
 	}
 	ops = append(ops, removeOfferOps...)
 
+	// Remove access granted on the application.
+	removeAccessOps, err := removeApplicationAccessOps(a.st, a.doc.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, removeAccessOps...)
+
+	// Remove secrets owned by the application.
+	removeSecretsOps, err := removeApplicationSecretsOps(a.st, a.doc.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, removeSecretsOps...)
+
 	// Note that appCharmDecRefOps might not catch the final decref
 	// when run in a transaction that decrefs more than once. So we
 	// avoid attempting to do the final cleanup in the ref dec ops and
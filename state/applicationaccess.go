@@ -0,0 +1,144 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/permission"
+)
+
+const applicationAccessGlobalKeyPrefix = "ap"
+
+// applicationAccessKey returns the key of the object used when
+// granting access to the named application in the given model.
+// Unlike an application's own global key, it includes the model
+// UUID because permissions are held in a global collection.
+func applicationAccessKey(modelUUID, appName string) string {
+	return fmt.Sprintf("%s#%s#%s", applicationAccessGlobalKeyPrefix, modelUUID, appName)
+}
+
+// SetApplicationAccess grants the given user read, operate or admin
+// access to the named application, replacing any existing grant.
+// Access granted on an application is in addition to the access the
+// user has on the model; a user with no access to the model may still
+// log in to it to use the applications they have been granted access
+// to.
+func (st *State) SetApplicationAccess(appName string, user names.UserTag, access permission.Access) error {
+	if err := permission.ValidateApplicationAccess(access); err != nil {
+		return errors.Trace(err)
+	}
+	if user.IsLocal() {
+		if _, err := st.User(user); err != nil {
+			if errors.IsNotFound(err) {
+				return errors.Annotatef(err, "user %q does not exist locally", user.Name())
+			}
+			return errors.Trace(err)
+		}
+	}
+	objectKey := applicationAccessKey(st.ModelUUID(), appName)
+	subjectKey := userGlobalKey(userAccessID(user))
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.Application(appName); err != nil {
+			return nil, errors.Trace(err)
+		}
+		appOp := txn.Op{
+			C:      applicationsC,
+			Id:     st.docID(appName),
+			Assert: isAliveDoc,
+		}
+		_, err := st.userPermission(objectKey, subjectKey)
+		if errors.IsNotFound(err) {
+			return []txn.Op{appOp, createPermissionOp(objectKey, subjectKey, access)}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{appOp, updatePermissionOp(objectKey, subjectKey, access)}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// RemoveApplicationAccess revokes access granted to the given user on
+// the named application. Access the user has on the model is not
+// affected.
+func (st *State) RemoveApplicationAccess(appName string, user names.UserTag) error {
+	ops := []txn.Op{removePermissionOp(
+		applicationAccessKey(st.ModelUUID(), appName),
+		userGlobalKey(userAccessID(user)),
+	)}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("access for %q on application %q", user.Id(), appName)
+	}
+	return errors.Trace(err)
+}
+
+// ApplicationAccess returns the access granted directly to the given
+// user on the named application.
+func (st *State) ApplicationAccess(appName string, user names.UserTag) (permission.Access, error) {
+	perm, err := st.userPermission(
+		applicationAccessKey(st.ModelUUID(), appName),
+		userGlobalKey(userAccessID(user)),
+	)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return perm.access(), nil
+}
+
+// ApplicationUsers returns the access granted directly to users on the
+// named application, keyed by user name.
+func (st *State) ApplicationUsers(appName string) (map[string]permission.Access, error) {
+	perms, err := st.usersPermissions(applicationAccessKey(st.ModelUUID(), appName))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]permission.Access)
+	for _, p := range perms {
+		// Skip access granted to user groups.
+		if !strings.HasPrefix(p.doc.SubjectGlobalKey, userGlobalKeyPrefix+"#") {
+			continue
+		}
+		result[userIDFromGlobalKey(p.doc.SubjectGlobalKey)] = p.access()
+	}
+	return result, nil
+}
+
+// HasApplicationAccess reports whether the given user has been granted
+// access to any application in the model, either directly or through
+// one of their user groups.
+func (st *State) HasApplicationAccess(user names.UserTag) (bool, error) {
+	subjects := []string{userGlobalKey(userAccessID(user))}
+	groups, err := st.UserGroupsForUser(user)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, group := range groups {
+		subjects = append(subjects, userGroupGlobalKey(group.doc.DocID))
+	}
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+	n, err := permissions.Find(bson.D{
+		{"object-global-key", bson.D{{"$regex", "^" + applicationAccessKey(st.ModelUUID(), "")}}},
+		{"subject-global-key", bson.D{{"$in", subjects}}},
+	}).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return n > 0, nil
+}
+
+// removeApplicationAccessOps returns the operations required to remove
+// all access granted on the named application.
+func removeApplicationAccessOps(st *State, appName string) ([]txn.Op, error) {
+	permPattern := bson.D{{"object-global-key", applicationAccessKey(st.ModelUUID(), appName)}}
+	ops, err := st.removeInCollectionOps(permissionsC, permPattern)
+	return ops, errors.Trace(err)
+}
@@ -0,0 +1,133 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type ApplicationAccessSuite struct {
+	ConnSuite
+	charm *state.Charm
+	app   *state.Application
+	bob   names.UserTag
+}
+
+var _ = gc.Suite(&ApplicationAccessSuite{})
+
+func (s *ApplicationAccessSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "mysql")
+	s.app = s.AddTestingApplication(c, "mysql", s.charm)
+	s.bob = s.Factory.MakeUser(c, &factory.UserParams{
+		Name:   "bob",
+		Access: permission.ReadAccess,
+	}).UserTag()
+}
+
+func (s *ApplicationAccessSuite) TestSetApplicationAccess(c *gc.C) {
+	_, err := s.State.ApplicationAccess("mysql", s.bob)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetApplicationAccess("mysql", s.bob, permission.OperateAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.ApplicationAccess("mysql", s.bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.OperateAccess)
+
+	err = s.State.SetApplicationAccess("mysql", s.bob, permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err = s.State.UserPermission(s.bob, s.app.ApplicationTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+
+	users, err := s.State.ApplicationUsers("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, jc.DeepEquals, map[string]permission.Access{"bob": permission.AdminAccess})
+}
+
+func (s *ApplicationAccessSuite) TestSetApplicationAccessInvalid(c *gc.C) {
+	err := s.State.SetApplicationAccess("mysql", s.bob, permission.WriteAccess)
+	c.Assert(err, gc.ErrorMatches, `"write" application access not valid`)
+
+	err = s.State.SetApplicationAccess("wordpress", s.bob, permission.ReadAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetApplicationAccess("mysql", names.NewUserTag("ghost"), permission.ReadAccess)
+	c.Assert(err, gc.ErrorMatches, `user "ghost" does not exist locally: user "ghost" not found`)
+}
+
+func (s *ApplicationAccessSuite) TestRemoveApplicationAccess(c *gc.C) {
+	err := s.State.SetApplicationAccess("mysql", s.bob, permission.OperateAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveApplicationAccess("mysql", s.bob)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ApplicationAccess("mysql", s.bob)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveApplicationAccess("mysql", s.bob)
+	c.Assert(err, gc.ErrorMatches, `access for "bob" on application "mysql" not found`)
+}
+
+func (s *ApplicationAccessSuite) TestEffectiveApplicationPermission(c *gc.C) {
+	group, err := s.State.AddUserGroup("dbas", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.AddMember(s.bob), jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("dbas", s.app.ApplicationTag(), permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetApplicationAccess("mysql", s.bob, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.EffectiveUserPermission(s.bob, s.app.ApplicationTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *ApplicationAccessSuite) TestHasApplicationAccess(c *gc.C) {
+	has, err := s.State.HasApplicationAccess(s.bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(has, jc.IsFalse)
+
+	err = s.State.SetApplicationAccess("mysql", s.bob, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	has, err = s.State.HasApplicationAccess(s.bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(has, jc.IsTrue)
+
+	// Access granted to one of the user's groups counts too.
+	err = s.State.RemoveApplicationAccess("mysql", s.bob)
+	c.Assert(err, jc.ErrorIsNil)
+	group, err := s.State.AddUserGroup("dbas", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.AddMember(s.bob), jc.ErrorIsNil)
+	has, err = s.State.HasApplicationAccess(s.bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(has, jc.IsFalse)
+	err = s.State.SetUserGroupAccess("dbas", s.app.ApplicationTag(), permission.OperateAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	has, err = s.State.HasApplicationAccess(s.bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(has, jc.IsTrue)
+}
+
+func (s *ApplicationAccessSuite) TestApplicationRemovalRemovesAccess(c *gc.C) {
+	err := s.State.SetApplicationAccess("mysql", s.bob, permission.OperateAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.app.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Access is not inherited by a new application of the same name.
+	s.AddTestingApplication(c, "mysql", s.charm)
+	_, err = s.State.ApplicationAccess("mysql", s.bob)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
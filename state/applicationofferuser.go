@@ -4,6 +4,8 @@
 package state
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
@@ -30,6 +32,10 @@ func (st *State) GetOfferUsers(offerUUID string) (map[string]permission.Access,
 	}
 	result := make(map[string]permission.Access)
 	for _, p := range perms {
+		// Skip access granted to user groups.
+		if !strings.HasPrefix(p.doc.SubjectGlobalKey, userGlobalKeyPrefix+"#") {
+			continue
+		}
 		result[userIDFromGlobalKey(p.doc.SubjectGlobalKey)] = p.access()
 	}
 	return result, nil
@@ -930,6 +930,22 @@ var assignUsingConstraintsTests = []struct {
 		unitConstraints:         "arch=amd64 mem=4G cores=2 root-disk=8192",
 		hardwareCharacteristics: "arch=amd64 mem=8G cores=1 root-disk=4096 cpu-power=50",
 		assignOk:                false,
+	}, {
+		unitConstraints:         "zones=az1,az2",
+		hardwareCharacteristics: "availability-zone=az2",
+		assignOk:                true,
+	}, {
+		unitConstraints:         "zones=az1,az2",
+		hardwareCharacteristics: "availability-zone=az3",
+		assignOk:                false,
+	}, {
+		unitConstraints:         "zones=az1",
+		hardwareCharacteristics: "mem=4G",
+		assignOk:                false,
+	}, {
+		unitConstraints:         "zones=",
+		hardwareCharacteristics: "mem=4G",
+		assignOk:                true,
 	},
 }
 
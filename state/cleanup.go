@@ -33,8 +33,15 @@ const (
 	cleanupMachinesForDyingModel         cleanupKind = "modelMachines"
 	cleanupResourceBlob                  cleanupKind = "resourceBlob"
 	cleanupStorageForDyingModel          cleanupKind = "modelStorage"
+	cleanupReplacementMachineStorage     cleanupKind = "replacementMachineStorage"
 )
 
+// errCleanupPending is returned by cleanups that cannot complete
+// until some other change has been made, such as storage being
+// detached. Such cleanups are retried without being logged as
+// failures.
+var errCleanupPending = errors.New("cleanup pending")
+
 // cleanupDoc originally represented a set of documents that should be
 // removed, but the Prefix field no longer means anything more than
 // "what will be passed to the cleanup func".
@@ -145,10 +152,18 @@ func (st *State) Cleanup() (err error) {
 			err = st.cleanupResourceBlob(doc.Prefix)
 		case cleanupStorageForDyingModel:
 			err = st.cleanupStorageForDyingModel(args)
+		case cleanupReplacementMachineStorage:
+			err = st.cleanupReplacementMachineStorage(doc.Prefix)
 		default:
 			err = errors.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
-		if err != nil {
+		if errors.Cause(err) == errCleanupPending {
+			logger.Debugf(
+				"cleanup pending in model %v for %v(%q): %v",
+				modelUUID, doc.Kind, doc.Prefix, err,
+			)
+			continue
+		} else if err != nil {
 			logger.Errorf(
 				"cleanup failed in model %v for %v(%q): %v",
 				modelUUID, doc.Kind, doc.Prefix, err,
@@ -43,6 +43,7 @@ type Controller struct {
 	policy                 Policy
 	newPolicy              NewPolicyFunc
 	runTransactionObserver RunTransactionObserverFunc
+	secretsKey             []byte
 }
 
 // Close the connection to the database.
@@ -62,6 +63,7 @@ func (ctlr *Controller) NewState(modelTag names.ModelTag) (*State, error) {
 		ctlr.newPolicy,
 		ctlr.clock,
 		ctlr.runTransactionObserver,
+		ctlr.secretsKey,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
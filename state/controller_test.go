@@ -36,6 +36,28 @@ func (s *ControllerSuite) TestControllerAndModelConfigInitialisation(c *gc.C) {
 		controller.JujuHASpace,
 		controller.JujuManagementSpace,
 		controller.AuditLogExcludeMethods,
+		controller.OIDCIssuerURL,
+		controller.OIDCClientID,
+		controller.OIDCUsernameClaim,
+		controller.OIDCGroupsClaim,
+		controller.LDAPURL,
+		controller.LDAPUserDNTemplate,
+		controller.LDAPGroupBaseDN,
+		controller.LDAPAutoCreateUsers,
+		controller.LDAPStartTLS,
+		controller.LDAPCACert,
+		controller.LDAPBindDN,
+		controller.LDAPBindPassword,
+		controller.PasswordMinLength,
+		controller.PasswordMinClasses,
+		controller.PasswordMaxAge,
+		controller.LoginLockoutThreshold,
+		controller.LoginLockoutDuration,
+		controller.RequireMFA,
+		controller.ExternalAPICertKey,
+		controller.ExternalAPIKeyKey,
+		controller.ExternalAPICertPathKey,
+		controller.ExternalAPIKeyPathKey,
 	)
 	for _, controllerAttr := range controller.ControllerOnlyConfigAttributes {
 		v, ok := controllerSettings.Get(controllerAttr)
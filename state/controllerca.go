@@ -0,0 +1,422 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/cert"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	jujucert "github.com/juju/juju/cert"
+	jujucontroller "github.com/juju/juju/controller"
+)
+
+// caRotationKey is the key for the controller CA rotation document.
+const caRotationKey = "caRotation"
+
+// CARotationPhase describes how far a controller CA rotation has
+// progressed.
+type CARotationPhase string
+
+const (
+	// CARotationTrusting is the first phase of a CA rotation. The
+	// controller CA certificate is a bundle of the old and new CA
+	// certificates, so that agents and clients come to trust both,
+	// while server certificates are still signed by the old CA.
+	CARotationTrusting CARotationPhase = "trusting"
+
+	// CARotationReissued is the second phase of a CA rotation. Server
+	// certificates are signed by the new CA, while both CAs are still
+	// trusted.
+	CARotationReissued CARotationPhase = "reissued"
+)
+
+// CARotation describes a controller CA rotation in progress.
+type CARotation struct {
+	// Phase holds the phase the rotation is in.
+	Phase CARotationPhase
+
+	// OldCACert holds the CA certificate being retired, in PEM format.
+	OldCACert string
+
+	// NewCACert holds the CA certificate being introduced, in PEM format.
+	NewCACert string
+
+	// Started holds the time the rotation was started.
+	Started time.Time
+
+	// PendingAgents holds the agents that have not yet acknowledged
+	// the new CA, as "<model UUID>:<tag>". The rotation cannot move
+	// on from the trusting phase, or finish, until there are none.
+	PendingAgents []string
+
+	// PendingControllers holds the ids of the controller machines
+	// that have not yet reissued their server certificates with the
+	// new CA. The rotation cannot finish until there are none.
+	PendingControllers []string
+}
+
+// caRotationDoc records a controller CA rotation in progress. The
+// new CA private key is held here until the server certificates are
+// reissued, when it becomes the controller's CA private key.
+type caRotationDoc struct {
+	DocID     string          `bson:"_id"`
+	Phase     CARotationPhase `bson:"phase"`
+	OldCACert string          `bson:"old-ca-cert"`
+	NewCACert string          `bson:"new-ca-cert"`
+	NewCAKey  string          `bson:"new-ca-key"`
+	Started   time.Time       `bson:"started"`
+
+	// Acknowledged holds the agents, as "<model UUID>:<tag>", that
+	// have recorded a CA certificate including the new CA.
+	Acknowledged []string `bson:"acknowledged,omitempty"`
+
+	// Reissued holds the ids of the controller machines that have
+	// reissued their server certificates with the new CA.
+	Reissued []string `bson:"reissued,omitempty"`
+}
+
+// ControllerCARotation returns the controller CA rotation in progress,
+// or an error satisfying errors.IsNotFound if there is none.
+func (st *State) ControllerCARotation() (*CARotation, error) {
+	doc, err := st.caRotationDoc()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pendingAgents, err := st.caRotationPendingAgents(doc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pendingControllers, err := st.caRotationPendingControllers(doc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &CARotation{
+		Phase:              doc.Phase,
+		OldCACert:          doc.OldCACert,
+		NewCACert:          doc.NewCACert,
+		Started:            doc.Started,
+		PendingAgents:      pendingAgents,
+		PendingControllers: pendingControllers,
+	}, nil
+}
+
+func (st *State) caRotationDoc() (*caRotationDoc, error) {
+	controllers, closer := st.db().GetCollection(controllersC)
+	defer closer()
+	var doc caRotationDoc
+	err := controllers.FindId(caRotationKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("controller CA rotation")
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller CA rotation")
+	}
+	return &doc, nil
+}
+
+// StartControllerCARotation starts rotating the controller CA to the
+// given CA certificate and private key. The controller CA certificate
+// becomes a bundle of the current and new CA certificates, so that
+// agents and clients trust server certificates signed by either.
+func (st *State) StartControllerCARotation(caCert, caKey string) error {
+	xcert, _, err := cert.ParseCertAndKey(caCert, caKey)
+	if err != nil {
+		return errors.Annotate(err, "cannot parse new CA certificate and key")
+	}
+	if !xcert.IsCA {
+		return errors.NotValidf("new CA certificate that is not a CA")
+	}
+	settings, err := readSettings(st.db(), controllersC, controllerSettingsGlobalKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	oldCACert, _ := jujucontroller.Config(settings.Map()).CACert()
+	if certs, err := jujucert.ParseCerts(oldCACert); err != nil {
+		return errors.Annotate(err, "cannot parse current CA certificate")
+	} else if len(certs) != 1 {
+		return errors.AlreadyExistsf("controller CA rotation")
+	}
+	settings.Set(jujucontroller.CACertKey, oldCACert+caCert)
+	_, ops := settings.settingsUpdateOps()
+	ops = append(ops, txn.Op{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: txn.DocMissing,
+		Insert: &caRotationDoc{
+			DocID:     caRotationKey,
+			Phase:     CARotationTrusting,
+			OldCACert: oldCACert,
+			NewCACert: caCert,
+			NewCAKey:  caKey,
+			Started:   st.clock().Now().UTC(),
+		},
+	})
+	if err := st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.AlreadyExistsf("controller CA rotation")
+		}
+		return errors.Annotate(err, "cannot start controller CA rotation")
+	}
+	return nil
+}
+
+// ReissueControllerCerts moves a controller CA rotation in the trusting
+// phase on to the reissued phase. The new CA becomes the controller's
+// signing CA, and controller machines reissue their server certificates
+// with it; the old CA is still trusted.
+func (st *State) ReissueControllerCerts() error {
+	doc, err := st.caRotationDoc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if doc.Phase != CARotationTrusting {
+		return errors.Errorf("controller CA rotation is in phase %q, not %q", doc.Phase, CARotationTrusting)
+	}
+	if err := st.checkAgentsTrustNewCA(doc); err != nil {
+		return errors.Trace(err)
+	}
+	settings, err := readSettings(st.db(), controllersC, controllerSettingsGlobalKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	settings.Set(jujucontroller.CACertKey, doc.NewCACert+doc.OldCACert)
+	_, ops := settings.settingsUpdateOps()
+	ops = append(ops, txn.Op{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", CARotationTrusting}},
+		Update: bson.D{{"$set", bson.D{{"phase", CARotationReissued}}}},
+	}, txn.Op{
+		C:      controllersC,
+		Id:     stateServingInfoKey,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"caprivatekey", doc.NewCAKey}}}},
+	})
+	if err := st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.New("controller CA rotation changed concurrently")
+		}
+		return errors.Annotate(err, "cannot reissue controller certificates")
+	}
+	return nil
+}
+
+// FinishControllerCARotation completes a controller CA rotation in the
+// reissued phase. The old CA is no longer trusted, and the controller CA
+// certificate is just the new CA certificate.
+func (st *State) FinishControllerCARotation() error {
+	doc, err := st.caRotationDoc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if doc.Phase != CARotationReissued {
+		return errors.Errorf("controller CA rotation is in phase %q, not %q", doc.Phase, CARotationReissued)
+	}
+	if err := st.checkAgentsTrustNewCA(doc); err != nil {
+		return errors.Trace(err)
+	}
+	pendingControllers, err := st.caRotationPendingControllers(doc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(pendingControllers) > 0 {
+		return errors.Errorf(
+			"controller machines %v have not yet reissued their certificates with the new CA",
+			pendingControllers,
+		)
+	}
+	settings, err := readSettings(st.db(), controllersC, controllerSettingsGlobalKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	settings.Set(jujucontroller.CACertKey, doc.NewCACert)
+	_, ops := settings.settingsUpdateOps()
+	ops = append(ops, txn.Op{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", CARotationReissued}},
+		Remove: true,
+	})
+	if err := st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.New("controller CA rotation changed concurrently")
+		}
+		return errors.Annotate(err, "cannot finish controller CA rotation")
+	}
+	return nil
+}
+
+// AcknowledgeControllerCACert records that the agent with the given tag,
+// in this state's model, has recorded the given controller CA
+// certificate, which may be a bundle. Once every agent has acknowledged
+// a certificate including the new CA of a rotation in progress, the
+// rotation may move on.
+func (st *State) AcknowledgeControllerCACert(tag names.Tag, caCert string) error {
+	doc, err := st.caRotationDoc()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	key := caRotationAgentKey(st.ModelUUID(), tag)
+	if set.NewStrings(doc.Acknowledged...).Contains(key) {
+		return nil
+	}
+	if trusted, err := bundleIncludes(caCert, doc.NewCACert); err != nil {
+		return errors.Annotatef(err, "cannot parse CA certificate of %s", names.ReadableString(tag))
+	} else if !trusted {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"new-ca-cert", doc.NewCACert}},
+		Update: bson.D{{"$addToSet", bson.D{{"acknowledged", key}}}},
+	}}
+	if err := st.db().RunTransaction(ops); err != nil && err != txn.ErrAborted {
+		// An aborted transaction means the rotation finished
+		// or was replaced, and there is nothing to record.
+		return errors.Annotatef(err, "cannot acknowledge controller CA for %s", names.ReadableString(tag))
+	}
+	return nil
+}
+
+// RecordControllerCertReissued records that the controller machine with
+// the given id has reissued its server certificate with the new CA of
+// the rotation in progress.
+func (st *State) RecordControllerCertReissued(machineId string) error {
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", CARotationReissued}},
+		Update: bson.D{{"$addToSet", bson.D{{"reissued", machineId}}}},
+	}}
+	if err := st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			return errors.Errorf("no controller CA rotation in phase %q", CARotationReissued)
+		}
+		return errors.Annotatef(err, "cannot record certificate reissued by controller %q", machineId)
+	}
+	return nil
+}
+
+// checkAgentsTrustNewCA returns an error if any agent has not yet
+// acknowledged the new CA of the rotation.
+func (st *State) checkAgentsTrustNewCA(doc *caRotationDoc) error {
+	pending, err := st.caRotationPendingAgents(doc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(pending) > 0 {
+		const maxShown = 5
+		shown := pending
+		if len(shown) > maxShown {
+			shown = shown[:maxShown]
+		}
+		return errors.Errorf(
+			"%d agents have not yet acknowledged the new controller CA, including %v",
+			len(pending), shown,
+		)
+	}
+	return nil
+}
+
+// caRotationPendingAgents returns the agents, in all models, that have
+// not acknowledged the new CA of the rotation. Only agents of machines
+// and units that are not dead and whose agent has started, and so
+// reported its version, are included; agents that start later are
+// given the controller CA certificate current at the time.
+func (st *State) caRotationPendingAgents(doc *caRotationDoc) ([]string, error) {
+	acknowledged := set.NewStrings(doc.Acknowledged...)
+	started := bson.D{
+		{"life", bson.D{{"$ne", Dead}}},
+		{"tools", bson.D{{"$exists", true}}},
+	}
+	var pending []string
+
+	machines, closer := st.db().GetRawCollection(machinesC)
+	defer closer()
+	var mdoc struct {
+		ModelUUID string `bson:"model-uuid"`
+		Id        string `bson:"machineid"`
+	}
+	iter := machines.Find(started).Select(bson.D{{"model-uuid", 1}, {"machineid", 1}}).Iter()
+	for iter.Next(&mdoc) {
+		key := caRotationAgentKey(mdoc.ModelUUID, names.NewMachineTag(mdoc.Id))
+		if !acknowledged.Contains(key) {
+			pending = append(pending, key)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Annotate(err, "cannot read machines")
+	}
+
+	units, closer := st.db().GetRawCollection(unitsC)
+	defer closer()
+	var udoc struct {
+		ModelUUID string `bson:"model-uuid"`
+		Name      string `bson:"name"`
+	}
+	iter = units.Find(started).Select(bson.D{{"model-uuid", 1}, {"name", 1}}).Iter()
+	for iter.Next(&udoc) {
+		key := caRotationAgentKey(udoc.ModelUUID, names.NewUnitTag(udoc.Name))
+		if !acknowledged.Contains(key) {
+			pending = append(pending, key)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Annotate(err, "cannot read units")
+	}
+	sort.Strings(pending)
+	return pending, nil
+}
+
+// caRotationPendingControllers returns the ids of the controller
+// machines that have not reissued their certificates with the new CA.
+func (st *State) caRotationPendingControllers(doc *caRotationDoc) ([]string, error) {
+	info, err := st.ControllerInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	reissued := set.NewStrings(doc.Reissued...)
+	var pending []string
+	for _, id := range info.MachineIds {
+		if !reissued.Contains(id) {
+			pending = append(pending, id)
+		}
+	}
+	return pending, nil
+}
+
+func caRotationAgentKey(modelUUID string, tag names.Tag) string {
+	return modelUUID + ":" + tag.String()
+}
+
+// bundleIncludes returns whether the PEM-encoded certificate bundle
+// includes the given PEM-encoded certificate.
+func bundleIncludes(bundle, caCert string) (bool, error) {
+	xcert, err := cert.ParseCert(caCert)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	xcerts, err := jujucert.ParseCerts(bundle)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, c := range xcerts {
+		if bytes.Equal(c.Raw, xcert.Raw) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type ControllerCASuite struct {
+	ConnSuite
+	newCACert string
+	newCAKey  string
+}
+
+var _ = gc.Suite(&ControllerCASuite{})
+
+func (s *ControllerCASuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.newCACert = testing.OtherCACert
+	s.newCAKey = testing.OtherCAKey
+}
+
+func (s *ControllerCASuite) assertCACert(c *gc.C, expect string) {
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	c.Assert(caCert, gc.Equals, expect)
+}
+
+func (s *ControllerCASuite) TestNoRotation(c *gc.C) {
+	_, err := s.State.ControllerCARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ControllerCASuite) TestRotation(c *gc.C) {
+	err := s.State.SetStateServingInfo(state.StateServingInfo{
+		APIPort:      1,
+		StatePort:    2,
+		Cert:         testing.ServerCert,
+		PrivateKey:   testing.ServerKey,
+		CAPrivateKey: testing.CAKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.StartControllerCARotation(s.newCACert, s.newCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCACert(c, testing.CACert+s.newCACert)
+	rotation, err := s.State.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Phase, gc.Equals, state.CARotationTrusting)
+	c.Assert(rotation.OldCACert, gc.Equals, testing.CACert)
+	c.Assert(rotation.NewCACert, gc.Equals, s.newCACert)
+	c.Assert(rotation.Started.IsZero(), jc.IsFalse)
+
+	err = s.State.FinishControllerCARotation()
+	c.Assert(err, gc.ErrorMatches, `controller CA rotation is in phase "trusting", not "reissued"`)
+
+	// Certificates are not reissued until every running agent
+	// trusts the new CA.
+	machine := s.Factory.MakeMachine(c, nil)
+	err = machine.SetAgentVersion(version.MustParseBinary("2.4.0-xenial-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeMachine(c, nil) // agent not started
+	agentKey := s.State.ModelUUID() + ":" + machine.Tag().String()
+	rotation, err = s.State.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.PendingAgents, jc.DeepEquals, []string{agentKey})
+	err = s.State.ReissueControllerCerts()
+	c.Assert(err, gc.ErrorMatches, `1 agents have not yet acknowledged the new controller CA, including \[`+agentKey+`\]`)
+
+	// Acknowledging just the old CA changes nothing.
+	err = s.State.AcknowledgeControllerCACert(machine.Tag(), testing.CACert)
+	c.Assert(err, jc.ErrorIsNil)
+	rotation, err = s.State.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.PendingAgents, gc.HasLen, 1)
+
+	err = s.State.AcknowledgeControllerCACert(machine.Tag(), testing.CACert+s.newCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	rotation, err = s.State.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.PendingAgents, gc.HasLen, 0)
+
+	err = s.State.ReissueControllerCerts()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCACert(c, s.newCACert+testing.CACert)
+	info, err := s.State.StateServingInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.CAPrivateKey, gc.Equals, s.newCAKey)
+	c.Assert(info.Cert, gc.Equals, testing.ServerCert)
+	rotation, err = s.State.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Phase, gc.Equals, state.CARotationReissued)
+
+	err = s.State.ReissueControllerCerts()
+	c.Assert(err, gc.ErrorMatches, `controller CA rotation is in phase "reissued", not "trusting"`)
+
+	err = s.State.FinishControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCACert(c, s.newCACert)
+	_, err = s.State.ControllerCARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ControllerCASuite) TestStartRotationAlreadyStarted(c *gc.C) {
+	err := s.State.StartControllerCARotation(s.newCACert, s.newCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.StartControllerCARotation(s.newCACert, s.newCAKey)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ControllerCASuite) TestStartRotationMismatchedKey(c *gc.C) {
+	err := s.State.StartControllerCARotation(s.newCACert, testing.CAKey)
+	c.Assert(err, gc.ErrorMatches, "cannot parse new CA certificate and key: .*")
+	s.assertCACert(c, testing.CACert)
+}
+
+func (s *ControllerCASuite) TestStartRotationNotCA(c *gc.C) {
+	err := s.State.StartControllerCARotation(testing.ServerCert, testing.ServerKey)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ControllerCASuite) TestAcknowledgeNoRotation(c *gc.C) {
+	err := s.State.AcknowledgeControllerCACert(names.NewMachineTag("0"), testing.CACert)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ControllerCASuite) TestRecordControllerCertReissued(c *gc.C) {
+	err := s.State.RecordControllerCertReissued("0")
+	c.Assert(err, gc.ErrorMatches, `no controller CA rotation in phase "reissued"`)
+
+	err = s.State.SetStateServingInfo(state.StateServingInfo{
+		APIPort:      1,
+		StatePort:    2,
+		Cert:         testing.ServerCert,
+		PrivateKey:   testing.ServerKey,
+		CAPrivateKey: testing.CAKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.StartControllerCARotation(s.newCACert, s.newCAKey)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReissueControllerCerts()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RecordControllerCertReissued("0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ControllerCASuite) TestReissueNoRotation(c *gc.C) {
+	err := s.State.ReissueControllerCerts()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -754,3 +754,8 @@ func (st *State) IsUserSuperuser(user names.UserTag) (bool, error) {
 func (st *State) ModelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.Query, SessionCloser, error) {
 	return st.modelQueryForUser(user, isSuperuser)
 }
+
+// SetSecretsKey sets the key that the State encrypts secrets with.
+func SetSecretsKey(st *State, key []byte) {
+	st.secretsKey = key
+}
@@ -62,6 +62,9 @@ type InitializeParams struct {
 
 	// AdminPassword holds the password for the initial user.
 	AdminPassword string
+
+	// SecretsKey is the key that charm secrets are encrypted with.
+	SecretsKey []byte
 }
 
 // Validate checks that the state initialization parameters are valid.
@@ -153,6 +156,7 @@ func Initialize(args InitializeParams) (_ *Controller, _ *State, err error) {
 		MongoSession:       args.MongoSession,
 		NewPolicy:          args.NewPolicy,
 		InitDatabaseFunc:   InitDatabase,
+		SecretsKey:         args.SecretsKey,
 	})
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "opening controller")
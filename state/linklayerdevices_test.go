@@ -805,6 +805,33 @@ func (s *linkLayerDevicesStateSuite) TestGetNetworkInfoForSpaces(c *gc.C) {
 	c.Assert(resDoesNotExists.NetworkInfos, gc.HasLen, 0)
 }
 
+func (s *linkLayerDevicesStateSuite) TestGetNetworkInfoForSpacesDualStack(c *gc.C) {
+	s.createSpaceAndSubnet(c, "private", "10.20.0.0/24")
+	s.createSpaceAndSubnet(c, "private6", "fc00:20::/64")
+	s.createNICWithIP(c, s.machine, "eth0", "10.20.0.20/24")
+	s.createNICWithIP(c, s.machine, "eth1", "fc00:20::20/64")
+	s.machine.SetMachineAddresses(
+		network.NewScopedAddress("10.20.0.20", network.ScopeCloudLocal),
+		network.NewScopedAddress("fc00:20::20", network.ScopeCloudLocal),
+	)
+
+	// The default space holds a private address of each family.
+	res := s.machine.GetNetworkInfoForSpaces(set.NewStrings(""))
+	resEmpty, ok := res[""]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(resEmpty.Error, jc.ErrorIsNil)
+	addresses := make(map[string]string)
+	for _, info := range resEmpty.NetworkInfos {
+		for _, addr := range info.Addresses {
+			addresses[info.InterfaceName] = addr.Address
+		}
+	}
+	c.Check(addresses, jc.DeepEquals, map[string]string{
+		"eth0": "10.20.0.20",
+		"eth1": "fc00:20::20",
+	})
+}
+
 func (s *linkLayerDevicesStateSuite) TestLinkLayerDevicesForSpacesNoSuchSpace(c *gc.C) {
 	s.setupTwoSpaces(c)
 	// Is put into the 'default' space
@@ -948,7 +948,13 @@ func (m *Machine) Remove() (err error) {
 		}
 		return ops, nil
 	}
-	return m.st.db().Run(buildTxn)
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return err
+	}
+	if err := eraseMachineUsage(m.st, m.Id()); err != nil {
+		logger.Errorf("cannot delete usage for machine %q: %v", m.Id(), err)
+	}
+	return nil
 }
 
 // Refresh refreshes the contents of the machine from the underlying
@@ -1347,6 +1353,13 @@ func (m *Machine) PublicAddress() (network.Address, error) {
 	return publicAddress, err
 }
 
+// preferredFamilyMatch reports whether addr belongs to the preferred
+// address family. When IPv6 is not preferred any address matches, so
+// that an existing IPv6 preferred address is not needlessly replaced.
+func preferredFamilyMatch(addr address, preferIPv6 bool) bool {
+	return !preferIPv6 || network.AddressType(addr.AddressType) == network.IPv6Address
+}
+
 // maybeGetNewAddress determines if the current address is the most appropriate
 // match, and if not it selects the best from the slice of all available
 // addresses. It returns the new address and a bool indicating if a different
@@ -1447,7 +1460,7 @@ func (m *Machine) setPreferredAddressOps(addr address, isPublic bool) []txn.Op {
 	return ops
 }
 
-func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddresses []address) ([]txn.Op, *address) {
+func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddresses []address, preferIPv6 bool) ([]txn.Op, *address) {
 	publicAddress := m.doc.PreferredPublicAddress
 	logger.Tracef(
 		"machine %v: current public address: %#v \nprovider addresses: %#v \nmachine addresses: %#v",
@@ -1455,11 +1468,12 @@ func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddres
 
 	// Always prefer an exact match if available.
 	checkScope := func(addr address) bool {
-		return network.ExactScopeMatch(addr.networkAddress(), network.ScopePublic)
+		return network.ExactScopeMatch(addr.networkAddress(), network.ScopePublic) &&
+			preferredFamilyMatch(addr, preferIPv6)
 	}
 	// Without an exact match, prefer a fallback match.
 	getAddr := func(addresses []address) network.Address {
-		addr, _ := network.SelectPublicAddress(networkAddresses(addresses))
+		addr, _ := network.SelectPublicAddressPreferring(networkAddresses(addresses), preferIPv6)
 		return addr
 	}
 
@@ -1473,16 +1487,17 @@ func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddres
 	return ops, &newAddr
 }
 
-func (m *Machine) setPrivateAddressOps(providerAddresses []address, machineAddresses []address) ([]txn.Op, *address) {
+func (m *Machine) setPrivateAddressOps(providerAddresses []address, machineAddresses []address, preferIPv6 bool) ([]txn.Op, *address) {
 	privateAddress := m.doc.PreferredPrivateAddress
 	// Always prefer an exact match if available.
 	checkScope := func(addr address) bool {
 		return network.ExactScopeMatch(
-			addr.networkAddress(), network.ScopeMachineLocal, network.ScopeCloudLocal, network.ScopeFanLocal)
+			addr.networkAddress(), network.ScopeMachineLocal, network.ScopeCloudLocal, network.ScopeFanLocal) &&
+			preferredFamilyMatch(addr, preferIPv6)
 	}
 	// Without an exact match, prefer a fallback match.
 	getAddr := func(addresses []address) network.Address {
-		addr, _ := network.SelectInternalAddress(networkAddresses(addresses), false)
+		addr, _ := network.SelectInternalAddressPreferring(networkAddresses(addresses), false, preferIPv6)
 		return addr
 	}
 
@@ -1612,8 +1627,13 @@ func (m *Machine) setAddressesOps(
 		Update: bson.D{{"$set", set}},
 	}}
 
-	setPrivateAddressOps, newPrivate := m.setPrivateAddressOps(providerStateAddresses, machineStateAddresses)
-	setPublicAddressOps, newPublic := m.setPublicAddressOps(providerStateAddresses, machineStateAddresses)
+	modelConfig, err := m.st.cachedModelConfig()
+	if err != nil {
+		return nil, nil, nil, nil, nil, errors.Trace(err)
+	}
+	preferIPv6 := modelConfig.PreferIPv6()
+	setPrivateAddressOps, newPrivate := m.setPrivateAddressOps(providerStateAddresses, machineStateAddresses, preferIPv6)
+	setPublicAddressOps, newPublic := m.setPublicAddressOps(providerStateAddresses, machineStateAddresses, preferIPv6)
 	ops = append(ops, setPrivateAddressOps...)
 	ops = append(ops, setPublicAddressOps...)
 	return ops, machineStateAddresses, providerStateAddresses, newPrivate, newPublic, nil
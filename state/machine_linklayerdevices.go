@@ -1167,14 +1167,23 @@ func addAddressToResult(networkInfos []network.NetworkInfo, address *Address) ([
 func (m *Machine) GetNetworkInfoForSpaces(spaces set.Strings) map[string](MachineNetworkInfoResult) {
 	results := make(map[string](MachineNetworkInfoResult))
 
-	var privateAddress network.Address
+	// The default space holds the machine's preferred private
+	// address, along with its best private address of the other
+	// family on dual-stack machines.
+	privateAddresses := set.NewStrings()
+	var privateAddressList []string
 
 	if spaces.Contains(environs.DefaultSpaceName) {
-		var err error
-		privateAddress, err = m.PrivateAddress()
+		privateAddress, err := m.PrivateAddress()
 		if err != nil {
 			results[environs.DefaultSpaceName] = MachineNetworkInfoResult{Error: errors.Annotatef(err, "getting machine %q preferred private address", m.MachineTag())}
 			spaces.Remove(environs.DefaultSpaceName)
+		} else {
+			privateAddressList = append(privateAddressList, privateAddress.Value)
+			if other, ok := m.otherFamilyPrivateAddress(privateAddress); ok {
+				privateAddressList = append(privateAddressList, other.Value)
+			}
+			privateAddresses = set.NewStrings(privateAddressList...)
 		}
 	}
 
@@ -1209,7 +1218,7 @@ func (m *Machine) GetNetworkInfoForSpaces(spaces set.Strings) map[string](Machin
 					results[space] = r
 				}
 			}
-			if spaces.Contains(environs.DefaultSpaceName) && privateAddress.Value == addr.Value() {
+			if spaces.Contains(environs.DefaultSpaceName) && privateAddresses.Contains(addr.Value()) {
 				r := results[environs.DefaultSpaceName]
 				r.NetworkInfos, err = addAddressToResult(r.NetworkInfos, addr)
 				if err != nil {
@@ -1224,10 +1233,12 @@ func (m *Machine) GetNetworkInfoForSpaces(spaces set.Strings) map[string](Machin
 	// For a spaceless environment we won't find a subnet that's linked to privateAddress,
 	// we have to work around that and at least return minimal information.
 	if r, filledPrivateAddress := results[environs.DefaultSpaceName]; !filledPrivateAddress && spaces.Contains(environs.DefaultSpaceName) {
+		var addresses []network.InterfaceAddress
+		for _, value := range privateAddressList {
+			addresses = append(addresses, network.InterfaceAddress{Address: value})
+		}
 		r.NetworkInfos = []network.NetworkInfo{{
-			Addresses: []network.InterfaceAddress{{
-				Address: privateAddress.Value,
-			}},
+			Addresses: addresses,
 		}}
 		results[environs.DefaultSpaceName] = r
 	}
@@ -1242,3 +1253,24 @@ func (m *Machine) GetNetworkInfoForSpaces(spaces set.Strings) map[string](Machin
 	}
 	return results
 }
+
+// otherFamilyPrivateAddress returns the machine's best private address
+// of the IP address family other than that of addr, if it has one.
+func (m *Machine) otherFamilyPrivateAddress(addr network.Address) (network.Address, bool) {
+	var otherType network.AddressType
+	switch addr.Type {
+	case network.IPv4Address:
+		otherType = network.IPv6Address
+	case network.IPv6Address:
+		otherType = network.IPv4Address
+	default:
+		return network.Address{}, false
+	}
+	var candidates []network.Address
+	for _, candidate := range m.Addresses() {
+		if candidate.Type == otherType {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return network.SelectInternalAddress(candidates, false)
+}
@@ -1917,6 +1917,68 @@ func (s *MachineSuite) TestPrivateAddress(c *gc.C) {
 	c.Assert(addr.Value, gc.Equals, "10.0.0.1")
 }
 
+func (s *MachineSuite) TestPublicAddressPreferIPv6(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{"prefer-ipv6": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetProviderAddresses(network.NewAddress("8.8.8.8"))
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err := machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "8.8.8.8")
+
+	err = machine.SetProviderAddresses(
+		network.NewAddress("8.8.8.8"),
+		network.NewAddress("2001:4860:4860::8888"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err = machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "2001:4860:4860::8888")
+}
+
+func (s *MachineSuite) TestPublicAddressPreferIPv6Changed(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	addrs := []network.Address{
+		network.NewAddress("8.8.8.8"),
+		network.NewAddress("2001:4860:4860::8888"),
+	}
+	err = machine.SetProviderAddresses(addrs...)
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err := machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "8.8.8.8")
+
+	// Changing the model config is seen the next time the
+	// addresses are set.
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{"prefer-ipv6": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(addrs[1], addrs[0])
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err = machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "2001:4860:4860::8888")
+}
+
+func (s *MachineSuite) TestPrivateAddressPreferIPv6(c *gc.C) {
+	err := s.IAASModel.UpdateModelConfig(map[string]interface{}{"prefer-ipv6": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetMachineAddresses(
+		network.NewAddress("10.0.0.1"),
+		network.NewAddress("fc00::1"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	addr, err := machine.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "fc00::1")
+}
+
 func (s *MachineSuite) TestPublicAddressBetterMatch(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,355 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/status"
+)
+
+// Replace adds a replacement for the machine, with the same series,
+// jobs, constraints and placement, and moves the machine's units to
+// the replacement. The units keep their names; their agents will be
+// deployed afresh on the replacement, which causes their install hooks
+// to be run again.
+//
+// A machine with no units cannot be replaced: there is nothing to
+// move, and a machine that has already been replaced must not be
+// replaced again if destroying it fails.
+//
+// The units' storage must be detachable. Storage is attached to the
+// replacement once it has been detached from this machine, which
+// happens when this machine is destroyed; the caller is responsible
+// for destroying the machine after it has been replaced.
+func (m *Machine) Replace() (_ *Machine, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot replace machine %s", m)
+	var (
+		replacement *machineDoc
+		units       []*Unit
+	)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		var ops []txn.Op
+		var err error
+		replacement, units, ops, err = m.replaceOps()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return ops, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	m.doc.Principals = nil
+
+	// The units' agents have not yet been deployed to the
+	// replacement machine, so reset their status to reflect
+	// that, as when the units were first added.
+	now := m.st.clock().Now()
+	for _, u := range units {
+		if err := setStatus(m.st.db(), setStatusParams{
+			badge:     "agent",
+			globalKey: u.globalAgentKey(),
+			status:    status.Allocating,
+			updated:   &now,
+		}); err != nil {
+			logger.Warningf("resetting agent status of unit %q: %v", u.Name(), err)
+		}
+		if err := setStatus(m.st.db(), setStatusParams{
+			badge:     "unit",
+			globalKey: u.globalKey(),
+			status:    status.Waiting,
+			message:   status.MessageWaitForMachine,
+			updated:   &now,
+		}); err != nil {
+			logger.Warningf("resetting workload status of unit %q: %v", u.Name(), err)
+		}
+	}
+	return newMachine(m.st, replacement), nil
+}
+
+// replaceOps returns the document for the replacement machine, the
+// units that will be moved to it (principals and subordinates), and
+// the txn.Ops to add the replacement and move the units.
+func (m *Machine) replaceOps() (*machineDoc, []*Unit, []txn.Op, error) {
+	if m.Life() != Alive {
+		return nil, nil, nil, machineNotAliveErr
+	}
+	if m.IsManager() {
+		return nil, nil, nil, managerMachineError
+	}
+	if m.IsContainer() {
+		return nil, nil, nil, errors.NotSupportedf("replacing containers")
+	}
+	manual, err := m.IsManual()
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	if manual {
+		return nil, nil, nil, errors.NotSupportedf("replacing manually provisioned machines")
+	}
+	containers, err := m.Containers()
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	if len(containers) > 0 {
+		return nil, nil, nil, errors.Errorf("machine hosts containers %s", strings.Join(containers, ", "))
+	}
+	if len(m.doc.Principals) == 0 {
+		return nil, nil, nil, errors.New("machine has no units")
+	}
+	cons, err := m.Constraints()
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	im, err := m.st.IAASModel()
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+
+	var units []*Unit
+	var hasStorage bool
+	for _, name := range m.doc.Principals {
+		principal, err := m.st.Unit(name)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		units = append(units, principal)
+		for _, subName := range principal.SubordinateNames() {
+			sub, err := m.st.Unit(subName)
+			if err != nil {
+				return nil, nil, nil, errors.Trace(err)
+			}
+			units = append(units, sub)
+		}
+	}
+	for _, u := range units {
+		if u.Life() != Alive {
+			return nil, nil, nil, errors.Errorf("unit %q is not alive", u.Name())
+		}
+		attached, err := checkUnitStorageDetachable(im, u)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		hasStorage = hasStorage || attached
+	}
+
+	mdoc, ops, err := m.st.addMachineOps(MachineTemplate{
+		Series:      m.doc.Series,
+		Constraints: cons,
+		Jobs:        m.doc.Jobs,
+		Placement:   m.doc.Placement,
+		Dirty:       true,
+		principals:  m.doc.Principals,
+	})
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: append(isAliveDoc, bson.DocElem{"principals", m.doc.Principals}),
+		Update: bson.D{{"$set", bson.D{{"principals", []string{}}}}},
+	})
+	for _, u := range units {
+		if !u.IsPrincipal() {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:  unitsC,
+			Id: u.doc.DocID,
+			Assert: append(isAliveDoc, bson.DocElem{
+				"machineid", m.doc.Id,
+			}),
+			Update: bson.D{{"$set", bson.D{{"machineid", mdoc.Id}}}},
+		})
+	}
+	if hasStorage {
+		ops = append(ops, newCleanupOp(cleanupReplacementMachineStorage, mdoc.Id))
+	}
+	return mdoc, units, ops, nil
+}
+
+// checkUnitStorageDetachable returns an error if any of the storage
+// attached to the unit cannot be detached from its machine, and
+// reports whether the unit has any storage attached.
+func checkUnitStorageDetachable(im *IAASModel, u *Unit) (bool, error) {
+	attachments, err := im.UnitStorageAttachments(u.UnitTag())
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, a := range attachments {
+		si, err := im.storageInstance(a.StorageInstance())
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		detachable := true
+		switch si.Kind() {
+		case StorageKindBlock:
+			v, err := im.storageInstanceVolume(si.StorageTag())
+			if err == nil {
+				detachable = v.Detachable()
+			} else if !errors.IsNotFound(err) {
+				return false, errors.Trace(err)
+			}
+		case StorageKindFilesystem:
+			f, err := im.storageInstanceFilesystem(si.StorageTag())
+			if err == nil {
+				detachable = f.Detachable()
+			} else if !errors.IsNotFound(err) {
+				return false, errors.Trace(err)
+			}
+		}
+		if !detachable {
+			return false, errors.Errorf(
+				"%s attached to %s is non-detachable",
+				names.ReadableString(si.StorageTag()),
+				names.ReadableString(u.UnitTag()),
+			)
+		}
+	}
+	return len(attachments) > 0, nil
+}
+
+// cleanupReplacementMachineStorage attaches the storage of the units
+// moved to the replacement machine with the given ID, once it has been
+// detached from the machine that was replaced. Until then, it returns
+// an error satisfying errCleanupPending, so that it is retried.
+func (st *State) cleanupReplacementMachineStorage(machineId string) error {
+	m, err := st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if m.Life() != Alive {
+		return nil
+	}
+	im, err := st.IAASModel()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var pending []string
+	for _, name := range m.doc.Principals {
+		principal, err := st.Unit(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		units := []*Unit{principal}
+		for _, subName := range principal.SubordinateNames() {
+			sub, err := st.Unit(subName)
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			units = append(units, sub)
+		}
+		for _, u := range units {
+			attachments, err := im.UnitStorageAttachments(u.UnitTag())
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for _, a := range attachments {
+				if a.Life() != Alive {
+					continue
+				}
+				err := im.attachReplacementMachineStorage(m, u, a.StorageInstance())
+				if errors.Cause(err) == errCleanupPending {
+					pending = append(pending, names.ReadableString(a.StorageInstance()))
+					continue
+				} else if err != nil {
+					return errors.Annotatef(
+						err, "attaching %s to %s",
+						names.ReadableString(a.StorageInstance()),
+						names.ReadableString(m.Tag()),
+					)
+				}
+			}
+		}
+	}
+	if len(pending) > 0 {
+		return errors.Annotatef(errCleanupPending, "%s still attached", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// attachReplacementMachineStorage attaches the volume or filesystem
+// backing the storage instance to the replacement machine m, to which
+// the unit has been assigned, if it is not attached to any other
+// machine.
+func (im *IAASModel) attachReplacementMachineStorage(m *Machine, u *Unit, tag names.StorageTag) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		si, err := im.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, err := u.charm()
+		if err != nil {
+			return nil, errors.Annotate(err, "getting charm")
+		}
+		machines, err := im.storageInstanceMachines(si)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, machineTag := range machines {
+			if machineTag.Id() == m.Id() {
+				return nil, jujutxn.ErrNoOperations
+			}
+		}
+		charmMeta := ch.Meta()
+		if len(machines) > 0 && !charmMeta.Storage[si.StorageName()].Shared {
+			return nil, errCleanupPending
+		}
+		return unitAssignedMachineStorageOps(im, u.UnitTag(), charmMeta, u.Series(), si, u)
+	}
+	return im.mb.db().Run(buildTxn)
+}
+
+// storageInstanceMachines returns the tags of the machines to which
+// the volume or filesystem backing the storage instance are attached.
+func (im *IAASModel) storageInstanceMachines(si *storageInstance) ([]names.MachineTag, error) {
+	var machines []names.MachineTag
+	if f, err := im.storageInstanceFilesystem(si.StorageTag()); err == nil {
+		attachments, err := im.FilesystemAttachments(f.FilesystemTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, a := range attachments {
+			machines = append(machines, a.Machine())
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	if v, err := im.storageInstanceVolume(si.StorageTag()); err == nil {
+		attachments, err := im.VolumeAttachments(v.VolumeTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, a := range attachments {
+			machines = append(machines, a.Machine())
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	return machines, nil
+}
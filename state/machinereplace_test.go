@@ -0,0 +1,158 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+type MachineReplaceSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&MachineReplaceSuite{})
+
+func (s *MachineReplaceSuite) TestReplaceMovesUnits(c *gc.C) {
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("mem=4G"),
+		Placement:   "zone=z1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(status.StatusInfo{Status: status.Idle})
+	c.Assert(err, jc.ErrorIsNil)
+
+	replacement, err := machine.Replace()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement.Id(), gc.Not(gc.Equals), machine.Id())
+	c.Assert(replacement.Series(), gc.Equals, "quantal")
+	c.Assert(replacement.Jobs(), jc.DeepEquals, []state.MachineJob{state.JobHostUnits})
+	c.Assert(replacement.Placement(), gc.Equals, "zone=z1")
+	c.Assert(replacement.Principals(), jc.DeepEquals, []string{unit.Name()})
+	cons, err := replacement.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, replacement.Id())
+	agentStatus, err := unit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(agentStatus.Status, gc.Equals, status.Allocating)
+
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Principals(), gc.HasLen, 0)
+	c.Assert(machine.Life(), gc.Equals, state.Alive)
+
+	// The replaced machine has no units, so it cannot be replaced
+	// again, but it can be destroyed.
+	_, err = machine.Replace()
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: machine has no units`)
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachineReplaceSuite) TestReplaceReattachesStorage(c *gc.C) {
+	_, unit, storageTag := s.setupSingleStorage(c, "block", "persistent-block")
+	s.provisionStorageVolume(c, unit, storageTag)
+	machine := unitMachine(c, s.State, unit)
+	volume := s.storageInstanceVolume(c, storageTag)
+
+	replacement, err := machine.Replace()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume is not attached to the replacement until it has
+	// been detached from the replaced machine.
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.IAASModel.VolumeAttachment(replacement.MachineTag(), volume.VolumeTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	attachment := s.volumeAttachment(c, machine.MachineTag(), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Dying)
+	err = s.IAASModel.RemoveVolumeAttachment(machine.MachineTag(), volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	attachment = s.volumeAttachment(c, replacement.MachineTag(), volume.VolumeTag())
+	c.Assert(attachment.Life(), gc.Equals, state.Alive)
+	needsCleanup, err := s.State.NeedsCleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(needsCleanup, jc.IsFalse)
+}
+
+func (s *MachineReplaceSuite) TestReplaceNonDetachableStorage(c *gc.C) {
+	_, unit, _ := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := unitMachine(c, s.State, unit)
+
+	_, err = machine.Replace()
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: storage data/0 attached to unit storage-block/0 is non-detachable`)
+}
+
+func (s *MachineReplaceSuite) TestReplaceNotSupported(c *gc.C) {
+	controller, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = controller.Replace()
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: machine is required by the model`)
+
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), "lxd")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = container.Replace()
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 1/lxd/0: replacing containers not supported`)
+	_, err = host.Replace()
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 1: machine hosts containers 1/lxd/0`)
+
+	manual, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:     "quantal",
+		Jobs:       []state.MachineJob{state.JobHostUnits},
+		InstanceId: "manual:host",
+		Nonce:      "manual:nonce",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = manual.Replace()
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 2: replacing manually provisioned machines not supported`)
+}
+
+func (s *MachineReplaceSuite) TestReplaceMachineWithoutUnits(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.Replace()
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: machine has no units`)
+}
+
+func (s *MachineReplaceSuite) TestReplaceDyingMachine(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.Replace()
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: machine is not alive`)
+}
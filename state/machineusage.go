@@ -0,0 +1,136 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MachineUsageRetention is the length of time for which machine
+// usage samples are kept. Samples older than this are removed as
+// new samples are recorded.
+const MachineUsageRetention = 14 * 24 * time.Hour
+
+// MachineUsage holds a sample of the resources used by a machine.
+type MachineUsage struct {
+	// Time is the time at which the sample was taken.
+	Time time.Time
+
+	// CPU is the percentage of the machine's total CPU capacity
+	// that was in use since the previous sample, from 0 to 100.
+	CPU float64
+
+	// Cores is the number of CPU cores available to the machine.
+	Cores uint64
+
+	// MemoryUsed and MemoryTotal are the amount of memory in use,
+	// and available to the machine, in MiB.
+	MemoryUsed  uint64
+	MemoryTotal uint64
+
+	// DiskUsed and DiskTotal are the amount of space in use, and
+	// available, on the machine's root disk, in MiB.
+	DiskUsed  uint64
+	DiskTotal uint64
+}
+
+// CoresUsed returns the number of CPU cores that were in use
+// when the sample was taken.
+func (u MachineUsage) CoresUsed() float64 {
+	return u.CPU / 100 * float64(u.Cores)
+}
+
+type machineUsageDoc struct {
+	ModelUUID   string  `bson:"model-uuid"`
+	MachineId   string  `bson:"machine-id"`
+	Time        int64   `bson:"time"`
+	CPU         float64 `bson:"cpu"`
+	Cores       uint64  `bson:"cores"`
+	MemoryUsed  uint64  `bson:"memory-used"`
+	MemoryTotal uint64  `bson:"memory-total"`
+	DiskUsed    uint64  `bson:"disk-used"`
+	DiskTotal   uint64  `bson:"disk-total"`
+}
+
+func (doc machineUsageDoc) usage() MachineUsage {
+	return MachineUsage{
+		Time:        time.Unix(0, doc.Time).UTC(),
+		CPU:         doc.CPU,
+		Cores:       doc.Cores,
+		MemoryUsed:  doc.MemoryUsed,
+		MemoryTotal: doc.MemoryTotal,
+		DiskUsed:    doc.DiskUsed,
+		DiskTotal:   doc.DiskTotal,
+	}
+}
+
+// RecordMachineUsage records a sample of the resources used by the
+// machine with the given id. Samples for the machine that are older
+// than MachineUsageRetention are removed.
+func (st *State) RecordMachineUsage(machineId string, usage MachineUsage) error {
+	if _, err := st.Machine(machineId); err != nil {
+		return errors.Trace(err)
+	}
+	coll, closer := st.db().GetCollection(machineUsageC)
+	defer closer()
+
+	collW := coll.Writeable()
+	err := collW.Insert(&machineUsageDoc{
+		MachineId:   machineId,
+		Time:        usage.Time.UnixNano(),
+		CPU:         usage.CPU,
+		Cores:       usage.Cores,
+		MemoryUsed:  usage.MemoryUsed,
+		MemoryTotal: usage.MemoryTotal,
+		DiskUsed:    usage.DiskUsed,
+		DiskTotal:   usage.DiskTotal,
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot record usage for machine %q", machineId)
+	}
+
+	expiry := st.clock().Now().Add(-MachineUsageRetention)
+	if _, err := collW.RemoveAll(bson.D{
+		{"machine-id", machineId},
+		{"time", bson.D{{"$lt", expiry.UnixNano()}}},
+	}); err != nil {
+		return errors.Annotatef(err, "cannot prune usage for machine %q", machineId)
+	}
+	return nil
+}
+
+// MachineUsage returns the samples of resource usage recorded for
+// the machine with the given id since the specified time, oldest
+// first.
+func (st *State) MachineUsage(machineId string, since time.Time) ([]MachineUsage, error) {
+	coll, closer := st.db().GetCollection(machineUsageC)
+	defer closer()
+
+	var docs []machineUsageDoc
+	err := coll.Find(bson.D{
+		{"machine-id", machineId},
+		{"time", bson.D{{"$gte", since.UnixNano()}}},
+	}).Sort("time").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get usage for machine %q", machineId)
+	}
+	result := make([]MachineUsage, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.usage()
+	}
+	return result, nil
+}
+
+// eraseMachineUsage removes all usage samples recorded for the
+// machine with the given id.
+func eraseMachineUsage(mb modelBackend, machineId string) error {
+	coll, closer := mb.db().GetCollection(machineUsageC)
+	defer closer()
+
+	_, err := coll.Writeable().RemoveAll(bson.D{{"machine-id", machineId}})
+	return errors.Annotatef(err, "cannot erase usage for machine %q", machineId)
+}
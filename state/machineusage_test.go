@@ -0,0 +1,106 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type MachineUsageSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&MachineUsageSuite{})
+
+func (s *MachineUsageSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.machine = s.Factory.MakeMachine(c, nil)
+}
+
+func (s *MachineUsageSuite) usage(t time.Time, cpu float64) state.MachineUsage {
+	return state.MachineUsage{
+		Time:        t,
+		CPU:         cpu,
+		Cores:       4,
+		MemoryUsed:  1024,
+		MemoryTotal: 4096,
+		DiskUsed:    2048,
+		DiskTotal:   8192,
+	}
+}
+
+func (s *MachineUsageSuite) TestRecordAndGet(c *gc.C) {
+	now := s.Clock.Now().UTC()
+	first := s.usage(now.Add(-2*time.Hour), 10)
+	second := s.usage(now.Add(-time.Hour), 50)
+	third := s.usage(now, 90)
+	for _, usage := range []state.MachineUsage{second, first, third} {
+		err := s.State.RecordMachineUsage(s.machine.Id(), usage)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	usage, err := s.State.MachineUsage(s.machine.Id(), now.Add(-90*time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, []state.MachineUsage{second, third})
+	c.Assert(usage[0].CoresUsed(), gc.Equals, 2.0)
+}
+
+func (s *MachineUsageSuite) TestRecordMachineNotFound(c *gc.C) {
+	err := s.State.RecordMachineUsage("42", s.usage(s.Clock.Now(), 10))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MachineUsageSuite) TestRecordPrunesExpired(c *gc.C) {
+	now := s.Clock.Now().UTC()
+	expired := s.usage(now.Add(-state.MachineUsageRetention-time.Minute), 10)
+	err := s.State.RecordMachineUsage(s.machine.Id(), expired)
+	c.Assert(err, jc.ErrorIsNil)
+	current := s.usage(now, 20)
+	err = s.State.RecordMachineUsage(s.machine.Id(), current)
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.State.MachineUsage(s.machine.Id(), time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, []state.MachineUsage{current})
+}
+
+func (s *MachineUsageSuite) TestModelIsolation(c *gc.C) {
+	now := s.Clock.Now().UTC()
+	usageA := s.usage(now, 10)
+	err := s.State.RecordMachineUsage(s.machine.Id(), usageA)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stB := s.Factory.MakeModel(c, nil)
+	defer stB.Close()
+	machineB := factory.NewFactory(stB).MakeMachine(c, nil)
+	usageB := s.usage(now, 20)
+	err = stB.RecordMachineUsage(machineB.Id(), usageB)
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.State.MachineUsage(s.machine.Id(), time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, []state.MachineUsage{usageA})
+}
+
+func (s *MachineUsageSuite) TestRemoveMachineErasesUsage(c *gc.C) {
+	err := s.State.RecordMachineUsage(s.machine.Id(), s.usage(s.Clock.Now().UTC(), 10))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.State.MachineUsage(s.machine.Id(), time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, gc.HasLen, 0)
+}
@@ -1506,6 +1506,17 @@ func (e *exporter) constraintsArgs(globalKey string) (description.ConstraintsArg
 		Tags:         optionalStringSlice("tags"),
 		VirtType:     optionalString("virttype"),
 	}
+	if zones := optionalStringSlice("zones"); len(zones) > 0 {
+		// The model description format does not yet support
+		// zones constraints, so the migration prechecks refuse
+		// to migrate models that use them.
+		e.logger.Warningf("not exporting zones constraint %q for %s", zones, globalKey)
+	}
+	if doc["interruptible"] != nil || doc["maxprice"] != nil {
+		// Nor does it support interruptible capacity, which
+		// the prechecks also refuse.
+		e.logger.Warningf("not exporting interruptible or max-price constraints for %s", globalKey)
+	}
 	if optionalErr != nil {
 		return description.ConstraintsArgs{}, errors.Trace(optionalErr)
 	}
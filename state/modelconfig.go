@@ -4,8 +4,12 @@
 package state
 
 import (
+	"sync"
+
 	"github.com/juju/errors"
 	"github.com/juju/schema"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
@@ -32,6 +36,51 @@ func getModelConfig(db Database) (*config.Config, error) {
 	return config.New(config.NoDefaults, modelSettings.Map())
 }
 
+// modelConfigCache holds the most recently parsed model config,
+// together with the version of the settings it was parsed from.
+// It is used by operations such as setting machine addresses, which
+// are run often and need only a few attributes of the model config.
+type modelConfigCache struct {
+	mu      sync.Mutex
+	version int64
+	config  *config.Config
+}
+
+// cachedModelConfig returns the model config, parsing it afresh only
+// when the model settings have changed since it was last parsed.
+func (st *State) cachedModelConfig() (*config.Config, error) {
+	settings, closer := st.db().GetCollection(settingsC)
+	defer closer()
+
+	var doc struct {
+		Version int64 `bson:"version"`
+	}
+	err := settings.FindId(modelGlobalKey).Select(bson.D{{"version", 1}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("model settings")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cache := &st.modelConfigCache
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.config != nil && cache.version == doc.Version {
+		return cache.config, nil
+	}
+	modelSettings, err := readSettings(st.db(), settingsC, modelGlobalKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := config.New(config.NoDefaults, modelSettings.Map())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cache.version = modelSettings.version
+	cache.config = cfg
+	return cfg, nil
+}
+
 // checkModelConfig returns an error if the config is definitely invalid.
 func checkModelConfig(cfg *config.Config) error {
 	allAttrs := cfg.AllAttrs()
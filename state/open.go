@@ -47,6 +47,11 @@ type OpenParams struct {
 	// InitDatabaseFunc, if non-nil, is a function that will be called
 	// just after the state database is opened.
 	InitDatabaseFunc InitDatabaseFunc
+
+	// SecretsKey is the key that charm secrets are encrypted with.
+	// It is held in the controller agents' configuration, never in
+	// the database. If it is empty, secrets cannot be used.
+	SecretsKey []byte
 }
 
 // Validate validates the OpenParams.
@@ -63,6 +68,9 @@ func (p OpenParams) Validate() error {
 	if p.MongoSession == nil {
 		return errors.NotValidf("nil MongoSession")
 	}
+	if len(p.SecretsKey) != 0 && len(p.SecretsKey) != secretsKeySize {
+		return errors.NotValidf("%d byte SecretsKey", len(p.SecretsKey))
+	}
 	return nil
 }
 
@@ -90,6 +98,7 @@ func OpenController(args OpenParams) (*Controller, error) {
 		session:                session,
 		newPolicy:              args.NewPolicy,
 		runTransactionObserver: args.RunTransactionObserver,
+		secretsKey:             args.SecretsKey,
 	}, nil
 }
 
@@ -111,6 +120,7 @@ func Open(args OpenParams) (*State, error) {
 		args.NewPolicy,
 		args.Clock,
 		args.RunTransactionObserver,
+		args.SecretsKey,
 	)
 	if err != nil {
 		session.Close()
@@ -139,8 +149,9 @@ func open(
 	newPolicy NewPolicyFunc,
 	clock clock.Clock,
 	runTransactionObserver RunTransactionObserverFunc,
+	secretsKey []byte,
 ) (*State, error) {
-	st, err := newState(controllerModelTag, controllerModelTag, session, newPolicy, clock, runTransactionObserver)
+	st, err := newState(controllerModelTag, controllerModelTag, session, newPolicy, clock, runTransactionObserver, secretsKey)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -168,6 +179,7 @@ func newState(
 	newPolicy NewPolicyFunc,
 	clock clock.Clock,
 	runTransactionObserver RunTransactionObserverFunc,
+	secretsKey []byte,
 ) (_ *State, err error) {
 
 	defer func() {
@@ -192,6 +204,7 @@ func newState(
 		database:               db,
 		newPolicy:              newPolicy,
 		runTransactionObserver: runTransactionObserver,
+		secretsKey:             secretsKey,
 	}
 	if newPolicy != nil {
 		st.policy = newPolicy(st)
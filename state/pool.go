@@ -202,6 +202,7 @@ func (p *StatePool) openState(modelUUID string) (*State, error) {
 		modelTag, p.systemState.controllerModelTag,
 		session, p.systemState.newPolicy, p.systemState.stateClock,
 		p.systemState.runTransactionObserver,
+		p.systemState.secretsKey,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
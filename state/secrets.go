@@ -0,0 +1,503 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// secretsKeySize is the size, in bytes, of the AES-256 key that secret
+// values are encrypted with.
+const secretsKeySize = 32
+
+// minRedactedSecretLength is the length below which secret values are
+// not redacted from status messages, so that trivial values such as
+// "1" or "yes" do not mangle them.
+const minRedactedSecretLength = 4
+
+// RedactedSecret replaces secret values redacted from text.
+const RedactedSecret = "[REDACTED]"
+
+var validSecretName = regexp.MustCompile("^[a-z][a-z0-9]*(-[a-z0-9]+)*$")
+
+// IsValidSecretName reports whether name is a valid secret name.
+func IsValidSecretName(name string) bool {
+	return validSecretName.MatchString(name)
+}
+
+// secretDoc records a secret owned by an application. The secret's
+// values are held encrypted, so that they are never stored in plain
+// text and never appear in settings documents or model exports.
+type secretDoc struct {
+	DocID       string `bson:"_id"`
+	ModelUUID   string `bson:"model-uuid"`
+	Application string `bson:"application"`
+	Name        string `bson:"name"`
+	Revision    int    `bson:"revision"`
+	Data        []byte `bson:"data"`
+
+	// RotateInterval holds how often, in nanoseconds, the secret
+	// should be rotated, or zero if it is not rotated.
+	RotateInterval int64     `bson:"rotate-interval"`
+	NextRotateTime time.Time `bson:"next-rotate-time"`
+
+	// SharedWith holds the names of the related applications the
+	// secret is shared with.
+	SharedWith []string `bson:"shared-with"`
+}
+
+// Secret represents a secret owned by an application.
+type Secret struct {
+	st  *State
+	doc secretDoc
+}
+
+// Name returns the name of the secret.
+func (s *Secret) Name() string {
+	return s.doc.Name
+}
+
+// Application returns the name of the application that owns the
+// secret.
+func (s *Secret) Application() string {
+	return s.doc.Application
+}
+
+// Revision returns the revision of the secret, which increments
+// each time its values are set.
+func (s *Secret) Revision() int {
+	return s.doc.Revision
+}
+
+// RotateInterval returns how often the secret should be rotated,
+// or zero if it is not rotated.
+func (s *Secret) RotateInterval() time.Duration {
+	return time.Duration(s.doc.RotateInterval)
+}
+
+// NextRotateTime returns when the secret should next be rotated,
+// and whether it is rotated at all.
+func (s *Secret) NextRotateTime() (time.Time, bool) {
+	return s.doc.NextRotateTime, s.doc.RotateInterval > 0
+}
+
+// SharedWith returns the names of the applications the secret is
+// shared with.
+func (s *Secret) SharedWith() []string {
+	return s.doc.SharedWith
+}
+
+// Values returns the decrypted values of the secret.
+func (s *Secret) Values() (map[string]string, error) {
+	key, err := s.st.requireSecretsKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := decryptSecret(key, s.doc.Data, s.doc.DocID)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot decrypt secret %q", s.doc.Name)
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, errors.Annotatef(err, "cannot unmarshal secret %q", s.doc.Name)
+	}
+	return values, nil
+}
+
+// ReadableBy reports whether the secret may be read by units of the
+// named application: the application that owns it, or a related
+// application that it has been shared with.
+func (s *Secret) ReadableBy(applicationName string) (bool, error) {
+	if applicationName == s.doc.Application {
+		return true, nil
+	}
+	shared := false
+	for _, name := range s.doc.SharedWith {
+		if name == applicationName {
+			shared = true
+			break
+		}
+	}
+	if !shared {
+		return false, nil
+	}
+	related, err := applicationsRelated(s.st, s.doc.Application, applicationName)
+	return related, errors.Trace(err)
+}
+
+func applicationsRelated(st *State, appName, otherAppName string) (bool, error) {
+	relations, err := applicationRelations(st, appName)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, rel := range relations {
+		if _, err := rel.Endpoint(otherAppName); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func secretKey(appName, name string) string {
+	return appName + "/" + name
+}
+
+// Secret returns the named secret owned by the application.
+func (a *Application) Secret(name string) (*Secret, error) {
+	secrets, closer := a.st.db().GetCollection(secretsC)
+	defer closer()
+
+	var doc secretDoc
+	err := secrets.FindId(secretKey(a.doc.Name, name)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("secret %q of application %q", name, a.doc.Name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get secret %q", name)
+	}
+	return &Secret{st: a.st, doc: doc}, nil
+}
+
+// Secrets returns the secrets owned by the application, sorted by
+// name.
+func (a *Application) Secrets() ([]*Secret, error) {
+	secrets, closer := a.st.db().GetCollection(secretsC)
+	defer closer()
+
+	var docs []secretDoc
+	if err := secrets.Find(bson.D{{"application", a.doc.Name}}).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get secrets of application %q", a.doc.Name)
+	}
+	result := make([]*Secret, len(docs))
+	for i, doc := range docs {
+		result[i] = &Secret{st: a.st, doc: doc}
+	}
+	return result, nil
+}
+
+// SetSecret creates or updates the named secret owned by the
+// application, replacing its values. If rotateInterval is non-zero,
+// the secret is due to be rotated after that interval.
+func (a *Application) SetSecret(name string, values map[string]string, rotateInterval time.Duration) error {
+	if !IsValidSecretName(name) {
+		return errors.NotValidf("secret name %q", name)
+	}
+	if len(values) == 0 {
+		return errors.NotValidf("secret %q with no values", name)
+	}
+	if rotateInterval < 0 {
+		return errors.NotValidf("negative rotate interval %v", rotateInterval)
+	}
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key, err := a.st.requireSecretsKey()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The secret's document ID is authenticated with its values, so
+	// that they cannot be copied to another secret's document.
+	data, err := encryptSecret(key, plaintext, a.st.docID(secretKey(a.doc.Name, name)))
+	if err != nil {
+		return errors.Annotatef(err, "cannot encrypt secret %q", name)
+	}
+	var nextRotateTime time.Time
+	if rotateInterval > 0 {
+		nextRotateTime = a.st.nowToTheSecond().Add(rotateInterval)
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, errors.Errorf("application %q is not alive", a.doc.Name)
+		}
+		ops := []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		existing, err := a.Secret(name)
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      secretsC,
+				Id:     secretKey(a.doc.Name, name),
+				Assert: txn.DocMissing,
+				Insert: &secretDoc{
+					Application:    a.doc.Name,
+					Name:           name,
+					Revision:       1,
+					Data:           data,
+					RotateInterval: int64(rotateInterval),
+					NextRotateTime: nextRotateTime,
+				},
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      secretsC,
+			Id:     secretKey(a.doc.Name, name),
+			Assert: bson.D{{"revision", existing.doc.Revision}},
+			Update: bson.D{{"$set", bson.D{
+				{"revision", existing.doc.Revision + 1},
+				{"data", data},
+				{"rotate-interval", int64(rotateInterval)},
+				{"next-rotate-time", nextRotateTime},
+			}}},
+		}), nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set secret %q", name)
+	}
+	return nil
+}
+
+// ShareSecret shares the named secret with a related application, so
+// that its units may read the secret.
+func (a *Application) ShareSecret(name, applicationName string) error {
+	if applicationName == a.doc.Name {
+		return errors.NotValidf("sharing secret %q with its own application", name)
+	}
+	related, err := applicationsRelated(a.st, a.doc.Name, applicationName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !related {
+		return errors.Errorf("cannot share secret %q: application %q is not related to %q", name, applicationName, a.doc.Name)
+	}
+	return a.updateSecret(name, "$addToSet", bson.D{{"shared-with", applicationName}})
+}
+
+// UnshareSecret stops sharing the named secret with an application.
+func (a *Application) UnshareSecret(name, applicationName string) error {
+	return a.updateSecret(name, "$pull", bson.D{{"shared-with", applicationName}})
+}
+
+// SecretRotated records that the named secret has been rotated, so
+// that it is next due to be rotated after its rotate interval.
+func (a *Application) SecretRotated(name string) error {
+	secret, err := a.Secret(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if secret.doc.RotateInterval == 0 {
+		return nil
+	}
+	next := a.st.nowToTheSecond().Add(secret.RotateInterval())
+	return a.updateSecret(name, "$set", bson.D{{"next-rotate-time", next}})
+}
+
+func (a *Application) updateSecret(name, operator string, update bson.D) error {
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     secretKey(a.doc.Name, name),
+		Assert: txn.DocExists,
+		Update: bson.D{{operator, update}},
+	}}
+	err := a.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("secret %q of application %q", name, a.doc.Name)
+	}
+	return errors.Annotatef(err, "cannot update secret %q", name)
+}
+
+// RemoveSecret removes the named secret owned by the application.
+func (a *Application) RemoveSecret(name string) error {
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     secretKey(a.doc.Name, name),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := a.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("secret %q of application %q", name, a.doc.Name)
+	}
+	return errors.Annotatef(err, "cannot remove secret %q", name)
+}
+
+// SecretRedactor removes the values of applications' secrets from
+// text. It remembers the values of the secrets it has decrypted, and
+// decrypts a secret again only when its revision changes.
+type SecretRedactor struct {
+	st *State
+
+	mu      sync.Mutex
+	secrets map[string]redactedSecret
+}
+
+type redactedSecret struct {
+	revision int
+	values   []string
+}
+
+// NewSecretRedactor returns a SecretRedactor for the applications in
+// the model.
+func (st *State) NewSecretRedactor() *SecretRedactor {
+	return &SecretRedactor{
+		st:      st,
+		secrets: make(map[string]redactedSecret),
+	}
+}
+
+// Values returns the values of the named application's secrets that
+// should be redacted, longest first so that a value containing
+// another is redacted whole.
+func (r *SecretRedactor) Values(appName string) ([]string, error) {
+	secrets, closer := r.st.db().GetCollection(secretsC)
+	defer closer()
+
+	var docs []secretDoc
+	if err := secrets.Find(bson.D{{"application", appName}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get secrets of application %q", appName)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var values []string
+	for _, doc := range docs {
+		cached, ok := r.secrets[doc.DocID]
+		if !ok || cached.revision != doc.Revision {
+			secret := &Secret{st: r.st, doc: doc}
+			secretValues, err := secret.Values()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			cached = redactedSecret{revision: doc.Revision}
+			for _, value := range secretValues {
+				if len(value) >= minRedactedSecretLength {
+					cached.values = append(cached.values, value)
+				}
+			}
+			r.secrets[doc.DocID] = cached
+		}
+		values = append(values, cached.values...)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	return values, nil
+}
+
+// Redact returns text with the values of the named application's
+// secrets replaced, so that charms cannot reveal them in status
+// messages.
+func (r *SecretRedactor) Redact(appName, text string) (string, error) {
+	if text == "" {
+		return text, nil
+	}
+	values, err := r.Values(appName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return RedactSecretValues(values, text), nil
+}
+
+// RedactSecretValues returns text with each of the given values
+// replaced, in order.
+func RedactSecretValues(values []string, text string) string {
+	for _, value := range values {
+		text = strings.Replace(text, value, RedactedSecret, -1)
+	}
+	return text
+}
+
+// removeApplicationSecretsOps returns the operations required to
+// remove the secrets owned by the named application.
+func removeApplicationSecretsOps(st *State, appName string) ([]txn.Op, error) {
+	secrets, closer := st.db().GetCollection(secretsC)
+	defer closer()
+
+	var docs []struct {
+		DocID string `bson:"_id"`
+	}
+	if err := secrets.Find(bson.D{{"application", appName}}).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      secretsC,
+			Id:     doc.DocID,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
+// SecretsKey returns the key that secret values are encrypted with,
+// so that it can be given to other controller agents.
+func (st *State) SecretsKey() []byte {
+	return st.secretsKey
+}
+
+// requireSecretsKey returns the key that secret values are encrypted
+// with, or an error if the controller has none.
+func (st *State) requireSecretsKey() ([]byte, error) {
+	if len(st.secretsKey) == 0 {
+		return nil, errors.NotProvisionedf("secrets key")
+	}
+	return st.secretsKey, nil
+}
+
+// GenerateSecretsKey returns a new random key for encrypting secret
+// values with.
+func GenerateSecretsKey() ([]byte, error) {
+	key := make([]byte, secretsKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return key, nil
+}
+
+func encryptSecret(key, plaintext []byte, docID string) ([]byte, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, []byte(docID)), nil
+}
+
+func decryptSecret(key, data []byte, docID string) ([]byte, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("secret data too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, []byte(docID))
+}
+
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cipher.NewGCM(block)
+}
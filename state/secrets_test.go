@@ -0,0 +1,262 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+)
+
+type SecretsSuite struct {
+	ConnSuite
+	mysql     *state.Application
+	wordpress *state.Application
+}
+
+var _ = gc.Suite(&SecretsSuite{})
+
+func (s *SecretsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.wordpress = s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *SecretsSuite) relate(c *gc.C) *state.Relation {
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	return rel
+}
+
+func (s *SecretsSuite) TestSetSecret(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	secret, err := s.mysql.Secret("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Name(), gc.Equals, "root-password")
+	c.Assert(secret.Application(), gc.Equals, "mysql")
+	c.Assert(secret.Revision(), gc.Equals, 1)
+	_, rotated := secret.NextRotateTime()
+	c.Assert(rotated, jc.IsFalse)
+	values, err := secret.Values()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{"password": "sekrit"})
+
+	err = s.mysql.SetSecret("root-password", map[string]string{"password": "changed"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	secret, err = s.mysql.Secret("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 2)
+	values, err = secret.Values()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(values, jc.DeepEquals, map[string]string{"password": "changed"})
+}
+
+func (s *SecretsSuite) TestSecretEncryptedAtRest(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var doc bson.M
+	err = s.Session.DB("juju").C("secrets").Find(nil).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	raw, err := bson.Marshal(doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Contains(string(raw), "sekrit"), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestSecretBoundToDocument(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetSecret("salt", map[string]string{"salt": "pepper"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Values copied into another secret's document cannot be
+	// decrypted there.
+	secrets := s.Session.DB("juju").C("secrets")
+	var doc struct {
+		Data []byte `bson:"data"`
+	}
+	err = secrets.Find(bson.D{{"application", "mysql"}}).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	err = secrets.Update(bson.D{{"application", "wordpress"}}, bson.D{{"$set", bson.D{{"data", doc.Data}}}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	secret, err := s.wordpress.Secret("salt")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = secret.Values()
+	c.Assert(err, gc.ErrorMatches, `cannot decrypt secret "salt": .*`)
+}
+
+func (s *SecretsSuite) TestSecretsKeyNotInDatabase(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	count, err := s.Session.DB("juju").C("controllers").FindId("secretsKey").Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+}
+
+func (s *SecretsSuite) TestNoSecretsKey(c *gc.C) {
+	state.SetSecretsKey(s.State, nil)
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, gc.ErrorMatches, "secrets key not provisioned")
+}
+
+func (s *SecretsSuite) TestSetSecretInvalid(c *gc.C) {
+	err := s.mysql.SetSecret("Root Password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, gc.ErrorMatches, `secret name "Root Password" not valid`)
+	err = s.mysql.SetSecret("root-password", nil, 0)
+	c.Assert(err, gc.ErrorMatches, `secret "root-password" with no values not valid`)
+}
+
+func (s *SecretsSuite) TestSecretNotFound(c *gc.C) {
+	_, err := s.mysql.Secret("root-password")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestSecrets(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetSecret("admin-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetSecret("salt", map[string]string{"salt": "pepper"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	secrets, err := s.mysql.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 2)
+	c.Assert(secrets[0].Name(), gc.Equals, "admin-password")
+	c.Assert(secrets[1].Name(), gc.Equals, "root-password")
+}
+
+func (s *SecretsSuite) TestShareSecret(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.ShareSecret("root-password", "wordpress")
+	c.Assert(err, gc.ErrorMatches, `cannot share secret "root-password": application "wordpress" is not related to "mysql"`)
+
+	rel := s.relate(c)
+	err = s.mysql.ShareSecret("root-password", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	secret, err := s.mysql.Secret("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.SharedWith(), jc.DeepEquals, []string{"wordpress"})
+
+	readable, err := secret.ReadableBy("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readable, jc.IsTrue)
+	readable, err = secret.ReadableBy("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readable, jc.IsTrue)
+
+	// Once the applications are no longer related, the secret
+	// cannot be read even though it is still shared.
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	readable, err = secret.ReadableBy("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readable, jc.IsFalse)
+
+	err = s.mysql.UnshareSecret("root-password", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	secret, err = s.mysql.Secret("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.SharedWith(), gc.HasLen, 0)
+}
+
+func (s *SecretsSuite) TestShareSecretNotFound(c *gc.C) {
+	s.relate(c)
+	err := s.mysql.ShareSecret("root-password", "wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestRemoveSecret(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.RemoveSecret("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.mysql.Secret("root-password")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.mysql.RemoveSecret("root-password")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestSecretsRemovedWithApplication(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	count, err := s.Session.DB("juju").C("secrets").Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+}
+
+func (s *SecretsSuite) TestRedactSecrets(c *gc.C) {
+	err := s.mysql.SetSecret("root-password", map[string]string{
+		"password": "sekrit",
+		"flag":     "1",
+	}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	redactor := s.State.NewSecretRedactor()
+	text, err := redactor.Redact("mysql", "logging in with sekrit as user 1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(text, gc.Equals, "logging in with [REDACTED] as user 1")
+
+	// Other applications' secrets are not known to them.
+	text, err = redactor.Redact("wordpress", "logging in with sekrit")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(text, gc.Equals, "logging in with sekrit")
+
+	// Changed values are redacted once they are set.
+	err = s.mysql.SetSecret("root-password", map[string]string{"password": "changed"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	text, err = redactor.Redact("mysql", "logging in with changed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(text, gc.Equals, "logging in with [REDACTED]")
+}
+
+func (s *SecretsSuite) TestWatchSecretRotations(c *gc.C) {
+	w := s.mysql.WatchSecretRotations()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	err := s.mysql.SetSecret("root-password", map[string]string{"password": "sekrit"}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetSecret("admin-password", map[string]string{"password": "sekrit"}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Once the rotate interval has passed, the secret is due.
+	err = s.Clock.WaitAdvance(time.Hour+time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange("root-password")
+	wc.AssertNoChange()
+
+	// Once rotated, it is no longer due.
+	err = s.mysql.SecretRotated("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange()
+	wc.AssertNoChange()
+	secret, err := s.mysql.Secret("root-password")
+	c.Assert(err, jc.ErrorIsNil)
+	next, rotated := secret.NextRotateTime()
+	c.Assert(rotated, jc.IsTrue)
+	c.Assert(next.After(s.Clock.Now()), jc.IsTrue)
+}
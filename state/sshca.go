@@ -0,0 +1,73 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/ssh"
+	cryptossh "golang.org/x/crypto/ssh"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// SSHCAPrivateKey returns the private key, in PEM format, of the
+// controller's SSH certificate authority, which signs the short-lived
+// user certificates used by "juju ssh" and the machines' host keys.
+// Like the controller's CA private key, it is held in the state
+// serving info, and so only reaches controller agents. The key is
+// generated the first time it is asked for.
+func (st *State) SSHCAPrivateKey() (string, error) {
+	privateKey, err := st.sshCAPrivateKey()
+	if err != nil || privateKey != "" {
+		return privateKey, errors.Trace(err)
+	}
+	privateKey, _, err = ssh.GenerateKey("juju-ssh-ca")
+	if err != nil {
+		return "", errors.Annotate(err, "cannot generate SSH CA key")
+	}
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     stateServingInfoKey,
+		Assert: bson.D{{"sshcaprivatekey", bson.D{{"$exists", false}}}},
+		Update: bson.D{{"$set", bson.D{{"sshcaprivatekey", privateKey}}}},
+	}}
+	if err := st.db().RunTransaction(ops); err != nil && err != txn.ErrAborted {
+		return "", errors.Annotate(err, "cannot store SSH CA key")
+	}
+	// If another controller stored a key first, use that one.
+	privateKey, err = st.sshCAPrivateKey()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if privateKey == "" {
+		return "", errors.NotFoundf("SSH certificate authority")
+	}
+	return privateKey, nil
+}
+
+// SSHCAPublicKey returns the public key, in authorized_keys format,
+// of the controller's SSH certificate authority. Machines are
+// configured to accept user certificates signed by it.
+func (st *State) SSHCAPublicKey() (string, error) {
+	privateKey, err := st.SSHCAPrivateKey()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	signer, err := cryptossh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return "", errors.Annotate(err, "cannot parse SSH CA key")
+	}
+	return string(cryptossh.MarshalAuthorizedKey(signer.PublicKey())), nil
+}
+
+func (st *State) sshCAPrivateKey() (string, error) {
+	controllers, closer := st.db().GetCollection(controllersC)
+	defer closer()
+	var info StateServingInfo
+	err := controllers.FindId(stateServingInfoKey).One(&info)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot get SSH certificate authority")
+	}
+	return info.SSHCAPrivateKey, nil
+}
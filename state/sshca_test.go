@@ -0,0 +1,73 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/crypto/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type SSHCASuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SSHCASuite{})
+
+func (s *SSHCASuite) TestSSHCAGeneratedOnce(c *gc.C) {
+	private, err := s.State.SSHCAPrivateKey()
+	c.Assert(err, jc.ErrorIsNil)
+	public, err := s.State.SSHCAPublicKey()
+	c.Assert(err, jc.ErrorIsNil)
+
+	signer, err := ssh.ParsePrivateKey([]byte(private))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(
+		strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))),
+		gc.Equals,
+		strings.Fields(public)[0]+" "+strings.Fields(public)[1],
+	)
+
+	again, err := s.State.SSHCAPrivateKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, gc.Equals, private)
+}
+
+func (s *SSHCASuite) TestSSHCASharedByModels(c *gc.C) {
+	public, err := s.State.SSHCAPublicKey()
+	c.Assert(err, jc.ErrorIsNil)
+
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	otherPublic, err := st.SSHCAPublicKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(otherPublic, gc.Equals, public)
+}
+
+func (s *SSHCASuite) TestSSHCAKeptInStateServingInfo(c *gc.C) {
+	private, err := s.State.SSHCAPrivateKey()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Setting serving info without the SSH CA key, as agents that
+	// predate it do, leaves the key alone.
+	err = s.State.SetStateServingInfo(state.StateServingInfo{
+		APIPort:      69,
+		StatePort:    80,
+		Cert:         "Some cert",
+		PrivateKey:   "Some key",
+		SharedSecret: "Some Keyfile",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.State.StateServingInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.SSHCAPrivateKey, gc.Equals, private)
+	again, err := s.State.SSHCAPrivateKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, gc.Equals, private)
+}
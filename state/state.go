@@ -88,6 +88,9 @@ type State struct {
 	newPolicy              NewPolicyFunc
 	runTransactionObserver RunTransactionObserverFunc
 
+	// secretsKey is the key that charm secrets are encrypted with.
+	secretsKey []byte
+
 	// cloudName is the name of the cloud on which the model
 	// represented by this state runs.
 	cloudName string
@@ -103,6 +106,10 @@ type State struct {
 	// first step.
 	workers *workers
 
+	// modelConfigCache holds the model config most recently read
+	// by cachedModelConfig.
+	modelConfigCache modelConfigCache
+
 	// TODO(anastasiamac 2015-07-16) As state gets broken up, remove this.
 	CloudImageMetadataStorage cloudimagemetadata.Storage
 }
@@ -125,6 +132,10 @@ type StateServingInfo struct {
 	// this will be passed as the KeyFile argument to MongoDB
 	SharedSecret   string
 	SystemIdentity string
+	// SSHCAPrivateKey is the private key of the controller's SSH
+	// certificate authority. It is omitted when empty so that
+	// setting serving info without it leaves the stored key alone.
+	SSHCAPrivateKey string `bson:"sshcaprivatekey,omitempty"`
 }
 
 // IsController returns true if this state instance has the bootstrap
@@ -264,6 +275,15 @@ func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// And those for the model's applications.
+	appPermPattern := bson.M{
+		"_id": bson.M{"$regex": "^" + applicationAccessKey(modelUUID, "")},
+	}
+	appOps, err := st.removeInCollectionOps(permissionsC, appPermPattern)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, appOps...)
 	err = st.db().RunTransaction(ops)
 	if err != nil {
 		return errors.Trace(err)
@@ -112,6 +112,7 @@ func InitializeWithArgs(c *gc.C, args InitializeArgs) (*state.Controller, *state
 		MongoSession:  session,
 		NewPolicy:     args.NewPolicy,
 		AdminPassword: "admin-secret",
+		SecretsKey:    testing.SecretsKey,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	return ctlr, st
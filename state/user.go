@@ -88,6 +88,13 @@ func (st *State) addUser(name, displayName, password, creator string, secretKey
 	}
 
 	if password != "" {
+		controllerConfig, err := st.ControllerConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := controllerConfig.PasswordPolicy().Validate(password); err != nil {
+			return nil, errors.Trace(err)
+		}
 		salt, err := utils.RandomSalt()
 		if err != nil {
 			return nil, err
@@ -149,6 +156,14 @@ func (st *State) RemoveUser(tag names.UserTag) error {
 			Assert: txn.DocExists,
 			Update: bson.M{"$set": bson.M{"deleted": true}},
 		}}
+		// A removed user no longer inherits access from groups.
+		groups, err := st.UserGroupsForUser(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, group := range groups {
+			ops = append(ops, removeUserGroupMemberOp(group.doc.DocID, tag))
+		}
 		return ops, nil
 	}
 	return st.db().Run(buildTxn)
@@ -282,6 +297,31 @@ type userDoc struct {
 	PasswordSalt string    `bson:"passwordsalt"`
 	CreatedBy    string    `bson:"createdby"`
 	DateCreated  time.Time `bson:"datecreated"`
+
+	// PasswordChanged records when the password was last set. It is
+	// not set for users whose password has not changed since they
+	// were created.
+	PasswordChanged time.Time `bson:"passwordchanged,omitempty"`
+
+	// FailedLogins counts the password logins that have failed since
+	// the last successful one.
+	FailedLogins int `bson:"failedlogins,omitempty"`
+
+	// LockedUntil records when a user locked out after too many
+	// failed logins may next log in. Locked users are also unlocked
+	// by enabling them.
+	LockedUntil time.Time `bson:"lockeduntil,omitempty"`
+
+	// MFASecret holds the user's secret for multi-factor
+	// authentication. MFAConfirmed records whether the user has
+	// confirmed enrolment by providing a code generated from it;
+	// until then the secret is not used to authenticate.
+	MFASecret    string `bson:"mfasecret,omitempty"`
+	MFAConfirmed bool   `bson:"mfaconfirmed,omitempty"`
+
+	// MFACounter holds the counter of the last multi-factor
+	// authentication code accepted, so that codes cannot be reused.
+	MFACounter int64 `bson:"mfacounter,omitempty"`
 }
 
 type userLastLoginDoc struct {
@@ -398,11 +438,19 @@ func (u *User) SecretKey() []byte {
 	return u.doc.SecretKey
 }
 
-// SetPassword sets the password associated with the User.
+// SetPassword sets the password associated with the User. The password
+// must meet the controller's password policy.
 func (u *User) SetPassword(password string) error {
 	if err := u.ensureNotDeleted(); err != nil {
 		return errors.Annotate(err, "cannot set password")
 	}
+	controllerConfig, err := u.st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := controllerConfig.PasswordPolicy().Validate(password); err != nil {
+		return errors.Trace(err)
+	}
 	salt, err := utils.RandomSalt()
 	if err != nil {
 		return err
@@ -419,9 +467,11 @@ func (u *User) SetPasswordHash(pwHash string, pwSalt string) error {
 		// explicit check before login.
 		return errors.Annotate(err, "cannot set password hash")
 	}
+	changed := u.st.nowToTheSecond()
 	update := bson.D{{"$set", bson.D{
 		{"passwordhash", pwHash},
 		{"passwordsalt", pwSalt},
+		{"passwordchanged", changed},
 	}}}
 	if u.doc.SecretKey != nil {
 		update = append(update,
@@ -439,10 +489,19 @@ func (u *User) SetPasswordHash(pwHash string, pwSalt string) error {
 	}
 	u.doc.PasswordHash = pwHash
 	u.doc.PasswordSalt = pwSalt
+	u.doc.PasswordChanged = changed
 	u.doc.SecretKey = nil
 	return nil
 }
 
+// PasswordChanged returns when the user's password was last set.
+func (u *User) PasswordChanged() time.Time {
+	if u.doc.PasswordChanged.IsZero() {
+		return u.doc.DateCreated
+	}
+	return u.doc.PasswordChanged.UTC()
+}
+
 // PasswordValid returns whether the given password is valid for the User. The
 // caller should call user.Refresh before calling this.
 func (u *User) PasswordValid(password string) bool {
@@ -451,7 +510,7 @@ func (u *User) PasswordValid(password string) bool {
 	// read from the database, there is a very small timeframe where an user
 	// could be disabled after it has been read but prior to being checked, but
 	// in practice, this isn't a problem.
-	if u.IsDisabled() || u.IsDeleted() {
+	if u.IsDisabled() || u.IsDeleted() || u.IsLocked() {
 		return false
 	}
 	if u.doc.PasswordSalt != "" {
@@ -494,11 +553,18 @@ func (u *User) Enable() error {
 }
 
 func (u *User) setDeactivated(value bool) error {
+	update := bson.D{{"$set", bson.D{{"deactivated", value}}}}
+	if !value {
+		// Enabling a user also unlocks it.
+		update = append(update, bson.DocElem{
+			"$unset", bson.D{{"lockeduntil", ""}, {"failedlogins", ""}},
+		})
+	}
 	ops := []txn.Op{{
 		C:      usersC,
 		Id:     u.Name(),
 		Assert: txn.DocExists,
-		Update: bson.D{{"$set", bson.D{{"deactivated", value}}}},
+		Update: update,
 	}}
 	if err := u.st.db().RunTransaction(ops); err != nil {
 		if err == txn.ErrAborted {
@@ -507,6 +573,10 @@ func (u *User) setDeactivated(value bool) error {
 		return err
 	}
 	u.doc.Deactivated = value
+	if !value {
+		u.doc.LockedUntil = time.Time{}
+		u.doc.FailedLogins = 0
+	}
 	return nil
 }
 
@@ -517,6 +587,208 @@ func (u *User) IsDisabled() bool {
 	return u.doc.Deactivated
 }
 
+// IsLocked returns whether the user is currently locked out after too
+// many failed logins.
+func (u *User) IsLocked() bool {
+	return u.st.clock().Now().Before(u.doc.LockedUntil)
+}
+
+// FailedLogins returns the number of password logins that have failed
+// since the last successful one.
+func (u *User) FailedLogins() int {
+	return u.doc.FailedLogins
+}
+
+// RecordFailedLogin records a failed password login for the user. If
+// threshold is positive and the user has failed to log in that many
+// times in a row, the user is locked out for the given duration, or
+// until it is next enabled. The controller owner is never locked out,
+// so that there is always a user able to unlock the others.
+func (u *User) RecordFailedLogin(threshold int, lockout time.Duration) error {
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$inc", bson.D{{"failedlogins", 1}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot record failed login of user %q", u.Name())
+	}
+	if err := u.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	if threshold <= 0 || u.doc.FailedLogins < threshold || u.IsLocked() {
+		return nil
+	}
+	owner, err := u.st.ControllerOwner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if u.doc.Name == owner.Name() {
+		return nil
+	}
+	// The count starts again once the lockout has expired.
+	lockedUntil := u.st.nowToTheSecond().Add(lockout)
+	ops = []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{
+			{"$set", bson.D{{"lockeduntil", lockedUntil}}},
+			{"$unset", bson.D{{"failedlogins", ""}}},
+		},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot lock user %q", u.Name())
+	}
+	u.doc.LockedUntil = lockedUntil
+	u.doc.FailedLogins = 0
+	return nil
+}
+
+// ResetFailedLogins clears the count of failed password logins after
+// the user has logged in successfully.
+func (u *User) ResetFailedLogins() error {
+	if u.doc.FailedLogins == 0 {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{{"failedlogins", ""}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot reset failed logins of user %q", u.Name())
+	}
+	u.doc.FailedLogins = 0
+	return nil
+}
+
+// MFAEnabled reports whether the user must provide a multi-factor
+// authentication code, as well as their password, to log in.
+func (u *User) MFAEnabled() bool {
+	return u.doc.MFAConfirmed && u.doc.MFASecret != ""
+}
+
+// MFASecret returns the user's multi-factor authentication secret,
+// which may not yet have been confirmed.
+func (u *User) MFASecret() string {
+	return u.doc.MFASecret
+}
+
+// MFACounter returns the counter of the last multi-factor
+// authentication code accepted for the user.
+func (u *User) MFACounter() int64 {
+	return u.doc.MFACounter
+}
+
+// SetMFASecret starts enrolling the user for multi-factor
+// authentication with the given secret. The secret is not used to
+// authenticate the user until enrolment is confirmed with ConfirmMFA.
+// It is an error to enrol a user who already has multi-factor
+// authentication enabled.
+func (u *User) SetMFASecret(secret string) error {
+	if secret == "" {
+		return errors.NotValidf("empty multi-factor authentication secret")
+	}
+	if u.MFAEnabled() {
+		return errors.AlreadyExistsf("multi-factor authentication for user %q", u.Name())
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
+		Assert: bson.D{{"mfaconfirmed", bson.D{{"$ne", true}}}},
+		Update: bson.D{
+			{"$set", bson.D{{"mfasecret", secret}}},
+			{"$unset", bson.D{{"mfacounter", ""}}},
+		},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.New("user removed or multi-factor authentication already enabled")
+		}
+		return errors.Annotatef(err, "cannot set multi-factor authentication secret of user %q", u.Name())
+	}
+	u.doc.MFASecret = secret
+	u.doc.MFACounter = 0
+	return nil
+}
+
+// ConfirmMFA completes the user's multi-factor authentication
+// enrolment, recording the counter of the code the user provided to
+// prove they hold the secret. It fails if the secret has changed since
+// the user was last read.
+func (u *User) ConfirmMFA(counter int64) error {
+	if u.doc.MFASecret == "" {
+		return errors.NotFoundf("multi-factor authentication secret for user %q", u.Name())
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
+		Assert: bson.D{{"mfasecret", u.doc.MFASecret}},
+		Update: bson.D{{"$set", bson.D{
+			{"mfaconfirmed", true},
+			{"mfacounter", counter},
+		}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.New("multi-factor authentication secret changed")
+		}
+		return errors.Annotatef(err, "cannot confirm multi-factor authentication of user %q", u.Name())
+	}
+	u.doc.MFAConfirmed = true
+	u.doc.MFACounter = counter
+	return nil
+}
+
+// DisableMFA removes the user's multi-factor authentication secret,
+// so they may log in with just their password.
+func (u *User) DisableMFA() error {
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{
+			{"mfasecret", ""},
+			{"mfaconfirmed", ""},
+			{"mfacounter", ""},
+		}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot disable multi-factor authentication of user %q", u.Name())
+	}
+	u.doc.MFASecret = ""
+	u.doc.MFAConfirmed = false
+	u.doc.MFACounter = 0
+	return nil
+}
+
+// UseMFACounter records that a multi-factor authentication code with
+// the given counter has been used to log in. It fails if a code with
+// the same or a later counter has already been used, so that codes
+// cannot be replayed.
+func (u *User) UseMFACounter(counter int64) error {
+	ops := []txn.Op{{
+		C:  usersC,
+		Id: u.doc.DocID,
+		Assert: bson.D{
+			{"mfaconfirmed", true},
+			{"mfacounter", bson.D{{"$not", bson.D{{"$gte", counter}}}}},
+		},
+		Update: bson.D{{"$set", bson.D{{"mfacounter", counter}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.NotValidf("reused multi-factor authentication code")
+		}
+		return errors.Annotatef(err, "user %q", u.Name())
+	}
+	u.doc.MFACounter = counter
+	return nil
+}
+
 // IsDeleted returns whether the user is currently deleted.
 func (u *User) IsDeleted() bool {
 	return u.doc.Deleted
@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
@@ -279,6 +280,160 @@ func (s *UserSuite) TestDisable(c *gc.C) {
 	c.Assert(s.activeUsers(c), jc.DeepEquals, []string{"test-admin", user.Name()})
 }
 
+func (s *UserSuite) TestSetPasswordPolicy(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "a-password"})
+	created := user.PasswordChanged()
+	c.Assert(created, gc.Equals, user.DateCreated())
+
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.PasswordMinLength:  10,
+		controller.PasswordMinClasses: 3,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = user.SetPassword("Sh0rt!")
+	c.Assert(err, gc.ErrorMatches, "password must be at least 10 characters long")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = user.SetPassword("not-complex-enough")
+	c.Assert(err, gc.ErrorMatches, "password must contain at least 3 of .*")
+	c.Assert(user.PasswordValid("a-password"), jc.IsTrue)
+
+	_, err = s.State.AddUser("newbie", "", "password", "admin")
+	c.Assert(err, gc.ErrorMatches, "password must be at least 10 characters long")
+
+	err = user.SetPassword("Compl3x-enough")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.PasswordValid("Compl3x-enough"), jc.IsTrue)
+	c.Assert(user.PasswordChanged().Before(created), jc.IsFalse)
+}
+
+func (s *UserSuite) TestRecordFailedLogin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "a-password"})
+
+	for i := 0; i < 2; i++ {
+		err := user.RecordFailedLogin(3, time.Hour)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(user.FailedLogins(), gc.Equals, 2)
+	c.Assert(user.IsLocked(), jc.IsFalse)
+
+	err := user.ResetFailedLogins()
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+
+	for i := 0; i < 3; i++ {
+		err := user.RecordFailedLogin(3, time.Hour)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(user.IsLocked(), jc.IsTrue)
+	c.Assert(user.PasswordValid("a-password"), jc.IsFalse)
+
+	// The lockout expires.
+	s.Clock.Advance(time.Hour)
+	c.Assert(user.IsLocked(), jc.IsFalse)
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+	c.Assert(user.PasswordValid("a-password"), jc.IsTrue)
+
+	for i := 0; i < 3; i++ {
+		err := user.RecordFailedLogin(3, time.Hour)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(user.IsLocked(), jc.IsTrue)
+
+	// Enabling the user unlocks it.
+	err = user.Enable()
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.IsLocked(), jc.IsFalse)
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+	c.Assert(user.PasswordValid("a-password"), jc.IsTrue)
+}
+
+func (s *UserSuite) TestRecordFailedLoginNoThreshold(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	for i := 0; i < 10; i++ {
+		err := user.RecordFailedLogin(0, time.Hour)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(user.FailedLogins(), gc.Equals, 10)
+	c.Assert(user.IsLocked(), jc.IsFalse)
+}
+
+func (s *UserSuite) TestRecordFailedLoginControllerOwner(c *gc.C) {
+	owner, err := s.State.User(s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 5; i++ {
+		err := owner.RecordFailedLogin(3, time.Hour)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(owner.FailedLogins(), gc.Equals, 5)
+	c.Assert(owner.IsLocked(), jc.IsFalse)
+}
+
+func (s *UserSuite) TestMFAEnrolment(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+
+	err := user.SetMFASecret("SECRET")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFASecret(), gc.Equals, "SECRET")
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+
+	err = user.ConfirmMFA(100)
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsTrue)
+	c.Assert(user.MFACounter(), gc.Equals, int64(100))
+
+	err = user.SetMFASecret("OTHER")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	err = user.DisableMFA()
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+	c.Assert(user.MFASecret(), gc.Equals, "")
+}
+
+func (s *UserSuite) TestConfirmMFASecretChanged(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	err := user.SetMFASecret("SECRET")
+	c.Assert(err, jc.ErrorIsNil)
+
+	other, err := s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.SetMFASecret("OTHER")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = user.ConfirmMFA(100)
+	c.Assert(err, gc.ErrorMatches, `cannot confirm multi-factor authentication of user ".*": multi-factor authentication secret changed`)
+}
+
+func (s *UserSuite) TestUseMFACounter(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	err := user.SetMFASecret("SECRET")
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.ConfirmMFA(100)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = user.UseMFACounter(100)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = user.UseMFACounter(99)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = user.UseMFACounter(101)
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFACounter(), gc.Equals, int64(101))
+	err = user.UseMFACounter(101)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
 func (s *UserSuite) activeUsers(c *gc.C) []string {
 	users, err := s.State.AllUsers(false)
 	c.Assert(err, jc.ErrorIsNil)
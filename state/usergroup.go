@@ -0,0 +1,502 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/permission"
+)
+
+const userGroupGlobalKeyPrefix = "gr"
+
+func userGroupGlobalKey(groupID string) string {
+	return fmt.Sprintf("%s#%s", userGroupGlobalKeyPrefix, groupID)
+}
+
+// UserGroup represents a named group of users. Access granted
+// to a group on a model, application, offer or the controller is inherited by all
+// of its members.
+type UserGroup struct {
+	st  *State
+	doc userGroupDoc
+}
+
+type userGroupDoc struct {
+	DocID       string    `bson:"_id"`
+	Name        string    `bson:"name"`
+	Members     []string  `bson:"members"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+
+	// ExternalGroups holds the external identity provider groups,
+	// as "name@domain", whose members are made members of this
+	// group when their membership is synced.
+	ExternalGroups []string `bson:"external-groups,omitempty"`
+}
+
+// AddUserGroup adds a new, empty, user group to the database.
+func (st *State) AddUserGroup(name, creator string) (*UserGroup, error) {
+	if !names.IsValidUserName(name) {
+		return nil, errors.NotValidf("group name %q", name)
+	}
+	group := &UserGroup{
+		st: st,
+		doc: userGroupDoc{
+			DocID:       strings.ToLower(name),
+			Name:        name,
+			Members:     []string{},
+			CreatedBy:   creator,
+			DateCreated: st.nowToTheSecond(),
+		},
+	}
+	ops := []txn.Op{{
+		C:      userGroupsC,
+		Id:     group.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &group.doc,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("group %q", name)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return group, nil
+}
+
+// UserGroup returns the user group with the given name.
+func (st *State) UserGroup(name string) (*UserGroup, error) {
+	groups, closer := st.db().GetCollection(userGroupsC)
+	defer closer()
+
+	group := &UserGroup{st: st}
+	err := groups.FindId(strings.ToLower(name)).One(&group.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("group %q", name)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	group.doc.DateCreated = group.doc.DateCreated.UTC()
+	return group, nil
+}
+
+// AllUserGroups returns all user groups, sorted by name.
+func (st *State) AllUserGroups() ([]*UserGroup, error) {
+	return st.findUserGroups(nil)
+}
+
+// UserGroupsForUser returns the groups the given user is a member of,
+// sorted by name.
+func (st *State) UserGroupsForUser(user names.UserTag) ([]*UserGroup, error) {
+	return st.findUserGroups(bson.D{{"members", userAccessID(user)}})
+}
+
+// SyncUserGroupMembership makes the given external user a member of
+// exactly those groups that an administrator has mapped one of the
+// named external groups to, removing them from any other group. It is
+// used to mirror the group membership reported by an external identity
+// provider each time the user logs in. The external group names are
+// qualified with the user's domain, so that a group reported by one
+// identity provider is never mistaken for one reported by another, or
+// for a local group; names that are not mapped to any group are
+// ignored.
+func (st *State) SyncUserGroupMembership(user names.UserTag, groupNames []string) error {
+	if user.IsLocal() {
+		return errors.NotValidf("local user %q for group sync", user.Id())
+	}
+	externalGroups := make(map[string]bool)
+	for _, name := range groupNames {
+		externalGroups[strings.ToLower(name+"@"+user.Domain())] = true
+	}
+	member := userAccessID(user)
+	buildTxn := func(int) ([]txn.Op, error) {
+		groups, err := st.AllUserGroups()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var ops []txn.Op
+		for _, group := range groups {
+			isMember := false
+			for _, m := range group.doc.Members {
+				if m == member {
+					isMember = true
+					break
+				}
+			}
+			wanted := false
+			for _, externalGroup := range group.doc.ExternalGroups {
+				if externalGroups[externalGroup] {
+					wanted = true
+					break
+				}
+			}
+			switch {
+			case wanted && !isMember:
+				ops = append(ops, txn.Op{
+					C:      userGroupsC,
+					Id:     group.doc.DocID,
+					Assert: bson.D{{"members", bson.D{{"$ne", member}}}},
+					Update: bson.D{{"$addToSet", bson.D{{"members", member}}}},
+				})
+			case !wanted && isMember:
+				ops = append(ops, removeUserGroupMemberOp(group.doc.DocID, user))
+			}
+		}
+		if len(ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return ops, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "syncing groups of %q", user.Id())
+}
+
+func (st *State) findUserGroups(query bson.D) ([]*UserGroup, error) {
+	groups, closer := st.db().GetCollection(userGroupsC)
+	defer closer()
+
+	var docs []userGroupDoc
+	if err := groups.Find(query).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]*UserGroup, len(docs))
+	for i, doc := range docs {
+		doc.DateCreated = doc.DateCreated.UTC()
+		result[i] = &UserGroup{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveUserGroup removes the group with the given name, along with
+// all access granted to it.
+func (st *State) RemoveUserGroup(name string) error {
+	groupID := strings.ToLower(name)
+	permPattern := bson.D{{"subject-global-key", userGroupGlobalKey(groupID)}}
+	ops, err := st.removeInCollectionOps(permissionsC, permPattern)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:      userGroupsC,
+		Id:     groupID,
+		Assert: txn.DocExists,
+		Remove: true,
+	})
+	err = st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("group %q", name)
+	}
+	return errors.Trace(err)
+}
+
+// Name returns the name of the group.
+func (g *UserGroup) Name() string {
+	return g.doc.Name
+}
+
+// CreatedBy returns the name of the user that created the group.
+func (g *UserGroup) CreatedBy() string {
+	return g.doc.CreatedBy
+}
+
+// DateCreated returns when the group was created in UTC.
+func (g *UserGroup) DateCreated() time.Time {
+	return g.doc.DateCreated
+}
+
+// Members returns the tags of the group's members, sorted by name.
+func (g *UserGroup) Members() []names.UserTag {
+	members := make([]string, len(g.doc.Members))
+	copy(members, g.doc.Members)
+	sort.Strings(members)
+	result := make([]names.UserTag, len(members))
+	for i, member := range members {
+		result[i] = names.NewUserTag(member)
+	}
+	return result
+}
+
+// AddMember adds the given local user to the group. Adding an
+// existing member is not an error.
+func (g *UserGroup) AddMember(user names.UserTag) error {
+	if !user.IsLocal() {
+		return errors.NotValidf("external user %q as group member", user.Id())
+	}
+	if _, err := g.st.User(user); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     userAccessID(user),
+		Assert: bson.D{{"deleted", bson.D{{"$ne", true}}}},
+	}, {
+		C:      userGroupsC,
+		Id:     g.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$addToSet", bson.D{{"members", userAccessID(user)}}}},
+	}}
+	if err := g.st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.Errorf("group %q or user %q no longer exists", g.Name(), user.Name())
+		}
+		return errors.Annotatef(err, "adding %q to group %q", user.Name(), g.Name())
+	}
+	return g.Refresh()
+}
+
+// RemoveMember removes the given user from the group.
+func (g *UserGroup) RemoveMember(user names.UserTag) error {
+	ops := []txn.Op{removeUserGroupMemberOp(g.doc.DocID, user)}
+	err := g.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("user %q in group %q", user.Id(), g.Name())
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return g.Refresh()
+}
+
+func removeUserGroupMemberOp(groupID string, user names.UserTag) txn.Op {
+	member := userAccessID(user)
+	return txn.Op{
+		C:      userGroupsC,
+		Id:     groupID,
+		Assert: bson.D{{"members", member}},
+		Update: bson.D{{"$pull", bson.D{{"members", member}}}},
+	}
+}
+
+// ExternalGroups returns the external identity provider groups, as
+// "name@domain", mapped to the group, sorted by name.
+func (g *UserGroup) ExternalGroups() []string {
+	externalGroups := make([]string, len(g.doc.ExternalGroups))
+	copy(externalGroups, g.doc.ExternalGroups)
+	sort.Strings(externalGroups)
+	return externalGroups
+}
+
+// AddExternalGroup maps the given external identity provider group,
+// as "name@domain", to the group. Users of that domain who are members
+// of the external group become members of the group the next time
+// their membership is synced. Adding an existing mapping is not an
+// error.
+func (g *UserGroup) AddExternalGroup(externalGroup string) error {
+	if err := validateExternalGroup(externalGroup); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      userGroupsC,
+		Id:     g.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$addToSet", bson.D{{"external-groups", strings.ToLower(externalGroup)}}}},
+	}}
+	if err := g.st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.NotFoundf("group %q", g.Name())
+		}
+		return errors.Annotatef(err, "adding external group %q to group %q", externalGroup, g.Name())
+	}
+	return g.Refresh()
+}
+
+// RemoveExternalGroup removes the mapping of the given external
+// identity provider group to the group. Members who only belonged to
+// the group through that external group are removed the next time
+// their membership is synced.
+func (g *UserGroup) RemoveExternalGroup(externalGroup string) error {
+	externalGroup = strings.ToLower(externalGroup)
+	ops := []txn.Op{{
+		C:      userGroupsC,
+		Id:     g.doc.DocID,
+		Assert: bson.D{{"external-groups", externalGroup}},
+		Update: bson.D{{"$pull", bson.D{{"external-groups", externalGroup}}}},
+	}}
+	err := g.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("external group %q in group %q", externalGroup, g.Name())
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return g.Refresh()
+}
+
+// validateExternalGroup checks that the given external group name is
+// of the form "name@domain", with a domain other than the local one.
+func validateExternalGroup(externalGroup string) error {
+	at := strings.LastIndex(externalGroup, "@")
+	if at <= 0 || at == len(externalGroup)-1 {
+		return errors.NotValidf("external group %q without domain", externalGroup)
+	}
+	if domain := externalGroup[at+1:]; domain == "local" {
+		return errors.NotValidf("external group %q in local domain", externalGroup)
+	}
+	return nil
+}
+
+// Refresh reloads the group's details from the database.
+func (g *UserGroup) Refresh() error {
+	group, err := g.st.UserGroup(g.doc.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	g.doc = group.doc
+	return nil
+}
+
+// userGroupAccessKey returns the global key of the object for which
+// access is being granted by the given target tag.
+func (st *State) userGroupAccessKey(target names.Tag) (string, error) {
+	switch target.Kind() {
+	case names.ModelTagKind:
+		return modelKey(target.Id()), nil
+	case names.ControllerTagKind:
+		return controllerKey(st.ControllerUUID()), nil
+	case names.ApplicationOfferTagKind:
+		offerUUID, err := applicationOfferUUID(st, target.Id())
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return applicationOfferKey(offerUUID), nil
+	case names.ApplicationTagKind:
+		return applicationAccessKey(st.ModelUUID(), target.Id()), nil
+	default:
+		return "", errors.NotValidf("%q as a target", target.Kind())
+	}
+}
+
+func validateTargetAccess(target names.Tag, access permission.Access) error {
+	switch target.Kind() {
+	case names.ModelTagKind:
+		return permission.ValidateModelAccess(access)
+	case names.ControllerTagKind:
+		return permission.ValidateControllerAccess(access)
+	case names.ApplicationOfferTagKind:
+		return permission.ValidateOfferAccess(access)
+	case names.ApplicationTagKind:
+		return permission.ValidateApplicationAccess(access)
+	}
+	return errors.NotValidf("%q as a target", target.Kind())
+}
+
+// SetUserGroupAccess grants the given access to the named group on the
+// target model, application, offer or controller, replacing any existing grant.
+func (st *State) SetUserGroupAccess(name string, target names.Tag, access permission.Access) error {
+	if err := validateTargetAccess(target, access); err != nil {
+		return errors.Trace(err)
+	}
+	group, err := st.UserGroup(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	objectKey, err := st.userGroupAccessKey(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subjectKey := userGroupGlobalKey(group.doc.DocID)
+	buildTxn := func(int) ([]txn.Op, error) {
+		groupOp := txn.Op{
+			C:      userGroupsC,
+			Id:     group.doc.DocID,
+			Assert: txn.DocExists,
+		}
+		_, err := st.userPermission(objectKey, subjectKey)
+		if errors.IsNotFound(err) {
+			return []txn.Op{groupOp, createPermissionOp(objectKey, subjectKey, access)}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{groupOp, updatePermissionOp(objectKey, subjectKey, access)}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// RemoveUserGroupAccess revokes access granted to the named group on
+// the target model, application, offer or controller.
+func (st *State) RemoveUserGroupAccess(name string, target names.Tag) error {
+	objectKey, err := st.userGroupAccessKey(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{removePermissionOp(objectKey, userGroupGlobalKey(strings.ToLower(name)))}
+	err = st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("access for group %q on %s", name, names.ReadableString(target))
+	}
+	return errors.Trace(err)
+}
+
+// UserGroupAccess returns the access granted to the named group on the
+// target model, application, offer or controller.
+func (st *State) UserGroupAccess(name string, target names.Tag) (permission.Access, error) {
+	objectKey, err := st.userGroupAccessKey(target)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	perm, err := st.userPermission(objectKey, userGroupGlobalKey(strings.ToLower(name)))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return perm.access(), nil
+}
+
+// EffectiveUserPermission returns the greatest access the given user
+// has on the target model, application, offer or controller, taking
+// into account both access granted directly to the user and access
+// inherited from the groups they are a member of. If the user has no
+// access at all, a NotFound error is returned.
+func (st *State) EffectiveUserPermission(user names.UserTag, target names.Tag) (permission.Access, error) {
+	access, err := st.UserPermission(user, target)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	groups, err := st.UserGroupsForUser(user)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, group := range groups {
+		groupAccess, err := st.UserGroupAccess(group.Name(), target)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+		if greaterAccess(target, groupAccess, access) {
+			access = groupAccess
+		}
+	}
+	if access == permission.NoAccess {
+		return "", errors.NotFoundf("access for %q on %s", user.Id(), names.ReadableString(target))
+	}
+	return access, nil
+}
+
+// greaterAccess reports whether a is greater than b for the kind of
+// the given target.
+func greaterAccess(target names.Tag, a, b permission.Access) bool {
+	switch target.Kind() {
+	case names.ModelTagKind:
+		return a.GreaterModelAccessThan(b)
+	case names.ControllerTagKind:
+		return a.GreaterControllerAccessThan(b)
+	case names.ApplicationOfferTagKind:
+		return a.GreaterOfferAccessThan(b)
+	case names.ApplicationTagKind:
+		return a.GreaterApplicationAccessThan(b)
+	}
+	return false
+}
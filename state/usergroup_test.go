@@ -0,0 +1,344 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserGroupSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserGroupSuite{})
+
+func (s *UserGroupSuite) TestAddUserGroup(c *gc.C) {
+	group, err := s.State.AddUserGroup("DevOps", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "DevOps")
+	c.Assert(group.CreatedBy(), gc.Equals, "admin")
+	c.Assert(group.Members(), gc.HasLen, 0)
+
+	group, err = s.State.UserGroup("devops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "DevOps")
+}
+
+func (s *UserGroupSuite) TestAddUserGroupInvalidName(c *gc.C) {
+	_, err := s.State.AddUserGroup("dev ops", "admin")
+	c.Assert(err, gc.ErrorMatches, `group name "dev ops" not valid`)
+}
+
+func (s *UserGroupSuite) TestAddUserGroupDuplicate(c *gc.C) {
+	_, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddUserGroup("DevOps", "admin")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *UserGroupSuite) TestUserGroupNotFound(c *gc.C) {
+	_, err := s.State.UserGroup("nope")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `group "nope" not found`)
+}
+
+func (s *UserGroupSuite) TestMembers(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	mary := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary", NoModelUser: true})
+	group, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(group.AddMember(mary.UserTag()), jc.ErrorIsNil)
+	c.Assert(group.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	// Adding an existing member is a no-op.
+	c.Assert(group.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{bob.UserTag(), mary.UserTag()})
+
+	groups, err := s.State.UserGroupsForUser(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 1)
+	c.Assert(groups[0].Name(), gc.Equals, "devops")
+
+	c.Assert(group.RemoveMember(bob.UserTag()), jc.ErrorIsNil)
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{mary.UserTag()})
+	err = group.RemoveMember(bob.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	groups, err = s.State.UserGroupsForUser(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+}
+
+func (s *UserGroupSuite) TestRemoveUserRemovesMemberships(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	mary := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary", NoModelUser: true})
+	devops, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devops.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	c.Assert(devops.AddMember(mary.UserTag()), jc.ErrorIsNil)
+	qa, err := s.State.AddUserGroup("qa", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(qa.AddMember(bob.UserTag()), jc.ErrorIsNil)
+
+	err = s.State.RemoveUser(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	groups, err := s.State.UserGroupsForUser(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+	c.Assert(devops.Refresh(), jc.ErrorIsNil)
+	c.Assert(devops.Members(), jc.DeepEquals, []names.UserTag{mary.UserTag()})
+}
+
+func (s *UserGroupSuite) TestAddMemberUnknownUser(c *gc.C) {
+	group, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddMember(names.NewUserTag("ghost"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = group.AddMember(names.NewUserTag("bob@external"))
+	c.Assert(err, gc.ErrorMatches, `external user "bob@external" as group member not valid`)
+}
+
+func (s *UserGroupSuite) TestSyncUserGroupMembership(c *gc.C) {
+	for name, externalGroups := range map[string][]string{
+		"dev":   {"Developers@ldap"},
+		"ops":   {"operators@ldap", "sre@ldap"},
+		"qa":    {"qa@ldap"},
+		"admin": {"ops@oidc"},
+		"sre":   nil,
+	} {
+		group, err := s.State.AddUserGroup(name, "admin")
+		c.Assert(err, jc.ErrorIsNil)
+		for _, externalGroup := range externalGroups {
+			err := group.AddExternalGroup(externalGroup)
+			c.Assert(err, jc.ErrorIsNil)
+		}
+	}
+	bob := names.NewUserTag("bob@ldap")
+	groupNames := func() []string {
+		groups, err := s.State.UserGroupsForUser(bob)
+		c.Assert(err, jc.ErrorIsNil)
+		var result []string
+		for _, g := range groups {
+			result = append(result, g.Name())
+		}
+		return result
+	}
+
+	// Only mapped groups are joined: neither the local group with
+	// the same name as an external group, nor the group mapped to a
+	// group of another identity provider.
+	err := s.State.SyncUserGroupMembership(bob, []string{"developers", "sre", "ops", "unknown"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groupNames(), jc.DeepEquals, []string{"dev", "ops"})
+
+	err = s.State.SyncUserGroupMembership(bob, []string{"operators", "qa"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groupNames(), jc.DeepEquals, []string{"ops", "qa"})
+
+	// Syncing without changes is fine.
+	err = s.State.SyncUserGroupMembership(bob, []string{"operators", "qa"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SyncUserGroupMembership(bob, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groupNames(), gc.HasLen, 0)
+}
+
+func (s *UserGroupSuite) TestExternalGroups(c *gc.C) {
+	group, err := s.State.AddUserGroup("dev", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.ExternalGroups(), gc.HasLen, 0)
+
+	err = group.AddExternalGroup("Developers@ldap")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddExternalGroup("developers@ldap")
+	c.Assert(err, jc.ErrorIsNil)
+	err = group.AddExternalGroup("dev@oidc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.ExternalGroups(), jc.DeepEquals, []string{"dev@oidc", "developers@ldap"})
+
+	err = group.RemoveExternalGroup("DEV@oidc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.ExternalGroups(), jc.DeepEquals, []string{"developers@ldap"})
+	err = group.RemoveExternalGroup("dev@oidc")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	for _, invalid := range []string{"developers", "developers@", "@ldap", "developers@local"} {
+		err = group.AddExternalGroup(invalid)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *UserGroupSuite) TestSyncUserGroupMembershipLocalUser(c *gc.C) {
+	err := s.State.SyncUserGroupMembership(names.NewUserTag("bob"), []string{"dev"})
+	c.Assert(err, gc.ErrorMatches, `local user "bob" for group sync not valid`)
+}
+
+func (s *UserGroupSuite) TestAllUserGroups(c *gc.C) {
+	for _, name := range []string{"ops", "dev", "qa"} {
+		_, err := s.State.AddUserGroup(name, "admin")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	groups, err := s.State.AllUserGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	var groupNames []string
+	for _, group := range groups {
+		groupNames = append(groupNames, group.Name())
+	}
+	c.Assert(groupNames, jc.DeepEquals, []string{"dev", "ops", "qa"})
+}
+
+func (s *UserGroupSuite) TestEffectiveModelPermission(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	modelTag := s.IAASModel.ModelTag()
+
+	_, err := s.State.EffectiveUserPermission(bob.UserTag(), modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	group, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("devops", modelTag, permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.EffectiveUserPermission(bob.UserTag(), modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	// The greatest of direct and inherited access wins.
+	_, err = s.IAASModel.AddUser(state.UserAccessSpec{
+		User:      bob.UserTag(),
+		CreatedBy: s.Owner,
+		Access:    permission.ReadAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	access, err = s.State.EffectiveUserPermission(bob.UserTag(), modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	_, err = s.State.SetUserAccess(bob.UserTag(), modelTag, permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err = s.State.EffectiveUserPermission(bob.UserTag(), modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *UserGroupSuite) TestEffectiveControllerPermission(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	controllerTag := s.State.ControllerTag()
+
+	access, err := s.State.EffectiveUserPermission(bob.UserTag(), controllerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.LoginAccess)
+
+	group, err := s.State.AddUserGroup("admins", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("admins", controllerTag, permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err = s.State.EffectiveUserPermission(bob.UserTag(), controllerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.SuperuserAccess)
+
+	// Leaving the group drops the inherited access.
+	c.Assert(group.RemoveMember(bob.UserTag()), jc.ErrorIsNil)
+	access, err = s.State.EffectiveUserPermission(bob.UserTag(), controllerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.LoginAccess)
+}
+
+func (s *UserGroupSuite) TestEffectiveOfferPermission(c *gc.C) {
+	s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	offers := state.NewApplicationOffers(s.State)
+	offer, err := offers.AddOffer(crossmodel.AddApplicationOfferArgs{
+		OfferName:       "someoffer",
+		ApplicationName: "mysql",
+		Owner:           "test-admin",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	offerTag := names.NewApplicationOfferTag(offer.OfferName)
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+
+	group, err := s.State.AddUserGroup("consumers", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("consumers", offerTag, permission.ConsumeAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.EffectiveUserPermission(bob.UserTag(), offerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.ConsumeAccess)
+
+	// Group grants are not reported as offer users.
+	users, err := s.State.GetOfferUsers(offer.OfferUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, jc.DeepEquals, map[string]permission.Access{
+		"test-admin": permission.AdminAccess,
+	})
+}
+
+func (s *UserGroupSuite) TestSetUserGroupAccess(c *gc.C) {
+	_, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	modelTag := s.IAASModel.ModelTag()
+
+	err = s.State.SetUserGroupAccess("devops", modelTag, permission.SuperuserAccess)
+	c.Assert(err, gc.ErrorMatches, `"superuser" model access not valid`)
+
+	err = s.State.SetUserGroupAccess("devops", modelTag, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("devops", modelTag, permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserGroupAccess("devops", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+
+	err = s.State.RemoveUserGroupAccess("devops", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserGroupAccess("devops", modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.RemoveUserGroupAccess("devops", modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserGroupSuite) TestSetUserGroupAccessUnknownGroup(c *gc.C) {
+	err := s.State.SetUserGroupAccess("nope", s.IAASModel.ModelTag(), permission.ReadAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserGroupSuite) TestRemoveUserGroup(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoModelUser: true})
+	modelTag := s.IAASModel.ModelTag()
+	group, err := s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.AddMember(bob.UserTag()), jc.ErrorIsNil)
+	err = s.State.SetUserGroupAccess("devops", modelTag, permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveUserGroup("devops")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserGroup("devops")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.EffectiveUserPermission(bob.UserTag(), modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Recreating the group does not resurrect its old grants.
+	_, err = s.State.AddUserGroup("devops", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserGroupAccess("devops", modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveUserGroup("nope")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -3165,3 +3165,124 @@ func (m *CAASModel) WatchPodSpec(appTag names.ApplicationTag) (NotifyWatcher, er
 	}}
 	return newDocWatcher(m.st, docKeys), nil
 }
+
+// WatchSecretRotations returns a StringsWatcher that notifies of the
+// names of the application's secrets that are due to be rotated. Each
+// event holds the names of all such secrets, so that names are dropped
+// once their secrets have been rotated.
+func (a *Application) WatchSecretRotations() StringsWatcher {
+	return newSecretRotationWatcher(a.st, a.doc.Name)
+}
+
+// secretRotationWatcher notifies of the secrets owned by an
+// application that are due to be rotated.
+type secretRotationWatcher struct {
+	commonWatcher
+	appName string
+	out     chan []string
+}
+
+var _ Watcher = (*secretRotationWatcher)(nil)
+
+func newSecretRotationWatcher(backend modelBackend, appName string) StringsWatcher {
+	w := &secretRotationWatcher{
+		commonWatcher: newCommonWatcher(backend),
+		appName:       appName,
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for the secretRotationWatcher.
+func (w *secretRotationWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+// dueSecrets returns the sorted names of the secrets that are due to
+// be rotated, and the earliest time at which another secret will be.
+func (w *secretRotationWatcher) dueSecrets() ([]string, time.Time, error) {
+	secrets, closer := w.db.GetCollection(secretsC)
+	defer closer()
+
+	var docs []secretDoc
+	query := bson.D{
+		{"application", w.appName},
+		{"rotate-interval", bson.D{{"$gt", 0}}},
+	}
+	if err := secrets.Find(query).Sort("name").All(&docs); err != nil {
+		return nil, time.Time{}, errors.Trace(err)
+	}
+	now := w.backend.clock().Now()
+	due := []string{}
+	var next time.Time
+	for _, doc := range docs {
+		if !doc.NextRotateTime.After(now) {
+			due = append(due, doc.Name)
+		} else if next.IsZero() || doc.NextRotateTime.Before(next) {
+			next = doc.NextRotateTime
+		}
+	}
+	return due, next, nil
+}
+
+func (w *secretRotationWatcher) loop() error {
+	in := make(chan watcher.Change)
+	filter := func(id interface{}) bool {
+		k, err := w.backend.strictLocalID(id.(string))
+		if err != nil {
+			return false
+		}
+		return strings.HasPrefix(k, w.appName+"/")
+	}
+	w.watcher.WatchCollectionWithFilter(secretsC, in, filter)
+	defer w.watcher.UnwatchCollection(secretsC, in)
+
+	var timer <-chan time.Time
+	update := func(due []string) ([]string, bool, error) {
+		newDue, next, err := w.dueSecrets()
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		timer = nil
+		if !next.IsZero() {
+			timer = w.backend.clock().After(next.Sub(w.backend.clock().Now()))
+		}
+		return newDue, !reflect.DeepEqual(newDue, due), nil
+	}
+
+	due, _, err := update(nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	out := w.out
+	for {
+		var changed bool
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case _, ok := <-in:
+			if !ok {
+				return tomb.ErrDying
+			}
+			if due, changed, err = update(due); err != nil {
+				return errors.Trace(err)
+			}
+		case <-timer:
+			if due, changed, err = update(due); err != nil {
+				return errors.Trace(err)
+			}
+		case out <- due:
+			out = nil
+		}
+		if changed {
+			out = w.out
+		}
+	}
+}
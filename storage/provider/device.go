@@ -0,0 +1,472 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// DeviceProviderType is the storage provider type for volumes
+	// backed by block devices already present on a machine.
+	DeviceProviderType = storage.ProviderType("device")
+
+	// DevicesAttr is the pool attribute identifying the block
+	// devices that volumes may be allocated from. The value is a
+	// comma-separated list of device names (e.g. "sdb"), device
+	// links, hardware IDs or WWNs.
+	DevicesAttr = "devices"
+)
+
+// ListBlockDevicesFunc is the type of a function that lists the
+// block devices present on the local machine.
+type ListBlockDevicesFunc func() ([]storage.BlockDevice, error)
+
+// deviceProvider creates volume sources which allocate existing,
+// unused block devices on the local machine to volumes.
+type deviceProvider struct {
+	// listBlockDevices is a function used for listing the block
+	// devices present on the local machine.
+	listBlockDevices ListBlockDevicesFunc
+
+	// run is a function used for running commands on the local
+	// machine.
+	run runCommandFunc
+}
+
+var _ storage.Provider = (*deviceProvider)(nil)
+
+// NewDeviceProvider returns a storage provider which allocates the
+// existing block devices listed by the given function to volumes.
+// If listBlockDevices is nil, the provider may be used for validating
+// storage pools, but cannot create volume sources.
+func NewDeviceProvider(listBlockDevices ListBlockDevicesFunc) storage.Provider {
+	return &deviceProvider{listBlockDevices, logAndExec}
+}
+
+// ValidateConfig is defined on the Provider interface.
+func (*deviceProvider) ValidateConfig(cfg *storage.Config) error {
+	devices, err := parseDevicesAttr(cfg.Attrs())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(devices) == 0 {
+		return errors.Errorf("%q must be specified", DevicesAttr)
+	}
+	return nil
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *deviceProvider) VolumeSource(sourceConfig *storage.Config) (storage.VolumeSource, error) {
+	if p.listBlockDevices == nil {
+		return nil, errors.NotSupportedf("listing block devices on this machine")
+	}
+	storageDir, ok := sourceConfig.ValueString(storage.ConfigStorageDir)
+	if !ok || storageDir == "" {
+		return nil, errors.New("storage directory not specified")
+	}
+	return &deviceVolumeSource{
+		dirFuncs:         &osDirFuncs{p.run},
+		listBlockDevices: p.listBlockDevices,
+		run:              p.run,
+		claimsDir:        filepath.Join(storageDir, "claims"),
+	}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (*deviceProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*deviceProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*deviceProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*deviceProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is defined on the Provider interface.
+func (*deviceProvider) Releasable() bool {
+	return true
+}
+
+// DefaultPools is defined on the Provider interface.
+func (*deviceProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// deviceVolumeSource allocates block devices to volumes. Allocations
+// are recorded as claim files in the claims directory, named after the
+// volume tag and holding the ID of the claimed device.
+type deviceVolumeSource struct {
+	dirFuncs         dirFuncs
+	listBlockDevices ListBlockDevicesFunc
+	run              runCommandFunc
+	claimsDir        string
+}
+
+var _ storage.VolumeSource = (*deviceVolumeSource)(nil)
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (s *deviceVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	devices, err := s.listBlockDevices()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing block devices")
+	}
+	claims, err := s.claims()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	claimed := set.NewStrings()
+	for _, deviceId := range claims {
+		claimed.Add(deviceId)
+	}
+
+	results := make([]storage.CreateVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := s.createVolume(arg, devices, claims, claimed)
+		if err != nil {
+			results[i].Error = errors.Annotate(err, "creating volume")
+			continue
+		}
+		results[i].Volume = volume
+	}
+	return results, nil
+}
+
+func (s *deviceVolumeSource) createVolume(
+	arg storage.VolumeParams,
+	devices []storage.BlockDevice,
+	claims map[names.VolumeTag]string,
+	claimed set.Strings,
+) (*storage.Volume, error) {
+	if deviceId, ok := claims[arg.Tag]; ok {
+		// The volume was allocated a device previously, but
+		// creation was not recorded in state.
+		device, err := findDevice(devices, deviceId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return deviceVolume(arg.Tag, device), nil
+	}
+
+	filter, err := parseDevicesAttr(arg.Attributes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var candidates []storage.BlockDevice
+	for _, device := range devices {
+		if device.InUse || device.Size < arg.Size {
+			continue
+		}
+		if claimed.Contains(deviceId(device)) || !matchDevice(device, filter) {
+			continue
+		}
+		candidates = append(candidates, device)
+	}
+	if len(candidates) == 0 {
+		return nil, errors.Errorf(
+			"no unused block device of at least %dMiB matching %q",
+			arg.Size, strings.Join(filter, ","),
+		)
+	}
+	// Allocate the smallest device that satisfies the request, to
+	// leave larger devices for larger requests.
+	sort.Sort(bySize(candidates))
+	device := candidates[0]
+	if err := s.claim(arg.Tag, deviceId(device)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	claims[arg.Tag] = deviceId(device)
+	claimed.Add(deviceId(device))
+	return deviceVolume(arg.Tag, device), nil
+}
+
+func deviceVolume(tag names.VolumeTag, device storage.BlockDevice) *storage.Volume {
+	return &storage.Volume{
+		tag,
+		storage.VolumeInfo{
+			VolumeId:   deviceId(device),
+			HardwareId: device.HardwareId,
+			WWN:        device.WWN,
+			Size:       device.Size,
+			Persistent: true,
+		},
+	}
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (s *deviceVolumeSource) ListVolumes() ([]string, error) {
+	claims, err := s.claims()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeIds := make([]string, 0, len(claims))
+	for _, deviceId := range claims {
+		volumeIds = append(volumeIds, deviceId)
+	}
+	sort.Strings(volumeIds)
+	return volumeIds, nil
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (s *deviceVolumeSource) DescribeVolumes(volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	devices, err := s.listBlockDevices()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing block devices")
+	}
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		device, err := findDevice(devices, volumeId)
+		if err != nil {
+			results[i].Error = errors.Trace(err)
+			continue
+		}
+		results[i].VolumeInfo = &deviceVolume(names.VolumeTag{}, device).VolumeInfo
+	}
+	return results, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+//
+// The start of each block device is zeroed, removing any partition
+// table and filesystem signatures, before it is made available for
+// allocation to other volumes.
+func (s *deviceVolumeSource) DestroyVolumes(volumeIds []string) ([]error, error) {
+	devices, err := s.listBlockDevices()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing block devices")
+	}
+	results := make([]error, len(volumeIds))
+	var wiped []string
+	var wipedIndexes []int
+	for i, volumeId := range volumeIds {
+		device, err := findDevice(devices, volumeId)
+		if err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+			continue
+		}
+		if err := s.wipeDevice(device); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+			continue
+		}
+		wiped = append(wiped, volumeId)
+		wipedIndexes = append(wipedIndexes, i)
+	}
+	releaseResults, err := s.releaseVolumes(wiped)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for i, err := range releaseResults {
+		results[wipedIndexes[i]] = err
+	}
+	return results, nil
+}
+
+// wipeDevice zeroes the first MiB of the block device.
+func (s *deviceVolumeSource) wipeDevice(device storage.BlockDevice) error {
+	_, err := s.run(
+		"dd", "if=/dev/zero", "of=/dev/"+device.DeviceName,
+		"bs=1M", "count=1", "conv=fsync",
+	)
+	return errors.Annotatef(err, "wiping block device %q", device.DeviceName)
+}
+
+// ReleaseVolumes is defined on the VolumeSource interface.
+func (s *deviceVolumeSource) ReleaseVolumes(volumeIds []string) ([]error, error) {
+	return s.releaseVolumes(volumeIds)
+}
+
+func (s *deviceVolumeSource) releaseVolumes(volumeIds []string) ([]error, error) {
+	claims, err := s.claims()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		for tag, deviceId := range claims {
+			if deviceId != volumeId {
+				continue
+			}
+			err := os.Remove(s.claimPath(tag))
+			if err != nil && !os.IsNotExist(err) {
+				results[i] = errors.Annotatef(err, "releasing %q", volumeId)
+			}
+		}
+	}
+	return results, nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (s *deviceVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	_, err := parseDevicesAttr(params.Attributes)
+	return errors.Trace(err)
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+//
+// The block devices are already attached to the machine, so
+// attaching just reports how the device is exposed.
+func (s *deviceVolumeSource) AttachVolumes(args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	devices, err := s.listBlockDevices()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing block devices")
+	}
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		device, err := findDevice(devices, arg.VolumeId)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+			continue
+		}
+		info := storage.VolumeAttachmentInfo{ReadOnly: arg.ReadOnly}
+		if device.HardwareId == "" && device.WWN == "" {
+			// There is no stable identifier for the block
+			// device, so fall back to the device name.
+			info.DeviceName = device.DeviceName
+		}
+		results[i].VolumeAttachment = &storage.VolumeAttachment{
+			arg.Volume,
+			arg.Machine,
+			info,
+		}
+	}
+	return results, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (s *deviceVolumeSource) DetachVolumes(args []storage.VolumeAttachmentParams) ([]error, error) {
+	// The block devices remain attached to the machine.
+	return make([]error, len(args)), nil
+}
+
+// claims returns the IDs of the claimed block devices, keyed by the
+// tag of the volume they are allocated to.
+func (s *deviceVolumeSource) claims() (map[names.VolumeTag]string, error) {
+	infos, err := ioutil.ReadDir(s.claimsDir)
+	if os.IsNotExist(err) {
+		return map[names.VolumeTag]string{}, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "reading block device claims")
+	}
+	claims := make(map[names.VolumeTag]string, len(infos))
+	for _, info := range infos {
+		tag, err := names.ParseVolumeTag(info.Name())
+		if err != nil {
+			logger.Warningf("ignoring unexpected file %q in %s", info.Name(), s.claimsDir)
+			continue
+		}
+		data, err := ioutil.ReadFile(s.claimPath(tag))
+		if err != nil {
+			return nil, errors.Annotatef(err, "reading block device claim for %s", names.ReadableString(tag))
+		}
+		claims[tag] = strings.TrimSpace(string(data))
+	}
+	return claims, nil
+}
+
+func (s *deviceVolumeSource) claim(tag names.VolumeTag, deviceId string) error {
+	if err := ensureDir(s.dirFuncs, s.claimsDir); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(s.claimPath(tag), []byte(deviceId), 0644); err != nil {
+		return errors.Annotatef(err, "claiming block device %q", deviceId)
+	}
+	return nil
+}
+
+func (s *deviceVolumeSource) claimPath(tag names.VolumeTag) string {
+	return filepath.Join(s.claimsDir, tag.String())
+}
+
+// parseDevicesAttr parses the devices attribute from the given
+// pool or volume attributes.
+func parseDevicesAttr(attrs map[string]interface{}) ([]string, error) {
+	value, ok := attrs[DevicesAttr]
+	if !ok {
+		return nil, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.Errorf("expected string for %q, got %T", DevicesAttr, value)
+	}
+	var devices []string
+	for _, device := range strings.Split(s, ",") {
+		if device = strings.TrimSpace(device); device != "" {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// deviceId returns the ID used to identify the block device, which is
+// the most stable of its identifiers.
+func deviceId(device storage.BlockDevice) string {
+	switch {
+	case device.WWN != "":
+		return device.WWN
+	case device.HardwareId != "":
+		return device.HardwareId
+	}
+	return device.DeviceName
+}
+
+// matchDevice reports whether any of the block device's
+// identifiers is in the given list.
+func matchDevice(device storage.BlockDevice, ids []string) bool {
+	deviceIds := set.NewStrings(device.DeviceLinks...)
+	deviceIds.Add(device.DeviceName)
+	deviceIds.Add("/dev/" + device.DeviceName)
+	if device.HardwareId != "" {
+		deviceIds.Add(device.HardwareId)
+	}
+	if device.WWN != "" {
+		deviceIds.Add(device.WWN)
+	}
+	for _, id := range ids {
+		if deviceIds.Contains(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// findDevice returns the block device with the given ID.
+func findDevice(devices []storage.BlockDevice, id string) (storage.BlockDevice, error) {
+	for _, device := range devices {
+		if deviceId(device) == id {
+			return device, nil
+		}
+	}
+	return storage.BlockDevice{}, errors.NotFoundf("block device %q", id)
+}
+
+type bySize []storage.BlockDevice
+
+func (s bySize) Len() int      { return len(s) }
+func (s bySize) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s bySize) Less(i, j int) bool {
+	if s[i].Size != s[j].Size {
+		return s[i].Size < s[j].Size
+	}
+	return deviceId(s[i]) < deviceId(s[j])
+}
@@ -0,0 +1,302 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&deviceSuite{})
+
+type deviceSuite struct {
+	testing.BaseSuite
+	storageDir   string
+	blockDevices []storage.BlockDevice
+	commands     *mockRunCommand
+}
+
+func (s *deviceSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.storageDir = c.MkDir()
+	s.commands = &mockRunCommand{c: c}
+	s.blockDevices = []storage.BlockDevice{{
+		DeviceName: "sda",
+		Size:       1024 * 100,
+		InUse:      true,
+	}, {
+		DeviceName: "sdb",
+		HardwareId: "ata-sdb",
+		Size:       1024 * 20,
+	}, {
+		DeviceName: "sdc",
+		WWN:        "0x5000c500",
+		Size:       1024 * 10,
+	}, {
+		DeviceName:  "sdd",
+		DeviceLinks: []string{"/dev/disk/by-id/wwn-sdd"},
+		Size:        1024 * 30,
+	}}
+}
+
+func (s *deviceSuite) listBlockDevices() ([]storage.BlockDevice, error) {
+	return s.blockDevices, nil
+}
+
+func (s *deviceSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *deviceSuite) volumeSource(c *gc.C) storage.VolumeSource {
+	p := provider.DeviceProvider(s.listBlockDevices, s.commands.run)
+	cfg, err := storage.NewConfig("device", provider.DeviceProviderType, map[string]interface{}{
+		"storage-dir": s.storageDir,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+func volumeParams(id string, size uint64, devices string) storage.VolumeParams {
+	return storage.VolumeParams{
+		Tag:        names.NewVolumeTag(id),
+		Size:       size,
+		Provider:   provider.DeviceProviderType,
+		Attributes: map[string]interface{}{"devices": devices},
+	}
+}
+
+func (s *deviceSuite) TestValidateConfig(c *gc.C) {
+	p := provider.NewDeviceProvider(nil)
+	cfg, err := storage.NewConfig("name", provider.DeviceProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `"devices" must be specified`)
+
+	cfg, err = storage.NewConfig("name", provider.DeviceProviderType, map[string]interface{}{
+		"devices": 123,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `expected string for "devices", got int`)
+
+	cfg, err = storage.NewConfig("name", provider.DeviceProviderType, map[string]interface{}{
+		"devices": "sdb, sdc",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.ValidateConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *deviceSuite) TestVolumeSource(c *gc.C) {
+	cfg, err := storage.NewConfig("name", provider.DeviceProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = provider.NewDeviceProvider(nil).VolumeSource(cfg)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = provider.NewDeviceProvider(s.listBlockDevices).VolumeSource(cfg)
+	c.Assert(err, gc.ErrorMatches, "storage directory not specified")
+}
+
+func (s *deviceSuite) TestProviderProperties(c *gc.C) {
+	p := provider.NewDeviceProvider(nil)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+	c.Assert(p.Releasable(), jc.IsTrue)
+	c.Assert(p.DefaultPools(), gc.HasLen, 0)
+}
+
+func (s *deviceSuite) TestCreateVolumes(c *gc.C) {
+	source := s.volumeSource(c)
+	results, err := source.CreateVolumes([]storage.VolumeParams{
+		volumeParams("0", 1024*5, "sda,sdb,sdc,sdd"),
+		volumeParams("1", 1024*5, "sda,sdb,sdc,sdd"),
+		volumeParams("2", 1024*25, "/dev/disk/by-id/wwn-sdd"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	for _, result := range results {
+		c.Assert(result.Error, jc.ErrorIsNil)
+	}
+	// The smallest matching unused devices are allocated
+	// first; sda is in use, so it is never allocated.
+	c.Assert(results[0].Volume.VolumeInfo, jc.DeepEquals, storage.VolumeInfo{
+		VolumeId:   "0x5000c500",
+		WWN:        "0x5000c500",
+		Size:       1024 * 10,
+		Persistent: true,
+	})
+	c.Assert(results[1].Volume.VolumeInfo, jc.DeepEquals, storage.VolumeInfo{
+		VolumeId:   "ata-sdb",
+		HardwareId: "ata-sdb",
+		Size:       1024 * 20,
+		Persistent: true,
+	})
+	c.Assert(results[2].Volume.VolumeInfo, jc.DeepEquals, storage.VolumeInfo{
+		VolumeId:   "sdd",
+		Size:       1024 * 30,
+		Persistent: true,
+	})
+
+	data, err := ioutil.ReadFile(filepath.Join(s.storageDir, "claims", "volume-0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "0x5000c500")
+
+	volumeIds, err := source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{"0x5000c500", "ata-sdb", "sdd"})
+}
+
+func (s *deviceSuite) TestCreateVolumesNoMatchingDevice(c *gc.C) {
+	source := s.volumeSource(c)
+	results, err := source.CreateVolumes([]storage.VolumeParams{
+		volumeParams("0", 1024*5, "sda"),
+		volumeParams("1", 1024*50, "sdb,sdc,sdd"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		`creating volume: no unused block device of at least 5120MiB matching "sda"`,
+	)
+	c.Assert(results[1].Error, gc.ErrorMatches,
+		`creating volume: no unused block device of at least 51200MiB matching "sdb,sdc,sdd"`,
+	)
+}
+
+func (s *deviceSuite) TestCreateVolumesClaimed(c *gc.C) {
+	source := s.volumeSource(c)
+	results, err := source.CreateVolumes([]storage.VolumeParams{
+		volumeParams("0", 1024, "sdc"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	// Creating the same volume again returns the device
+	// previously allocated, but other volumes cannot have it.
+	results, err = source.CreateVolumes([]storage.VolumeParams{
+		volumeParams("0", 1024, "sdc"),
+		volumeParams("1", 1024, "sdc"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "0x5000c500")
+	c.Assert(results[1].Error, gc.ErrorMatches, "creating volume: no unused block device .*")
+
+	// Once the volume is destroyed, the device is wiped and can be
+	// allocated again.
+	s.commands.expect("dd", "if=/dev/zero", "of=/dev/sdc", "bs=1M", "count=1", "conv=fsync")
+	errs, err := source.DestroyVolumes([]string{"0x5000c500"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+	results, err = source.CreateVolumes([]storage.VolumeParams{
+		volumeParams("1", 1024, "sdc"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "0x5000c500")
+}
+
+func (s *deviceSuite) TestDestroyVolumesWipeFails(c *gc.C) {
+	source := s.volumeSource(c)
+	results, err := source.CreateVolumes([]storage.VolumeParams{
+		volumeParams("0", 1024, "sdc"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	// A device that cannot be wiped is not released.
+	s.commands.expect("dd", "if=/dev/zero", "of=/dev/sdc", "bs=1M", "count=1", "conv=fsync").respond("", errors.New("oops"))
+	errs, err := source.DestroyVolumes([]string{"0x5000c500"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], gc.ErrorMatches, `destroying "0x5000c500": wiping block device "sdc": oops`)
+	volumeIds, err := source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{"0x5000c500"})
+}
+
+func (s *deviceSuite) TestReleaseVolumesDoesNotWipe(c *gc.C) {
+	source := s.volumeSource(c)
+	results, err := source.CreateVolumes([]storage.VolumeParams{
+		volumeParams("0", 1024, "sdc"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	errs, err := source.ReleaseVolumes([]string{"0x5000c500"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+	volumeIds, err := source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, gc.HasLen, 0)
+}
+
+func (s *deviceSuite) TestDescribeVolumes(c *gc.C) {
+	source := s.volumeSource(c)
+	results, err := source.DescribeVolumes([]string{"ata-sdb", "sdz"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId:   "ata-sdb",
+		HardwareId: "ata-sdb",
+		Size:       1024 * 20,
+		Persistent: true,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `block device "sdz" not found`)
+}
+
+func (s *deviceSuite) TestAttachVolumes(c *gc.C) {
+	source := s.volumeSource(c)
+	machine := names.NewMachineTag("0")
+	results, err := source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{Machine: machine},
+		Volume:           names.NewVolumeTag("0"),
+		VolumeId:         "ata-sdb",
+	}, {
+		AttachmentParams: storage.AttachmentParams{Machine: machine, ReadOnly: true},
+		Volume:           names.NewVolumeTag("1"),
+		VolumeId:         "sdd",
+	}, {
+		AttachmentParams: storage.AttachmentParams{Machine: machine},
+		Volume:           names.NewVolumeTag("2"),
+		VolumeId:         "sdz",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment, jc.DeepEquals, &storage.VolumeAttachment{
+		names.NewVolumeTag("0"), machine, storage.VolumeAttachmentInfo{},
+	})
+	c.Assert(results[1].Error, jc.ErrorIsNil)
+	c.Assert(results[1].VolumeAttachment, jc.DeepEquals, &storage.VolumeAttachment{
+		names.NewVolumeTag("1"), machine, storage.VolumeAttachmentInfo{
+			DeviceName: "sdd",
+			ReadOnly:   true,
+		},
+	})
+	c.Assert(results[2].Error, gc.ErrorMatches, `attaching volume 2: block device "sdz" not found`)
+}
+
+func (s *deviceSuite) TestDetachVolumes(c *gc.C) {
+	source := s.volumeSource(c)
+	errs, err := source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "ata-sdb",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
@@ -27,6 +27,13 @@ func LoopVolumeSource(
 	return &loopVolumeSource{dirFuncs, run, storageDir}, dirFuncs
 }
 
+func DeviceProvider(
+	listBlockDevices ListBlockDevicesFunc,
+	run func(string, ...string) (string, error),
+) storage.Provider {
+	return &deviceProvider{listBlockDevices, run}
+}
+
 func LoopProvider(
 	run func(string, ...string) (string, error),
 ) storage.Provider {
@@ -50,6 +50,7 @@ func (s *JujuOSEnvSuite) SetUpTest(c *gc.C) {
 		osenv.JujuModelEnvKey,
 		osenv.JujuLoggingConfigEnvKey,
 		osenv.JujuFeatureFlagEnvKey,
+		osenv.JujuTokenEnvKey,
 		osenv.XDGDataHome,
 	} {
 		s.oldEnvironment[name] = os.Getenv(name)
@@ -26,3 +26,7 @@ var LongAttempt = &utils.AttemptStrategy{
 	Total: LongWait,
 	Delay: ShortWait,
 }
+
+// SecretsKey is the key that charm secrets are encrypted with in
+// the states opened by tests.
+var SecretsKey = []byte("juju-testing-secrets-key-3214579")
@@ -107,6 +107,7 @@ type Client struct {
 	*imageClient
 	*networkClient
 	*storageClient
+	*clusterClient
 	baseURL                  string
 	defaultProfileBridgeName string
 }
@@ -135,6 +136,7 @@ func Connect(cfg Config, verifyBridgeConfig bool) (*Client, error) {
 
 	networkAPISupported := false
 	storageAPISupported := false
+	clusterAPISupported := false
 	var defaultProfile *api.Profile
 	if cfg.Remote.Protocol != SimplestreamsProtocol {
 		status, err := raw.ServerStatus()
@@ -150,6 +152,10 @@ func Connect(cfg Config, verifyBridgeConfig bool) (*Client, error) {
 			storageAPISupported = true
 		}
 
+		if lxdshared.StringInSlice("clustering", status.APIExtensions) {
+			clusterAPISupported = true
+		}
+
 		defaultProfile, err = raw.ProfileConfig("default")
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -175,14 +181,16 @@ func Connect(cfg Config, verifyBridgeConfig bool) (*Client, error) {
 		}
 	}
 
+	clusterRaw := clusterRawClient{raw}
 	conn := &Client{
 		configClient:             &configClient{raw},
 		certClient:               &certClient{raw},
 		profileClient:            &profileClient{raw},
-		instanceClient:           &instanceClient{raw, remoteID},
+		instanceClient:           &instanceClient{clusterRaw, remoteID},
 		imageClient:              &imageClient{raw, connectToRaw},
 		networkClient:            &networkClient{raw, networkAPISupported},
 		storageClient:            &storageClient{raw, storageAPISupported},
+		clusterClient:            &clusterClient{clusterRaw, clusterAPISupported},
 		baseURL:                  raw.BaseURL,
 		defaultProfileBridgeName: bridgeName,
 	}
@@ -0,0 +1,198 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxdclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/lxc/lxd"
+	"github.com/lxc/lxd/shared/api"
+)
+
+// ClusterMemberOnline is the status reported by LXD for a cluster
+// member that is up and able to host containers.
+const ClusterMemberOnline = "Online"
+
+// ClusterMember describes a single member of an LXD cluster.
+type ClusterMember struct {
+	// Name is the name of the cluster member. It is used to target
+	// the member when creating containers.
+	Name string `json:"server_name"`
+
+	// URL is the address the member's API is served on.
+	URL string `json:"url"`
+
+	// Status is the member's status, e.g. "Online" or "Offline".
+	Status string `json:"status"`
+
+	// Message holds any additional information about the status.
+	Message string `json:"message"`
+}
+
+type rawClusterClient interface {
+	ClusterEnabled() (bool, error)
+	ClusterMembers() ([]ClusterMember, error)
+	ContainerLocations() (map[string]string, error)
+}
+
+type clusterClient struct {
+	raw       rawClusterClient
+	supported bool
+}
+
+// IsClustered reports whether the LXD remote is a member of an
+// LXD cluster.
+func (c *clusterClient) IsClustered() (bool, error) {
+	if !c.supported {
+		return false, nil
+	}
+	enabled, err := c.raw.ClusterEnabled()
+	if err != nil {
+		return false, errors.Annotate(err, "querying cluster status")
+	}
+	return enabled, nil
+}
+
+// ClusterMembers returns the members of the LXD cluster that the
+// remote belongs to.
+func (c *clusterClient) ClusterMembers() ([]ClusterMember, error) {
+	if !c.supported {
+		return nil, errors.NotSupportedf("clustering API on this remote")
+	}
+	members, err := c.raw.ClusterMembers()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing cluster members")
+	}
+	return members, nil
+}
+
+// ContainerLocations returns the name of the cluster member hosting
+// each container, keyed by container name.
+func (c *clusterClient) ContainerLocations() (map[string]string, error) {
+	if !c.supported {
+		return nil, errors.NotSupportedf("clustering API on this remote")
+	}
+	locations, err := c.raw.ContainerLocations()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing container locations")
+	}
+	return locations, nil
+}
+
+// clusterRawClient extends the LXD API client with the clustering
+// endpoints, which the vendored LXD client predates.
+type clusterRawClient struct {
+	*lxd.Client
+}
+
+// ClusterEnabled reports whether clustering is enabled on the server.
+func (c clusterRawClient) ClusterEnabled() (bool, error) {
+	var cluster struct {
+		Enabled bool `json:"enabled"`
+	}
+	if _, err := c.query("GET", "/1.0/cluster", nil, &cluster); err != nil {
+		return false, errors.Trace(err)
+	}
+	return cluster.Enabled, nil
+}
+
+// ClusterMembers returns all members of the cluster.
+func (c clusterRawClient) ClusterMembers() ([]ClusterMember, error) {
+	var members []ClusterMember
+	if _, err := c.query("GET", "/1.0/cluster/members?recursion=1", nil, &members); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return members, nil
+}
+
+// ContainerLocations returns the cluster member each container is
+// located on, keyed by container name.
+func (c clusterRawClient) ContainerLocations() (map[string]string, error) {
+	var containers []struct {
+		Name     string `json:"name"`
+		Location string `json:"location"`
+	}
+	if _, err := c.query("GET", "/1.0/containers?recursion=1", nil, &containers); err != nil {
+		return nil, errors.Trace(err)
+	}
+	locations := make(map[string]string, len(containers))
+	for _, container := range containers {
+		locations[container.Name] = container.Location
+	}
+	return locations, nil
+}
+
+// InitOnTarget creates a container from a local image on the named
+// cluster member.
+func (c clusterRawClient) InitOnTarget(
+	target, name, image string,
+	profiles *[]string,
+	config map[string]string,
+	devices map[string]map[string]string,
+	ephem bool,
+) (*api.Response, error) {
+	req := api.ContainersPost{
+		Name: name,
+		Source: api.ContainerSource{
+			Type:  "image",
+			Alias: image,
+		},
+	}
+	req.Config = config
+	req.Devices = devices
+	req.Ephemeral = ephem
+	if profiles != nil {
+		req.Profiles = *profiles
+	}
+	path := "/1.0/containers?target=" + url.QueryEscape(target)
+	resp, err := c.query("POST", path, req, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "creating container %q on cluster member %q", name, target)
+	}
+	return resp, nil
+}
+
+// query sends a request to the LXD API and decodes the response
+// metadata into out, if it is non-nil.
+func (c clusterRawClient) query(method, path string, in, out interface{}) (*api.Response, error) {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	req, err := http.NewRequest(method, c.BaseURL+path, &body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	httpResp, err := c.Http.Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer httpResp.Body.Close()
+
+	var resp api.Response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, errors.Annotatef(err, "decoding response to %s %s", method, path)
+	}
+	if resp.Type == api.ErrorResponse {
+		if httpResp.StatusCode == http.StatusNotFound {
+			return nil, errors.NotFoundf("%s", path)
+		}
+		return nil, errors.New(resp.Error)
+	}
+	if out != nil {
+		if err := json.Unmarshal(resp.Metadata, out); err != nil {
+			return nil, errors.Annotatef(err, "decoding response to %s %s", method, path)
+		}
+	}
+	return &resp, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxdclient_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/tools/lxdclient"
+)
+
+type ClusterClientSuite struct {
+	testing.IsolationSuite
+
+	raw *mockRawClusterClient
+}
+
+var _ = gc.Suite(&ClusterClientSuite{})
+
+func (s *ClusterClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.raw = &mockRawClusterClient{
+		enabled: true,
+		members: []lxdclient.ClusterMember{{
+			Name:   "node1",
+			URL:    "https://10.0.0.1:8443",
+			Status: lxdclient.ClusterMemberOnline,
+		}, {
+			Name:   "node2",
+			URL:    "https://10.0.0.2:8443",
+			Status: "Offline",
+		}},
+	}
+}
+
+func (s *ClusterClientSuite) TestIsClustered(c *gc.C) {
+	client := lxdclient.NewClusterClient(s.raw, true)
+	clustered, err := client.IsClustered()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clustered, jc.IsTrue)
+	s.raw.CheckCallNames(c, "ClusterEnabled")
+}
+
+func (s *ClusterClientSuite) TestIsClusteredNotEnabled(c *gc.C) {
+	s.raw.enabled = false
+	client := lxdclient.NewClusterClient(s.raw, true)
+	clustered, err := client.IsClustered()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clustered, jc.IsFalse)
+}
+
+func (s *ClusterClientSuite) TestIsClusteredError(c *gc.C) {
+	s.raw.SetErrors(errors.New("boom"))
+	client := lxdclient.NewClusterClient(s.raw, true)
+	_, err := client.IsClustered()
+	c.Assert(err, gc.ErrorMatches, "querying cluster status: boom")
+}
+
+func (s *ClusterClientSuite) TestClusterNotSupported(c *gc.C) {
+	client := lxdclient.NewClusterClient(s.raw, false)
+	clustered, err := client.IsClustered()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clustered, jc.IsFalse)
+
+	_, err = client.ClusterMembers()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	s.raw.CheckNoCalls(c)
+}
+
+func (s *ClusterClientSuite) TestClusterMembers(c *gc.C) {
+	client := lxdclient.NewClusterClient(s.raw, true)
+	members, err := client.ClusterMembers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(members, jc.DeepEquals, s.raw.members)
+	s.raw.CheckCallNames(c, "ClusterMembers")
+}
+
+func (s *ClusterClientSuite) TestClusterMembersError(c *gc.C) {
+	s.raw.SetErrors(errors.New("boom"))
+	client := lxdclient.NewClusterClient(s.raw, true)
+	_, err := client.ClusterMembers()
+	c.Assert(err, gc.ErrorMatches, "listing cluster members: boom")
+}
+
+func (s *ClusterClientSuite) TestContainerLocations(c *gc.C) {
+	s.raw.locations = map[string]string{"juju-machine-0": "node1"}
+	client := lxdclient.NewClusterClient(s.raw, true)
+	locations, err := client.ContainerLocations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(locations, jc.DeepEquals, map[string]string{"juju-machine-0": "node1"})
+	s.raw.CheckCallNames(c, "ContainerLocations")
+}
+
+func (s *ClusterClientSuite) TestContainerLocationsNotSupported(c *gc.C) {
+	client := lxdclient.NewClusterClient(s.raw, false)
+	_, err := client.ContainerLocations()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	s.raw.CheckNoCalls(c)
+}
+
+type mockRawClusterClient struct {
+	testing.Stub
+	enabled   bool
+	members   []lxdclient.ClusterMember
+	locations map[string]string
+}
+
+func (c *mockRawClusterClient) ClusterEnabled() (bool, error) {
+	c.MethodCall(c, "ClusterEnabled")
+	return c.enabled, c.NextErr()
+}
+
+func (c *mockRawClusterClient) ClusterMembers() ([]lxdclient.ClusterMember, error) {
+	c.MethodCall(c, "ClusterMembers")
+	return c.members, c.NextErr()
+}
+
+func (c *mockRawClusterClient) ContainerLocations() (map[string]string, error) {
+	c.MethodCall(c, "ContainerLocations")
+	return c.locations, c.NextErr()
+}
@@ -34,6 +34,7 @@ type rawInstanceClient interface {
 	ListContainers() ([]api.Container, error)
 	ContainerInfo(name string) (*api.Container, error)
 	Init(name string, imgremote string, image string, profiles *[]string, config map[string]string, devices map[string]map[string]string, ephem bool) (*api.Response, error)
+	InitOnTarget(target string, name string, image string, profiles *[]string, config map[string]string, devices map[string]map[string]string, ephem bool) (*api.Response, error)
 	Action(name string, action shared.ContainerAction, timeout int, force bool, stateful bool) (*api.Response, error)
 	Delete(name string) (*api.Response, error)
 
@@ -74,7 +75,15 @@ func (client *instanceClient) addInstance(spec InstanceSpec) error {
 	}
 
 	config := spec.config()
-	resp, err := client.raw.Init(spec.Name, imageRemote, imageAlias, profiles, config, lxdDevices, spec.Ephemeral)
+	var resp *api.Response
+	var err error
+	if spec.Target != "" {
+		// Cluster members share their images, so the image is
+		// always sourced locally when targeting a member.
+		resp, err = client.raw.InitOnTarget(spec.Target, spec.Name, imageAlias, profiles, config, lxdDevices, spec.Ephemeral)
+	} else {
+		resp, err = client.raw.Init(spec.Name, imageRemote, imageAlias, profiles, config, lxdDevices, spec.Ephemeral)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
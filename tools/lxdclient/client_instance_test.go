@@ -256,3 +256,42 @@ func (s *devicesSuite) TestRemoveDeviceAsyncError(c *gc.C) {
 	err := client.RemoveDevice("instance", "device")
 	c.Assert(err, gc.ErrorMatches, "async error")
 }
+
+type addInstanceSuite struct {
+	lxdclient.BaseSuite
+}
+
+var _ = gc.Suite(&addInstanceSuite{})
+
+func (s *addInstanceSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.Client.Response = &lxdapi.Response{Operation: "/1.0/operations/op"}
+}
+
+func (s *addInstanceSuite) TestAddInstance(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	_, err := client.AddInstance(lxdclient.InstanceSpec{
+		Name:  "juju-machine-0",
+		Image: "ubuntu-xenial",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCallNames(c, "Init", "WaitForSuccess", "Action", "WaitForSuccess", "ContainerInfo")
+}
+
+func (s *addInstanceSuite) TestAddInstanceOnTarget(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	_, err := client.AddInstance(lxdclient.InstanceSpec{
+		Name:     "juju-machine-0",
+		Image:    "ubuntu-xenial",
+		Profiles: []string{"default"},
+		Target:   "node2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCallNames(c, "InitOnTarget", "WaitForSuccess", "Action", "WaitForSuccess", "ContainerInfo")
+	profiles := []string{"default"}
+	s.Stub.CheckCall(c, 0, "InitOnTarget",
+		"node2", "juju-machine-0", "ubuntu-xenial", &profiles,
+		map[string]string{}, map[string]map[string]string{}, false,
+	)
+	s.Stub.CheckCall(c, 1, "WaitForSuccess", "/1.0/operations/op")
+}
@@ -14,6 +14,7 @@ var NewInstanceSummary = newInstanceSummary
 type (
 	RawInstanceClient rawInstanceClient
 	RawStorageClient  rawStorageClient
+	RawClusterClient  rawClusterClient
 )
 
 func NewInstanceClient(raw RawInstanceClient) *instanceClient {
@@ -30,6 +31,13 @@ func NewStorageClient(raw RawStorageClient, supported bool) *storageClient {
 	}
 }
 
+func NewClusterClient(raw RawClusterClient, supported bool) *clusterClient {
+	return &clusterClient{
+		raw:       raw,
+		supported: supported,
+	}
+}
+
 func PatchGenerateCertificate(s *testing.CleanupSuite, cert, key string) {
 	s.PatchValue(&generateCertificate, func() ([]byte, []byte, error) {
 		return []byte(cert), []byte(key), nil
@@ -106,6 +106,11 @@ type InstanceSpec struct {
 	// Devices to be added at container initialisation time.
 	Devices
 
+	// Target is the name of the cluster member to create the
+	// container on. It is only meaningful for clustered remotes;
+	// if empty, LXD chooses the member.
+	Target string
+
 	// TODO(ericsnow) Other possible fields:
 	// Disks
 	// Networks
@@ -116,6 +116,15 @@ func (s *stubClient) Init(name, remote, image string, profiles *[]string, config
 	return s.Response, nil
 }
 
+func (s *stubClient) InitOnTarget(target, name, image string, profiles *[]string, config map[string]string, devices map[string]map[string]string, ephem bool) (*api.Response, error) {
+	s.stub.AddCall("InitOnTarget", target, name, image, profiles, config, devices, ephem)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.Response, nil
+}
+
 func (s *stubClient) Delete(name string) (*api.Response, error) {
 	s.stub.AddCall("Delete", name)
 	if err := s.stub.NextErr(); err != nil {
@@ -0,0 +1,45 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxy
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+)
+
+// ParseControllerProxy parses the URL of a proxy through which
+// connections to the controller should be made. Supported schemes
+// are "http", for proxies supporting the HTTP CONNECT method, and
+// "socks5". The empty string yields a nil URL.
+func ParseControllerProxy(s string) (*url.URL, error) {
+	if s == "" {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.NotValidf("controller proxy %q", s)
+	}
+	if err := ValidateControllerProxy(proxyURL); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return proxyURL, nil
+}
+
+// ValidateControllerProxy checks that the given controller proxy URL
+// has a supported scheme and a host. A nil URL is valid.
+func ValidateControllerProxy(proxyURL *url.URL) error {
+	if proxyURL == nil {
+		return nil
+	}
+	switch proxyURL.Scheme {
+	case "http", "socks5":
+	default:
+		return errors.NotValidf("controller proxy scheme %q", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return errors.NotValidf("controller proxy %q without host", proxyURL)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package apicaller
 
 import (
+	"net/url"
 	"time"
 
 	"github.com/juju/errors"
@@ -14,6 +15,7 @@ import (
 	apiagent "github.com/juju/juju/api/agent"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/utils/proxy"
 )
 
 var (
@@ -51,7 +53,11 @@ func OnlyConnect(a agent.Agent, apiOpen api.OpenFunc) (api.Connection, error) {
 	if !ok {
 		return nil, errors.New("API info not available")
 	}
-	conn, _, err := connectFallback(apiOpen, info, agentConfig.OldPassword())
+	controllerProxy, err := proxy.ParseControllerProxy(agentConfig.Value(agent.ControllerProxy))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	conn, _, err := connectFallback(apiOpen, info, agentConfig.OldPassword(), controllerProxy)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -59,7 +65,8 @@ func OnlyConnect(a agent.Agent, apiOpen api.OpenFunc) (api.Connection, error) {
 }
 
 // connectFallback opens an API connection using the supplied info,
-// or a copy using the fallbackPassword; blocks for up to 5 minutes
+// or a copy using the fallbackPassword, through controllerProxy if
+// it is not nil; blocks for up to 5 minutes
 // if it encounters a CodeNotProvisioned error, periodically retrying;
 // and eventually, having either succeeded, failed, or timed out, returns:
 //
@@ -78,7 +85,7 @@ func OnlyConnect(a agent.Agent, apiOpen api.OpenFunc) (api.Connection, error) {
 // until it's managed to log in, and any suicide-cutoff point we pick here
 // will be objectively bad in some circumstances.)
 func connectFallback(
-	apiOpen api.OpenFunc, info *api.Info, fallbackPassword string,
+	apiOpen api.OpenFunc, info *api.Info, fallbackPassword string, controllerProxy *url.URL,
 ) (
 	conn api.Connection, didFallback bool, err error,
 ) {
@@ -100,7 +107,8 @@ func connectFallback(
 			// If the server is rate limiting, it will normally pause
 			// before responding to the login request, but the pause is
 			// in the realm of five to ten seconds.
-			Timeout: time.Minute,
+			Timeout:         time.Minute,
+			ControllerProxy: controllerProxy,
 		})
 	}
 
@@ -176,6 +184,10 @@ func ScaryConnect(a agent.Agent, apiOpen api.OpenFunc) (_ api.Connection, err er
 		return nil, errors.New("API info not available")
 	}
 	oldPassword := agentConfig.OldPassword()
+	controllerProxy, err := proxy.ParseControllerProxy(agentConfig.Value(agent.ControllerProxy))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	defer func() {
 		cause := errors.Cause(err)
@@ -191,7 +203,7 @@ func ScaryConnect(a agent.Agent, apiOpen api.OpenFunc) (_ api.Connection, err er
 	}()
 
 	// Start connection...
-	conn, usedOldPassword, err := connectFallback(apiOpen, info, oldPassword)
+	conn, usedOldPassword, err := connectFallback(apiOpen, info, oldPassword, controllerProxy)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -227,6 +239,12 @@ func ScaryConnect(a agent.Agent, apiOpen api.OpenFunc) (_ api.Connection, err er
 		return nil, errors.Errorf("unknown life value %q", life)
 	}
 
+	// Keep trusting the controller while its CA is rotated, by
+	// recording the CA certificate it reports in local config.
+	if err := updateCACert(a, conn); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// If we need to change the password, it's far cleaner to
 	// exit with ErrChangedPassword and depend on the framework
 	// for expeditious retry than it is to mess around with those
@@ -277,6 +295,23 @@ func changePassword(oldPassword string, a agent.Agent, facade apiagent.ConnFacad
 	return facade.SetPassword(a.CurrentConfig().Tag(), newPassword)
 }
 
+// updateCACert records the controller CA certificate reported by the
+// connection in local agent configuration, if it has changed. While
+// the controller CA is being rotated the certificate is a bundle of the
+// old and new CAs, and the agent must trust both before the controller
+// retires the old one.
+func updateCACert(a agent.Agent, conn api.Connection) error {
+	caCert := conn.ControllerCACert()
+	if caCert == "" || caCert == a.CurrentConfig().CACert() {
+		return nil
+	}
+	logger.Infof("controller CA certificate changed")
+	return a.ChangeConfig(func(c agent.ConfigSetter) error {
+		c.SetCACert(caCert)
+		return nil
+	})
+}
+
 // NewExternalControllerConnectionFunc returns a function returning an
 // api connection to a controller with the specified api info.
 type NewExternalControllerConnectionFunc func(*api.Info) (api.Connection, error)
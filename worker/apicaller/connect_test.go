@@ -70,6 +70,40 @@ func testEntityFine(c *gc.C, life apiagent.Life) {
 	}})
 }
 
+func (*ScaryConnectSuite) TestControllerCACertChanged(c *gc.C) {
+	stub := &testing.Stub{}
+	expectConn := &mockConn{stub: stub, controllerCACert: "old-ca" + "new-ca"}
+	apiOpen := func(info *api.Info, opts api.DialOpts) (api.Connection, error) {
+		return expectConn, nil
+	}
+
+	entity := names.NewApplicationTag("omg")
+	connect := func() (api.Connection, error) {
+		return apicaller.ScaryConnect(&mockAgent{
+			stub:   stub,
+			model:  coretesting.ModelTag,
+			entity: entity,
+			caCert: "old-ca",
+		}, apiOpen)
+	}
+
+	conn, err := lifeTest(c, stub, apiagent.Alive, connect)
+	c.Check(conn, gc.Equals, expectConn)
+	c.Check(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []testing.StubCall{{
+		FuncName: "Life",
+		Args:     []interface{}{entity},
+	}, {
+		FuncName: "ChangeConfig",
+	}, {
+		FuncName: "SetCACert",
+		Args:     []interface{}{"old-ca" + "new-ca"},
+	}, {
+		FuncName: "SetPassword",
+		Args:     []interface{}{entity, "new"},
+	}})
+}
+
 func (*ScaryConnectSuite) TestEntityDead(c *gc.C) {
 	// permanent failure case
 	stub := &testing.Stub{}
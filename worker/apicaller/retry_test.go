@@ -10,6 +10,7 @@ import (
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/worker/apicaller"
 )
@@ -67,6 +68,35 @@ func (s *RetryStrategySuite) TestOnlyConnectOldPasswordSuccess(c *gc.C) {
 	c.Check(conn, gc.NotNil)
 }
 
+func (s *RetryStrategySuite) TestOnlyConnectControllerProxy(c *gc.C) {
+	var dialOpts api.DialOpts
+	apiOpen := func(info *api.Info, opts api.DialOpts) (api.Connection, error) {
+		dialOpts = opts
+		return &mockConn{stub: &testing.Stub{}}, nil
+	}
+	conn, err := apicaller.OnlyConnect(&mockAgent{
+		stub:   &testing.Stub{},
+		values: map[string]string{agent.ControllerProxy: "socks5://10.0.0.1:1080"},
+	}, apiOpen)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(conn, gc.NotNil)
+	c.Assert(dialOpts.ControllerProxy, gc.NotNil)
+	c.Check(dialOpts.ControllerProxy.String(), gc.Equals, "socks5://10.0.0.1:1080")
+}
+
+func (s *RetryStrategySuite) TestOnlyConnectInvalidControllerProxy(c *gc.C) {
+	apiOpen := func(info *api.Info, opts api.DialOpts) (api.Connection, error) {
+		c.Fatalf("unexpected connection attempt")
+		return nil, nil
+	}
+	conn, err := apicaller.OnlyConnect(&mockAgent{
+		stub:   &testing.Stub{},
+		values: map[string]string{agent.ControllerProxy: "ftp://10.0.0.1:21"},
+	}, apiOpen)
+	c.Check(conn, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, `controller proxy scheme "ftp" not valid`)
+}
+
 func (s *RetryStrategySuite) TestOnlyConnectEventualError(c *gc.C) {
 	conn, err := checkWaitProvisionedError(c, apicaller.OnlyConnect)
 	c.Check(conn, gc.IsNil)
@@ -30,12 +30,16 @@ type mockAgent struct {
 	stub   *testing.Stub
 	entity names.Tag
 	model  names.ModelTag
+	caCert string
+	values map[string]string
 }
 
 func (mock *mockAgent) CurrentConfig() agent.Config {
 	return dummyConfig{
 		entity: mock.entity,
 		model:  mock.model,
+		caCert: mock.caCert,
+		values: mock.values,
 	}
 }
 
@@ -51,6 +55,8 @@ type dummyConfig struct {
 	agent.Config
 	entity names.Tag
 	model  names.ModelTag
+	caCert string
+	values map[string]string
 }
 
 func (dummy dummyConfig) Tag() names.Tag {
@@ -69,10 +75,18 @@ func (dummy dummyConfig) APIInfo() (*api.Info, bool) {
 	}, true
 }
 
+func (dummy dummyConfig) CACert() string {
+	return dummy.caCert
+}
+
 func (dummy dummyConfig) OldPassword() string {
 	return "old"
 }
 
+func (dummy dummyConfig) Value(key string) string {
+	return dummy.values[key]
+}
+
 type mockSetter struct {
 	stub *testing.Stub
 	agent.ConfigSetter
@@ -88,11 +102,17 @@ func (mock *mockSetter) SetPassword(pw string) {
 	mock.stub.PopNoErr()
 }
 
+func (mock *mockSetter) SetCACert(caCert string) {
+	mock.stub.AddCall("SetCACert", caCert)
+	mock.stub.PopNoErr()
+}
+
 type mockConn struct {
 	stub *testing.Stub
 	api.Connection
-	controllerOnly bool
-	broken         chan struct{}
+	controllerOnly   bool
+	controllerCACert string
+	broken           chan struct{}
 }
 
 func (mock *mockConn) ModelTag() (names.ModelTag, bool) {
@@ -103,6 +123,10 @@ func (mock *mockConn) ModelTag() (names.ModelTag, bool) {
 	return coretesting.ModelTag, true
 }
 
+func (mock *mockConn) ControllerCACert() string {
+	return mock.controllerCACert
+}
+
 func (mock *mockConn) Broken() <-chan struct{} {
 	return mock.broken
 }
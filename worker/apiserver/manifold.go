@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/apiserverhttp"
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/apiservercertwatcher"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/gate"
 	workerstate "github.com/juju/juju/worker/state"
@@ -26,13 +27,14 @@ import (
 // ManifoldConfig holds the information necessary to run an apiserver
 // worker in a dependency.Engine.
 type ManifoldConfig struct {
-	AgentName              string
-	CertWatcherName        string
-	ClockName              string
-	RestoreStatusName      string
-	StateName              string
-	UpgradeGateName        string
-	AuditConfigUpdaterName string
+	AgentName               string
+	CertWatcherName         string
+	ExternalCertWatcherName string
+	ClockName               string
+	RestoreStatusName       string
+	StateName               string
+	UpgradeGateName         string
+	AuditConfigUpdaterName  string
 
 	PrometheusRegisterer              prometheus.Registerer
 	RegisterIntrospectionHTTPHandlers func(func(path string, _ http.Handler))
@@ -49,6 +51,9 @@ func (config ManifoldConfig) Validate() error {
 	if config.CertWatcherName == "" {
 		return errors.NotValidf("empty CertWatcherName")
 	}
+	if config.ExternalCertWatcherName == "" {
+		return errors.NotValidf("empty ExternalCertWatcherName")
+	}
 	if config.ClockName == "" {
 		return errors.NotValidf("empty ClockName")
 	}
@@ -87,6 +92,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 		Inputs: []string{
 			config.AgentName,
 			config.CertWatcherName,
+			config.ExternalCertWatcherName,
 			config.ClockName,
 			config.RestoreStatusName,
 			config.StateName,
@@ -114,6 +120,11 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		return nil, errors.Trace(err)
 	}
 
+	var getExternalCertificate apiservercertwatcher.ExternalCertificateGetter
+	if err := context.Get(config.ExternalCertWatcherName, &getExternalCertificate); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	var clock clock.Clock
 	if err := context.Get(config.ClockName, &clock); err != nil {
 		return nil, errors.Trace(err)
@@ -155,6 +166,7 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		UpgradeComplete:                   upgradeLock.IsUnlocked,
 		Hub:                               config.Hub,
 		GetCertificate:                    getCertificate,
+		GetExternalCertificate:            getExternalCertificate,
 		GetAuditConfig:                    getAuditConfig,
 		NewServer:                         newServerShim,
 	})
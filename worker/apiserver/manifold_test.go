@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/apiserver"
+	"github.com/juju/juju/worker/apiservercertwatcher"
 	"github.com/juju/juju/worker/dependency"
 	dt "github.com/juju/juju/worker/dependency/testing"
 	"github.com/juju/juju/worker/gate"
@@ -38,6 +39,7 @@ type ManifoldSuite struct {
 	state                stubStateTracker
 	prometheusRegisterer stubPrometheusRegisterer
 	certWatcher          stubCertWatcher
+	externalCertWatcher  stubCertWatcher
 	hub                  pubsub.StructuredHub
 	upgradeGate          stubGateWaiter
 	auditConfig          stubAuditConfig
@@ -55,6 +57,7 @@ func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 	s.state = stubStateTracker{}
 	s.prometheusRegisterer = stubPrometheusRegisterer{}
 	s.certWatcher = stubCertWatcher{}
+	s.externalCertWatcher = stubCertWatcher{}
 	s.upgradeGate = stubGateWaiter{}
 	s.auditConfig = stubAuditConfig{}
 	s.stub.ResetCalls()
@@ -63,6 +66,7 @@ func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 	s.manifold = apiserver.Manifold(apiserver.ManifoldConfig{
 		AgentName:                         "agent",
 		CertWatcherName:                   "cert-watcher",
+		ExternalCertWatcherName:           "external-cert-watcher",
 		ClockName:                         "clock",
 		RestoreStatusName:                 "restore-status",
 		StateName:                         "state",
@@ -77,13 +81,14 @@ func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 
 func (s *ManifoldSuite) newContext(overlay map[string]interface{}) dependency.Context {
 	resources := map[string]interface{}{
-		"agent":               s.agent,
-		"cert-watcher":        s.certWatcher.get,
-		"clock":               s.clock,
-		"restore-status":      s.RestoreStatus,
-		"state":               &s.state,
-		"upgrade":             &s.upgradeGate,
-		"auditconfig-updater": s.auditConfig.get,
+		"agent":                 s.agent,
+		"cert-watcher":          s.certWatcher.get,
+		"external-cert-watcher": apiservercertwatcher.ExternalCertificateGetter(s.externalCertWatcher.get),
+		"clock":                 s.clock,
+		"restore-status":        s.RestoreStatus,
+		"state":                 &s.state,
+		"upgrade":               &s.upgradeGate,
+		"auditconfig-updater":   s.auditConfig.get,
 	}
 	for k, v := range overlay {
 		resources[k] = v
@@ -105,7 +110,7 @@ func (s *ManifoldSuite) newWorker(config apiserver.Config) (worker.Worker, error
 }
 
 var expectedInputs = []string{
-	"agent", "cert-watcher", "clock", "restore-status", "state", "upgrade", "auditconfig-updater",
+	"agent", "cert-watcher", "external-cert-watcher", "clock", "restore-status", "state", "upgrade", "auditconfig-updater",
 }
 
 func (s *ManifoldSuite) TestInputs(c *gc.C) {
@@ -142,6 +147,10 @@ func (s *ManifoldSuite) TestStart(c *gc.C) {
 	c.Assert(config.GetCertificate(), gc.Equals, &s.certWatcher.cert)
 	config.GetCertificate = nil
 
+	c.Assert(config.GetExternalCertificate, gc.NotNil)
+	c.Assert(config.GetExternalCertificate(), gc.Equals, &s.externalCertWatcher.cert)
+	config.GetExternalCertificate = nil
+
 	c.Assert(config.GetAuditConfig, gc.NotNil)
 	c.Assert(config.GetAuditConfig(), gc.DeepEquals, s.auditConfig.config)
 	config.GetAuditConfig = nil
@@ -34,6 +34,7 @@ type Config struct {
 	RestoreStatus                     func() state.RestoreStatus
 	UpgradeComplete                   func() bool
 	GetCertificate                    func() *tls.Certificate
+	GetExternalCertificate            func() *tls.Certificate
 	GetAuditConfig                    func() auditlog.Config
 	NewServer                         NewServerFunc
 }
@@ -121,6 +122,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		LogDir:                        config.AgentConfig.LogDir(),
 		Hub:                           config.Hub,
 		GetCertificate:                config.GetCertificate,
+		GetExternalCertificate:        config.GetExternalCertificate,
 		RestoreStatus:                 config.RestoreStatus,
 		UpgradeComplete:               config.UpgradeComplete,
 		AutocertURL:                   controllerConfig.AutocertURL(),
@@ -0,0 +1,268 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiservercertwatcher
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/catacomb"
+	"github.com/juju/juju/worker/common"
+	"github.com/juju/juju/worker/dependency"
+	workerstate "github.com/juju/juju/worker/state"
+)
+
+// externalCertPollInterval is how often certificate files named in
+// controller config are reread, so that certificates renewed in place
+// are picked up.
+const externalCertPollInterval = time.Minute
+
+// ExternalCertificateGetter returns the controller's current externally
+// provided API certificate, or nil if there is none. The certificate's
+// Leaf field is set.
+type ExternalCertificateGetter func() *tls.Certificate
+
+// ExternalConfigSource lets the external certificate watcher get
+// notifications of changes to controller configuration, and then get
+// the changed config. (Primary implementation is State.)
+type ExternalConfigSource interface {
+	WatchControllerConfig() state.NotifyWatcher
+	ControllerConfig() (controller.Config, error)
+}
+
+// ExternalConfig holds the configuration for an external certificate
+// watcher.
+type ExternalConfig struct {
+	Source ExternalConfigSource
+	Clock  clock.Clock
+}
+
+// Validate validates the external certificate watcher configuration.
+func (config ExternalConfig) Validate() error {
+	if config.Source == nil {
+		return errors.NotValidf("nil Source")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// ExternalManifoldConfig holds the information needed to run an
+// external certificate watcher in a dependency.Engine.
+type ExternalManifoldConfig struct {
+	ClockName string
+	StateName string
+}
+
+// Validate validates the manifold configuration.
+func (config ExternalManifoldConfig) Validate() error {
+	if config.ClockName == "" {
+		return errors.NotValidf("empty ClockName")
+	}
+	if config.StateName == "" {
+		return errors.NotValidf("empty StateName")
+	}
+	return nil
+}
+
+// ExternalManifold returns a dependency.Manifold which watches the
+// controller config for an externally provided API certificate, given
+// either as PEM content or as paths to files on the controller
+// machine, and makes the current certificate available via the
+// manifold's Output. The Output expects a pointer to an
+// ExternalCertificateGetter.
+//
+// The manifold is intended to be a dependency for the apiserver.
+func ExternalManifold(config ExternalManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.ClockName,
+			config.StateName,
+		},
+		Start:  config.start,
+		Output: externalOutputFunc,
+	}
+}
+
+func (config ExternalManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var clock clock.Clock
+	if err := context.Get(config.ClockName, &clock); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var stTracker workerstate.StateTracker
+	if err := context.Get(config.StateName, &stTracker); err != nil {
+		return nil, errors.Trace(err)
+	}
+	statePool, err := stTracker.Use()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	w, err := NewExternalWorker(ExternalConfig{
+		Source: statePool.SystemState(),
+		Clock:  clock,
+	})
+	if err != nil {
+		stTracker.Done()
+		return nil, errors.Trace(err)
+	}
+	return common.NewCleanupWorker(w, func() { stTracker.Done() }), nil
+}
+
+func externalOutputFunc(in worker.Worker, out interface{}) error {
+	if w, ok := in.(*common.CleanupWorker); ok {
+		in = w.Worker
+	}
+	inWorker, _ := in.(*externalCertWatcher)
+	if inWorker == nil {
+		return errors.Errorf("in should be a %T; got a %T", inWorker, in)
+	}
+	outPointer, ok := out.(*ExternalCertificateGetter)
+	if !ok {
+		return errors.Errorf("out should be %T; got %T", outPointer, out)
+	}
+	*outPointer = inWorker.getCurrent
+	return nil
+}
+
+// NewExternalWorker returns a worker that keeps the controller's
+// externally provided API certificate up to date.
+func NewExternalWorker(config ExternalConfig) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &externalCertWatcher{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type externalCertWatcher struct {
+	catacomb catacomb.Catacomb
+	config   ExternalConfig
+
+	certPath string
+	keyPath  string
+
+	mu         sync.Mutex
+	currentRaw string
+	current    *tls.Certificate
+}
+
+// Kill implements worker.Worker.
+func (w *externalCertWatcher) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait implements worker.Worker.
+func (w *externalCertWatcher) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *externalCertWatcher) loop() error {
+	watcher := w.config.Source.WatchControllerConfig()
+	if err := w.catacomb.Add(watcher); err != nil {
+		return errors.Trace(err)
+	}
+	var poll <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return errors.New("controller config watcher closed")
+			}
+			cfg, err := w.config.Source.ControllerConfig()
+			if err != nil {
+				return errors.Annotate(err, "cannot get controller config")
+			}
+			w.certPath, w.keyPath = cfg.ExternalAPICertPath()
+			if w.certPath == "" {
+				w.update(cfg.ExternalAPICert())
+			} else {
+				w.updateFromFiles()
+			}
+		case <-poll:
+			w.updateFromFiles()
+		}
+		poll = nil
+		if w.certPath != "" {
+			poll = w.config.Clock.After(externalCertPollInterval)
+		}
+	}
+}
+
+func (w *externalCertWatcher) updateFromFiles() {
+	certPEM, err := ioutil.ReadFile(w.certPath)
+	if err != nil {
+		// We don't bounce the worker on unreadable files; the
+		// previous certificate is served until they are fixed.
+		logger.Errorf("cannot read external API certificate: %v", err)
+		return
+	}
+	keyPEM, err := ioutil.ReadFile(w.keyPath)
+	if err != nil {
+		logger.Errorf("cannot read external API private key: %v", err)
+		return
+	}
+	w.update(string(certPEM), string(keyPEM))
+}
+
+func (w *externalCertWatcher) update(certPEM, keyPEM string) {
+	if certPEM+keyPEM == w.currentRaw {
+		// No change.
+		return
+	}
+	var newCert *tls.Certificate
+	if certPEM != "" {
+		tlsCert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			logger.Errorf("cannot create external API certificate: %v", err)
+			return
+		}
+		x509Cert, err := x509.ParseCertificate(tlsCert.Certificate[0])
+		if err != nil {
+			logger.Errorf("cannot parse external API certificate: %v", err)
+			return
+		}
+		tlsCert.Leaf = x509Cert
+		newCert = &tlsCert
+		logger.Infof("new external API certificate DNS names: %v", strings.Join(x509Cert.DNSNames, ", "))
+	} else if w.currentRaw != "" {
+		logger.Infof("external API certificate removed")
+	}
+
+	w.currentRaw = certPEM + keyPEM
+	w.mu.Lock()
+	w.current = newCert
+	w.mu.Unlock()
+}
+
+func (w *externalCertWatcher) getCurrent() *tls.Certificate {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
@@ -0,0 +1,241 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiservercertwatcher_test
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/cert"
+	gc "gopkg.in/check.v1"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher/watchertest"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/apiservercertwatcher"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type ExternalSuite struct {
+	testing.IsolationSuite
+
+	clock         *testing.Clock
+	configChanged chan struct{}
+	source        *configSource
+	certPEM       string
+	keyPEM        string
+}
+
+var _ = gc.Suite(&ExternalSuite{})
+
+func (s *ExternalSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.clock = testing.NewClock(time.Time{})
+	s.configChanged = make(chan struct{}, 1)
+	s.source = &configSource{
+		watcher: watchertest.NewNotifyWatcher(s.configChanged),
+		cfg:     controller.Config{},
+	}
+	var err error
+	s.certPEM, s.keyPEM, err = cert.NewServer(
+		coretesting.CACert, coretesting.CAKey,
+		time.Now().AddDate(1, 0, 0), []string{"api.example.com"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ExternalSuite) startWorker(c *gc.C) (worker.Worker, apiservercertwatcher.ExternalCertificateGetter) {
+	w, err := apiservercertwatcher.NewExternalWorker(apiservercertwatcher.ExternalConfig{
+		Source: s.source,
+		Clock:  s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var getCert apiservercertwatcher.ExternalCertificateGetter
+	manifold := apiservercertwatcher.ExternalManifold(apiservercertwatcher.ExternalManifoldConfig{})
+	err = manifold.Output(w, &getCert)
+	c.Assert(err, jc.ErrorIsNil)
+	return w, getCert
+}
+
+func (s *ExternalSuite) setConfig(cfg controller.Config) {
+	s.source.setConfig(cfg)
+	s.configChanged <- struct{}{}
+}
+
+func waitForCert(c *gc.C, getCert apiservercertwatcher.ExternalCertificateGetter, predicate func(*tls.Certificate) bool) *tls.Certificate {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if cert := getCert(); predicate(cert) {
+			return cert
+		}
+	}
+	c.Fatalf("timed out waiting for external certificate")
+	return nil
+}
+
+func (s *ExternalSuite) TestValidate(c *gc.C) {
+	_, err := apiservercertwatcher.NewExternalWorker(apiservercertwatcher.ExternalConfig{
+		Clock: s.clock,
+	})
+	c.Assert(err, gc.ErrorMatches, "nil Source not valid")
+	_, err = apiservercertwatcher.NewExternalWorker(apiservercertwatcher.ExternalConfig{
+		Source: s.source,
+	})
+	c.Assert(err, gc.ErrorMatches, "nil Clock not valid")
+}
+
+func (s *ExternalSuite) TestManifoldInputs(c *gc.C) {
+	manifold := apiservercertwatcher.ExternalManifold(apiservercertwatcher.ExternalManifoldConfig{
+		ClockName: "clock",
+		StateName: "state",
+	})
+	c.Assert(manifold.Inputs, jc.SameContents, []string{"clock", "state"})
+}
+
+func (s *ExternalSuite) TestManifoldMissingInputs(c *gc.C) {
+	manifold := apiservercertwatcher.ExternalManifold(apiservercertwatcher.ExternalManifoldConfig{
+		ClockName: "clock",
+		StateName: "state",
+	})
+	for _, input := range manifold.Inputs {
+		context := dt.StubContext(nil, map[string]interface{}{
+			"clock": s.clock,
+			"state": nil,
+			input:   dependency.ErrMissing,
+		})
+		_, err := manifold.Start(context)
+		c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+	}
+}
+
+func (s *ExternalSuite) TestNoCertificate(c *gc.C) {
+	w, getCert := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.setConfig(controller.Config{})
+	time.Sleep(coretesting.ShortWait)
+	c.Assert(getCert(), gc.IsNil)
+}
+
+func (s *ExternalSuite) TestCertificateFromConfig(c *gc.C) {
+	w, getCert := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.setConfig(controller.Config{
+		controller.ExternalAPICertKey: s.certPEM,
+		controller.ExternalAPIKeyKey:  s.keyPEM,
+	})
+	cert := waitForCert(c, getCert, func(cert *tls.Certificate) bool {
+		return cert != nil
+	})
+	c.Assert(cert.Leaf, gc.NotNil)
+	c.Assert(cert.Leaf.DNSNames, jc.DeepEquals, []string{"api.example.com"})
+
+	// Removing the certificate from config stops it being served.
+	s.setConfig(controller.Config{})
+	waitForCert(c, getCert, func(cert *tls.Certificate) bool {
+		return cert == nil
+	})
+}
+
+func (s *ExternalSuite) TestBadCertificateKeepsPrevious(c *gc.C) {
+	w, getCert := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.setConfig(controller.Config{
+		controller.ExternalAPICertKey: s.certPEM,
+		controller.ExternalAPIKeyKey:  s.keyPEM,
+	})
+	cert := waitForCert(c, getCert, func(cert *tls.Certificate) bool {
+		return cert != nil
+	})
+
+	s.setConfig(controller.Config{
+		controller.ExternalAPICertKey: s.certPEM,
+		controller.ExternalAPIKeyKey:  coretesting.CAKey,
+	})
+	time.Sleep(coretesting.ShortWait)
+	c.Assert(getCert(), gc.Equals, cert)
+	workertest.CheckAlive(c, w)
+}
+
+func (s *ExternalSuite) TestCertificateFromFiles(c *gc.C) {
+	dir := c.MkDir()
+	certPath := filepath.Join(dir, "api.crt")
+	keyPath := filepath.Join(dir, "api.key")
+	err := ioutil.WriteFile(certPath, []byte(s.certPEM), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(keyPath, []byte(s.keyPEM), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w, getCert := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.setConfig(controller.Config{
+		controller.ExternalAPICertPathKey: certPath,
+		controller.ExternalAPIKeyPathKey:  keyPath,
+	})
+	tlsCert := waitForCert(c, getCert, func(cert *tls.Certificate) bool {
+		return cert != nil
+	})
+	c.Assert(tlsCert.Leaf.DNSNames, jc.DeepEquals, []string{"api.example.com"})
+
+	// Renew the certificate in place; it is picked up when the
+	// files are next polled.
+	newCertPEM, newKeyPEM, err := cert.NewServer(
+		coretesting.CACert, coretesting.CAKey,
+		time.Now().AddDate(1, 0, 0), []string{"juju.example.com"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(certPath, []byte(newCertPEM), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(keyPath, []byte(newKeyPEM), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	tlsCert = waitForCert(c, getCert, func(cert *tls.Certificate) bool {
+		return cert.Leaf.DNSNames[0] == "juju.example.com"
+	})
+	c.Assert(tlsCert.Leaf.DNSNames, jc.DeepEquals, []string{"juju.example.com"})
+}
+
+func (s *ExternalSuite) TestWatcherClosed(c *gc.C) {
+	w, _ := s.startWorker(c)
+	close(s.configChanged)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "controller config watcher closed")
+}
+
+type configSource struct {
+	mu      sync.Mutex
+	watcher *watchertest.NotifyWatcher
+	cfg     controller.Config
+}
+
+func (s *configSource) WatchControllerConfig() state.NotifyWatcher {
+	return s.watcher
+}
+
+func (s *configSource) ControllerConfig() (controller.Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg, nil
+}
+
+func (s *configSource) setConfig(cfg controller.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cacertupdater provides a worker that keeps the controller CA
+// certificate in an agent's configuration up to date, so that agents
+// come to trust a new controller CA while it is rotated without having
+// to reconnect, and tells the controller which CA certificate the agent
+// has recorded.
+package cacertupdater
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/watcher"
+)
+
+var logger = loggo.GetLogger("juju.worker.cacertupdater")
+
+// Facade provides the API methods used by the worker.
+type Facade interface {
+	// ControllerConfig returns the controller configuration, which
+	// holds the controller CA certificate.
+	ControllerConfig() (controller.Config, error)
+
+	// WatchControllerCACert returns a watcher that notifies when
+	// the controller CA certificate may have changed.
+	WatchControllerCACert() (watcher.NotifyWatcher, error)
+
+	// AcknowledgeControllerCACert tells the controller which
+	// controller CA certificate the agent has recorded.
+	AcknowledgeControllerCACert(caCert string) error
+}
+
+// Config holds the configuration and dependencies of the worker.
+type Config struct {
+	Agent  agent.Agent
+	Facade Facade
+}
+
+// Validate returns an error if the config is not valid.
+func (config Config) Validate() error {
+	if config.Agent == nil {
+		return errors.NotValidf("nil Agent")
+	}
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that records the controller CA
+// certificate in the agent's configuration whenever it changes.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: &caCertUpdater{config: config},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type caCertUpdater struct {
+	config Config
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (u *caCertUpdater) SetUp() (watcher.NotifyWatcher, error) {
+	return u.config.Facade.WatchControllerCACert()
+}
+
+// Handle is part of the watcher.NotifyHandler interface.
+func (u *caCertUpdater) Handle(_ <-chan struct{}) error {
+	cfg, err := u.config.Facade.ControllerConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot get controller configuration")
+	}
+	caCert, ok := cfg.CACert()
+	if !ok || caCert == "" {
+		return nil
+	}
+	if caCert != u.config.Agent.CurrentConfig().CACert() {
+		logger.Infof("controller CA certificate changed")
+		err := u.config.Agent.ChangeConfig(func(c agent.ConfigSetter) error {
+			c.SetCACert(caCert)
+			return nil
+		})
+		if err != nil {
+			return errors.Annotate(err, "cannot record controller CA certificate")
+		}
+	}
+	return errors.Trace(u.config.Facade.AcknowledgeControllerCACert(caCert))
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (u *caCertUpdater) TearDown() error {
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	apiagent "github.com/juju/juju/api/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a Manifold will depend.
+type ManifoldConfig engine.AgentAPIManifoldConfig
+
+// Manifold returns a dependency manifold that runs a CA certificate
+// updater worker, using the resource names defined in the supplied config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	typedConfig := engine.AgentAPIManifoldConfig(config)
+	return engine.AgentAPIManifold(typedConfig, newWorker)
+}
+
+// newWorker wraps NewWorker for use in a engine.AgentAPIManifold.
+func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	if apiCaller.BestFacadeVersion("Agent") < 3 {
+		// The controller cannot rotate its CA, so there is
+		// nothing to do.
+		return nil, dependency.ErrUninstall
+	}
+	st, err := apiagent.NewState(apiCaller)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := NewWorker(Config{
+		Agent:  a,
+		Facade: facadeShim{st, a.CurrentConfig().Tag()},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// facadeShim adapts the agent API to the Facade interface, on behalf
+// of the agent with the given tag.
+type facadeShim struct {
+	*apiagent.State
+	tag names.Tag
+}
+
+// AcknowledgeControllerCACert is part of the Facade interface.
+func (f facadeShim) AcknowledgeControllerCACert(caCert string) error {
+	return f.State.AcknowledgeControllerCACert(f.tag, caCert)
+}
@@ -0,0 +1,147 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/controller"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/watcher/watchertest"
+	"github.com/juju/juju/worker/cacertupdater"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+	agent  *fakeAgent
+	facade *fakeFacade
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.agent = &fakeAgent{caCert: "old-ca"}
+	s.facade = &fakeFacade{
+		changes: make(chan struct{}, 1),
+		acked:   make(chan string, 1),
+		caCert:  "old-ca",
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := cacertupdater.NewWorker(cacertupdater.Config{Facade: s.facade})
+	c.Assert(err, gc.ErrorMatches, "nil Agent not valid")
+	_, err = cacertupdater.NewWorker(cacertupdater.Config{Agent: s.agent})
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) func() {
+	w, err := cacertupdater.NewWorker(cacertupdater.Config{
+		Agent:  s.agent,
+		Facade: s.facade,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return func() { workertest.CleanKill(c, w) }
+}
+
+func (s *WorkerSuite) assertAcknowledged(c *gc.C, expect string) {
+	select {
+	case caCert := <-s.facade.acked:
+		c.Assert(caCert, gc.Equals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for acknowledgement")
+	}
+}
+
+func (s *WorkerSuite) TestUnchanged(c *gc.C) {
+	defer s.startWorker(c)()
+	s.facade.changes <- struct{}{}
+	s.assertAcknowledged(c, "old-ca")
+	c.Assert(s.agent.changed, jc.IsFalse)
+}
+
+func (s *WorkerSuite) TestChanged(c *gc.C) {
+	defer s.startWorker(c)()
+	s.facade.changes <- struct{}{}
+	s.assertAcknowledged(c, "old-ca")
+
+	s.facade.setCACert("old-ca" + "new-ca")
+	s.facade.changes <- struct{}{}
+	s.assertAcknowledged(c, "old-ca"+"new-ca")
+	c.Assert(s.agent.changed, jc.IsTrue)
+	c.Assert(s.agent.CurrentConfig().CACert(), gc.Equals, "old-ca"+"new-ca")
+}
+
+type fakeFacade struct {
+	changes chan struct{}
+	acked   chan string
+
+	mu     sync.Mutex
+	caCert string
+}
+
+func (f *fakeFacade) setCACert(caCert string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.caCert = caCert
+}
+
+func (f *fakeFacade) ControllerConfig() (controller.Config, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return controller.Config{controller.CACertKey: f.caCert}, nil
+}
+
+func (f *fakeFacade) WatchControllerCACert() (watcher.NotifyWatcher, error) {
+	return watchertest.NewMockNotifyWatcher(f.changes), nil
+}
+
+func (f *fakeFacade) AcknowledgeControllerCACert(caCert string) error {
+	f.acked <- caCert
+	return nil
+}
+
+type fakeAgent struct {
+	agent.Agent
+	agent.ConfigSetter
+
+	mu      sync.Mutex
+	caCert  string
+	changed bool
+}
+
+func (a *fakeAgent) CurrentConfig() agent.Config {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return fakeConfig{caCert: a.caCert}
+}
+
+func (a *fakeAgent) ChangeConfig(mutate agent.ConfigMutator) error {
+	return mutate(a)
+}
+
+func (a *fakeAgent) SetCACert(caCert string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.caCert = caCert
+	a.changed = true
+}
+
+type fakeConfig struct {
+	agent.Config
+	caCert string
+}
+
+func (c fakeConfig) CACert() string {
+	return c.caCert
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/utils/set"
 	worker "gopkg.in/juju/worker.v1"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/network"
@@ -32,6 +33,10 @@ type CertificateUpdater struct {
 	setter          StateServingInfoSetter
 	configGetter    ControllerConfigGetter
 	hostPortsGetter APIHostPortsGetter
+	controllerCA    ControllerCAGetter
+	machineId       string
+	updateMongoCert func(cert, key string) error
+	restartMongo    func() error
 	addresses       []network.Address
 }
 
@@ -64,6 +69,16 @@ type APIHostPortsGetter interface {
 	APIHostPortsForClients() ([][]network.HostPort, error)
 }
 
+// ControllerCAGetter is an interface that is provided to NewCertificateUpdater
+// which can be used to watch for and read the controller CA private key
+// recorded in state, which changes when the controller CA is rotated,
+// and to record that the controller certificate has been reissued.
+type ControllerCAGetter interface {
+	WatchControllerConfig() state.NotifyWatcher
+	StateServingInfo() (state.StateServingInfo, error)
+	RecordControllerCertReissued(machineId string) error
+}
+
 // Config holds the configuration for the certificate updater worker.
 type Config struct {
 	AddressWatcher         AddressWatcher
@@ -71,6 +86,21 @@ type Config struct {
 	StateServingInfoSetter StateServingInfoSetter
 	ControllerConfigGetter ControllerConfigGetter
 	APIHostPortsGetter     APIHostPortsGetter
+
+	// ControllerCAGetter, if set, is used to reissue the
+	// controller certificate when the controller CA is rotated.
+	ControllerCAGetter ControllerCAGetter
+
+	// MachineId is the id of the controller machine, recorded
+	// against the rotation once its certificate is reissued.
+	MachineId string
+
+	// UpdateMongoCert and RestartMongo, if set, are called
+	// when the certificate is reissued with a rotated CA, to
+	// write the certificate and key served by mongo and to
+	// restart mongo so that it loads them.
+	UpdateMongoCert func(cert, key string) error
+	RestartMongo    func() error
 }
 
 // NewCertificateUpdater returns a worker.Worker that watches for changes to
@@ -83,6 +113,10 @@ func NewCertificateUpdater(config Config) worker.Worker {
 		hostPortsGetter: config.APIHostPortsGetter,
 		getter:          config.StateServingInfoGetter,
 		setter:          config.StateServingInfoSetter,
+		controllerCA:    config.ControllerCAGetter,
+		machineId:       config.MachineId,
+		updateMongoCert: config.UpdateMongoCert,
+		restartMongo:    config.RestartMongo,
 	})
 }
 
@@ -105,15 +139,22 @@ func (c *CertificateUpdater) SetUp() (state.NotifyWatcher, error) {
 	if err := c.updateCertificate(initialSANAddresses); err != nil {
 		return nil, errors.Annotate(err, "setting initial certificate SAN list")
 	}
-	return c.addressWatcher.WatchAddresses(), nil
+	if c.controllerCA == nil {
+		return c.addressWatcher.WatchAddresses(), nil
+	}
+	return common.NewMultiNotifyWatcher(
+		c.addressWatcher.WatchAddresses(),
+		c.controllerCA.WatchControllerConfig(),
+	), nil
 }
 
 // Handle is defined on the NotifyWatchHandler interface.
 func (c *CertificateUpdater) Handle(done <-chan struct{}) error {
 	addresses := c.addressWatcher.Addresses()
-	if reflect.DeepEqual(addresses, c.addresses) {
+	if reflect.DeepEqual(addresses, c.addresses) && c.controllerCA == nil {
 		// Sometimes the watcher will tell us things have changed, when they
-		// haven't as far as we can tell.
+		// haven't as far as we can tell. When the controller CA may have
+		// been rotated we always check, as the addresses need not change.
 		logger.Debugf("addresses haven't really changed since last updated cert")
 		return nil
 	}
@@ -130,7 +171,10 @@ func (c *CertificateUpdater) updateCertificate(addresses []network.Address) erro
 	if !ok {
 		return errors.New("no state serving info, cannot regenerate server certificate")
 	}
-	caPrivateKey := stateInfo.CAPrivateKey
+	caPrivateKey, rotated, err := c.caPrivateKey(stateInfo.CAPrivateKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	if caPrivateKey == "" {
 		logger.Errorf("no CA cert private key, cannot regenerate server certificate")
 		return nil
@@ -157,7 +201,7 @@ func (c *CertificateUpdater) updateCertificate(addresses []network.Address) erro
 	if err != nil {
 		return errors.Annotate(err, "cannot determine if cert update needed")
 	}
-	if !update {
+	if !update && !rotated {
 		logger.Debugf("no certificate update required")
 		return nil
 	}
@@ -171,16 +215,55 @@ func (c *CertificateUpdater) updateCertificate(addresses []network.Address) erro
 	if err != nil {
 		return errors.Annotate(err, "cannot generate controller certificate")
 	}
+	if rotated {
+		// Everything that must be retried if it fails is done
+		// before the agent config is written, as the certificate
+		// is only reissued again while the CA keys differ.
+		if c.updateMongoCert != nil {
+			if err := c.updateMongoCert(newCert, newKey); err != nil {
+				return errors.Annotate(err, "cannot update mongo certificate")
+			}
+		}
+		if err := c.controllerCA.RecordControllerCertReissued(c.machineId); err != nil {
+			return errors.Annotate(err, "cannot record reissued controller certificate")
+		}
+	}
 	stateInfo.Cert = newCert
 	stateInfo.PrivateKey = newKey
+	stateInfo.CAPrivateKey = caPrivateKey
 	err = c.setter(stateInfo)
 	if err != nil {
 		return errors.Annotate(err, "cannot write agent config")
 	}
+	if rotated {
+		logger.Infof("controller certificate reissued with rotated controller CA")
+		if c.restartMongo != nil {
+			if err := c.restartMongo(); err != nil {
+				return errors.Annotate(err, "cannot restart mongo")
+			}
+		}
+	}
 	logger.Infof("controller certificate addresses updated to %q", newServerAddrs)
 	return nil
 }
 
+// caPrivateKey returns the CA private key to sign the controller
+// certificate with, and whether it differs from the agent's key
+// because the controller CA has been rotated.
+func (c *CertificateUpdater) caPrivateKey(agentKey string) (string, bool, error) {
+	if c.controllerCA == nil {
+		return agentKey, false, nil
+	}
+	info, err := c.controllerCA.StateServingInfo()
+	if err != nil {
+		return "", false, errors.Annotate(err, "cannot read controller CA private key")
+	}
+	if info.CAPrivateKey == "" || info.CAPrivateKey == agentKey {
+		return agentKey, false, nil
+	}
+	return info.CAPrivateKey, true, nil
+}
+
 // updateRequired returns true and a list of merged addresses if any of the
 // new addresses are not yet contained in the server cert SAN list.
 func updateRequired(serverCert string, newAddrs []string) ([]string, bool, error) {
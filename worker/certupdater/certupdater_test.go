@@ -182,6 +182,121 @@ func (s *CertUpdaterSuite) TestAddressChange(c *gc.C) {
 		[]string{"localhost", "juju-apiserver", "juju-mongodb", "anything"})
 }
 
+type mockControllerCA struct {
+	changes  chan struct{}
+	caKey    string
+	reissued []string
+}
+
+func (m *mockControllerCA) WatchControllerConfig() state.NotifyWatcher {
+	return newMockNotifyWatcher(m.changes)
+}
+
+func (m *mockControllerCA) StateServingInfo() (state.StateServingInfo, error) {
+	return state.StateServingInfo{CAPrivateKey: m.caKey}, nil
+}
+
+func (m *mockControllerCA) RecordControllerCertReissued(machineId string) error {
+	m.reissued = append(m.reissued, machineId)
+	return nil
+}
+
+func (s *CertUpdaterSuite) TestControllerCARotated(c *gc.C) {
+	var srvCert *x509.Certificate
+	updated := make(chan struct{}, 1)
+	setter := func(info params.StateServingInfo) error {
+		s.stateServingInfo = info
+		var err error
+		srvCert, err = cert.ParseCert(info.Cert)
+		c.Assert(err, jc.ErrorIsNil)
+		updated <- struct{}{}
+		return nil
+	}
+	controllerCA := &mockControllerCA{
+		changes: make(chan struct{}),
+		caKey:   coretesting.CAKey,
+	}
+	var mongoCert string
+	restarted := make(chan struct{}, 1)
+	worker := certupdater.NewCertificateUpdater(certupdater.Config{
+		AddressWatcher:         &mockMachine{make(chan struct{})},
+		APIHostPortsGetter:     &mockAPIHostGetter{},
+		ControllerConfigGetter: &mockRotatedConfigGetter{controllerCA},
+		StateServingInfoGetter: s,
+		StateServingInfoSetter: setter,
+		ControllerCAGetter:     controllerCA,
+		MachineId:              "0",
+		UpdateMongoCert: func(cert, key string) error {
+			mongoCert = cert
+			return nil
+		},
+		RestartMongo: func() error {
+			restarted <- struct{}{}
+			return nil
+		},
+	})
+	defer workertest.CleanKill(c, worker)
+
+	// The initial certificate is signed with the original CA.
+	select {
+	case <-updated:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for initial certificate")
+	}
+	c.Assert(s.stateServingInfo.CAPrivateKey, gc.Equals, coretesting.CAKey)
+	c.Assert(controllerCA.reissued, gc.HasLen, 0)
+
+	// Once the CA private key in state changes, the certificate
+	// is reissued with the new CA even though the addresses
+	// have not changed.
+	controllerCA.caKey = coretesting.OtherCAKey
+	controllerCA.changes <- struct{}{}
+	select {
+	case <-updated:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for certificate to be reissued")
+	}
+	c.Assert(s.stateServingInfo.CAPrivateKey, gc.Equals, coretesting.OtherCAKey)
+	err := jujucert.Verify(s.stateServingInfo.Cert, coretesting.OtherCACert, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(srvCert.IPAddresses, gc.Not(gc.HasLen), 0)
+
+	// The reissued certificate is served by mongo too, and
+	// recorded against the rotation.
+	c.Assert(mongoCert, gc.Equals, s.stateServingInfo.Cert)
+	c.Assert(controllerCA.reissued, jc.DeepEquals, []string{"0"})
+	select {
+	case <-restarted:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for mongo to be restarted")
+	}
+
+	// Nothing changes when the key doesn't.
+	controllerCA.changes <- struct{}{}
+	select {
+	case <-time.After(coretesting.ShortWait):
+	case <-updated:
+		c.Fatalf("set state serving info unexpectedly called")
+	}
+}
+
+// mockRotatedConfigGetter returns the controller CA certificate bundle
+// matching the controller CA private key, as it is while the
+// controller CA is being rotated.
+type mockRotatedConfigGetter struct {
+	controllerCA *mockControllerCA
+}
+
+func (g *mockRotatedConfigGetter) ControllerConfig() (jujucontroller.Config, error) {
+	caCert := coretesting.CACert + coretesting.OtherCACert
+	if g.controllerCA.caKey == coretesting.OtherCAKey {
+		caCert = coretesting.OtherCACert + coretesting.CACert
+	}
+	return map[string]interface{}{
+		jujucontroller.CACertKey: caCert,
+	}, nil
+}
+
 type mockStateServingGetterNoCAKey struct{}
 
 func (g *mockStateServingGetterNoCAKey) StateServingInfo() (params.StateServingInfo, bool) {
@@ -9,6 +9,7 @@ import (
 
 	jujuagent "github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/common"
 	"github.com/juju/juju/worker/dependency"
@@ -73,6 +74,7 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 	}
 
 	agentConfig := agent.CurrentConfig()
+	getStateServingInfo := agentStateServingInfoGetter{agent}
 	setStateServingInfo := func(info params.StateServingInfo) error {
 		return agent.ChangeConfig(func(config jujuagent.ConfigSetter) error {
 			config.SetStateServingInfo(info)
@@ -88,14 +90,32 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 
 	w := config.NewWorker(Config{
 		AddressWatcher:         addressWatcher,
-		StateServingInfoGetter: agentConfig,
+		StateServingInfoGetter: getStateServingInfo,
 		StateServingInfoSetter: setStateServingInfo,
 		ControllerConfigGetter: st,
 		APIHostPortsGetter:     st,
+		ControllerCAGetter:     st,
+		MachineId:              agentConfig.Tag().Id(),
+		UpdateMongoCert: func(cert, key string) error {
+			return mongo.UpdateSSLKey(agentConfig.DataDir(), cert, key)
+		},
+		RestartMongo: mongo.ReStartService,
 	})
 	return common.NewCleanupWorker(w, func() { stTracker.Done() }), nil
 }
 
+// agentStateServingInfoGetter reads the state serving info from the
+// agent's current config, rather than a snapshot, so that the updater
+// sees the certificates it has written.
+type agentStateServingInfoGetter struct {
+	agent jujuagent.Agent
+}
+
+// StateServingInfo is part of the StateServingInfoGetter interface.
+func (g agentStateServingInfoGetter) StateServingInfo() (params.StateServingInfo, bool) {
+	return g.agent.CurrentConfig().StateServingInfo()
+}
+
 // NewMachineAddressWatcher is the function that non-test code should
 // pass into ManifoldConfig.NewMachineAddressWatcher.
 func NewMachineAddressWatcher(st *state.State, machineId string) (AddressWatcher, error) {
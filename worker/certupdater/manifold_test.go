@@ -111,11 +111,27 @@ func (s *ManifoldSuite) TestStart(c *gc.C) {
 	c.Assert(config.StateServingInfoSetter, gc.NotNil)
 	config.StateServingInfoSetter = nil
 
+	// The getter reads the agent's current config each time.
+	c.Assert(config.StateServingInfoGetter, gc.NotNil)
+	_, ok := config.StateServingInfoGetter.StateServingInfo()
+	c.Assert(ok, jc.IsFalse)
+	s.agent.conf.info = &params.StateServingInfo{APIPort: 1234}
+	info, ok := config.StateServingInfoGetter.StateServingInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info.APIPort, gc.Equals, 1234)
+	config.StateServingInfoGetter = nil
+
+	c.Assert(config.UpdateMongoCert, gc.NotNil)
+	config.UpdateMongoCert = nil
+	c.Assert(config.RestartMongo, gc.NotNil)
+	config.RestartMongo = nil
+
 	c.Assert(config, jc.DeepEquals, certupdater.Config{
 		AddressWatcher:         &s.addressWatcher,
-		StateServingInfoGetter: &s.agent.conf,
 		ControllerConfigGetter: s.State,
 		APIHostPortsGetter:     s.State,
+		ControllerCAGetter:     s.State,
+		MachineId:              "123",
 	})
 }
 
@@ -43,6 +43,8 @@ type FirewallerAPI interface {
 	MacaroonForRelation(relationKey string) (*macaroon.Macaroon, error)
 	SetRelationStatus(relationKey string, status relation.Status, message string) error
 	FirewallRules(serviceNames ...string) ([]params.FirewallRule, error)
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	ModelConfig() (*config.Config, error)
 }
 
 // CrossModelFirewallerFacade exposes firewaller functionality on the
@@ -132,6 +134,14 @@ type Firewaller struct {
 	globalMode           bool
 	globalIngressRuleRef map[string]int // map of rule names to count of occurrences
 
+	// modelConfigWatcher notifies of model config changes, so
+	// that ports are reopened when IPv6 is enabled or disabled.
+	modelConfigWatcher watcher.NotifyWatcher
+	// exposeIPv6 is true when the model's expose-ipv6 setting is
+	// enabled, in which case exposed applications are opened to
+	// IPv6 as well as IPv4 sources.
+	exposeIPv6 bool
+
 	modelUUID                  string
 	newRemoteFirewallerAPIFunc newCrossModelFacadeFunc
 	remoteRelationsWatcher     watcher.StringsWatcher
@@ -223,6 +233,19 @@ func (fw *Firewaller) setUp() error {
 		return errors.Trace(err)
 	}
 
+	fw.modelConfigWatcher, err = fw.firewallerApi.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := fw.catacomb.Add(fw.modelConfigWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	modelConfig, err := fw.firewallerApi.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fw.exposeIPv6 = modelConfig.ExposeIPv6()
+
 	logger.Debugf("started watching opened port ranges for the model")
 	return nil
 }
@@ -291,6 +314,13 @@ func (fw *Firewaller) loop() error {
 			if err := fw.unitsChanged(change); err != nil {
 				return errors.Trace(err)
 			}
+		case _, ok := <-fw.modelConfigWatcher.Changes():
+			if !ok {
+				return errors.New("model config watcher closed")
+			}
+			if err := fw.modelConfigChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		case change := <-fw.exposedChange:
 			change.applicationd.exposed = change.exposed
 			unitds := []*unitData{}
@@ -304,6 +334,35 @@ func (fw *Firewaller) loop() error {
 	}
 }
 
+// modelConfigChanged reopens the ports of all machines if IPv6 has
+// been enabled or disabled for the model.
+func (fw *Firewaller) modelConfigChanged() error {
+	modelConfig, err := fw.firewallerApi.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	exposeIPv6 := modelConfig.ExposeIPv6()
+	if exposeIPv6 == fw.exposeIPv6 {
+		return nil
+	}
+	logger.Debugf("exposing applications to IPv6 sources: %v", exposeIPv6)
+	fw.exposeIPv6 = exposeIPv6
+	for _, machined := range fw.machineds {
+		if err := fw.flushMachine(machined); err != nil {
+			return errors.Annotate(err, "cannot change firewall ports")
+		}
+	}
+	return nil
+}
+
+// addPublicCIDRs adds to cidrs the CIDRs matching all sources.
+func (fw *Firewaller) addPublicCIDRs(cidrs set.Strings) {
+	cidrs.Add("0.0.0.0/0")
+	if fw.exposeIPv6 {
+		cidrs.Add("::/0")
+	}
+}
+
 func (fw *Firewaller) relationIngressChanged(change *remoteRelationNetworkChange) error {
 	logger.Debugf("process remote relation ingress change for %v", change.relationTag)
 	relData, ok := fw.relationIngress[change.relationTag]
@@ -743,7 +802,7 @@ func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.In
 			cidrs := set.NewStrings()
 			// If the unit is exposed, allow access from everywhere.
 			if unitd.applicationd.exposed {
-				cidrs.Add("0.0.0.0/0")
+				fw.addPublicCIDRs(cidrs)
 			} else {
 				// Not exposed, so add any ingress rules required by remote relations.
 				if err := fw.updateForRemoteRelationIngress(unitd.applicationd.application.Tag(), cidrs); err != nil {
@@ -814,7 +873,7 @@ func (fw *Firewaller) updateForRemoteRelationIngress(appTag names.ApplicationTag
 		}
 		// No relevant firewall rule exists, so go public.
 		if newCidrs.Size() == 0 {
-			newCidrs.Add("0.0.0.0/0")
+			fw.addPublicCIDRs(newCidrs)
 		}
 	}
 	for _, cidr := range newCidrs.Values() {
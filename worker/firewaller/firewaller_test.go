@@ -543,6 +543,39 @@ func (s *InstanceModeSuite) TestSetClearExposedApplication(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestExposedApplicationExposeIPv6(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err := app.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0"),
+	})
+
+	// Preferring IPv6 addresses does not open the ports to IPv6
+	// sources; expose-ipv6 does.
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{"prefer-ipv6": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{"expose-ipv6": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0", "::/0"),
+	})
+
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{"expose-ipv6": false}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0"),
+	})
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
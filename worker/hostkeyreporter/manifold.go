@@ -73,9 +73,10 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 	}
 
 	worker, err := config.NewWorker(Config{
-		Facade:    facade,
-		MachineId: tag.Id(),
-		RootDir:   config.RootDir,
+		Facade:     facade,
+		MachineId:  tag.Id(),
+		RootDir:    config.RootDir,
+		ReloadSSHD: ReloadSSHD,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
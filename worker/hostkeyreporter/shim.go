@@ -4,6 +4,8 @@
 package hostkeyreporter
 
 import (
+	"os/exec"
+
 	"github.com/juju/errors"
 	worker "gopkg.in/juju/worker.v1"
 
@@ -22,3 +24,12 @@ func NewWorker(config Config) (worker.Worker, error) {
 	}
 	return worker, nil
 }
+
+// ReloadSSHD has the machine's sshd reload its configuration.
+func ReloadSSHD() error {
+	out, err := exec.Command("/bin/sh", "-c", "systemctl reload ssh || service ssh reload").CombinedOutput()
+	if err != nil {
+		return errors.Annotatef(err, "%s", out)
+	}
+	return nil
+}
@@ -7,9 +7,12 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
 	worker "gopkg.in/juju/worker.v1"
 	"gopkg.in/tomb.v1"
 
@@ -21,6 +24,7 @@ var logger = loggo.GetLogger("juju.worker.hostkeyreporter")
 // Facade exposes controller functionality to a Worker.
 type Facade interface {
 	ReportKeys(machineId string, publicKeys []string) error
+	SignKeys(machineId string, publicKeys []string) ([]string, error)
 }
 
 // Config defines the parameters of the hostkeyreporter worker.
@@ -28,6 +32,10 @@ type Config struct {
 	Facade    Facade
 	MachineId string
 	RootDir   string
+
+	// ReloadSSHD is called to have sshd pick up newly written
+	// host certificates.
+	ReloadSSHD func() error
 }
 
 // Validate returns an error if Config cannot drive a hostkeyreporter.
@@ -38,6 +46,9 @@ func (config Config) Validate() error {
 	if config.MachineId == "" {
 		return errors.NotValidf("empty MachineId")
 	}
+	if config.ReloadSSHD == nil {
+		return errors.NotValidf("nil ReloadSSHD")
+	}
 	return nil
 }
 
@@ -72,7 +83,7 @@ func (w *hostkeyreporter) Wait() error {
 }
 
 func (w *hostkeyreporter) run() error {
-	keys, err := w.readSSHKeys()
+	keys, keyFiles, err := w.readSSHKeys()
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -84,25 +95,41 @@ func (w *hostkeyreporter) run() error {
 		return errors.Trace(err)
 	}
 	logger.Debugf("%d SSH host keys reported for machine %s", len(keys), w.config.MachineId)
+
+	certs, err := w.config.Facade.SignKeys(w.config.MachineId, keys)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("controller cannot sign SSH host keys")
+		return dependency.ErrUninstall
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if len(certs) != len(keys) {
+		return errors.Errorf("expected %d SSH host certificates, got %d", len(keys), len(certs))
+	}
+	if err := w.installCertificates(keyFiles, certs); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("%d SSH host certificates installed for machine %s", len(certs), w.config.MachineId)
 	return dependency.ErrUninstall
 }
 
-func (w *hostkeyreporter) readSSHKeys() ([]string, error) {
+func (w *hostkeyreporter) readSSHKeys() ([]string, []string, error) {
 	sshDir := w.sshDir()
 	_, err := os.Stat(sshDir)
 	if os.IsNotExist(err) {
 		logger.Errorf("%s doesn't exist - giving up", sshDir)
-		return nil, dependency.ErrUninstall
+		return nil, nil, dependency.ErrUninstall
 	}
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
 	filenames, err := filepath.Glob(sshDir + "/ssh_host_*_key.pub")
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	keys := make([]string, 0, len(filenames))
+	keyFiles := make([]string, 0, len(filenames))
 	for _, filename := range filenames {
 		key, err := ioutil.ReadFile(filename)
 		if err != nil {
@@ -110,8 +137,47 @@ func (w *hostkeyreporter) readSSHKeys() ([]string, error) {
 			continue
 		}
 		keys = append(keys, string(key))
+		keyFiles = append(keyFiles, filename)
+	}
+	return keys, keyFiles, nil
+}
+
+// installCertificates writes each host certificate next to the public
+// key it certifies, configures sshd to present the certificates, and
+// reloads sshd so that it picks them up.
+func (w *hostkeyreporter) installCertificates(keyFiles, certs []string) error {
+	var hostCertificates []string
+	for i, keyFile := range keyFiles {
+		certFile := strings.TrimSuffix(keyFile, ".pub") + "-cert.pub"
+		if err := utils.AtomicWriteFile(certFile, []byte(certs[i]), 0644); err != nil {
+			return errors.Annotate(err, "writing SSH host certificate")
+		}
+		hostCertificates = append(hostCertificates, filepath.Join("/etc/ssh", filepath.Base(certFile)))
+	}
+
+	sshdConfigFile := filepath.Join(w.sshDir(), "sshd_config")
+	sshdConfig, err := ioutil.ReadFile(sshdConfigFile)
+	if err != nil {
+		return errors.Annotate(err, "reading sshd configuration")
+	}
+	present := set.NewStrings()
+	for _, line := range strings.Split(string(sshdConfig), "\n") {
+		present.Add(strings.TrimSpace(line))
+	}
+	var missing []string
+	for _, hostCertificate := range hostCertificates {
+		line := "HostCertificate " + hostCertificate
+		if !present.Contains(line) {
+			missing = append(missing, line)
+		}
+	}
+	if len(missing) > 0 {
+		config := strings.TrimSuffix(string(sshdConfig), "\n") + "\n" + strings.Join(missing, "\n") + "\n"
+		if err := utils.AtomicWriteFile(sshdConfigFile, []byte(config), 0644); err != nil {
+			return errors.Annotate(err, "writing sshd configuration")
+		}
 	}
-	return keys, nil
+	return errors.Annotate(w.config.ReloadSSHD(), "reloading sshd")
 }
 
 func (w *hostkeyreporter) sshDir() string {
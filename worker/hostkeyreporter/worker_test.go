@@ -47,6 +47,8 @@ func (s *Suite) SetUpTest(c *gc.C) {
 	writeKey("dsa")
 	writeKey("rsa")
 	writeKey("ecdsa")
+	err = ioutil.WriteFile(filepath.Join(sshDir, "sshd_config"), []byte("UsePAM yes"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
 
 	s.stub = new(jujutesting.Stub)
 	s.facade = newStubFacade(s.stub)
@@ -54,6 +56,10 @@ func (s *Suite) SetUpTest(c *gc.C) {
 		Facade:    s.facade,
 		MachineId: "42",
 		RootDir:   s.dir,
+		ReloadSSHD: func() error {
+			s.stub.AddCall("ReloadSSHD")
+			return s.stub.NextErr()
+		},
 	}
 }
 
@@ -101,7 +107,54 @@ func (s *Suite) TestSuccess(c *gc.C) {
 	c.Check(err, gc.Equals, dependency.ErrUninstall)
 	s.stub.CheckCalls(c, []jujutesting.StubCall{{
 		"ReportKeys", []interface{}{"42", []string{"dsa", "ecdsa", "rsa"}},
+	}, {
+		"SignKeys", []interface{}{"42", []string{"dsa", "ecdsa", "rsa"}},
+	}, {
+		"ReloadSSHD", nil,
 	}})
+
+	sshDir := filepath.Join(s.dir, "etc", "ssh")
+	for _, keyType := range []string{"dsa", "ecdsa", "rsa"} {
+		cert, err := ioutil.ReadFile(filepath.Join(sshDir, "ssh_host_"+keyType+"_key-cert.pub"))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(cert), gc.Equals, keyType+"-cert")
+	}
+	sshdConfig, err := ioutil.ReadFile(filepath.Join(sshDir, "sshd_config"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(sshdConfig), gc.Equals, `
+UsePAM yes
+HostCertificate /etc/ssh/ssh_host_dsa_key-cert.pub
+HostCertificate /etc/ssh/ssh_host_ecdsa_key-cert.pub
+HostCertificate /etc/ssh/ssh_host_rsa_key-cert.pub
+`[1:])
+
+	// Running again renews the certificates without adding
+	// the sshd configuration again.
+	w, err = hostkeyreporter.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.Equals, dependency.ErrUninstall)
+	again, err := ioutil.ReadFile(filepath.Join(sshDir, "sshd_config"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(again), gc.Equals, string(sshdConfig))
+}
+
+func (s *Suite) TestSignKeysNotSupported(c *gc.C) {
+	s.facade.signErr = errors.NotSupportedf("signing SSH host keys")
+	w, err := hostkeyreporter.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCallNames(c, "ReportKeys", "SignKeys")
+}
+
+func (s *Suite) TestSignKeysError(c *gc.C) {
+	s.facade.signErr = errors.New("blam")
+	w, err := hostkeyreporter.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.ErrorMatches, "blam")
+	s.stub.CheckCallNames(c, "ReportKeys", "SignKeys")
 }
 
 func newStubFacade(stub *jujutesting.Stub) *stubFacade {
@@ -113,9 +166,22 @@ func newStubFacade(stub *jujutesting.Stub) *stubFacade {
 type stubFacade struct {
 	stub      *jujutesting.Stub
 	reportErr error
+	signErr   error
 }
 
 func (c *stubFacade) ReportKeys(machineId string, publicKeys []string) error {
 	c.stub.AddCall("ReportKeys", machineId, publicKeys)
 	return c.reportErr
 }
+
+func (c *stubFacade) SignKeys(machineId string, publicKeys []string) ([]string, error) {
+	c.stub.AddCall("SignKeys", machineId, publicKeys)
+	if c.signErr != nil {
+		return nil, c.signErr
+	}
+	certs := make([]string, len(publicKeys))
+	for i, key := range publicKeys {
+		certs[i] = key + "-cert"
+	}
+	return certs, nil
+}
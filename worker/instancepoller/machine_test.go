@@ -361,6 +361,10 @@ type testMachineContext struct {
 	killErr         error
 	getInstanceInfo func(instance.Id) (instanceInfo, error)
 	dyingc          chan struct{}
+
+	recovery   recoveryConfig
+	restartErr error
+	restarted  []instance.Id
 }
 
 func (context *testMachineContext) kill(err error) {
@@ -374,6 +378,18 @@ func (context *testMachineContext) instanceInfo(id instance.Id) (instanceInfo, e
 	return context.getInstanceInfo(id)
 }
 
+func (context *testMachineContext) machineRecovery() (recoveryConfig, error) {
+	return context.recovery, nil
+}
+
+func (context *testMachineContext) restartInstance(id instance.Id) error {
+	if context.restartErr != nil {
+		return context.restartErr
+	}
+	context.restarted = append(context.restarted, id)
+	return nil
+}
+
 func (context *testMachineContext) dying() <-chan struct{} {
 	return context.dyingc
 }
@@ -388,7 +404,16 @@ type testMachine struct {
 	tag             names.MachineTag
 	instStatus      status.Status
 	instStatusInfo  string
+	instStatusSince *time.Time
 	status          status.Status
+	statusInfo      string
+	setStatusCount  int
+	agentPresence   bool
+	replacement     string
+	replaceErr      error
+	replaceCount    int
+	destroyErr      error
+	destroyCount    int
 	refresh         func() error
 	setAddressesErr error
 	// mu protects the following fields.
@@ -448,7 +473,11 @@ func (m *testMachine) IsManual() (bool, error) {
 func (m *testMachine) InstanceStatus() (params.StatusResult, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return params.StatusResult{Status: m.instStatus.String()}, nil
+	return params.StatusResult{
+		Status: m.instStatus.String(),
+		Info:   m.instStatusInfo,
+		Since:  m.instStatusSince,
+	}, nil
 }
 
 func (m *testMachine) SetInstanceStatus(machineStatus status.Status, info string, data map[string]interface{}) error {
@@ -459,6 +488,42 @@ func (m *testMachine) SetInstanceStatus(machineStatus status.Status, info string
 	return nil
 }
 
+func (m *testMachine) AgentPresence() (bool, error) {
+	return m.agentPresence, nil
+}
+
+func (m *testMachine) Replace() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replaceCount++
+	if m.replaceErr != nil {
+		return "", m.replaceErr
+	}
+	// As with the API, the machine is replaced even if it
+	// cannot then be destroyed.
+	return m.replacement, m.destroyErr
+}
+
+func (m *testMachine) ForceDestroy() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.destroyCount++
+	if m.destroyErr != nil {
+		return m.destroyErr
+	}
+	m.life = params.Dying
+	return nil
+}
+
+func (m *testMachine) SetStatus(machineStatus status.Status, info string, data map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = machineStatus
+	m.statusInfo = info
+	m.setStatusCount++
+	return nil
+}
+
 func (m *testMachine) SetProviderAddresses(addrs ...network.Address) error {
 	if m.setAddressesErr != nil {
 		return m.setAddressesErr
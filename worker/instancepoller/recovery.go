@@ -0,0 +1,255 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancepoller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/status"
+)
+
+// instanceNotFoundMessage is the instance status message recorded
+// for machines whose instances the provider no longer knows about.
+const instanceNotFoundMessage = "instance not found"
+
+// maxReplaceFailures is the number of consecutive times replacing a
+// lost machine must fail before the failure is reported in the
+// machine's status.
+const maxReplaceFailures = 3
+
+// recoveryConfig holds the model's machine recovery settings.
+type recoveryConfig struct {
+	// enabled is whether lost machines are recovered.
+	enabled bool
+
+	// replace is whether lost machines whose instances cannot be
+	// restarted are replaced.
+	replace bool
+
+	// timeout is how long a machine must have been lost before
+	// it is recovered.
+	timeout time.Duration
+}
+
+// machineRecovery recovers a machine whose agent has been lost and
+// whose instance has been stopped or removed outside of Juju, when
+// machine recovery is enabled for the model.
+//
+// The machine's instance is restarted if the provider supports it.
+// If the instance cannot be restarted, or has not recovered within
+// the recovery timeout of being restarted, the machine is replaced
+// by a new machine to which its units are moved, if replacement is
+// enabled for the model. Otherwise, or if replacing the machine keeps
+// failing, the failure is reported in the machine's status. If the
+// machine is replaced but cannot be destroyed, only destroying it is
+// retried, so that it is not replaced again.
+//
+// A machine whose instance was interrupted by the cloud, as reported
+// by the interruption watcher, is replaced as soon as its instance
+// has gone: interruptible capacity is reclaimed by the cloud, so
+// there is no point in waiting for or restarting it.
+type machineRecovery struct {
+	context machineContext
+	machine machine
+	clock   clock.Clock
+
+	// restarted records when the machine's instance was restarted,
+	// or is zero if it has not been restarted since it was last
+	// seen running.
+	restarted time.Time
+
+	// replaceFailures is the number of consecutive times replacing
+	// the machine has failed.
+	replaceFailures int
+
+	// statusMessage is the message last set in the machine's status
+	// to report the failure to recover it.
+	statusMessage string
+
+	// interrupted records whether the machine's instance was
+	// interrupted by the cloud, since reporting a failure to
+	// recover the machine replaces the status that said so.
+	interrupted bool
+
+	// replacement is the ID of the machine that replaced the
+	// machine, if it has been replaced.
+	replacement string
+}
+
+// check recovers the machine if it is due to be recovered, given the
+// latest polled instance info, the status of the machine's agent and
+// whether the machine's instance was interrupted by the cloud.
+// It returns the time after which the machine should next be checked,
+// or zero if it need not be checked any sooner than usual.
+//
+// Failures are logged rather than returned, so that a machine which
+// cannot be recovered does not stop the polling of other machines.
+func (r *machineRecovery) check(instInfo instanceInfo, machineStatus status.Status, interrupted bool) time.Duration {
+	wait, err := r.recover(instInfo, machineStatus, interrupted)
+	if err != nil {
+		logger.Warningf("cannot recover machine %v: %v", r.machine, err)
+		return 0
+	}
+	return wait
+}
+
+func (r *machineRecovery) recover(instInfo instanceInfo, machineStatus status.Status, interrupted bool) (time.Duration, error) {
+	if r.replacement != "" {
+		// The machine's units have been moved to its replacement,
+		// so all that is left to do is to destroy the machine.
+		if r.machine.Life() != params.Alive {
+			return 0, nil
+		}
+		return 0, r.destroyReplaced()
+	}
+	if instInfo.status.Status != status.Empty {
+		r.restarted = time.Time{}
+		r.replaceFailures = 0
+		r.statusMessage = ""
+		r.interrupted = false
+		return 0, nil
+	}
+	if interrupted {
+		r.interrupted = true
+	}
+	if r.machine.Life() != params.Alive {
+		return 0, nil
+	}
+	switch machineStatus {
+	case status.Pending, status.Allocating:
+		// The machine's agent has never started, so there is
+		// nothing to recover; provisioning problems are dealt
+		// with by the provisioner.
+		return 0, nil
+	}
+	cfg, err := r.context.machineRecovery()
+	if err != nil {
+		return 0, errors.Annotate(err, "getting machine recovery config")
+	}
+	if !cfg.enabled {
+		return 0, nil
+	}
+
+	// Only the recorded instance status is considered, since the
+	// polled status is also empty when the provider could not be
+	// queried.
+	instStatus, err := r.machine.InstanceStatus()
+	if err != nil {
+		return 0, errors.Annotate(err, "getting instance status")
+	}
+	if status.Status(instStatus.Status) != status.Empty || instStatus.Since == nil {
+		return 0, nil
+	}
+	alive, err := r.machine.AgentPresence()
+	if err != nil {
+		return 0, errors.Annotate(err, "getting agent presence")
+	}
+	if alive {
+		return 0, nil
+	}
+
+	if !r.interrupted {
+		lost := *instStatus.Since
+		if r.restarted.After(lost) {
+			lost = r.restarted
+		}
+		if wait := lost.Add(cfg.timeout).Sub(r.clock.Now()); wait > 0 {
+			return wait, nil
+		}
+
+		if r.restarted.IsZero() && instStatus.Info != instanceNotFoundMessage {
+			err := r.restartInstance()
+			if err == nil {
+				r.restarted = r.clock.Now()
+				return cfg.timeout, nil
+			}
+			if !errors.IsNotSupported(err) {
+				logger.Warningf("cannot restart instance of lost machine %v: %v", r.machine, err)
+			}
+		}
+	}
+	if !cfg.replace {
+		// Replacing a machine redeploys its units from scratch,
+		// so it is only done if the model's config allows it.
+		err := errors.New("instance cannot be restarted, and machine replacement is not enabled")
+		if r.interrupted {
+			err = errors.New("instance was interrupted, and machine replacement is not enabled")
+		}
+		r.reportFailure(err)
+		return 0, err
+	}
+	replacement, err := r.machine.Replace()
+	if replacement != "" {
+		logger.Infof("replaced lost machine %v with machine %v", r.machine, replacement)
+		r.replacement = replacement
+		r.replaceFailures = 0
+	}
+	if err != nil {
+		r.replaceFailures++
+		if r.replaceFailures >= maxReplaceFailures {
+			r.reportFailure(errors.Annotate(err, "replacing machine"))
+		}
+		return 0, errors.Annotate(err, "replacing machine")
+	}
+	return 0, nil
+}
+
+// destroyReplaced retries destroying the machine after it has been
+// replaced, reporting the failure in the machine's status if it
+// keeps failing.
+func (r *machineRecovery) destroyReplaced() error {
+	if err := r.machine.ForceDestroy(); err != nil {
+		err = errors.Annotatef(err, "destroying machine after replacing it with machine %v", r.replacement)
+		r.replaceFailures++
+		if r.replaceFailures >= maxReplaceFailures {
+			r.reportFailure(err)
+		}
+		return err
+	}
+	logger.Infof("destroyed lost machine %v after replacing it with machine %v", r.machine, r.replacement)
+	return nil
+}
+
+// reportFailure sets the machine's status to report the failure to
+// recover it, unless it already reports the same failure.
+func (r *machineRecovery) reportFailure(err error) {
+	message := fmt.Sprintf("cannot recover lost machine: %v", err)
+	if message == r.statusMessage {
+		return
+	}
+	if err := r.machine.SetStatus(status.Error, message, nil); err != nil {
+		logger.Warningf("cannot set status of lost machine %v: %v", r.machine, err)
+		return
+	}
+	r.statusMessage = message
+}
+
+func (r *machineRecovery) restartInstance() error {
+	instId, err := r.machine.InstanceId()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := r.context.restartInstance(instId); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("restarted instance %v of lost machine %v", instId, r.machine)
+	return nil
+}
+
+// instanceLost returns the instance info recorded for a machine whose
+// instance the provider no longer knows about.
+func instanceLost() instanceInfo {
+	return instanceInfo{
+		status: instance.InstanceStatus{
+			Status:  status.Empty,
+			Message: instanceNotFoundMessage,
+		},
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancepoller
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&recoverySuite{})
+
+type recoverySuite struct {
+	coretesting.BaseSuite
+
+	clock    *gitjujutesting.Clock
+	context  *testMachineContext
+	machine  *testMachine
+	recovery *machineRecovery
+	stopped  instanceInfo
+}
+
+func (s *recoverySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.reset()
+}
+
+// reset sets up a machine whose agent has been lost, and whose
+// instance was stopped longer than the recovery timeout ago.
+func (s *recoverySuite) reset() {
+	s.clock = gitjujutesting.NewClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	s.context = &testMachineContext{
+		dyingc: make(chan struct{}),
+		recovery: recoveryConfig{
+			enabled: true,
+			replace: true,
+			timeout: 15 * time.Minute,
+		},
+	}
+	since := s.clock.Now().Add(-20 * time.Minute)
+	s.machine = &testMachine{
+		tag:             names.NewMachineTag("99"),
+		instanceId:      "i1234",
+		life:            params.Alive,
+		status:          status.Started,
+		instStatus:      status.Empty,
+		instStatusInfo:  "stopped",
+		instStatusSince: &since,
+		replacement:     "100",
+	}
+	s.recovery = &machineRecovery{
+		context: s.context,
+		machine: s.machine,
+		clock:   s.clock,
+	}
+	s.stopped = instanceInfo{nil, instance.InstanceStatus{Status: status.Empty, Message: "stopped"}}
+}
+
+func (s *recoverySuite) TestRestartsInstance(c *gc.C) {
+	wait := s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(wait, gc.Equals, 15*time.Minute)
+	c.Assert(s.context.restarted, jc.DeepEquals, []instance.Id{"i1234"})
+	c.Assert(s.machine.replaceCount, gc.Equals, 0)
+}
+
+func (s *recoverySuite) TestReplacesMachineIfRestartFails(c *gc.C) {
+	s.context.restartErr = errors.New("instance terminated")
+	wait := s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(wait, gc.Equals, time.Duration(0))
+	c.Assert(s.machine.replaceCount, gc.Equals, 1)
+}
+
+func (s *recoverySuite) TestReplacesMachineIfRestartNotSupported(c *gc.C) {
+	s.context.restartErr = errors.NotSupportedf("restarting instances")
+	s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(s.machine.replaceCount, gc.Equals, 1)
+}
+
+func (s *recoverySuite) TestReplacesMachineIfRestartedInstanceDoesNotRecover(c *gc.C) {
+	s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(s.context.restarted, gc.HasLen, 1)
+
+	// The instance is given the recovery timeout to come back
+	// after being restarted.
+	s.clock.Advance(10 * time.Minute)
+	wait := s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(wait, gc.Equals, 5*time.Minute)
+	c.Assert(s.machine.replaceCount, gc.Equals, 0)
+
+	s.clock.Advance(5 * time.Minute)
+	s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(s.context.restarted, gc.HasLen, 1)
+	c.Assert(s.machine.replaceCount, gc.Equals, 1)
+}
+
+func (s *recoverySuite) TestRestartedInstanceRecovers(c *gc.C) {
+	s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(s.context.restarted, gc.HasLen, 1)
+
+	running := instanceInfo{testAddrs, instance.InstanceStatus{Status: status.Running, Message: "running"}}
+	c.Assert(s.recovery.check(running, status.Started, false), gc.Equals, time.Duration(0))
+	c.Assert(s.recovery.restarted.IsZero(), jc.IsTrue)
+}
+
+func (s *recoverySuite) TestReplacesMachineIfInstanceNotFound(c *gc.C) {
+	s.machine.instStatusInfo = instanceNotFoundMessage
+	s.recovery.check(instanceLost(), status.Started, false)
+	c.Assert(s.context.restarted, gc.HasLen, 0)
+	c.Assert(s.machine.replaceCount, gc.Equals, 1)
+}
+
+func (s *recoverySuite) TestReplaceDisabled(c *gc.C) {
+	s.context.recovery.replace = false
+	s.context.restartErr = errors.NotSupportedf("restarting instances")
+	wait := s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(wait, gc.Equals, time.Duration(0))
+	c.Assert(s.machine.replaceCount, gc.Equals, 0)
+	c.Assert(s.machine.status, gc.Equals, status.Error)
+	c.Assert(s.machine.statusInfo, gc.Equals,
+		"cannot recover lost machine: instance cannot be restarted, and machine replacement is not enabled")
+
+	// The same failure is only reported once.
+	s.recovery.check(s.stopped, status.Error, false)
+	c.Assert(s.machine.setStatusCount, gc.Equals, 1)
+}
+
+func (s *recoverySuite) TestReportsRepeatedReplaceFailures(c *gc.C) {
+	s.context.restartErr = errors.NotSupportedf("restarting instances")
+	s.machine.replaceErr = errors.New("boom")
+	for i := 1; i < maxReplaceFailures; i++ {
+		s.recovery.check(s.stopped, status.Started, false)
+		c.Assert(s.machine.replaceCount, gc.Equals, i)
+		c.Assert(s.machine.setStatusCount, gc.Equals, 0)
+	}
+	s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(s.machine.replaceCount, gc.Equals, maxReplaceFailures)
+	c.Assert(s.machine.status, gc.Equals, status.Error)
+	c.Assert(s.machine.statusInfo, gc.Equals, "cannot recover lost machine: replacing machine: boom")
+
+	// The failures are forgotten once the instance is running again.
+	running := instanceInfo{testAddrs, instance.InstanceStatus{Status: status.Running, Message: "running"}}
+	s.recovery.check(running, status.Error, false)
+	c.Assert(s.recovery.replaceFailures, gc.Equals, 0)
+}
+
+func (s *recoverySuite) TestRetriesOnlyDestroyAfterReplacing(c *gc.C) {
+	s.context.restartErr = errors.NotSupportedf("restarting instances")
+	s.machine.destroyErr = errors.New("boom")
+	s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(s.machine.replaceCount, gc.Equals, 1)
+	c.Assert(s.machine.destroyCount, gc.Equals, 0)
+
+	// The machine is not replaced again; destroying it is retried,
+	// and the failure reported if it keeps failing.
+	for i := 1; i < maxReplaceFailures; i++ {
+		s.recovery.check(s.stopped, status.Started, false)
+		c.Assert(s.machine.replaceCount, gc.Equals, 1)
+		c.Assert(s.machine.destroyCount, gc.Equals, i)
+	}
+	c.Assert(s.machine.status, gc.Equals, status.Error)
+	c.Assert(s.machine.statusInfo, gc.Equals,
+		"cannot recover lost machine: destroying machine after replacing it with machine 100: boom")
+
+	s.machine.destroyErr = nil
+	s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(s.machine.replaceCount, gc.Equals, 1)
+	c.Assert(s.machine.destroyCount, gc.Equals, maxReplaceFailures)
+	c.Assert(s.machine.life, gc.Equals, params.Dying)
+
+	// Once the machine is dying, there is nothing left to do.
+	s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(s.machine.destroyCount, gc.Equals, maxReplaceFailures)
+}
+
+func (s *recoverySuite) TestWaitsForTimeout(c *gc.C) {
+	since := s.clock.Now().Add(-5 * time.Minute)
+	s.machine.instStatusSince = &since
+	wait := s.recovery.check(s.stopped, status.Started, false)
+	c.Assert(wait, gc.Equals, 10*time.Minute)
+	c.Assert(s.context.restarted, gc.HasLen, 0)
+	c.Assert(s.machine.replaceCount, gc.Equals, 0)
+}
+
+func (s *recoverySuite) TestReplacesInterruptedMachine(c *gc.C) {
+	// Interrupted machines are replaced without waiting for the
+	// timeout or restarting their instances.
+	since := s.clock.Now().Add(-time.Minute)
+	s.machine.instStatusSince = &since
+	wait := s.recovery.check(s.stopped, status.Error, true)
+	c.Assert(wait, gc.Equals, time.Duration(0))
+	c.Assert(s.context.restarted, gc.HasLen, 0)
+	c.Assert(s.machine.replaceCount, gc.Equals, 1)
+}
+
+func (s *recoverySuite) TestInterruptedReplaceDisabled(c *gc.C) {
+	s.context.recovery.replace = false
+	s.recovery.check(s.stopped, status.Error, true)
+	c.Assert(s.context.restarted, gc.HasLen, 0)
+	c.Assert(s.machine.replaceCount, gc.Equals, 0)
+	c.Assert(s.machine.statusInfo, gc.Equals,
+		"cannot recover lost machine: instance was interrupted, and machine replacement is not enabled")
+
+	// Reporting the failure replaces the status recording the
+	// interruption, but the interruption is remembered.
+	s.recovery.check(s.stopped, status.Error, false)
+	c.Assert(s.context.restarted, gc.HasLen, 0)
+	c.Assert(s.machine.setStatusCount, gc.Equals, 1)
+}
+
+func (s *recoverySuite) TestNoRecovery(c *gc.C) {
+	for i, test := range []struct {
+		about         string
+		mutate        func()
+		instInfo      instanceInfo
+		machineStatus status.Status
+	}{{
+		about:  "recovery disabled",
+		mutate: func() { s.context.recovery.enabled = false },
+	}, {
+		about:  "agent alive",
+		mutate: func() { s.machine.agentPresence = true },
+	}, {
+		about:  "recorded instance status running",
+		mutate: func() { s.machine.instStatus = status.Running },
+	}, {
+		about:    "polled instance status running",
+		instInfo: instanceInfo{testAddrs, instance.InstanceStatus{Status: status.Running}},
+	}, {
+		about:  "machine dying",
+		mutate: func() { s.machine.life = params.Dying },
+	}, {
+		about:         "agent never started",
+		machineStatus: status.Pending,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		s.reset()
+		if test.mutate != nil {
+			test.mutate()
+		}
+		if test.instInfo.status.Status == "" {
+			test.instInfo = s.stopped
+		}
+		if test.machineStatus == "" {
+			test.machineStatus = status.Started
+		}
+		c.Check(s.recovery.check(test.instInfo, test.machineStatus, false), gc.Equals, time.Duration(0))
+		c.Check(s.context.restarted, gc.HasLen, 0)
+		c.Check(s.machine.replaceCount, gc.Equals, 0)
+	}
+}
+
+func (s *recoverySuite) TestPollRecordsLostInstance(c *gc.C) {
+	s.machine.instStatus = status.Running
+	s.machine.instStatusInfo = "running"
+	s.machine.addresses = testAddrs
+	s.context.getInstanceInfo = func(id instance.Id) (instanceInfo, error) {
+		return instanceInfo{}, errors.NotFoundf("instance %v", id)
+	}
+	instInfo, err := pollInstanceInfo(s.context, s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instInfo, jc.DeepEquals, instanceLost())
+	c.Assert(s.machine.instStatus, gc.Equals, status.Empty)
+	c.Assert(s.machine.instStatusInfo, gc.Equals, instanceNotFoundMessage)
+
+	// The addresses of the lost instance are left alone.
+	c.Assert(s.machine.addresses, jc.DeepEquals, testAddrs)
+	c.Assert(s.machine.setAddressCount, gc.Equals, 0)
+}
@@ -12,10 +12,12 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/interruptionwatcher"
 )
 
 var logger = loggo.GetLogger("juju.worker.instancepoller")
@@ -46,6 +48,10 @@ type machine interface {
 	Life() params.Life
 	Status() (params.StatusResult, error)
 	IsManual() (bool, error)
+	AgentPresence() (bool, error)
+	Replace() (string, error)
+	ForceDestroy() error
+	SetStatus(status status.Status, message string, data map[string]interface{}) error
 }
 
 type instanceInfo struct {
@@ -65,6 +71,8 @@ type lifetimeContext interface {
 type machineContext interface {
 	lifetimeContext
 	instanceInfo(id instance.Id) (instanceInfo, error)
+	machineRecovery() (recoveryConfig, error)
+	restartInstance(id instance.Id) error
 }
 
 type updaterContext interface {
@@ -186,6 +194,11 @@ func machineLoop(context machineContext, m machine, lifeChanged <-chan struct{},
 	// a machine's address and machine agent to start, and a long one when it already
 	// has an address and the machine agent is started.
 	pollInterval := ShortPoll
+	recovery := &machineRecovery{
+		context: context,
+		machine: m,
+		clock:   clock,
+	}
 	pollInstance := func() error {
 		instInfo, err := pollInstanceInfo(context, m)
 		if err != nil {
@@ -193,12 +206,14 @@ func machineLoop(context machineContext, m machine, lifeChanged <-chan struct{},
 		}
 
 		machineStatus := status.Pending
+		interrupted := false
 		if err == nil {
 			if statusInfo, err := m.Status(); err != nil {
 				logger.Warningf("cannot get current machine status for machine %v: %v", m.Id(), err)
 			} else {
 				// TODO(perrito666) add status validation.
 				machineStatus = status.Status(statusInfo.Status)
+				interrupted, _ = statusInfo.Data[interruptionwatcher.InterruptedKey].(bool)
 			}
 		}
 
@@ -217,6 +232,12 @@ func machineLoop(context machineContext, m machine, lifeChanged <-chan struct{},
 				}
 			}
 		}
+
+		// Poll again when the machine is due to be recovered,
+		// if that is sooner than usual.
+		if wait := recovery.check(instInfo, machineStatus, interrupted); wait > 0 && wait < pollInterval {
+			pollInterval = wait
+		}
 		return nil
 	}
 
@@ -259,6 +280,13 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 		return instanceInfo{}, errors.Annotate(err, "cannot get machine's instance id")
 	}
 	instInfo, err = context.instanceInfo(instId)
+	lost := errors.IsNotFound(err) || errors.Cause(err) == environs.ErrNoInstances
+	if lost {
+		// The instance has been removed outside of Juju. Record
+		// that in the instance status, so that the machine can
+		// be recovered, but leave its addresses alone.
+		instInfo, err = instanceLost(), nil
+	}
 	if err != nil {
 		// TODO (anastasiamac 2016-02-01) This does not look like it needs to be removed now.
 		if params.IsCodeNotImplemented(err) {
@@ -287,7 +315,7 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 		}
 
 	}
-	if m.Life() != params.Dead {
+	if !lost && m.Life() != params.Dead {
 		providerAddresses, err := m.ProviderAddresses()
 		if err != nil {
 			return instanceInfo{}, err
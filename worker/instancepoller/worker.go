@@ -12,6 +12,7 @@ import (
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/api/instancepoller"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/worker/catacomb"
 )
@@ -114,6 +115,28 @@ func (u *updaterWorker) instanceInfo(id instance.Id) (instanceInfo, error) {
 	return u.aggregator.instanceInfo(id)
 }
 
+// machineRecovery is part of the machineContext interface.
+func (u *updaterWorker) machineRecovery() (recoveryConfig, error) {
+	cfg, err := u.config.Facade.ModelConfig()
+	if err != nil {
+		return recoveryConfig{}, errors.Trace(err)
+	}
+	return recoveryConfig{
+		enabled: cfg.MachineRecovery(),
+		replace: cfg.MachineRecoveryReplace(),
+		timeout: cfg.MachineRecoveryTimeout(),
+	}, nil
+}
+
+// restartInstance is part of the machineContext interface.
+func (u *updaterWorker) restartInstance(id instance.Id) error {
+	restarter, ok := u.config.Environ.(environs.InstanceRestarter)
+	if !ok {
+		return errors.NotSupportedf("restarting instances")
+	}
+	return restarter.RestartInstances(id)
+}
+
 // kill is part of the lifetimeContext interface.
 func (u *updaterWorker) kill(err error) {
 	u.catacomb.Kill(err)
@@ -0,0 +1,132 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package interruptionwatcher
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// EC2MetadataURL is the base URL of the EC2 instance
+	// metadata service.
+	EC2MetadataURL = "http://169.254.169.254"
+
+	// GCEMetadataURL is the base URL of the GCE instance
+	// metadata service.
+	GCEMetadataURL = "http://metadata.google.internal"
+)
+
+// Notice describes the cloud's notice that it is about to
+// interrupt the machine's instance.
+type Notice struct {
+	// Action is the action the cloud will take, e.g. "terminate"
+	// or "stop".
+	Action string
+
+	// Time is the time at which the action will be taken, or the
+	// zero time if the action is already under way.
+	Time time.Time
+}
+
+// Checker checks for notice of the machine's instance being
+// interrupted.
+type Checker interface {
+	// Check returns the interruption notice for the instance, or
+	// nil if the instance is not due to be interrupted.
+	Check() (*Notice, error)
+}
+
+// NewChecker returns a Checker for instances of the cloud with the
+// given provider type, which queries the metadata service at baseURL
+// using client. If baseURL is empty, the cloud's metadata service is
+// used. Only EC2 spot instances and GCE preemptible instances can be
+// interrupted; NewChecker returns an error satisfying
+// errors.IsNotSupported for any other provider type.
+func NewChecker(providerType string, client *http.Client, baseURL string) (Checker, error) {
+	switch providerType {
+	case "ec2":
+		if baseURL == "" {
+			baseURL = EC2MetadataURL
+		}
+		return &ec2Checker{client, baseURL}, nil
+	case "gce":
+		if baseURL == "" {
+			baseURL = GCEMetadataURL
+		}
+		return &gceChecker{client, baseURL}, nil
+	}
+	return nil, errors.NotSupportedf("instance interruption on %q", providerType)
+}
+
+// ec2Checker checks for EC2 spot instance interruption notices,
+// which are published in the instance metadata two minutes before
+// the instance is interrupted.
+type ec2Checker struct {
+	client  *http.Client
+	baseURL string
+}
+
+// Check is part of the Checker interface.
+func (c *ec2Checker) Check() (*Notice, error) {
+	resp, err := c.client.Get(c.baseURL + "/latest/meta-data/spot/instance-action")
+	if err != nil {
+		return nil, errors.Annotate(err, "querying EC2 instance metadata")
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// There is no notice, or this is not a spot instance.
+		return nil, nil
+	default:
+		return nil, errors.Errorf("querying EC2 instance metadata: %s", resp.Status)
+	}
+	var action struct {
+		Action string    `json:"action"`
+		Time   time.Time `json:"time"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&action); err != nil {
+		return nil, errors.Annotate(err, "decoding EC2 instance action")
+	}
+	return &Notice{Action: action.Action, Time: action.Time}, nil
+}
+
+// gceChecker checks whether a GCE preemptible instance has been
+// preempted. GCE gives 30 seconds notice, during which the instance
+// metadata reports that the instance is preempted.
+type gceChecker struct {
+	client  *http.Client
+	baseURL string
+}
+
+// Check is part of the Checker interface.
+func (c *gceChecker) Check() (*Notice, error) {
+	req, err := http.NewRequest("GET", c.baseURL+"/computeMetadata/v1/instance/preempted", nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "querying GCE instance metadata")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("querying GCE instance metadata: %s", resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading GCE instance metadata")
+	}
+	if strings.TrimSpace(string(body)) != "TRUE" {
+		return nil, nil
+	}
+	return &Notice{Action: "stop"}, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package interruptionwatcher_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/interruptionwatcher"
+)
+
+type CheckerSuite struct {
+	testing.IsolationSuite
+	requests []*http.Request
+	status   int
+	body     string
+}
+
+var _ = gc.Suite(&CheckerSuite{})
+
+func (s *CheckerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.requests = nil
+	s.status = http.StatusOK
+	s.body = ""
+}
+
+func (s *CheckerSuite) checker(c *gc.C, providerType string) interruptionwatcher.Checker {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.requests = append(s.requests, req)
+		w.WriteHeader(s.status)
+		w.Write([]byte(s.body))
+	}))
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	checker, err := interruptionwatcher.NewChecker(providerType, http.DefaultClient, server.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	return checker
+}
+
+func (s *CheckerSuite) TestNewCheckerNotSupported(c *gc.C) {
+	_, err := interruptionwatcher.NewChecker("maas", http.DefaultClient, "")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *CheckerSuite) TestEC2NoNotice(c *gc.C) {
+	checker := s.checker(c, "ec2")
+	s.status = http.StatusNotFound
+	notice, err := checker.Check()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(notice, gc.IsNil)
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Assert(s.requests[0].URL.Path, gc.Equals, "/latest/meta-data/spot/instance-action")
+}
+
+func (s *CheckerSuite) TestEC2Notice(c *gc.C) {
+	checker := s.checker(c, "ec2")
+	s.body = `{"action": "terminate", "time": "2018-03-01T12:30:00Z"}`
+	notice, err := checker.Check()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(notice, jc.DeepEquals, &interruptionwatcher.Notice{
+		Action: "terminate",
+		Time:   time.Date(2018, 3, 1, 12, 30, 0, 0, time.UTC),
+	})
+}
+
+func (s *CheckerSuite) TestEC2Error(c *gc.C) {
+	checker := s.checker(c, "ec2")
+	s.status = http.StatusInternalServerError
+	_, err := checker.Check()
+	c.Assert(err, gc.ErrorMatches, "querying EC2 instance metadata: 500 Internal Server Error")
+}
+
+func (s *CheckerSuite) TestGCENotPreempted(c *gc.C) {
+	checker := s.checker(c, "gce")
+	s.body = "FALSE"
+	notice, err := checker.Check()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(notice, gc.IsNil)
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Assert(s.requests[0].URL.Path, gc.Equals, "/computeMetadata/v1/instance/preempted")
+	c.Assert(s.requests[0].Header.Get("Metadata-Flavor"), gc.Equals, "Google")
+}
+
+func (s *CheckerSuite) TestGCEPreempted(c *gc.C) {
+	checker := s.checker(c, "gce")
+	s.body = "TRUE"
+	notice, err := checker.Check()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(notice, jc.DeepEquals, &interruptionwatcher.Notice{Action: "stop"})
+}
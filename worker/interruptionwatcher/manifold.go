@@ -0,0 +1,104 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package interruptionwatcher
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a
+// Manifold will depend, and the worker's configuration.
+type ManifoldConfig struct {
+	AgentName     string
+	APICallerName string
+	Clock         clock.Clock
+	Interval      time.Duration
+
+	NewFacade          func(base.APICaller, names.MachineTag) (Facade, error)
+	MachineConstraints func(base.APICaller, names.MachineTag) (constraints.Value, error)
+	NewChecker         func(providerType string) (Checker, error)
+	NewWorker          func(Config) (worker.Worker, error)
+}
+
+// start is an engine.AgentAPIStartFunc that draws context from the
+// ManifoldConfig on which it is defined.
+func (config ManifoldConfig) start(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	agentConfig := a.CurrentConfig()
+	tag, ok := agentConfig.Tag().(names.MachineTag)
+	if !ok {
+		return nil, errors.New("interruptionwatcher may only be used with a machine agent")
+	}
+	if names.IsContainerMachine(tag.Id()) {
+		// Containers run on their host's instance, and
+		// are not interrupted on their own.
+		logger.Debugf("not watching for instance interruption of container %s", tag.Id())
+		return nil, dependency.ErrUninstall
+	}
+
+	providerType := agentConfig.Value(agent.ProviderType)
+	checker, err := config.NewChecker(providerType)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not watching for instance interruption: %v", err)
+		return nil, dependency.ErrUninstall
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Only instances started with interruptible capacity can be
+	// interrupted, and a provisioned machine's constraints cannot
+	// change, so there is no need to poll the metadata service of
+	// any other machine.
+	cons, err := config.MachineConstraints(apiCaller, tag)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting machine constraints")
+	}
+	if !cons.HasInterruptible() {
+		logger.Debugf("not watching for instance interruption of non-interruptible machine %s", tag.Id())
+		return nil, dependency.ErrUninstall
+	}
+
+	facade, err := config.NewFacade(apiCaller, tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	worker, err := config.NewWorker(Config{
+		Facade:   facade,
+		Checker:  checker,
+		Clock:    config.Clock,
+		Interval: config.Interval,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// Manifold returns a dependency manifold that runs an interruption
+// watcher, using the resources named or defined in the supplied config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	typedConfig := engine.AgentAPIManifoldConfig{
+		AgentName:     config.AgentName,
+		APICallerName: config.APICallerName,
+	}
+	return engine.AgentAPIManifold(typedConfig, config.start)
+}
+
+// NewMetadataChecker returns a Checker that queries the metadata
+// service of the cloud with the given provider type.
+func NewMetadataChecker(providerType string) (Checker, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return NewChecker(providerType, client, "")
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package interruptionwatcher_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/interruptionwatcher"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	stub         testing.Stub
+	tag          names.Tag
+	providerType string
+	constraints  constraints.Value
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.tag = names.NewMachineTag("4")
+	s.providerType = "ec2"
+	s.constraints = constraints.MustParse("interruptible=true")
+}
+
+func (s *ManifoldSuite) manifold() dependency.Manifold {
+	return interruptionwatcher.Manifold(interruptionwatcher.ManifoldConfig{
+		AgentName:     "agent",
+		APICallerName: "api-caller",
+		NewFacade: func(base.APICaller, names.MachineTag) (interruptionwatcher.Facade, error) {
+			s.stub.AddCall("NewFacade")
+			return &mockFacade{stub: &s.stub}, s.stub.NextErr()
+		},
+		MachineConstraints: func(_ base.APICaller, tag names.MachineTag) (constraints.Value, error) {
+			s.stub.AddCall("MachineConstraints", tag)
+			return s.constraints, s.stub.NextErr()
+		},
+		NewChecker: func(providerType string) (interruptionwatcher.Checker, error) {
+			s.stub.AddCall("NewChecker", providerType)
+			if providerType != "ec2" {
+				return nil, errors.NotSupportedf("instance interruption on %q", providerType)
+			}
+			return &mockChecker{stub: &s.stub}, nil
+		},
+		NewWorker: func(config interruptionwatcher.Config) (worker.Worker, error) {
+			s.stub.AddCall("NewWorker")
+			return &fakeWorker{}, s.stub.NextErr()
+		},
+	})
+}
+
+func (s *ManifoldSuite) context() dependency.Context {
+	return dt.StubContext(nil, map[string]interface{}{
+		"agent":      &fakeAgent{tag: s.tag, providerType: s.providerType},
+		"api-caller": &fakeCaller{},
+	})
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Check(s.manifold().Inputs, jc.DeepEquals, []string{"agent", "api-caller"})
+}
+
+func (s *ManifoldSuite) TestStartInterruptible(c *gc.C) {
+	w, err := s.manifold().Start(s.context())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, gc.FitsTypeOf, &fakeWorker{})
+	s.stub.CheckCallNames(c, "NewChecker", "MachineConstraints", "NewFacade", "NewWorker")
+	s.stub.CheckCall(c, 1, "MachineConstraints", names.NewMachineTag("4"))
+}
+
+func (s *ManifoldSuite) TestStartNotInterruptible(c *gc.C) {
+	s.constraints = constraints.MustParse("mem=4G")
+	_, err := s.manifold().Start(s.context())
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCallNames(c, "NewChecker", "MachineConstraints")
+}
+
+func (s *ManifoldSuite) TestStartContainer(c *gc.C) {
+	s.tag = names.NewMachineTag("4/lxd/0")
+	_, err := s.manifold().Start(s.context())
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *ManifoldSuite) TestStartProviderNotSupported(c *gc.C) {
+	s.providerType = "maas"
+	_, err := s.manifold().Start(s.context())
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	s.stub.CheckCallNames(c, "NewChecker")
+}
+
+func (s *ManifoldSuite) TestStartConstraintsError(c *gc.C) {
+	s.stub.SetErrors(errors.New("boom"))
+	_, err := s.manifold().Start(s.context())
+	c.Assert(err, gc.ErrorMatches, "getting machine constraints: boom")
+}
+
+type fakeAgent struct {
+	agent.Agent
+	tag          names.Tag
+	providerType string
+}
+
+func (a *fakeAgent) CurrentConfig() agent.Config {
+	return &fakeConfig{tag: a.tag, providerType: a.providerType}
+}
+
+type fakeConfig struct {
+	agent.Config
+	tag          names.Tag
+	providerType string
+}
+
+func (c *fakeConfig) Tag() names.Tag {
+	return c.tag
+}
+
+func (c *fakeConfig) Value(key string) string {
+	if key == agent.ProviderType {
+		return c.providerType
+	}
+	return ""
+}
+
+type fakeCaller struct {
+	base.APICaller
+}
+
+type fakeWorker struct {
+	worker.Worker
+}
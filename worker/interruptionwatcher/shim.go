@@ -0,0 +1,37 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package interruptionwatcher
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/machiner"
+	"github.com/juju/juju/api/provisioner"
+	"github.com/juju/juju/constraints"
+)
+
+// NewFacade returns a Facade for setting the status of the
+// machine with the given tag.
+func NewFacade(apiCaller base.APICaller, tag names.MachineTag) (Facade, error) {
+	machine, err := machiner.NewState(apiCaller).Machine(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machine, nil
+}
+
+// MachineConstraints returns the constraints of the machine with the
+// given tag.
+func MachineConstraints(apiCaller base.APICaller, tag names.MachineTag) (constraints.Value, error) {
+	results, err := provisioner.NewState(apiCaller).Machines(tag)
+	if err != nil {
+		return constraints.Value{}, errors.Trace(err)
+	}
+	if results[0].Err != nil {
+		return constraints.Value{}, errors.Trace(results[0].Err)
+	}
+	return results[0].Machine.Constraints()
+}
@@ -0,0 +1,153 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package interruptionwatcher provides a worker that watches for the
+// cloud's notice that it is about to interrupt the machine's instance,
+// as happens to EC2 spot instances and GCE preemptible instances, and
+// reports the interruption in the machine's status.
+//
+// The worker only runs on host machines whose constraints ask for
+// interruptible capacity. It does not recover the machine itself:
+// the controller's instance poller replaces interrupted machines once
+// their instances have gone, if machine recovery and replacement are
+// enabled for the model. Otherwise the user can see from the
+// machine's status why its agent went away.
+package interruptionwatcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/catacomb"
+	"github.com/juju/juju/worker/dependency"
+)
+
+var logger = loggo.GetLogger("juju.worker.interruptionwatcher")
+
+// InterruptedKey is the key in the machine's status data that is set
+// to true when the machine's instance is being interrupted.
+const InterruptedKey = "interrupted"
+
+// Facade exposes the controller functionality required by the worker.
+type Facade interface {
+	// SetStatus sets the status of the machine.
+	SetStatus(status status.Status, info string, data map[string]interface{}) error
+}
+
+// Config holds the dependencies and configuration necessary to
+// drive an interruption watcher.
+type Config struct {
+	Facade   Facade
+	Checker  Checker
+	Clock    clock.Clock
+	Interval time.Duration
+}
+
+// Validate returns an error if config cannot be expected to drive
+// an interruption watcher.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Checker == nil {
+		return errors.NotValidf("nil Checker")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	return nil
+}
+
+// New returns a worker that periodically checks for notice of the
+// machine's instance being interrupted. When notice is given, the
+// worker sets the machine's status to error, with InterruptedKey set
+// in the status data, and uninstalls itself.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &interruptionWatcher{config: config}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type interruptionWatcher struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *interruptionWatcher) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *interruptionWatcher) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *interruptionWatcher) loop() error {
+	var interval time.Duration
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(interval):
+		}
+		interval = w.config.Interval
+
+		notice, err := w.config.Checker.Check()
+		if err != nil {
+			// The metadata service may be briefly unavailable;
+			// try again at the next interval.
+			logger.Warningf("cannot check for instance interruption: %v", err)
+			continue
+		}
+		if notice == nil {
+			continue
+		}
+		logger.Warningf("instance is being interrupted: %s", noticeString(notice))
+		if err := w.config.Facade.SetStatus(
+			status.Error,
+			"instance interrupted by cloud: "+noticeString(notice),
+			noticeData(notice),
+		); err != nil {
+			return errors.Annotate(err, "setting machine status")
+		}
+		// There is nothing more to do; the instance is going away.
+		return dependency.ErrUninstall
+	}
+}
+
+func noticeString(notice *Notice) string {
+	if notice.Time.IsZero() {
+		return notice.Action
+	}
+	return fmt.Sprintf("%s at %s", notice.Action, notice.Time.UTC().Format(time.RFC3339))
+}
+
+func noticeData(notice *Notice) map[string]interface{} {
+	data := map[string]interface{}{
+		InterruptedKey: true,
+		"action":       notice.Action,
+	}
+	if !notice.Time.IsZero() {
+		data["time"] = notice.Time.UTC().Format(time.RFC3339)
+	}
+	return data
+}
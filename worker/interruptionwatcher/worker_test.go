@@ -0,0 +1,177 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package interruptionwatcher_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/interruptionwatcher"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+	stub    testing.Stub
+	clock   *testing.Clock
+	facade  *mockFacade
+	checker *mockChecker
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.clock = testing.NewClock(time.Now())
+	s.facade = &mockFacade{stub: &s.stub}
+	s.checker = &mockChecker{stub: &s.stub}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := interruptionwatcher.New(interruptionwatcher.Config{
+		Facade:   s.facade,
+		Checker:  s.checker,
+		Clock:    s.clock,
+		Interval: 5 * time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := interruptionwatcher.New(interruptionwatcher.Config{
+		Checker:  s.checker,
+		Clock:    s.clock,
+		Interval: time.Second,
+	})
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+
+	_, err = interruptionwatcher.New(interruptionwatcher.Config{
+		Facade:   s.facade,
+		Clock:    s.clock,
+		Interval: time.Second,
+	})
+	c.Assert(err, gc.ErrorMatches, "nil Checker not valid")
+
+	_, err = interruptionwatcher.New(interruptionwatcher.Config{
+		Facade:  s.facade,
+		Checker: s.checker,
+		Clock:   s.clock,
+	})
+	c.Assert(err, gc.ErrorMatches, "non-positive Interval not valid")
+}
+
+func (s *WorkerSuite) TestNoNotice(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	waitAlarms(c, s.clock, 2)
+	s.clock.Advance(5 * time.Second)
+	waitAlarms(c, s.clock, 1)
+	workertest.CleanKill(c, w)
+	s.stub.CheckCallNames(c, "Check", "Check")
+}
+
+func (s *WorkerSuite) TestCheckErrorRetries(c *gc.C) {
+	s.stub.SetErrors(errors.New("metadata unavailable"))
+	w := s.startWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	waitAlarms(c, s.clock, 2)
+	s.clock.Advance(5 * time.Second)
+	waitAlarms(c, s.clock, 1)
+	workertest.CheckAlive(c, w)
+	workertest.CleanKill(c, w)
+	s.stub.CheckCallNames(c, "Check", "Check")
+}
+
+func (s *WorkerSuite) TestNotice(c *gc.C) {
+	s.checker.notices = []*interruptionwatcher.Notice{nil, {
+		Action: "terminate",
+		Time:   time.Date(2018, 3, 1, 12, 30, 0, 0, time.UTC),
+	}}
+	w := s.startWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	waitAlarms(c, s.clock, 2)
+	s.clock.Advance(5 * time.Second)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+
+	s.stub.CheckCallNames(c, "Check", "Check", "SetStatus")
+	s.stub.CheckCall(c, 2, "SetStatus",
+		status.Error,
+		"instance interrupted by cloud: terminate at 2018-03-01T12:30:00Z",
+		map[string]interface{}{
+			"interrupted": true,
+			"action":      "terminate",
+			"time":        "2018-03-01T12:30:00Z",
+		},
+	)
+}
+
+func (s *WorkerSuite) TestSetStatusError(c *gc.C) {
+	s.checker.notices = []*interruptionwatcher.Notice{{Action: "stop"}}
+	s.stub.SetErrors(nil, errors.New("boom"))
+	w := s.startWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "setting machine status: boom")
+	s.stub.CheckCall(c, 1, "SetStatus",
+		status.Error,
+		"instance interrupted by cloud: stop",
+		map[string]interface{}{
+			"interrupted": true,
+			"action":      "stop",
+		},
+	)
+}
+
+type mockFacade struct {
+	stub *testing.Stub
+}
+
+func (f *mockFacade) SetStatus(status status.Status, info string, data map[string]interface{}) error {
+	f.stub.AddCall("SetStatus", status, info, data)
+	return f.stub.NextErr()
+}
+
+type mockChecker struct {
+	stub    *testing.Stub
+	notices []*interruptionwatcher.Notice
+}
+
+func (m *mockChecker) Check() (*interruptionwatcher.Notice, error) {
+	m.stub.AddCall("Check")
+	if err := m.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	if len(m.notices) == 0 {
+		return nil, nil
+	}
+	notice := m.notices[0]
+	m.notices = m.notices[1:]
+	return notice, nil
+}
+
+func waitAlarms(c *gc.C, clock *testing.Clock, count int) {
+	timeout := time.After(coretesting.LongWait)
+	for i := 0; i < count; i++ {
+		select {
+		case <-clock.Alarms():
+		case <-timeout:
+			c.Fatalf("timed out waiting for alarm %d", i)
+		}
+	}
+}
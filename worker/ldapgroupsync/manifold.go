@@ -0,0 +1,116 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ldapgroupsync
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/dependency"
+	workerstate "github.com/juju/juju/worker/state"
+)
+
+// ManifoldConfig holds the information necessary to run an LDAP group
+// sync worker in a dependency.Engine.
+type ManifoldConfig struct {
+	ClockName string
+	StateName string
+
+	Interval  time.Duration
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+func (config ManifoldConfig) Validate() error {
+	if config.ClockName == "" {
+		return errors.NotValidf("empty ClockName")
+	}
+	if config.StateName == "" {
+		return errors.NotValidf("empty StateName")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// Manifold returns a dependency.Manifold that will run an LDAP group
+// sync worker.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.ClockName,
+			config.StateName,
+		},
+		Start: config.start,
+	}
+}
+
+// start is a method on ManifoldConfig because it's more readable than a closure.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var clock clock.Clock
+	if err := context.Get(config.ClockName, &clock); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var stTracker workerstate.StateTracker
+	if err := context.Get(config.StateName, &stTracker); err != nil {
+		return nil, errors.Trace(err)
+	}
+	statePool, err := stTracker.Use()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	worker, err := config.NewWorker(Config{
+		State:        stateShim{statePool.SystemState()},
+		Clock:        clock,
+		Interval:     config.Interval,
+		NewDirectory: NewDirectory,
+	})
+	if err != nil {
+		stTracker.Done()
+		return nil, errors.Trace(err)
+	}
+
+	go func() {
+		worker.Wait()
+		stTracker.Done()
+	}()
+	return worker, nil
+}
+
+type stateShim struct {
+	*state.State
+}
+
+// UserGroupMembers is part of the State interface.
+func (s stateShim) UserGroupMembers() ([]names.UserTag, error) {
+	groups, err := s.AllUserGroups()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seen := make(map[names.UserTag]bool)
+	var users []names.UserTag
+	for _, group := range groups {
+		for _, user := range group.Members() {
+			if !seen[user] {
+				seen[user] = true
+				users = append(users, user)
+			}
+		}
+	}
+	return users, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package ldapgroupsync provides a worker that periodically syncs the
+// user group membership of LDAP users with their groups in the
+// directory, so that users removed from a directory group lose the
+// access granted to it without having to log in again.
+package ldapgroupsync
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/authentication/ldap"
+	"github.com/juju/juju/controller"
+	jworker "github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.ldapgroupsync")
+
+// State provides the state methods used by the worker.
+type State interface {
+	// ControllerConfig returns the controller configuration.
+	ControllerConfig() (controller.Config, error)
+
+	// UserGroupMembers returns the users that are a member of any
+	// user group.
+	UserGroupMembers() ([]names.UserTag, error)
+
+	// SyncUserGroupMembership makes the user a member of exactly
+	// the user groups mapped to the named directory groups.
+	SyncUserGroupMembership(user names.UserTag, groups []string) error
+}
+
+// Directory looks up the groups of users in an LDAP directory.
+type Directory interface {
+	// UserGroups returns the names of the groups the named user
+	// is a member of.
+	UserGroups(username string) ([]string, error)
+}
+
+// NewDirectory returns the directory described by the given config.
+func NewDirectory(config ldap.Config) (Directory, error) {
+	directory, err := ldap.NewDirectory(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return directory, nil
+}
+
+// Config holds the configuration and dependencies of the worker.
+type Config struct {
+	State        State
+	Clock        clock.Clock
+	Interval     time.Duration
+	NewDirectory func(ldap.Config) (Directory, error)
+}
+
+// Validate returns an error if the config is not valid.
+func (config Config) Validate() error {
+	if config.State == nil {
+		return errors.NotValidf("nil State")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.NewDirectory == nil {
+		return errors.NotValidf("nil NewDirectory")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that syncs the groups of LDAP users every
+// Interval. It does nothing unless both an LDAP directory and a group
+// base are configured.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &syncWorker{config: config}
+	return jworker.NewSimpleWorker(w.loop), nil
+}
+
+type syncWorker struct {
+	config Config
+}
+
+func (w *syncWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-w.config.Clock.After(w.config.Interval):
+			if err := w.sync(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func (w *syncWorker) sync() error {
+	controllerConfig, err := w.config.State.ControllerConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot load controller configuration")
+	}
+	if controllerConfig.LDAPURL() == "" || controllerConfig.LDAPGroupBaseDN() == "" {
+		return nil
+	}
+	directory, err := w.config.NewDirectory(ldap.NewConfig(controllerConfig))
+	if err != nil {
+		return errors.Annotate(err, "cannot create LDAP directory")
+	}
+	users, err := w.config.State.UserGroupMembers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, user := range users {
+		if user.Domain() != authentication.LDAPUserDomain {
+			continue
+		}
+		groups, err := directory.UserGroups(user.Name())
+		if err != nil {
+			// The directory may be unavailable for a while;
+			// rather than restarting, try again next time.
+			logger.Warningf("cannot get LDAP groups of %q: %v", user.Id(), err)
+			return nil
+		}
+		if err := w.config.State.SyncUserGroupMembership(user, groups); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ldapgroupsync_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/authentication/ldap"
+	"github.com/juju/juju/controller"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/ldapgroupsync"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+	clock     *testing.Clock
+	state     *fakeState
+	directory *fakeDirectory
+	synced    chan struct{}
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Time{})
+	s.synced = make(chan struct{}, 10)
+	s.state = &fakeState{
+		config: controller.Config{
+			controller.LDAPURL:            "ldap://ldap.example.com",
+			controller.LDAPUserDNTemplate: "uid=%s,ou=people,dc=example,dc=com",
+			controller.LDAPGroupBaseDN:    "ou=groups,dc=example,dc=com",
+		},
+		members: []names.UserTag{
+			names.NewUserTag("bob@ldap"),
+			names.NewUserTag("mary"),
+			names.NewUserTag("jim@ldap"),
+		},
+		synced: s.synced,
+	}
+	s.directory = &fakeDirectory{
+		groups: map[string][]string{"bob": {"dev", "ops"}},
+	}
+}
+
+func (s *WorkerSuite) config() ldapgroupsync.Config {
+	return ldapgroupsync.Config{
+		State:    s.state,
+		Clock:    s.clock,
+		Interval: time.Minute,
+		NewDirectory: func(config ldap.Config) (ldapgroupsync.Directory, error) {
+			s.directory.config = config
+			return s.directory, nil
+		},
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	config := s.config()
+	config.Interval = 0
+	_, err := ldapgroupsync.NewWorker(config)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, "non-positive Interval not valid")
+}
+
+func (s *WorkerSuite) TestSync(c *gc.C) {
+	w, err := ldapgroupsync.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.waitSynced(c, 2)
+
+	c.Assert(s.directory.config.GroupBaseDN, gc.Equals, "ou=groups,dc=example,dc=com")
+	c.Assert(s.state.memberships, jc.DeepEquals, map[string][]string{
+		"bob@ldap": {"dev", "ops"},
+		"jim@ldap": nil,
+	})
+}
+
+func (s *WorkerSuite) TestSyncWithoutGroupBase(c *gc.C) {
+	delete(s.state.config, controller.LDAPGroupBaseDN)
+	w, err := ldapgroupsync.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	// Wait for the worker to go round the loop again.
+	c.Assert(s.clock.WaitAdvance(0, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(s.state.memberships, gc.HasLen, 0)
+}
+
+func (s *WorkerSuite) TestDirectoryErrorRetries(c *gc.C) {
+	s.directory.err = errors.New("directory down")
+	w, err := ldapgroupsync.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(s.clock.WaitAdvance(0, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(s.state.memberships, gc.HasLen, 0)
+
+	s.directory.err = nil
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.waitSynced(c, 2)
+}
+
+func (s *WorkerSuite) waitSynced(c *gc.C, n int) {
+	for i := 0; i < n; i++ {
+		select {
+		case <-s.synced:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for sync")
+		}
+	}
+}
+
+type fakeState struct {
+	config      controller.Config
+	members     []names.UserTag
+	memberships map[string][]string
+	synced      chan<- struct{}
+}
+
+func (s *fakeState) ControllerConfig() (controller.Config, error) {
+	return s.config, nil
+}
+
+func (s *fakeState) UserGroupMembers() ([]names.UserTag, error) {
+	return s.members, nil
+}
+
+func (s *fakeState) SyncUserGroupMembership(user names.UserTag, groups []string) error {
+	if s.memberships == nil {
+		s.memberships = make(map[string][]string)
+	}
+	s.memberships[user.Id()] = groups
+	s.synced <- struct{}{}
+	return nil
+}
+
+type fakeDirectory struct {
+	config ldap.Config
+	groups map[string][]string
+	err    error
+}
+
+func (d *fakeDirectory) UserGroups(username string) ([]string, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.groups[username], nil
+}
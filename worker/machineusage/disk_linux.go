@@ -0,0 +1,23 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineusage
+
+import (
+	"syscall"
+
+	"github.com/juju/errors"
+)
+
+// diskUsage returns the used and total space, in MiB, of the
+// filesystem containing path.
+func diskUsage(path string) (used, total uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, errors.Annotatef(err, "getting disk usage for %q", path)
+	}
+	blockSize := uint64(stat.Bsize)
+	total = stat.Blocks * blockSize
+	used = (stat.Blocks - stat.Bfree) * blockSize
+	return used / (1024 * 1024), total / (1024 * 1024), nil
+}
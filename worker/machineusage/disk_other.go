@@ -0,0 +1,16 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !linux
+
+package machineusage
+
+import (
+	"runtime"
+
+	"github.com/juju/errors"
+)
+
+func diskUsage(path string) (used, total uint64, err error) {
+	return 0, 0, errors.NotSupportedf("disk usage on %s", runtime.GOOS)
+}
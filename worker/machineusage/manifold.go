@@ -0,0 +1,77 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineusage
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a
+// Manifold will depend, and the worker's configuration.
+type ManifoldConfig struct {
+	AgentName            string
+	APICallerName        string
+	Clock                clock.Clock
+	Interval             time.Duration
+	PrometheusRegisterer prometheus.Registerer
+
+	NewFacade  func(base.APICaller) (Facade, error)
+	NewSampler func(clock.Clock) (Sampler, error)
+	NewWorker  func(Config) (worker.Worker, error)
+}
+
+// start is an engine.AgentAPIStartFunc that draws context from the
+// ManifoldConfig on which it is defined.
+func (config ManifoldConfig) start(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	tag, ok := a.CurrentConfig().Tag().(names.MachineTag)
+	if !ok {
+		return nil, errors.New("machineusage may only be used with a machine agent")
+	}
+
+	sampler, err := config.NewSampler(config.Clock)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not sampling machine usage: %v", err)
+		return nil, dependency.ErrUninstall
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	facade, err := config.NewFacade(apiCaller)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	worker, err := config.NewWorker(Config{
+		Facade:               facade,
+		Sampler:              sampler,
+		Clock:                config.Clock,
+		Interval:             config.Interval,
+		MachineId:            tag.Id(),
+		PrometheusRegisterer: config.PrometheusRegisterer,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// Manifold returns a dependency manifold that runs a machine usage
+// worker, using the resources named or defined in the supplied config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	typedConfig := engine.AgentAPIManifoldConfig{
+		AgentName:     config.AgentName,
+		APICallerName: config.APICallerName,
+	}
+	return engine.AgentAPIManifold(typedConfig, config.start)
+}
@@ -0,0 +1,187 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineusage
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/api/machineusage"
+)
+
+// Sampler takes samples of the resources used by a machine.
+type Sampler interface {
+	// Sample returns the resources used by the machine. The CPU
+	// usage is measured since the previous sample was taken.
+	Sample() (machineusage.Usage, error)
+}
+
+// cpuTimes holds the cumulative time the CPUs have spent busy and in
+// total, in clock ticks, as reported by /proc/stat.
+type cpuTimes struct {
+	busy  uint64
+	total uint64
+}
+
+// procSampler is a Sampler that reads CPU and memory usage from the
+// Linux proc filesystem, and disk usage from the filesystem mounted
+// at diskPath.
+type procSampler struct {
+	procDir  string
+	diskPath string
+	clock    clock.Clock
+	previous cpuTimes
+}
+
+// NewProcSampler returns a Sampler that reads CPU and memory usage
+// from the proc filesystem mounted at procDir, and disk usage from
+// the filesystem containing diskPath.
+func NewProcSampler(procDir, diskPath string, clock clock.Clock) (Sampler, error) {
+	times, _, err := readCPUTimes(filepath.Join(procDir, "stat"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &procSampler{
+		procDir:  procDir,
+		diskPath: diskPath,
+		clock:    clock,
+		previous: times,
+	}, nil
+}
+
+// Sample is part of the Sampler interface.
+func (s *procSampler) Sample() (machineusage.Usage, error) {
+	times, cores, err := readCPUTimes(filepath.Join(s.procDir, "stat"))
+	if err != nil {
+		return machineusage.Usage{}, errors.Trace(err)
+	}
+	var cpu float64
+	if total := times.total - s.previous.total; total > 0 {
+		cpu = 100 * float64(times.busy-s.previous.busy) / float64(total)
+	}
+	s.previous = times
+
+	memUsed, memTotal, err := readMemory(filepath.Join(s.procDir, "meminfo"))
+	if err != nil {
+		return machineusage.Usage{}, errors.Trace(err)
+	}
+	diskUsed, diskTotal, err := diskUsage(s.diskPath)
+	if err != nil {
+		return machineusage.Usage{}, errors.Trace(err)
+	}
+	return machineusage.Usage{
+		Time:        s.clock.Now(),
+		CPU:         cpu,
+		Cores:       cores,
+		MemoryUsed:  memUsed,
+		MemoryTotal: memTotal,
+		DiskUsed:    diskUsed,
+		DiskTotal:   diskTotal,
+	}, nil
+}
+
+// readCPUTimes reads the aggregate CPU times, and the number of CPU
+// cores, from the given /proc/stat file.
+func readCPUTimes(path string) (cpuTimes, uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return cpuTimes{}, 0, errors.Trace(err)
+	}
+	defer f.Close()
+
+	var times cpuTimes
+	var found bool
+	var cores uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if fields[0] != "cpu" {
+			cores++
+			continue
+		}
+		// The fields are user, nice, system, idle, iowait, irq,
+		// softirq and steal; guest time is included in user time.
+		if len(fields) < 5 {
+			return cpuTimes{}, 0, errors.Errorf("malformed cpu line %q in %s", scanner.Text(), path)
+		}
+		for i, field := range fields[1:] {
+			if i >= 8 {
+				break
+			}
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return cpuTimes{}, 0, errors.Annotatef(err, "parsing cpu line in %s", path)
+			}
+			times.total += value
+			if i != 3 && i != 4 {
+				// Neither idle nor iowait.
+				times.busy += value
+			}
+		}
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return cpuTimes{}, 0, errors.Trace(err)
+	}
+	if !found {
+		return cpuTimes{}, 0, errors.NotFoundf("cpu line in %s", path)
+	}
+	return times, cores, nil
+}
+
+// readMemory reads the used and total memory, in MiB, from the given
+// /proc/meminfo file. Memory that is available for reuse, such as the
+// page cache, is not considered used.
+func readMemory(path string) (used, total uint64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		key := strings.TrimSuffix(fields[0], ":")
+		switch key {
+		case "MemTotal", "MemAvailable", "MemFree", "Buffers", "Cached":
+		default:
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, errors.Annotatef(err, "parsing %s in %s", key, path)
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	totalKiB, ok := values["MemTotal"]
+	if !ok {
+		return 0, 0, errors.NotFoundf("MemTotal in %s", path)
+	}
+	availableKiB, ok := values["MemAvailable"]
+	if !ok {
+		// Kernels older than 3.14 do not report MemAvailable.
+		availableKiB = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	if availableKiB > totalKiB {
+		availableKiB = totalKiB
+	}
+	return (totalKiB - availableKiB) / 1024, totalKiB / 1024, nil
+}
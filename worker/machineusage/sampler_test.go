@@ -0,0 +1,104 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package machineusage_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/machineusage"
+)
+
+type SamplerSuite struct {
+	testing.IsolationSuite
+	procDir string
+	clock   *testing.Clock
+}
+
+var _ = gc.Suite(&SamplerSuite{})
+
+const meminfo = `
+MemTotal:        8388608 kB
+MemFree:          524288 kB
+MemAvailable:    6291456 kB
+Buffers:          262144 kB
+Cached:          4194304 kB
+`
+
+func (s *SamplerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.procDir = c.MkDir()
+	s.clock = testing.NewClock(time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC))
+	s.writeFile(c, "meminfo", meminfo)
+}
+
+func (s *SamplerSuite) writeFile(c *gc.C, name, content string) {
+	err := ioutil.WriteFile(filepath.Join(s.procDir, name), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SamplerSuite) writeStat(c *gc.C, cpuLine string) {
+	s.writeFile(c, "stat", cpuLine+`
+cpu0 1 0 1 1 0 0 0 0 0 0
+cpu1 1 0 1 1 0 0 0 0 0 0
+intr 12345
+ctxt 67890
+`)
+}
+
+func (s *SamplerSuite) TestSample(c *gc.C) {
+	s.writeStat(c, "cpu  100 0 100 700 100 0 0 0 0 0")
+	sampler, err := machineusage.NewProcSampler(s.procDir, c.MkDir(), s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// 300 busy ticks out of 400.
+	s.writeStat(c, "cpu  300 0 200 750 150 0 0 0 0 0")
+	usage, err := sampler.Sample()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage.Time, gc.Equals, s.clock.Now())
+	c.Assert(usage.CPU, gc.Equals, 75.0)
+	c.Assert(usage.Cores, gc.Equals, uint64(2))
+	c.Assert(usage.MemoryTotal, gc.Equals, uint64(8192))
+	c.Assert(usage.MemoryUsed, gc.Equals, uint64(2048))
+	c.Assert(usage.DiskTotal > 0, jc.IsTrue)
+
+	// Nothing has changed since the previous sample.
+	usage, err = sampler.Sample()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage.CPU, gc.Equals, 0.0)
+}
+
+func (s *SamplerSuite) TestSampleWithoutMemAvailable(c *gc.C) {
+	s.writeStat(c, "cpu  100 0 100 700 100 0 0 0 0 0")
+	s.writeFile(c, "meminfo", `
+MemTotal:        8388608 kB
+MemFree:          524288 kB
+Buffers:          262144 kB
+Cached:          4194304 kB
+`)
+	sampler, err := machineusage.NewProcSampler(s.procDir, c.MkDir(), s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := sampler.Sample()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage.MemoryUsed, gc.Equals, uint64(3328))
+}
+
+func (s *SamplerSuite) TestMissingStat(c *gc.C) {
+	_, err := machineusage.NewProcSampler(s.procDir, c.MkDir(), s.clock)
+	c.Assert(err, gc.ErrorMatches, "open .*/stat: no such file or directory")
+}
+
+func (s *SamplerSuite) TestMalformedStat(c *gc.C) {
+	s.writeStat(c, "cpu  100 0 bad 700 100 0 0 0 0 0")
+	_, err := machineusage.NewProcSampler(s.procDir, c.MkDir(), s.clock)
+	c.Assert(err, gc.ErrorMatches, `parsing cpu line in .*/stat: .*invalid syntax`)
+}
@@ -0,0 +1,28 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineusage
+
+import (
+	"runtime"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/machineusage"
+)
+
+// NewFacade returns a Facade for recording machine usage.
+func NewFacade(apiCaller base.APICaller) (Facade, error) {
+	return machineusage.NewFacade(apiCaller), nil
+}
+
+// NewSampler returns a Sampler for the machine's root filesystem,
+// using the host's proc filesystem.
+func NewSampler(clock clock.Clock) (Sampler, error) {
+	if runtime.GOOS != "linux" {
+		return nil, errors.NotSupportedf("machine usage sampling on %s", runtime.GOOS)
+	}
+	return NewProcSampler("/proc", "/", clock)
+}
@@ -0,0 +1,201 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machineusage provides a worker that periodically samples the
+// CPU, memory and disk usage of the machine, exposes the latest sample
+// through the agent's introspection metrics, and reports each sample
+// to the controller so that instance types can be recommended for the
+// applications deployed to the machine.
+package machineusage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/machineusage"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.machineusage")
+
+const bytesPerMiB = 1024 * 1024
+
+var (
+	cpuPercentDesc = prometheus.NewDesc(
+		"juju_machine_cpu_percent",
+		"Percentage of the machine's total CPU capacity in use.",
+		nil, nil,
+	)
+	cpuCoresDesc = prometheus.NewDesc(
+		"juju_machine_cpu_cores",
+		"Number of CPU cores available to the machine.",
+		nil, nil,
+	)
+	memoryUsedDesc = prometheus.NewDesc(
+		"juju_machine_memory_used_bytes",
+		"Memory in use on the machine, excluding reclaimable caches.",
+		nil, nil,
+	)
+	memoryTotalDesc = prometheus.NewDesc(
+		"juju_machine_memory_total_bytes",
+		"Memory available to the machine.",
+		nil, nil,
+	)
+	diskUsedDesc = prometheus.NewDesc(
+		"juju_machine_root_disk_used_bytes",
+		"Space in use on the machine's root disk.",
+		nil, nil,
+	)
+	diskTotalDesc = prometheus.NewDesc(
+		"juju_machine_root_disk_total_bytes",
+		"Size of the machine's root disk.",
+		nil, nil,
+	)
+)
+
+// Facade exposes the controller functionality required by the worker.
+type Facade interface {
+	// RecordUsage records a sample of the resources used by
+	// the machine with the given id.
+	RecordUsage(machineId string, usage machineusage.Usage) error
+}
+
+// Config holds the dependencies and configuration necessary to
+// drive a machine usage worker.
+type Config struct {
+	Facade    Facade
+	Sampler   Sampler
+	Clock     clock.Clock
+	Interval  time.Duration
+	MachineId string
+
+	// PrometheusRegisterer, if non-nil, is used to register the
+	// worker's metrics, so they are exposed by the agent's
+	// introspection endpoint.
+	PrometheusRegisterer prometheus.Registerer
+}
+
+// Validate returns an error if config cannot be expected to drive
+// a machine usage worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Sampler == nil {
+		return errors.NotValidf("nil Sampler")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.MachineId == "" {
+		return errors.NotValidf("empty MachineId")
+	}
+	return nil
+}
+
+// New returns a worker that samples the machine's resource usage at
+// the configured interval and reports each sample to the controller.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &usageWorker{config: config}
+	if config.PrometheusRegisterer != nil {
+		if err := config.PrometheusRegisterer.Register(w); err != nil {
+			return nil, errors.Annotate(err, "registering metrics")
+		}
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		if config.PrometheusRegisterer != nil {
+			config.PrometheusRegisterer.Unregister(w)
+		}
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type usageWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	mu     sync.Mutex
+	latest *machineusage.Usage
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *usageWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *usageWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *usageWorker) loop() error {
+	if w.config.PrometheusRegisterer != nil {
+		defer w.config.PrometheusRegisterer.Unregister(w)
+	}
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(w.config.Interval):
+		}
+
+		usage, err := w.config.Sampler.Sample()
+		if err != nil {
+			logger.Warningf("cannot sample machine usage: %v", err)
+			continue
+		}
+		w.mu.Lock()
+		w.latest = &usage
+		w.mu.Unlock()
+
+		if err := w.config.Facade.RecordUsage(w.config.MachineId, usage); err != nil {
+			return errors.Annotate(err, "recording machine usage")
+		}
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (w *usageWorker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cpuPercentDesc
+	ch <- cpuCoresDesc
+	ch <- memoryUsedDesc
+	ch <- memoryTotalDesc
+	ch <- diskUsedDesc
+	ch <- diskTotalDesc
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (w *usageWorker) Collect(ch chan<- prometheus.Metric) {
+	w.mu.Lock()
+	latest := w.latest
+	w.mu.Unlock()
+	if latest == nil {
+		return
+	}
+	gauge := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value)
+	}
+	gauge(cpuPercentDesc, latest.CPU)
+	gauge(cpuCoresDesc, float64(latest.Cores))
+	gauge(memoryUsedDesc, float64(latest.MemoryUsed*bytesPerMiB))
+	gauge(memoryTotalDesc, float64(latest.MemoryTotal*bytesPerMiB))
+	gauge(diskUsedDesc, float64(latest.DiskUsed*bytesPerMiB))
+	gauge(diskTotalDesc, float64(latest.DiskTotal*bytesPerMiB))
+}
@@ -0,0 +1,200 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineusage_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/machineusage"
+	coretesting "github.com/juju/juju/testing"
+	workermachineusage "github.com/juju/juju/worker/machineusage"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+	stub     testing.Stub
+	clock    *testing.Clock
+	facade   *mockFacade
+	sampler  *mockSampler
+	registry *prometheus.Registry
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+var sample = machineusage.Usage{
+	Time:        time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC),
+	CPU:         25,
+	Cores:       2,
+	MemoryUsed:  512,
+	MemoryTotal: 2048,
+	DiskUsed:    1024,
+	DiskTotal:   8192,
+}
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.clock = testing.NewClock(time.Now())
+	s.facade = &mockFacade{stub: &s.stub}
+	s.sampler = &mockSampler{stub: &s.stub}
+	s.registry = prometheus.NewRegistry()
+}
+
+func (s *WorkerSuite) config() workermachineusage.Config {
+	return workermachineusage.Config{
+		Facade:               s.facade,
+		Sampler:              s.sampler,
+		Clock:                s.clock,
+		Interval:             5 * time.Minute,
+		MachineId:            "42",
+		PrometheusRegisterer: s.registry,
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := workermachineusage.New(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		mutate func(*workermachineusage.Config)
+		err    string
+	}{{
+		func(config *workermachineusage.Config) { config.Facade = nil },
+		"nil Facade not valid",
+	}, {
+		func(config *workermachineusage.Config) { config.Sampler = nil },
+		"nil Sampler not valid",
+	}, {
+		func(config *workermachineusage.Config) { config.Clock = nil },
+		"nil Clock not valid",
+	}, {
+		func(config *workermachineusage.Config) { config.Interval = 0 },
+		"non-positive Interval not valid",
+	}, {
+		func(config *workermachineusage.Config) { config.MachineId = "" },
+		"empty MachineId not valid",
+	}} {
+		c.Logf("test #%d: %s", i, test.err)
+		config := s.config()
+		test.mutate(&config)
+		_, err := workermachineusage.New(config)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *WorkerSuite) TestRecordsSamples(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	waitAlarms(c, s.clock, 1)
+	s.clock.Advance(5 * time.Minute)
+	waitAlarms(c, s.clock, 1)
+	s.clock.Advance(5 * time.Minute)
+	waitAlarms(c, s.clock, 1)
+	workertest.CleanKill(c, w)
+
+	s.stub.CheckCallNames(c, "Sample", "RecordUsage", "Sample", "RecordUsage")
+	s.stub.CheckCall(c, 1, "RecordUsage", "42", sample)
+}
+
+func (s *WorkerSuite) TestSampleErrorRetries(c *gc.C) {
+	s.stub.SetErrors(errors.New("no proc"))
+	w := s.startWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	waitAlarms(c, s.clock, 1)
+	s.clock.Advance(5 * time.Minute)
+	waitAlarms(c, s.clock, 1)
+	workertest.CheckAlive(c, w)
+	workertest.CleanKill(c, w)
+	s.stub.CheckCallNames(c, "Sample")
+}
+
+func (s *WorkerSuite) TestRecordUsageError(c *gc.C) {
+	s.stub.SetErrors(nil, errors.New("boom"))
+	w := s.startWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	waitAlarms(c, s.clock, 1)
+	s.clock.Advance(5 * time.Minute)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "recording machine usage: boom")
+}
+
+func (s *WorkerSuite) TestMetrics(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.DirtyKill(c, w)
+
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(families, gc.HasLen, 0)
+
+	waitAlarms(c, s.clock, 1)
+	s.clock.Advance(5 * time.Minute)
+	waitAlarms(c, s.clock, 1)
+
+	families, err = s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	values := make(map[string]float64)
+	for _, family := range families {
+		values[family.GetName()] = family.GetMetric()[0].GetGauge().GetValue()
+	}
+	c.Assert(values, jc.DeepEquals, map[string]float64{
+		"juju_machine_cpu_percent":           25,
+		"juju_machine_cpu_cores":             2,
+		"juju_machine_memory_used_bytes":     512 * 1024 * 1024,
+		"juju_machine_memory_total_bytes":    2048 * 1024 * 1024,
+		"juju_machine_root_disk_used_bytes":  1024 * 1024 * 1024,
+		"juju_machine_root_disk_total_bytes": 8192 * 1024 * 1024,
+	})
+
+	// The metrics are unregistered when the worker stops.
+	workertest.CleanKill(c, w)
+	families, err = s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(families, gc.HasLen, 0)
+}
+
+type mockFacade struct {
+	stub *testing.Stub
+}
+
+func (f *mockFacade) RecordUsage(machineId string, usage machineusage.Usage) error {
+	f.stub.AddCall("RecordUsage", machineId, usage)
+	return f.stub.NextErr()
+}
+
+type mockSampler struct {
+	stub *testing.Stub
+}
+
+func (s *mockSampler) Sample() (machineusage.Usage, error) {
+	s.stub.AddCall("Sample")
+	if err := s.stub.NextErr(); err != nil {
+		return machineusage.Usage{}, err
+	}
+	return sample, nil
+}
+
+func waitAlarms(c *gc.C, clock *testing.Clock, count int) {
+	timeout := time.After(coretesting.LongWait)
+	for i := 0; i < count; i++ {
+		select {
+		case <-clock.Alarms():
+		case <-timeout:
+			c.Fatalf("timed out waiting for alarm %d", i)
+		}
+	}
+}
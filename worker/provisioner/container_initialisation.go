@@ -218,12 +218,17 @@ func observeNetwork() ([]params.NetworkConfig, error) {
 	return getObservedNetworkConfig(common.DefaultNetworkConfigSource())
 }
 
-func defaultBridger() (network.Bridger, error) {
+func (cs *ContainerSetup) defaultBridger() (network.Bridger, error) {
 	if _, err := os.Stat(systemSbinIfup); err == nil {
 		return network.DefaultEtcNetworkInterfacesBridger(activateBridgesTimeout, systemNetworkInterfacesFile)
-	} else {
-		return network.DefaultNetplanBridger(activateBridgesTimeout, systemNetplanDirectory)
 	}
+	// Netplan bridging is rolled back if the controller becomes
+	// unreachable once the new configuration has been applied.
+	apiAddresses, err := cs.provisioner.APIAddresses()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting controller addresses")
+	}
+	return network.DefaultNetplanBridger(activateBridgesTimeout, systemNetplanDirectory, apiAddresses...)
 }
 
 func (cs *ContainerSetup) prepareHost(containerTag names.MachineTag, log loggo.Logger) error {
@@ -232,7 +237,7 @@ func (cs *ContainerSetup) prepareHost(containerTag names.MachineTag, log loggo.L
 		ObserveNetworkFunc: observeNetwork,
 		LockName:           cs.initLockName,
 		AcquireLockFunc:    cs.acquireLock,
-		CreateBridger:      defaultBridger,
+		CreateBridger:      cs.defaultBridger,
 		// TODO(jam): 2017-02-08 figure out how to thread catacomb.Dying() into
 		// this function, so that we can stop trying to acquire the lock if we
 		// are stopping.
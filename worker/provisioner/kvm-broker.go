@@ -137,6 +137,10 @@ func (broker *kvmBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		kvmLogger.Errorf("failed to populate machine config: %v", err)
 		return nil, err
 	}
+	// Containers reach the controller the same way as their host.
+	if controllerProxy := broker.agentConfig.Value(agent.ControllerProxy); controllerProxy != "" {
+		args.InstanceConfig.AgentEnvironment[agent.ControllerProxy] = controllerProxy
+	}
 
 	storageConfig := &container.StorageConfig{
 		AllowMount: true,
@@ -127,6 +127,10 @@ func (broker *lxdBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		lxdLogger.Errorf("failed to populate machine config: %v", err)
 		return nil, err
 	}
+	// Containers reach the controller the same way as their host.
+	if controllerProxy := broker.agentConfig.Value(agent.ControllerProxy); controllerProxy != "" {
+		args.InstanceConfig.AgentEnvironment[agent.ControllerProxy] = controllerProxy
+	}
 
 	storageConfig := &container.StorageConfig{}
 	inst, hardware, err := broker.manager.CreateContainer(
@@ -4,6 +4,7 @@
 package provisioner
 
 import (
+	"reflect"
 	"sync"
 	"time"
 
@@ -220,6 +221,7 @@ func (p *environProvisioner) loop() error {
 
 	modelConfig := p.environ.Config()
 	p.configObserver.notify(modelConfig)
+	resourceTags, _ := modelConfig.ResourceTags()
 	harvestMode := modelConfig.ProvisionerHarvestMode()
 	task, err := p.getStartTask(harvestMode)
 	if err != nil {
@@ -245,6 +247,21 @@ func (p *environProvisioner) loop() error {
 				return errors.Annotate(err, "loaded invalid model configuration")
 			}
 			task.SetHarvestMode(modelConfig.ProvisionerHarvestMode())
+
+			// Instances are tagged with the model's resource
+			// tags when they are started, so they need to be
+			// retagged when the resource tags change.
+			newResourceTags, _ := modelConfig.ResourceTags()
+			if !reflect.DeepEqual(newResourceTags, resourceTags) {
+				var removed []string
+				for name := range resourceTags {
+					if _, ok := newResourceTags[name]; !ok {
+						removed = append(removed, name)
+					}
+				}
+				resourceTags = newResourceTags
+				task.RetagInstances(removed)
+			}
 		}
 	}
 }
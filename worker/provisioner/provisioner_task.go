@@ -48,6 +48,13 @@ type ProvisionerTask interface {
 	// should harvest machines. See config.HarvestMode for
 	// documentation of behavior.
 	SetHarvestMode(mode config.HarvestMode)
+
+	// RetagInstances causes the provisioner task to re-apply the
+	// tags of the model's resources and of the instances of all
+	// provisioned machines, after the model's resource tags have
+	// changed. The tags with the given names, which have been removed
+	// from the model's resource tags, are removed from the resources.
+	RetagInstances(removed []string)
 }
 
 type MachineGetter interface {
@@ -106,6 +113,20 @@ func NewProvisionerTask(
 		imageStream:                imageStream,
 		retryStartInstanceStrategy: retryStartInstanceStrategy,
 	}
+	if canRetag(broker) {
+		retagger, err := newInstanceRetagger(
+			controllerUUID, broker, task.getMachine, task.getMachines,
+		)
+		if err != nil {
+			worker.Stop(machineWatcher)
+			if retryWatcher != nil {
+				worker.Stop(retryWatcher)
+			}
+			return nil, errors.Trace(err)
+		}
+		task.retagger = retagger
+		workers = append(workers, retagger)
+	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &task.catacomb,
 		Work: task.loop,
@@ -138,6 +159,9 @@ type provisionerTask struct {
 	harvestMode                config.HarvestMode
 	harvestModeChan            chan config.HarvestMode
 	retryStartInstanceStrategy RetryStrategy
+	// retagger retags instances when their tags change, if the
+	// broker supports it; it is nil otherwise.
+	retagger *instanceRetagger
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
@@ -210,6 +234,33 @@ func (task *provisionerTask) SetHarvestMode(mode config.HarvestMode) {
 	}
 }
 
+// RetagInstances implements ProvisionerTask.RetagInstances().
+func (task *provisionerTask) RetagInstances(removed []string) {
+	if task.retagger != nil {
+		task.retagger.RetagAll(removed)
+	}
+}
+
+// getMachine returns the machine with the given id, if the task
+// knows of it.
+func (task *provisionerTask) getMachine(id string) (*apiprovisioner.Machine, bool) {
+	task.machinesMutex.RLock()
+	defer task.machinesMutex.RUnlock()
+	machine, ok := task.machines[id]
+	return machine, ok
+}
+
+// getMachines returns all of the machines the task knows of.
+func (task *provisionerTask) getMachines() []*apiprovisioner.Machine {
+	task.machinesMutex.RLock()
+	defer task.machinesMutex.RUnlock()
+	machines := make([]*apiprovisioner.Machine, 0, len(task.machines))
+	for _, machine := range task.machines {
+		machines = append(machines, machine)
+	}
+	return machines
+}
+
 func (task *provisionerTask) processMachinesWithTransientErrors() error {
 	results, err := task.machineGetter.MachinesWithTransientErrors()
 	if err != nil {
@@ -247,6 +298,12 @@ func (task *provisionerTask) processMachines(ids []string) error {
 		return err
 	}
 
+	// Have the retagger watch the units assigned to the machines,
+	// so that their instances can be retagged when they change.
+	if task.retagger != nil {
+		task.retagger.MachinesChanged(ids)
+	}
+
 	// Find machines without an instance id or that are dead
 	pending, dead, maintain, err := task.pendingOrDeadOrMaintain(ids)
 	if err != nil {
@@ -587,6 +644,7 @@ func (task *provisionerTask) constructInstanceConfig(
 	}
 
 	instanceConfig.CloudInitUserData = pInfo.CloudInitUserData
+	instanceConfig.SSHUserCAPublicKey = pInfo.SSHUserCAPublicKey
 
 	return instanceConfig, nil
 }
@@ -995,8 +1053,8 @@ func (task *provisionerTask) setupToStartMachine(machine *apiprovisioner.Machine
 }
 
 // populateExcludedMachines, translates the results of DeriveAvailabilityZones
-// into availabilityZoneMachines.ExcludedMachineIds for machines not to be used
-// in the given zone.
+// and the zones constraint into availabilityZoneMachines.ExcludedMachineIds
+// for machines not to be used in the given zone.
 func (task *provisionerTask) populateExcludedMachines(machineId string, startInstanceParams environs.StartInstanceParams) error {
 	zonedEnv, ok := task.broker.(providercommon.ZonedEnviron)
 	if !ok {
@@ -1006,12 +1064,23 @@ func (task *provisionerTask) populateExcludedMachines(machineId string, startIns
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if len(derivedZones) == 0 {
+	var useZones set.Strings
+	if len(derivedZones) > 0 {
+		useZones = set.NewStrings(derivedZones...)
+	}
+	if cons := startInstanceParams.Constraints; cons.HasZones() {
+		consZones := set.NewStrings(*cons.Zones...)
+		if useZones == nil {
+			useZones = consZones
+		} else {
+			useZones = useZones.Intersection(consZones)
+		}
+	}
+	if useZones == nil {
 		return nil
 	}
 	task.machinesMutex.Lock()
 	defer task.machinesMutex.Unlock()
-	useZones := set.NewStrings(derivedZones...)
 	for _, zoneMachines := range task.availabilityZoneMachines {
 		if !useZones.Contains(zoneMachines.ZoneName) {
 			zoneMachines.ExcludedMachineIds.Add(machineId)
@@ -1034,8 +1103,8 @@ func (task *provisionerTask) startMachine(
 	}
 
 	// Figure out if the zones available to use for a new instance are
-	// restricted based on placement, and if so exclude those machines
-	// from being started in any other zone.
+	// restricted based on placement or constraints, and if so exclude
+	// those machines from being started in any other zone.
 	if err := task.populateExcludedMachines(machine.Id(), startInstanceParams); err != nil {
 		return err
 	}
@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/imagemetadata"
 	imagetesting "github.com/juju/juju/environs/imagemetadata/testing"
+	"github.com/juju/juju/environs/tags"
 	envtesting "github.com/juju/juju/environs/testing"
 	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/instance"
@@ -1490,6 +1491,150 @@ func (s *ProvisionerSuite) TestStopInstancesIgnoresMachinesWithKeep(c *gc.C) {
 	s.waitForRemovalMark(c, m1)
 }
 
+func (s *ProvisionerSuite) TestRetagInstances(c *gc.C) {
+	broker := &taggingBroker{
+		Environ: s.Environ,
+		tagged:  make(chan taggedInstance, 1),
+	}
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		broker,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	s.waitInstanceId(c, m0, i0.Id())
+
+	err = s.IAASModel.UpdateModelConfig(map[string]interface{}{
+		"resource-tags": "owner=machine-${machine}",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	task.RetagInstances(nil)
+
+	select {
+	case tagged := <-broker.tagged:
+		c.Assert(tagged.id, gc.Equals, i0.Id())
+		c.Assert(tagged.tags["owner"], gc.Equals, "machine-"+m0.Id())
+		c.Assert(tagged.tags["juju-model-uuid"], gc.Equals, s.IAASModel.UUID())
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to be retagged")
+	}
+}
+
+func (s *ProvisionerSuite) TestRetagInstancesRemovesTags(c *gc.C) {
+	broker := &retaggingBroker{
+		taggingBroker: taggingBroker{
+			Environ: s.Environ,
+			tagged:  make(chan taggedInstance, 1),
+		},
+		retagged: make(chan []string, 1),
+	}
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		broker,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	s.waitInstanceId(c, m0, i0.Id())
+
+	task.RetagInstances([]string{"owner"})
+
+	select {
+	case removed := <-broker.retagged:
+		c.Assert(removed, jc.DeepEquals, []string{"owner"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for resources to be retagged")
+	}
+	select {
+	case tagged := <-broker.tagged:
+		c.Assert(tagged.id, gc.Equals, i0.Id())
+		c.Assert(tagged.removed, jc.DeepEquals, []string{"owner"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to be retagged")
+	}
+}
+
+func (s *ProvisionerSuite) TestRetagInstanceWhenUnitsChange(c *gc.C) {
+	broker := &taggingBroker{
+		Environ: s.Environ,
+		tagged:  make(chan taggedInstance, 1),
+	}
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		broker,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	s.waitInstanceId(c, m0, i0.Id())
+
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case tagged := <-broker.tagged:
+		c.Assert(tagged.id, gc.Equals, i0.Id())
+		c.Assert(tagged.tags[tags.JujuUnitsDeployed], gc.Equals, unit.Name())
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to be retagged")
+	}
+}
+
+func (s *ProvisionerSuite) TestRetagInstancesDoesNotBlockProvisioning(c *gc.C) {
+	broker := &blockingTaggingBroker{
+		Environ: s.Environ,
+		tagging: make(chan instance.Id, 1),
+		unblock: make(chan struct{}),
+	}
+	task := s.newProvisionerTask(c,
+		config.HarvestDestroyed,
+		broker,
+		s.provisioner,
+		&mockDistributionGroupFinder{},
+		mockToolsFinder{},
+	)
+	defer workertest.CleanKill(c, task)
+	defer close(broker.unblock)
+
+	m0, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	i0 := s.checkStartInstance(c, m0)
+	s.waitInstanceId(c, m0, i0.Id())
+
+	task.RetagInstances(nil)
+	select {
+	case id := <-broker.tagging:
+		c.Assert(id, gc.Equals, i0.Id())
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for instance to be retagged")
+	}
+
+	// The instance is still being tagged, but new machines are
+	// provisioned regardless.
+	m1, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m1)
+}
+
 func (s *ProvisionerSuite) TestProvisionerRetriesTransientErrors(c *gc.C) {
 	s.PatchValue(&apiserverprovisioner.ErrorRetryWaitDelay, 5*time.Millisecond)
 	e := &mockBroker{
@@ -1939,6 +2084,30 @@ func (s *ProvisionerSuite) TestProvisioningMachinesDerivedAZ(c *gc.C) {
 	}
 }
 
+func (s *ProvisionerSuite) TestProvisioningMachinesZonesConstraint(c *gc.C) {
+	// Per provider dummy, the available availability zones are
+	// zone1, zone3 and zone4.
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, s.Environ, s.provisioner, &mockDistributionGroupFinder{}, mockToolsFinder{})
+	defer workertest.CleanKill(c, task)
+
+	cons := constraints.MustParse(s.defaultConstraints.String(), "zones=zone3,zone4")
+	var machines []*state.Machine
+	for i := 0; i < 4; i++ {
+		m, err := s.addMachineWithConstraints(cons)
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, m)
+	}
+	s.checkStartInstancesCustom(c, machines, "pork", cons, nil, nil, nil, nil, nil, true)
+
+	zoneCounts := make(map[string]int)
+	for _, m := range machines {
+		machineAZ, err := m.AvailabilityZone()
+		c.Assert(err, jc.ErrorIsNil)
+		zoneCounts[machineAZ]++
+	}
+	c.Assert(zoneCounts, jc.DeepEquals, map[string]int{"zone3": 2, "zone4": 2})
+}
+
 func (s *ProvisionerSuite) TestProvisioningMachinesNoZonedEnviron(c *gc.C) {
 	// Make sure the provisioner still works for providers which do not
 	// implement the ZonedEnviron interface.
@@ -1976,6 +2145,54 @@ type mockBroker struct {
 	derivedAZ                map[string][]string
 }
 
+type taggedInstance struct {
+	id      instance.Id
+	tags    map[string]string
+	removed []string
+}
+
+type taggingBroker struct {
+	environs.Environ
+	tagged chan taggedInstance
+}
+
+func (b *taggingBroker) TagInstance(id instance.Id, tags map[string]string) error {
+	b.tagged <- taggedInstance{id, tags, nil}
+	return nil
+}
+
+type retaggingBroker struct {
+	taggingBroker
+	retagged chan []string
+}
+
+func (b *retaggingBroker) RetagInstance(id instance.Id, tags map[string]string, removed []string) error {
+	b.tagged <- taggedInstance{id, tags, removed}
+	return nil
+}
+
+func (b *retaggingBroker) RetagResources(controllerUUID string, removed []string) error {
+	b.retagged <- removed
+	return nil
+}
+
+// blockingTaggingBroker reports each instance it is asked to tag, and
+// then blocks until unblock is closed.
+type blockingTaggingBroker struct {
+	environs.Environ
+	tagging chan instance.Id
+	unblock chan struct{}
+}
+
+func (b *blockingTaggingBroker) TagInstance(id instance.Id, tags map[string]string) error {
+	select {
+	case b.tagging <- id:
+	default:
+	}
+	<-b.unblock
+	return nil
+}
+
 type mockBrokerFailures struct {
 	err         error
 	whenSucceed int
@@ -0,0 +1,311 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/worker.v1"
+
+	apiprovisioner "github.com/juju/juju/api/provisioner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// canRetag reports whether the broker supports tagging instances.
+func canRetag(broker environs.InstanceBroker) bool {
+	switch broker.(type) {
+	case environs.ResourceRetagger, environs.InstanceTagger:
+		return true
+	}
+	return false
+}
+
+// instanceRetagger re-applies the tags of the model's resources and
+// of the instances of a provisioner task's machines, when the model's
+// resource tags or the units assigned to the machines change. It runs
+// separately from the provisioner task's main loop, so that tagging,
+// which may take many calls to the cloud, does not hold up
+// provisioning.
+type instanceRetagger struct {
+	catacomb       catacomb.Catacomb
+	controllerUUID string
+	broker         environs.InstanceBroker
+
+	// getMachine returns the provisioner task's machine with the
+	// given id, and getMachines all of the task's machines.
+	getMachine  func(id string) (*apiprovisioner.Machine, bool)
+	getMachines func() []*apiprovisioner.Machine
+
+	// wake is signalled when there are changes to process.
+	wake chan struct{}
+
+	// mu protects the fields below, which record the changes
+	// that have not yet been processed.
+	mu sync.Mutex
+	// ready is set once the provisioner task has processed its
+	// first set of machine changes, and so knows of all machines.
+	ready bool
+	// retagAll is set when all resources need to be retagged.
+	retagAll bool
+	// names of resource tags removed since the last retag
+	removed set.Strings
+	// ids of machines whose units may need to be watched, or no
+	// longer watched
+	changedMachines set.Strings
+
+	// machine id -> watcher of the units assigned to the machine
+	unitsWatchers map[string]worker.Worker
+	unitsChanges  chan string
+}
+
+func newInstanceRetagger(
+	controllerUUID string,
+	broker environs.InstanceBroker,
+	getMachine func(id string) (*apiprovisioner.Machine, bool),
+	getMachines func() []*apiprovisioner.Machine,
+) (*instanceRetagger, error) {
+	r := &instanceRetagger{
+		controllerUUID:  controllerUUID,
+		broker:          broker,
+		getMachine:      getMachine,
+		getMachines:     getMachines,
+		wake:            make(chan struct{}, 1),
+		removed:         set.NewStrings(),
+		changedMachines: set.NewStrings(),
+		unitsWatchers:   make(map[string]worker.Worker),
+		unitsChanges:    make(chan string),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &r.catacomb,
+		Work: r.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return r, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (r *instanceRetagger) Kill() {
+	r.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (r *instanceRetagger) Wait() error {
+	return r.catacomb.Wait()
+}
+
+// RetagAll causes the retagger to re-apply the tags of the model's
+// resources and of the instances of all provisioned machines, and
+// to remove the tags with the given names from them.
+func (r *instanceRetagger) RetagAll(removed []string) {
+	r.mu.Lock()
+	r.retagAll = true
+	for _, name := range removed {
+		r.removed.Add(name)
+	}
+	r.mu.Unlock()
+	r.signal()
+}
+
+// MachinesChanged tells the retagger that the provisioner task has
+// processed changes to the machines with the given ids, so that it
+// can start or stop watching their units.
+func (r *instanceRetagger) MachinesChanged(ids []string) {
+	r.mu.Lock()
+	r.ready = true
+	for _, id := range ids {
+		r.changedMachines.Add(id)
+	}
+	r.mu.Unlock()
+	r.signal()
+}
+
+func (r *instanceRetagger) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+		// The changes are already pending.
+	}
+}
+
+func (r *instanceRetagger) loop() error {
+	for {
+		select {
+		case <-r.catacomb.Dying():
+			return r.catacomb.ErrDying()
+		case <-r.wake:
+			r.mu.Lock()
+			// Don't retag instances until the provisioner
+			// task knows of all machines.
+			retagAll := r.ready && r.retagAll
+			var removed []string
+			if retagAll {
+				removed = r.removed.SortedValues()
+				r.removed = set.NewStrings()
+				r.retagAll = false
+			}
+			changedMachines := r.changedMachines.SortedValues()
+			r.changedMachines = set.NewStrings()
+			r.mu.Unlock()
+
+			if err := r.updateUnitsWatchers(changedMachines); err != nil {
+				return errors.Trace(err)
+			}
+			if retagAll {
+				r.retagInstances(removed)
+			}
+		case machineId := <-r.unitsChanges:
+			if machine, ok := r.getMachine(machineId); ok {
+				r.retagInstance(machine, nil)
+			}
+		}
+	}
+}
+
+// retagInstances re-applies the tags of the model's resources and
+// of the instances of all provisioned machines. Tagging is best
+// effort: failures are logged, and do not stop the retagger.
+func (r *instanceRetagger) retagInstances(removed []string) {
+	if retagger, ok := r.broker.(environs.ResourceRetagger); ok {
+		if err := retagger.RetagResources(r.controllerUUID, removed); err != nil {
+			logger.Errorf("cannot retag model resources: %v", err)
+		}
+	}
+	for _, machine := range r.getMachines() {
+		r.retagInstance(machine, removed)
+	}
+}
+
+// retagInstance re-applies the tags of the instance of the given
+// machine, if it has been provisioned, and removes the tags with the
+// given names.
+func (r *instanceRetagger) retagInstance(machine *apiprovisioner.Machine, removed []string) {
+	instanceId, err := machine.InstanceId()
+	if params.IsCodeNotProvisioned(err) {
+		return
+	} else if err != nil {
+		logger.Errorf("cannot get instance id of machine %q: %v", machine.Id(), err)
+		return
+	}
+	pInfo, err := machine.ProvisioningInfo()
+	if err != nil {
+		logger.Errorf("cannot get provisioning info of machine %q: %v", machine.Id(), err)
+		return
+	}
+	switch broker := r.broker.(type) {
+	case environs.ResourceRetagger:
+		err = broker.RetagInstance(instanceId, pInfo.Tags, removed)
+	case environs.InstanceTagger:
+		err = broker.TagInstance(instanceId, pInfo.Tags)
+	}
+	if err != nil {
+		logger.Errorf("cannot tag instance of machine %q: %v", machine.Id(), err)
+		return
+	}
+	logger.Debugf("retagged instance %q of machine %q", instanceId, machine.Id())
+}
+
+// updateUnitsWatchers starts watching the units assigned to those of
+// the given machines that are alive, and stops watching the units of
+// the others, so that the instances of machines can be retagged when
+// their units change.
+func (r *instanceRetagger) updateUnitsWatchers(ids []string) error {
+	for _, id := range ids {
+		machine, found := r.getMachine(id)
+		alive := found && machine.Life() == params.Alive
+
+		w, watching := r.unitsWatchers[id]
+		switch {
+		case alive && !watching:
+			unitsWatcher, err := machine.WatchUnits()
+			if params.IsCodeNotFound(err) {
+				continue
+			} else if err != nil {
+				return errors.Annotatef(err, "cannot watch units of machine %q", id)
+			}
+			w, err := newMachineUnitsWatcher(id, unitsWatcher, r.unitsChanges)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err := r.catacomb.Add(w); err != nil {
+				return errors.Trace(err)
+			}
+			r.unitsWatchers[id] = w
+		case !alive && watching:
+			w.Kill()
+			delete(r.unitsWatchers, id)
+		}
+	}
+	return nil
+}
+
+// machineUnitsWatcher reports changes to the units assigned to a
+// machine, by sending the machine's id on the changes channel, so
+// that the retagger can retag the machine's instance.
+type machineUnitsWatcher struct {
+	catacomb  catacomb.Catacomb
+	machineId string
+	changes   chan<- string
+}
+
+func newMachineUnitsWatcher(
+	machineId string, unitsWatcher watcher.StringsWatcher, changes chan<- string,
+) (*machineUnitsWatcher, error) {
+	w := &machineUnitsWatcher{
+		machineId: machineId,
+		changes:   changes,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: func() error {
+			return w.loop(unitsWatcher)
+		},
+		Init: []worker.Worker{unitsWatcher},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+func (w *machineUnitsWatcher) loop(unitsWatcher watcher.StringsWatcher) error {
+	// The instance is tagged with the machine's units when it is
+	// started, so the initial event does not need reporting.
+	initial := true
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-unitsWatcher.Changes():
+			if !ok {
+				return errors.New("machine units watcher closed")
+			}
+			if initial {
+				initial = false
+				continue
+			}
+			select {
+			case <-w.catacomb.Dying():
+				return w.catacomb.ErrDying()
+			case w.changes <- w.machineId:
+			}
+		}
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *machineUnitsWatcher) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *machineUnitsWatcher) Wait() error {
+	return w.catacomb.Wait()
+}
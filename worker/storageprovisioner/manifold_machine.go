@@ -15,8 +15,10 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/storageprovisioner"
 	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/diskmanager"
 )
 
 // MachineManifoldConfig defines a storage provisioner's configuration and dependencies.
@@ -49,7 +51,7 @@ func (config MachineManifoldConfig) newWorker(a agent.Agent, apiCaller base.APIC
 		Volumes:     api,
 		Filesystems: api,
 		Life:        api,
-		Registry:    provider.CommonStorageProviders(),
+		Registry:    machineStorageProviders(),
 		Machines:    api,
 		Status:      api,
 		Clock:       config.Clock,
@@ -60,6 +62,23 @@ func (config MachineManifoldConfig) newWorker(a agent.Agent, apiCaller base.APIC
 	return w, nil
 }
 
+// machineStorageProviders returns the registry of storage providers
+// whose volumes and filesystems are managed by the machine agent. As
+// well as the common providers, this includes the device provider,
+// which allocates block devices discovered on the machine to volumes.
+func machineStorageProviders() storage.ProviderRegistry {
+	return storage.ChainedProviderRegistry{
+		provider.CommonStorageProviders(),
+		storage.StaticProviderRegistry{
+			map[storage.ProviderType]storage.Provider{
+				provider.DeviceProviderType: provider.NewDeviceProvider(
+					provider.ListBlockDevicesFunc(diskmanager.DefaultListBlockDevices),
+				),
+			},
+		},
+	}
+}
+
 // MachineManifold returns a dependency.Manifold that runs a storage provisioner.
 func MachineManifold(config MachineManifoldConfig) dependency.Manifold {
 	typedConfig := engine.AgentAPIManifoldConfig{
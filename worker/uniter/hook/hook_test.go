@@ -50,6 +50,8 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.StorageAttached}, `invalid storage ID ""`},
 	{hook.Info{Kind: hooks.StorageAttached, StorageId: "data/0"}, ""},
 	{hook.Info{Kind: hooks.StorageDetaching, StorageId: "data/0"}, ""},
+	{hook.Info{Kind: hook.SecretRotate}, `"secret-rotate" hook requires a secret name`},
+	{hook.Info{Kind: hook.SecretRotate, SecretName: "root-password"}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
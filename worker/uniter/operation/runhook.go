@@ -42,6 +42,8 @@ func (rh *runHook) String() string {
 		}
 	case rh.info.Kind.IsStorage():
 		suffix = fmt.Sprintf(" (%s)", rh.info.StorageId)
+	case rh.info.Kind == hook.SecretRotate:
+		suffix = fmt.Sprintf(" (%s)", rh.info.SecretName)
 	}
 	return fmt.Sprintf("run %s%s hook", rh.info.Kind, suffix)
 }
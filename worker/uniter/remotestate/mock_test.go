@@ -206,6 +206,7 @@ type mockUnit struct {
 	storageWatcher        *mockStringsWatcher
 	actionWatcher         *mockStringsWatcher
 	relationsWatcher      *mockStringsWatcher
+	secretRotationWatcher *mockStringsWatcher
 }
 
 func (u *mockUnit) Life() params.Life {
@@ -252,6 +253,10 @@ func (u *mockUnit) WatchActionNotifications() (watcher.StringsWatcher, error) {
 	return u.actionWatcher, nil
 }
 
+func (u *mockUnit) WatchSecretRotations() (watcher.StringsWatcher, error) {
+	return u.secretRotationWatcher, nil
+}
+
 func (u *mockUnit) WatchRelations() (watcher.StringsWatcher, error) {
 	return u.relationsWatcher, nil
 }
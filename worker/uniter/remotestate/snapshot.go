@@ -69,6 +69,10 @@ type Snapshot struct {
 	// executed by this unit.
 	Commands []string
 
+	// SecretRotations is the list of names of the
+	// application's secrets that are due to be rotated.
+	SecretRotations []string
+
 	// Series is the current series running on the unit
 	Series string
 }
@@ -43,6 +43,9 @@ type Unit interface {
 	WatchConfigSettings() (watcher.NotifyWatcher, error)
 	WatchStorage() (watcher.StringsWatcher, error)
 	WatchActionNotifications() (watcher.StringsWatcher, error)
+	// WatchSecretRotations returns a watcher that fires with the
+	// names of the application's secrets that are due to be rotated.
+	WatchSecretRotations() (watcher.StringsWatcher, error)
 	// WatchRelation returns a watcher that fires when relations
 	// relevant for this unit change.
 	WatchRelations() (watcher.StringsWatcher, error)
@@ -132,6 +132,8 @@ func (w *RemoteStateWatcher) Snapshot() Snapshot {
 	copy(snapshot.Actions, w.current.Actions)
 	snapshot.Commands = make([]string, len(w.current.Commands))
 	copy(snapshot.Commands, w.current.Commands)
+	snapshot.SecretRotations = make([]string, len(w.current.SecretRotations))
+	copy(snapshot.SecretRotations, w.current.SecretRotations)
 	return snapshot
 }
 
@@ -273,6 +275,25 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 	}
 	requiredEvents++
 
+	var (
+		seenSecretRotationsChange bool
+		secretRotationsChanges    watcher.StringsChannel
+	)
+	secretRotationsw, err := w.unit.WatchSecretRotations()
+	if errors.IsNotSupported(err) {
+		// Older controllers don't support secrets, so
+		// there are never any to rotate.
+		logger.Debugf("secrets not supported by controller")
+	} else if err != nil {
+		return errors.Trace(err)
+	} else {
+		secretRotationsChanges = secretRotationsw.Changes()
+		if err := w.catacomb.Add(secretRotationsw); err != nil {
+			return errors.Trace(err)
+		}
+		requiredEvents++
+	}
+
 	var seenUpdateStatusIntervalChange bool
 	updateStatusIntervalw, err := w.st.WatchUpdateStatusHookInterval()
 	if err != nil {
@@ -397,6 +418,16 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 			}
 			observedEvent(&seenActionsChange)
 
+		case secretNames, ok := <-secretRotationsChanges:
+			logger.Debugf("got secret rotations change: %v ok=%t", secretNames, ok)
+			if !ok {
+				return errors.New("secret rotations watcher closed")
+			}
+			if err := w.secretRotationsChanged(secretNames); err != nil {
+				return errors.Trace(err)
+			}
+			observedEvent(&seenSecretRotationsChange)
+
 		case keys, ok := <-relationsw.Changes():
 			logger.Debugf("got relations change: ok=%t", ok)
 			if !ok {
@@ -576,6 +607,15 @@ func (w *RemoteStateWatcher) leaderSettingsChanged() error {
 	return nil
 }
 
+// secretRotationsChanged responds to changes in the application's
+// secrets that are due to be rotated.
+func (w *RemoteStateWatcher) secretRotationsChanged(secretNames []string) error {
+	w.mu.Lock()
+	w.current.SecretRotations = secretNames
+	w.mu.Unlock()
+	return nil
+}
+
 func (w *RemoteStateWatcher) leadershipChanged(isLeader bool) error {
 	w.mu.Lock()
 	w.current.Leader = isLeader
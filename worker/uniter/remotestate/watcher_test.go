@@ -61,6 +61,7 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 			storageWatcher:        newMockStringsWatcher(),
 			actionWatcher:         newMockStringsWatcher(),
 			relationsWatcher:      newMockStringsWatcher(),
+			secretRotationWatcher: newMockStringsWatcher(),
 		},
 		relations:                   make(map[names.RelationTag]*mockRelation),
 		storageAttachment:           make(map[params.StorageAttachmentId]params.StorageAttachment),
@@ -129,6 +130,7 @@ func (s *WatcherSuite) TestInitialSignal(c *gc.C) {
 	s.st.unit.application.applicationWatcher.changes <- struct{}{}
 	s.st.unit.application.leaderSettingsWatcher.changes <- struct{}{}
 	s.st.unit.relationsWatcher.changes <- []string{}
+	s.st.unit.secretRotationWatcher.changes <- []string{}
 	s.st.updateStatusIntervalWatcher.changes <- struct{}{}
 	s.leadership.claimTicket.ch <- struct{}{}
 	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
@@ -141,6 +143,7 @@ func signalAll(st *mockState, l *mockLeadershipTracker) {
 	st.unit.application.applicationWatcher.changes <- struct{}{}
 	st.unit.application.leaderSettingsWatcher.changes <- struct{}{}
 	st.unit.relationsWatcher.changes <- []string{}
+	st.unit.secretRotationWatcher.changes <- []string{}
 	st.updateStatusIntervalWatcher.changes <- struct{}{}
 	l.claimTicket.ch <- struct{}{}
 	if st.modelType == model.IAAS {
@@ -239,6 +242,19 @@ func (s *WatcherSuite) TestActionsReceived(c *gc.C) {
 	c.Assert(s.watcher.Snapshot().Actions, gc.DeepEquals, []string{"an-action"})
 }
 
+func (s *WatcherSuite) TestSecretRotationsReceived(c *gc.C) {
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	s.st.unit.secretRotationWatcher.changes <- []string{"root-password"}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretRotations, jc.DeepEquals, []string{"root-password"})
+
+	s.st.unit.secretRotationWatcher.changes <- []string{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().SecretRotations, gc.HasLen, 0)
+}
+
 func (s *WatcherSuite) TestClearResolvedMode(c *gc.C) {
 	s.st.unit.resolved = params.ResolvedRetryHooks
 	signalAll(s.st, s.leadership)
@@ -270,6 +270,19 @@ func (s *uniterResolver) nextOp(
 		return op, err
 	}
 
+	// Only the leader rotates the application's secrets.
+	if localState.Leader {
+		for _, name := range remoteState.SecretRotations {
+			if _, ok := localState.CompletedSecretRotations[name]; ok {
+				continue
+			}
+			return opFactory.NewRunHook(hook.Info{
+				Kind:       hook.SecretRotate,
+				SecretName: name,
+			})
+		}
+	}
+
 	// UpdateStatus hook runs if nothing else needs to.
 	if localState.UpdateStatusVersion != remoteState.UpdateStatusVersion {
 		return opFactory.NewRunHook(hook.Info{Kind: hooks.UpdateStatus})
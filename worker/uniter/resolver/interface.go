@@ -95,6 +95,12 @@ type LocalState struct {
 	// controller.
 	CompletedActions map[string]struct{}
 
+	// CompletedSecretRotations is the set of secrets for which a
+	// secret-rotate hook has been committed. This is used to prevent
+	// us rotating a secret again before the controller has recorded
+	// the rotation.
+	CompletedSecretRotations map[string]struct{}
+
 	// Series is the current series running on the unit from remotestate.Snapshot
 	// for which a config-changed hook has been committed.
 	Series string
@@ -122,6 +122,16 @@ func (s *resolverOpFactory) wrapHookOp(op operation.Operation, info hook.Info) o
 		op = onCommitWrapper{op, func() {
 			s.LocalState.LeaderSettingsVersion = v
 		}}
+	case hook.SecretRotate:
+		op = onCommitWrapper{op, func() {
+			if s.LocalState.CompletedSecretRotations == nil {
+				s.LocalState.CompletedSecretRotations = make(map[string]struct{})
+			}
+			s.LocalState.CompletedSecretRotations[info.SecretName] = struct{}{}
+			s.LocalState.CompletedSecretRotations = trimCompletedActions(
+				s.RemoteState.SecretRotations, s.LocalState.CompletedSecretRotations,
+			)
+		}}
 	}
 
 	charmModifiedVersion := s.RemoteState.CharmModifiedVersion
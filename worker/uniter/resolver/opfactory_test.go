@@ -129,6 +129,24 @@ func (s *ResolverOpFactorySuite) testLeaderSettingsChanged(
 	c.Assert(f.LocalState.UpdateStatusVersion, gc.Equals, 3)
 }
 
+func (s *ResolverOpFactorySuite) TestSecretRotate(c *gc.C) {
+	f := resolver.NewResolverOpFactory(s.opFactory)
+	f.RemoteState.SecretRotations = []string{"root-password", "salt"}
+	f.LocalState.CompletedSecretRotations = map[string]struct{}{
+		"admin-password": struct{}{},
+	}
+
+	op, err := f.NewRunHook(hook.Info{Kind: hook.SecretRotate, SecretName: "salt"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Secrets that are no longer due are trimmed.
+	c.Assert(f.LocalState.CompletedSecretRotations, gc.DeepEquals, map[string]struct{}{
+		"salt": struct{}{},
+	})
+}
+
 func (s *ResolverOpFactorySuite) TestUpgrade(c *gc.C) {
 	s.testUpgrade(c, resolver.ResolverOpFactory.NewUpgrade)
 	s.testUpgrade(c, resolver.ResolverOpFactory.NewRevertUpgrade)
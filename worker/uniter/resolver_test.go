@@ -142,6 +142,42 @@ func (s *resolverSuite) TestSeriesChangedBlank(c *gc.C) {
 	c.Assert(op.String(), gc.Equals, "run config-changed hook")
 }
 
+func (s *resolverSuite) TestSecretRotate(c *gc.C) {
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		CompletedSecretRotations: map[string]struct{}{
+			"admin-password": struct{}{},
+		},
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+			Leader:    true,
+		},
+	}
+	s.remoteState.Leader = true
+	s.remoteState.SecretRotations = []string{"admin-password", "root-password"}
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run secret-rotate (root-password) hook")
+}
+
+func (s *resolverSuite) TestSecretRotateNotLeader(c *gc.C) {
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+		},
+	}
+	s.remoteState.SecretRotations = []string{"root-password"}
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
 func (s *resolverSuite) TestHookErrorDoesNotStartRetryTimerIfShouldRetryFalse(c *gc.C) {
 	s.resolverConfig.ShouldRetryHooks = false
 	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
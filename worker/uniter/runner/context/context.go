@@ -198,6 +198,10 @@ type HookContext struct {
 	// storageId is the tag of the storage instance associated with the running hook.
 	storageTag names.StorageTag
 
+	// secretName is the name of the secret being rotated by the
+	// running hook. It is empty if the hook is not secret-rotate.
+	secretName string
+
 	// hasRunSetStatus is true if a call to the status-set was made during the
 	// invocation of a hook.
 	// This attribute is persisted to local uniter state at the end of the hook
@@ -636,6 +640,9 @@ func (context *HookContext) HookVars(paths Paths) ([]string, error) {
 	} else if !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
 	}
+	if context.secretName != "" {
+		vars = append(vars, "JUJU_SECRET_NAME="+context.secretName)
+	}
 	if context.actionData != nil {
 		vars = append(vars,
 			"JUJU_ACTION_NAME="+context.actionData.Name,
@@ -758,6 +765,19 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		}
 	}
 
+	// Record that the secret has been rotated, so that it is not
+	// rotated again until its rotate interval has passed.
+	if ctx.secretName != "" && writeChanges {
+		err := ctx.unit.SecretRotated(ctx.secretName)
+		if err != nil {
+			err = errors.Annotatef(err, "cannot record rotation of secret %q", ctx.secretName)
+			logger.Errorf("%v", err)
+			if ctxErr == nil {
+				ctxErr = err
+			}
+		}
+	}
+
 	// TODO (tasdomas) 2014 09 03: context finalization needs to modified to apply all
 	//                             changes in one api call to minimize the risk
 	//                             of partial failures.
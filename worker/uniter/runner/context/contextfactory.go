@@ -235,6 +235,9 @@ func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", storageName, hookName)
 	}
+	if hookInfo.Kind == hook.SecretRotate {
+		ctx.secretName = hookInfo.SecretName
+	}
 	ctx.id = f.newId(hookName)
 	return ctx, nil
 }
@@ -181,6 +181,23 @@ func (s *ContextFactorySuite) TestRelationHookContext(c *gc.C) {
 	s.AssertNotStorageContext(c, ctx)
 }
 
+func (s *ContextFactorySuite) TestSecretRotateHookContext(c *gc.C) {
+	hi := hook.Info{
+		Kind:       hook.SecretRotate,
+		SecretName: "root-password",
+	}
+	ctx, err := s.factory.HookContext(hi)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertCoreContext(c, ctx)
+	s.AssertNotActionContext(c, ctx)
+	s.AssertNotRelationContext(c, ctx)
+	s.AssertNotStorageContext(c, ctx)
+
+	vars, err := ctx.HookVars(s.paths)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vars, jc.Contains, "JUJU_SECRET_NAME=root-password")
+}
+
 func (s *ContextFactorySuite) TestNewHookContextWithStorage(c *gc.C) {
 	// We need to set up a unit that has storage metadata defined.
 	ch := s.AddTestingCharm(c, "storage-block")
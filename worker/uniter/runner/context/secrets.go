@@ -0,0 +1,36 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// SetSecret implements jujuc.ContextSecrets.
+func (ctx *HookContext) SetSecret(name string, values map[string]string, rotateInterval time.Duration) error {
+	return errors.Trace(ctx.unit.SetSecret(name, values, rotateInterval))
+}
+
+// Secret implements jujuc.ContextSecrets.
+func (ctx *HookContext) Secret(name, application string) (map[string]string, error) {
+	values, err := ctx.unit.Secret(name, application)
+	return values, errors.Trace(err)
+}
+
+// ShareSecret implements jujuc.ContextSecrets.
+func (ctx *HookContext) ShareSecret(name, application string) error {
+	return errors.Trace(ctx.unit.ShareSecret(name, application))
+}
+
+// UnshareSecret implements jujuc.ContextSecrets.
+func (ctx *HookContext) UnshareSecret(name, application string) error {
+	return errors.Trace(ctx.unit.UnshareSecret(name, application))
+}
+
+// RemoveSecret implements jujuc.ContextSecrets.
+func (ctx *HookContext) RemoveSecret(name string) error {
+	return errors.Trace(ctx.unit.RemoveSecret(name))
+}
@@ -41,6 +41,7 @@ type HookContext interface {
 	ContextComponents
 	ContextRelations
 	ContextVersion
+	ContextSecrets
 }
 
 // UnitHookContext is the context for a unit hook.
@@ -177,6 +178,35 @@ type ContextLeadership interface {
 	WriteLeaderSettings(map[string]string) error
 }
 
+// ContextSecrets is the part of a hook context related to secrets owned
+// by the unit's application, or shared with it by related applications.
+type ContextSecrets interface {
+	// SetSecret creates or updates the named secret owned by the unit's
+	// application, or fails if the local unit is not the application's
+	// leader. The secret is due to be rotated every rotateInterval,
+	// unless that is zero.
+	SetSecret(name string, values map[string]string, rotateInterval time.Duration) error
+
+	// Secret returns the values of the named secret owned by the given
+	// application, or by the unit's application if that is empty.
+	Secret(name, application string) (map[string]string, error)
+
+	// ShareSecret shares the named secret owned by the unit's
+	// application with a related application, or fails if the local
+	// unit is not the application's leader.
+	ShareSecret(name, application string) error
+
+	// UnshareSecret stops sharing the named secret owned by the unit's
+	// application with an application, or fails if the local unit is
+	// not the application's leader.
+	UnshareSecret(name, application string) error
+
+	// RemoveSecret removes the named secret owned by the unit's
+	// application, or fails if the local unit is not the
+	// application's leader.
+	RemoveSecret(name string) error
+}
+
 // ContextMetrics is the part of a hook context related to metrics.
 type ContextMetrics interface {
 	// AddMetric records a metric to return after hook execution.
@@ -0,0 +1,60 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuctesting
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Secrets holds the values for the hook context.
+type Secrets struct {
+	// Secrets holds the values of the secrets readable by the unit,
+	// keyed on owning application and then secret name.
+	Secrets map[string]map[string]map[string]string
+}
+
+// ContextSecrets is a test double for jujuc.ContextSecrets.
+type ContextSecrets struct {
+	contextBase
+	info *Secrets
+}
+
+// SetSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) SetSecret(name string, values map[string]string, rotateInterval time.Duration) error {
+	c.stub.AddCall("SetSecret", name, values, rotateInterval)
+	return errors.Trace(c.stub.NextErr())
+}
+
+// Secret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) Secret(name, application string) (map[string]string, error) {
+	c.stub.AddCall("Secret", name, application)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	values, ok := c.info.Secrets[application][name]
+	if !ok {
+		return nil, errors.NotFoundf("secret %q", name)
+	}
+	return values, nil
+}
+
+// ShareSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) ShareSecret(name, application string) error {
+	c.stub.AddCall("ShareSecret", name, application)
+	return errors.Trace(c.stub.NextErr())
+}
+
+// UnshareSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) UnshareSecret(name, application string) error {
+	c.stub.AddCall("UnshareSecret", name, application)
+	return errors.Trace(c.stub.NextErr())
+}
+
+// RemoveSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) RemoveSecret(name string) error {
+	c.stub.AddCall("RemoveSecret", name)
+	return errors.Trace(c.stub.NextErr())
+}
@@ -97,6 +97,25 @@ func (*RestrictedContext) LeaderSettings() (map[string]string, error) {
 // WriteLeaderSettings implements hooks.Context.
 func (*RestrictedContext) WriteLeaderSettings(map[string]string) error { return ErrRestrictedContext }
 
+// SetSecret implements hooks.Context.
+func (*RestrictedContext) SetSecret(string, map[string]string, time.Duration) error {
+	return ErrRestrictedContext
+}
+
+// Secret implements hooks.Context.
+func (*RestrictedContext) Secret(string, string) (map[string]string, error) {
+	return nil, ErrRestrictedContext
+}
+
+// ShareSecret implements hooks.Context.
+func (*RestrictedContext) ShareSecret(string, string) error { return ErrRestrictedContext }
+
+// UnshareSecret implements hooks.Context.
+func (*RestrictedContext) UnshareSecret(string, string) error { return ErrRestrictedContext }
+
+// RemoveSecret implements hooks.Context.
+func (*RestrictedContext) RemoveSecret(string) error { return ErrRestrictedContext }
+
 // AddMetric implements hooks.Context.
 func (*RestrictedContext) AddMetric(string, string, time.Time) error { return ErrRestrictedContext }
 
@@ -0,0 +1,78 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+)
+
+// secretGetCommand implements the secret-get command.
+type secretGetCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	name        string
+	key         string
+	application string
+	out         cmd.Output
+}
+
+// NewSecretGetCommand returns a new secretGetCommand with the given context.
+func NewSecretGetCommand(ctx Context) (cmd.Command, error) {
+	return &secretGetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *secretGetCommand) Info() *cmd.Info {
+	doc := `
+secret-get prints the value of a secret specified by key. If no key is given,
+all keys and values of the secret will be printed.
+
+By default the secret is one owned by the unit's application. If --application
+is given, the secret is one owned by that application, which must have shared
+it with the unit's application.
+`
+	return &cmd.Info{
+		Name:    "secret-get",
+		Args:    "<name> [<key>]",
+		Purpose: "print the values of a secret",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *secretGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.StringVar(&c.application, "application", "", "the application that owns the secret")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretGetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no secret name specified")
+	}
+	c.name, args = args[0], args[1:]
+	c.key = ""
+	if len(args) > 0 {
+		c.key, args = args[0], args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretGetCommand) Run(ctx *cmd.Context) error {
+	values, err := c.ctx.Secret(c.name, c.application)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read secret %q", c.name)
+	}
+	if c.key == "" {
+		return c.out.Write(ctx, values)
+	}
+	if value, ok := values[c.key]; ok {
+		return c.out.Write(ctx, value)
+	}
+	return c.out.Write(ctx, nil)
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// secretRemoveCommand implements the secret-remove command.
+type secretRemoveCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	name string
+}
+
+// NewSecretRemoveCommand returns a new secretRemoveCommand with the given context.
+func NewSecretRemoveCommand(ctx Context) (cmd.Command, error) {
+	return &secretRemoveCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *secretRemoveCommand) Info() *cmd.Info {
+	doc := `
+secret-remove removes a secret owned by the unit's application, so that it can
+no longer be read by the application or by any application it was shared with.
+It will fail if called by a unit that is not currently application leader.
+`
+	return &cmd.Info{
+		Name:    "secret-remove",
+		Args:    "<name>",
+		Purpose: "remove a secret",
+		Doc:     doc,
+	}
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretRemoveCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no secret name specified")
+	}
+	c.name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretRemoveCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.RemoveSecret(c.name)
+	return errors.Annotatef(err, "cannot remove secret %q", c.name)
+}
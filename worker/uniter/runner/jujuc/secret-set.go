@@ -0,0 +1,74 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/keyvalues"
+)
+
+// secretSetCommand implements the secret-set command.
+type secretSetCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	name           string
+	values         map[string]string
+	rotateInterval time.Duration
+}
+
+// NewSecretSetCommand returns a new secretSetCommand with the given context.
+func NewSecretSetCommand(ctx Context) (cmd.Command, error) {
+	return &secretSetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *secretSetCommand) Info() *cmd.Info {
+	doc := `
+secret-set creates or updates a secret owned by the unit's application,
+replacing any previous values. Secret values are encrypted by the controller
+and are redacted from status messages. It will fail if called by a unit that
+is not currently application leader.
+
+If --rotate is given, a secret-rotate hook will run on the application leader
+each time the interval passes, so that the charm can replace the secret.
+`
+	return &cmd.Info{
+		Name:    "secret-set",
+		Args:    "<name> <key>=<value> [...]",
+		Purpose: "create or update a secret",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *secretSetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.DurationVar(&c.rotateInterval, "rotate", 0, "how often the secret should be rotated")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretSetCommand) Init(args []string) (err error) {
+	if len(args) < 1 {
+		return errors.New("no secret name specified")
+	}
+	if c.rotateInterval < 0 {
+		return errors.NotValidf("negative rotate interval")
+	}
+	c.name = args[0]
+	if len(args) < 2 {
+		return errors.New("no secret values specified")
+	}
+	c.values, err = keyvalues.Parse(args[1:], false)
+	return err
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretSetCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.SetSecret(c.name, c.values, c.rotateInterval)
+	return errors.Annotatef(err, "cannot set secret %q", c.name)
+}
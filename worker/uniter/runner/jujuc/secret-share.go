@@ -0,0 +1,68 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+)
+
+// secretShareCommand implements the secret-share command.
+type secretShareCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	name        string
+	application string
+	revoke      bool
+}
+
+// NewSecretShareCommand returns a new secretShareCommand with the given context.
+func NewSecretShareCommand(ctx Context) (cmd.Command, error) {
+	return &secretShareCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *secretShareCommand) Info() *cmd.Info {
+	doc := `
+secret-share allows the units of a related application to read a secret owned
+by the unit's application, using secret-get --application. The secret can only
+be read while the applications remain related. It will fail if called by a unit
+that is not currently application leader.
+`
+	return &cmd.Info{
+		Name:    "secret-share",
+		Args:    "<name> <application>",
+		Purpose: "share a secret with a related application",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *secretShareCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.revoke, "revoke", false, "stop sharing the secret with the application")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretShareCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no secret name specified")
+	}
+	if len(args) < 2 {
+		return errors.New("no application specified")
+	}
+	c.name, c.application = args[0], args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretShareCommand) Run(_ *cmd.Context) error {
+	if c.revoke {
+		err := c.ctx.UnshareSecret(c.name, c.application)
+		return errors.Annotatef(err, "cannot stop sharing secret %q", c.name)
+	}
+	err := c.ctx.ShareSecret(c.name, c.application)
+	return errors.Annotatef(err, "cannot share secret %q", c.name)
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type secretsSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&secretsSuite{})
+
+func (s *secretsSuite) run(c *gc.C, name string, args ...string) (code int, stdout, stderr string) {
+	hctx := s.newHookContext(c)
+	hctx.info.Secrets.Secrets = map[string]map[string]map[string]string{
+		"": {
+			"root-password": {"password": "sekrit"},
+		},
+		"mysql": {
+			"admin-password": {"password": "hunter2"},
+		},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString(name))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code = cmd.Main(com, ctx, args)
+	return code, bufferString(ctx.Stdout), bufferString(ctx.Stderr)
+}
+
+func (s *secretsSuite) TestSecretSet(c *gc.C) {
+	code, _, stderr := s.run(c, "secret-set", "--rotate", "24h", "root-password", "password=changed")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stderr, gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "SetSecret", "root-password", map[string]string{"password": "changed"}, 24*time.Hour)
+}
+
+func (s *secretsSuite) TestSecretSetInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no secret name specified",
+	}, {
+		args: []string{"root-password"},
+		err:  "no secret values specified",
+	}, {
+		args: []string{"root-password", "nonsense"},
+		err:  `expected "key=value", got "nonsense"`,
+	}, {
+		args: []string{"--rotate", "-1h", "root-password", "password=x"},
+		err:  "negative rotate interval not valid",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		com, err := jujuc.NewSecretSetCommand(nil)
+		c.Assert(err, jc.ErrorIsNil)
+		err = cmdtesting.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *secretsSuite) TestSecretSetError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("not leader"))
+	code, _, stderr := s.run(c, "secret-set", "root-password", "password=changed")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "ERROR cannot set secret \"root-password\": not leader\n")
+}
+
+func (s *secretsSuite) TestSecretGet(c *gc.C) {
+	code, stdout, stderr := s.run(c, "secret-get", "root-password", "password")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stderr, gc.Equals, "")
+	c.Assert(stdout, gc.Equals, "sekrit\n")
+	s.Stub.CheckCall(c, 0, "Secret", "root-password", "")
+}
+
+func (s *secretsSuite) TestSecretGetAll(c *gc.C) {
+	code, stdout, _ := s.run(c, "secret-get", "--application", "mysql", "--format", "json", "admin-password")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stdout, jc.JSONEquals, map[string]string{"password": "hunter2"})
+	s.Stub.CheckCall(c, 0, "Secret", "admin-password", "mysql")
+}
+
+func (s *secretsSuite) TestSecretGetNotFound(c *gc.C) {
+	code, _, stderr := s.run(c, "secret-get", "salt")
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(stderr, gc.Equals, "ERROR cannot read secret \"salt\": secret \"salt\" not found\n")
+}
+
+func (s *secretsSuite) TestSecretShare(c *gc.C) {
+	code, _, stderr := s.run(c, "secret-share", "root-password", "wordpress")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stderr, gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "ShareSecret", "root-password", "wordpress")
+}
+
+func (s *secretsSuite) TestSecretShareRevoke(c *gc.C) {
+	code, _, stderr := s.run(c, "secret-share", "--revoke", "root-password", "wordpress")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stderr, gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "UnshareSecret", "root-password", "wordpress")
+}
+
+func (s *secretsSuite) TestSecretShareInitErrors(c *gc.C) {
+	com, err := jujuc.NewSecretShareCommand(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmdtesting.InitCommand(com, []string{"root-password"})
+	c.Assert(err, gc.ErrorMatches, "no application specified")
+	err = cmdtesting.InitCommand(com, []string{"root-password", "wordpress", "mysql"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["mysql"\]`)
+}
+
+func (s *secretsSuite) TestSecretRemove(c *gc.C) {
+	code, _, stderr := s.run(c, "secret-remove", "root-password")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(stderr, gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "RemoveSecret", "root-password")
+}
+
+func (s *secretsSuite) TestSecretRemoveInitErrors(c *gc.C) {
+	com, err := jujuc.NewSecretRemoveCommand(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmdtesting.InitCommand(com, nil)
+	c.Assert(err, gc.ErrorMatches, "no secret name specified")
+	err = cmdtesting.InitCommand(com, []string{"root-password", "admin-password"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["admin-password"\]`)
+}
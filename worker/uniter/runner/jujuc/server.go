@@ -79,6 +79,13 @@ var leaderCommands = map[string]creator{
 	"leader-set" + cmdSuffix: NewLeaderSetCommand,
 }
 
+var secretCommands = map[string]creator{
+	"secret-get" + cmdSuffix:    NewSecretGetCommand,
+	"secret-remove" + cmdSuffix: NewSecretRemoveCommand,
+	"secret-set" + cmdSuffix:    NewSecretSetCommand,
+	"secret-share" + cmdSuffix:  NewSecretShareCommand,
+}
+
 func allEnabledCommands() map[string]creator {
 	all := map[string]creator{}
 	add := func(m map[string]creator) {
@@ -89,6 +96,7 @@ func allEnabledCommands() map[string]creator {
 	add(baseCommands)
 	add(storageCommands)
 	add(leaderCommands)
+	add(secretCommands)
 	add(registeredCommands)
 	return all
 }